@@ -0,0 +1,410 @@
+/*
+ * foundationdb_conversion.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// deprecatedFieldConversionTimestamp is used as the removal and exclusion
+// timestamp of process groups that were marked for removal or excluded with
+// the deprecated fields. A fixed timestamp makes sure that converting the same
+// resource always has the same result.
+var deprecatedFieldConversionTimestamp = metav1.NewTime(time.Unix(0, 0).UTC())
+
+// ConvertTo converts this cluster to the hub version.
+func (cluster *FoundationDBCluster) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1beta2.FoundationDBCluster)
+	cluster.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	return convertResource(cluster.withConvertedDeprecatedFields(), dst)
+}
+
+// ConvertFrom converts the cluster from the hub version to this version.
+func (cluster *FoundationDBCluster) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1beta2.FoundationDBCluster)
+	src.ObjectMeta.DeepCopyInto(&cluster.ObjectMeta)
+	return convertResource(src, cluster)
+}
+
+// ConvertTo converts this backup to the hub version.
+func (backup *FoundationDBBackup) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1beta2.FoundationDBBackup)
+	backup.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	return convertResource(backup.withConvertedDeprecatedFields(), dst)
+}
+
+// ConvertFrom converts the backup from the hub version to this version.
+func (backup *FoundationDBBackup) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1beta2.FoundationDBBackup)
+	src.ObjectMeta.DeepCopyInto(&backup.ObjectMeta)
+	return convertResource(src, backup)
+}
+
+// ConvertTo converts this restore to the hub version.
+func (restore *FoundationDBRestore) ConvertTo(hub conversion.Hub) error {
+	dst := hub.(*v1beta2.FoundationDBRestore)
+	restore.ObjectMeta.DeepCopyInto(&dst.ObjectMeta)
+	return convertResource(restore.withConvertedDeprecatedFields(), dst)
+}
+
+// ConvertFrom converts the restore from the hub version to this version.
+func (restore *FoundationDBRestore) ConvertFrom(hub conversion.Hub) error {
+	src := hub.(*v1beta2.FoundationDBRestore)
+	src.ObjectMeta.DeepCopyInto(&restore.ObjectMeta)
+	return convertResource(src, restore)
+}
+
+// withConvertedDeprecatedFields returns a copy of the cluster where the
+// deprecated fields that have a replacement in v1beta2 are moved into their
+// replacement.
+func (cluster *FoundationDBCluster) withConvertedDeprecatedFields() *FoundationDBCluster {
+	converted := cluster.DeepCopy()
+
+	for _, processGroupID := range converted.Spec.InstancesToRemove {
+		converted.Spec.ProcessGroupsToRemove = appendMissing(converted.Spec.ProcessGroupsToRemove, processGroupID)
+	}
+	converted.Spec.InstancesToRemove = nil
+
+	for _, processGroupID := range converted.Spec.InstancesToRemoveWithoutExclusion {
+		converted.Spec.ProcessGroupsToRemoveWithoutExclusion = appendMissing(converted.Spec.ProcessGroupsToRemoveWithoutExclusion, processGroupID)
+	}
+	converted.Spec.InstancesToRemoveWithoutExclusion = nil
+
+	if converted.Spec.ProcessCounts.Resolution == 0 {
+		converted.Spec.ProcessCounts.Resolution = converted.Spec.ProcessCounts.Resolver
+		converted.Spec.ProcessCounts.Resolver = 0
+	}
+
+	// A volume size of 0 used to mean that no volume is used, which has no
+	// replacement in v1beta2.
+	volumeSize, err := resource.ParseQuantity(converted.Spec.VolumeSize)
+	if err == nil && !volumeSize.IsZero() {
+		if converted.Spec.Processes == nil {
+			converted.Spec.Processes = map[ProcessClass]ProcessSettings{}
+		}
+
+		settings := converted.Spec.Processes[ProcessClassGeneral]
+		if settings.VolumeClaimTemplate == nil {
+			settings.VolumeClaimTemplate = &corev1.PersistentVolumeClaim{}
+		}
+
+		if _, ok := settings.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]; !ok {
+			if settings.VolumeClaimTemplate.Spec.Resources.Requests == nil {
+				settings.VolumeClaimTemplate.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			settings.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage] = volumeSize
+		}
+
+		converted.Spec.Processes[ProcessClassGeneral] = settings
+		converted.Spec.VolumeSize = ""
+	}
+
+	for _, processGroup := range converted.Status.ProcessGroups {
+		if processGroup.Remove {
+			if processGroup.RemovalTimestamp == nil {
+				processGroup.RemovalTimestamp = deprecatedFieldConversionTimestamp.DeepCopy()
+			}
+			processGroup.Remove = false
+		}
+
+		if processGroup.Excluded {
+			if processGroup.ExclusionTimestamp == nil {
+				processGroup.ExclusionTimestamp = deprecatedFieldConversionTimestamp.DeepCopy()
+			}
+			processGroup.Excluded = false
+		}
+	}
+
+	return converted
+}
+
+// withConvertedDeprecatedFields returns a copy of the backup where the
+// deprecated blobstore fields are moved into the blobstore configuration.
+func (backup *FoundationDBBackup) withConvertedDeprecatedFields() *FoundationDBBackup {
+	converted := backup.DeepCopy()
+	if converted.Spec.AccountName == "" && converted.Spec.Bucket == "" {
+		return converted
+	}
+
+	// The deprecated account name is only used if there is no blobstore
+	// configuration.
+	if converted.Spec.BlobStoreConfiguration == nil {
+		converted.Spec.BlobStoreConfiguration = &BlobStoreConfiguration{
+			AccountName: converted.Spec.AccountName,
+		}
+		converted.Spec.AccountName = ""
+	}
+
+	if converted.Spec.BlobStoreConfiguration.Bucket == "" {
+		converted.Spec.BlobStoreConfiguration.Bucket = converted.Spec.Bucket
+		converted.Spec.Bucket = ""
+	}
+
+	return converted
+}
+
+// withConvertedDeprecatedFields returns a copy of the restore where the
+// deprecated backup URL is moved into the blobstore configuration.
+func (restore *FoundationDBRestore) withConvertedDeprecatedFields() *FoundationDBRestore {
+	converted := restore.DeepCopy()
+
+	// The backup URL is only used if there is no blobstore configuration.
+	if converted.Spec.BackupURL == "" || converted.Spec.BlobStoreConfiguration != nil {
+		return converted
+	}
+
+	configuration := parseBlobStoreURL(converted.Spec.BackupURL)
+	if configuration == nil {
+		return converted
+	}
+
+	converted.Spec.BlobStoreConfiguration = configuration
+	converted.Spec.BackupURL = ""
+
+	return converted
+}
+
+// parseBlobStoreURL parses a URL in the format
+// blobstore://<account>/<backup>?bucket=<bucket>&<parameters> into a blobstore
+// configuration. This returns nil if the URL doesn't have this format.
+func parseBlobStoreURL(url string) *BlobStoreConfiguration {
+	if !strings.HasPrefix(url, "blobstore://") {
+		return nil
+	}
+
+	location, query, _ := strings.Cut(strings.TrimPrefix(url, "blobstore://"), "?")
+	accountName, backupName, _ := strings.Cut(location, "/")
+	if accountName == "" || backupName == "" || strings.Contains(backupName, "/") {
+		return nil
+	}
+
+	configuration := &BlobStoreConfiguration{
+		AccountName: accountName,
+		BackupName:  backupName,
+	}
+
+	if query == "" {
+		return configuration
+	}
+
+	for _, parameter := range strings.Split(query, "&") {
+		if strings.HasPrefix(parameter, "bucket=") {
+			configuration.Bucket = strings.TrimPrefix(parameter, "bucket=")
+			continue
+		}
+
+		configuration.URLParameters = append(configuration.URLParameters, URLParamater(parameter))
+	}
+
+	return configuration
+}
+
+// appendMissing appends the value to the list if it's not already present.
+func appendMissing(list []string, value string) []string {
+	for _, current := range list {
+		if current == value {
+			return list
+		}
+	}
+
+	return append(list, value)
+}
+
+// convertResource converts the spec and status of src into dst through their
+// JSON representation, since both API versions use the same field names for
+// the fields they have in common.
+//
+// Fields of dst that were stored in the conversion data annotation when the
+// resource was converted to the version of src are restored. Fields of the
+// spec of src that can't be represented in dst are stored in the conversion
+// data annotation of dst, so converting the resource back doesn't lose any
+// of the desired state. The status is not stored, since it can be large and is
+// recomputed by the operator. The object metadata must already be copied to
+// dst.
+func convertResource(src metav1.Object, dst metav1.Object) error {
+	data, err := getConversionFields(src)
+	if err != nil {
+		return err
+	}
+
+	annotations := dst.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+
+	storedData, ok := annotations[v1beta2.ConversionDataAnnotation]
+	if ok {
+		restored := map[string]interface{}{}
+		err = json.Unmarshal([]byte(storedData), &restored)
+		if err != nil {
+			return err
+		}
+
+		restoreConversionData(data, restored)
+		delete(annotations, v1beta2.ConversionDataAnnotation)
+	}
+
+	serialized, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(serialized, dst)
+	if err != nil {
+		return err
+	}
+
+	converted, err := getConversionFields(dst)
+	if err != nil {
+		return err
+	}
+
+	remaining, _ := subtractConversionData(data, converted).(map[string]interface{})
+	delete(remaining, "status")
+	if len(remaining) > 0 {
+		serialized, err = json.Marshal(remaining)
+		if err != nil {
+			return err
+		}
+
+		annotations[v1beta2.ConversionDataAnnotation] = string(serialized)
+	}
+
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	dst.SetAnnotations(annotations)
+
+	return nil
+}
+
+// getConversionFields returns the JSON representation of the resource
+// without the type and object metadata.
+func getConversionFields(object metav1.Object) (map[string]interface{}, error) {
+	serialized, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]interface{}{}
+	err = json.Unmarshal(serialized, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	delete(data, "apiVersion")
+	delete(data, "kind")
+	delete(data, "metadata")
+
+	return data, nil
+}
+
+// subtractConversionData returns the parts of the source value that are not
+// present in the converted value. Lists are compared element by element, and
+// elements without any remaining data are represented as null. This returns
+// nil if the converted value contains all data of the source value.
+func subtractConversionData(source interface{}, converted interface{}) interface{} {
+	switch sourceValue := source.(type) {
+	case map[string]interface{}:
+		convertedValue, _ := converted.(map[string]interface{})
+		remaining := map[string]interface{}{}
+		for key, value := range sourceValue {
+			remainingValue := subtractConversionData(value, convertedValue[key])
+			if remainingValue != nil {
+				remaining[key] = remainingValue
+			}
+		}
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		return remaining
+	case []interface{}:
+		convertedValue, _ := converted.([]interface{})
+		if len(convertedValue) != len(sourceValue) {
+			return source
+		}
+
+		remaining := make([]interface{}, len(sourceValue))
+		hasRemaining := false
+		for index, value := range sourceValue {
+			remaining[index] = subtractConversionData(value, convertedValue[index])
+			if remaining[index] != nil {
+				hasRemaining = true
+			}
+		}
+
+		if !hasRemaining {
+			return nil
+		}
+
+		return remaining
+	default:
+		if converted == nil {
+			return source
+		}
+
+		return nil
+	}
+}
+
+// restoreConversionData adds the stored data to the fields that are not set
+// in the target. Lists are only restored element by element if they still have
+// the same length as when the data was stored.
+func restoreConversionData(target map[string]interface{}, stored map[string]interface{}) {
+	for key, storedValue := range stored {
+		currentValue, ok := target[key]
+		if !ok || currentValue == nil {
+			target[key] = storedValue
+			continue
+		}
+
+		switch storedTyped := storedValue.(type) {
+		case map[string]interface{}:
+			currentTyped, ok := currentValue.(map[string]interface{})
+			if ok {
+				restoreConversionData(currentTyped, storedTyped)
+			}
+		case []interface{}:
+			currentTyped, ok := currentValue.([]interface{})
+			if !ok || len(currentTyped) != len(storedTyped) {
+				continue
+			}
+
+			for index, storedElement := range storedTyped {
+				storedMap, storedOk := storedElement.(map[string]interface{})
+				currentMap, currentOk := currentTyped[index].(map[string]interface{})
+				if storedOk && currentOk {
+					restoreConversionData(currentMap, storedMap)
+				}
+			}
+		}
+	}
+}
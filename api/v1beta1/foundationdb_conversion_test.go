@@ -0,0 +1,325 @@
+/*
+ * foundationdb_conversion_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+	"sigs.k8s.io/yaml"
+)
+
+// loadConversionFixture loads the resource from the testdata directory. The
+// type metadata is cleared, since it is set by the conversion webhook and not
+// by the conversion functions.
+func loadConversionFixture(name string, object runtime.Object) {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	Expect(err).NotTo(HaveOccurred())
+	Expect(yaml.UnmarshalStrict(data, object)).NotTo(HaveOccurred())
+	object.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{})
+}
+
+var _ = Describe("[api] conversion", func() {
+	DescribeTable("checking if a resource is convertible",
+		func(object runtime.Object) {
+			scheme := runtime.NewScheme()
+			Expect(AddToScheme(scheme)).NotTo(HaveOccurred())
+			Expect(v1beta2.AddToScheme(scheme)).NotTo(HaveOccurred())
+
+			convertible, err := conversion.IsConvertible(scheme, object)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(convertible).To(BeTrue())
+		},
+		Entry("cluster", &v1beta2.FoundationDBCluster{}),
+		Entry("backup", &v1beta2.FoundationDBBackup{}),
+		Entry("restore", &v1beta2.FoundationDBRestore{}),
+	)
+
+	When("converting a v1beta1 cluster", func() {
+		var original *FoundationDBCluster
+		var hub *v1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			original = &FoundationDBCluster{}
+			loadConversionFixture("cluster_v1beta1.yaml", original)
+			hub = &v1beta2.FoundationDBCluster{}
+			Expect(original.DeepCopy().ConvertTo(hub)).NotTo(HaveOccurred())
+		})
+
+		It("should convert the common fields", func() {
+			Expect(hub.ObjectMeta.Name).To(Equal("sample-cluster"))
+			Expect(hub.ObjectMeta.Labels).To(Equal(map[string]string{"app": "sample"}))
+			Expect(hub.Spec.Version).To(Equal("6.2.30"))
+			Expect(hub.Spec.ProcessCounts.Storage).To(Equal(3))
+			Expect(hub.Spec.AutomationOptions.Replacements.Enabled).To(Equal(pointer.Bool(true)))
+			Expect(hub.Status.ProcessGroups).To(HaveLen(2))
+			Expect(hub.Status.ProcessGroups[1].ProcessGroupID).To(Equal(v1beta2.ProcessGroupID("storage-3")))
+		})
+
+		It("should convert the deprecated fields", func() {
+			Expect(hub.Spec.ProcessGroupsToRemove).To(ConsistOf(v1beta2.ProcessGroupID("storage-3"), v1beta2.ProcessGroupID("storage-4")))
+			Expect(hub.Spec.ProcessCounts.Resolution).To(Equal(1))
+			Expect(hub.Spec.Processes[v1beta2.ProcessClassGeneral].VolumeClaimTemplate.Spec.Resources.Requests).To(HaveKeyWithValue(corev1.ResourceStorage, resource.MustParse("16G")))
+			Expect(hub.Spec.Processes[v1beta2.ProcessClassGeneral].PodTemplate).NotTo(BeNil())
+			Expect(hub.Status.ProcessGroups[0].RemovalTimestamp).To(BeNil())
+			Expect(hub.Status.ProcessGroups[0].ExclusionTimestamp).To(BeNil())
+			Expect(hub.Status.ProcessGroups[1].RemovalTimestamp.Equal(&deprecatedFieldConversionTimestamp)).To(BeTrue())
+			Expect(hub.Status.ProcessGroups[1].ExclusionTimestamp.Equal(&deprecatedFieldConversionTimestamp)).To(BeTrue())
+		})
+
+		It("should always have the same result", func() {
+			convertedAgain := &v1beta2.FoundationDBCluster{}
+			Expect(original.DeepCopy().ConvertTo(convertedAgain)).NotTo(HaveOccurred())
+			Expect(convertedAgain).To(Equal(hub))
+		})
+
+		It("should only store the fields without a replacement in v1beta2", func() {
+			Expect(hub.ObjectMeta.Annotations).To(HaveKeyWithValue(v1beta2.ConversionDataAnnotation, `{"spec":{"automationOptions":{"deletePods":false},"nextInstanceID":5,"processes":{"general":{"allowTagOverride":true}}}}`))
+		})
+
+		It("should use the replacements of the deprecated fields when converting back", func() {
+			converted := &FoundationDBCluster{}
+			Expect(converted.ConvertFrom(hub)).NotTo(HaveOccurred())
+			Expect(converted.Spec.InstancesToRemove).To(BeEmpty())
+			Expect(converted.Spec.ProcessGroupsToRemove).To(ConsistOf("storage-3", "storage-4"))
+			Expect(converted.Spec.VolumeSize).To(BeEmpty())
+			Expect(converted.Spec.ProcessCounts.Resolver).To(BeZero())
+			Expect(converted.Spec.ProcessCounts.Resolution).To(Equal(1))
+			Expect(converted.Spec.NextInstanceID).To(Equal(5))
+			Expect(converted.Status.IncorrectPods).To(BeEmpty())
+			Expect(converted.Status.ProcessGroups[1].Remove).To(BeFalse())
+			Expect(converted.Status.ProcessGroups[1].IsMarkedForRemoval()).To(BeTrue())
+			Expect(converted.Status.ProcessGroups[1].IsExcluded()).To(BeTrue())
+			Expect(converted.ObjectMeta.Annotations).NotTo(HaveKey(v1beta2.ConversionDataAnnotation))
+
+			roundTripped := &v1beta2.FoundationDBCluster{}
+			Expect(converted.ConvertTo(roundTripped)).NotTo(HaveOccurred())
+			Expect(roundTripped).To(Equal(hub))
+		})
+
+		When("the process groups already have timestamps", func() {
+			var timestamp metav1.Time
+
+			BeforeEach(func() {
+				timestamp = metav1.Now().Rfc3339Copy()
+				original.Status.ProcessGroups[1].RemovalTimestamp = &timestamp
+				original.Status.ProcessGroups[1].ExclusionTimestamp = &timestamp
+				hub = &v1beta2.FoundationDBCluster{}
+				Expect(original.DeepCopy().ConvertTo(hub)).NotTo(HaveOccurred())
+			})
+
+			It("should keep the timestamps", func() {
+				Expect(hub.Status.ProcessGroups[1].RemovalTimestamp.Equal(&timestamp)).To(BeTrue())
+				Expect(hub.Status.ProcessGroups[1].ExclusionTimestamp.Equal(&timestamp)).To(BeTrue())
+			})
+		})
+
+		When("the cluster is changed in the hub version", func() {
+			BeforeEach(func() {
+				hub.Spec.Version = "6.2.31"
+				hub.Spec.AutomationOptions.OperationQueue.Enabled = pointer.Bool(true)
+			})
+
+			It("should keep the changes and the fields that only exist in v1beta1", func() {
+				converted := &FoundationDBCluster{}
+				Expect(converted.ConvertFrom(hub)).NotTo(HaveOccurred())
+				Expect(converted.Spec.Version).To(Equal("6.2.31"))
+				Expect(converted.Spec.NextInstanceID).To(Equal(5))
+				Expect(converted.ObjectMeta.Annotations[v1beta2.ConversionDataAnnotation]).To(Equal(`{"spec":{"automationOptions":{"operationQueue":{"enabled":true}}}}`))
+
+				roundTripped := &v1beta2.FoundationDBCluster{}
+				Expect(converted.ConvertTo(roundTripped)).NotTo(HaveOccurred())
+				Expect(roundTripped).To(Equal(hub))
+			})
+		})
+	})
+
+	When("converting a v1beta2 cluster", func() {
+		var original *v1beta2.FoundationDBCluster
+		var converted *FoundationDBCluster
+
+		BeforeEach(func() {
+			original = &v1beta2.FoundationDBCluster{}
+			loadConversionFixture("cluster_v1beta2.yaml", original)
+			converted = &FoundationDBCluster{}
+			Expect(converted.ConvertFrom(original.DeepCopy())).NotTo(HaveOccurred())
+		})
+
+		It("should convert the common fields", func() {
+			Expect(converted.Spec.Version).To(Equal("7.1.26"))
+			Expect(converted.Spec.ProcessCounts.Storage).To(Equal(3))
+			Expect(converted.ObjectMeta.Annotations).To(HaveKeyWithValue(v1beta2.LastSpecKey, "abc"))
+			Expect(converted.Status.ProcessGroups).To(HaveLen(2))
+		})
+
+		It("should store the fields that only exist in v1beta2", func() {
+			data := converted.ObjectMeta.Annotations[v1beta2.ConversionDataAnnotation]
+			Expect(data).To(ContainSubstring(`"grv_proxy":1`))
+			Expect(data).To(ContainSubstring(`"autoscaling":{`))
+		})
+
+		It("should not store the status", func() {
+			Expect(converted.ObjectMeta.Annotations[v1beta2.ConversionDataAnnotation]).NotTo(ContainSubstring(`"status"`))
+		})
+
+		It("should restore the spec when converting back", func() {
+			roundTripped := &v1beta2.FoundationDBCluster{}
+			Expect(converted.ConvertTo(roundTripped)).NotTo(HaveOccurred())
+			Expect(roundTripped.ObjectMeta).To(Equal(original.ObjectMeta))
+			Expect(roundTripped.Spec).To(Equal(original.Spec))
+			Expect(roundTripped.Status.ProcessGroups).To(HaveLen(2))
+			Expect(roundTripped.Status.ProcessGroups[0].FaultDomain).To(BeEmpty())
+			Expect(roundTripped.Status.Operations).To(BeEmpty())
+		})
+
+		When("a process group is added in v1beta1", func() {
+			BeforeEach(func() {
+				converted.Status.ProcessGroups = append(converted.Status.ProcessGroups, &ProcessGroupStatus{ProcessGroupID: "storage-3"})
+			})
+
+			It("should not restore the process group fields", func() {
+				roundTripped := &v1beta2.FoundationDBCluster{}
+				Expect(converted.ConvertTo(roundTripped)).NotTo(HaveOccurred())
+				Expect(roundTripped.Status.ProcessGroups).To(HaveLen(3))
+				Expect(roundTripped.Status.ProcessGroups[0].FaultDomain).To(BeEmpty())
+				Expect(roundTripped.Spec.Autoscaling).To(Equal(original.Spec.Autoscaling))
+			})
+		})
+	})
+
+	When("converting a cluster without version specific fields", func() {
+		It("should not add the conversion data annotation", func() {
+			hub := &v1beta2.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "sample-cluster"},
+				Spec:       v1beta2.FoundationDBClusterSpec{Version: "6.2.30"},
+			}
+
+			converted := &FoundationDBCluster{}
+			Expect(converted.ConvertFrom(hub)).NotTo(HaveOccurred())
+			Expect(converted.ObjectMeta.Annotations).To(BeNil())
+		})
+	})
+
+	When("converting a v1beta1 backup", func() {
+		It("should move the deprecated bucket into the blobstore configuration", func() {
+			original := &FoundationDBBackup{}
+			loadConversionFixture("backup_v1beta1.yaml", original)
+
+			hub := &v1beta2.FoundationDBBackup{}
+			Expect(original.DeepCopy().ConvertTo(hub)).NotTo(HaveOccurred())
+			Expect(hub.Spec.ClusterName).To(Equal("sample-cluster"))
+			Expect(hub.Spec.BlobStoreConfiguration.AccountName).To(Equal("account@object-store.example:443"))
+			Expect(hub.Spec.BlobStoreConfiguration.Bucket).To(Equal("fdb-backups"))
+			Expect(hub.Spec.BlobStoreConfiguration.URLParameters).To(ConsistOf(v1beta2.URLParameter("secure_connection=0")))
+			Expect(hub.BackupURL()).To(Equal(original.BackupURL()))
+			// The deprecated account name is ignored if a blobstore
+			// configuration is present, so it's only kept for the conversion.
+			Expect(hub.ObjectMeta.Annotations).To(HaveKeyWithValue(v1beta2.ConversionDataAnnotation, `{"spec":{"accountName":"account@object-store.example:443"}}`))
+
+			converted := &FoundationDBBackup{}
+			Expect(converted.ConvertFrom(hub)).NotTo(HaveOccurred())
+			Expect(converted.Spec.AccountName).To(Equal("account@object-store.example:443"))
+			Expect(converted.Spec.Bucket).To(BeEmpty())
+			Expect(converted.BackupURL()).To(Equal(original.BackupURL()))
+		})
+
+		When("the backup has no blobstore configuration", func() {
+			It("should create the blobstore configuration from the deprecated fields", func() {
+				original := &FoundationDBBackup{}
+				loadConversionFixture("backup_v1beta1.yaml", original)
+				original.Spec.BlobStoreConfiguration = nil
+
+				hub := &v1beta2.FoundationDBBackup{}
+				Expect(original.DeepCopy().ConvertTo(hub)).NotTo(HaveOccurred())
+				Expect(hub.Spec.BlobStoreConfiguration).To(Equal(&v1beta2.BlobStoreConfiguration{
+					AccountName: "account@object-store.example:443",
+					Bucket:      "fdb-backups",
+				}))
+				Expect(hub.BackupURL()).To(Equal(original.BackupURL()))
+				Expect(hub.ObjectMeta.Annotations).NotTo(HaveKey(v1beta2.ConversionDataAnnotation))
+			})
+		})
+	})
+
+	When("converting a v1beta1 restore", func() {
+		It("should move the backup URL into the blobstore configuration", func() {
+			original := &FoundationDBRestore{}
+			loadConversionFixture("restore_v1beta1.yaml", original)
+
+			hub := &v1beta2.FoundationDBRestore{}
+			Expect(original.DeepCopy().ConvertTo(hub)).NotTo(HaveOccurred())
+			Expect(hub.Spec.DestinationClusterName).To(Equal("sample-cluster"))
+			Expect(hub.Spec.KeyRanges).To(HaveLen(1))
+			Expect(hub.Spec.BlobStoreConfiguration).To(Equal(&v1beta2.BlobStoreConfiguration{
+				AccountName: "account@object-store.example:443",
+				BackupName:  "sample-backup",
+				Bucket:      "fdb-backups",
+			}))
+			Expect(hub.BackupURL()).To(Equal(original.Spec.BackupURL))
+			Expect(hub.ObjectMeta.Annotations).NotTo(HaveKey(v1beta2.ConversionDataAnnotation))
+
+			converted := &FoundationDBRestore{}
+			Expect(converted.ConvertFrom(hub)).NotTo(HaveOccurred())
+			Expect(converted.Spec.BackupURL).To(BeEmpty())
+			Expect(converted.BackupURL()).To(Equal(original.BackupURL()))
+		})
+	})
+
+	DescribeTable("parsing a blobstore URL",
+		func(url string, expected *BlobStoreConfiguration) {
+			Expect(parseBlobStoreURL(url)).To(Equal(expected))
+		},
+		Entry("with URL parameters",
+			"blobstore://account@object-store.example:443/sample-backup?bucket=fdb-backups&secure_connection=0",
+			&BlobStoreConfiguration{
+				AccountName:   "account@object-store.example:443",
+				BackupName:    "sample-backup",
+				Bucket:        "fdb-backups",
+				URLParameters: []URLParamater{"secure_connection=0"},
+			},
+		),
+		Entry("without a query",
+			"blobstore://account@object-store.example:443/sample-backup",
+			&BlobStoreConfiguration{
+				AccountName: "account@object-store.example:443",
+				BackupName:  "sample-backup",
+			},
+		),
+		Entry("with a file URL",
+			"file:///var/backups/sample-backup",
+			nil,
+		),
+		Entry("without a backup name",
+			"blobstore://account@object-store.example:443?bucket=fdb-backups",
+			nil,
+		),
+	)
+})
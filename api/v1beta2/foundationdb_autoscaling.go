@@ -0,0 +1,233 @@
+/*
+ * foundationdb_autoscaling.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"fmt"
+
+	"k8s.io/utils/pointer"
+)
+
+// AutoscalingTarget represents a count that can be changed by the autoscaler.
+type AutoscalingTarget string
+
+const (
+	// AutoscalingTargetStorage represents the storage process count.
+	AutoscalingTargetStorage AutoscalingTarget = "storage"
+	// AutoscalingTargetLogs represents the log role count.
+	AutoscalingTargetLogs AutoscalingTarget = "logs"
+	// AutoscalingTargetCommitProxies represents the commit proxy role count.
+	AutoscalingTargetCommitProxies AutoscalingTarget = "commit_proxies"
+	// AutoscalingTargetGrvProxies represents the GRV proxy role count.
+	AutoscalingTargetGrvProxies AutoscalingTarget = "grv_proxies"
+)
+
+// AutoscalingOptions controls the automatic scaling of the process and role
+// counts based on the load of the cluster.
+type AutoscalingOptions struct {
+	// Enabled defines whether the operator changes the process and role
+	// counts based on the load of the cluster. The default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Storage defines the range for the storage process count. If unset the
+	// storage process count will not be changed.
+	Storage *AutoscalingRange `json:"storage,omitempty"`
+
+	// Logs defines the range for the log role count. If unset the log role
+	// count will not be changed.
+	Logs *AutoscalingRange `json:"logs,omitempty"`
+
+	// CommitProxies defines the range for the commit proxy role count. This
+	// requires that commit_proxies and grv_proxies are set in the database
+	// configuration. If unset the commit proxy role count will not be changed.
+	CommitProxies *AutoscalingRange `json:"commitProxies,omitempty"`
+
+	// GrvProxies defines the range for the GRV proxy role count. This
+	// requires that commit_proxies and grv_proxies are set in the database
+	// configuration. If unset the GRV proxy role count will not be changed.
+	GrvProxies *AutoscalingRange `json:"grvProxies,omitempty"`
+
+	// ScaleUpCooldownSeconds defines the minimum time between the last
+	// scaling and a scale up. The default is 600.
+	// +kubebuilder:validation:Minimum=0
+	ScaleUpCooldownSeconds *int `json:"scaleUpCooldownSeconds,omitempty"`
+
+	// ScaleDownCooldownSeconds defines the minimum time between the last
+	// scaling and a scale down. The default is 3600.
+	// +kubebuilder:validation:Minimum=0
+	ScaleDownCooldownSeconds *int `json:"scaleDownCooldownSeconds,omitempty"`
+
+	// Thresholds defines the thresholds for the signals that trigger a
+	// scaling.
+	Thresholds AutoscalingThresholds `json:"thresholds,omitempty"`
+}
+
+// AutoscalingRange defines the lower and upper bound for a count.
+type AutoscalingRange struct {
+	// Min defines the lower bound.
+	// +kubebuilder:validation:Minimum=1
+	Min int `json:"min"`
+
+	// Max defines the upper bound.
+	// +kubebuilder:validation:Minimum=1
+	Max int `json:"max"`
+}
+
+// AutoscalingThresholds defines the thresholds for the signals that trigger
+// a scaling.
+type AutoscalingThresholds struct {
+	// StorageQueueBytes defines the size of the worst storage queue above
+	// which the storage process count is increased. The default is 500000000.
+	// +kubebuilder:validation:Minimum=1
+	StorageQueueBytes *int64 `json:"storageQueueBytes,omitempty"`
+
+	// LogQueueBytes defines the size of the worst log queue above which the
+	// log role count is increased. The default is 1000000000.
+	// +kubebuilder:validation:Minimum=1
+	LogQueueBytes *int64 `json:"logQueueBytes,omitempty"`
+
+	// DiskUsedPercent defines the disk usage of the fullest storage process
+	// above which the storage process count is increased. The default is 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	DiskUsedPercent *int `json:"diskUsedPercent,omitempty"`
+
+	// CommitLatencyMilliseconds defines the commit latency of the latency
+	// probe above which the commit proxy role count is increased. The default
+	// is 50.
+	// +kubebuilder:validation:Minimum=1
+	CommitLatencyMilliseconds *int `json:"commitLatencyMilliseconds,omitempty"`
+
+	// GrvLatencyMilliseconds defines the transaction start latency of the
+	// latency probe above which the GRV proxy role count is increased. The
+	// default is 25.
+	// +kubebuilder:validation:Minimum=1
+	GrvLatencyMilliseconds *int `json:"grvLatencyMilliseconds,omitempty"`
+
+	// ScaleDownPercent defines the percentage of a threshold below which a
+	// signal allows a scale down. A count is only decreased if all of its
+	// signals are below this percentage of their thresholds. The default is
+	// 30.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	ScaleDownPercent *int `json:"scaleDownPercent,omitempty"`
+}
+
+// AutoscalingStatus records the last change of the counts by the
+// autoscaler.
+type AutoscalingStatus struct {
+	// LastScaleTimestamp defines when the autoscaler changed the counts the
+	// last time.
+	LastScaleTimestamp int64 `json:"lastScaleTimestamp,omitempty"`
+
+	// Decisions contains the changes of the last scaling.
+	Decisions []AutoscalingDecision `json:"decisions,omitempty"`
+}
+
+// AutoscalingDecision describes a change of a count by the autoscaler.
+type AutoscalingDecision struct {
+	// Target defines the count that was changed.
+	Target AutoscalingTarget `json:"target"`
+
+	// Previous defines the count before the change.
+	Previous int `json:"previous,omitempty"`
+
+	// Desired defines the count after the change.
+	Desired int `json:"desired,omitempty"`
+
+	// Reason describes the signal that triggered the change.
+	Reason string `json:"reason,omitempty"`
+}
+
+// validate checks that the ranges of the autoscaling options are valid.
+func (options AutoscalingOptions) validate() []string {
+	var validations []string
+
+	ranges := map[AutoscalingTarget]*AutoscalingRange{
+		AutoscalingTargetStorage:       options.Storage,
+		AutoscalingTargetLogs:          options.Logs,
+		AutoscalingTargetCommitProxies: options.CommitProxies,
+		AutoscalingTargetGrvProxies:    options.GrvProxies,
+	}
+
+	for _, target := range []AutoscalingTarget{AutoscalingTargetStorage, AutoscalingTargetLogs, AutoscalingTargetCommitProxies, AutoscalingTargetGrvProxies} {
+		autoscalingRange := ranges[target]
+		if autoscalingRange != nil && autoscalingRange.Min > autoscalingRange.Max {
+			validations = append(validations, fmt.Sprintf("autoscaling range for %s has a minimum of %d which is larger than the maximum of %d", target, autoscalingRange.Min, autoscalingRange.Max))
+		}
+	}
+
+	return validations
+}
+
+// GetAutoscalingEnabled returns the value of autoscaling.enabled or false if
+// unset.
+func (cluster *FoundationDBCluster) GetAutoscalingEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.Autoscaling.Enabled, false)
+}
+
+// GetAutoscalingScaleUpCooldownSeconds returns the value of
+// autoscaling.scaleUpCooldownSeconds or 600 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingScaleUpCooldownSeconds() int {
+	return pointer.IntDeref(cluster.Spec.Autoscaling.ScaleUpCooldownSeconds, 600)
+}
+
+// GetAutoscalingScaleDownCooldownSeconds returns the value of
+// autoscaling.scaleDownCooldownSeconds or 3600 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingScaleDownCooldownSeconds() int {
+	return pointer.IntDeref(cluster.Spec.Autoscaling.ScaleDownCooldownSeconds, 3600)
+}
+
+// GetAutoscalingStorageQueueBytes returns the value of
+// autoscaling.thresholds.storageQueueBytes or 500000000 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingStorageQueueBytes() int64 {
+	return pointer.Int64Deref(cluster.Spec.Autoscaling.Thresholds.StorageQueueBytes, 500000000)
+}
+
+// GetAutoscalingLogQueueBytes returns the value of
+// autoscaling.thresholds.logQueueBytes or 1000000000 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingLogQueueBytes() int64 {
+	return pointer.Int64Deref(cluster.Spec.Autoscaling.Thresholds.LogQueueBytes, 1000000000)
+}
+
+// GetAutoscalingDiskUsedPercent returns the value of
+// autoscaling.thresholds.diskUsedPercent or 80 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingDiskUsedPercent() int {
+	return pointer.IntDeref(cluster.Spec.Autoscaling.Thresholds.DiskUsedPercent, 80)
+}
+
+// GetAutoscalingCommitLatencyMilliseconds returns the value of
+// autoscaling.thresholds.commitLatencyMilliseconds or 50 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingCommitLatencyMilliseconds() int {
+	return pointer.IntDeref(cluster.Spec.Autoscaling.Thresholds.CommitLatencyMilliseconds, 50)
+}
+
+// GetAutoscalingGrvLatencyMilliseconds returns the value of
+// autoscaling.thresholds.grvLatencyMilliseconds or 25 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingGrvLatencyMilliseconds() int {
+	return pointer.IntDeref(cluster.Spec.Autoscaling.Thresholds.GrvLatencyMilliseconds, 25)
+}
+
+// GetAutoscalingScaleDownPercent returns the value of
+// autoscaling.thresholds.scaleDownPercent or 30 if unset.
+func (cluster *FoundationDBCluster) GetAutoscalingScaleDownPercent() int {
+	return pointer.IntDeref(cluster.Spec.Autoscaling.Thresholds.ScaleDownPercent, 30)
+}
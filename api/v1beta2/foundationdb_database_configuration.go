@@ -884,6 +884,15 @@ func (counts *ProcessCounts) IncreaseCount(name ProcessClass, amount int) {
 	}
 }
 
+// SetCount sets one of the process counts based on the name.
+func (counts *ProcessCounts) SetCount(name ProcessClass, amount int) {
+	index, present := processClassIndices[name]
+	if present {
+		countValue := reflect.ValueOf(counts)
+		countValue.Elem().Field(index).SetInt(int64(amount))
+	}
+}
+
 // DecreaseCount adds to one of the process counts based on the name.
 func (counts *ProcessCounts) DecreaseCount(name ProcessClass, amount int) {
 	index, present := processClassIndices[name]
@@ -0,0 +1,88 @@
+/*
+ * foundationdb_disruption_schedule.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DisruptionScheduleOptions defines when the operator is allowed to perform
+// disruptive actions like restarting processes, deleting Pods, removing
+// process groups, changing coordinators or changing the database
+// configuration. Non-disruptive actions like updating the status or adding
+// new Pods are always performed.
+type DisruptionScheduleOptions struct {
+	// MaintenanceWindows defines the recurring windows in which disruptive
+	// actions are allowed. If no maintenance windows are defined, disruptive
+	// actions are allowed at any time outside the freeze windows.
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+
+	// FreezeWindows defines the periods in which no disruptive actions are
+	// allowed, even inside a maintenance window.
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+}
+
+// MaintenanceWindow defines a recurring window in which the operator is
+// allowed to perform disruptive actions.
+type MaintenanceWindow struct {
+	// Schedule defines the start of the window in the cron format, e.g.
+	// "0 2 * * 1-5" for 2am UTC on every weekday. A time zone can be
+	// defined with the CRON_TZ prefix, e.g. "CRON_TZ=Europe/Berlin 0 2 * * *".
+	Schedule string `json:"schedule"`
+
+	// Duration defines how long the window lasts after it started.
+	Duration metav1.Duration `json:"duration"`
+}
+
+// FreezeWindow defines a period in which the operator doesn't perform any
+// disruptive actions.
+type FreezeWindow struct {
+	// Start defines when the freeze starts.
+	Start metav1.Time `json:"start"`
+
+	// End defines when the freeze ends.
+	End metav1.Time `json:"end"`
+
+	// Reason describes why the disruptive actions are frozen.
+	Reason string `json:"reason,omitempty"`
+}
+
+// IsActive returns true if the provided time is inside the freeze window.
+func (window FreezeWindow) IsActive(now time.Time) bool {
+	return !now.Before(window.Start.Time) && now.Before(window.End.Time)
+}
+
+// PendingDisruptionsStatus contains the disruptive actions that are delayed
+// until disruptive actions are allowed again.
+type PendingDisruptionsStatus struct {
+	// Actions contains the names of the sub-reconcilers that have pending
+	// disruptive work.
+	Actions []string `json:"actions,omitempty"`
+
+	// Reason describes why the disruptive actions are not allowed.
+	Reason string `json:"reason,omitempty"`
+
+	// NextAllowedTime defines when disruptive actions are allowed again, if
+	// known.
+	NextAllowedTime *metav1.Time `json:"nextAllowedTime,omitempty"`
+}
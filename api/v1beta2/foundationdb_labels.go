@@ -33,10 +33,27 @@ const (
 	// timestamp when we saw an outdated config map.
 	OutdatedConfigMapKey = "foundationdb.org/outdated-config-map-seen"
 
+	// ConversionDataAnnotation provides the annotation name we use to store
+	// the fields of a resource that can't be represented in the API version
+	// the resource was converted to.
+	ConversionDataAnnotation = "foundationdb.org/conversion-data"
+
 	// BackupDeploymentLabel provides the label we use to connect backup
 	// deployments to a cluster.
 	BackupDeploymentLabel = "foundationdb.org/backup-for"
 
+	// DisasterRecoveryDeploymentLabel provides the label we use to connect DR
+	// agent deployments to a DR.
+	DisasterRecoveryDeploymentLabel = "foundationdb.org/dr-for"
+
+	// BlobCredentialsHashAnnotation provides the annotation name we use to
+	// store the hash of the blob store credentials on the backup agents.
+	BlobCredentialsHashAnnotation = "foundationdb.org/blob-credentials-hash"
+
+	// BackupVerificationLabel provides the label we use to connect the
+	// resources created for a backup verification to a backup.
+	BackupVerificationLabel = "foundationdb.org/backup-verification-for"
+
 	// PublicIPSourceAnnotation is an annotation key that specifies where a pod
 	// gets its public IP from.
 	PublicIPSourceAnnotation = "foundationdb.org/public-ip-source"
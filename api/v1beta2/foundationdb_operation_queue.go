@@ -0,0 +1,140 @@
+/*
+ * foundationdb_operation_queue.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"k8s.io/utils/pointer"
+)
+
+// ProcessGroupOperationType represents a disruptive action on a process
+// group that is planned through the operation queue.
+type ProcessGroupOperationType string
+
+const (
+	// ProcessGroupOperationReplacement represents the replacement of a
+	// misconfigured process group.
+	ProcessGroupOperationReplacement ProcessGroupOperationType = "Replacement"
+	// ProcessGroupOperationRemoval represents the removal of a process group
+	// when the cluster is shrunk.
+	ProcessGroupOperationRemoval ProcessGroupOperationType = "Removal"
+	// ProcessGroupOperationPodUpdate represents the recreation of a Pod with
+	// a new spec.
+	ProcessGroupOperationPodUpdate ProcessGroupOperationType = "PodUpdate"
+	// ProcessGroupOperationBounce represents the restart of the processes of
+	// a process group.
+	ProcessGroupOperationBounce ProcessGroupOperationType = "Bounce"
+)
+
+// ProcessGroupOperationTypes contains all operation types in the order of
+// their default priority, starting with the highest priority.
+var ProcessGroupOperationTypes = []ProcessGroupOperationType{
+	ProcessGroupOperationReplacement,
+	ProcessGroupOperationRemoval,
+	ProcessGroupOperationPodUpdate,
+	ProcessGroupOperationBounce,
+}
+
+// OperationQueueOptions controls the operation queue, which limits how many
+// process groups are disrupted at the same time across replacements,
+// removals, Pod updates and bounces.
+type OperationQueueOptions struct {
+	// Enabled defines whether the operator plans disruptive actions through
+	// the operation queue. The default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// MaxConcurrentOperations defines how many process groups can be
+	// disrupted at the same time in the whole cluster. If unset there is no
+	// limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentOperations *int `json:"maxConcurrentOperations,omitempty"`
+
+	// MaxOperationsPerFaultDomain defines how many process groups can be
+	// disrupted at the same time in a single fault domain. If unset there is
+	// no limit.
+	// +kubebuilder:validation:Minimum=1
+	MaxOperationsPerFaultDomain *int `json:"maxOperationsPerFaultDomain,omitempty"`
+
+	// MaxDisruptedFaultDomains defines how many fault domains can have
+	// disrupted process groups at the same time. The default is 1.
+	// +kubebuilder:validation:Minimum=1
+	MaxDisruptedFaultDomains *int `json:"maxDisruptedFaultDomains,omitempty"`
+
+	// Priorities overrides the priority of operation types. Operations with
+	// a higher priority are planned first. The defaults are 30 for
+	// replacements, 20 for removals, 10 for Pod updates and 0 for bounces.
+	Priorities map[ProcessGroupOperationType]int `json:"priorities,omitempty"`
+}
+
+// ProcessGroupOperation represents a disruptive action on a process group
+// that is waiting in the operation queue or is allowed to run.
+type ProcessGroupOperation struct {
+	// ProcessGroupID defines the process group the operation targets.
+	ProcessGroupID ProcessGroupID `json:"processGroupID"`
+
+	// Type defines the kind of the operation.
+	Type ProcessGroupOperationType `json:"type"`
+
+	// Priority defines the priority of the operation. Operations with a
+	// higher priority are planned first.
+	Priority int `json:"priority,omitempty"`
+
+	// Reason describes why the operation is needed.
+	Reason string `json:"reason,omitempty"`
+
+	// FaultDomain defines the fault domain of the process group.
+	FaultDomain string `json:"faultDomain,omitempty"`
+
+	// Timestamp defines when the operation was queued, as a Unix timestamp.
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// Allowed defines whether the operation is allowed to run in the current
+	// plan.
+	Allowed bool `json:"allowed,omitempty"`
+}
+
+// GetOperationQueueEnabled returns the value of
+// automationOptions.operationQueue.enabled or false if unset.
+func (cluster *FoundationDBCluster) GetOperationQueueEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.OperationQueue.Enabled, false)
+}
+
+// GetMaxDisruptedFaultDomains returns the value of
+// automationOptions.operationQueue.maxDisruptedFaultDomains or 1 if unset.
+func (cluster *FoundationDBCluster) GetMaxDisruptedFaultDomains() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.OperationQueue.MaxDisruptedFaultDomains, 1)
+}
+
+// GetOperationPriority returns the priority for the operation type, either
+// from automationOptions.operationQueue.priorities or the default priority.
+func (cluster *FoundationDBCluster) GetOperationPriority(operationType ProcessGroupOperationType) int {
+	priority, ok := cluster.Spec.AutomationOptions.OperationQueue.Priorities[operationType]
+	if ok {
+		return priority
+	}
+
+	for index, defaultType := range ProcessGroupOperationTypes {
+		if defaultType == operationType {
+			return (len(ProcessGroupOperationTypes) - index - 1) * 10
+		}
+	}
+
+	return 0
+}
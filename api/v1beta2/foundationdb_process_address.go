@@ -170,6 +170,15 @@ func ParseProcessAddress(address string) (ProcessAddress, error) {
 		return result, nil
 	}
 
+	// IPv6 addresses can also be provided in brackets without a port, e.g. "[::1]".
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		ip = net.ParseIP(address[1 : len(address)-1])
+		if ip != nil {
+			result.IPAddress = ip
+			return result, nil
+		}
+	}
+
 	// In order to find the address port pair we will go over the address stored in a tmp String.
 	// The idea is to split from the right to the left. If we find a Substring that is not a valid host port pair
 	// we can trim the last part and store it as a flag e.g. ":tls" and try the next substring with the flag removed.
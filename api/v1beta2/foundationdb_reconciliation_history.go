@@ -0,0 +1,100 @@
+/*
+ * foundationdb_reconciliation_history.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"k8s.io/utils/pointer"
+)
+
+// ReconciliationResult represents the outcome of a reconciliation run.
+type ReconciliationResult string
+
+const (
+	// ReconciliationResultCompleted represents a reconciliation run that
+	// reconciled the latest generation of the cluster.
+	ReconciliationResultCompleted ReconciliationResult = "Completed"
+	// ReconciliationResultRequeued represents a reconciliation run that ran
+	// all sub-reconcilers but didn't reconcile the latest generation of the
+	// cluster, e.g. because a sub-reconciler delayed the requeue.
+	ReconciliationResultRequeued ReconciliationResult = "Requeued"
+	// ReconciliationResultTerminatedEarly represents a reconciliation run
+	// that was stopped by a sub-reconciler.
+	ReconciliationResultTerminatedEarly ReconciliationResult = "TerminatedEarly"
+	// ReconciliationResultFailed represents a reconciliation run that was
+	// stopped by a sub-reconciler with an error.
+	ReconciliationResultFailed ReconciliationResult = "Failed"
+)
+
+// ReconciliationHistoryEntry represents a single reconciliation run of the
+// cluster. The operator keeps the most recent runs in the reconciliation
+// history ConfigMap of the cluster.
+type ReconciliationHistoryEntry struct {
+	// StartTimestamp defines when the reconciliation run started, as a Unix
+	// timestamp.
+	StartTimestamp int64 `json:"startTimestamp,omitempty"`
+
+	// EndTimestamp defines when the reconciliation run ended, as a Unix
+	// timestamp.
+	EndTimestamp int64 `json:"endTimestamp,omitempty"`
+
+	// Generation defines the generation of the cluster spec that was
+	// reconciled.
+	Generation int64 `json:"generation,omitempty"`
+
+	// ReconciledGeneration defines the last reconciled generation of the
+	// cluster at the end of the reconciliation run.
+	ReconciledGeneration int64 `json:"reconciledGeneration,omitempty"`
+
+	// Result defines the outcome of the reconciliation run.
+	Result ReconciliationResult `json:"result,omitempty"`
+
+	// Requeue contains the requeue of the sub-reconciler that stopped the
+	// reconciliation run.
+	Requeue *ReconciliationRequeue `json:"requeue,omitempty"`
+
+	// DelayedRequeues contains the requeues of the sub-reconcilers that
+	// delayed the requeue to the end of the reconciliation run.
+	DelayedRequeues []ReconciliationRequeue `json:"delayedRequeues,omitempty"`
+}
+
+// ReconciliationRequeue represents a requeue that was returned by a
+// sub-reconciler.
+type ReconciliationRequeue struct {
+	// SubReconciler defines the name of the sub-reconciler that returned the
+	// requeue.
+	SubReconciler string `json:"subReconciler"`
+
+	// Message defines the message of the requeue.
+	Message string `json:"message,omitempty"`
+
+	// Error defines the error of the requeue.
+	Error string `json:"error,omitempty"`
+
+	// DelaySeconds defines how long the operator waits before the next
+	// reconciliation run.
+	DelaySeconds int64 `json:"delaySeconds,omitempty"`
+}
+
+// GetReconciliationHistorySize returns the value of
+// automationOptions.reconciliationHistorySize or 10 if unset.
+func (cluster *FoundationDBCluster) GetReconciliationHistorySize() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.ReconciliationHistorySize, 10)
+}
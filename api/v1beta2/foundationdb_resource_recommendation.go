@@ -0,0 +1,116 @@
+/*
+ * foundationdb_resource_recommendation.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// ResourceRecommendationOptions controls the recommendations for the
+// resources of the processes.
+type ResourceRecommendationOptions struct {
+	// Enabled defines whether the operator gathers the resource usage of the
+	// processes and recommends resource requests and limits for every process
+	// class. The default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// HeadroomPercent defines how much headroom is added on top of the
+	// observed peak usage. The default is 20.
+	// +kubebuilder:validation:Minimum=0
+	HeadroomPercent *int `json:"headroomPercent,omitempty"`
+
+	// SampleIntervalSeconds defines the minimum time between two samples of
+	// the resource usage. The default is 300.
+	// +kubebuilder:validation:Minimum=0
+	SampleIntervalSeconds *int `json:"sampleIntervalSeconds,omitempty"`
+}
+
+// ResourceRecommendation contains the observed resource usage and the
+// recommended resources for the Pods of a process class.
+type ResourceRecommendation struct {
+	// ProcessClass defines the process class the recommendation is for.
+	ProcessClass ProcessClass `json:"processClass"`
+
+	// Samples defines how many samples of the resource usage were gathered.
+	Samples int `json:"samples,omitempty"`
+
+	// LastSampleTimestamp defines when the last sample was gathered.
+	LastSampleTimestamp int64 `json:"lastSampleTimestamp,omitempty"`
+
+	// Usage contains the observed resource usage of the busiest Pod of the
+	// process class.
+	Usage ResourceUsage `json:"usage,omitempty"`
+
+	// Requests defines the recommended resource requests for the main
+	// container.
+	Requests corev1.ResourceList `json:"requests,omitempty"`
+
+	// Limits defines the recommended resource limits for the main container.
+	Limits corev1.ResourceList `json:"limits,omitempty"`
+}
+
+// ResourceUsage contains the observed resource usage of a Pod.
+//
+// The peak values decay slowly with every sample, so a single spike will
+// not dominate the recommendation forever.
+type ResourceUsage struct {
+	// PeakCPUMillicores defines the peak CPU usage in millicores.
+	PeakCPUMillicores int64 `json:"peakCPUMillicores,omitempty"`
+
+	// AverageCPUMillicores defines the average CPU usage in millicores.
+	AverageCPUMillicores int64 `json:"averageCPUMillicores,omitempty"`
+
+	// PeakMemoryBytes defines the peak memory usage in bytes.
+	PeakMemoryBytes int64 `json:"peakMemoryBytes,omitempty"`
+
+	// AverageMemoryBytes defines the average memory usage in bytes.
+	AverageMemoryBytes int64 `json:"averageMemoryBytes,omitempty"`
+
+	// PeakDiskUsedBytes defines the peak disk usage in bytes.
+	PeakDiskUsedBytes int64 `json:"peakDiskUsedBytes,omitempty"`
+
+	// PeakQueueBytes defines the peak size of the storage and log queues in
+	// bytes.
+	PeakQueueBytes int64 `json:"peakQueueBytes,omitempty"`
+
+	// AverageQueueBytes defines the average size of the storage and log
+	// queues in bytes.
+	AverageQueueBytes int64 `json:"averageQueueBytes,omitempty"`
+}
+
+// GetResourceRecommendationsEnabled returns the value of
+// resourceRecommendations.enabled or false if unset.
+func (cluster *FoundationDBCluster) GetResourceRecommendationsEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.ResourceRecommendations.Enabled, false)
+}
+
+// GetResourceRecommendationHeadroomPercent returns the value of
+// resourceRecommendations.headroomPercent or 20 if unset.
+func (cluster *FoundationDBCluster) GetResourceRecommendationHeadroomPercent() int {
+	return pointer.IntDeref(cluster.Spec.ResourceRecommendations.HeadroomPercent, 20)
+}
+
+// GetResourceRecommendationSampleIntervalSeconds returns the value of
+// resourceRecommendations.sampleIntervalSeconds or 300 if unset.
+func (cluster *FoundationDBCluster) GetResourceRecommendationSampleIntervalSeconds() int {
+	return pointer.IntDeref(cluster.Spec.ResourceRecommendations.SampleIntervalSeconds, 300)
+}
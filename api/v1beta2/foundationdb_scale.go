@@ -0,0 +1,96 @@
+/*
+ * foundationdb_scale.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"fmt"
+)
+
+// ScaleOptions controls which process count is exposed through the scale
+// subresource of the cluster.
+type ScaleOptions struct {
+	// ProcessClass defines the process class whose process count is managed
+	// through the scale subresource. The default is storage.
+	ProcessClass ProcessClass `json:"processClass,omitempty"`
+
+	// Replicas defines the desired process count for the process class. If
+	// set, this takes precedence over the matching entry in processCounts.
+	// This field is changed by the scale subresource, e.g. by
+	// `kubectl scale` or a HorizontalPodAutoscaler. A process count of 0
+	// means that the default count is used, so the replicas must be at least
+	// 1.
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int `json:"replicas,omitempty"`
+}
+
+// ScaleStatus contains the information for the scale subresource of the
+// cluster.
+type ScaleStatus struct {
+	// Replicas defines the number of process groups of the scaled process
+	// class that are not marked for removal.
+	Replicas int `json:"replicas,omitempty"`
+
+	// Selector defines the label selector for the Pods of the scaled process
+	// class.
+	Selector string `json:"selector,omitempty"`
+}
+
+// validate checks that the scaled process class has a process count and
+// that the replicas are not 0, which would be interpreted as the default
+// process count.
+func (options ScaleOptions) validate() []string {
+	var validations []string
+	if options.Replicas != nil && *options.Replicas < 1 {
+		validations = append(validations, fmt.Sprintf("scale.replicas must be at least 1, got %d", *options.Replicas))
+	}
+
+	if options.ProcessClass == "" {
+		return validations
+	}
+
+	if _, ok := processClassIndices[options.ProcessClass]; !ok {
+		validations = append(validations, fmt.Sprintf("process class %s can't be used for the scale subresource", options.ProcessClass))
+	}
+
+	return validations
+}
+
+// GetScaleProcessClass returns the value of scale.processClass or storage if
+// unset.
+func (cluster *FoundationDBCluster) GetScaleProcessClass() ProcessClass {
+	if cluster.Spec.Scale.ProcessClass == "" {
+		return ProcessClassStorage
+	}
+
+	return cluster.Spec.Scale.ProcessClass
+}
+
+// SetProcessCount sets the desired process count for a process class. If the
+// process class is managed through the scale subresource and scale.replicas
+// is set, the replicas are changed instead of the process counts.
+func (cluster *FoundationDBCluster) SetProcessCount(processClass ProcessClass, count int) {
+	if cluster.Spec.Scale.Replicas != nil && cluster.GetScaleProcessClass() == processClass {
+		cluster.Spec.Scale.Replicas = &count
+		return
+	}
+
+	cluster.Spec.ProcessCounts.SetCount(processClass, count)
+}
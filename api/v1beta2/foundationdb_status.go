@@ -49,6 +49,10 @@ type FoundationDBStatusLocalClientInfo struct {
 type FoundationDBStatusCoordinatorInfo struct {
 	// Coordinators provides a list with coordinator details.
 	Coordinators []FoundationDBStatusCoordinator `json:"coordinators,omitempty"`
+
+	// QuorumReachable indicates whether a quorum of the coordinators is
+	// reachable.
+	QuorumReachable bool `json:"quorum_reachable,omitempty"`
 }
 
 // FoundationDBStatusCoordinator contains information about one of the
@@ -102,6 +106,47 @@ type FoundationDBStatusClusterInfo struct {
 
 	// ConnectionString represents the connection string in the cluster status json output.
 	ConnectionString string `json:"connection_string,omitempty"`
+
+	// Qos provides information about the quality of service of the cluster.
+	Qos FoundationDBStatusQosInfo `json:"qos,omitempty"`
+
+	// LatencyProbe provides the latencies measured by the latency probe.
+	LatencyProbe FoundationDBStatusLatencyProbe `json:"latency_probe,omitempty"`
+}
+
+// FoundationDBStatusQosInfo provides information about the quality of
+// service of the cluster.
+type FoundationDBStatusQosInfo struct {
+	// PerformanceLimitedBy provides the reason why ratekeeper limits the
+	// transaction rate.
+	PerformanceLimitedBy FoundationDBStatusPerformanceLimitedBy `json:"performance_limited_by,omitempty"`
+
+	// WorstQueueBytesStorageServer provides the size of the largest storage
+	// queue.
+	WorstQueueBytesStorageServer int64 `json:"worst_queue_bytes_storage_server,omitempty"`
+
+	// WorstQueueBytesLogServer provides the size of the largest log queue.
+	WorstQueueBytesLogServer int64 `json:"worst_queue_bytes_log_server,omitempty"`
+}
+
+// FoundationDBStatusPerformanceLimitedBy provides the reason why ratekeeper
+// limits the transaction rate.
+type FoundationDBStatusPerformanceLimitedBy struct {
+	// Name provides a machine-readable identifier for the reason.
+	Name string `json:"name,omitempty"`
+
+	// ReasonID provides a numeric identifier for the reason.
+	ReasonID int `json:"reason_id,omitempty"`
+}
+
+// FoundationDBStatusLatencyProbe provides the latencies measured by the
+// latency probe.
+type FoundationDBStatusLatencyProbe struct {
+	// CommitSeconds provides the latency of a commit.
+	CommitSeconds float64 `json:"commit_seconds,omitempty"`
+
+	// TransactionStartSeconds provides the latency to get a read version.
+	TransactionStartSeconds float64 `json:"transaction_start_seconds,omitempty"`
 }
 
 // FaultTolerance provides information about the fault tolerance status
@@ -142,6 +187,45 @@ type FoundationDBStatusProcessInfo struct {
 
 	// Messages contains error messages from that fdbserver process instance
 	Messages []FoundationDBStatusProcessMessage `json:"messages,omitempty"`
+
+	// CPU contains information about the CPU usage of the process.
+	CPU FoundationDBStatusProcessCPU `json:"cpu,omitempty"`
+
+	// Memory contains information about the memory usage of the process.
+	Memory FoundationDBStatusProcessMemory `json:"memory,omitempty"`
+
+	// Disk contains information about the disk usage of the process.
+	Disk FoundationDBStatusProcessDisk `json:"disk,omitempty"`
+}
+
+// FoundationDBStatusProcessCPU contains the CPU usage of a process.
+type FoundationDBStatusProcessCPU struct {
+	// UsageCores defines the number of cores the process is using.
+	UsageCores float64 `json:"usage_cores,omitempty"`
+}
+
+// FoundationDBStatusProcessMemory contains the memory usage of a process.
+type FoundationDBStatusProcessMemory struct {
+	// AvailableBytes defines the memory available to the process.
+	AvailableBytes int64 `json:"available_bytes,omitempty"`
+
+	// LimitBytes defines the memory limit of the process.
+	LimitBytes int64 `json:"limit_bytes,omitempty"`
+
+	// UsedBytes defines the memory used by the process.
+	UsedBytes int64 `json:"used_bytes,omitempty"`
+}
+
+// FoundationDBStatusProcessDisk contains the disk usage of a process.
+type FoundationDBStatusProcessDisk struct {
+	// Busy defines the fraction of time the disk was busy.
+	Busy float64 `json:"busy,omitempty"`
+
+	// FreeBytes defines the free space on the disk.
+	FreeBytes int64 `json:"free_bytes,omitempty"`
+
+	// TotalBytes defines the size of the disk.
+	TotalBytes int64 `json:"total_bytes,omitempty"`
 }
 
 // FoundationDBStatusProcessMessage represents an error message in the status json
@@ -161,6 +245,22 @@ type FoundationDBStatusProcessRoleInfo struct {
 	Role string `json:"role,omitempty"`
 	// StoredBytes defines the number of bytes that are currently stored for this process.
 	StoredBytes int `json:"stored_bytes,omitempty"`
+	// InputBytes defines the number of bytes that the role has received.
+	InputBytes FoundationDBStatusCounter `json:"input_bytes,omitempty"`
+	// DurableBytes defines the number of bytes that the role has made durable.
+	DurableBytes FoundationDBStatusCounter `json:"durable_bytes,omitempty"`
+	// KVStoreAvailableBytes defines the number of bytes that are still available
+	// for the key-value store of this role.
+	KVStoreAvailableBytes int64 `json:"kvstore_available_bytes,omitempty"`
+	// KVStoreTotalBytes defines the total number of bytes of the disk that is
+	// used by the key-value store of this role.
+	KVStoreTotalBytes int64 `json:"kvstore_total_bytes,omitempty"`
+}
+
+// FoundationDBStatusCounter represents a counter in the status json.
+type FoundationDBStatusCounter struct {
+	// Counter defines the current value of the counter.
+	Counter int64 `json:"counter,omitempty"`
 }
 
 // FoundationDBStatusDataStatistics provides information about the data in
@@ -300,6 +400,10 @@ type ProcessRole string
 const (
 	// ProcessRoleCoordinator model for FDB coordinator role.
 	ProcessRoleCoordinator ProcessRole = "coordinator"
+	// ProcessRoleStorage model for FDB storage role.
+	ProcessRoleStorage ProcessRole = "storage"
+	// ProcessRoleLog model for FDB log role.
+	ProcessRoleLog ProcessRole = "log"
 )
 
 // RecoveryState represents the recovery state from the FDB cluster json.
@@ -66,12 +66,25 @@ var _ = Describe("FoundationDBStatus", func() {
 								Reachable: true,
 							},
 						},
+						QuorumReachable: true,
 					},
 					DatabaseStatus: FoundationDBStatusClientDBStatus{Available: true, Healthy: true},
 				},
 				Cluster: FoundationDBStatusClusterInfo{
 					IncompatibleConnections: []string{},
 					ConnectionString:        "sample_cluster:JLjCjL6Vp3kWoIfHJeDZMhYqPBb1bIZr@10.1.38.94:4501,10.1.38.102:4501,10.1.38.104:4501",
+					Qos: FoundationDBStatusQosInfo{
+						PerformanceLimitedBy: FoundationDBStatusPerformanceLimitedBy{
+							Name:     "workload",
+							ReasonID: 2,
+						},
+						WorstQueueBytesStorageServer: 2006,
+						WorstQueueBytesLogServer:     190,
+					},
+					LatencyProbe: FoundationDBStatusLatencyProbe{
+						CommitSeconds:           0.00480127,
+						TransactionStartSeconds: 0.00218654,
+					},
 					FaultTolerance: FaultTolerance{
 						MaxZoneFailuresWithoutLosingAvailability: 1,
 						MaxZoneFailuresWithoutLosingData:         1,
@@ -102,9 +115,23 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 2955.58,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0370445},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 7990071296,
+								LimitBytes:     8589934592,
+								UsedBytes:      510480384,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
-									Role: "log",
+									Role:                  "log",
+									KVStoreAvailableBytes: 7176683520,
+									KVStoreTotalBytes:     8396963840,
+									InputBytes:            FoundationDBStatusCounter{Counter: 18381},
+									DurableBytes:          FoundationDBStatusCounter{Counter: 18191},
 								},
 							},
 							Messages: []FoundationDBStatusProcessMessage{},
@@ -125,12 +152,26 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 2475.33,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0494183},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 7836241920,
+								LimitBytes:     8589934592,
+								UsedBytes:      357195776,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
 									Role: "proxy",
 								},
 								{
-									Role: "storage",
+									Role:                  "storage",
+									KVStoreAvailableBytes: 7176683520,
+									KVStoreTotalBytes:     8396963840,
+									InputBytes:            FoundationDBStatusCounter{Counter: 46608},
+									DurableBytes:          FoundationDBStatusCounter{Counter: 46608},
 								},
 							},
 							Messages: []FoundationDBStatusProcessMessage{},
@@ -151,12 +192,26 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 2951.17,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0496311},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 7971037184,
+								LimitBytes:     8589934592,
+								UsedBytes:      492015616,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
 									Role: "proxy",
 								},
 								{
-									Role: "storage",
+									Role:                  "storage",
+									KVStoreAvailableBytes: 7176683520,
+									KVStoreTotalBytes:     8396963840,
+									InputBytes:            FoundationDBStatusCounter{Counter: 1021596},
+									DurableBytes:          FoundationDBStatusCounter{Counter: 1019590},
 								},
 							},
 							Messages: []FoundationDBStatusProcessMessage{},
@@ -177,12 +232,26 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 710.119,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0553955},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 7989477376,
+								LimitBytes:     8589934592,
+								UsedBytes:      510365696,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
 									Role: "cluster_controller",
 								},
 								{
-									Role: "log",
+									Role:                  "log",
+									KVStoreAvailableBytes: 7176683520,
+									KVStoreTotalBytes:     8396963840,
+									InputBytes:            FoundationDBStatusCounter{Counter: 296},
+									DurableBytes:          FoundationDBStatusCounter{Counter: 296},
 								},
 							},
 							Messages: []FoundationDBStatusProcessMessage{},
@@ -203,6 +272,16 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 1095.18,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0185648},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 7977865216,
+								LimitBytes:     8589934592,
+								UsedBytes:      498348032,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
 									Role: string(ProcessRoleCoordinator),
@@ -229,6 +308,16 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 880.18,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0932934},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 8000761856,
+								LimitBytes:     8589934592,
+								UsedBytes:      521166848,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
 									Role: "master",
@@ -243,7 +332,11 @@ var _ = Describe("FoundationDBStatus", func() {
 									Role: string(ProcessRoleCoordinator),
 								},
 								{
-									Role: "log",
+									Role:                  "log",
+									KVStoreAvailableBytes: 7176683520,
+									KVStoreTotalBytes:     8396963840,
+									InputBytes:            FoundationDBStatusCounter{Counter: 18381},
+									DurableBytes:          FoundationDBStatusCounter{Counter: 18191},
 								},
 							},
 							Messages: []FoundationDBStatusProcessMessage{},
@@ -264,6 +357,16 @@ var _ = Describe("FoundationDBStatus", func() {
 							},
 							Version:       "6.2.15",
 							UptimeSeconds: 2650.5,
+							CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.057441799999999994},
+							Memory: FoundationDBStatusProcessMemory{
+								AvailableBytes: 7972458496,
+								LimitBytes:     8589934592,
+								UsedBytes:      492867584,
+							},
+							Disk: FoundationDBStatusProcessDisk{
+								FreeBytes:  7176683520,
+								TotalBytes: 8396963840,
+							},
 							Roles: []FoundationDBStatusProcessRoleInfo{
 								{
 									Role: string(ProcessRoleCoordinator),
@@ -272,7 +375,11 @@ var _ = Describe("FoundationDBStatus", func() {
 									Role: "proxy",
 								},
 								{
-									Role: "storage",
+									Role:                  "storage",
+									KVStoreAvailableBytes: 7176683520,
+									KVStoreTotalBytes:     8396963840,
+									InputBytes:            FoundationDBStatusCounter{Counter: 890158},
+									DurableBytes:          FoundationDBStatusCounter{Counter: 890158},
 								},
 							},
 							Messages: []FoundationDBStatusProcessMessage{},
@@ -473,6 +580,18 @@ var _ = Describe("FoundationDBStatus", func() {
 		status := FoundationDBStatusClusterInfo{
 			IncompatibleConnections: []string{},
 			ConnectionString:        "test_cluster:aHeD9ocNXOUxi0dyzU3k7Bhg53SpyrBV@10.1.18.253:4501,10.1.18.254:4501,10.1.19.0:4501",
+			Qos: FoundationDBStatusQosInfo{
+				PerformanceLimitedBy: FoundationDBStatusPerformanceLimitedBy{
+					Name:     "workload",
+					ReasonID: 2,
+				},
+				WorstQueueBytesStorageServer: 1996,
+				WorstQueueBytesLogServer:     12144,
+			},
+			LatencyProbe: FoundationDBStatusLatencyProbe{
+				CommitSeconds:           0.00458646,
+				TransactionStartSeconds: 0.00389361,
+			},
 			DatabaseConfiguration: DatabaseConfiguration{
 				RedundancyMode:  "double",
 				StorageEngine:   StorageEngineSSD2,
@@ -499,10 +618,21 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0026,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.036252700000000006},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      189898752,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.00979976,
+						FreeBytes:  84178145280,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
 						{Role: "coordinator"},
 						{Role: "grv_proxy"},
-						{Role: "storage"},
+						{Role: "storage", InputBytes: FoundationDBStatusCounter{Counter: 77854}, DurableBytes: FoundationDBStatusCounter{Counter: 75858}, KVStoreAvailableBytes: 84178223104, KVStoreTotalBytes: 135012552704},
 					},
 					Messages: []FoundationDBStatusProcessMessage{},
 				},
@@ -522,9 +652,20 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0031,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0126458},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      196194304,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.00979973,
+						FreeBytes:  84178145280,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
 						{Role: "coordinator"},
-						{Role: string(ProcessClassStorage)},
+						{Role: string(ProcessClassStorage), InputBytes: FoundationDBStatusCounter{Counter: 77854}, DurableBytes: FoundationDBStatusCounter{Counter: 75858}, KVStoreAvailableBytes: 84178239488, KVStoreTotalBytes: 135012552704},
 						{Role: "resolver"},
 					},
 					Messages: []FoundationDBStatusProcessMessage{},
@@ -545,10 +686,21 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0029,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.016351300000000003},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      196325376,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.0097998,
+						FreeBytes:  84178145280,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
 						{Role: "coordinator"},
 						{Role: "commit_proxy"},
-						{Role: "storage"},
+						{Role: "storage", InputBytes: FoundationDBStatusCounter{Counter: 1106}, DurableBytes: FoundationDBStatusCounter{Counter: 1106}, KVStoreAvailableBytes: 84178112512, KVStoreTotalBytes: 135012552704},
 					},
 					Messages: []FoundationDBStatusProcessMessage{},
 				},
@@ -568,6 +720,17 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0027,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0418108},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      141787136,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.0101997,
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
 						{Role: "master"},
 						{Role: "data_distributor"},
@@ -591,6 +754,17 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0029,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.011798900000000001},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      142704640,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.0101994,
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
 						{Role: string(ProcessClassClusterController)},
 					},
@@ -612,8 +786,19 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0029,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.012726600000000001},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      216772608,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.0101993,
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
-						{Role: "log"},
+						{Role: "log", InputBytes: FoundationDBStatusCounter{Counter: 1512}, DurableBytes: FoundationDBStatusCounter{Counter: 255}, KVStoreAvailableBytes: 84178214912, KVStoreTotalBytes: 135012552704},
 					},
 					Messages: []FoundationDBStatusProcessMessage{},
 				},
@@ -633,8 +818,19 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.003,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0137228},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      197763072,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.0101996,
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
-						{Role: "log"},
+						{Role: "log", InputBytes: FoundationDBStatusCounter{Counter: 14551}, DurableBytes: FoundationDBStatusCounter{Counter: 3264}, KVStoreAvailableBytes: 84178214912, KVStoreTotalBytes: 135012552704},
 					},
 					Messages: []FoundationDBStatusProcessMessage{},
 				},
@@ -654,8 +850,19 @@ var _ = Describe("FoundationDBStatus", func() {
 					},
 					Version:       "7.1.0-rc1",
 					UptimeSeconds: 85.0027,
+					CPU:           FoundationDBStatusProcessCPU{UsageCores: 0.0140474},
+					Memory: FoundationDBStatusProcessMemory{
+						AvailableBytes: 8589934592,
+						LimitBytes:     8589934592,
+						UsedBytes:      210481152,
+					},
+					Disk: FoundationDBStatusProcessDisk{
+						Busy:       0.0101996,
+						FreeBytes:  84178165760,
+						TotalBytes: 135012552704,
+					},
 					Roles: []FoundationDBStatusProcessRoleInfo{
-						{Role: "log"},
+						{Role: "log", InputBytes: FoundationDBStatusCounter{Counter: 15459}, DurableBytes: FoundationDBStatusCounter{Counter: 3315}, KVStoreAvailableBytes: 84178202624, KVStoreTotalBytes: 135012552704},
 					},
 					Messages: []FoundationDBStatusProcessMessage{},
 				},
@@ -207,6 +207,12 @@ func (version Version) SupportsRecoveryState() bool {
 	return version.IsAtLeast(Versions.SupportsRecoveryState)
 }
 
+// SupportsTenants returns true if the version of FDB supports tenants and
+// tenant groups.
+func (version Version) SupportsTenants() bool {
+	return version.IsAtLeast(Versions.SupportsTenants)
+}
+
 // Versions provides a shorthand for known versions.
 // This is only to be used in testing.
 var Versions = struct {
@@ -219,6 +225,7 @@ var Versions = struct {
 	IncompatibleVersion,
 	PreviousPatchVersion,
 	SupportsRecoveryState,
+	SupportsTenants,
 	Default Version
 }{
 	Default:                Version{Major: 6, Minor: 2, Patch: 21},
@@ -231,4 +238,5 @@ var Versions = struct {
 	SupportsIsPresent:      Version{Major: 7, Minor: 1, Patch: 4},
 	SupportsShardedRocksDB: Version{Major: 7, Minor: 2, Patch: 0},
 	SupportsRecoveryState:  Version{Major: 7, Minor: 1, Patch: 22},
+	SupportsTenants:        Version{Major: 7, Minor: 2, Patch: 0},
 }
@@ -0,0 +1,131 @@
+/*
+Copyright 2023 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdbaudit
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster that the action was taken on",priority=0
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="Disruptive action that was taken",priority=0
+// +kubebuilder:printcolumn:name="Outcome",type="string",JSONPath=".spec.outcome",description="Outcome of the action",priority=0
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.reason",description="Reason for the action",priority=1
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBAuditEvent is the Schema for the foundationdbauditevents API.
+// The operator creates an audit event for every disruptive action if the
+// Kubernetes audit sink is enabled.
+type FoundationDBAuditEvent struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AuditEntry `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBAuditEventList contains a list of FoundationDBAuditEvent objects
+type FoundationDBAuditEventList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBAuditEvent `json:"items"`
+}
+
+// AuditEntry describes a single disruptive action that the operator took on
+// a cluster.
+type AuditEntry struct {
+	// Timestamp defines when the action was taken.
+	Timestamp metav1.Time `json:"timestamp"`
+
+	// Namespace defines the namespace of the cluster.
+	Namespace string `json:"namespace"`
+
+	// ClusterName defines the name of the cluster.
+	ClusterName string `json:"clusterName"`
+
+	// Action defines the disruptive action that was taken.
+	Action AuditAction `json:"action"`
+
+	// Targets defines the process addresses, coordinators or resource names
+	// that the action was taken on.
+	Targets []string `json:"targets,omitempty"`
+
+	// Reason defines why the operator took the action.
+	Reason string `json:"reason,omitempty"`
+
+	// OperatorInstance defines the instance of the operator that took the
+	// action.
+	OperatorInstance string `json:"operatorInstance,omitempty"`
+
+	// Outcome defines whether the action succeeded.
+	Outcome AuditOutcome `json:"outcome"`
+
+	// Error contains the error message if the action failed.
+	Error string `json:"error,omitempty"`
+}
+
+// AuditAction defines a disruptive action that is recorded in the audit log.
+type AuditAction string
+
+const (
+	// AuditActionExcludeProcesses represents the exclusion of processes.
+	AuditActionExcludeProcesses AuditAction = "ExcludeProcesses"
+
+	// AuditActionIncludeProcesses represents the inclusion of processes.
+	AuditActionIncludeProcesses AuditAction = "IncludeProcesses"
+
+	// AuditActionKillProcesses represents the restart of processes.
+	AuditActionKillProcesses AuditAction = "KillProcesses"
+
+	// AuditActionChangeCoordinators represents a change of the coordinators.
+	AuditActionChangeCoordinators AuditAction = "ChangeCoordinators"
+
+	// AuditActionConfigureDatabase represents a change of the database
+	// configuration.
+	AuditActionConfigureDatabase AuditAction = "ConfigureDatabase"
+
+	// AuditActionSetMaintenanceZone represents setting the maintenance zone.
+	AuditActionSetMaintenanceZone AuditAction = "SetMaintenanceZone"
+
+	// AuditActionResetMaintenanceMode represents resetting the maintenance
+	// mode.
+	AuditActionResetMaintenanceMode AuditAction = "ResetMaintenanceMode"
+
+	// AuditActionDeletePod represents the deletion of Pods.
+	AuditActionDeletePod AuditAction = "DeletePod"
+
+	// AuditActionDeletePVC represents the deletion of PVCs.
+	AuditActionDeletePVC AuditAction = "DeletePVC"
+)
+
+// AuditOutcome defines the outcome of a disruptive action.
+type AuditOutcome string
+
+const (
+	// AuditOutcomeSucceeded represents an action that succeeded.
+	AuditOutcomeSucceeded AuditOutcome = "Succeeded"
+
+	// AuditOutcomeFailed represents an action that failed.
+	AuditOutcomeFailed AuditOutcome = "Failed"
+)
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBAuditEvent{}, &FoundationDBAuditEventList{})
+}
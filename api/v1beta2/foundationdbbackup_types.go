@@ -18,9 +18,13 @@ package v1beta2
 
 import (
 	"fmt"
+	"net/url"
+	"path"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -101,6 +105,14 @@ type FoundationDBBackupSpec struct {
 	// SidecarContainer defines customization for the
 	// foundationdb-kubernetes-sidecar container.
 	SidecarContainer ContainerOverrides `json:"sidecarContainer,omitempty"`
+
+	// Verification defines the configuration for periodically verifying that
+	// the backup can be restored into a temporary cluster.
+	Verification *BackupVerificationConfiguration `json:"verification,omitempty"`
+
+	// Alerting defines the thresholds for the conditions that the operator
+	// sets in the backup status.
+	Alerting *BackupAlertingConfiguration `json:"alerting,omitempty"`
 }
 
 // FoundationDBBackupStatus describes the current status of the backup for a cluster.
@@ -120,6 +132,16 @@ type FoundationDBBackupStatus struct {
 	// Generations provides information about the latest generation to be
 	// reconciled, or to reach other stages in reconciliation.
 	Generations BackupGenerationStatus `json:"generations,omitempty"`
+
+	// Verification provides information about the current and the last
+	// backup verification.
+	Verification *BackupVerificationStatus `json:"verification,omitempty"`
+
+	// Conditions provides signals about the health of the backup, like
+	// whether the backup is lagging behind or whether agents are down.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // FoundationDBBackupStatusBackupDetails provides information about the state
@@ -129,6 +151,31 @@ type FoundationDBBackupStatusBackupDetails struct {
 	Running               bool   `json:"running,omitempty"`
 	Paused                bool   `json:"paused,omitempty"`
 	SnapshotPeriodSeconds int    `json:"snapshotTime,omitempty"`
+
+	// LatestRestorableVersion provides the latest version that the backup
+	// can be restored to.
+	LatestRestorableVersion *int64 `json:"latestRestorableVersion,omitempty"`
+
+	// LatestRestorableTime provides the time of the latest version that the
+	// backup can be restored to.
+	LatestRestorableTime *metav1.Time `json:"latestRestorableTime,omitempty"`
+
+	// SecondsBehind provides how far the latest restorable version is behind
+	// the cluster.
+	SecondsBehind int64 `json:"secondsBehind,omitempty"`
+
+	// CurrentSnapshotStartTime provides the time when the snapshot that is
+	// currently written was started.
+	CurrentSnapshotStartTime *metav1.Time `json:"currentSnapshotStartTime,omitempty"`
+
+	// SnapshotProgressPercent provides the expected progress of the snapshot
+	// that is currently written.
+	SnapshotProgressPercent int `json:"snapshotProgressPercent,omitempty"`
+
+	// Errors provides the most recent errors that were reported by the
+	// backup agents.
+	// +kubebuilder:validation:MaxItems=10
+	Errors []string `json:"errors,omitempty"`
 }
 
 // BackupGenerationStatus stores information on which generations have reached
@@ -171,10 +218,151 @@ const (
 	BackupStateStopped BackupState = "Stopped"
 )
 
+// BackupConditionType defines the type of a condition in the backup status.
+type BackupConditionType string
+
+const (
+	// BackupLagging is set to true when the latest restorable version of the
+	// backup is further behind than the lag threshold.
+	BackupLagging BackupConditionType = "BackupLagging"
+
+	// BackupSnapshotStale is set to true when the current snapshot was started
+	// longer ago than the snapshot stale threshold.
+	BackupSnapshotStale BackupConditionType = "SnapshotStale"
+
+	// BackupAgentsDown is set to true when fewer backup agents are ready than
+	// desired.
+	BackupAgentsDown BackupConditionType = "AgentsDown"
+
+	// BackupReconciled is set to true when the latest generation of the spec
+	// is reconciled.
+	BackupReconciled BackupConditionType = "Reconciled"
+)
+
+// MaxBackupStatusErrors defines how many of the errors reported by the backup
+// agents are kept in the backup status.
+const MaxBackupStatusErrors = 10
+
+// BackupAlertingConfiguration defines the thresholds for the conditions in the
+// backup status.
+type BackupAlertingConfiguration struct {
+	// LagThresholdSeconds defines how far the latest restorable version can
+	// be behind the cluster before the BackupLagging condition is set.
+	// The default is 600, or 10 minutes.
+	// +kubebuilder:validation:Minimum=0
+	LagThresholdSeconds *int `json:"lagThresholdSeconds,omitempty"`
+
+	// SnapshotStaleThresholdSeconds defines how long the current snapshot
+	// can be running before the SnapshotStale condition is set.
+	// The default is twice the snapshot period.
+	// +kubebuilder:validation:Minimum=0
+	SnapshotStaleThresholdSeconds *int `json:"snapshotStaleThresholdSeconds,omitempty"`
+}
+
+// BackupVerificationConfiguration describes how the operator verifies that a
+// backup can be restored.
+type BackupVerificationConfiguration struct {
+	// IntervalSeconds defines the time between the start of two verification
+	// runs.
+	// The default is 604,800, or 7 days.
+	// +kubebuilder:validation:Minimum=3600
+	IntervalSeconds *int `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds defines the maximum duration of a verification run. If
+	// the run doesn't complete within this time, it is recorded as failed and
+	// the temporary resources are removed.
+	// The default is 86,400, or 1 day.
+	// +kubebuilder:validation:Minimum=600
+	TimeoutSeconds *int `json:"timeoutSeconds,omitempty"`
+
+	// ClusterTemplate defines the spec of the temporary cluster that the
+	// backup will be restored into.
+	ClusterTemplate FoundationDBClusterSpec `json:"clusterTemplate"`
+
+	// CheckPodTemplateSpec defines an optional pod template for a Job that
+	// runs against the restored cluster, e.g. to compare checksums or row
+	// counts. The cluster file of the temporary cluster is provided through
+	// the FDB_CLUSTER_FILE environment variable. The verification only
+	// passes if this Job succeeds.
+	CheckPodTemplateSpec *corev1.PodTemplateSpec `json:"checkPodTemplateSpec,omitempty"`
+}
+
+// BackupVerificationPhase describes the phase of a verification run.
+type BackupVerificationPhase string
+
+const (
+	// BackupVerificationPhaseCreatingCluster defines the phase where the
+	// operator waits for the temporary cluster to be reconciled.
+	BackupVerificationPhaseCreatingCluster BackupVerificationPhase = "CreatingCluster"
+	// BackupVerificationPhaseRestoring defines the phase where the operator
+	// waits for the restore into the temporary cluster to complete.
+	BackupVerificationPhaseRestoring BackupVerificationPhase = "Restoring"
+	// BackupVerificationPhaseChecking defines the phase where the operator
+	// waits for the check Job to complete.
+	BackupVerificationPhaseChecking BackupVerificationPhase = "Checking"
+)
+
+// BackupVerificationStatus provides information about the backup
+// verification.
+type BackupVerificationStatus struct {
+	// Phase provides the phase of the current verification run. This will be
+	// empty if no verification is running.
+	Phase BackupVerificationPhase `json:"phase,omitempty"`
+
+	// ClusterName provides the name of the temporary cluster used by the
+	// current verification run.
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// StartTime provides the time when the current verification run was
+	// started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// LastResult provides the result of the last completed verification run.
+	LastResult *BackupVerificationResult `json:"lastResult,omitempty"`
+}
+
+// BackupVerificationResult describes the result of a completed verification
+// run.
+type BackupVerificationResult struct {
+	// Passed defines whether the backup was successfully restored and
+	// checked.
+	Passed bool `json:"passed"`
+
+	// StartTime provides the time when the verification run was started.
+	StartTime metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime provides the time when the verification run was
+	// completed.
+	CompletionTime metav1.Time `json:"completionTime,omitempty"`
+
+	// DurationSeconds provides the duration of the verification run.
+	DurationSeconds int `json:"durationSeconds,omitempty"`
+
+	// Message provides details about the result of the verification run.
+	Message string `json:"message,omitempty"`
+}
+
 // URLParameter defines a single URL parameter to pass to the blobstore.
 // +kubebuilder:validation:MaxLength=1024
 type URLParameter string
 
+// BlobStoreBackend defines the type of the backup destination.
+// +kubebuilder:validation:Enum=blobstore;file
+type BlobStoreBackend string
+
+const (
+	// BlobStoreBackendBlobStore defines an S3-compatible object store as
+	// backup destination.
+	BlobStoreBackendBlobStore BlobStoreBackend = "blobstore"
+	// BlobStoreBackendFile defines a directory on a PersistentVolumeClaim as
+	// backup destination.
+	BlobStoreBackendFile BlobStoreBackend = "file"
+)
+
+// FileBackupMountPath defines the path where the PersistentVolumeClaim for
+// file backups is mounted in the backup agents.
+const FileBackupMountPath = "/var/fdb-backups"
+
 // BlobStoreConfiguration describes the blob store configuration.
 type BlobStoreConfiguration struct {
 	// The name for the backup.
@@ -182,10 +370,14 @@ type BlobStoreConfiguration struct {
 	// +kubebuilder:validation:MaxLength=1024
 	BackupName string `json:"backupName,omitempty"`
 
+	// Backend defines the type of the backup destination.
+	// The default is blobstore.
+	Backend BlobStoreBackend `json:"backend,omitempty"`
+
 	// The account name to use with the backup destination.
+	// This is required for the blobstore backend.
 	// +kubebuilder:validation:MaxLength=100
-	// +kubebuilder:validation:Required
-	AccountName string `json:"accountName"`
+	AccountName string `json:"accountName,omitempty"`
 
 	// The backup bucket to write to.
 	// The default is "fdb-backups".
@@ -196,6 +388,18 @@ type BlobStoreConfiguration struct {
 	// Additional URL parameters passed to the blobstore URL.
 	// +kubebuilder:validation:MaxItems=100
 	URLParameters []URLParameter `json:"urlParameters,omitempty"`
+
+	// CredentialsSecret references the key of a Secret that contains the
+	// credentials file for the blobstore backend. The operator mounts this
+	// Secret into the backup agents and sets FDB_BLOB_CREDENTIALS. Changes to
+	// the Secret will cause the backup agents to be updated.
+	CredentialsSecret *corev1.SecretKeySelector `json:"credentialsSecret,omitempty"`
+
+	// VolumeClaimName defines the name of the PersistentVolumeClaim that
+	// stores the backups for the file backend. The operator mounts this
+	// PersistentVolumeClaim into the backup agents. Restores from the file
+	// backend are not supported.
+	VolumeClaimName string `json:"volumeClaimName,omitempty"`
 }
 
 // ShouldRun determines whether a backup should be running.
@@ -238,6 +442,45 @@ func (backup *FoundationDBBackup) SnapshotPeriodSeconds() int {
 	return pointer.IntDeref(backup.Spec.SnapshotPeriodSeconds, 864000)
 }
 
+// ShouldVerify determines whether the backup should be verified.
+func (backup *FoundationDBBackup) ShouldVerify() bool {
+	return backup.Spec.Verification != nil && backup.ShouldRun()
+}
+
+// VerificationInterval gets the time between two verification runs.
+func (backup *FoundationDBBackup) VerificationInterval() time.Duration {
+	if backup.Spec.Verification == nil {
+		return 0
+	}
+
+	return time.Duration(pointer.IntDeref(backup.Spec.Verification.IntervalSeconds, 604800)) * time.Second
+}
+
+// VerificationTimeout gets the maximum duration of a verification run.
+func (backup *FoundationDBBackup) VerificationTimeout() time.Duration {
+	if backup.Spec.Verification == nil {
+		return 0
+	}
+
+	return time.Duration(pointer.IntDeref(backup.Spec.Verification.TimeoutSeconds, 86400)) * time.Second
+}
+
+// VerificationClusterName gets the name of the temporary cluster that is used
+// to verify the backup.
+func (backup *FoundationDBBackup) VerificationClusterName() string {
+	return fmt.Sprintf("%s-verification", backup.ObjectMeta.Name)
+}
+
+// NextVerificationTime gets the time when the next verification run should be
+// started.
+func (backup *FoundationDBBackup) NextVerificationTime() time.Time {
+	if backup.Status.Verification == nil || backup.Status.Verification.LastResult == nil {
+		return time.Time{}
+	}
+
+	return backup.Status.Verification.LastResult.StartTime.Add(backup.VerificationInterval())
+}
+
 // FoundationDBLiveBackupStatus describes the live status of the backup for a
 // cluster, as provided by the backup status command.
 type FoundationDBLiveBackupStatus struct {
@@ -252,6 +495,62 @@ type FoundationDBLiveBackupStatus struct {
 
 	// BackupAgentsPaused describes whether the backup agents are paused.
 	BackupAgentsPaused bool `json:"BackupAgentsPaused,omitempty"`
+
+	// LatestRestorablePoint provides the latest point that the backup can be
+	// restored to.
+	LatestRestorablePoint *FoundationDBLiveBackupStatusRestorablePoint `json:"LatestRestorablePoint,omitempty"`
+
+	// CurrentSnapshot provides information about the snapshot that is
+	// currently written.
+	CurrentSnapshot *FoundationDBLiveBackupStatusSnapshot `json:"CurrentSnapshot,omitempty"`
+
+	// Errors provides the errors that were recently reported by the backup
+	// agents.
+	Errors []FoundationDBLiveBackupStatusError `json:"Errors,omitempty"`
+}
+
+// FoundationDBLiveBackupStatusVersion provides a version and the time of
+// that version in the backup status.
+type FoundationDBLiveBackupStatusVersion struct {
+	// Version provides the version.
+	Version int64 `json:"Version,omitempty"`
+
+	// EpochSeconds provides the time of the version as unix timestamp.
+	EpochSeconds float64 `json:"EpochSeconds,omitempty"`
+}
+
+// FoundationDBLiveBackupStatusRestorablePoint provides the latest point that
+// a backup can be restored to.
+type FoundationDBLiveBackupStatusRestorablePoint struct {
+	FoundationDBLiveBackupStatusVersion `json:",inline"`
+
+	// LagSeconds provides how far the restorable point is behind the
+	// cluster.
+	LagSeconds float64 `json:"LagSeconds,omitempty"`
+}
+
+// FoundationDBLiveBackupStatusSnapshot provides information about a snapshot
+// in the backup status.
+type FoundationDBLiveBackupStatusSnapshot struct {
+	// Begin provides the version at which the snapshot was started.
+	Begin FoundationDBLiveBackupStatusVersion `json:"Begin,omitempty"`
+
+	// IntervalSeconds provides the target duration of the snapshot.
+	IntervalSeconds int `json:"IntervalSeconds,omitempty"`
+
+	// ExpectedProgress provides the expected progress of the snapshot in
+	// percent.
+	ExpectedProgress float64 `json:"ExpectedProgress,omitempty"`
+}
+
+// FoundationDBLiveBackupStatusError provides an error that was reported by
+// the backup agents.
+type FoundationDBLiveBackupStatusError struct {
+	// Message provides the error message.
+	Message string `json:"Message,omitempty"`
+
+	// RelativeSeconds provides how long ago the error was reported.
+	RelativeSeconds float64 `json:"RelativeSeconds,omitempty"`
 }
 
 // FoundationDBLiveBackupStatusState provides the state of a backup in the
@@ -261,6 +560,109 @@ type FoundationDBLiveBackupStatusState struct {
 	Running bool `json:"Running,omitempty"`
 }
 
+// GetLagThresholdSeconds gets how far the latest restorable version can be
+// behind before the backup is considered to be lagging.
+func (backup *FoundationDBBackup) GetLagThresholdSeconds() int {
+	if backup.Spec.Alerting == nil {
+		return 600
+	}
+
+	return pointer.IntDeref(backup.Spec.Alerting.LagThresholdSeconds, 600)
+}
+
+// GetSnapshotStaleThresholdSeconds gets how long a snapshot can be running
+// before it is considered to be stale.
+func (backup *FoundationDBBackup) GetSnapshotStaleThresholdSeconds() int {
+	defaultThreshold := 2 * backup.SnapshotPeriodSeconds()
+	if backup.Spec.Alerting == nil {
+		return defaultThreshold
+	}
+
+	return pointer.IntDeref(backup.Spec.Alerting.SnapshotStaleThresholdSeconds, defaultThreshold)
+}
+
+// UpdateConditions sets the conditions in the backup status based on the
+// backup details, the agent count and the generations in the status. This
+// must be called after CheckReconciliation.
+func (backup *FoundationDBBackup) UpdateConditions(now time.Time) {
+	details := backup.Status.BackupDetails
+	isRunning := details != nil && details.Running
+
+	lagging := metav1.Condition{
+		Type:   string(BackupLagging),
+		Status: metav1.ConditionFalse,
+		Reason: "WithinThreshold",
+	}
+	if isRunning && details.SecondsBehind > int64(backup.GetLagThresholdSeconds()) {
+		lagging.Status = metav1.ConditionTrue
+		lagging.Reason = "ThresholdExceeded"
+		lagging.Message = fmt.Sprintf("Latest restorable version is %d seconds behind, threshold is %d seconds", details.SecondsBehind, backup.GetLagThresholdSeconds())
+	}
+
+	stale := metav1.Condition{
+		Type:   string(BackupSnapshotStale),
+		Status: metav1.ConditionFalse,
+		Reason: "WithinThreshold",
+	}
+	if isRunning && details.CurrentSnapshotStartTime != nil {
+		snapshotAge := now.Sub(details.CurrentSnapshotStartTime.Time)
+		if snapshotAge > time.Duration(backup.GetSnapshotStaleThresholdSeconds())*time.Second {
+			stale.Status = metav1.ConditionTrue
+			stale.Reason = "ThresholdExceeded"
+			stale.Message = fmt.Sprintf("Current snapshot was started %s ago, threshold is %d seconds", snapshotAge.Round(time.Second), backup.GetSnapshotStaleThresholdSeconds())
+		}
+	}
+
+	agentsDown := metav1.Condition{
+		Type:   string(BackupAgentsDown),
+		Status: metav1.ConditionFalse,
+		Reason: "AgentsReady",
+	}
+	if backup.Status.AgentCount < backup.GetDesiredAgentCount() {
+		agentsDown.Status = metav1.ConditionTrue
+		agentsDown.Reason = "AgentsNotReady"
+		agentsDown.Message = fmt.Sprintf("%d of %d backup agents are ready", backup.Status.AgentCount, backup.GetDesiredAgentCount())
+	}
+
+	reconciled := metav1.Condition{
+		Type:    string(BackupReconciled),
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReconciliationComplete",
+		Message: fmt.Sprintf("Generation %d is reconciled", backup.ObjectMeta.Generation),
+	}
+	if backup.Status.Generations.Reconciled != backup.ObjectMeta.Generation {
+		reconciled.Status = metav1.ConditionFalse
+		reconciled.Reason = backup.getPendingReconciliationReason()
+		reconciled.Message = fmt.Sprintf("Generation %d is not reconciled", backup.ObjectMeta.Generation)
+	}
+
+	for _, condition := range []metav1.Condition{lagging, stale, agentsDown, reconciled} {
+		condition.ObservedGeneration = backup.ObjectMeta.Generation
+		condition.LastTransitionTime = metav1.NewTime(now)
+		meta.SetStatusCondition(&backup.Status.Conditions, condition)
+	}
+}
+
+// getPendingReconciliationReason returns the name of the first generation
+// field in the status that blocks the reconciliation of the backup.
+func (backup *FoundationDBBackup) getPendingReconciliationReason() string {
+	generations := backup.Status.Generations
+	switch {
+	case generations.NeedsBackupAgentUpdate > 0:
+		return "NeedsBackupAgentUpdate"
+	case generations.NeedsBackupStart > 0:
+		return "NeedsBackupStart"
+	case generations.NeedsBackupStop > 0:
+		return "NeedsBackupStop"
+	case generations.NeedsBackupPauseToggle > 0:
+		return "NeedsBackupPauseToggle"
+	case generations.NeedsBackupReconfiguration > 0:
+		return "NeedsBackupReconfiguration"
+	default:
+		return "ReconciliationPending"
+	}
+}
+
 // GetDesiredAgentCount determines how many backup agents we should run
 // for a cluster.
 func (backup *FoundationDBBackup) GetDesiredAgentCount() int {
@@ -315,8 +717,22 @@ func (foundationDBBackupSpec *FoundationDBBackupSpec) GetAllowTagOverride() bool
 	return pointer.BoolDeref(foundationDBBackupSpec.AllowTagOverride, false)
 }
 
+// GetBackend returns the backend of the backup destination.
+// This will fill in a default value if the backend is empty.
+func (configuration *BlobStoreConfiguration) GetBackend() BlobStoreBackend {
+	if configuration.Backend == "" {
+		return BlobStoreBackendBlobStore
+	}
+
+	return configuration.Backend
+}
+
 // getURL returns the blobstore URL for the specific configuration
 func (configuration *BlobStoreConfiguration) getURL(backup string, bucket string) string {
+	if configuration.GetBackend() == BlobStoreBackendFile {
+		return fmt.Sprintf("file://%s", path.Join(FileBackupMountPath, backup))
+	}
+
 	if configuration.AccountName == "" {
 		return ""
 	}
@@ -340,6 +756,56 @@ func (configuration *BlobStoreConfiguration) BucketName() string {
 	return "fdb-backups"
 }
 
+// ValidateURL checks if the provided URL is a valid backup URL for this
+// configuration.
+func (configuration *BlobStoreConfiguration) ValidateURL(backupURL string) error {
+	if backupURL == "" {
+		return fmt.Errorf("backup URL is empty, check the blobStoreConfiguration")
+	}
+
+	parsedURL, err := url.Parse(backupURL)
+	if err != nil {
+		return fmt.Errorf("invalid backup URL %s: %w", backupURL, err)
+	}
+
+	backend := configuration.GetBackend()
+	if parsedURL.Scheme != string(backend) {
+		return fmt.Errorf("invalid backup URL %s: expected scheme %s but got %s", backupURL, backend, parsedURL.Scheme)
+	}
+
+	switch backend {
+	case BlobStoreBackendBlobStore:
+		if parsedURL.Host == "" {
+			return fmt.Errorf("invalid backup URL %s: missing account name", backupURL)
+		}
+
+		if strings.Trim(parsedURL.Path, "/") == "" {
+			return fmt.Errorf("invalid backup URL %s: missing backup name", backupURL)
+		}
+
+		for _, parameter := range strings.Split(parsedURL.RawQuery, "&") {
+			key, value, found := strings.Cut(parameter, "=")
+			if !found || key == "" || value == "" {
+				return fmt.Errorf("invalid backup URL %s: URL parameter %s must have the format key=value", backupURL, parameter)
+			}
+		}
+
+		if parsedURL.Query().Get("bucket") == "" {
+			return fmt.Errorf("invalid backup URL %s: missing bucket", backupURL)
+		}
+	case BlobStoreBackendFile:
+		if configuration.VolumeClaimName == "" {
+			return fmt.Errorf("invalid backup URL %s: the file backend requires a volumeClaimName", backupURL)
+		}
+
+		if !strings.HasPrefix(parsedURL.Path, FileBackupMountPath+"/") {
+			return fmt.Errorf("invalid backup URL %s: path must be inside of %s", backupURL, FileBackupMountPath)
+		}
+	}
+
+	return nil
+}
+
 func init() {
 	SchemeBuilder.Register(&FoundationDBBackup{}, &FoundationDBBackupList{})
 }
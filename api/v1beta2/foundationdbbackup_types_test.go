@@ -21,9 +21,13 @@
 package v1beta2
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("[api] FoundationDBBackup", func() {
@@ -224,6 +228,102 @@ var _ = Describe("[api] FoundationDBBackup", func() {
 		})
 	})
 
+	When("getting the alerting thresholds", func() {
+		It("should return the defaults", func() {
+			Expect(backup.GetLagThresholdSeconds()).To(Equal(600))
+			Expect(backup.GetSnapshotStaleThresholdSeconds()).To(Equal(1728000))
+		})
+
+		It("should return the configured thresholds", func() {
+			backup.Spec.Alerting = &BackupAlertingConfiguration{
+				LagThresholdSeconds:           pointer.Int(60),
+				SnapshotStaleThresholdSeconds: pointer.Int(3600),
+			}
+			Expect(backup.GetLagThresholdSeconds()).To(Equal(60))
+			Expect(backup.GetSnapshotStaleThresholdSeconds()).To(Equal(3600))
+		})
+	})
+
+	When("updating the conditions", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now()
+			backup.ObjectMeta.Generation = 2
+			backup.Status.AgentCount = 2
+			backup.Status.BackupDetails = &FoundationDBBackupStatusBackupDetails{
+				Running:                  true,
+				SecondsBehind:            10,
+				CurrentSnapshotStartTime: &metav1.Time{Time: now.Add(-time.Hour)},
+			}
+		})
+
+		It("should set all alert conditions to false for a healthy backup", func() {
+			backup.UpdateConditions(now)
+			Expect(backup.Status.Conditions).To(HaveLen(4))
+			for _, conditionType := range []BackupConditionType{BackupLagging, BackupSnapshotStale, BackupAgentsDown} {
+				condition := meta.FindStatusCondition(backup.Status.Conditions, string(conditionType))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.ObservedGeneration).To(BeNumerically("==", 2))
+			}
+		})
+
+		It("should set the reconciled condition if the latest generation is reconciled", func() {
+			backup.Status.Generations.Reconciled = 2
+			backup.UpdateConditions(now)
+			condition := meta.FindStatusCondition(backup.Status.Conditions, string(BackupReconciled))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("ReconciliationComplete"))
+		})
+
+		It("should use the pending generation as reason if the latest generation is not reconciled", func() {
+			backup.Status.Generations.Reconciled = 1
+			backup.Status.Generations.NeedsBackupStart = 2
+			backup.UpdateConditions(now)
+			condition := meta.FindStatusCondition(backup.Status.Conditions, string(BackupReconciled))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NeedsBackupStart"))
+		})
+
+		It("should set the lagging condition", func() {
+			backup.Status.BackupDetails.SecondsBehind = 601
+			backup.UpdateConditions(now)
+			Expect(meta.IsStatusConditionTrue(backup.Status.Conditions, string(BackupLagging))).To(BeTrue())
+		})
+
+		It("should set the snapshot stale condition", func() {
+			backup.Status.BackupDetails.CurrentSnapshotStartTime = &metav1.Time{Time: now.Add(-21 * 24 * time.Hour)}
+			backup.UpdateConditions(now)
+			Expect(meta.IsStatusConditionTrue(backup.Status.Conditions, string(BackupSnapshotStale))).To(BeTrue())
+		})
+
+		It("should set the agents down condition", func() {
+			backup.Status.AgentCount = 1
+			backup.UpdateConditions(now)
+			condition := meta.FindStatusCondition(backup.Status.Conditions, string(BackupAgentsDown))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Message).To(Equal("1 of 2 backup agents are ready"))
+		})
+
+		It("should keep the transition time if the condition doesn't change", func() {
+			backup.UpdateConditions(now.Add(-time.Minute))
+			backup.UpdateConditions(now)
+			condition := meta.FindStatusCondition(backup.Status.Conditions, string(BackupLagging))
+			Expect(condition.LastTransitionTime.Time).To(BeTemporally("~", now.Add(-time.Minute), time.Second))
+		})
+
+		It("should not set the lagging condition if the backup is not running", func() {
+			backup.Status.BackupDetails.Running = false
+			backup.Status.BackupDetails.SecondsBehind = 601
+			backup.UpdateConditions(now)
+			Expect(meta.IsStatusConditionFalse(backup.Status.Conditions, string(BackupLagging))).To(BeTrue())
+		})
+	})
+
 	When("getting the backup URL", func() {
 		DescribeTable("should generate the correct backup URL",
 			func(backup FoundationDBBackup, expected string) {
@@ -301,6 +401,84 @@ var _ = Describe("[api] FoundationDBBackup", func() {
 					},
 				},
 				"blobstore://account@account/mybackup?bucket=fdb-backups&secure_connection=0"),
+			Entry("A Backup with a file backend",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							Backend:         BlobStoreBackendFile,
+							VolumeClaimName: "backup-data",
+						},
+					},
+				},
+				"file:///var/fdb-backups/mybackup"),
+			Entry("A Backup with a file backend and backup name",
+				FoundationDBBackup{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "mybackup",
+					},
+					Spec: FoundationDBBackupSpec{
+						BlobStoreConfiguration: &BlobStoreConfiguration{
+							Backend:         BlobStoreBackendFile,
+							BackupName:      "test",
+							VolumeClaimName: "backup-data",
+						},
+					},
+				},
+				"file:///var/fdb-backups/test"),
+		)
+	})
+
+	When("validating the backup URL", func() {
+		DescribeTable("should validate the backup URL",
+			func(configuration BlobStoreConfiguration, backupURL string, expected string) {
+				err := configuration.ValidateURL(backupURL)
+				if expected == "" {
+					Expect(err).NotTo(HaveOccurred())
+					return
+				}
+
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(expected))
+			},
+			Entry("a valid blobstore URL",
+				BlobStoreConfiguration{AccountName: "account@account"},
+				"blobstore://account@account/test?bucket=fdb-backups&secure_connection=0",
+				""),
+			Entry("an empty URL",
+				BlobStoreConfiguration{},
+				"",
+				"backup URL is empty"),
+			Entry("a blobstore URL for the file backend",
+				BlobStoreConfiguration{Backend: BlobStoreBackendFile, VolumeClaimName: "backup-data"},
+				"blobstore://account@account/test?bucket=fdb-backups",
+				"expected scheme file"),
+			Entry("a blobstore URL without a backup name",
+				BlobStoreConfiguration{AccountName: "account@account"},
+				"blobstore://account@account/?bucket=fdb-backups",
+				"missing backup name"),
+			Entry("a blobstore URL without a bucket",
+				BlobStoreConfiguration{AccountName: "account@account"},
+				"blobstore://account@account/test?secure_connection=0",
+				"missing bucket"),
+			Entry("a blobstore URL with an invalid parameter",
+				BlobStoreConfiguration{AccountName: "account@account"},
+				"blobstore://account@account/test?bucket=fdb-backups&secure_connection",
+				"must have the format key=value"),
+			Entry("a valid file URL",
+				BlobStoreConfiguration{Backend: BlobStoreBackendFile, VolumeClaimName: "backup-data"},
+				"file:///var/fdb-backups/test",
+				""),
+			Entry("a file URL without a volume claim",
+				BlobStoreConfiguration{Backend: BlobStoreBackendFile},
+				"file:///var/fdb-backups/test",
+				"requires a volumeClaimName"),
+			Entry("a file URL outside of the mount path",
+				BlobStoreConfiguration{Backend: BlobStoreBackendFile, VolumeClaimName: "backup-data"},
+				"file:///tmp/test",
+				"path must be inside of /var/fdb-backups"),
 		)
 	})
 })
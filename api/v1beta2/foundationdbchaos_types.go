@@ -0,0 +1,264 @@
+/*
+Copyright 2023 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// ChaosLabel is the label that the operator adds to all resources that are
+// created to inject a fault for a chaos experiment. The value is the name of
+// the experiment.
+const ChaosLabel = "foundationdb.org/chaos"
+
+// ChaosInjectionLabel is the label that the operator adds to all resources
+// that are created to inject a fault for a chaos experiment. The value is the
+// number of the injection that created the resource.
+const ChaosInjectionLabel = "foundationdb.org/chaos-injection"
+
+// ChaosTargetAnnotation is the annotation that the operator adds to the pods
+// that fill up the data volume of a process group. The value is the process
+// group ID of the target.
+const ChaosTargetAnnotation = "foundationdb.org/chaos-target"
+
+// ChaosFinalizer is the finalizer that the operator adds to running chaos
+// experiments to revert the injected faults before the resource is removed.
+const ChaosFinalizer = "foundationdb.org/chaos"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdbchaos
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster that the experiment runs against",priority=0
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="Fault that is injected by the experiment",priority=0
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the experiment",priority=0
+// +kubebuilder:printcolumn:name="Injections",type="integer",JSONPath=".status.report.injections",description="Number of injected faults",priority=0
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBChaos is the Schema for the foundationdbchaos API
+type FoundationDBChaos struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FoundationDBChaosSpec   `json:"spec,omitempty"`
+	Status FoundationDBChaosStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBChaosList contains a list of FoundationDBChaos objects
+type FoundationDBChaosList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBChaos `json:"items"`
+}
+
+// FoundationDBChaosSpec describes a time-boxed chaos experiment against a
+// cluster.
+type FoundationDBChaosSpec struct {
+	// ClusterName defines the cluster that the experiment runs against.
+	ClusterName string `json:"clusterName"`
+
+	// +kubebuilder:validation:Enum=KillProcesses;PartitionZone;DiskFull;StallSidecars
+	// Action defines the fault that is injected by the experiment.
+	Action ChaosAction `json:"action"`
+
+	// ProcessClass limits the processes that can be targeted by the
+	// KillProcesses, DiskFull and StallSidecars actions. By default all
+	// processes of the cluster can be targeted.
+	ProcessClass ProcessClass `json:"processClass,omitempty"`
+
+	// Count defines how many process groups are targeted by every
+	// injection. This is ignored for the PartitionZone action, which targets
+	// all process groups in the zone.
+	// The default is 1.
+	// +kubebuilder:validation:Minimum=1
+	Count *int `json:"count,omitempty"`
+
+	// IntervalSeconds defines how often processes are killed by the
+	// KillProcesses action.
+	// The default is 60.
+	// +kubebuilder:validation:Minimum=1
+	IntervalSeconds *int `json:"intervalSeconds,omitempty"`
+
+	// ZoneID defines the zone that is partitioned by the PartitionZone
+	// action. By default a random zone is picked.
+	ZoneID string `json:"zoneID,omitempty"`
+
+	// DurationSeconds defines how long the experiment runs. All injected
+	// faults are reverted once the duration has passed.
+	// The default is 300.
+	// +kubebuilder:validation:Minimum=1
+	DurationSeconds *int `json:"durationSeconds,omitempty"`
+
+	// StartTime defines the earliest time at which the experiment is
+	// started. By default the experiment is started right away.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+}
+
+// FoundationDBChaosStatus describes the current state of a chaos experiment.
+type FoundationDBChaosStatus struct {
+	// Phase provides the phase of the experiment.
+	Phase ChaosPhase `json:"phase,omitempty"`
+
+	// StartTime provides the time when the first fault was injected.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime provides the time when all faults were reverted.
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// LastInjectionTime provides the time when the last fault was
+	// injected.
+	LastInjectionTime *metav1.Time `json:"lastInjectionTime,omitempty"`
+
+	// Targets provides the process groups that are affected by the most
+	// recent injection.
+	Targets []ProcessGroupID `json:"targets,omitempty"`
+
+	// Report provides the outcome of the experiment.
+	Report ChaosReport `json:"report,omitempty"`
+}
+
+// ChaosReport records what happened during a chaos experiment.
+type ChaosReport struct {
+	// Injections provides the number of faults that were injected.
+	Injections int `json:"injections,omitempty"`
+
+	// SkippedInjections provides the number of injections that were skipped
+	// because the cluster didn't have the desired fault tolerance.
+	SkippedInjections int `json:"skippedInjections,omitempty"`
+
+	// MinimumFaultTolerance provides the lowest fault tolerance that was
+	// observed while the experiment was running.
+	MinimumFaultTolerance *int `json:"minimumFaultTolerance,omitempty"`
+
+	// LostAvailability defines whether the database was unavailable while the
+	// experiment was running.
+	LostAvailability bool `json:"lostAvailability,omitempty"`
+
+	// Message provides a human-readable summary of the outcome.
+	Message string `json:"message,omitempty"`
+}
+
+// ChaosAction defines the fault that is injected by a chaos experiment.
+type ChaosAction string
+
+const (
+	// ChaosActionKillProcesses kills random processes at a fixed interval.
+	ChaosActionKillProcesses ChaosAction = "KillProcesses"
+
+	// ChaosActionPartitionZone cuts off all network traffic to and from the
+	// pods in a zone.
+	ChaosActionPartitionZone ChaosAction = "PartitionZone"
+
+	// ChaosActionDiskFull fills up the data volume of random process groups.
+	ChaosActionDiskFull ChaosAction = "DiskFull"
+
+	// ChaosActionStallSidecars blocks the traffic to the sidecar of random
+	// process groups, while the fdbserver processes stay reachable.
+	ChaosActionStallSidecars ChaosAction = "StallSidecars"
+)
+
+// ChaosPhase defines the phase of a chaos experiment.
+type ChaosPhase string
+
+const (
+	// ChaosPhasePending means that the experiment hasn't started yet.
+	ChaosPhasePending ChaosPhase = "Pending"
+
+	// ChaosPhaseRunning means that faults are being injected.
+	ChaosPhaseRunning ChaosPhase = "Running"
+
+	// ChaosPhaseCompleted means that the experiment ran for the full
+	// duration and all faults have been reverted.
+	ChaosPhaseCompleted ChaosPhase = "Completed"
+
+	// ChaosPhaseAborted means that the experiment was stopped early because
+	// the database became unavailable and all faults have been reverted.
+	ChaosPhaseAborted ChaosPhase = "Aborted"
+)
+
+// GetCount returns the number of process groups that are targeted by every
+// injection.
+func (chaos *FoundationDBChaos) GetCount() int {
+	return pointer.IntDeref(chaos.Spec.Count, 1)
+}
+
+// GetInterval returns the time between two injections of the KillProcesses
+// action.
+func (chaos *FoundationDBChaos) GetInterval() time.Duration {
+	return time.Duration(pointer.IntDeref(chaos.Spec.IntervalSeconds, 60)) * time.Second
+}
+
+// GetDuration returns how long the experiment runs.
+func (chaos *FoundationDBChaos) GetDuration() time.Duration {
+	return time.Duration(pointer.IntDeref(chaos.Spec.DurationSeconds, 300)) * time.Second
+}
+
+// GetPhase returns the phase of the experiment. This will fill in
+// ChaosPhasePending if no phase has been recorded yet.
+func (chaos *FoundationDBChaos) GetPhase() ChaosPhase {
+	if chaos.Status.Phase == "" {
+		return ChaosPhasePending
+	}
+
+	return chaos.Status.Phase
+}
+
+// IsFinished determines whether the experiment has ended, either because it
+// ran for the full duration or because it was aborted.
+func (chaos *FoundationDBChaos) IsFinished() bool {
+	phase := chaos.GetPhase()
+	return phase == ChaosPhaseCompleted || phase == ChaosPhaseAborted
+}
+
+// GetEndTime returns the time when the experiment should end. This will
+// return nil if the experiment hasn't started yet.
+func (chaos *FoundationDBChaos) GetEndTime() *time.Time {
+	if chaos.Status.StartTime == nil {
+		return nil
+	}
+
+	endTime := chaos.Status.StartTime.Add(chaos.GetDuration())
+	return &endTime
+}
+
+// NeedsInjection determines whether a fault should be injected at the
+// provided time. Only the KillProcesses action injects faults repeatedly, all
+// other actions inject their fault once when the experiment starts.
+func (chaos *FoundationDBChaos) NeedsInjection(now time.Time) bool {
+	if chaos.GetPhase() != ChaosPhaseRunning {
+		return false
+	}
+
+	if chaos.Status.LastInjectionTime == nil {
+		return true
+	}
+
+	if chaos.Spec.Action != ChaosActionKillProcesses {
+		return false
+	}
+
+	return !now.Before(chaos.Status.LastInjectionTime.Add(chaos.GetInterval()))
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBChaos{}, &FoundationDBChaosList{})
+}
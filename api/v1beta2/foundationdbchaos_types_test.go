@@ -0,0 +1,113 @@
+/*
+ * foundationdbchaos_types_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[api] FoundationDBChaos", func() {
+	var chaos *FoundationDBChaos
+
+	BeforeEach(func() {
+		chaos = &FoundationDBChaos{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "sample-chaos",
+			},
+			Spec: FoundationDBChaosSpec{
+				ClusterName: "sample-cluster",
+				Action:      ChaosActionKillProcesses,
+			},
+		}
+	})
+
+	When("getting the experiment settings", func() {
+		It("should fill in the defaults", func() {
+			Expect(chaos.GetCount()).To(Equal(1))
+			Expect(chaos.GetInterval()).To(Equal(time.Minute))
+			Expect(chaos.GetDuration()).To(Equal(5 * time.Minute))
+			Expect(chaos.GetPhase()).To(Equal(ChaosPhasePending))
+			Expect(chaos.GetEndTime()).To(BeNil())
+		})
+
+		It("should use the values from the spec", func() {
+			chaos.Spec.Count = pointer.Int(3)
+			chaos.Spec.IntervalSeconds = pointer.Int(10)
+			chaos.Spec.DurationSeconds = pointer.Int(30)
+			startTime := metav1.NewTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+			chaos.Status.StartTime = &startTime
+
+			Expect(chaos.GetCount()).To(Equal(3))
+			Expect(chaos.GetInterval()).To(Equal(10 * time.Second))
+			Expect(chaos.GetDuration()).To(Equal(30 * time.Second))
+			Expect(*chaos.GetEndTime()).To(Equal(startTime.Add(30 * time.Second)))
+		})
+	})
+
+	DescribeTable("checking if the experiment has ended",
+		func(phase ChaosPhase, expected bool) {
+			chaos.Status.Phase = phase
+			Expect(chaos.IsFinished()).To(Equal(expected))
+		},
+		Entry("no phase", ChaosPhase(""), false),
+		Entry("pending", ChaosPhasePending, false),
+		Entry("running", ChaosPhaseRunning, false),
+		Entry("completed", ChaosPhaseCompleted, true),
+		Entry("aborted", ChaosPhaseAborted, true),
+	)
+
+	When("checking if a fault should be injected", func() {
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+			chaos.Status.Phase = ChaosPhaseRunning
+		})
+
+		It("should not inject a fault before the experiment is running", func() {
+			chaos.Status.Phase = ChaosPhasePending
+			Expect(chaos.NeedsInjection(now)).To(BeFalse())
+		})
+
+		It("should inject a fault when no fault was injected", func() {
+			Expect(chaos.NeedsInjection(now)).To(BeTrue())
+		})
+
+		It("should inject the next fault once the interval has passed", func() {
+			lastInjection := metav1.NewTime(now.Add(-30 * time.Second))
+			chaos.Status.LastInjectionTime = &lastInjection
+			Expect(chaos.NeedsInjection(now)).To(BeFalse())
+			Expect(chaos.NeedsInjection(now.Add(30 * time.Second))).To(BeTrue())
+		})
+
+		It("should inject the fault of other actions only once", func() {
+			chaos.Spec.Action = ChaosActionPartitionZone
+			lastInjection := metav1.NewTime(now.Add(-time.Hour))
+			chaos.Status.LastInjectionTime = &lastInjection
+			Expect(chaos.NeedsInjection(now)).To(BeFalse())
+		})
+	})
+})
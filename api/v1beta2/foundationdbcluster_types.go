@@ -27,6 +27,8 @@ import (
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 )
@@ -34,6 +36,7 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:shortName=fdb
 // +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.scale.replicas,statuspath=.status.scale.replicas,selectorpath=.status.scale.selector
 // +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
 // +kubebuilder:printcolumn:name="Available",type="boolean",JSONPath=".status.health.available",description="Database available",priority=0
@@ -133,6 +136,9 @@ type FoundationDBClusterSpec struct {
 	// format.
 	TrustedCAs []string `json:"trustedCAs,omitempty"`
 
+	// TLS defines the Secrets that contain the certificates of the cluster.
+	TLS *TLSConfiguration `json:"tls,omitempty"`
+
 	// SidecarVariables defines Custom variables that the sidecar should make
 	// available for substitution in the monitor conf file.
 	SidecarVariables []string `json:"sidecarVariables,omitempty"`
@@ -216,6 +222,18 @@ type FoundationDBClusterSpec struct {
 	// UseUnifiedImage determines if we should use the unified image rather than
 	// separate images for the main container and the sidecar container.
 	UseUnifiedImage *bool `json:"useUnifiedImage,omitempty"`
+
+	// ResourceRecommendations controls the recommendations for the resources
+	// of the processes based on their observed usage.
+	ResourceRecommendations ResourceRecommendationOptions `json:"resourceRecommendations,omitempty"`
+
+	// Autoscaling controls the automatic scaling of the process and role
+	// counts based on the load of the cluster.
+	Autoscaling AutoscalingOptions `json:"autoscaling,omitempty"`
+
+	// Scale controls which process count is exposed through the scale
+	// subresource.
+	Scale ScaleOptions `json:"scale,omitempty"`
 }
 
 // ImageType defines a single kind of images used in the cluster.
@@ -238,6 +256,10 @@ type FoundationDBClusterStatus struct {
 	// processes in the cluster.
 	RequiredAddresses RequiredAddressSet `json:"requiredAddresses,omitempty"`
 
+	// TLS provides information about a pending transition between TLS and
+	// non-TLS and about the certificates of the cluster.
+	TLS TLSStatus `json:"tls,omitempty"`
+
 	// HasIncorrectConfigMap indicates whether the latest config map is out
 	// of date with the cluster spec.
 	HasIncorrectConfigMap bool `json:"hasIncorrectConfigMap,omitempty"`
@@ -250,6 +272,12 @@ type FoundationDBClusterStatus struct {
 	// new coordinators to fulfill its fault tolerance requirements.
 	NeedsNewCoordinators bool `json:"needsNewCoordinators,omitempty"`
 
+	// HasUnhealthyCoordinators indicates whether any of the current
+	// coordinators is not reachable, excluded or marked for removal. Changing
+	// these coordinators restores the fault tolerance of the cluster, so it
+	// is not delayed by the disruption schedule.
+	HasUnhealthyCoordinators bool `json:"hasUnhealthyCoordinators,omitempty"`
+
 	// RunningVersion defines the version of FoundationDB that the cluster is
 	// currently running.
 	RunningVersion string `json:"runningVersion,omitempty"`
@@ -289,6 +317,100 @@ type FoundationDBClusterStatus struct {
 
 	// ReconciledProcessGroups reflects the number of process groups that have no condition and are not marked for removal.
 	ReconciledProcessGroups int `json:"reconciledProcessGroups,omitempty"`
+
+	// CoordinatorIPRecovery contains information about the last time the
+	// operator updated the coordinator IPs in the cluster file.
+	CoordinatorIPRecovery *CoordinatorIPRecoveryStatus `json:"coordinatorIPRecovery,omitempty"`
+
+	// PendingCoordinatorIPRecovery contains the coordinator IP update that
+	// the operator observed while the coordinator quorum was lost. The
+	// operator only updates the cluster file if it observes the same update
+	// again in a later reconciliation.
+	PendingCoordinatorIPRecovery *CoordinatorIPRecoveryStatus `json:"pendingCoordinatorIPRecovery,omitempty"`
+
+	// ResourceRecommendations contains the observed resource usage and the
+	// recommended resources for every process class. This is only populated
+	// if resource recommendations are enabled.
+	ResourceRecommendations []ResourceRecommendation `json:"resourceRecommendations,omitempty"`
+
+	// StorageScaling contains information about the last time the operator
+	// increased the storage process count because processes were running
+	// low on disk space.
+	StorageScaling *StorageScalingStatus `json:"storageScaling,omitempty"`
+
+	// Autoscaling contains information about the last time the autoscaler
+	// changed the process or role counts.
+	Autoscaling *AutoscalingStatus `json:"autoscaling,omitempty"`
+
+	// Scale contains the information for the scale subresource.
+	Scale ScaleStatus `json:"scale,omitempty"`
+
+	// Operations contains the disruptive actions on process groups that are
+	// waiting in the operation queue or are allowed to run.
+	Operations []ProcessGroupOperation `json:"operations,omitempty"`
+
+	// Conditions provides a summary of the state of the cluster that follows
+	// the Kubernetes API conventions, e.g. whether the database is available
+	// or whether the latest generation of the spec is reconciled. The
+	// conditions are derived from the generations, the health and the
+	// process group conditions in the status.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// PendingDisruptions contains the disruptive actions that are delayed
+	// because they are not allowed by the disruption schedule of the
+	// cluster or the freeze windows of the operator.
+	PendingDisruptions *PendingDisruptionsStatus `json:"pendingDisruptions,omitempty"`
+}
+
+// StorageScalingStatus records an increase of the storage process count by
+// the operator.
+type StorageScalingStatus struct {
+	// Timestamp defines when the storage process count was increased.
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// PreviousCount defines the storage process count before the increase.
+	PreviousCount int `json:"previousCount,omitempty"`
+
+	// Count defines the storage process count after the increase.
+	Count int `json:"count,omitempty"`
+
+	// ProcessGroups contains the process groups that were running low on
+	// disk space.
+	ProcessGroups []ProcessGroupID `json:"processGroups,omitempty"`
+}
+
+// CoordinatorIPRecoveryStatus records an update of the coordinator IPs in the
+// cluster file.
+type CoordinatorIPRecoveryStatus struct {
+	// Timestamp defines when the coordinator IPs were updated. For a pending
+	// update this defines when the update was observed first.
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// PreviousConnectionString defines the connection string before the
+	// coordinator IPs were updated.
+	PreviousConnectionString string `json:"previousConnectionString,omitempty"`
+
+	// ConnectionString defines the connection string with the new coordinator
+	// IPs.
+	ConnectionString string `json:"connectionString,omitempty"`
+
+	// Coordinators contains the mapping from the old to the new coordinator
+	// addresses.
+	Coordinators []CoordinatorIPChange `json:"coordinators,omitempty"`
+}
+
+// CoordinatorIPChange describes the new address of a coordinator.
+type CoordinatorIPChange struct {
+	// ProcessGroupID defines the process group of the coordinator.
+	ProcessGroupID ProcessGroupID `json:"processGroupID,omitempty"`
+
+	// PreviousAddress defines the address in the previous connection string.
+	PreviousAddress string `json:"previousAddress,omitempty"`
+
+	// Address defines the new address of the coordinator.
+	Address string `json:"address,omitempty"`
 }
 
 // MaintenanceModeInfo contains information regarding the zone and process groups that are put
@@ -326,6 +448,9 @@ type ProcessGroupStatus struct {
 	ExclusionSkipped bool `json:"exclusionSkipped,omitempty"`
 	// ProcessGroupConditions represents a list of degraded conditions that the process group is in.
 	ProcessGroupConditions []*ProcessGroupCondition `json:"processGroupConditions,omitempty"`
+	// FaultDomain represents the last known fault domain of the process group, based on the zone ID locality
+	// of its processes.
+	FaultDomain string `json:"faultDomain,omitempty"`
 }
 
 // ProcessGroupID represents the ID of the process group
@@ -359,6 +484,12 @@ func (processGroupStatus *ProcessGroupStatus) MarkForRemoval() {
 
 // NeedsReplacement checks if the ProcessGroupStatus has conditions so that it should be removed
 func (processGroupStatus *ProcessGroupStatus) NeedsReplacement(failureTime int) (bool, int64) {
+	return processGroupStatus.NeedsReplacementAt(failureTime, time.Now())
+}
+
+// NeedsReplacementAt checks if the ProcessGroupStatus has conditions so that it should be removed at the provided
+// time.
+func (processGroupStatus *ProcessGroupStatus) NeedsReplacementAt(failureTime int, now time.Time) (bool, int64) {
 	var missingTime *int64
 	for _, condition := range conditionsThatNeedReplacement {
 		conditionTime := processGroupStatus.GetConditionTime(condition)
@@ -367,7 +498,7 @@ func (processGroupStatus *ProcessGroupStatus) NeedsReplacement(failureTime int)
 		}
 	}
 
-	failureWindowStart := time.Now().Add(-1 * time.Duration(failureTime) * time.Second).Unix()
+	failureWindowStart := now.Add(-1 * time.Duration(failureTime) * time.Second).Unix()
 	if missingTime != nil && *missingTime < failureWindowStart && !processGroupStatus.IsMarkedForRemoval() {
 		return true, *missingTime
 	}
@@ -509,8 +640,14 @@ func MarkProcessGroupForRemoval(processGroups []*ProcessGroupStatus, processGrou
 // If the old ProcessGroupStatus already contains the condition, and the condition is being set,
 // the condition is reused to contain the same timestamp.
 func (processGroupStatus *ProcessGroupStatus) UpdateCondition(conditionType ProcessGroupConditionType, set bool, oldProcessGroups []*ProcessGroupStatus, processGroupID ProcessGroupID) {
+	processGroupStatus.UpdateConditionAt(conditionType, set, oldProcessGroups, processGroupID, time.Now())
+}
+
+// UpdateConditionAt will add or remove a condition in the ProcessGroupStatus like UpdateCondition. New conditions
+// will get the provided time as timestamp.
+func (processGroupStatus *ProcessGroupStatus) UpdateConditionAt(conditionType ProcessGroupConditionType, set bool, oldProcessGroups []*ProcessGroupStatus, processGroupID ProcessGroupID, now time.Time) {
 	if set {
-		processGroupStatus.addCondition(oldProcessGroups, processGroupID, conditionType)
+		processGroupStatus.addCondition(oldProcessGroups, processGroupID, conditionType, now)
 	} else {
 		processGroupStatus.removeCondition(conditionType)
 	}
@@ -518,7 +655,7 @@ func (processGroupStatus *ProcessGroupStatus) UpdateCondition(conditionType Proc
 
 // addCondition will add the condition to the ProcessGroupStatus.
 // If the old ProcessGroupStatus already contains the condition the condition is reused to contain the same timestamp.
-func (processGroupStatus *ProcessGroupStatus) addCondition(oldProcessGroups []*ProcessGroupStatus, processGroupID ProcessGroupID, conditionType ProcessGroupConditionType) {
+func (processGroupStatus *ProcessGroupStatus) addCondition(oldProcessGroups []*ProcessGroupStatus, processGroupID ProcessGroupID, conditionType ProcessGroupConditionType, now time.Time) {
 	var oldProcessGroupStatus *ProcessGroupStatus
 
 	// Check if we got a ProcessGroupStatus for the processGroupID
@@ -550,7 +687,10 @@ func (processGroupStatus *ProcessGroupStatus) addCondition(oldProcessGroups []*P
 	}
 
 	// We didn't find any condition so we create a new one
-	processGroupStatus.ProcessGroupConditions = append(processGroupStatus.ProcessGroupConditions, NewProcessGroupCondition(conditionType))
+	processGroupStatus.ProcessGroupConditions = append(processGroupStatus.ProcessGroupConditions, &ProcessGroupCondition{
+		ProcessGroupConditionType: conditionType,
+		Timestamp:                 now.Unix(),
+	})
 }
 
 // removeCondition will remove a condition from the ProcessGroupStatus, if it is
@@ -696,6 +836,9 @@ const (
 	SidecarUnreachable ProcessGroupConditionType = "SidecarUnreachable"
 	// PodPending represents a process group where the pod is in a pending state.
 	PodPending ProcessGroupConditionType = "PodPending"
+	// LowDiskSpace represents a process group where the available disk space
+	// is below the configured threshold.
+	LowDiskSpace ProcessGroupConditionType = "LowDiskSpace"
 	// ReadyCondition is currently only used in the metrics.
 	ReadyCondition ProcessGroupConditionType = "Ready"
 )
@@ -713,6 +856,7 @@ func AllProcessGroupConditionTypes() []ProcessGroupConditionType {
 		MissingProcesses,
 		SidecarUnreachable,
 		PodPending,
+		LowDiskSpace,
 		ReadyCondition,
 	}
 }
@@ -740,6 +884,8 @@ func GetProcessGroupConditionType(processGroupConditionType string) (ProcessGrou
 		return SidecarUnreachable, nil
 	case "PodPending":
 		return PodPending, nil
+	case "LowDiskSpace":
+		return LowDiskSpace, nil
 	}
 
 	return "", fmt.Errorf("unknown process group condition type: %s", processGroupConditionType)
@@ -826,6 +972,37 @@ type ClusterHealth struct {
 	DataMovementPriority int `json:"dataMovementPriority,omitempty"`
 }
 
+// ClusterConditionType defines the type of a condition in the cluster status.
+type ClusterConditionType string
+
+const (
+	// ClusterAvailable is set to true when the database is accepting reads
+	// and writes.
+	ClusterAvailable ClusterConditionType = "Available"
+
+	// ClusterReconciled is set to true when the latest generation of the spec
+	// is reconciled.
+	ClusterReconciled ClusterConditionType = "Reconciled"
+
+	// ClusterFullyReplicated is set to true when all data are fully
+	// replicated according to the current replication policy.
+	ClusterFullyReplicated ClusterConditionType = "FullyReplicated"
+
+	// ClusterUpgrading is set to true when the running version of the
+	// database differs from the version in the spec.
+	ClusterUpgrading ClusterConditionType = "Upgrading"
+
+	// ClusterProgressing is set to true when the operator is working on
+	// changes to bring the cluster to the state defined in the spec. This is
+	// set to false while disruptive actions are delayed by the disruption
+	// schedule.
+	ClusterProgressing ClusterConditionType = "Progressing"
+
+	// ClusterDegraded is set to true when the database is not fully healthy
+	// or when process groups have conditions that indicate a failure.
+	ClusterDegraded ClusterConditionType = "Degraded"
+)
+
 // FoundationDBClusterAutomationOptions provides flags for enabling or disabling
 // operations that can be performed on a cluster.
 type FoundationDBClusterAutomationOptions struct {
@@ -918,6 +1095,73 @@ type FoundationDBClusterAutomationOptions struct {
 
 	// MaintenanceModeOptions contains options for maintenance mode related settings.
 	MaintenanceModeOptions MaintenanceModeOptions `json:"maintenanceModeOptions,omitempty"`
+
+	// FixCoordinatorIPs defines whether the operator is allowed to update the
+	// coordinator IPs in the cluster file when the coordinators are unreachable
+	// because their Pods got new IP addresses, e.g. after a restart of the
+	// Kubernetes cluster. This requires that the operator is allowed to exec
+	// into the Pods. The default is false.
+	FixCoordinatorIPs *bool `json:"fixCoordinatorIPs,omitempty"`
+
+	// StorageCapacity contains options for automatically managing the
+	// storage capacity of the cluster when processes are running low on
+	// disk space.
+	StorageCapacity StorageCapacityOptions `json:"storageCapacity,omitempty"`
+
+	// OperationQueue contains options for planning disruptive actions on
+	// process groups through a shared queue.
+	OperationQueue OperationQueueOptions `json:"operationQueue,omitempty"`
+
+	// ReconciliationHistorySize defines how many reconciliation runs are kept
+	// in the reconciliation history ConfigMap of the cluster. Setting this to
+	// 0 disables the reconciliation history. The default is 10.
+	// +kubebuilder:validation:Minimum=0
+	ReconciliationHistorySize *int `json:"reconciliationHistorySize,omitempty"`
+
+	// DisruptionSchedule defines the maintenance and freeze windows that
+	// control when the operator is allowed to perform disruptive actions.
+	DisruptionSchedule DisruptionScheduleOptions `json:"disruptionSchedule,omitempty"`
+}
+
+// StorageCapacityOptions controls options for automatically managing the
+// storage capacity of the cluster.
+type StorageCapacityOptions struct {
+	// Enabled controls whether the operator is allowed to expand the PVCs
+	// or to add storage processes when processes are running low on disk
+	// space. The LowDiskSpace condition is reported independent of this
+	// setting.
+	// The default is false.
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// LowDiskSpaceThresholdPercent defines the percentage of available disk
+	// space below which a process group gets the LowDiskSpace condition.
+	// The default is 10.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	LowDiskSpaceThresholdPercent *int `json:"lowDiskSpaceThresholdPercent,omitempty"`
+
+	// VolumeExpansionPercent defines by how many percent the operator will
+	// expand a PVC if the StorageClass allows volume expansion.
+	// The default is 50.
+	// +kubebuilder:validation:Minimum=1
+	VolumeExpansionPercent *int `json:"volumeExpansionPercent,omitempty"`
+
+	// MaxVolumeSize defines the maximum size the operator will expand a PVC
+	// to. If a PVC can't be expanded any further the operator will add
+	// storage processes instead. If unset there is no upper limit.
+	MaxVolumeSize *resource.Quantity `json:"maxVolumeSize,omitempty"`
+
+	// MaxStorageProcesses defines the maximum number of storage process
+	// groups the operator will scale the cluster to. If unset there is no
+	// upper limit.
+	// +kubebuilder:validation:Minimum=0
+	MaxStorageProcesses *int `json:"maxStorageProcesses,omitempty"`
+
+	// ScaleIntervalSeconds defines the minimum time between two increases
+	// of the storage process count by the operator.
+	// The default is 3600 seconds, or 1 hour.
+	// +kubebuilder:validation:Minimum=0
+	ScaleIntervalSeconds *int `json:"scaleIntervalSeconds,omitempty"`
 }
 
 // MaintenanceModeOptions controls options for placing zones in maintenance mode.
@@ -1083,6 +1327,9 @@ func (cluster *FoundationDBCluster) calculateProcessCount(addFaultTolerance bool
 func (cluster *FoundationDBCluster) GetProcessCountsWithDefaults() (ProcessCounts, error) {
 	roleCounts := cluster.GetRoleCountsWithDefaults()
 	processCounts := cluster.Spec.ProcessCounts.DeepCopy()
+	if cluster.Spec.Scale.Replicas != nil {
+		processCounts.SetCount(cluster.GetScaleProcessClass(), *cluster.Spec.Scale.Replicas)
+	}
 
 	isSatellite := false
 	isMain := false
@@ -1223,16 +1470,22 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 			continue
 		}
 
-		if len(processGroup.ProcessGroupConditions) > 0 {
-			conditions := make([]ProcessGroupConditionType, 0, len(processGroup.ProcessGroupConditions))
-			for _, condition := range processGroup.ProcessGroupConditions {
-				if condition.ProcessGroupConditionType == IncorrectCommandLine && cluster.Status.Generations.NeedsBounce == 0 {
-					logger.Info("Pending restart of fdbserver processes", "state", "NeedsBounce")
-					cluster.Status.Generations.NeedsBounce = cluster.ObjectMeta.Generation
-				}
-				conditions = append(conditions, condition.ProcessGroupConditionType)
+		conditions := make([]ProcessGroupConditionType, 0, len(processGroup.ProcessGroupConditions))
+		for _, condition := range processGroup.ProcessGroupConditions {
+			// Low disk space doesn't make a process group unhealthy, the
+			// storage capacity management takes care of it.
+			if condition.ProcessGroupConditionType == LowDiskSpace {
+				continue
+			}
+
+			if condition.ProcessGroupConditionType == IncorrectCommandLine && cluster.Status.Generations.NeedsBounce == 0 {
+				logger.Info("Pending restart of fdbserver processes", "state", "NeedsBounce")
+				cluster.Status.Generations.NeedsBounce = cluster.ObjectMeta.Generation
 			}
+			conditions = append(conditions, condition.ProcessGroupConditionType)
+		}
 
+		if len(conditions) > 0 {
 			logger.Info("Has unhealthy process group", "processGroupID", processGroup.ProcessGroupID, "state", "HasUnhealthyProcess", "conditions", conditions)
 			cluster.Status.Generations.HasUnhealthyProcess = cluster.ObjectMeta.Generation
 			reconciled = false
@@ -1284,8 +1537,8 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 		desiredAddressSet.NonTLS = true
 	}
 
-	if cluster.Status.RequiredAddresses != desiredAddressSet {
-		logger.Info("Pending TLS change", "state", "HasExtraListeners")
+	if cluster.Status.RequiredAddresses != desiredAddressSet || cluster.Status.TLS.Transition != "" {
+		logger.Info("Pending TLS change", "state", "HasExtraListeners", "transition", cluster.Status.TLS.Transition)
 		cluster.Status.Generations.HasExtraListeners = cluster.ObjectMeta.Generation
 		reconciled = false
 	}
@@ -1327,6 +1580,164 @@ func (cluster *FoundationDBCluster) CheckReconciliation(log logr.Logger) (bool,
 	return reconciled, nil
 }
 
+// degradingProcessGroupConditions contains the process group conditions that
+// indicate a failure. Conditions like IncorrectPodSpec are expected during a
+// rollout and don't mark the cluster as degraded.
+var degradingProcessGroupConditions = map[ProcessGroupConditionType]None{
+	PodFailing:         {},
+	MissingPod:         {},
+	MissingPVC:         {},
+	MissingService:     {},
+	MissingProcesses:   {},
+	SidecarUnreachable: {},
+	PodPending:         {},
+	LowDiskSpace:       {},
+}
+
+// getPendingReconciliationReasons returns the reasons why the latest
+// generation of the spec is not reconciled, based on the generations in the
+// status. The reasons have the same names as the generation fields.
+func (cluster *FoundationDBCluster) getPendingReconciliationReasons() []string {
+	generations := cluster.Status.Generations
+	pending := []struct {
+		reason     string
+		generation int64
+	}{
+		{"NeedsConfigurationChange", generations.NeedsConfigurationChange},
+		{"NeedsCoordinatorChange", generations.NeedsCoordinatorChange},
+		{"NeedsBounce", generations.NeedsBounce},
+		{"NeedsPodDeletion", generations.NeedsPodDeletion},
+		{"NeedsShrink", generations.NeedsShrink},
+		{"NeedsGrow", generations.NeedsGrow},
+		{"NeedsMonitorConfUpdate", generations.NeedsMonitorConfUpdate},
+		{"DatabaseUnavailable", generations.DatabaseUnavailable},
+		{"HasExtraListeners", generations.HasExtraListeners},
+		{"NeedsServiceUpdate", generations.NeedsServiceUpdate},
+		{"HasUnhealthyProcess", generations.HasUnhealthyProcess},
+		{"NeedsLockConfigurationChanges", generations.NeedsLockConfigurationChanges},
+	}
+
+	reasons := make([]string, 0, len(pending))
+	for _, entry := range pending {
+		if entry.generation > 0 {
+			reasons = append(reasons, entry.reason)
+		}
+	}
+
+	return reasons
+}
+
+// UpdateConditions sets the conditions in the cluster status based on the
+// generations, the health and the process group conditions in the status.
+// This must be called after CheckReconciliation.
+func (cluster *FoundationDBCluster) UpdateConditions(now time.Time) {
+	reconciled := metav1.Condition{
+		Type:    string(ClusterReconciled),
+		Status:  metav1.ConditionTrue,
+		Reason:  "ReconciliationComplete",
+		Message: fmt.Sprintf("Generation %d is reconciled", cluster.ObjectMeta.Generation),
+	}
+	progressing := metav1.Condition{
+		Type:   string(ClusterProgressing),
+		Status: metav1.ConditionFalse,
+		Reason: "ReconciliationComplete",
+	}
+	if cluster.Status.Generations.Reconciled != cluster.ObjectMeta.Generation {
+		reason := "ReconciliationPending"
+		pendingReasons := cluster.getPendingReconciliationReasons()
+		if len(pendingReasons) > 0 {
+			reason = pendingReasons[0]
+		}
+
+		reconciled.Status = metav1.ConditionFalse
+		reconciled.Reason = reason
+		reconciled.Message = fmt.Sprintf("Generation %d is not reconciled", cluster.ObjectMeta.Generation)
+		if len(pendingReasons) > 0 {
+			reconciled.Message = fmt.Sprintf("%s, pending: %s", reconciled.Message, strings.Join(pendingReasons, ", "))
+		}
+		progressing.Status = metav1.ConditionTrue
+		progressing.Reason = reason
+		progressing.Message = reconciled.Message
+	}
+
+	if cluster.Status.PendingDisruptions != nil {
+		progressing.Status = metav1.ConditionFalse
+		progressing.Reason = "DisruptionsPending"
+		progressing.Message = fmt.Sprintf("Disruptive actions are delayed: %s", cluster.Status.PendingDisruptions.Reason)
+	}
+
+	available := metav1.Condition{
+		Type:   string(ClusterAvailable),
+		Status: metav1.ConditionTrue,
+		Reason: "DatabaseAvailable",
+	}
+	if !cluster.Status.Configured {
+		available.Status = metav1.ConditionFalse
+		available.Reason = "DatabaseNotConfigured"
+		available.Message = "The database has not been configured yet"
+	} else if !cluster.Status.Health.Available {
+		available.Status = metav1.ConditionFalse
+		available.Reason = "DatabaseUnavailable"
+		available.Message = "The database is not accepting reads and writes"
+	}
+
+	fullyReplicated := metav1.Condition{
+		Type:   string(ClusterFullyReplicated),
+		Status: metav1.ConditionTrue,
+		Reason: "FullReplication",
+	}
+	if !cluster.Status.Health.FullReplication {
+		fullyReplicated.Status = metav1.ConditionFalse
+		fullyReplicated.Reason = "DataNotFullyReplicated"
+		fullyReplicated.Message = fmt.Sprintf("Data are not fully replicated, highest data movement priority is %d", cluster.Status.Health.DataMovementPriority)
+	}
+
+	upgrading := metav1.Condition{
+		Type:   string(ClusterUpgrading),
+		Status: metav1.ConditionFalse,
+		Reason: "VersionReconciled",
+	}
+	if cluster.Status.RunningVersion != "" && cluster.Status.RunningVersion != cluster.Spec.Version {
+		upgrading.Status = metav1.ConditionTrue
+		upgrading.Reason = "VersionMismatch"
+		upgrading.Message = fmt.Sprintf("Running version is %s, desired version is %s", cluster.Status.RunningVersion, cluster.Spec.Version)
+	}
+
+	degraded := metav1.Condition{
+		Type:   string(ClusterDegraded),
+		Status: metav1.ConditionFalse,
+		Reason: "DatabaseHealthy",
+	}
+	var degradedProcessGroups []ProcessGroupID
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		for _, condition := range processGroup.ProcessGroupConditions {
+			if _, ok := degradingProcessGroupConditions[condition.ProcessGroupConditionType]; ok {
+				degradedProcessGroups = append(degradedProcessGroups, processGroup.ProcessGroupID)
+				break
+			}
+		}
+	}
+	if cluster.Status.Configured && !cluster.Status.Health.Healthy {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "DatabaseUnhealthy"
+		degraded.Message = "The database is not fully healthy"
+	} else if len(degradedProcessGroups) > 0 {
+		degraded.Status = metav1.ConditionTrue
+		degraded.Reason = "ProcessGroupsFailing"
+		degraded.Message = fmt.Sprintf("%d process groups have failure conditions, e.g. %s", len(degradedProcessGroups), degradedProcessGroups[0])
+	}
+
+	for _, condition := range []metav1.Condition{available, reconciled, fullyReplicated, upgrading, progressing, degraded} {
+		condition.ObservedGeneration = cluster.ObjectMeta.Generation
+		condition.LastTransitionTime = metav1.NewTime(now)
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	}
+}
+
 // GetStorageServersPerPod returns the StorageServer per Pod.
 func (cluster *FoundationDBCluster) GetStorageServersPerPod() int {
 	if cluster.Spec.StorageServersPerPod <= 1 {
@@ -1431,6 +1842,27 @@ func (cluster *FoundationDBCluster) GetFullAddressList(address string, primaryOn
 		cluster.Status.RequiredAddresses.NonTLS)
 }
 
+// GetTrustedCAs returns the CAs that should be written to the CA file in the
+// ConfigMap. During a change of the trusted CAs this will return the CAs from
+// the status, which are rolled out in steps, otherwise the CAs from the spec.
+func (cluster *FoundationDBCluster) GetTrustedCAs() []string {
+	if len(cluster.Status.TLS.TrustedCAs) > 0 {
+		return cluster.Status.TLS.TrustedCAs
+	}
+
+	return cluster.Spec.TrustedCAs
+}
+
+// GetTLSCertificateSecrets returns the names of the Secrets that contain the
+// certificates of the cluster.
+func (cluster *FoundationDBCluster) GetTLSCertificateSecrets() []string {
+	if cluster.Spec.TLS == nil {
+		return nil
+	}
+
+	return cluster.Spec.TLS.CertificateSecrets
+}
+
 // HasCoordinators checks whether this connection string matches a set of
 // coordinators.
 func (str *ConnectionString) HasCoordinators(coordinators []ProcessAddress) bool {
@@ -1458,6 +1890,23 @@ func (str *ConnectionString) HasCoordinators(coordinators []ProcessAddress) bool
 	return true
 }
 
+// HasDNSCoordinators checks whether any of the coordinators in this connection
+// string is identified by a DNS name rather than an IP address.
+func (str *ConnectionString) HasDNSCoordinators() bool {
+	for _, coordinator := range str.Coordinators {
+		address, err := ParseProcessAddress(coordinator)
+		if err != nil {
+			continue
+		}
+
+		if address.StringAddress != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
 // FoundationDBClusterFaultDomain describes the fault domain that a cluster is
 // replicated across.
 type FoundationDBClusterFaultDomain struct {
@@ -1691,9 +2140,11 @@ type RoutingConfig struct {
 	PodIPFamily *int `json:"podIPFamily,omitempty"`
 
 	// UseDNSInClusterFile determines whether to use DNS names rather than IP
-	// addresses to identify coordinators in the cluster file.
-	// NOTE: This is an experimental feature, and is not supported in the
-	// latest stable version of FoundationDB.
+	// addresses to identify coordinators in the cluster file. The DNS names
+	// are created by the headless service of the cluster, so coordinators
+	// keep their address when a Pod gets a new IP address. An existing cluster
+	// file with IP addresses will be migrated to DNS names.
+	// This requires FoundationDB 7.1 or later.
 	UseDNSInClusterFile *bool `json:"useDNSInClusterFile,omitempty"`
 
 	// DNSDomain defines the cluster domain used in a DNS name generated for a
@@ -1714,6 +2165,78 @@ type RequiredAddressSet struct {
 	NonTLS bool `json:"nonTLS,omitempty"`
 }
 
+// TLSConfiguration defines the certificates that are used by a cluster.
+type TLSConfiguration struct {
+	// CertificateSecrets defines the names of the Secrets that contain the
+	// certificates of the cluster, e.g. Secrets issued by cert-manager.
+	// The operator reads the certificate from the tls.crt key to report its
+	// expiry in the status, and trusts the CA from the ca.crt key in
+	// addition to the TrustedCAs.
+	// +kubebuilder:validation:MaxItems=10
+	CertificateSecrets []string `json:"certificateSecrets,omitempty"`
+}
+
+// TLSTransitionPhase describes a step in the transition of a cluster between
+// TLS and non-TLS.
+// +kubebuilder:validation:MaxLength=64
+type TLSTransitionPhase string
+
+const (
+	// TLSTransitionAddListeners is the step in which the processes are
+	// restarted to listen on the TLS and on the non-TLS address.
+	TLSTransitionAddListeners TLSTransitionPhase = "AddListeners"
+
+	// TLSTransitionChangeCoordinators is the step in which all processes
+	// listen on both addresses, and the coordinators and the connection
+	// string are changed to the new addresses.
+	TLSTransitionChangeCoordinators TLSTransitionPhase = "ChangeCoordinators"
+
+	// TLSTransitionRemoveListeners is the step in which the coordinators use
+	// the new addresses, and the processes are restarted to stop listening
+	// on the old address.
+	TLSTransitionRemoveListeners TLSTransitionPhase = "RemoveListeners"
+)
+
+// TLSStatus provides information about a pending transition between TLS and
+// non-TLS and about the certificates of the cluster.
+type TLSStatus struct {
+	// Transition provides the current step of a transition between TLS and
+	// non-TLS. This is empty if no transition is pending.
+	Transition TLSTransitionPhase `json:"transition,omitempty"`
+
+	// TrustedCAs provides the CAs that are currently written to the CA file
+	// in the ConfigMap. While the trusted CAs are changed, this contains the
+	// old and the new CAs.
+	TrustedCAs []string `json:"trustedCAs,omitempty"`
+
+	// Certificates provides information about the certificates in the
+	// Secrets defined in the TLS configuration.
+	// +kubebuilder:validation:MaxItems=10
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+}
+
+// CertificateStatus provides information about a certificate in a Secret.
+type CertificateStatus struct {
+	// SecretName provides the name of the Secret that contains the
+	// certificate.
+	SecretName string `json:"secretName"`
+
+	// Subject provides the subject of the certificate.
+	Subject string `json:"subject,omitempty"`
+
+	// Issuer provides the issuer of the certificate.
+	Issuer string `json:"issuer,omitempty"`
+
+	// SerialNumber provides the serial number of the certificate.
+	SerialNumber string `json:"serialNumber,omitempty"`
+
+	// NotAfter provides the time when the certificate expires.
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// Error provides the reason why the certificate could not be read.
+	Error string `json:"error,omitempty"`
+}
+
 // CrashLoopContainerObject specifies crash-loop target for specific container.
 type CrashLoopContainerObject struct {
 	// Name of the target container.
@@ -1884,6 +2407,31 @@ func (cluster *FoundationDBCluster) GetFailedPodDuration() time.Duration {
 	return time.Duration(pointer.IntDeref(cluster.Spec.AutomationOptions.FailedPodDurationSeconds, 300)) * time.Second
 }
 
+// GetFixCoordinatorIPs returns the value of fixCoordinatorIPs or false if unset.
+func (cluster *FoundationDBCluster) GetFixCoordinatorIPs() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.FixCoordinatorIPs, false)
+}
+
+// GetStorageCapacityManagementEnabled returns the value of storageCapacity.enabled or false if unset.
+func (cluster *FoundationDBCluster) GetStorageCapacityManagementEnabled() bool {
+	return pointer.BoolDeref(cluster.Spec.AutomationOptions.StorageCapacity.Enabled, false)
+}
+
+// GetLowDiskSpaceThresholdPercent returns the value of storageCapacity.lowDiskSpaceThresholdPercent or 10 if unset.
+func (cluster *FoundationDBCluster) GetLowDiskSpaceThresholdPercent() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.StorageCapacity.LowDiskSpaceThresholdPercent, 10)
+}
+
+// GetVolumeExpansionPercent returns the value of storageCapacity.volumeExpansionPercent or 50 if unset.
+func (cluster *FoundationDBCluster) GetVolumeExpansionPercent() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.StorageCapacity.VolumeExpansionPercent, 50)
+}
+
+// GetStorageScaleIntervalSeconds returns the value of storageCapacity.scaleIntervalSeconds or 3600 if unset.
+func (cluster *FoundationDBCluster) GetStorageScaleIntervalSeconds() int {
+	return pointer.IntDeref(cluster.Spec.AutomationOptions.StorageCapacity.ScaleIntervalSeconds, 3600)
+}
+
 // GetUseNonBlockingExcludes returns the value of useNonBlockingExcludes or false if unset.
 func (cluster *FoundationDBCluster) GetUseNonBlockingExcludes() bool {
 	return pointer.BoolDeref(cluster.Spec.AutomationOptions.UseNonBlockingExcludes, false)
@@ -2273,6 +2821,9 @@ func (cluster *FoundationDBCluster) Validate() error {
 		}
 	}
 
+	validations = append(validations, cluster.Spec.Autoscaling.validate()...)
+	validations = append(validations, cluster.Spec.Scale.validate()...)
+
 	if len(validations) == 0 {
 		return nil
 	}
@@ -29,6 +29,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/pointer"
 
@@ -426,6 +427,43 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 			}))
 		})
 
+		When("the replicas are set through the scale subresource", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts = ProcessCounts{
+					Storage: 10,
+				}
+				cluster.Spec.Scale.Replicas = pointer.Int(7)
+			})
+
+			It("should use the replicas for the storage processes", func() {
+				counts, err := cluster.GetProcessCountsWithDefaults()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(counts.Storage).To(Equal(7))
+			})
+
+			It("should change the replicas when setting the storage process count", func() {
+				cluster.SetProcessCount(ProcessClassStorage, 8)
+				Expect(cluster.Spec.Scale.Replicas).To(Equal(pointer.Int(8)))
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(10))
+
+				cluster.SetProcessCount(ProcessClassLog, 6)
+				Expect(cluster.Spec.ProcessCounts.Log).To(Equal(6))
+			})
+
+			When("a different process class is scaled", func() {
+				BeforeEach(func() {
+					cluster.Spec.Scale.ProcessClass = ProcessClassLog
+				})
+
+				It("should use the replicas for the log processes", func() {
+					counts, err := cluster.GetProcessCountsWithDefaults()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(counts.Storage).To(Equal(10))
+					Expect(counts.Log).To(Equal(7))
+				})
+			})
+		})
+
 		When("using a version that supports grv and commit proxies", func() {
 			It("should return the default process counts", func() {
 				cluster.Spec.Version = "7.1.0"
@@ -691,6 +729,15 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				Status: FoundationDBLiveBackupStatusState{
 					Running: true,
 				},
+				CurrentSnapshot: &FoundationDBLiveBackupStatusSnapshot{
+					Begin: FoundationDBLiveBackupStatusVersion{
+						Version:      334642281,
+						EpochSeconds: 1588041701,
+					},
+					IntervalSeconds:  864000,
+					ExpectedProgress: 0.00155186,
+				},
+				Errors: []FoundationDBLiveBackupStatusError{},
 			}))
 		})
 	})
@@ -730,6 +777,18 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 		})
 	})
 
+	When("checking for DNS coordinators", func() {
+		It("should detect coordinators with DNS names", func() {
+			str, err := ParseConnectionString("test:abcd@127.0.0.1:4500,127.0.0.2:4500,127.0.0.3:4500")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(str.HasDNSCoordinators()).To(BeFalse())
+
+			str, err = ParseConnectionString("test:abcd@storage-1.test.svc.cluster.local:4500,127.0.0.2:4500,127.0.0.3:4500")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(str.HasDNSCoordinators()).To(BeTrue())
+		})
+	})
+
 	When("formatting the connection string", func() {
 		It("should be formatted correctly", func() {
 			str := ConnectionString{
@@ -2797,6 +2856,17 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 					expectedStr: "::1",
 					err:         nil,
 				}),
+			Entry("IPv6 in brackets without port",
+				testCase{
+					input: "[fd00::1]",
+					expectedAddr: ProcessAddress{
+						IPAddress: net.ParseIP("fd00::1"),
+						Port:      0,
+						Flags:     nil,
+					},
+					expectedStr: "fd00::1",
+					err:         nil,
+				}),
 			Entry("IPv6 with bad port",
 				testCase{
 					input: "[::1]:bad",
@@ -3076,6 +3146,16 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 					HasExtraListeners: 2,
 				}))
 
+				cluster = createCluster()
+				cluster.Status.TLS.Transition = TLSTransitionRemoveListeners
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeFalse())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled:        1,
+					HasExtraListeners: 2,
+				}))
+
 				cluster = createCluster()
 				cluster.Spec.ProcessCounts.Storage = 2
 				cluster.Status.ProcessGroups[0].MarkForRemoval()
@@ -3144,6 +3224,15 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 					NeedsBounce:         2,
 				}))
 
+				cluster = createCluster()
+				cluster.Status.ProcessGroups[0].UpdateCondition(LowDiskSpace, true, nil, "")
+				result, err = cluster.CheckReconciliation(log)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result).To(BeTrue())
+				Expect(cluster.Status.Generations).To(Equal(ClusterGenerationStatus{
+					Reconciled: 2,
+				}))
+
 				cluster = createCluster()
 				cluster.Spec.LockOptions.DenyList = append(cluster.Spec.LockOptions.DenyList, LockDenyListEntry{ID: "dc1"})
 				result, err = cluster.CheckReconciliation(log)
@@ -3440,6 +3529,195 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 
 	})
 
+	When("updating the conditions of a cluster", func() {
+		var cluster *FoundationDBCluster
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now()
+			cluster = &FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "sample-cluster",
+					Namespace:  "default",
+					Generation: 2,
+				},
+				Spec: FoundationDBClusterSpec{
+					Version: "7.1.26",
+				},
+				Status: FoundationDBClusterStatus{
+					Health: ClusterHealth{
+						Available:       true,
+						Healthy:         true,
+						FullReplication: true,
+					},
+					Generations: ClusterGenerationStatus{
+						Reconciled: 2,
+					},
+					ProcessGroups: []*ProcessGroupStatus{
+						{ProcessGroupID: "storage-1", ProcessClass: "storage"},
+					},
+					RunningVersion: "7.1.26",
+					Configured:     true,
+				},
+			}
+		})
+
+		When("the cluster is reconciled and healthy", func() {
+			BeforeEach(func() {
+				cluster.UpdateConditions(now)
+			})
+
+			It("should set all conditions", func() {
+				Expect(cluster.Status.Conditions).To(HaveLen(6))
+				for _, condition := range cluster.Status.Conditions {
+					Expect(condition.ObservedGeneration).To(BeNumerically("==", 2))
+				}
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ClusterAvailable))).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ClusterReconciled))).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ClusterFullyReplicated))).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(ClusterUpgrading))).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(ClusterProgressing))).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(ClusterDegraded))).To(BeTrue())
+			})
+		})
+
+		When("the cluster is not configured", func() {
+			BeforeEach(func() {
+				cluster.Status = FoundationDBClusterStatus{
+					Generations: ClusterGenerationStatus{
+						NeedsConfigurationChange: 2,
+					},
+				}
+				cluster.UpdateConditions(now)
+			})
+
+			It("should mark the cluster as unavailable and progressing", func() {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterAvailable))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("DatabaseNotConfigured"))
+				condition = meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterProgressing))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("NeedsConfigurationChange"))
+			})
+
+			It("should not mark the cluster as degraded", func() {
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(ClusterDegraded))).To(BeTrue())
+			})
+		})
+
+		When("the latest generation is not reconciled", func() {
+			BeforeEach(func() {
+				cluster.Status.Generations = ClusterGenerationStatus{
+					Reconciled:  1,
+					NeedsBounce: 2,
+					NeedsGrow:   2,
+				}
+				cluster.UpdateConditions(now)
+			})
+
+			It("should use the first pending generation as reason", func() {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterReconciled))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("NeedsBounce"))
+				Expect(condition.Message).To(Equal("Generation 2 is not reconciled, pending: NeedsBounce, NeedsGrow"))
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ClusterProgressing))).To(BeTrue())
+			})
+		})
+
+		When("disruptive actions are delayed by the disruption schedule", func() {
+			BeforeEach(func() {
+				cluster.Status.Generations = ClusterGenerationStatus{
+					Reconciled:  1,
+					NeedsBounce: 2,
+				}
+				cluster.Status.PendingDisruptions = &PendingDisruptionsStatus{
+					Actions: []string{"controllers.bounceProcesses"},
+					Reason:  "Disruptive actions are only allowed inside the maintenance windows",
+				}
+				cluster.UpdateConditions(now)
+			})
+
+			It("should mark the cluster as not progressing", func() {
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(ClusterReconciled))).To(BeTrue())
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterProgressing))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("DisruptionsPending"))
+				Expect(condition.Message).To(Equal("Disruptive actions are delayed: Disruptive actions are only allowed inside the maintenance windows"))
+			})
+		})
+
+		When("the running version differs from the desired version", func() {
+			BeforeEach(func() {
+				cluster.Spec.Version = "7.3.27"
+				cluster.UpdateConditions(now)
+			})
+
+			It("should mark the cluster as upgrading", func() {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterUpgrading))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Message).To(Equal("Running version is 7.1.26, desired version is 7.3.27"))
+			})
+		})
+
+		When("a process group has a failure condition", func() {
+			BeforeEach(func() {
+				cluster.Status.ProcessGroups[0].ProcessGroupConditions = []*ProcessGroupCondition{
+					NewProcessGroupCondition(MissingProcesses),
+				}
+				cluster.UpdateConditions(now)
+			})
+
+			It("should mark the cluster as degraded", func() {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterDegraded))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+				Expect(condition.Reason).To(Equal("ProcessGroupsFailing"))
+			})
+		})
+
+		When("a process group has a rollout condition", func() {
+			BeforeEach(func() {
+				cluster.Status.ProcessGroups[0].ProcessGroupConditions = []*ProcessGroupCondition{
+					NewProcessGroupCondition(IncorrectPodSpec),
+				}
+				cluster.UpdateConditions(now)
+			})
+
+			It("should not mark the cluster as degraded", func() {
+				Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(ClusterDegraded))).To(BeTrue())
+			})
+		})
+
+		When("the database is not fully replicated", func() {
+			BeforeEach(func() {
+				cluster.Status.Health.Healthy = false
+				cluster.Status.Health.FullReplication = false
+				cluster.UpdateConditions(now.Add(-time.Minute))
+				cluster.Status.Health.DataMovementPriority = 100
+				cluster.UpdateConditions(now)
+			})
+
+			It("should mark the cluster as degraded and not fully replicated", func() {
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ClusterDegraded))).To(BeTrue())
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterFullyReplicated))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Message).To(Equal("Data are not fully replicated, highest data movement priority is 100"))
+			})
+
+			It("should keep the transition time of the unchanged conditions", func() {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(ClusterFullyReplicated))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.LastTransitionTime.Time).To(BeTemporally("~", now.Add(-time.Minute), time.Second))
+			})
+		})
+	})
+
 	When("getting the process settings", func() {
 		It("should return the correct settings", func() {
 			cluster := &FoundationDBCluster{
@@ -4650,6 +4928,50 @@ var _ = Describe("[api] FoundationDBCluster", func() {
 				},
 				nil,
 			),
+			Entry("using an autoscaling range with a minimum larger than the maximum",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.2.0",
+						Autoscaling: AutoscalingOptions{
+							Storage: &AutoscalingRange{Min: 6, Max: 3},
+						},
+					},
+				},
+				fmt.Errorf("autoscaling range for storage has a minimum of 6 which is larger than the maximum of 3"),
+			),
+			Entry("using a process class without a process count for the scale subresource",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.2.0",
+						Scale: ScaleOptions{
+							ProcessClass: ProcessClassGeneral,
+						},
+					},
+				},
+				fmt.Errorf("process class general can't be used for the scale subresource"),
+			),
+			Entry("scaling the process class to 0 replicas",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.2.0",
+						Scale: ScaleOptions{
+							Replicas: pointer.Int(0),
+						},
+					},
+				},
+				fmt.Errorf("scale.replicas must be at least 1, got 0"),
+			),
+			Entry("scaling the process class to 1 replica",
+				&FoundationDBCluster{
+					Spec: FoundationDBClusterSpec{
+						Version: "7.2.0",
+						Scale: ScaleOptions{
+							Replicas: pointer.Int(1),
+						},
+					},
+				},
+				nil,
+			),
 		)
 	})
 
@@ -0,0 +1,280 @@
+/*
+Copyright 2023 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdbdr
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Source",type="string",JSONPath=".status.drDetails.sourceClusterName",description="Cluster that is currently replicated",priority=0
+// +kubebuilder:printcolumn:name="Destination",type="string",JSONPath=".status.drDetails.destinationClusterName",description="Cluster that currently receives the replicated data",priority=0
+// +kubebuilder:printcolumn:name="Lag",type="integer",JSONPath=".status.drDetails.lagMilliseconds",description="Lag of the destination cluster in milliseconds",priority=0
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBDisasterRecovery is the Schema for the foundationdbdisasterrecoveries API
+type FoundationDBDisasterRecovery struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FoundationDBDisasterRecoverySpec   `json:"spec,omitempty"`
+	Status FoundationDBDisasterRecoveryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBDisasterRecoveryList contains a list of FoundationDBDisasterRecovery objects
+type FoundationDBDisasterRecoveryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBDisasterRecovery `json:"items"`
+}
+
+// FoundationDBDisasterRecoverySpec describes the desired state of the
+// asynchronous replication between two clusters.
+type FoundationDBDisasterRecoverySpec struct {
+	// The version of FoundationDB that the DR agents should run.
+	Version string `json:"version"`
+
+	// SourceClusterName defines the cluster that should be replicated.
+	// Swapping the source and the destination cluster of a running DR will
+	// perform a switchover.
+	SourceClusterName string `json:"sourceClusterName"`
+
+	// DestinationClusterName defines the cluster that the source cluster
+	// should be replicated into. The destination cluster will be locked while
+	// the DR is running.
+	DestinationClusterName string `json:"destinationClusterName"`
+
+	// +kubebuilder:validation:Enum=Running;Stopped;Paused
+	// The desired state of the DR.
+	// The default is Running. Changing the state to Stopped will abort the
+	// DR.
+	DRState DisasterRecoveryState `json:"drState,omitempty"`
+
+	// AgentCount defines the number of DR agents to run.
+	// The default is run 2 agents.
+	AgentCount *int `json:"agentCount,omitempty"`
+
+	// AgentDeploymentMetadata allows customizing labels and annotations on the
+	// deployment for the DR agents.
+	AgentDeploymentMetadata *metav1.ObjectMeta `json:"agentDeploymentMetadata,omitempty"`
+
+	// PodTemplateSpec allows customizing the pod template for the DR agents.
+	PodTemplateSpec *corev1.PodTemplateSpec `json:"podTemplateSpec,omitempty"`
+
+	// CustomParameters defines additional parameters to pass to the DR
+	// agents.
+	CustomParameters FoundationDBCustomParameters `json:"customParameters,omitempty"`
+
+	// MainContainer defines customization for the foundationdb container.
+	MainContainer ContainerOverrides `json:"mainContainer,omitempty"`
+
+	// SidecarContainer defines customization for the
+	// foundationdb-kubernetes-sidecar container.
+	SidecarContainer ContainerOverrides `json:"sidecarContainer,omitempty"`
+}
+
+// FoundationDBDisasterRecoveryStatus describes the current status of the
+// asynchronous replication between two clusters.
+type FoundationDBDisasterRecoveryStatus struct {
+	// AgentCount provides the number of agents that are up-to-date, ready,
+	// and not terminated.
+	AgentCount int `json:"agentCount,omitempty"`
+
+	// DeploymentConfigured indicates whether the deployment is correctly
+	// configured.
+	DeploymentConfigured bool `json:"deploymentConfigured,omitempty"`
+
+	// DRDetails provides information about the state of the DR.
+	DRDetails *FoundationDBDisasterRecoveryStatusDetails `json:"drDetails,omitempty"`
+
+	// Generations provides information about the latest generation to be
+	// reconciled, or to reach other stages in reconciliation.
+	Generations DisasterRecoveryGenerationStatus `json:"generations,omitempty"`
+}
+
+// FoundationDBDisasterRecoveryStatusDetails provides information about the
+// state of the DR.
+type FoundationDBDisasterRecoveryStatusDetails struct {
+	// SourceClusterName provides the cluster that is currently replicated.
+	// This differs from the spec while a switchover is pending.
+	SourceClusterName string `json:"sourceClusterName,omitempty"`
+
+	// DestinationClusterName provides the cluster that currently receives
+	// the replicated data.
+	DestinationClusterName string `json:"destinationClusterName,omitempty"`
+
+	// Running defines whether the DR is running.
+	Running bool `json:"running,omitempty"`
+
+	// Paused defines whether the DR agents are paused.
+	Paused bool `json:"paused,omitempty"`
+
+	// LagMilliseconds provides how far the destination cluster is behind
+	// the source cluster.
+	LagMilliseconds int64 `json:"lagMilliseconds,omitempty"`
+}
+
+// DisasterRecoveryGenerationStatus stores information on which generations
+// have reached different stages in reconciliation for the DR.
+type DisasterRecoveryGenerationStatus struct {
+	// Reconciled provides the last generation that was fully reconciled.
+	Reconciled int64 `json:"reconciled,omitempty"`
+
+	// NeedsAgentUpdate provides the last generation that could not complete
+	// reconciliation because the DR agent deployment needs to be updated.
+	NeedsAgentUpdate int64 `json:"needsAgentUpdate,omitempty"`
+
+	// NeedsStart provides the last generation that could not complete
+	// reconciliation because we need to start the DR.
+	NeedsStart int64 `json:"needsStart,omitempty"`
+
+	// NeedsAbort provides the last generation that could not complete
+	// reconciliation because we need to abort the DR.
+	NeedsAbort int64 `json:"needsAbort,omitempty"`
+
+	// NeedsPauseToggle provides the last generation that needs to have the
+	// DR paused or resumed.
+	NeedsPauseToggle int64 `json:"needsPauseToggle,omitempty"`
+
+	// NeedsSwitchover provides the last generation that could not complete
+	// reconciliation because we need to switch the source and the
+	// destination cluster.
+	NeedsSwitchover int64 `json:"needsSwitchover,omitempty"`
+}
+
+// DisasterRecoveryState defines the desired state of a DR
+type DisasterRecoveryState string
+
+const (
+	// DisasterRecoveryStateRunning defines the running state
+	DisasterRecoveryStateRunning DisasterRecoveryState = "Running"
+	// DisasterRecoveryStatePaused defines the paused state
+	DisasterRecoveryStatePaused DisasterRecoveryState = "Paused"
+	// DisasterRecoveryStateStopped defines the stopped state
+	DisasterRecoveryStateStopped DisasterRecoveryState = "Stopped"
+)
+
+// FoundationDBLiveDisasterRecoveryStatus describes the live status of the
+// DR, as provided by the DR status command.
+type FoundationDBLiveDisasterRecoveryStatus struct {
+	// Running determines whether the DR is currently running.
+	Running bool
+
+	// Paused determines whether the DR agents are paused.
+	Paused bool
+
+	// SecondsBehind provides how far the destination cluster is behind the
+	// source cluster.
+	SecondsBehind float64
+}
+
+// ShouldRun determines whether the DR should be running.
+func (dr *FoundationDBDisasterRecovery) ShouldRun() bool {
+	return dr.Spec.DRState == "" || dr.Spec.DRState == DisasterRecoveryStateRunning || dr.Spec.DRState == DisasterRecoveryStatePaused
+}
+
+// ShouldBePaused determines whether the DR agents should be paused.
+func (dr *FoundationDBDisasterRecovery) ShouldBePaused() bool {
+	return dr.Spec.DRState == DisasterRecoveryStatePaused
+}
+
+// GetDesiredAgentCount determines how many DR agents we should run.
+func (dr *FoundationDBDisasterRecovery) GetDesiredAgentCount() int {
+	return pointer.IntDeref(dr.Spec.AgentCount, 2)
+}
+
+// GetCurrentClusterNames returns the source and the destination cluster of
+// the DR that is currently running. If no DR has been recorded in the status,
+// this will return the clusters from the spec.
+func (dr *FoundationDBDisasterRecovery) GetCurrentClusterNames() (string, string) {
+	details := dr.Status.DRDetails
+	if details == nil || details.SourceClusterName == "" || details.DestinationClusterName == "" {
+		return dr.Spec.SourceClusterName, dr.Spec.DestinationClusterName
+	}
+
+	return details.SourceClusterName, details.DestinationClusterName
+}
+
+// NeedsSwitchover determines whether the source and the destination cluster
+// of the running DR have been swapped in the spec.
+func (dr *FoundationDBDisasterRecovery) NeedsSwitchover() bool {
+	if dr.Status.DRDetails == nil || !dr.Status.DRDetails.Running {
+		return false
+	}
+
+	source, destination := dr.GetCurrentClusterNames()
+	return source == dr.Spec.DestinationClusterName && destination == dr.Spec.SourceClusterName
+}
+
+// CheckReconciliation compares the spec and the status to determine if
+// reconciliation is complete.
+func (dr *FoundationDBDisasterRecovery) CheckReconciliation() (bool, error) {
+	var reconciled = true
+
+	desiredAgentCount := dr.GetDesiredAgentCount()
+	if dr.Status.AgentCount != desiredAgentCount || !dr.Status.DeploymentConfigured {
+		dr.Status.Generations.NeedsAgentUpdate = dr.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	isRunning := dr.Status.DRDetails != nil && dr.Status.DRDetails.Running
+	isPaused := dr.Status.DRDetails != nil && dr.Status.DRDetails.Paused
+
+	if dr.ShouldRun() && !isRunning {
+		dr.Status.Generations.NeedsStart = dr.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if !dr.ShouldRun() && isRunning {
+		dr.Status.Generations.NeedsAbort = dr.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if isRunning && dr.ShouldBePaused() != isPaused {
+		dr.Status.Generations.NeedsPauseToggle = dr.ObjectMeta.Generation
+		reconciled = false
+	}
+
+	if isRunning {
+		source, destination := dr.GetCurrentClusterNames()
+		if source != dr.Spec.SourceClusterName || destination != dr.Spec.DestinationClusterName {
+			dr.Status.Generations.NeedsSwitchover = dr.ObjectMeta.Generation
+			reconciled = false
+		}
+	}
+
+	if reconciled {
+		dr.Status.Generations = DisasterRecoveryGenerationStatus{
+			Reconciled: dr.ObjectMeta.Generation,
+		}
+	}
+
+	return reconciled, nil
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBDisasterRecovery{}, &FoundationDBDisasterRecoveryList{})
+}
@@ -0,0 +1,126 @@
+/*
+ * foundationdbdisasterrecovery_types_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[api] FoundationDBDisasterRecovery", func() {
+	var dr *FoundationDBDisasterRecovery
+
+	BeforeEach(func() {
+		dr = &FoundationDBDisasterRecovery{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "dr",
+				Generation: 2,
+			},
+			Spec: FoundationDBDisasterRecoverySpec{
+				SourceClusterName:      "primary",
+				DestinationClusterName: "secondary",
+				AgentCount:             pointer.Int(3),
+			},
+			Status: FoundationDBDisasterRecoveryStatus{
+				AgentCount:           3,
+				DeploymentConfigured: true,
+				DRDetails: &FoundationDBDisasterRecoveryStatusDetails{
+					SourceClusterName:      "primary",
+					DestinationClusterName: "secondary",
+					Running:                true,
+				},
+			},
+		}
+	})
+
+	DescribeTable("determining whether the DR should run",
+		func(state DisasterRecoveryState, shouldRun bool, shouldBePaused bool) {
+			dr.Spec.DRState = state
+			Expect(dr.ShouldRun()).To(Equal(shouldRun))
+			Expect(dr.ShouldBePaused()).To(Equal(shouldBePaused))
+		},
+		Entry("no state is set", DisasterRecoveryState(""), true, false),
+		Entry("the state is Running", DisasterRecoveryStateRunning, true, false),
+		Entry("the state is Paused", DisasterRecoveryStatePaused, true, true),
+		Entry("the state is Stopped", DisasterRecoveryStateStopped, false, false),
+	)
+
+	When("getting the current cluster names", func() {
+		It("should use the clusters from the status", func() {
+			dr.Spec.SourceClusterName = "secondary"
+			dr.Spec.DestinationClusterName = "primary"
+			source, destination := dr.GetCurrentClusterNames()
+			Expect(source).To(Equal("primary"))
+			Expect(destination).To(Equal("secondary"))
+			Expect(dr.NeedsSwitchover()).To(BeTrue())
+		})
+
+		It("should fall back to the spec if no DR is recorded", func() {
+			dr.Status.DRDetails = nil
+			dr.Spec.SourceClusterName = "secondary"
+			dr.Spec.DestinationClusterName = "primary"
+			source, destination := dr.GetCurrentClusterNames()
+			Expect(source).To(Equal("secondary"))
+			Expect(destination).To(Equal("primary"))
+			Expect(dr.NeedsSwitchover()).To(BeFalse())
+		})
+	})
+
+	When("checking the reconciliation", func() {
+		It("should be reconciled if the DR is running", func() {
+			Expect(dr.CheckReconciliation()).To(BeTrue())
+			Expect(dr.Status.Generations).To(Equal(DisasterRecoveryGenerationStatus{Reconciled: 2}))
+		})
+
+		It("should need a start if the DR is not running", func() {
+			dr.Status.DRDetails.Running = false
+			Expect(dr.CheckReconciliation()).To(BeFalse())
+			Expect(dr.Status.Generations.NeedsStart).To(BeNumerically("==", 2))
+		})
+
+		It("should need an abort if the DR is stopped", func() {
+			dr.Spec.DRState = DisasterRecoveryStateStopped
+			Expect(dr.CheckReconciliation()).To(BeFalse())
+			Expect(dr.Status.Generations.NeedsAbort).To(BeNumerically("==", 2))
+		})
+
+		It("should need a pause toggle if the DR should be paused", func() {
+			dr.Spec.DRState = DisasterRecoveryStatePaused
+			Expect(dr.CheckReconciliation()).To(BeFalse())
+			Expect(dr.Status.Generations.NeedsPauseToggle).To(BeNumerically("==", 2))
+		})
+
+		It("should need a switchover if the clusters were swapped", func() {
+			dr.Spec.SourceClusterName = "secondary"
+			dr.Spec.DestinationClusterName = "primary"
+			Expect(dr.CheckReconciliation()).To(BeFalse())
+			Expect(dr.Status.Generations.NeedsSwitchover).To(BeNumerically("==", 2))
+		})
+
+		It("should need an agent update if the agent count differs", func() {
+			dr.Status.AgentCount = 2
+			Expect(dr.CheckReconciliation()).To(BeFalse())
+			Expect(dr.Status.Generations.NeedsAgentUpdate).To(BeNumerically("==", 2))
+		})
+	})
+})
@@ -17,6 +17,9 @@ limitations under the License.
 package v1beta2
 
 import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -65,8 +68,27 @@ type FoundationDBRestoreSpec struct {
 type FoundationDBRestoreStatus struct {
 	// Running describes whether the restore is currently running.
 	Running bool `json:"running,omitempty"`
+
+	// Conditions provides a summary of the state of the restore that follows
+	// the Kubernetes API conventions, e.g. whether the restore was started.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
+// RestoreConditionType defines the type of a condition in the restore status.
+type RestoreConditionType string
+
+const (
+	// RestoreRunning is set to true when the operator has started the
+	// restore.
+	RestoreRunning RestoreConditionType = "Running"
+
+	// RestoreReconciled is set to true when the latest generation of the spec
+	// is reconciled.
+	RestoreReconciled RestoreConditionType = "Reconciled"
+)
+
 // FoundationDBKeyRange describes a range of keys for a command.
 //
 // The keys in the key range must match the following pattern:
@@ -97,6 +119,38 @@ func (restore *FoundationDBRestore) BackupURL() string {
 	return restore.Spec.BlobStoreConfiguration.getURL(restore.BackupName(), restore.Spec.BlobStoreConfiguration.BucketName())
 }
 
+// UpdateConditions sets the conditions in the restore status. The restore
+// has no generation status, so the result of the reconciliation must be
+// passed in. The message describes why the reconciliation didn't complete.
+func (restore *FoundationDBRestore) UpdateConditions(reconciled bool, message string, now time.Time) {
+	running := metav1.Condition{
+		Type:   string(RestoreRunning),
+		Status: metav1.ConditionTrue,
+		Reason: "RestoreStarted",
+	}
+	if !restore.Status.Running {
+		running.Status = metav1.ConditionFalse
+		running.Reason = "RestoreNotStarted"
+	}
+
+	reconciledCondition := metav1.Condition{
+		Type:   string(RestoreReconciled),
+		Status: metav1.ConditionTrue,
+		Reason: "ReconciliationComplete",
+	}
+	if !reconciled {
+		reconciledCondition.Status = metav1.ConditionFalse
+		reconciledCondition.Reason = "ReconciliationFailed"
+		reconciledCondition.Message = message
+	}
+
+	for _, condition := range []metav1.Condition{running, reconciledCondition} {
+		condition.ObservedGeneration = restore.ObjectMeta.Generation
+		condition.LastTransitionTime = metav1.NewTime(now)
+		meta.SetStatusCondition(&restore.Status.Conditions, condition)
+	}
+}
+
 func init() {
 	SchemeBuilder.Register(&FoundationDBRestore{}, &FoundationDBRestoreList{})
 }
@@ -21,12 +21,51 @@
 package v1beta2
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var _ = Describe("[api] FoundationDBRestore", func() {
+	When("updating the conditions", func() {
+		var restore *FoundationDBRestore
+		var now time.Time
+
+		BeforeEach(func() {
+			now = time.Now()
+			restore = &FoundationDBRestore{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:       "mybackup",
+					Generation: 2,
+				},
+			}
+		})
+
+		It("should set the conditions for a running restore", func() {
+			restore.Status.Running = true
+			restore.UpdateConditions(true, "", now)
+			Expect(restore.Status.Conditions).To(HaveLen(2))
+			Expect(meta.IsStatusConditionTrue(restore.Status.Conditions, string(RestoreRunning))).To(BeTrue())
+			condition := meta.FindStatusCondition(restore.Status.Conditions, string(RestoreReconciled))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.ObservedGeneration).To(BeNumerically("==", 2))
+		})
+
+		It("should set the conditions for a failed reconciliation", func() {
+			restore.UpdateConditions(false, "invalid backup URL", now)
+			Expect(meta.IsStatusConditionFalse(restore.Status.Conditions, string(RestoreRunning))).To(BeTrue())
+			condition := meta.FindStatusCondition(restore.Status.Conditions, string(RestoreReconciled))
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("ReconciliationFailed"))
+			Expect(condition.Message).To(Equal("invalid backup URL"))
+		})
+	})
+
 	When("getting the backup URL", func() {
 		DescribeTable("should generate the correct backup URL",
 			func(restore FoundationDBRestore, expected string) {
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 FoundationDB project authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TenantFinalizer is the finalizer that the operator adds to tenants to
+// delete the tenant in the cluster before the resource is removed.
+const TenantFinalizer = "foundationdb.org/tenant"
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName=fdbtenant
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Generation",type="integer",JSONPath=".metadata.generation",description="Latest generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Reconciled",type="integer",JSONPath=".status.generations.reconciled",description="Last reconciled generation of the spec",priority=0
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.clusterName",description="Cluster that contains the tenant",priority=0
+// +kubebuilder:printcolumn:name="ID",type="integer",JSONPath=".status.id",description="ID of the tenant",priority=0
+// +kubebuilder:printcolumn:name="State",type="string",JSONPath=".status.state",description="State of the tenant",priority=0
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:storageversion
+
+// FoundationDBTenant is the Schema for the foundationdbtenants API
+type FoundationDBTenant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   FoundationDBTenantSpec   `json:"spec,omitempty"`
+	Status FoundationDBTenantStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// FoundationDBTenantList contains a list of FoundationDBTenant objects
+type FoundationDBTenantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []FoundationDBTenant `json:"items"`
+}
+
+// FoundationDBTenantSpec describes the desired state of a tenant in a
+// cluster.
+type FoundationDBTenantSpec struct {
+	// ClusterName defines the cluster that contains the tenant.
+	ClusterName string `json:"clusterName"`
+
+	// TenantName defines the name of the tenant in the cluster.
+	// The default is the name of the resource.
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9][a-zA-Z0-9_.-]*$
+	TenantName string `json:"tenantName,omitempty"`
+
+	// TenantGroup defines the tenant group that the tenant should be
+	// assigned to. Tenant groups are not supported yet, since they require FDB
+	// API version 720. The operator rejects tenants that define a tenant
+	// group.
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9][a-zA-Z0-9_.-]*$
+	TenantGroup string `json:"tenantGroup,omitempty"`
+
+	// ForceDeletion defines whether the data of the tenant should be cleared
+	// when the resource is deleted. By default the operator refuses to delete
+	// a tenant that holds data.
+	ForceDeletion bool `json:"forceDeletion,omitempty"`
+}
+
+// FoundationDBTenantStatus describes the current state of a tenant in a
+// cluster.
+type FoundationDBTenantStatus struct {
+	// ID provides the ID that FoundationDB assigned to the tenant.
+	ID *int64 `json:"id,omitempty"`
+
+	// State provides the state of the tenant, as reported by FoundationDB.
+	State string `json:"state,omitempty"`
+
+	// TenantGroup provides the tenant group that the tenant is assigned to.
+	TenantGroup string `json:"tenantGroup,omitempty"`
+
+	// Generations provides information about the latest generation to be
+	// reconciled, or to reach other stages in reconciliation.
+	Generations TenantGenerationStatus `json:"generations,omitempty"`
+}
+
+// TenantGenerationStatus stores information on which generations have reached
+// different stages in reconciliation for the tenant.
+type TenantGenerationStatus struct {
+	// Reconciled provides the last generation that was fully reconciled.
+	Reconciled int64 `json:"reconciled,omitempty"`
+}
+
+// FoundationDBLiveTenantStatus describes the live status of a tenant, as
+// provided by the tenant management special keys.
+type FoundationDBLiveTenantStatus struct {
+	// ID provides the ID of the tenant.
+	ID int64
+
+	// State provides the state of the tenant.
+	State string
+
+	// TenantGroup provides the tenant group of the tenant.
+	TenantGroup string
+}
+
+// GetTenantName returns the name of the tenant in the cluster.
+// This will fill in a default value if the tenant name in the spec is empty.
+func (tenant *FoundationDBTenant) GetTenantName() string {
+	if tenant.Spec.TenantName == "" {
+		return tenant.ObjectMeta.Name
+	}
+
+	return tenant.Spec.TenantName
+}
+
+// CheckReconciliation compares the spec and the status to determine if
+// reconciliation is complete.
+func (tenant *FoundationDBTenant) CheckReconciliation() bool {
+	reconciled := tenant.Status.ID != nil
+	if reconciled {
+		tenant.Status.Generations.Reconciled = tenant.ObjectMeta.Generation
+	}
+
+	return reconciled
+}
+
+// Validate checks if all settings in the tenant are valid, if not an error
+// will be returned.
+func (tenant *FoundationDBTenant) Validate() error {
+	// Assigning a tenant to a tenant group requires FDB API version 720,
+	// which is not supported by the FDB client bindings of the operator.
+	if tenant.Spec.TenantGroup != "" {
+		return fmt.Errorf("tenantGroup is not supported, assigning tenants to tenant groups requires FDB API version 720")
+	}
+
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&FoundationDBTenant{}, &FoundationDBTenantList{})
+}
@@ -0,0 +1,85 @@
+/*
+ * foundationdbtenant_types_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta2
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[api] FoundationDBTenant", func() {
+	var tenant *FoundationDBTenant
+
+	BeforeEach(func() {
+		tenant = &FoundationDBTenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:       "sample-tenant",
+				Generation: 2,
+			},
+			Spec: FoundationDBTenantSpec{
+				ClusterName: "sample-cluster",
+			},
+		}
+	})
+
+	When("getting the tenant name", func() {
+		It("should default to the name of the resource", func() {
+			Expect(tenant.GetTenantName()).To(Equal("sample-tenant"))
+		})
+
+		It("should use the name from the spec", func() {
+			tenant.Spec.TenantName = "other-tenant"
+			Expect(tenant.GetTenantName()).To(Equal("other-tenant"))
+		})
+	})
+
+	When("checking the reconciliation", func() {
+		It("should not be reconciled if the tenant doesn't exist", func() {
+			Expect(tenant.CheckReconciliation()).To(BeFalse())
+			Expect(tenant.Status.Generations.Reconciled).To(BeZero())
+		})
+
+		It("should be reconciled if the tenant exists", func() {
+			tenant.Status.ID = pointer.Int64(1)
+			Expect(tenant.CheckReconciliation()).To(BeTrue())
+			Expect(tenant.Status.Generations.Reconciled).To(BeNumerically("==", 2))
+		})
+
+		It("should ignore a tenant group that was assigned outside of the operator", func() {
+			tenant.Status.ID = pointer.Int64(1)
+			tenant.Status.TenantGroup = "group"
+			Expect(tenant.CheckReconciliation()).To(BeTrue())
+		})
+	})
+
+	When("validating the tenant", func() {
+		It("should accept a tenant without a tenant group", func() {
+			Expect(tenant.Validate()).To(Succeed())
+		})
+
+		It("should reject a tenant with a tenant group", func() {
+			tenant.Spec.TenantGroup = "group"
+			Expect(tenant.Validate()).To(MatchError("tenantGroup is not supported, assigning tenants to tenant groups requires FDB API version 720"))
+		})
+	})
+})
@@ -22,12 +22,33 @@ limitations under the License.
 package v1beta2
 
 import (
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	netx "net"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditEntry) DeepCopyInto(out *AuditEntry) {
+	*out = *in
+	in.Timestamp.DeepCopyInto(&out.Timestamp)
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditEntry.
+func (in *AuditEntry) DeepCopy() *AuditEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AutomaticReplacementOptions) DeepCopyInto(out *AutomaticReplacementOptions) {
 	*out = *in
@@ -58,6 +79,177 @@ func (in *AutomaticReplacementOptions) DeepCopy() *AutomaticReplacementOptions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingDecision) DeepCopyInto(out *AutoscalingDecision) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingDecision.
+func (in *AutoscalingDecision) DeepCopy() *AutoscalingDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingOptions) DeepCopyInto(out *AutoscalingOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Storage != nil {
+		in, out := &in.Storage, &out.Storage
+		*out = new(AutoscalingRange)
+		**out = **in
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(AutoscalingRange)
+		**out = **in
+	}
+	if in.CommitProxies != nil {
+		in, out := &in.CommitProxies, &out.CommitProxies
+		*out = new(AutoscalingRange)
+		**out = **in
+	}
+	if in.GrvProxies != nil {
+		in, out := &in.GrvProxies, &out.GrvProxies
+		*out = new(AutoscalingRange)
+		**out = **in
+	}
+	if in.ScaleUpCooldownSeconds != nil {
+		in, out := &in.ScaleUpCooldownSeconds, &out.ScaleUpCooldownSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleDownCooldownSeconds != nil {
+		in, out := &in.ScaleDownCooldownSeconds, &out.ScaleDownCooldownSeconds
+		*out = new(int)
+		**out = **in
+	}
+	in.Thresholds.DeepCopyInto(&out.Thresholds)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingOptions.
+func (in *AutoscalingOptions) DeepCopy() *AutoscalingOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingRange) DeepCopyInto(out *AutoscalingRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingRange.
+func (in *AutoscalingRange) DeepCopy() *AutoscalingRange {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingStatus) DeepCopyInto(out *AutoscalingStatus) {
+	*out = *in
+	if in.Decisions != nil {
+		in, out := &in.Decisions, &out.Decisions
+		*out = make([]AutoscalingDecision, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingStatus.
+func (in *AutoscalingStatus) DeepCopy() *AutoscalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingThresholds) DeepCopyInto(out *AutoscalingThresholds) {
+	*out = *in
+	if in.StorageQueueBytes != nil {
+		in, out := &in.StorageQueueBytes, &out.StorageQueueBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LogQueueBytes != nil {
+		in, out := &in.LogQueueBytes, &out.LogQueueBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.DiskUsedPercent != nil {
+		in, out := &in.DiskUsedPercent, &out.DiskUsedPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.CommitLatencyMilliseconds != nil {
+		in, out := &in.CommitLatencyMilliseconds, &out.CommitLatencyMilliseconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.GrvLatencyMilliseconds != nil {
+		in, out := &in.GrvLatencyMilliseconds, &out.GrvLatencyMilliseconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleDownPercent != nil {
+		in, out := &in.ScaleDownPercent, &out.ScaleDownPercent
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingThresholds.
+func (in *AutoscalingThresholds) DeepCopy() *AutoscalingThresholds {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingThresholds)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupAlertingConfiguration) DeepCopyInto(out *BackupAlertingConfiguration) {
+	*out = *in
+	if in.LagThresholdSeconds != nil {
+		in, out := &in.LagThresholdSeconds, &out.LagThresholdSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.SnapshotStaleThresholdSeconds != nil {
+		in, out := &in.SnapshotStaleThresholdSeconds, &out.SnapshotStaleThresholdSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupAlertingConfiguration.
+func (in *BackupAlertingConfiguration) DeepCopy() *BackupAlertingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupAlertingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupGenerationStatus) DeepCopyInto(out *BackupGenerationStatus) {
 	*out = *in
@@ -73,6 +265,78 @@ func (in *BackupGenerationStatus) DeepCopy() *BackupGenerationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationConfiguration) DeepCopyInto(out *BackupVerificationConfiguration) {
+	*out = *in
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int)
+		**out = **in
+	}
+	in.ClusterTemplate.DeepCopyInto(&out.ClusterTemplate)
+	if in.CheckPodTemplateSpec != nil {
+		in, out := &in.CheckPodTemplateSpec, &out.CheckPodTemplateSpec
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationConfiguration.
+func (in *BackupVerificationConfiguration) DeepCopy() *BackupVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationResult) DeepCopyInto(out *BackupVerificationResult) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationResult.
+func (in *BackupVerificationResult) DeepCopy() *BackupVerificationResult {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupVerificationStatus) DeepCopyInto(out *BackupVerificationStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastResult != nil {
+		in, out := &in.LastResult, &out.LastResult
+		*out = new(BackupVerificationResult)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupVerificationStatus.
+func (in *BackupVerificationStatus) DeepCopy() *BackupVerificationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupVerificationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlobStoreConfiguration) DeepCopyInto(out *BlobStoreConfiguration) {
 	*out = *in
@@ -81,6 +345,11 @@ func (in *BlobStoreConfiguration) DeepCopyInto(out *BlobStoreConfiguration) {
 		*out = make([]URLParameter, len(*in))
 		copy(*out, *in)
 	}
+	if in.CredentialsSecret != nil {
+		in, out := &in.CredentialsSecret, &out.CredentialsSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlobStoreConfiguration.
@@ -130,6 +399,45 @@ func (in *BuggifyConfig) DeepCopy() *BuggifyConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChaosReport) DeepCopyInto(out *ChaosReport) {
+	*out = *in
+	if in.MinimumFaultTolerance != nil {
+		in, out := &in.MinimumFaultTolerance, &out.MinimumFaultTolerance
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChaosReport.
+func (in *ChaosReport) DeepCopy() *ChaosReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ChaosReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterGenerationStatus) DeepCopyInto(out *ClusterGenerationStatus) {
 	*out = *in
@@ -210,6 +518,41 @@ func (in *ContainerOverrides) DeepCopy() *ContainerOverrides {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorIPChange) DeepCopyInto(out *CoordinatorIPChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorIPChange.
+func (in *CoordinatorIPChange) DeepCopy() *CoordinatorIPChange {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorIPChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CoordinatorIPRecoveryStatus) DeepCopyInto(out *CoordinatorIPRecoveryStatus) {
+	*out = *in
+	if in.Coordinators != nil {
+		in, out := &in.Coordinators, &out.Coordinators
+		*out = make([]CoordinatorIPChange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CoordinatorIPRecoveryStatus.
+func (in *CoordinatorIPRecoveryStatus) DeepCopy() *CoordinatorIPRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CoordinatorIPRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CoordinatorSelectionSetting) DeepCopyInto(out *CoordinatorSelectionSetting) {
 	*out = *in
@@ -289,6 +632,48 @@ func (in *DatabaseConfiguration) DeepCopy() *DatabaseConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisasterRecoveryGenerationStatus) DeepCopyInto(out *DisasterRecoveryGenerationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisasterRecoveryGenerationStatus.
+func (in *DisasterRecoveryGenerationStatus) DeepCopy() *DisasterRecoveryGenerationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DisasterRecoveryGenerationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DisruptionScheduleOptions) DeepCopyInto(out *DisruptionScheduleOptions) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		copy(*out, *in)
+	}
+	if in.FreezeWindows != nil {
+		in, out := &in.FreezeWindows, &out.FreezeWindows
+		*out = make([]FreezeWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisruptionScheduleOptions.
+func (in *DisruptionScheduleOptions) DeepCopy() *DisruptionScheduleOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(DisruptionScheduleOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExcludedServers) DeepCopyInto(out *ExcludedServers) {
 	*out = *in
@@ -320,15 +705,73 @@ func (in *FaultTolerance) DeepCopy() *FaultTolerance {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBBackup) DeepCopyInto(out *FoundationDBBackup) {
+func (in *FoundationDBAuditEvent) DeepCopyInto(out *FoundationDBAuditEvent) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackup.
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBAuditEvent.
+func (in *FoundationDBAuditEvent) DeepCopy() *FoundationDBAuditEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBAuditEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBAuditEvent) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBAuditEventList) DeepCopyInto(out *FoundationDBAuditEventList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBAuditEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBAuditEventList.
+func (in *FoundationDBAuditEventList) DeepCopy() *FoundationDBAuditEventList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBAuditEventList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBAuditEventList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBBackup) DeepCopyInto(out *FoundationDBBackup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackup.
 func (in *FoundationDBBackup) DeepCopy() *FoundationDBBackup {
 	if in == nil {
 		return nil
@@ -393,12 +836,12 @@ func (in *FoundationDBBackupSpec) DeepCopyInto(out *FoundationDBBackupSpec) {
 	}
 	if in.BackupDeploymentMetadata != nil {
 		in, out := &in.BackupDeploymentMetadata, &out.BackupDeploymentMetadata
-		*out = new(v1.ObjectMeta)
+		*out = new(metav1.ObjectMeta)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.PodTemplateSpec != nil {
 		in, out := &in.PodTemplateSpec, &out.PodTemplateSpec
-		*out = new(corev1.PodTemplateSpec)
+		*out = new(v1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.CustomParameters != nil {
@@ -418,6 +861,16 @@ func (in *FoundationDBBackupSpec) DeepCopyInto(out *FoundationDBBackupSpec) {
 	}
 	in.MainContainer.DeepCopyInto(&out.MainContainer)
 	in.SidecarContainer.DeepCopyInto(&out.SidecarContainer)
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerting != nil {
+		in, out := &in.Alerting, &out.Alerting
+		*out = new(BackupAlertingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupSpec.
@@ -436,9 +889,21 @@ func (in *FoundationDBBackupStatus) DeepCopyInto(out *FoundationDBBackupStatus)
 	if in.BackupDetails != nil {
 		in, out := &in.BackupDetails, &out.BackupDetails
 		*out = new(FoundationDBBackupStatusBackupDetails)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	out.Generations = in.Generations
+	if in.Verification != nil {
+		in, out := &in.Verification, &out.Verification
+		*out = new(BackupVerificationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupStatus.
@@ -454,6 +919,24 @@ func (in *FoundationDBBackupStatus) DeepCopy() *FoundationDBBackupStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBBackupStatusBackupDetails) DeepCopyInto(out *FoundationDBBackupStatusBackupDetails) {
 	*out = *in
+	if in.LatestRestorableVersion != nil {
+		in, out := &in.LatestRestorableVersion, &out.LatestRestorableVersion
+		*out = new(int64)
+		**out = **in
+	}
+	if in.LatestRestorableTime != nil {
+		in, out := &in.LatestRestorableTime, &out.LatestRestorableTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CurrentSnapshotStartTime != nil {
+		in, out := &in.CurrentSnapshotStartTime, &out.CurrentSnapshotStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBBackupStatusBackupDetails.
@@ -466,6 +949,132 @@ func (in *FoundationDBBackupStatusBackupDetails) DeepCopy() *FoundationDBBackupS
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBChaos) DeepCopyInto(out *FoundationDBChaos) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBChaos.
+func (in *FoundationDBChaos) DeepCopy() *FoundationDBChaos {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBChaos)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBChaos) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBChaosList) DeepCopyInto(out *FoundationDBChaosList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBChaos, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBChaosList.
+func (in *FoundationDBChaosList) DeepCopy() *FoundationDBChaosList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBChaosList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBChaosList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBChaosSpec) DeepCopyInto(out *FoundationDBChaosSpec) {
+	*out = *in
+	if in.Count != nil {
+		in, out := &in.Count, &out.Count
+		*out = new(int)
+		**out = **in
+	}
+	if in.IntervalSeconds != nil {
+		in, out := &in.IntervalSeconds, &out.IntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.DurationSeconds != nil {
+		in, out := &in.DurationSeconds, &out.DurationSeconds
+		*out = new(int)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBChaosSpec.
+func (in *FoundationDBChaosSpec) DeepCopy() *FoundationDBChaosSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBChaosSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBChaosStatus) DeepCopyInto(out *FoundationDBChaosStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.EndTime != nil {
+		in, out := &in.EndTime, &out.EndTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastInjectionTime != nil {
+		in, out := &in.LastInjectionTime, &out.LastInjectionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]ProcessGroupID, len(*in))
+		copy(*out, *in)
+	}
+	in.Report.DeepCopyInto(&out.Report)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBChaosStatus.
+func (in *FoundationDBChaosStatus) DeepCopy() *FoundationDBChaosStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBChaosStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBCluster) DeepCopyInto(out *FoundationDBCluster) {
 	*out = *in
@@ -548,6 +1157,19 @@ func (in *FoundationDBClusterAutomationOptions) DeepCopyInto(out *FoundationDBCl
 		**out = **in
 	}
 	in.MaintenanceModeOptions.DeepCopyInto(&out.MaintenanceModeOptions)
+	if in.FixCoordinatorIPs != nil {
+		in, out := &in.FixCoordinatorIPs, &out.FixCoordinatorIPs
+		*out = new(bool)
+		**out = **in
+	}
+	in.StorageCapacity.DeepCopyInto(&out.StorageCapacity)
+	in.OperationQueue.DeepCopyInto(&out.OperationQueue)
+	if in.ReconciliationHistorySize != nil {
+		in, out := &in.ReconciliationHistorySize, &out.ReconciliationHistorySize
+		*out = new(int)
+		**out = **in
+	}
+	in.DisruptionSchedule.DeepCopyInto(&out.DisruptionSchedule)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterAutomationOptions.
@@ -633,7 +1255,7 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 	}
 	if in.ConfigMap != nil {
 		in, out := &in.ConfigMap, &out.ConfigMap
-		*out = new(corev1.ConfigMap)
+		*out = new(v1.ConfigMap)
 		(*in).DeepCopyInto(*out)
 	}
 	in.MainContainer.DeepCopyInto(&out.MainContainer)
@@ -643,6 +1265,11 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SidecarVariables != nil {
 		in, out := &in.SidecarVariables, &out.SidecarVariables
 		*out = make([]string, len(*in))
@@ -673,6 +1300,9 @@ func (in *FoundationDBClusterSpec) DeepCopyInto(out *FoundationDBClusterSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	in.ResourceRecommendations.DeepCopyInto(&out.ResourceRecommendations)
+	in.Autoscaling.DeepCopyInto(&out.Autoscaling)
+	in.Scale.DeepCopyInto(&out.Scale)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterSpec.
@@ -692,6 +1322,7 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 	out.Generations = in.Generations
 	out.Health = in.Health
 	out.RequiredAddresses = in.RequiredAddresses
+	in.TLS.DeepCopyInto(&out.TLS)
 	if in.StorageServersPerDisk != nil {
 		in, out := &in.StorageServersPerDisk, &out.StorageServersPerDisk
 		*out = make([]int, len(*in))
@@ -715,6 +1346,51 @@ func (in *FoundationDBClusterStatus) DeepCopyInto(out *FoundationDBClusterStatus
 	}
 	in.Locks.DeepCopyInto(&out.Locks)
 	in.MaintenanceModeInfo.DeepCopyInto(&out.MaintenanceModeInfo)
+	if in.CoordinatorIPRecovery != nil {
+		in, out := &in.CoordinatorIPRecovery, &out.CoordinatorIPRecovery
+		*out = new(CoordinatorIPRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingCoordinatorIPRecovery != nil {
+		in, out := &in.PendingCoordinatorIPRecovery, &out.PendingCoordinatorIPRecovery
+		*out = new(CoordinatorIPRecoveryStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceRecommendations != nil {
+		in, out := &in.ResourceRecommendations, &out.ResourceRecommendations
+		*out = make([]ResourceRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.StorageScaling != nil {
+		in, out := &in.StorageScaling, &out.StorageScaling
+		*out = new(StorageScalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Scale = in.Scale
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]ProcessGroupOperation, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PendingDisruptions != nil {
+		in, out := &in.PendingDisruptions, &out.PendingDisruptions
+		*out = new(PendingDisruptionsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBClusterStatus.
@@ -747,72 +1423,26 @@ func (in FoundationDBCustomParameters) DeepCopy() FoundationDBCustomParameters {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBKeyRange) DeepCopyInto(out *FoundationDBKeyRange) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBKeyRange.
-func (in *FoundationDBKeyRange) DeepCopy() *FoundationDBKeyRange {
-	if in == nil {
-		return nil
-	}
-	out := new(FoundationDBKeyRange)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBLiveBackupStatus) DeepCopyInto(out *FoundationDBLiveBackupStatus) {
-	*out = *in
-	out.Status = in.Status
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatus.
-func (in *FoundationDBLiveBackupStatus) DeepCopy() *FoundationDBLiveBackupStatus {
-	if in == nil {
-		return nil
-	}
-	out := new(FoundationDBLiveBackupStatus)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBLiveBackupStatusState) DeepCopyInto(out *FoundationDBLiveBackupStatusState) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusState.
-func (in *FoundationDBLiveBackupStatusState) DeepCopy() *FoundationDBLiveBackupStatusState {
-	if in == nil {
-		return nil
-	}
-	out := new(FoundationDBLiveBackupStatusState)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestore) DeepCopyInto(out *FoundationDBRestore) {
+func (in *FoundationDBDisasterRecovery) DeepCopyInto(out *FoundationDBDisasterRecovery) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestore.
-func (in *FoundationDBRestore) DeepCopy() *FoundationDBRestore {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBDisasterRecovery.
+func (in *FoundationDBDisasterRecovery) DeepCopy() *FoundationDBDisasterRecovery {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBRestore)
+	out := new(FoundationDBDisasterRecovery)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
+func (in *FoundationDBDisasterRecovery) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -820,31 +1450,31 @@ func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestoreList) DeepCopyInto(out *FoundationDBRestoreList) {
+func (in *FoundationDBDisasterRecoveryList) DeepCopyInto(out *FoundationDBDisasterRecoveryList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]FoundationDBRestore, len(*in))
+		*out = make([]FoundationDBDisasterRecovery, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreList.
-func (in *FoundationDBRestoreList) DeepCopy() *FoundationDBRestoreList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBDisasterRecoveryList.
+func (in *FoundationDBDisasterRecoveryList) DeepCopy() *FoundationDBDisasterRecoveryList {
 	if in == nil {
 		return nil
 	}
-	out := new(FoundationDBRestoreList)
+	out := new(FoundationDBDisasterRecoveryList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
+func (in *FoundationDBDisasterRecoveryList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -852,13 +1482,298 @@ func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *FoundationDBRestoreSpec) DeepCopyInto(out *FoundationDBRestoreSpec) {
+func (in *FoundationDBDisasterRecoverySpec) DeepCopyInto(out *FoundationDBDisasterRecoverySpec) {
 	*out = *in
-	if in.KeyRanges != nil {
-		in, out := &in.KeyRanges, &out.KeyRanges
-		*out = make([]FoundationDBKeyRange, len(*in))
-		copy(*out, *in)
-	}
+	if in.AgentCount != nil {
+		in, out := &in.AgentCount, &out.AgentCount
+		*out = new(int)
+		**out = **in
+	}
+	if in.AgentDeploymentMetadata != nil {
+		in, out := &in.AgentDeploymentMetadata, &out.AgentDeploymentMetadata
+		*out = new(metav1.ObjectMeta)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodTemplateSpec != nil {
+		in, out := &in.PodTemplateSpec, &out.PodTemplateSpec
+		*out = new(v1.PodTemplateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomParameters != nil {
+		in, out := &in.CustomParameters, &out.CustomParameters
+		*out = make(FoundationDBCustomParameters, len(*in))
+		copy(*out, *in)
+	}
+	in.MainContainer.DeepCopyInto(&out.MainContainer)
+	in.SidecarContainer.DeepCopyInto(&out.SidecarContainer)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBDisasterRecoverySpec.
+func (in *FoundationDBDisasterRecoverySpec) DeepCopy() *FoundationDBDisasterRecoverySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBDisasterRecoverySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBDisasterRecoveryStatus) DeepCopyInto(out *FoundationDBDisasterRecoveryStatus) {
+	*out = *in
+	if in.DRDetails != nil {
+		in, out := &in.DRDetails, &out.DRDetails
+		*out = new(FoundationDBDisasterRecoveryStatusDetails)
+		**out = **in
+	}
+	out.Generations = in.Generations
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBDisasterRecoveryStatus.
+func (in *FoundationDBDisasterRecoveryStatus) DeepCopy() *FoundationDBDisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBDisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBDisasterRecoveryStatusDetails) DeepCopyInto(out *FoundationDBDisasterRecoveryStatusDetails) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBDisasterRecoveryStatusDetails.
+func (in *FoundationDBDisasterRecoveryStatusDetails) DeepCopy() *FoundationDBDisasterRecoveryStatusDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBDisasterRecoveryStatusDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBKeyRange) DeepCopyInto(out *FoundationDBKeyRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBKeyRange.
+func (in *FoundationDBKeyRange) DeepCopy() *FoundationDBKeyRange {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBKeyRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatus) DeepCopyInto(out *FoundationDBLiveBackupStatus) {
+	*out = *in
+	out.Status = in.Status
+	if in.LatestRestorablePoint != nil {
+		in, out := &in.LatestRestorablePoint, &out.LatestRestorablePoint
+		*out = new(FoundationDBLiveBackupStatusRestorablePoint)
+		**out = **in
+	}
+	if in.CurrentSnapshot != nil {
+		in, out := &in.CurrentSnapshot, &out.CurrentSnapshot
+		*out = new(FoundationDBLiveBackupStatusSnapshot)
+		**out = **in
+	}
+	if in.Errors != nil {
+		in, out := &in.Errors, &out.Errors
+		*out = make([]FoundationDBLiveBackupStatusError, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatus.
+func (in *FoundationDBLiveBackupStatus) DeepCopy() *FoundationDBLiveBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatusError) DeepCopyInto(out *FoundationDBLiveBackupStatusError) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusError.
+func (in *FoundationDBLiveBackupStatusError) DeepCopy() *FoundationDBLiveBackupStatusError {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatusError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatusRestorablePoint) DeepCopyInto(out *FoundationDBLiveBackupStatusRestorablePoint) {
+	*out = *in
+	out.FoundationDBLiveBackupStatusVersion = in.FoundationDBLiveBackupStatusVersion
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusRestorablePoint.
+func (in *FoundationDBLiveBackupStatusRestorablePoint) DeepCopy() *FoundationDBLiveBackupStatusRestorablePoint {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatusRestorablePoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatusSnapshot) DeepCopyInto(out *FoundationDBLiveBackupStatusSnapshot) {
+	*out = *in
+	out.Begin = in.Begin
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusSnapshot.
+func (in *FoundationDBLiveBackupStatusSnapshot) DeepCopy() *FoundationDBLiveBackupStatusSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatusSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatusState) DeepCopyInto(out *FoundationDBLiveBackupStatusState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusState.
+func (in *FoundationDBLiveBackupStatusState) DeepCopy() *FoundationDBLiveBackupStatusState {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatusState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveBackupStatusVersion) DeepCopyInto(out *FoundationDBLiveBackupStatusVersion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveBackupStatusVersion.
+func (in *FoundationDBLiveBackupStatusVersion) DeepCopy() *FoundationDBLiveBackupStatusVersion {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveBackupStatusVersion)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveDisasterRecoveryStatus) DeepCopyInto(out *FoundationDBLiveDisasterRecoveryStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveDisasterRecoveryStatus.
+func (in *FoundationDBLiveDisasterRecoveryStatus) DeepCopy() *FoundationDBLiveDisasterRecoveryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveDisasterRecoveryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBLiveTenantStatus) DeepCopyInto(out *FoundationDBLiveTenantStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBLiveTenantStatus.
+func (in *FoundationDBLiveTenantStatus) DeepCopy() *FoundationDBLiveTenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBLiveTenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestore) DeepCopyInto(out *FoundationDBRestore) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestore.
+func (in *FoundationDBRestore) DeepCopy() *FoundationDBRestore {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBRestore) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreList) DeepCopyInto(out *FoundationDBRestoreList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBRestore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreList.
+func (in *FoundationDBRestoreList) DeepCopy() *FoundationDBRestoreList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBRestoreList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBRestoreList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBRestoreSpec) DeepCopyInto(out *FoundationDBRestoreSpec) {
+	*out = *in
+	if in.KeyRanges != nil {
+		in, out := &in.KeyRanges, &out.KeyRanges
+		*out = make([]FoundationDBKeyRange, len(*in))
+		copy(*out, *in)
+	}
 	if in.BlobStoreConfiguration != nil {
 		in, out := &in.BlobStoreConfiguration, &out.BlobStoreConfiguration
 		*out = new(BlobStoreConfiguration)
@@ -884,6 +1799,13 @@ func (in *FoundationDBRestoreSpec) DeepCopy() *FoundationDBRestoreSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBRestoreStatus) DeepCopyInto(out *FoundationDBRestoreStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBRestoreStatus.
@@ -1008,6 +1930,8 @@ func (in *FoundationDBStatusClusterInfo) DeepCopyInto(out *FoundationDBStatusClu
 		copy(*out, *in)
 	}
 	out.RecoveryState = in.RecoveryState
+	out.Qos = in.Qos
+	out.LatencyProbe = in.LatencyProbe
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusClusterInfo.
@@ -1073,6 +1997,21 @@ func (in *FoundationDBStatusCoordinatorInfo) DeepCopy() *FoundationDBStatusCoord
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusCounter) DeepCopyInto(out *FoundationDBStatusCounter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusCounter.
+func (in *FoundationDBStatusCounter) DeepCopy() *FoundationDBStatusCounter {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusCounter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusDataState) DeepCopyInto(out *FoundationDBStatusDataState) {
 	*out = *in
@@ -1105,6 +2044,21 @@ func (in *FoundationDBStatusDataStatistics) DeepCopy() *FoundationDBStatusDataSt
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusLatencyProbe) DeepCopyInto(out *FoundationDBStatusLatencyProbe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusLatencyProbe.
+func (in *FoundationDBStatusLatencyProbe) DeepCopy() *FoundationDBStatusLatencyProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusLatencyProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusLayerInfo) DeepCopyInto(out *FoundationDBStatusLayerInfo) {
 	*out = *in
@@ -1153,6 +2107,51 @@ func (in *FoundationDBStatusMovingData) DeepCopy() *FoundationDBStatusMovingData
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusPerformanceLimitedBy) DeepCopyInto(out *FoundationDBStatusPerformanceLimitedBy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusPerformanceLimitedBy.
+func (in *FoundationDBStatusPerformanceLimitedBy) DeepCopy() *FoundationDBStatusPerformanceLimitedBy {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusPerformanceLimitedBy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusProcessCPU) DeepCopyInto(out *FoundationDBStatusProcessCPU) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessCPU.
+func (in *FoundationDBStatusProcessCPU) DeepCopy() *FoundationDBStatusProcessCPU {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusProcessCPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusProcessDisk) DeepCopyInto(out *FoundationDBStatusProcessDisk) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessDisk.
+func (in *FoundationDBStatusProcessDisk) DeepCopy() *FoundationDBStatusProcessDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusProcessDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusProcessInfo) {
 	*out = *in
@@ -1174,6 +2173,9 @@ func (in *FoundationDBStatusProcessInfo) DeepCopyInto(out *FoundationDBStatusPro
 		*out = make([]FoundationDBStatusProcessMessage, len(*in))
 		copy(*out, *in)
 	}
+	out.CPU = in.CPU
+	out.Memory = in.Memory
+	out.Disk = in.Disk
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessInfo.
@@ -1186,6 +2188,21 @@ func (in *FoundationDBStatusProcessInfo) DeepCopy() *FoundationDBStatusProcessIn
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusProcessMemory) DeepCopyInto(out *FoundationDBStatusProcessMemory) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessMemory.
+func (in *FoundationDBStatusProcessMemory) DeepCopy() *FoundationDBStatusProcessMemory {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusProcessMemory)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusProcessMessage) DeepCopyInto(out *FoundationDBStatusProcessMessage) {
 	*out = *in
@@ -1204,6 +2221,8 @@ func (in *FoundationDBStatusProcessMessage) DeepCopy() *FoundationDBStatusProces
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusProcessRoleInfo) DeepCopyInto(out *FoundationDBStatusProcessRoleInfo) {
 	*out = *in
+	out.InputBytes = in.InputBytes
+	out.DurableBytes = in.DurableBytes
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusProcessRoleInfo.
@@ -1216,6 +2235,22 @@ func (in *FoundationDBStatusProcessRoleInfo) DeepCopy() *FoundationDBStatusProce
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBStatusQosInfo) DeepCopyInto(out *FoundationDBStatusQosInfo) {
+	*out = *in
+	out.PerformanceLimitedBy = in.PerformanceLimitedBy
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBStatusQosInfo.
+func (in *FoundationDBStatusQosInfo) DeepCopy() *FoundationDBStatusQosInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBStatusQosInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FoundationDBStatusSupportedVersion) DeepCopyInto(out *FoundationDBStatusSupportedVersion) {
 	*out = *in
@@ -1241,6 +2276,118 @@ func (in *FoundationDBStatusSupportedVersion) DeepCopy() *FoundationDBStatusSupp
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTenant) DeepCopyInto(out *FoundationDBTenant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTenant.
+func (in *FoundationDBTenant) DeepCopy() *FoundationDBTenant {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTenant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBTenant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTenantList) DeepCopyInto(out *FoundationDBTenantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]FoundationDBTenant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTenantList.
+func (in *FoundationDBTenantList) DeepCopy() *FoundationDBTenantList {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTenantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *FoundationDBTenantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTenantSpec) DeepCopyInto(out *FoundationDBTenantSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTenantSpec.
+func (in *FoundationDBTenantSpec) DeepCopy() *FoundationDBTenantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTenantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FoundationDBTenantStatus) DeepCopyInto(out *FoundationDBTenantStatus) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int64)
+		**out = **in
+	}
+	out.Generations = in.Generations
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FoundationDBTenantStatus.
+func (in *FoundationDBTenantStatus) DeepCopy() *FoundationDBTenantStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FoundationDBTenantStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FreezeWindow) DeepCopyInto(out *FreezeWindow) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FreezeWindow.
+func (in *FreezeWindow) DeepCopy() *FreezeWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(FreezeWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ImageConfig) DeepCopyInto(out *ImageConfig) {
 	*out = *in
@@ -1348,83 +2495,165 @@ func (in *LockOptions) DeepCopy() *LockOptions {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LockSystemStatus) DeepCopyInto(out *LockSystemStatus) {
 	*out = *in
-	if in.DenyList != nil {
-		in, out := &in.DenyList, &out.DenyList
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
+	if in.DenyList != nil {
+		in, out := &in.DenyList, &out.DenyList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockSystemStatus.
+func (in *LockSystemStatus) DeepCopy() *LockSystemStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LockSystemStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceModeInfo) DeepCopyInto(out *MaintenanceModeInfo) {
+	*out = *in
+	if in.StartTimestamp != nil {
+		in, out := &in.StartTimestamp, &out.StartTimestamp
+		*out = (*in).DeepCopy()
+	}
+	if in.ProcessGroups != nil {
+		in, out := &in.ProcessGroups, &out.ProcessGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeInfo.
+func (in *MaintenanceModeInfo) DeepCopy() *MaintenanceModeInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceModeOptions) DeepCopyInto(out *MaintenanceModeOptions) {
+	*out = *in
+	if in.UseMaintenanceModeChecker != nil {
+		in, out := &in.UseMaintenanceModeChecker, &out.UseMaintenanceModeChecker
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaintenanceModeTimeSeconds != nil {
+		in, out := &in.MaintenanceModeTimeSeconds, &out.MaintenanceModeTimeSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeOptions.
+func (in *MaintenanceModeOptions) DeepCopy() *MaintenanceModeOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceModeOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	out.Duration = in.Duration
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LockSystemStatus.
-func (in *LockSystemStatus) DeepCopy() *LockSystemStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(LockSystemStatus)
+	out := new(MaintenanceWindow)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeInfo) DeepCopyInto(out *MaintenanceModeInfo) {
+func (in *None) DeepCopyInto(out *None) {
 	*out = *in
-	if in.StartTimestamp != nil {
-		in, out := &in.StartTimestamp, &out.StartTimestamp
-		*out = (*in).DeepCopy()
-	}
-	if in.ProcessGroups != nil {
-		in, out := &in.ProcessGroups, &out.ProcessGroups
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeInfo.
-func (in *MaintenanceModeInfo) DeepCopy() *MaintenanceModeInfo {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new None.
+func (in *None) DeepCopy() *None {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceModeInfo)
+	out := new(None)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MaintenanceModeOptions) DeepCopyInto(out *MaintenanceModeOptions) {
+func (in *OperationQueueOptions) DeepCopyInto(out *OperationQueueOptions) {
 	*out = *in
-	if in.UseMaintenanceModeChecker != nil {
-		in, out := &in.UseMaintenanceModeChecker, &out.UseMaintenanceModeChecker
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
 		*out = new(bool)
 		**out = **in
 	}
-	if in.MaintenanceModeTimeSeconds != nil {
-		in, out := &in.MaintenanceModeTimeSeconds, &out.MaintenanceModeTimeSeconds
+	if in.MaxConcurrentOperations != nil {
+		in, out := &in.MaxConcurrentOperations, &out.MaxConcurrentOperations
 		*out = new(int)
 		**out = **in
 	}
+	if in.MaxOperationsPerFaultDomain != nil {
+		in, out := &in.MaxOperationsPerFaultDomain, &out.MaxOperationsPerFaultDomain
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxDisruptedFaultDomains != nil {
+		in, out := &in.MaxDisruptedFaultDomains, &out.MaxDisruptedFaultDomains
+		*out = new(int)
+		**out = **in
+	}
+	if in.Priorities != nil {
+		in, out := &in.Priorities, &out.Priorities
+		*out = make(map[ProcessGroupOperationType]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceModeOptions.
-func (in *MaintenanceModeOptions) DeepCopy() *MaintenanceModeOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationQueueOptions.
+func (in *OperationQueueOptions) DeepCopy() *OperationQueueOptions {
 	if in == nil {
 		return nil
 	}
-	out := new(MaintenanceModeOptions)
+	out := new(OperationQueueOptions)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *None) DeepCopyInto(out *None) {
+func (in *PendingDisruptionsStatus) DeepCopyInto(out *PendingDisruptionsStatus) {
 	*out = *in
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NextAllowedTime != nil {
+		in, out := &in.NextAllowedTime, &out.NextAllowedTime
+		*out = (*in).DeepCopy()
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new None.
-func (in *None) DeepCopy() *None {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingDisruptionsStatus.
+func (in *PendingDisruptionsStatus) DeepCopy() *PendingDisruptionsStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(None)
+	out := new(PendingDisruptionsStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1486,6 +2715,21 @@ func (in *ProcessGroupCondition) DeepCopy() *ProcessGroupCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProcessGroupOperation) DeepCopyInto(out *ProcessGroupOperation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProcessGroupOperation.
+func (in *ProcessGroupOperation) DeepCopy() *ProcessGroupOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(ProcessGroupOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProcessGroupStatus) DeepCopyInto(out *ProcessGroupStatus) {
 	*out = *in
@@ -1530,12 +2774,12 @@ func (in *ProcessSettings) DeepCopyInto(out *ProcessSettings) {
 	*out = *in
 	if in.PodTemplate != nil {
 		in, out := &in.PodTemplate, &out.PodTemplate
-		*out = new(corev1.PodTemplateSpec)
+		*out = new(v1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.VolumeClaimTemplate != nil {
 		in, out := &in.VolumeClaimTemplate, &out.VolumeClaimTemplate
-		*out = new(corev1.PersistentVolumeClaim)
+		*out = new(v1.PersistentVolumeClaim)
 		(*in).DeepCopyInto(*out)
 	}
 	if in.CustomParameters != nil {
@@ -1555,6 +2799,46 @@ func (in *ProcessSettings) DeepCopy() *ProcessSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconciliationHistoryEntry) DeepCopyInto(out *ReconciliationHistoryEntry) {
+	*out = *in
+	if in.Requeue != nil {
+		in, out := &in.Requeue, &out.Requeue
+		*out = new(ReconciliationRequeue)
+		**out = **in
+	}
+	if in.DelayedRequeues != nil {
+		in, out := &in.DelayedRequeues, &out.DelayedRequeues
+		*out = make([]ReconciliationRequeue, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconciliationHistoryEntry.
+func (in *ReconciliationHistoryEntry) DeepCopy() *ReconciliationHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconciliationHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReconciliationRequeue) DeepCopyInto(out *ReconciliationRequeue) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReconciliationRequeue.
+func (in *ReconciliationRequeue) DeepCopy() *ReconciliationRequeue {
+	if in == nil {
+		return nil
+	}
+	out := new(ReconciliationRequeue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryState) DeepCopyInto(out *RecoveryState) {
 	*out = *in
@@ -1605,6 +2889,81 @@ func (in *RequiredAddressSet) DeepCopy() *RequiredAddressSet {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendation) DeepCopyInto(out *ResourceRecommendation) {
+	*out = *in
+	out.Usage = in.Usage
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendation.
+func (in *ResourceRecommendation) DeepCopy() *ResourceRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceRecommendationOptions) DeepCopyInto(out *ResourceRecommendationOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HeadroomPercent != nil {
+		in, out := &in.HeadroomPercent, &out.HeadroomPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.SampleIntervalSeconds != nil {
+		in, out := &in.SampleIntervalSeconds, &out.SampleIntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceRecommendationOptions.
+func (in *ResourceRecommendationOptions) DeepCopy() *ResourceRecommendationOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceRecommendationOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsage) DeepCopyInto(out *ResourceUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsage.
+func (in *ResourceUsage) DeepCopy() *ResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RoleCounts) DeepCopyInto(out *RoleCounts) {
 	*out = *in
@@ -1660,6 +3019,168 @@ func (in *RoutingConfig) DeepCopy() *RoutingConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleOptions) DeepCopyInto(out *ScaleOptions) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleOptions.
+func (in *ScaleOptions) DeepCopy() *ScaleOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleStatus) DeepCopyInto(out *ScaleStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleStatus.
+func (in *ScaleStatus) DeepCopy() *ScaleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageCapacityOptions) DeepCopyInto(out *StorageCapacityOptions) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.LowDiskSpaceThresholdPercent != nil {
+		in, out := &in.LowDiskSpaceThresholdPercent, &out.LowDiskSpaceThresholdPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.VolumeExpansionPercent != nil {
+		in, out := &in.VolumeExpansionPercent, &out.VolumeExpansionPercent
+		*out = new(int)
+		**out = **in
+	}
+	if in.MaxVolumeSize != nil {
+		in, out := &in.MaxVolumeSize, &out.MaxVolumeSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxStorageProcesses != nil {
+		in, out := &in.MaxStorageProcesses, &out.MaxStorageProcesses
+		*out = new(int)
+		**out = **in
+	}
+	if in.ScaleIntervalSeconds != nil {
+		in, out := &in.ScaleIntervalSeconds, &out.ScaleIntervalSeconds
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageCapacityOptions.
+func (in *StorageCapacityOptions) DeepCopy() *StorageCapacityOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageCapacityOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StorageScalingStatus) DeepCopyInto(out *StorageScalingStatus) {
+	*out = *in
+	if in.ProcessGroups != nil {
+		in, out := &in.ProcessGroups, &out.ProcessGroups
+		*out = make([]ProcessGroupID, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageScalingStatus.
+func (in *StorageScalingStatus) DeepCopy() *StorageScalingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(StorageScalingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSConfiguration) DeepCopyInto(out *TLSConfiguration) {
+	*out = *in
+	if in.CertificateSecrets != nil {
+		in, out := &in.CertificateSecrets, &out.CertificateSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSConfiguration.
+func (in *TLSConfiguration) DeepCopy() *TLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TLSStatus) DeepCopyInto(out *TLSStatus) {
+	*out = *in
+	if in.TrustedCAs != nil {
+		in, out := &in.TrustedCAs, &out.TrustedCAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TLSStatus.
+func (in *TLSStatus) DeepCopy() *TLSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TLSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TenantGenerationStatus) DeepCopyInto(out *TenantGenerationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TenantGenerationStatus.
+func (in *TenantGenerationStatus) DeepCopy() *TenantGenerationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(TenantGenerationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Version) DeepCopyInto(out *Version) {
 	*out = *in
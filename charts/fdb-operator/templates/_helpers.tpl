@@ -58,3 +58,14 @@ Create the name of the service account
     {{ default "default" .Values.serviceAccount.name }}
 {{- end -}}
 {{- end -}}
+
+{{/*
+Create the name of the secret with the serving certificate of the webhook
+*/}}
+{{- define "fdb-operator.webhookCertificateSecretName" -}}
+{{- if .Values.webhook.certManager.enabled -}}
+    {{ include "fdb-operator.fullname" . }}-webhook-cert
+{{- else -}}
+    {{ required "webhook.certificateSecretName is required if cert-manager is not used" .Values.webhook.certificateSecretName }}
+{{- end -}}
+{{- end -}}
@@ -110,7 +110,7 @@ func (a addPods) reconcile(ctx context.Context, r *FoundationDBClusterReconciler
 			if err != nil {
 				return &requeue{curError: err}
 			}
-			ip := service.Spec.ClusterIP
+			ip := internal.GetServiceIP(cluster, service)
 			if ip == "" {
 				logger.Info("Service does not have an IP address", "processGroupID", processGroup.ProcessGroupID)
 				return &requeue{message: fmt.Sprintf("Service %s does not have an IP address", service.Name)}
@@ -116,6 +116,11 @@ func updateService(ctx context.Context, r *FoundationDBClusterReconciler, cluste
 	originalSpec := currentService.Spec.DeepCopy()
 
 	currentService.Spec.Selector = newService.Spec.Selector
+	// Services that were created before the cluster file used DNS names have
+	// to publish the addresses of Pods that are not ready as well.
+	if cluster.UseDNSInClusterFile() {
+		currentService.Spec.PublishNotReadyAddresses = newService.Spec.PublishNotReadyAddresses
+	}
 
 	needsUpdate := !equality.Semantic.DeepEqual(currentService.Spec, *originalSpec)
 	metadata := currentService.ObjectMeta
@@ -31,6 +31,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("add_services", func() {
@@ -170,6 +171,39 @@ var _ = Describe("add_services", func() {
 		})
 	})
 
+	Context("with a headless service that doesn't publish the addresses of pods that are not ready", func() {
+		BeforeEach(func() {
+			service := &corev1.Service{}
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, service)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Spec.PublishNotReadyAddresses).To(BeFalse())
+		})
+
+		It("should not update the service", func() {
+			Expect(requeue).To(BeNil())
+
+			service := &corev1.Service{}
+			err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, service)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(service.Spec.PublishNotReadyAddresses).To(BeFalse())
+		})
+
+		When("the cluster file uses DNS names", func() {
+			BeforeEach(func() {
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			})
+
+			It("should publish the addresses of pods that are not ready", func() {
+				Expect(requeue).To(BeNil())
+
+				service := &corev1.Service{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}, service)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(service.Spec.PublishNotReadyAddresses).To(BeTrue())
+			})
+		})
+	})
+
 	Context("with no headless service", func() {
 		BeforeEach(func() {
 			service := &corev1.Service{}
@@ -0,0 +1,95 @@
+/*
+ * autoscale_cluster.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/autoscaling"
+)
+
+// autoscaleCluster adjusts the storage, log and proxy counts of the cluster
+// based on the signals reported in the machine-readable status.
+type autoscaleCluster struct{}
+
+// reconcile runs the reconciler's work.
+func (a autoscaleCluster) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "autoscaleCluster")
+	if !cluster.GetAutoscalingEnabled() {
+		return nil
+	}
+
+	// Only make a new decision once the previous change has been rolled out completely.
+	if !cluster.Status.Configured || cluster.Status.Generations.Reconciled != cluster.ObjectMeta.Generation {
+		logger.Info("Waiting for the cluster to be reconciled before autoscaling", "generation", cluster.ObjectMeta.Generation, "reconciled", cluster.Status.Generations.Reconciled)
+		return nil
+	}
+
+	adminClient, err := r.getAdminClient(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if !status.Client.DatabaseStatus.Available {
+		logger.Info("Skipping autoscaling because the database is not available")
+		return nil
+	}
+
+	now := r.now()
+	decisions, err := autoscaling.GetDecisions(cluster, status, now)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if len(decisions) == 0 {
+		return nil
+	}
+
+	logger.Info("Autoscaling cluster", "decisions", decisions)
+	err = r.updateProcessCounts(ctx, cluster, func(current *fdbv1beta2.FoundationDBCluster) {
+		autoscaling.ApplyDecisions(current, decisions)
+	}, func(status *fdbv1beta2.FoundationDBClusterStatus) {
+		status.Autoscaling = &fdbv1beta2.AutoscalingStatus{
+			LastScaleTimestamp: now.Unix(),
+			Decisions:          decisions,
+		}
+	})
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	for _, decision := range decisions {
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "Autoscaling",
+			fmt.Sprintf("Changing the %s count from %d to %d because %s", decision.Target, decision.Previous, decision.Desired, decision.Reason))
+	}
+
+	return &requeue{message: "Autoscaled the cluster"}
+}
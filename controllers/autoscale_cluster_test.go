@@ -0,0 +1,171 @@
+/*
+ * autoscale_cluster_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("autoscale_cluster", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var result *requeue
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Autoscaling.Enabled = pointer.Bool(true)
+		cluster.Spec.Autoscaling.Storage = &fdbv1beta2.AutoscalingRange{Min: 3, Max: 6}
+		Expect(setupClusterForTest(cluster)).To(Succeed())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(adminClient.FreezeStatus()).To(Succeed())
+		adminClient.FrozenStatus.Cluster.Qos = fdbv1beta2.FoundationDBStatusQosInfo{
+			PerformanceLimitedBy:         fdbv1beta2.FoundationDBStatusPerformanceLimitedBy{Name: "workload"},
+			WorstQueueBytesStorageServer: 600000000,
+		}
+	})
+
+	JustBeforeEach(func() {
+		result = autoscaleCluster{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the storage queue is above the threshold", func() {
+		It("should increase the storage count", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.message).To(Equal("Autoscaled the cluster"))
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+
+			latest := &fdbv1beta2.FoundationDBCluster{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), latest)).To(Succeed())
+			Expect(latest.Spec.ProcessCounts.Storage).To(Equal(5))
+			Expect(latest.Status.Autoscaling).NotTo(BeNil())
+			Expect(latest.Status.Autoscaling.LastScaleTimestamp).To(BeNumerically(">", 0))
+			Expect(latest.Status.Autoscaling.Decisions).To(ConsistOf(fdbv1beta2.AutoscalingDecision{
+				Target:   fdbv1beta2.AutoscalingTargetStorage,
+				Previous: 4,
+				Desired:  5,
+				Reason:   "worst storage queue of 600000000 bytes is above the threshold of 500000000 bytes",
+			}))
+		})
+	})
+
+	When("autoscaling is disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.Autoscaling.Enabled = nil
+		})
+
+		It("should not change the storage count", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+	})
+
+	When("the cluster is not reconciled", func() {
+		BeforeEach(func() {
+			cluster.Status.Generations.Reconciled = cluster.ObjectMeta.Generation - 1
+		})
+
+		It("should not change the storage count", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+	})
+
+	When("the database is unavailable", func() {
+		BeforeEach(func() {
+			adminClient.FrozenStatus.Client.DatabaseStatus.Available = false
+		})
+
+		It("should not change the storage count", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+	})
+
+	When("the cluster was scaled recently", func() {
+		BeforeEach(func() {
+			cluster.Status.Autoscaling = &fdbv1beta2.AutoscalingStatus{LastScaleTimestamp: time.Now().Unix()}
+		})
+
+		It("should not change the storage count", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+	})
+
+	When("the storage capacity management increased the storage count above the maximum", func() {
+		BeforeEach(func() {
+			cluster.Spec.Autoscaling.Storage.Max = 4
+			cluster.Spec.AutomationOptions.StorageCapacity.Enabled = pointer.Bool(true)
+			Expect(k8sClient.Update(context.TODO(), cluster)).To(Succeed())
+			adminClient.FrozenStatus.Cluster.Qos.WorstQueueBytesStorageServer = 1000
+
+			Expect(k8sClient.Create(context.TODO(), &storagev1.StorageClass{
+				ObjectMeta:           metav1.ObjectMeta{Name: "fixed"},
+				AllowVolumeExpansion: pointer.Bool(false),
+			})).To(Succeed())
+
+			pvcs := &corev1.PersistentVolumeClaimList{}
+			Expect(k8sClient.List(context.TODO(), pvcs, internal.GetSinglePodListOptions(cluster, "storage-1")...)).To(Succeed())
+			Expect(pvcs.Items).To(HaveLen(1))
+			pvcs.Items[0].Spec.StorageClassName = pointer.String("fixed")
+			Expect(k8sClient.Update(context.TODO(), &pvcs.Items[0])).To(Succeed())
+
+			processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1")
+			processGroup.ProcessGroupConditions = append(processGroup.ProcessGroupConditions, fdbv1beta2.NewProcessGroupCondition(fdbv1beta2.LowDiskSpace))
+
+			capacityResult := manageStorageCapacity{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			Expect(capacityResult).NotTo(BeNil())
+			Expect(capacityResult.message).To(Equal("Increased the storage process count"))
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+
+			// Mark the increase as rolled out, so that the autoscaler makes a decision.
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), cluster)).To(Succeed())
+			cluster.Status.Generations.Reconciled = cluster.ObjectMeta.Generation
+			Expect(k8sClient.Status().Update(context.TODO(), cluster)).To(Succeed())
+		})
+
+		It("should keep the storage count of the storage capacity management", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+
+			latest := &fdbv1beta2.FoundationDBCluster{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), latest)).To(Succeed())
+			Expect(latest.Spec.ProcessCounts.Storage).To(Equal(5))
+			Expect(latest.Status.Autoscaling).To(BeNil())
+		})
+	})
+})
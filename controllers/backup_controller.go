@@ -22,13 +22,23 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/go-logr/logr"
@@ -40,6 +50,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// backupStatusRefreshInterval defines how often the status of a running backup
+// is refreshed.
+const backupStatusRefreshInterval = time.Minute
+
 // FoundationDBBackupReconciler reconciles a FoundationDBCluster object
 type FoundationDBBackupReconciler struct {
 	client.Client
@@ -48,11 +62,18 @@ type FoundationDBBackupReconciler struct {
 	InSimulation           bool
 	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
 	ServerSideApply        bool
+	Sharder                *sharding.Sharder
+	PodCommandExecutor     internal.PodCommandExecutor
 }
 
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbbackups,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbbackups/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbrestores,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile runs the reconciliation logic.
@@ -75,6 +96,21 @@ func (r *FoundationDBBackupReconciler) Reconcile(ctx context.Context, request ct
 
 	backupLog := log.WithValues("namespace", backup.Namespace, "backup", backup.Name)
 
+	if !r.Sharder.OwnsCluster(backup.Namespace, backup.Spec.ClusterName) {
+		backupLog.V(1).Info("Skipping backup of cluster owned by another shard", "cluster", backup.Spec.ClusterName)
+		return ctrl.Result{}, nil
+	}
+
+	acquired, err := r.Sharder.AcquireCluster(ctx, backup.Namespace, backup.Spec.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !acquired {
+		backupLog.Info("Waiting for the previous shard to finish the reconciliation of the cluster", "cluster", backup.Spec.ClusterName)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	defer r.Sharder.ReleaseCluster(ctx, backup.Namespace, backup.Spec.ClusterName)
+
 	subReconcilers := []backupSubReconciler{
 		updateBackupStatus{},
 		updateBackupAgents{},
@@ -82,10 +118,18 @@ func (r *FoundationDBBackupReconciler) Reconcile(ctx context.Context, request ct
 		stopBackup{},
 		toggleBackupPaused{},
 		modifyBackup{},
+		verifyBackup{},
 		updateBackupStatus{},
 	}
 
 	for _, subReconciler := range subReconcilers {
+		// The cluster might have been assigned to another shard while the
+		// previous sub-reconcilers were running.
+		if !r.Sharder.HoldsCluster(backup.Namespace, backup.Spec.ClusterName) {
+			backupLog.Info("Stopping reconciliation of cluster owned by another shard", "subReconciler", fmt.Sprintf("%T", subReconciler), "cluster", backup.Spec.ClusterName)
+			return ctrl.Result{}, nil
+		}
+
 		requeue := subReconciler.reconcile(ctx, r, backup)
 		if requeue == nil {
 			continue
@@ -101,6 +145,13 @@ func (r *FoundationDBBackupReconciler) Reconcile(ctx context.Context, request ct
 
 	backupLog.Info("Reconciliation complete")
 
+	// A running backup is requeued periodically to keep the lag and the
+	// conditions in the status up to date. This also makes progress on the
+	// backup verification, which can only be enabled for running backups.
+	if backup.ShouldRun() {
+		return ctrl.Result{RequeueAfter: backupStatusRefreshInterval}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -143,23 +194,87 @@ func (r *FoundationDBBackupReconciler) SetupWithManager(mgr ctrl.Manager, maxCon
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	backupSelector, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return err
+	}
+
+	// Only react on generation changes or annotation changes and only watch
+	// resources with the provided label selector.
+	eventFilter := builder.WithPredicates(
+		predicate.And(
+			labelSelectorPredicate,
+			predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			),
+		))
+
+	managedBy := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles},
 		).
-		For(&fdbv1beta2.FoundationDBBackup{}).
-		Owns(&appsv1.Deployment{}).
-		// Only react on generation changes or annotation changes and only watch
-		// resources with the provided label selector.
-		WithEventFilter(
-			predicate.And(
-				labelSelectorPredicate,
-				predicate.Or(
-					predicate.GenerationChangedPredicate{},
-					predicate.AnnotationChangedPredicate{},
-				),
-			)).
-		Complete(r)
+		For(&fdbv1beta2.FoundationDBBackup{}, eventFilter).
+		Owns(&appsv1.Deployment{}, eventFilter).
+		// Secrets don't have a generation, so we react on every change to
+		// the data of a Secret that is used as blob store credentials.
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+				return r.findBackupsForCredentialsSecret(object, backupSelector)
+			}),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+
+	if r.Sharder != nil {
+		managedBy.Watches(r.Sharder.GetSource(&fdbv1beta2.FoundationDBBackupList{}), &handler.EnqueueRequestForObject{}, eventFilter)
+	}
+
+	return managedBy.Complete(r)
+}
+
+// findBackupsForCredentialsSecret returns the reconcile requests for all
+// backups that use the provided Secret as blob store credentials.
+func (r *FoundationDBBackupReconciler) findBackupsForCredentialsSecret(secret client.Object, selector labels.Selector) []reconcile.Request {
+	backups := &fdbv1beta2.FoundationDBBackupList{}
+	err := r.List(context.Background(), backups, client.InNamespace(secret.GetNamespace()), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		log.Error(err, "Error listing backups for credentials secret", "namespace", secret.GetNamespace(), "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, backup := range backups.Items {
+		configuration := backup.Spec.BlobStoreConfiguration
+		if configuration == nil || configuration.CredentialsSecret == nil || configuration.CredentialsSecret.Name != secret.GetName() {
+			continue
+		}
+
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&backup)})
+	}
+
+	return requests
+}
+
+// getCredentialsSecret fetches the Secret that contains the blob store
+// credentials for a backup. This will return nil if no Secret is configured.
+func (r *FoundationDBBackupReconciler) getCredentialsSecret(ctx context.Context, backup *fdbv1beta2.FoundationDBBackup) (*corev1.Secret, error) {
+	configuration := backup.Spec.BlobStoreConfiguration
+	if configuration == nil || configuration.CredentialsSecret == nil {
+		return nil, nil
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: configuration.CredentialsSecret.Name}, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := secret.Data[configuration.CredentialsSecret.Key]; !ok {
+		return nil, fmt.Errorf("credentials secret %s has no key %s", secret.Name, configuration.CredentialsSecret.Key)
+	}
+
+	return secret, nil
 }
 
 // backupSubReconciler describes a class that does part of the work of
@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
 
@@ -34,8 +35,12 @@ import (
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 )
 
 func reloadBackup(backup *fdbv1beta2.FoundationDBBackup) (int64, error) {
@@ -131,6 +136,17 @@ var _ = Describe("backup_controller", func() {
 			})
 
 			It("should update the status on the resource", func() {
+				conditions := backup.Status.Conditions
+				backup.Status.Conditions = nil
+				Expect(conditions).To(HaveLen(4))
+				for _, condition := range conditions {
+					if condition.Type == string(fdbv1beta2.BackupReconciled) {
+						Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+						continue
+					}
+					Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				}
+
 				Expect(backup.Status).To(Equal(fdbv1beta2.FoundationDBBackupStatus{
 					AgentCount:           3,
 					DeploymentConfigured: true,
@@ -298,6 +314,254 @@ var _ = Describe("backup_controller", func() {
 			})
 		})
 
+		When("using a credentials secret", func() {
+			var secret *corev1.Secret
+
+			BeforeEach(func() {
+				secret = &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: backup.Namespace,
+						Name:      "blob-credentials",
+					},
+					Data: map[string][]byte{
+						"credentials.json": []byte("{}"),
+					},
+				}
+				Expect(k8sClient.Create(context.TODO(), secret)).To(Succeed())
+
+				backup.Spec.BlobStoreConfiguration.CredentialsSecret = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+					Key:                  "credentials.json",
+				}
+				err = k8sClient.Update(context.TODO(), backup)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should mount the credentials in the deployment", func() {
+				deployment := &appsv1.Deployment{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-backup-agents", cluster.Name)}, deployment)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment.Spec.Template.Spec.Containers[0].Env).To(ContainElement(corev1.EnvVar{Name: "FDB_BLOB_CREDENTIALS", Value: "/var/backup-credentials/credentials"}))
+				Expect(deployment.Spec.Template.Annotations).To(HaveKey(fdbv1beta2.BlobCredentialsHashAnnotation))
+			})
+
+			When("the credentials are rotated", func() {
+				var originalHash string
+
+				JustBeforeEach(func() {
+					deployment := &appsv1.Deployment{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-backup-agents", cluster.Name)}, deployment)
+					Expect(err).NotTo(HaveOccurred())
+					originalHash = deployment.Spec.Template.Annotations[fdbv1beta2.BlobCredentialsHashAnnotation]
+
+					secret.Data["credentials.json"] = []byte(`{"accounts": {}}`)
+					Expect(k8sClient.Update(context.TODO(), secret)).To(Succeed())
+
+					_, err = reconcileBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should update the deployment", func() {
+					deployment := &appsv1.Deployment{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-backup-agents", cluster.Name)}, deployment)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(deployment.Spec.Template.Annotations[fdbv1beta2.BlobCredentialsHashAnnotation]).NotTo(Equal(originalHash))
+				})
+			})
+		})
+
+		When("using an invalid backup URL", func() {
+			BeforeEach(func() {
+				backup.Spec.BackupState = fdbv1beta2.BackupStateStopped
+				err = k8sClient.Update(context.TODO(), backup)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should not start a new backup", func() {
+				backup.Spec.BlobStoreConfiguration.URLParameters = []fdbv1beta2.URLParameter{"secure_connection"}
+				backup.Spec.BackupState = fdbv1beta2.BackupStateRunning
+				Expect(k8sClient.Update(context.TODO(), backup)).To(Succeed())
+
+				_, err = reconcileBackup(backup)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("must have the format key=value"))
+			})
+		})
+
+		When("enabling the backup verification", func() {
+			BeforeEach(func() {
+				backup.Spec.Verification = &fdbv1beta2.BackupVerificationConfiguration{
+					ClusterTemplate: *internal.CreateDefaultCluster().Spec.DeepCopy(),
+				}
+				err = k8sClient.Update(context.TODO(), backup)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should create the verification cluster", func() {
+				Expect(backup.Status.Verification).NotTo(BeNil())
+				Expect(backup.Status.Verification.Phase).To(Equal(fdbv1beta2.BackupVerificationPhaseCreatingCluster))
+				Expect(backup.Status.Verification.ClusterName).To(Equal(backup.VerificationClusterName()))
+				Expect(backup.Status.Verification.StartTime).NotTo(BeNil())
+
+				verificationCluster := &fdbv1beta2.FoundationDBCluster{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, verificationCluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(verificationCluster.Labels).To(HaveKeyWithValue(fdbv1beta2.BackupVerificationLabel, string(backup.UID)))
+			})
+
+			It("should create the backup agents for the verification cluster", func() {
+				deployment := &appsv1.Deployment{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-backup-agents", backup.VerificationClusterName())}, deployment)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment.Labels).To(HaveKeyWithValue(fdbv1beta2.BackupVerificationLabel, string(backup.UID)))
+				Expect(deployment.Labels).NotTo(HaveKey(fdbv1beta2.BackupDeploymentLabel))
+			})
+
+			It("should restore the backup and record the result", func() {
+				verificationCluster := &fdbv1beta2.FoundationDBCluster{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, verificationCluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				By("reconciling the verification cluster")
+				_, err = reconcileCluster(verificationCluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = reconcileBackup(backup)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reloadBackup(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(backup.Status.Verification.Phase).To(Equal(fdbv1beta2.BackupVerificationPhaseRestoring))
+
+				restore := &fdbv1beta2.FoundationDBRestore{}
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, restore)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(restore.Spec.DestinationClusterName).To(Equal(verificationCluster.Name))
+				Expect(restore.BackupURL()).To(Equal(backup.BackupURL()))
+
+				By("completing the restore")
+				_, err = reconcileRestore(restore)
+				Expect(err).NotTo(HaveOccurred())
+				verificationAdminClient, err := mock.NewMockAdminClientUncast(verificationCluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				verificationAdminClient.RestoreState = "completed"
+
+				_, err = reconcileBackup(backup)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = reloadBackup(backup)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(backup.Status.Verification.Phase).To(BeEmpty())
+				Expect(backup.Status.Verification.LastResult).NotTo(BeNil())
+				Expect(backup.Status.Verification.LastResult.Passed).To(BeTrue())
+
+				By("removing the verification resources")
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, verificationCluster)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, restore)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-backup-agents", backup.VerificationClusterName())}, &appsv1.Deployment{})
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+
+			When("the verification doesn't complete in time", func() {
+				It("should record a failed verification and remove the verification resources", func() {
+					startTime := metav1.NewTime(time.Now().Add(-48 * time.Hour))
+					backup.Status.Verification.StartTime = &startTime
+					Expect(k8sClient.Status().Update(context.TODO(), backup)).To(Succeed())
+
+					_, err = reconcileBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = reloadBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(backup.Status.Verification.Phase).To(BeEmpty())
+					Expect(backup.Status.Verification.LastResult).NotTo(BeNil())
+					Expect(backup.Status.Verification.LastResult.Passed).To(BeFalse())
+					Expect(backup.Status.Verification.LastResult.Message).To(Equal("Verification did not complete within 24h0m0s"))
+
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, &fdbv1beta2.FoundationDBCluster{})
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-backup-agents", backup.VerificationClusterName())}, &appsv1.Deployment{})
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+
+			When("a cluster with the name of the verification cluster already exists", func() {
+				BeforeEach(func() {
+					existingCluster := internal.CreateDefaultCluster()
+					existingCluster.Name = backup.VerificationClusterName()
+					Expect(k8sClient.Create(context.TODO(), existingCluster)).To(Succeed())
+				})
+
+				It("should record a failed verification without deleting the cluster", func() {
+					Expect(backup.Status.Verification).NotTo(BeNil())
+					Expect(backup.Status.Verification.Phase).To(BeEmpty())
+					Expect(backup.Status.Verification.LastResult).NotTo(BeNil())
+					Expect(backup.Status.Verification.LastResult.Passed).To(BeFalse())
+					Expect(backup.Status.Verification.LastResult.Message).To(ContainSubstring("doesn't belong to the verification of the backup"))
+
+					existingCluster := &fdbv1beta2.FoundationDBCluster{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, existingCluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(existingCluster.Labels).NotTo(HaveKey(fdbv1beta2.BackupVerificationLabel))
+				})
+			})
+
+			When("the restore is aborted", func() {
+				It("should record a failed verification", func() {
+					verificationCluster := &fdbv1beta2.FoundationDBCluster{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}, verificationCluster)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = reconcileCluster(verificationCluster)
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = reconcileBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+
+					verificationAdminClient, err := mock.NewMockAdminClientUncast(verificationCluster, k8sClient)
+					Expect(err).NotTo(HaveOccurred())
+					verificationAdminClient.RestoreState = "aborted"
+
+					_, err = reconcileBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+					_, err = reloadBackup(backup)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(backup.Status.Verification.Phase).To(BeEmpty())
+					Expect(backup.Status.Verification.LastResult).NotTo(BeNil())
+					Expect(backup.Status.Verification.LastResult.Passed).To(BeFalse())
+				})
+			})
+		})
+
+		When("the backup is lagging behind", func() {
+			BeforeEach(func() {
+				backup.Spec.Alerting = &fdbv1beta2.BackupAlertingConfiguration{
+					LagThresholdSeconds: pointer.Int(60),
+				}
+				err = k8sClient.Update(context.TODO(), backup)
+				Expect(err).NotTo(HaveOccurred())
+
+				liveBackup := adminClient.Backups["default"]
+				liveBackup.LatestRestorableVersion = pointer.Int64(1000)
+				liveBackup.SecondsBehind = 120
+				liveBackup.CurrentSnapshotStartTime = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+				liveBackup.SnapshotProgressPercent = 50
+				liveBackup.Errors = []string{"blob store is not reachable"}
+				adminClient.Backups["default"] = liveBackup
+			})
+
+			It("should report the lag in the status", func() {
+				Expect(*backup.Status.BackupDetails.LatestRestorableVersion).To(BeNumerically("==", 1000))
+				Expect(backup.Status.BackupDetails.SecondsBehind).To(BeNumerically("==", 120))
+				Expect(backup.Status.BackupDetails.SnapshotProgressPercent).To(Equal(50))
+				Expect(backup.Status.BackupDetails.Errors).To(ConsistOf("blob store is not reachable"))
+			})
+
+			It("should set the lagging condition", func() {
+				Expect(meta.IsStatusConditionTrue(backup.Status.Conditions, string(fdbv1beta2.BackupLagging))).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(backup.Status.Conditions, string(fdbv1beta2.BackupSnapshotStale))).To(BeTrue())
+				Expect(meta.IsStatusConditionFalse(backup.Status.Conditions, string(fdbv1beta2.BackupAgentsDown))).To(BeTrue())
+			})
+		})
+
 		When("providing custom parameters", func() {
 			BeforeEach(func() {
 				backup.Spec.CustomParameters = fdbv1beta2.FoundationDBCustomParameters{
@@ -313,4 +577,59 @@ var _ = Describe("backup_controller", func() {
 			})
 		})
 	})
+
+	Describe("starting a file backup", func() {
+		var executor *mockPodCommandExecutor
+		var result *requeue
+
+		BeforeEach(func() {
+			executor = &mockPodCommandExecutor{commands: map[string][]string{}, failAfter: -1}
+			backupReconciler.PodCommandExecutor = executor
+
+			Expect(k8sClient.Create(context.TODO(), cluster)).To(Succeed())
+			backup.Spec.BlobStoreConfiguration = &fdbv1beta2.BlobStoreConfiguration{
+				Backend:         fdbv1beta2.BlobStoreBackendFile,
+				VolumeClaimName: "backup-data",
+			}
+			Expect(k8sClient.Create(context.TODO(), backup)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			backupReconciler.PodCommandExecutor = nil
+		})
+
+		JustBeforeEach(func() {
+			result = startBackup{}.reconcile(context.TODO(), backupReconciler, backup)
+		})
+
+		When("no backup agent is running", func() {
+			It("should wait for a backup agent", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.message).To(Equal("Waiting for a running backup agent to start the file backup"))
+				Expect(executor.commands).To(BeEmpty())
+			})
+		})
+
+		When("a backup agent is running", func() {
+			BeforeEach(func() {
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: backup.Namespace,
+						Name:      "backup-agent-1",
+						Labels:    map[string]string{"foundationdb.org/deployment-name": fmt.Sprintf("%s-backup-agents", backup.Name)},
+					},
+				}
+				Expect(k8sClient.Create(context.TODO(), pod)).To(Succeed())
+			})
+
+			It("should start the backup in the backup agent", func() {
+				Expect(result).To(BeNil())
+				Expect(executor.commands).To(HaveKeyWithValue("backup-agent-1", []string{"fdbbackup", "start", "-d", "file:///var/fdb-backups/" + backup.Name, "-s", "864000", "-z"}))
+
+				status, err := adminClient.GetBackupStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Status.Running).To(BeFalse())
+			})
+		})
+	})
 })
@@ -29,6 +29,8 @@ import (
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/operations"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
@@ -39,6 +41,11 @@ import (
 // processes.
 type bounceProcesses struct{}
 
+// hasPendingDisruption returns true if processes must be restarted.
+func (bounceProcesses) hasPendingDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool {
+	return cluster.Status.Generations.NeedsBounce > 0
+}
+
 // reconcile runs the reconciler's work.
 func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	if !pointer.BoolDeref(cluster.Spec.AutomationOptions.KillProcesses, true) {
@@ -46,7 +53,7 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 	}
 
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "bounceProcesses")
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -79,12 +86,14 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		}
 	}
 
-	addresses, req := getProcessesReadyForRestart(logger, cluster, addressMap, upgradedProcesses)
+	addresses, req := getProcessesReadyForRestart(logger, cluster, addressMap, upgradedProcesses, r.now())
 	if req != nil {
 		return req
 	}
 
 	if len(addresses) == 0 {
+		// Remove the bounces from the operation queue, as no process needs to be restarted anymore.
+		operations.Plan(cluster, fdbv1beta2.ProcessGroupOperationBounce, nil, r.now())
 		return nil
 	}
 
@@ -155,9 +164,25 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		return nil
 	}
 
+	// Upgrades require that all processes are restarted at the same time, so they are not planned through the
+	// operation queue.
+	if !upgrading {
+		var blocked int
+		addresses, blocked = planBounces(cluster, addressMap, addresses, r.now())
+		if len(addresses) == 0 {
+			logger.Info("Waiting for the operation queue to bounce processes", "blocked", blocked)
+			return &requeue{message: "Waiting for the operation queue to bounce processes", delay: 15 * time.Second, delayedRequeue: true}
+		}
+	}
+
 	logger.Info("Bouncing processes", "addresses", addresses, "upgrading", upgrading)
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "BouncingProcesses", fmt.Sprintf("Bouncing processes: %v", addresses))
 	err = adminClient.KillProcesses(addresses)
+	reason := "Processes have an incorrect command line"
+	if upgrading {
+		reason = fmt.Sprintf("Processes are upgraded to version %s", cluster.Spec.Version)
+	}
+	r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionKillProcesses, audit.GetProcessAddressTargets(addresses), reason, err)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -175,7 +200,7 @@ func (bounceProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReco
 
 // getProcessesReadyForRestart returns a slice of process addresses that can be restarted. If addresses are missing or not all processes
 // have the latest configuration this method will return a requeue struct with more details.
-func getProcessesReadyForRestart(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, addressMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.ProcessAddress, upgradedProcesses int) ([]fdbv1beta2.ProcessAddress, *requeue) {
+func getProcessesReadyForRestart(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, addressMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.ProcessAddress, upgradedProcesses int, now time.Time) ([]fdbv1beta2.ProcessAddress, *requeue) {
 	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(cluster.Status.ProcessGroups))
 	allSynced := true
 	var missingAddress []fdbv1beta2.ProcessGroupID
@@ -191,7 +216,7 @@ func getProcessesReadyForRestart(logger logr.Logger, cluster *fdbv1beta2.Foundat
 		// This is required since the update status will not update the SidecarUnreachable setting if a process is
 		// missing in the status.
 		if missingTime := processGroup.GetConditionTime(fdbv1beta2.MissingProcesses); missingTime != nil {
-			if time.Unix(*missingTime, 0).Add(cluster.GetIgnoreMissingProcessesSeconds()).Before(time.Now()) {
+			if time.Unix(*missingTime, 0).Add(cluster.GetIgnoreMissingProcessesSeconds()).Before(now) {
 				logger.Info("ignore process group with missing process", "processGroupID", processGroup.ProcessGroupID)
 				missingProcesses++
 				continue
@@ -325,3 +350,51 @@ func filterIgnoredProcessGroups(cluster *fdbv1beta2.FoundationDBCluster, address
 
 	return filteredAddresses, removedAddresses
 }
+
+// planBounces queues the bounces in the operation queue and returns the
+// addresses that are allowed to be restarted now together with the number of
+// process groups that have to wait. Addresses that don't belong to a known
+// process group are always allowed.
+func planBounces(cluster *fdbv1beta2.FoundationDBCluster, addressMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.ProcessAddress, addresses []fdbv1beta2.ProcessAddress, now time.Time) ([]fdbv1beta2.ProcessAddress, int) {
+	processGroupByAddress := make(map[string]fdbv1beta2.ProcessGroupID)
+	for processGroupID, processAddresses := range addressMap {
+		for _, address := range processAddresses {
+			processGroupByAddress[address.String()] = processGroupID
+		}
+	}
+
+	requested := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None)
+	requests := make([]operations.Request, 0, len(addresses))
+	for _, address := range addresses {
+		processGroupID, ok := processGroupByAddress[address.String()]
+		if !ok {
+			continue
+		}
+
+		if _, ok := requested[processGroupID]; ok {
+			continue
+		}
+
+		requested[processGroupID] = fdbv1beta2.None{}
+		requests = append(requests, operations.Request{ProcessGroupID: processGroupID, Reason: "processes need to be restarted"})
+	}
+
+	allowed := operations.Plan(cluster, fdbv1beta2.ProcessGroupOperationBounce, requests, now)
+	if len(allowed) == len(requests) {
+		return addresses, 0
+	}
+
+	allowedAddresses := make([]fdbv1beta2.ProcessAddress, 0, len(addresses))
+	for _, address := range addresses {
+		processGroupID, ok := processGroupByAddress[address.String()]
+		if ok {
+			if _, isAllowed := allowed[processGroupID]; !isAllowed {
+				continue
+			}
+		}
+
+		allowedAddresses = append(allowedAddresses, address)
+	}
+
+	return allowedAddresses, len(requests) - len(allowed)
+}
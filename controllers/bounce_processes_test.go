@@ -25,8 +25,11 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"github.com/go-logr/logr"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	. "github.com/onsi/ginkgo/v2"
@@ -93,6 +96,68 @@ var _ = Describe("bounceProcesses", func() {
 			}
 			Expect(adminClient.KilledAddresses).To(Equal(addresses))
 		})
+
+		When("the audit log is enabled", func() {
+			BeforeEach(func() {
+				clusterReconciler.AuditRecorder = audit.NewRecorder(logr.Discard(), "test-operator", audit.NewKubernetesSink(k8sClient))
+			})
+
+			AfterEach(func() {
+				clusterReconciler.AuditRecorder = nil
+			})
+
+			It("should record the killed processes", func() {
+				events := &fdbv1beta2.FoundationDBAuditEventList{}
+				Expect(k8sClient.List(context.TODO(), events, client.InNamespace(cluster.Namespace), client.MatchingLabels{fdbv1beta2.FDBClusterLabel: cluster.Name})).NotTo(HaveOccurred())
+				Expect(events.Items).To(HaveLen(1))
+
+				entry := events.Items[0].Spec
+				Expect(entry.Action).To(Equal(fdbv1beta2.AuditActionKillProcesses))
+				Expect(entry.Outcome).To(Equal(fdbv1beta2.AuditOutcomeSucceeded))
+				Expect(entry.OperatorInstance).To(Equal("test-operator"))
+				Expect(entry.Reason).To(Equal("Processes have an incorrect command line"))
+
+				var targets []string
+				for _, processGroupID := range []fdbv1beta2.ProcessGroupID{"storage-1", "storage-2"} {
+					for _, address := range fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID).Addresses {
+						targets = append(targets, fmt.Sprintf("%s:4501", address))
+					}
+				}
+				Expect(entry.Targets).To(ConsistOf(targets))
+			})
+		})
+	})
+
+	Context("with incorrect processes and the operation queue enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.OperationQueue.Enabled = pointer.Bool(true)
+
+			processGroup := cluster.Status.ProcessGroups[len(cluster.Status.ProcessGroups)-4]
+			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+			processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, true, nil, "")
+
+			processGroup = cluster.Status.ProcessGroups[len(cluster.Status.ProcessGroups)-3]
+			Expect(processGroup.ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-2")))
+			processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, true, nil, "")
+		})
+
+		It("should only kill the processes in one fault domain", func() {
+			Expect(requeue).To(BeNil())
+
+			addresses := make(map[string]fdbv1beta2.None, 1)
+			processGroupAddresses := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1").Addresses
+			for _, address := range processGroupAddresses {
+				addresses[fmt.Sprintf("%s:4501", address)] = fdbv1beta2.None{}
+			}
+			Expect(adminClient.KilledAddresses).To(Equal(addresses))
+		})
+
+		It("should queue the bounce of the other process group", func() {
+			Expect(cluster.Status.Operations).To(HaveLen(2))
+			Expect(cluster.Status.Operations[1].ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-2")))
+			Expect(cluster.Status.Operations[1].Type).To(Equal(fdbv1beta2.ProcessGroupOperationBounce))
+			Expect(cluster.Status.Operations[1].Allowed).To(BeFalse())
+		})
 	})
 
 	Context("with excluded and incorrect processes", func() {
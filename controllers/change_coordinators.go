@@ -23,11 +23,16 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/locality"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
 	"github.com/go-logr/logr"
 
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 )
@@ -36,6 +41,18 @@ import (
 // coordinators.
 type changeCoordinators struct{}
 
+// hasPendingDisruption returns true if the cluster needs new coordinators.
+func (changeCoordinators) hasPendingDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool {
+	return cluster.Status.NeedsNewCoordinators
+}
+
+// hasUrgentDisruption returns true if any of the current coordinators is
+// unhealthy. Replacing those coordinators restores the fault tolerance of the
+// cluster.
+func (changeCoordinators) hasUrgentDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool {
+	return cluster.Status.HasUnhealthyCoordinators
+}
+
 // reconcile runs the reconciler's work.
 func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "changeCoordinators")
@@ -44,7 +61,7 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 		return nil
 	}
 
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -66,11 +83,7 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 		}
 	}
 
-	coordinatorStatus := make(map[string]bool, len(status.Client.Coordinators.Coordinators))
-	for _, coordinator := range status.Client.Coordinators.Coordinators {
-		coordinatorStatus[coordinator.Address.String()] = false
-	}
-
+	coordinatorStatus := locality.GetCoordinatorStatus(status)
 	hasValidCoordinators, allAddressesValid, err := locality.CheckCoordinatorValidity(logger, cluster, status, coordinatorStatus)
 	if err != nil {
 		return &requeue{curError: err}
@@ -86,26 +99,56 @@ func (c changeCoordinators) reconcile(ctx context.Context, r *FoundationDBCluste
 		return nil
 	}
 
+	// During a transition between TLS and non-TLS the coordinators can only be
+	// changed once all processes listen on the new address.
+	if cluster.Status.TLS.Transition == fdbv1beta2.TLSTransitionAddListeners {
+		logger.Info("Deferring coordinator change until all processes listen on the new address", "transition", cluster.Status.TLS.Transition)
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "DeferringCoordinatorChange", "Deferring coordinator change until all processes listen on the TLS and the non-TLS address")
+		return &requeue{message: "TLS transition is waiting for the processes to listen on the new address", delayedRequeue: true}
+	}
+
 	hasLock, err := r.takeLock(cluster, "changing coordinators")
 	if !hasLock {
 		return &requeue{curError: err}
 	}
 
-	logger.Info("Changing coordinators")
-	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ChangingCoordinators", "Choosing new coordinators")
+	var coordinatorAddresses []fdbv1beta2.ProcessAddress
+	if cluster.UseDNSInClusterFile() {
+		coordinatorAddresses = getDNSMigrationAddresses(logger, cluster, status, coordinatorStatus)
+	}
 
-	coordinators, err := selectCoordinators(logger, cluster, status)
-	if err != nil {
-		return &requeue{curError: err}
+	reason := "Coordinators are not valid"
+	if len(coordinatorAddresses) > 0 {
+		reason = "Coordinators are migrated to DNS names"
+		logger.Info("Migrating coordinators to DNS names")
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "MigratingCoordinatorsToDNS", "Changing the coordinators to use DNS names")
+	} else {
+		logger.Info("Changing coordinators")
+		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ChangingCoordinators", "Choosing new coordinators")
+
+		coordinators, err := selectCoordinators(logger, cluster, status)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		coordinatorAddresses = make([]fdbv1beta2.ProcessAddress, len(coordinators))
+		for index, process := range coordinators {
+			coordinatorAddresses[index] = getCoordinatorAddress(cluster, process)
+		}
 	}
 
-	coordinatorAddresses := make([]fdbv1beta2.ProcessAddress, len(coordinators))
-	for index, process := range coordinators {
-		coordinatorAddresses[index] = getCoordinatorAddress(cluster, process)
+	if cluster.UseDNSInClusterFile() {
+		err = checkCoordinatorDNSNames(ctx, r, cluster, coordinatorAddresses)
+		if err != nil {
+			logger.Info("Deferring coordinator change", "error", err.Error())
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "DeferringCoordinatorChange", fmt.Sprintf("Deferring coordinator change until the DNS names of the coordinators resolve: %s", err.Error()))
+			return &requeue{message: fmt.Sprintf("DNS names of the coordinators don't resolve: %s", err.Error()), delayedRequeue: true}
+		}
 	}
 
 	logger.Info("Final coordinators candidates", "coordinators", coordinatorAddresses)
 	connectionString, err := adminClient.ChangeCoordinators(coordinatorAddresses)
+	r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionChangeCoordinators, audit.GetProcessAddressTargets(coordinatorAddresses), reason, err)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -199,3 +242,82 @@ func getCoordinatorAddress(cluster *fdbv1beta2.FoundationDBCluster, locality loc
 	}
 	return address
 }
+
+// getDNSMigrationAddresses returns the DNS addresses of the current
+// coordinators, if the coordinators only need to be changed to use DNS names.
+// Keeping the same processes as coordinators prevents a coordinator change to
+// new processes. This returns an empty list if not all current coordinators
+// have a DNS name, or if the current coordinators would not be valid.
+func getDNSMigrationAddresses(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, coordinatorStatus map[string]bool) []fdbv1beta2.ProcessAddress {
+	processes := make(map[string]locality.Info, len(status.Cluster.Processes))
+	for _, process := range status.Cluster.Processes {
+		processLocality, err := locality.InfoForProcess(process, cluster.Spec.MainContainer.EnableTLS)
+		if err != nil {
+			continue
+		}
+
+		processes[processLocality.Address.String()] = processLocality
+	}
+
+	addresses := make([]fdbv1beta2.ProcessAddress, 0, len(coordinatorStatus))
+	migratedStatus := make(map[string]bool, len(coordinatorStatus))
+	for coordinator := range coordinatorStatus {
+		processLocality, ok := processes[coordinator]
+		if !ok {
+			return nil
+		}
+
+		address := getCoordinatorAddress(cluster, processLocality)
+		if address.StringAddress == "" {
+			return nil
+		}
+
+		addresses = append(addresses, address)
+		migratedStatus[address.String()] = false
+	}
+
+	hasValidCoordinators, allAddressesValid, err := locality.CheckCoordinatorValidity(logger, cluster, status, migratedStatus)
+	if err != nil || !hasValidCoordinators || !allAddressesValid {
+		return nil
+	}
+
+	// Sort the addresses to get a deterministic connection string.
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i].String() < addresses[j].String()
+	})
+
+	return addresses
+}
+
+// checkCoordinatorDNSNames checks that the DNS names of the coordinators
+// resolve through the headless service of the cluster.
+func checkCoordinatorDNSNames(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, coordinators []fdbv1beta2.ProcessAddress) error {
+	var service *corev1.Service
+	for _, coordinator := range coordinators {
+		if coordinator.StringAddress == "" {
+			continue
+		}
+
+		if service == nil {
+			service = &corev1.Service{}
+			err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Name}, service)
+			if err != nil {
+				return err
+			}
+		}
+
+		podName := strings.Split(coordinator.StringAddress, ".")[0]
+		pod := &corev1.Pod{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: podName}, pod)
+		if err != nil {
+			return err
+		}
+
+		err = internal.CheckPodDNSName(cluster, service, pod, coordinator.StringAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
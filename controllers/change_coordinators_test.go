@@ -655,7 +655,11 @@ var _ = Describe("Change coordinators", func() {
 			})
 
 			Context("when the pods have DNS names", func() {
+				var createService bool
+
 				BeforeEach(func() {
+					createService = true
+
 					pods := &corev1.PodList{}
 					err = k8sClient.List(context.TODO(), pods)
 					Expect(err).NotTo(HaveOccurred())
@@ -664,11 +668,24 @@ var _ = Describe("Change coordinators", func() {
 						container := pod.Spec.Containers[1]
 						container.Env = append(container.Env, corev1.EnvVar{Name: "FDB_DNS_NAME", Value: internal.GetPodDNSName(cluster, pod.Name)})
 						pod.Spec.Containers[1] = container
+						pod.Spec.Hostname = pod.Name
+						pod.Spec.Subdomain = cluster.Name
 						err = k8sClient.Update(context.TODO(), &pod)
 						Expect(err).NotTo(HaveOccurred())
 					}
 				})
 
+				JustBeforeEach(func() {
+					// The headless service must be created before the reconciliation
+					// of the outer JustBeforeEach, so we run the reconciliation again.
+					if !createService {
+						return
+					}
+
+					Expect(k8sClient.Create(context.TODO(), internal.GetHeadlessService(cluster))).NotTo(HaveOccurred())
+					requeue = changeCoordinators{}.reconcile(context.TODO(), clusterReconciler, cluster)
+				})
+
 				It("should not requeue", func() {
 					Expect(requeue).To(BeNil())
 				})
@@ -677,6 +694,82 @@ var _ = Describe("Change coordinators", func() {
 					Expect(cluster.Status.ConnectionString).NotTo(Equal(originalConnectionString))
 					Expect(cluster.Status.ConnectionString).To(ContainSubstring("my-ns.svc.cluster.local"))
 				})
+
+				It("should keep the same processes as coordinators", func() {
+					originalCoordinators, err := fdbv1beta2.ParseConnectionString(originalConnectionString)
+					Expect(err).NotTo(HaveOccurred())
+					newCoordinators, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+					Expect(err).NotTo(HaveOccurred())
+
+					status, err := adminClient.GetStatus()
+					Expect(err).NotTo(HaveOccurred())
+
+					expectedNames := make([]string, 0, len(originalCoordinators.Coordinators))
+					for _, process := range status.Cluster.Processes {
+						for _, coordinator := range originalCoordinators.Coordinators {
+							if process.Address.String() == coordinator {
+								expectedNames = append(expectedNames, process.Locality[fdbv1beta2.FDBLocalityDNSNameKey])
+							}
+						}
+					}
+
+					Expect(expectedNames).To(HaveLen(len(originalCoordinators.Coordinators)))
+					newNames := make([]string, 0, len(newCoordinators.Coordinators))
+					for _, coordinator := range newCoordinators.Coordinators {
+						address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+						Expect(err).NotTo(HaveOccurred())
+						newNames = append(newNames, address.StringAddress)
+					}
+					Expect(newNames).To(ConsistOf(expectedNames))
+				})
+
+				When("the headless service doesn't exist", func() {
+					BeforeEach(func() {
+						createService = false
+					})
+
+					It("should requeue", func() {
+						Expect(requeue).NotTo(BeNil())
+						Expect(requeue.delayedRequeue).To(BeTrue())
+						Expect(requeue.message).To(HavePrefix("DNS names of the coordinators don't resolve"))
+					})
+
+					It("should not change the cluster file", func() {
+						Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+					})
+				})
+			})
+		})
+
+		When("enabling TLS while not all processes listen on a TLS address", func() {
+			BeforeEach(func() {
+				cluster.Spec.MainContainer.EnableTLS = true
+			})
+
+			It("should not requeue", func() {
+				Expect(requeue).To(BeNil())
+			})
+
+			It("should not change the cluster file", func() {
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			})
+		})
+
+		When("the TLS transition waits for the processes to listen on the new address", func() {
+			BeforeEach(func() {
+				cluster.Spec.MainContainer.EnableTLS = true
+				cluster.Status.RequiredAddresses = fdbv1beta2.RequiredAddressSet{TLS: true, NonTLS: true}
+				cluster.Status.TLS.Transition = fdbv1beta2.TLSTransitionAddListeners
+			})
+
+			It("should requeue", func() {
+				Expect(requeue).NotTo(BeNil())
+				Expect(requeue.delayedRequeue).To(BeTrue())
+				Expect(requeue.message).To(ContainSubstring("TLS transition"))
+			})
+
+			It("should not change the cluster file", func() {
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
 			})
 		})
 	})
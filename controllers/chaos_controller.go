@@ -0,0 +1,267 @@
+/*
+ * chaos_controller.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// chaosStatusRefreshInterval defines how often a running chaos experiment
+// checks the availability of the cluster.
+const chaosStatusRefreshInterval = 15 * time.Second
+
+// FoundationDBChaosReconciler reconciles a FoundationDBChaos object
+type FoundationDBChaosReconciler struct {
+	client.Client
+	Recorder               record.EventRecorder
+	Log                    logr.Logger
+	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
+	ServerSideApply        bool
+	AuditRecorder          *audit.Recorder
+	Sharder                *sharding.Sharder
+	PodCommandExecutor     internal.PodCommandExecutor
+}
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbchaos,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbchaos/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
+// +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs the reconciliation logic.
+func (r *FoundationDBChaosReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	chaos := &fdbv1beta2.FoundationDBChaos{}
+
+	err := r.Get(ctx, request.NamespacedName, chaos)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Object not found, return. The faults of a running experiment
+			// are reverted before the finalizer is removed.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	chaosLog := log.WithValues("namespace", chaos.Namespace, "chaos", chaos.Name)
+
+	if !r.Sharder.OwnsCluster(chaos.Namespace, chaos.Spec.ClusterName) {
+		chaosLog.V(1).Info("Skipping chaos experiment of cluster owned by another shard", "cluster", chaos.Spec.ClusterName)
+		return ctrl.Result{}, nil
+	}
+
+	acquired, err := r.Sharder.AcquireCluster(ctx, chaos.Namespace, chaos.Spec.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !acquired {
+		chaosLog.Info("Waiting for the previous shard to finish the reconciliation of the cluster", "cluster", chaos.Spec.ClusterName)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	defer r.Sharder.ReleaseCluster(ctx, chaos.Namespace, chaos.Spec.ClusterName)
+
+	subReconcilers := []chaosSubReconciler{
+		startChaos{},
+		injectChaos{},
+		revertChaos{},
+	}
+
+	if !chaos.ObjectMeta.DeletionTimestamp.IsZero() {
+		subReconcilers = []chaosSubReconciler{
+			revertChaos{},
+		}
+	}
+
+	for _, subReconciler := range subReconcilers {
+		// The cluster might have been assigned to another shard while the
+		// previous sub-reconcilers were running.
+		if !r.Sharder.HoldsCluster(chaos.Namespace, chaos.Spec.ClusterName) {
+			chaosLog.Info("Stopping reconciliation of cluster owned by another shard", "subReconciler", fmt.Sprintf("%T", subReconciler), "cluster", chaos.Spec.ClusterName)
+			return ctrl.Result{}, nil
+		}
+
+		requeue := subReconciler.reconcile(ctx, r, chaos)
+		if requeue == nil {
+			continue
+		}
+
+		return processRequeue(requeue, subReconciler, chaos, r.Recorder, chaosLog)
+	}
+
+	chaosLog.Info("Reconciliation complete", "phase", chaos.GetPhase())
+
+	if chaos.IsFinished() || !chaos.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{RequeueAfter: getChaosRequeueDelay(chaos, time.Now())}, nil
+}
+
+// getChaosRequeueDelay returns the time until the experiment needs to be
+// reconciled again, to start it, to inject the next fault or to end it.
+func getChaosRequeueDelay(chaos *fdbv1beta2.FoundationDBChaos, now time.Time) time.Duration {
+	if chaos.GetPhase() == fdbv1beta2.ChaosPhasePending {
+		if chaos.Spec.StartTime != nil && now.Before(chaos.Spec.StartTime.Time) {
+			return chaos.Spec.StartTime.Sub(now)
+		}
+
+		return chaosStatusRefreshInterval
+	}
+
+	delay := chaosStatusRefreshInterval
+
+	endTime := chaos.GetEndTime()
+	if endTime != nil && endTime.Sub(now) < delay {
+		delay = endTime.Sub(now)
+	}
+
+	if chaos.Spec.Action == fdbv1beta2.ChaosActionKillProcesses && chaos.Status.LastInjectionTime != nil {
+		nextInjection := chaos.Status.LastInjectionTime.Add(chaos.GetInterval())
+		if nextInjection.Sub(now) < delay {
+			delay = nextInjection.Sub(now)
+		}
+	}
+
+	if delay < time.Second {
+		return time.Second
+	}
+
+	return delay
+}
+
+// getDatabaseClientProvider gets the client provider for a reconciler.
+func (r *FoundationDBChaosReconciler) getDatabaseClientProvider() fdbadminclient.DatabaseClientProvider {
+	if r.DatabaseClientProvider != nil {
+		return r.DatabaseClientProvider
+	}
+	panic("Chaos reconciler does not have a DatabaseClientProvider defined")
+}
+
+// adminClientForChaos provides the cluster of a chaos experiment, together
+// with an admin client for it.
+func (r *FoundationDBChaosReconciler) adminClientForChaos(ctx context.Context, chaos *fdbv1beta2.FoundationDBChaos) (*fdbv1beta2.FoundationDBCluster, fdbadminclient.AdminClient, error) {
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: chaos.ObjectMeta.Namespace, Name: chaos.Spec.ClusterName}, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cluster, adminClient, nil
+}
+
+// SetupWithManager prepares a reconciler for use.
+func (r *FoundationDBChaosReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector) error {
+	labelSelectorPredicate, err := predicate.LabelSelectorPredicate(selector)
+	if err != nil {
+		return err
+	}
+
+	managedBy := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentReconciles},
+		).
+		For(&fdbv1beta2.FoundationDBChaos{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		Owns(&corev1.Pod{}).
+		// Only react on generation changes or annotation changes and only watch
+		// resources with the provided label selector.
+		WithEventFilter(
+			predicate.And(
+				labelSelectorPredicate,
+				predicate.Or(
+					predicate.GenerationChangedPredicate{},
+					predicate.AnnotationChangedPredicate{},
+				),
+			))
+
+	if r.Sharder != nil {
+		managedBy.Watches(r.Sharder.GetSource(&fdbv1beta2.FoundationDBChaosList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	return managedBy.Complete(r)
+}
+
+// chaosSubReconciler describes a class that does part of the work of
+// reconciliation for a chaos experiment.
+type chaosSubReconciler interface {
+	/**
+	reconcile runs the reconciler's work.
+
+	If reconciliation can continue, this should return nil.
+
+	If reconciliation encounters an error, this should return a requeue object
+	with an `Error` field.
+
+	If reconciliation cannot proceed, this should return a requeue object with a
+	`Message` field.
+	*/
+	reconcile(ctx context.Context, r *FoundationDBChaosReconciler, chaos *fdbv1beta2.FoundationDBChaos) *requeue
+}
+
+// updateOrApply updates the status either with server-side apply or if disabled with the normal update call.
+func (r *FoundationDBChaosReconciler) updateOrApply(ctx context.Context, chaos *fdbv1beta2.FoundationDBChaos) error {
+	if r.ServerSideApply {
+		// We have to set the TypeMeta otherwise the Patch command will fail.
+		patch := &fdbv1beta2.FoundationDBChaos{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       chaos.Kind,
+				APIVersion: chaos.APIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      chaos.Name,
+				Namespace: chaos.Namespace,
+			},
+			Status: chaos.Status,
+		}
+
+		return r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner("fdb-operator"), client.ForceOwnership)
+	}
+
+	return r.Status().Update(ctx, chaos)
+}
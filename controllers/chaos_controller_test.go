@@ -0,0 +1,419 @@
+/*
+ * chaos_controller_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+func reloadChaos(chaos *fdbv1beta2.FoundationDBChaos) error {
+	return k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(chaos), chaos)
+}
+
+func getChaosEvents(chaos *fdbv1beta2.FoundationDBChaos) []string {
+	events := &corev1.EventList{}
+	Expect(k8sClient.List(context.TODO(), events, client.InNamespace(chaos.Namespace))).NotTo(HaveOccurred())
+
+	var reasons []string
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind == "FoundationDBChaos" || event.InvolvedObject.Name == chaos.Name {
+			reasons = append(reasons, event.Reason)
+		}
+	}
+
+	return reasons
+}
+
+func listChaosNetworkPolicies(chaos *fdbv1beta2.FoundationDBChaos) []networkingv1.NetworkPolicy {
+	policies := &networkingv1.NetworkPolicyList{}
+	Expect(k8sClient.List(context.TODO(), policies, client.InNamespace(chaos.Namespace), client.MatchingLabels{fdbv1beta2.ChaosLabel: chaos.Name})).NotTo(HaveOccurred())
+	return policies.Items
+}
+
+func listChaosPods(chaos *fdbv1beta2.FoundationDBChaos) []corev1.Pod {
+	pods := &corev1.PodList{}
+	Expect(k8sClient.List(context.TODO(), pods, client.InNamespace(chaos.Namespace), client.MatchingLabels{fdbv1beta2.ChaosLabel: chaos.Name})).NotTo(HaveOccurred())
+	return pods.Items
+}
+
+// endChaos moves the start of a running experiment into the past, so the
+// next reconciliation ends the experiment.
+func endChaos(chaos *fdbv1beta2.FoundationDBChaos) {
+	startTime := metav1.NewTime(time.Now().Add(-chaos.GetDuration()))
+	chaos.Status.StartTime = &startTime
+	Expect(k8sClient.Status().Update(context.TODO(), chaos)).NotTo(HaveOccurred())
+}
+
+var _ = Describe("chaos_controller", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var chaos *fdbv1beta2.FoundationDBChaos
+	var adminClient *mock.AdminClient
+	var executor *mockPodCommandExecutor
+	var err error
+
+	BeforeEach(func() {
+		executor = &mockPodCommandExecutor{commands: map[string][]string{}, failAfter: -1}
+		chaosReconciler.PodCommandExecutor = executor
+
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		chaos = &fdbv1beta2.FoundationDBChaos{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sample-chaos",
+				Namespace: cluster.Namespace,
+			},
+			Spec: fdbv1beta2.FoundationDBChaosSpec{
+				ClusterName:  cluster.Name,
+				Action:       fdbv1beta2.ChaosActionKillProcesses,
+				ProcessClass: fdbv1beta2.ProcessClassStorage,
+				Count:        pointer.Int(2),
+			},
+		}
+	})
+
+	AfterEach(func() {
+		chaosReconciler.PodCommandExecutor = nil
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), chaos)).NotTo(HaveOccurred())
+
+		_, err = reconcileChaos(chaos)
+		Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+	})
+
+	When("killing processes", func() {
+		It("should start the experiment", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chaos.Status.Phase).To(Equal(fdbv1beta2.ChaosPhaseRunning))
+			Expect(chaos.Status.StartTime).NotTo(BeNil())
+			Expect(chaos.Status.LastInjectionTime).NotTo(BeNil())
+			Expect(chaos.Status.Report.Injections).To(Equal(1))
+			Expect(chaos.Status.Report.MinimumFaultTolerance).To(Equal(pointer.Int(1)))
+			Expect(chaos.Finalizers).To(ContainElement(fdbv1beta2.ChaosFinalizer))
+			Expect(getChaosEvents(chaos)).To(ContainElements("ChaosStarted", "ChaosInjected"))
+		})
+
+		It("should kill the processes of the targeted process groups", func() {
+			Expect(chaos.Status.Targets).To(HaveLen(2))
+			for _, target := range chaos.Status.Targets {
+				Expect(string(target)).To(HavePrefix(string(fdbv1beta2.ProcessClassStorage)))
+			}
+
+			Expect(adminClient.KilledAddresses).To(HaveLen(2))
+		})
+
+		When("the interval has not passed", func() {
+			It("should not kill more processes", func() {
+				_, err = reconcileChaos(chaos)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+				Expect(chaos.Status.Report.Injections).To(Equal(1))
+			})
+		})
+
+		When("the interval has passed", func() {
+			It("should kill more processes", func() {
+				lastInjection := metav1.NewTime(time.Now().Add(-chaos.GetInterval()))
+				chaos.Status.LastInjectionTime = &lastInjection
+				Expect(k8sClient.Status().Update(context.TODO(), chaos)).NotTo(HaveOccurred())
+
+				_, err = reconcileChaos(chaos)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+				Expect(chaos.Status.Report.Injections).To(Equal(2))
+			})
+
+			It("should skip the injection if the cluster lost fault tolerance", func() {
+				lastInjection := metav1.NewTime(time.Now().Add(-chaos.GetInterval()))
+				chaos.Status.LastInjectionTime = &lastInjection
+				Expect(k8sClient.Status().Update(context.TODO(), chaos)).NotTo(HaveOccurred())
+				adminClient.MaxZoneFailuresWithoutLosingAvailability = pointer.Int(0)
+
+				_, err = reconcileChaos(chaos)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+				Expect(chaos.Status.Report.Injections).To(Equal(1))
+				Expect(chaos.Status.Report.SkippedInjections).To(Equal(1))
+				Expect(chaos.Status.Report.MinimumFaultTolerance).To(Equal(pointer.Int(0)))
+				Expect(getChaosEvents(chaos)).To(ContainElement("ChaosInjectionSkipped"))
+			})
+		})
+
+		When("the duration has passed", func() {
+			It("should complete the experiment", func() {
+				endChaos(chaos)
+
+				_, err = reconcileChaos(chaos)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+				Expect(chaos.Status.Phase).To(Equal(fdbv1beta2.ChaosPhaseCompleted))
+				Expect(chaos.Status.EndTime).NotTo(BeNil())
+				Expect(chaos.Status.Targets).To(BeEmpty())
+				Expect(chaos.Status.Report.Message).To(Equal("Completed after 1 injections"))
+				Expect(chaos.Finalizers).NotTo(ContainElement(fdbv1beta2.ChaosFinalizer))
+				Expect(getChaosEvents(chaos)).To(ContainElement("ChaosCompleted"))
+			})
+		})
+
+		When("the database becomes unavailable", func() {
+			It("should abort the experiment", func() {
+				adminClient.MockAvailability(false)
+
+				_, err = reconcileChaos(chaos)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+				Expect(chaos.Status.Phase).To(Equal(fdbv1beta2.ChaosPhaseAborted))
+				Expect(chaos.Status.EndTime).NotTo(BeNil())
+				Expect(chaos.Status.Report.LostAvailability).To(BeTrue())
+				Expect(getChaosEvents(chaos)).To(ContainElement("ChaosAborted"))
+			})
+		})
+	})
+
+	When("the start time is in the future", func() {
+		BeforeEach(func() {
+			startTime := metav1.NewTime(time.Now().Add(time.Hour))
+			chaos.Spec.StartTime = &startTime
+		})
+
+		It("should not start the experiment", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chaos.Status.Phase).To(BeEmpty())
+			Expect(adminClient.KilledAddresses).To(BeEmpty())
+		})
+	})
+
+	When("the cluster doesn't have the desired fault tolerance", func() {
+		BeforeEach(func() {
+			adminClient.MaxZoneFailuresWithoutLosingAvailability = pointer.Int(0)
+		})
+
+		It("should not start the experiment", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chaos.Status.Phase).To(BeEmpty())
+			Expect(adminClient.KilledAddresses).To(BeEmpty())
+		})
+	})
+
+	When("partitioning a zone", func() {
+		BeforeEach(func() {
+			chaos.Spec.Action = fdbv1beta2.ChaosActionPartitionZone
+			// The mock admin client uses the pod name as zone.
+			chaos.Spec.ZoneID = cluster.Name + "-storage-1"
+		})
+
+		It("should create a network policy for the zone", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chaos.Status.Targets).To(Equal([]fdbv1beta2.ProcessGroupID{"storage-1"}))
+
+			policies := listChaosNetworkPolicies(chaos)
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Spec.PodSelector.MatchExpressions[0].Values).To(Equal([]string{"storage-1"}))
+			Expect(policies[0].Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+		})
+
+		It("should remove the network policy when the experiment ends", func() {
+			endChaos(chaos)
+
+			_, err = reconcileChaos(chaos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadChaos(chaos)).NotTo(HaveOccurred())
+			Expect(chaos.Status.Phase).To(Equal(fdbv1beta2.ChaosPhaseCompleted))
+			Expect(listChaosNetworkPolicies(chaos)).To(BeEmpty())
+		})
+
+		When("the zone doesn't exist", func() {
+			BeforeEach(func() {
+				chaos.Spec.ZoneID = "missing"
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+				Expect(listChaosNetworkPolicies(chaos)).To(BeEmpty())
+			})
+		})
+	})
+
+	When("stalling the sidecars", func() {
+		BeforeEach(func() {
+			chaos.Spec.Action = fdbv1beta2.ChaosActionStallSidecars
+		})
+
+		It("should create a network policy for the targets", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chaos.Status.Targets).To(HaveLen(2))
+
+			policies := listChaosNetworkPolicies(chaos)
+			Expect(policies).To(HaveLen(1))
+			Expect(policies[0].Name).To(Equal("sample-chaos-1"))
+			Expect(policies[0].Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+			Expect(policies[0].Spec.Ingress).To(HaveLen(1))
+		})
+
+		When("an earlier attempt of the injection left a network policy behind", func() {
+			BeforeEach(func() {
+				stalePolicy, err := internal.GetChaosNetworkPolicy(chaos, cluster, []fdbv1beta2.ProcessGroupID{"missing-1", "missing-2"}, 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Create(context.TODO(), stalePolicy)).NotTo(HaveOccurred())
+			})
+
+			It("should replace the network policy with one for the reported targets", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(chaos.Status.Report.Injections).To(Equal(1))
+
+				targets := make([]string, 0, len(chaos.Status.Targets))
+				for _, target := range chaos.Status.Targets {
+					targets = append(targets, string(target))
+				}
+
+				policies := listChaosNetworkPolicies(chaos)
+				Expect(policies).To(HaveLen(1))
+				Expect(policies[0].Spec.PodSelector.MatchExpressions[0].Values).To(Equal(targets))
+			})
+		})
+	})
+
+	When("filling up the disk", func() {
+		BeforeEach(func() {
+			chaos.Spec.Action = fdbv1beta2.ChaosActionDiskFull
+			chaos.Spec.ProcessClass = ""
+			chaos.Spec.Count = pointer.Int(1)
+		})
+
+		It("should create a pod that fills up the data volume of the target", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(chaos.Status.Targets).To(HaveLen(1))
+			target := chaos.Status.Targets[0]
+
+			pods := listChaosPods(chaos)
+			Expect(pods).To(HaveLen(1))
+			Expect(pods[0].Name).To(Equal("sample-chaos-1-" + string(target)))
+			Expect(pods[0].Annotations).To(HaveKeyWithValue(fdbv1beta2.ChaosTargetAnnotation, string(target)))
+			Expect(pods[0].Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal(cluster.Name + "-" + string(target) + "-data"))
+		})
+
+		It("should remove the file and the pod when the experiment ends", func() {
+			target := chaos.Status.Targets[0]
+			endChaos(chaos)
+
+			_, err = reconcileChaos(chaos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listChaosPods(chaos)).To(BeEmpty())
+			Expect(executor.commands).To(HaveKeyWithValue(cluster.Name+"-"+string(target), internal.GetChaosDiskFillCleanupCommand()))
+		})
+
+		It("should remove the file and the pod when the experiment is deleted", func() {
+			target := chaos.Status.Targets[0]
+			Expect(k8sClient.Delete(context.TODO(), chaos)).NotTo(HaveOccurred())
+
+			_, err = reconcileChaos(chaos)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(listChaosPods(chaos)).To(BeEmpty())
+			Expect(executor.commands).To(HaveKeyWithValue(cluster.Name+"-"+string(target), internal.GetChaosDiskFillCleanupCommand()))
+			Expect(k8serrors.IsNotFound(reloadChaos(chaos))).To(BeTrue())
+		})
+
+		When("the file cannot be removed", func() {
+			It("should keep the pod until the file is removed", func() {
+				executor.failAfter = 0
+				endChaos(chaos)
+
+				_, err = reconcileChaos(chaos)
+				Expect(err).To(HaveOccurred())
+				Expect(listChaosPods(chaos)).To(HaveLen(1))
+
+				executor.failAfter = -1
+				_, err = reconcileChaos(chaos)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(listChaosPods(chaos)).To(BeEmpty())
+			})
+		})
+	})
+})
+
+var _ = DescribeTable("getting the requeue delay for a chaos experiment",
+	func(status fdbv1beta2.FoundationDBChaosStatus, startTime *time.Time, expected time.Duration) {
+		now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		chaos := &fdbv1beta2.FoundationDBChaos{
+			Spec: fdbv1beta2.FoundationDBChaosSpec{
+				Action:          fdbv1beta2.ChaosActionKillProcesses,
+				IntervalSeconds: pointer.Int(10),
+				DurationSeconds: pointer.Int(60),
+			},
+			Status: status,
+		}
+
+		if startTime != nil {
+			chaos.Spec.StartTime = &metav1.Time{Time: *startTime}
+		}
+
+		Expect(getChaosRequeueDelay(chaos, now)).To(Equal(expected))
+	},
+	Entry("a pending experiment",
+		fdbv1beta2.FoundationDBChaosStatus{},
+		nil,
+		chaosStatusRefreshInterval),
+	Entry("a pending experiment with a start time",
+		fdbv1beta2.FoundationDBChaosStatus{},
+		&[]time.Time{time.Date(2023, 1, 1, 1, 0, 0, 0, time.UTC)}[0],
+		time.Hour),
+	Entry("a running experiment that injects the next fault soon",
+		fdbv1beta2.FoundationDBChaosStatus{
+			Phase:             fdbv1beta2.ChaosPhaseRunning,
+			StartTime:         &metav1.Time{Time: time.Date(2022, 12, 31, 23, 59, 30, 0, time.UTC)},
+			LastInjectionTime: &metav1.Time{Time: time.Date(2022, 12, 31, 23, 59, 55, 0, time.UTC)},
+		},
+		nil,
+		5*time.Second),
+	Entry("a running experiment that ends soon",
+		fdbv1beta2.FoundationDBChaosStatus{
+			Phase:     fdbv1beta2.ChaosPhaseRunning,
+			StartTime: &metav1.Time{Time: time.Date(2022, 12, 31, 23, 59, 2, 0, time.UTC)},
+		},
+		nil,
+		2*time.Second),
+	Entry("a running experiment that has ended",
+		fdbv1beta2.FoundationDBChaosStatus{
+			Phase:     fdbv1beta2.ChaosPhaseRunning,
+			StartTime: &metav1.Time{Time: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC)},
+		},
+		nil,
+		time.Second),
+)
@@ -25,6 +25,7 @@ import (
 	"fmt"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/locality"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/operations"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -53,7 +54,7 @@ func (c chooseRemovals) reconcile(ctx context.Context, r *FoundationDBClusterRec
 	}
 	desiredCounts := desiredCountStruct.Map()
 
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -111,9 +112,27 @@ func (c chooseRemovals) reconcile(ctx context.Context, r *FoundationDBClusterRec
 		}
 	}
 
+	requests := make([]operations.Request, 0)
 	if hasNewRemovals {
 		for _, processGroup := range cluster.Status.ProcessGroups {
-			if !remainingProcessMap[string(processGroup.ProcessGroupID)] {
+			if !remainingProcessMap[string(processGroup.ProcessGroupID)] && !processGroup.IsMarkedForRemoval() {
+				requests = append(requests, operations.Request{ProcessGroupID: processGroup.ProcessGroupID, Reason: "cluster is shrinking"})
+			}
+		}
+	}
+
+	allowed := operations.Plan(cluster, fdbv1beta2.ProcessGroupOperationRemoval, requests, r.now())
+	if len(allowed) < len(requests) {
+		logger.Info("Waiting for the operation queue to remove process groups", "requested", len(requests), "allowed", len(allowed))
+	}
+
+	if hasNewRemovals {
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if remainingProcessMap[string(processGroup.ProcessGroupID)] {
+				continue
+			}
+
+			if _, ok := allowed[processGroup.ProcessGroupID]; ok || processGroup.IsMarkedForRemoval() {
 				processGroup.MarkForRemoval()
 			}
 		}
@@ -27,21 +27,32 @@ import (
 	"regexp"
 	"time"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/tracing"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
 	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/clock"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -57,10 +68,15 @@ type FoundationDBClusterReconciler struct {
 	EnableRecoveryState                bool
 	PodLifecycleManager                podmanager.PodLifecycleManager
 	PodClientProvider                  func(*fdbv1beta2.FoundationDBCluster, *corev1.Pod) (podclient.FdbPodClient, error)
+	PodCommandExecutor                 internal.PodCommandExecutor
 	DatabaseClientProvider             fdbadminclient.DatabaseClientProvider
 	DeprecationOptions                 internal.DeprecationOptions
 	GetTimeout                         time.Duration
 	PostTimeout                        time.Duration
+	AuditRecorder                      *audit.Recorder
+	Sharder                            *sharding.Sharder
+	FreezeWindows                      []fdbv1beta2.FreezeWindow
+	Clock                              clock.PassiveClock
 }
 
 // NewFoundationDBClusterReconciler creates a new FoundationDBClusterReconciler with defaults.
@@ -75,11 +91,17 @@ func NewFoundationDBClusterReconciler(podLifecycleManager podmanager.PodLifecycl
 
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbauditevents,verbs=get;list;watch;create
 // +kubebuilder:rbac:groups="",resources=pods;configmaps;persistentvolumeclaims;events;secrets;services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="",resources=pods/exec,verbs=create
 // +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="storage.k8s.io",resources=storageclasses,verbs=get;list;watch
 
 // Reconcile runs the reconciliation logic.
 func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.StartSpan(ctx, "Reconcile", tracing.NamespaceKey.String(request.Namespace), tracing.ClusterKey.String(request.Name))
+	defer span.End()
+
 	cluster := &fdbv1beta2.FoundationDBCluster{}
 
 	err := r.Get(ctx, request.NamespacedName, cluster)
@@ -93,6 +115,21 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 
 	clusterLog := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name)
 
+	if !r.Sharder.OwnsCluster(cluster.Namespace, cluster.Name) {
+		clusterLog.V(1).Info("Skipping cluster owned by another shard")
+		return ctrl.Result{}, nil
+	}
+
+	acquired, err := r.Sharder.AcquireCluster(ctx, cluster.Namespace, cluster.Name)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !acquired {
+		clusterLog.Info("Waiting for the previous shard to finish the reconciliation of the cluster")
+		return ctrl.Result{Requeue: true}, nil
+	}
+	defer r.Sharder.ReleaseCluster(ctx, cluster.Namespace, cluster.Name)
+
 	if cluster.Spec.Skip {
 		clusterLog.Info("Skipping cluster with skip value true", "skip", cluster.Spec.Skip)
 		// Don't requeue
@@ -104,7 +141,7 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		return ctrl.Result{}, err
 	}
 
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
@@ -124,9 +161,17 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		return ctrl.Result{}, fmt.Errorf("ClusterSpec is not valid: %w", err)
 	}
 
+	disruptionState, err := internal.GetDisruptionState(cluster, r.FreezeWindows, r.now())
+	if err != nil {
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "DisruptionSchedule not valid", err.Error())
+		return ctrl.Result{}, err
+	}
+
 	subReconcilers := []clusterSubReconciler{
 		updateStatus{},
+		recoverCoordinatorIPs{},
 		updateLockConfiguration{},
+		updateTLSCertificates{},
 		updateConfigMap{},
 		checkClientCompatibility{},
 		deletePodsForBuggification{},
@@ -136,6 +181,7 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		addServices{},
 		addPVCs{},
 		addPods{},
+		manageStorageCapacity{},
 		generateInitialClusterFile{},
 		removeIncompatibleProcesses{},
 		updateSidecarVersions{},
@@ -150,20 +196,56 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 		updatePods{},
 		removeProcessGroups{},
 		removeServices{},
+		autoscaleCluster{},
 		updateStatus{},
 	}
 
 	originalGeneration := cluster.ObjectMeta.Generation
 	normalizedSpec := cluster.Spec.DeepCopy()
 	delayedRequeue := false
+	var pendingDisruptions []string
+	historyEntry := fdbv1beta2.ReconciliationHistoryEntry{
+		StartTimestamp: r.now().Unix(),
+		Generation:     originalGeneration,
+	}
 
 	for _, subReconciler := range subReconcilers {
+		// The cluster might have been assigned to another shard while the
+		// previous sub-reconcilers were running.
+		if !r.Sharder.HoldsCluster(cluster.Namespace, cluster.Name) {
+			clusterLog.Info("Stopping reconciliation of cluster owned by another shard", "subReconciler", fmt.Sprintf("%T", subReconciler))
+			return ctrl.Result{}, nil
+		}
+
 		// We have to set the normalized spec here again otherwise any call to Update() for the status of the cluster
 		// will reset all normalized fields...
 		cluster.Spec = *(normalizedSpec.DeepCopy())
+
+		// Disruptive actions on a configured cluster are only performed if
+		// the disruption schedule allows them, unless they are required to
+		// restore the fault tolerance of the cluster. Pending disruptive work
+		// is recorded in the status until the next allowed time.
+		if disruptive, ok := subReconciler.(disruptiveSubReconciler); ok && cluster.Status.Configured {
+			if disruptionState.Allowed {
+				cluster.Status.PendingDisruptions = nil
+			} else if isUrgentDisruption(subReconciler, cluster) {
+				clusterLog.Info("Running disruptive sub-reconciler to restore fault tolerance", "subReconciler", fmt.Sprintf("%T", subReconciler), "reason", disruptionState.Reason)
+			} else {
+				if disruptive.hasPendingDisruption(cluster) {
+					clusterLog.Info("Delaying disruptive sub-reconciler", "subReconciler", fmt.Sprintf("%T", subReconciler), "reason", disruptionState.Reason, "nextAllowedTime", disruptionState.NextAllowedTime)
+					pendingDisruptions = append(pendingDisruptions, fmt.Sprintf("%T", subReconciler))
+				}
+
+				cluster.Status.PendingDisruptions = internal.GetPendingDisruptionsStatus(disruptionState, pendingDisruptions)
+				continue
+			}
+		}
+
 		clusterLog.Info("Attempting to run sub-reconciler", "subReconciler", fmt.Sprintf("%T", subReconciler))
 
-		requeue := subReconciler.reconcile(ctx, r, cluster)
+		subReconcilerCtx, subReconcilerSpan := tracing.StartSpan(ctx, fmt.Sprintf("%T", subReconciler), tracing.GetClusterAttributes(cluster)...)
+		requeue := subReconciler.reconcile(subReconcilerCtx, r, cluster)
+		endSubReconcilerSpan(subReconcilerSpan, requeue)
 		if requeue == nil {
 			continue
 		}
@@ -174,24 +256,155 @@ func (r *FoundationDBClusterReconciler) Reconcile(ctx context.Context, request c
 				"message", requeue.message,
 				"error", requeue.curError)
 			delayedRequeue = true
+			historyEntry.DelayedRequeues = append(historyEntry.DelayedRequeues, getReconciliationRequeue(requeue, subReconciler))
 			continue
 		}
 
-		return processRequeue(requeue, subReconciler, cluster, r.Recorder, clusterLog)
+		result, err := processRequeue(requeue, subReconciler, cluster, r.Recorder, clusterLog)
+		historyEntry.Result = fdbv1beta2.ReconciliationResultTerminatedEarly
+		if err != nil {
+			historyEntry.Result = fdbv1beta2.ReconciliationResultFailed
+		}
+		stoppingRequeue := getReconciliationRequeue(requeue, subReconciler)
+		historyEntry.Requeue = &stoppingRequeue
+		r.recordReconciliationHistory(ctx, cluster, historyEntry, clusterLog)
+
+		return result, err
+	}
+
+	if len(pendingDisruptions) > 0 {
+		delay := getPendingDisruptionsRequeueDelay(disruptionState, r.now())
+		clusterLog.Info("Cluster has pending disruptive actions", "pendingDisruptions", pendingDisruptions, "delay", delay)
+		for _, subReconciler := range pendingDisruptions {
+			historyEntry.DelayedRequeues = append(historyEntry.DelayedRequeues, fdbv1beta2.ReconciliationRequeue{
+				SubReconciler: subReconciler,
+				Message:       disruptionState.Reason,
+				DelaySeconds:  int64(delay.Seconds()),
+			})
+		}
+		historyEntry.Result = fdbv1beta2.ReconciliationResultRequeued
+		r.recordReconciliationHistory(ctx, cluster, historyEntry, clusterLog)
+
+		return ctrl.Result{RequeueAfter: delay}, nil
 	}
 
 	if cluster.Status.Generations.Reconciled < originalGeneration || delayedRequeue {
 		clusterLog.Info("Cluster was not fully reconciled by reconciliation process", "status", cluster.Status.Generations)
+		historyEntry.Result = fdbv1beta2.ReconciliationResultRequeued
+		r.recordReconciliationHistory(ctx, cluster, historyEntry, clusterLog)
 
 		return ctrl.Result{Requeue: true}, nil
 	}
 
 	clusterLog.Info("Reconciliation complete", "generation", cluster.Status.Generations.Reconciled)
+	historyEntry.Result = fdbv1beta2.ReconciliationResultCompleted
+	r.recordReconciliationHistory(ctx, cluster, historyEntry, clusterLog)
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ReconciliationComplete", fmt.Sprintf("Reconciled generation %d", cluster.Status.Generations.Reconciled))
 
 	return ctrl.Result{}, nil
 }
 
+// maxPendingDisruptionsRequeueDelay defines the maximum delay before the next
+// reconciliation if disruptive actions are pending.
+const maxPendingDisruptionsRequeueDelay = 15 * time.Minute
+
+// getPendingDisruptionsRequeueDelay returns how long to wait before the next
+// reconciliation if disruptive actions are pending. The delay is limited to
+// maxPendingDisruptionsRequeueDelay, so changes to the disruption schedule or
+// the status are picked up in a timely manner.
+func getPendingDisruptionsRequeueDelay(state internal.DisruptionState, now time.Time) time.Duration {
+	if state.NextAllowedTime == nil {
+		return maxPendingDisruptionsRequeueDelay
+	}
+
+	delay := state.NextAllowedTime.Sub(now)
+	if delay < time.Second {
+		return time.Second
+	}
+
+	if delay > maxPendingDisruptionsRequeueDelay {
+		return maxPendingDisruptionsRequeueDelay
+	}
+
+	return delay
+}
+
+// endSubReconcilerSpan adds the requeue of the sub-reconciler to the span and
+// ends the span.
+func endSubReconcilerSpan(span trace.Span, requeue *requeue) {
+	if requeue == nil {
+		tracing.EndSpan(span, nil)
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("requeue.message", requeue.message),
+		attribute.Bool("requeue.delayed", requeue.delayedRequeue),
+	)
+	tracing.EndSpan(span, requeue.curError)
+}
+
+// getReconciliationRequeue returns the representation of the requeue for the reconciliation history.
+func getReconciliationRequeue(requeue *requeue, subReconciler clusterSubReconciler) fdbv1beta2.ReconciliationRequeue {
+	reconciliationRequeue := fdbv1beta2.ReconciliationRequeue{
+		SubReconciler: fmt.Sprintf("%T", subReconciler),
+		Message:       requeue.message,
+		DelaySeconds:  int64(requeue.delay.Seconds()),
+	}
+
+	if requeue.curError != nil {
+		reconciliationRequeue.Error = requeue.curError.Error()
+	}
+
+	return reconciliationRequeue
+}
+
+// recordReconciliationHistory adds the entry to the reconciliation history ConfigMap of the cluster. The history is
+// only used for debugging, so errors are logged and will not fail the reconciliation.
+func (r *FoundationDBClusterReconciler) recordReconciliationHistory(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.ReconciliationHistoryEntry, logger logr.Logger) {
+	entry.EndTimestamp = r.now().Unix()
+	entry.ReconciledGeneration = cluster.Status.Generations.Reconciled
+
+	configMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: internal.GetReconciliationHistoryConfigMapName(cluster)}, configMap)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		logger.Error(err, "Error fetching reconciliation history")
+		return
+	}
+	exists := err == nil
+
+	if cluster.GetReconciliationHistorySize() <= 0 {
+		if exists {
+			err = r.Delete(ctx, configMap)
+			if err != nil {
+				logger.Error(err, "Error deleting reconciliation history")
+			}
+		}
+
+		return
+	}
+
+	if !exists {
+		configMap = internal.GetReconciliationHistoryConfigMap(cluster)
+	}
+
+	err = internal.AddReconciliationHistoryEntry(cluster, configMap, entry)
+	if err != nil {
+		logger.Error(err, "Error adding entry to reconciliation history")
+		return
+	}
+
+	if exists {
+		err = r.Update(ctx, configMap)
+	} else {
+		err = r.Create(ctx, configMap)
+	}
+
+	if err != nil {
+		logger.Error(err, "Error recording reconciliation history")
+	}
+}
+
 // SetupWithManager prepares a reconciler for use.
 func (r *FoundationDBClusterReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector, watchedObjects ...client.Object) error {
 	err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, "metadata.name", func(o client.Object) []string {
@@ -219,37 +432,80 @@ func (r *FoundationDBClusterReconciler) SetupWithManager(mgr ctrl.Manager, maxCo
 		return err
 	}
 
-	builder := ctrl.NewControllerManagedBy(mgr).
+	clusterSelector, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return err
+	}
+
+	// Only react on generation changes or annotation changes and only watch
+	// resources with the provided label selector.
+	eventFilter := builder.WithPredicates(
+		predicate.And(
+			labelSelectorPredicate,
+			predicate.Or(
+				predicate.GenerationChangedPredicate{},
+				predicate.AnnotationChangedPredicate{},
+			),
+		))
+
+	managedBy := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles},
 		).
-		For(&fdbv1beta2.FoundationDBCluster{}).
-		Owns(&corev1.Pod{}).
-		Owns(&corev1.PersistentVolumeClaim{}).
-		Owns(&corev1.ConfigMap{}).
-		Owns(&corev1.Service{}).
-		// Only react on generation changes or annotation changes and only watch
-		// resources with the provided label selector.
-		WithEventFilter(
-			predicate.And(
-				labelSelectorPredicate,
-				predicate.Or(
-					predicate.GenerationChangedPredicate{},
-					predicate.AnnotationChangedPredicate{},
-				),
-			))
+		For(&fdbv1beta2.FoundationDBCluster{}, eventFilter).
+		Owns(&corev1.Pod{}, eventFilter).
+		Owns(&corev1.PersistentVolumeClaim{}, eventFilter).
+		Owns(&corev1.ConfigMap{}, eventFilter).
+		Owns(&corev1.Service{}, eventFilter).
+		// Secrets don't have a generation, so we react on every change to
+		// the data of a Secret that contains certificates of a cluster, e.g.
+		// when cert-manager rotates a certificate.
+		Watches(
+			&source.Kind{Type: &corev1.Secret{}},
+			handler.EnqueueRequestsFromMapFunc(func(object client.Object) []reconcile.Request {
+				return r.findClustersForCertificateSecret(object, clusterSelector)
+			}),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{}),
+		)
+
+	if r.Sharder != nil {
+		managedBy.Watches(r.Sharder.GetSource(&fdbv1beta2.FoundationDBClusterList{}), &handler.EnqueueRequestForObject{}, eventFilter)
+	}
 
 	for _, object := range watchedObjects {
-		builder.Owns(object)
+		managedBy.Owns(object, eventFilter)
 	}
-	return builder.Complete(r)
+	return managedBy.Complete(r)
 }
 
-func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (bool, error) {
+// findClustersForCertificateSecret returns the reconcile requests for all
+// clusters that use the provided Secret in their TLS configuration.
+func (r *FoundationDBClusterReconciler) findClustersForCertificateSecret(secret client.Object, selector labels.Selector) []reconcile.Request {
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := r.List(context.Background(), clusters, client.InNamespace(secret.GetNamespace()), client.MatchingLabelsSelector{Selector: selector})
+	if err != nil {
+		log.Error(err, "Error listing clusters for certificate secret", "namespace", secret.GetNamespace(), "secret", secret.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, cluster := range clusters.Items {
+		for _, secretName := range cluster.GetTLSCertificateSecrets() {
+			if secretName == secret.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&cluster)})
+				break
+			}
+		}
+	}
+
+	return requests
+}
+
+func (r *FoundationDBClusterReconciler) updatePodDynamicConf(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (bool, error) {
 	if cluster.ProcessGroupIsBeingRemoved(podmanager.GetProcessGroupID(cluster, pod)) {
 		return true, nil
 	}
-	podClient, message := r.getPodClient(cluster, pod)
+	podClient, message := r.getPodClient(ctx, cluster, pod)
 	if podClient == nil {
 		log.Info("Unable to generate pod client", "namespace", cluster.Namespace, "cluster", cluster.Name, "processGroupID", podmanager.GetProcessGroupID(cluster, pod), "message", message)
 		return false, nil
@@ -306,7 +562,7 @@ func (r *FoundationDBClusterReconciler) updatePodDynamicConf(cluster *fdbv1beta2
 	return true, nil
 }
 
-func (r *FoundationDBClusterReconciler) getPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, string) {
+func (r *FoundationDBClusterReconciler) getPodClient(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, string) {
 	if pod == nil {
 		return nil, fmt.Sprintf("Process group in cluster %s/%s does not have pod defined", cluster.Namespace, cluster.Name)
 	}
@@ -316,7 +572,7 @@ func (r *FoundationDBClusterReconciler) getPodClient(cluster *fdbv1beta2.Foundat
 		return nil, err.Error()
 	}
 
-	return podClient, ""
+	return tracing.NewPodClient(ctx, cluster, pod.Name, podmanager.GetProcessGroupID(cluster, pod), podClient), ""
 }
 
 // getDatabaseClientProvider gets the client provider for a reconciler.
@@ -328,6 +584,26 @@ func (r *FoundationDBClusterReconciler) getDatabaseClientProvider() fdbadminclie
 	panic("Cluster reconciler does not have a DatabaseClientProvider defined")
 }
 
+// now returns the current time of the reconciler's clock. If no clock is defined the wall clock is used.
+func (r *FoundationDBClusterReconciler) now() time.Time {
+	if r.Clock != nil {
+		return r.Clock.Now()
+	}
+
+	return time.Now()
+}
+
+// getAdminClient gets the admin client for the cluster. The calls of the admin
+// client are traced as children of the span in ctx.
+func (r *FoundationDBClusterReconciler) getAdminClient(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (fdbadminclient.AdminClient, error) {
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracing.NewAdminClient(ctx, cluster, adminClient), nil
+}
+
 func (r *FoundationDBClusterReconciler) getLockClient(cluster *fdbv1beta2.FoundationDBCluster) (fdbadminclient.LockClient, error) {
 	return r.getDatabaseClientProvider().GetLockClient(cluster)
 }
@@ -370,13 +646,43 @@ type clusterSubReconciler interface {
 	reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue
 }
 
+// disruptiveSubReconciler describes a sub-reconciler that performs disruptive
+// actions, which are only performed if the disruption schedule of the cluster
+// allows them.
+type disruptiveSubReconciler interface {
+	clusterSubReconciler
+
+	// hasPendingDisruption returns true if the sub-reconciler has disruptive
+	// work pending for the cluster.
+	hasPendingDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool
+}
+
+// urgentDisruptiveSubReconciler describes a disruptive sub-reconciler whose
+// work can be required to restore the fault tolerance of the cluster. Urgent
+// work is performed even if the disruption schedule doesn't allow disruptive
+// actions.
+type urgentDisruptiveSubReconciler interface {
+	disruptiveSubReconciler
+
+	// hasUrgentDisruption returns true if the sub-reconciler has disruptive
+	// work pending that restores the fault tolerance of the cluster.
+	hasUrgentDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool
+}
+
+// isUrgentDisruption returns true if the sub-reconciler has urgent disruptive
+// work pending for the cluster.
+func isUrgentDisruption(subReconciler clusterSubReconciler, cluster *fdbv1beta2.FoundationDBCluster) bool {
+	urgent, ok := subReconciler.(urgentDisruptiveSubReconciler)
+	return ok && urgent.hasUrgentDisruption(cluster)
+}
+
 // newFdbPodClient builds a client for working with an FDB Pod
 func (r *FoundationDBClusterReconciler) newFdbPodClient(cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod) (podclient.FdbPodClient, error) {
 	return internal.NewFdbPodClient(cluster, pod, log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "pod", pod.Name), r.GetTimeout, r.PostTimeout)
 }
 
-func (r *FoundationDBClusterReconciler) getCoordinatorSet(cluster *fdbv1beta2.FoundationDBCluster) (map[string]fdbv1beta2.None, error) {
-	adminClient, err := r.DatabaseClientProvider.GetAdminClient(cluster, r)
+func (r *FoundationDBClusterReconciler) getCoordinatorSet(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster) (map[string]fdbv1beta2.None, error) {
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return map[string]fdbv1beta2.None{}, err
 	}
@@ -408,3 +714,31 @@ func (r *FoundationDBClusterReconciler) updateOrApply(ctx context.Context, clust
 
 	return r.Status().Update(ctx, cluster)
 }
+
+// updateProcessCounts changes the process counts in the latest version of the cluster spec and records the status of
+// the change afterwards. Fetching the latest version prevents that the normalized spec of the reconciliation is written
+// back. Both changes are applied to the provided cluster as well, so the following sub-reconcilers use the new counts.
+func (r *FoundationDBClusterReconciler) updateProcessCounts(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, updateSpec func(*fdbv1beta2.FoundationDBCluster), updateStatus func(*fdbv1beta2.FoundationDBClusterStatus)) error {
+	latest := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKeyFromObject(cluster), latest)
+	if err != nil {
+		return err
+	}
+
+	updateSpec(latest)
+	err = r.Update(ctx, latest)
+	if err != nil {
+		return err
+	}
+
+	updateStatus(&latest.Status)
+	err = r.updateOrApply(ctx, latest)
+	if err != nil {
+		return err
+	}
+
+	updateSpec(cluster)
+	updateStatus(&cluster.Status)
+
+	return nil
+}
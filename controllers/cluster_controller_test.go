@@ -24,6 +24,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net"
 	"regexp"
 	"sort"
 	"strings"
@@ -36,8 +37,13 @@ import (
 	"k8s.io/utils/pointer"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
 
 	"github.com/prometheus/common/expfmt"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -359,6 +365,167 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("when recording the reconciliation history", func() {
+			var history []fdbv1beta2.ReconciliationHistoryEntry
+
+			getHistory := func() ([]fdbv1beta2.ReconciliationHistoryEntry, error) {
+				configMap := &corev1.ConfigMap{}
+				err := k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: internal.GetReconciliationHistoryConfigMapName(cluster)}, configMap)
+				if err != nil {
+					return nil, err
+				}
+
+				return internal.GetReconciliationHistory(configMap)
+			}
+
+			When("the reconciliation completes", func() {
+				BeforeEach(func() {
+					generationGap = 0
+				})
+
+				JustBeforeEach(func() {
+					history, err = getHistory()
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should record the completed run", func() {
+					Expect(history).NotTo(BeEmpty())
+					lastEntry := history[len(history)-1]
+					Expect(lastEntry.Result).To(Equal(fdbv1beta2.ReconciliationResultCompleted))
+					Expect(lastEntry.Generation).To(Equal(cluster.ObjectMeta.Generation))
+					Expect(lastEntry.ReconciledGeneration).To(Equal(cluster.ObjectMeta.Generation))
+					Expect(lastEntry.Requeue).To(BeNil())
+					Expect(lastEntry.EndTimestamp).To(BeNumerically(">=", lastEntry.StartTimestamp))
+				})
+
+				It("should not have more entries than the default size", func() {
+					Expect(len(history)).To(BeNumerically("<=", 10))
+				})
+			})
+
+			When("a sub-reconciler delays the requeue", func() {
+				BeforeEach(func() {
+					adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+					Expect(err).NotTo(HaveOccurred())
+
+					cluster.Spec.ProcessGroupsToRemove = []fdbv1beta2.ProcessGroupID{
+						fdbv1beta2.ProcessGroupID(originalPods.Items[firstStorageIndex].ObjectMeta.Labels[fdbv1beta2.FDBProcessGroupIDLabel]),
+					}
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+
+					adminClient.MockMissingProcessGroup("storage-2", true)
+					adminClient.MockMissingProcessGroup("storage-3", true)
+					shouldCompleteReconciliation = false
+					generationGap = 0
+				})
+
+				JustBeforeEach(func() {
+					history, err = getHistory()
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should record the delayed and the stopping requeue", func() {
+					Expect(history).NotTo(BeEmpty())
+					lastEntry := history[len(history)-1]
+					Expect(lastEntry.Result).To(Equal(fdbv1beta2.ReconciliationResultTerminatedEarly))
+					Expect(lastEntry.Generation).To(Equal(originalVersion + 1))
+					Expect(lastEntry.ReconciledGeneration).To(Equal(originalVersion))
+					Expect(lastEntry.DelayedRequeues).To(HaveLen(1))
+					Expect(lastEntry.DelayedRequeues[0].SubReconciler).To(Equal("controllers.excludeProcesses"))
+					Expect(lastEntry.DelayedRequeues[0].Message).To(HavePrefix("Waiting for missing processes"))
+					Expect(lastEntry.Requeue).NotTo(BeNil())
+					Expect(lastEntry.Requeue.SubReconciler).To(Equal("controllers.removeProcessGroups"))
+					Expect(lastEntry.Requeue.Message).To(Equal("Reconciliation needs to exclude more processes"))
+				})
+			})
+
+			When("the reconciliation history is disabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.ReconciliationHistorySize = pointer.Int(0)
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should delete the reconciliation history", func() {
+					_, err = getHistory()
+					Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+				})
+			})
+		})
+
+		When("tracing the reconciliation", func() {
+			var recorder *tracetest.SpanRecorder
+
+			BeforeEach(func() {
+				recorder = tracetest.NewSpanRecorder()
+				otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+				generationGap = 0
+			})
+
+			AfterEach(func() {
+				otel.SetTracerProvider(trace.NewNoopTracerProvider())
+			})
+
+			It("should create a span for the reconciliation with child spans for the sub-reconcilers and admin client calls", func() {
+				spans := map[string][]sdktrace.ReadOnlySpan{}
+				for _, span := range recorder.Ended() {
+					spans[span.Name()] = append(spans[span.Name()], span)
+				}
+
+				Expect(spans).To(HaveKeyWithValue("Reconcile", HaveLen(1)))
+				reconcileSpan := spans["Reconcile"][0]
+				Expect(reconcileSpan.Parent().IsValid()).To(BeFalse())
+
+				Expect(spans).To(HaveKeyWithValue("controllers.updateStatus", HaveLen(2)))
+				for _, span := range spans["controllers.updateStatus"] {
+					Expect(span.Parent().SpanID()).To(Equal(reconcileSpan.SpanContext().SpanID()))
+				}
+
+				subReconcilerSpanIDs := map[trace.SpanID]fdbv1beta2.None{}
+				for _, span := range recorder.Ended() {
+					if span.Parent().SpanID() == reconcileSpan.SpanContext().SpanID() {
+						subReconcilerSpanIDs[span.SpanContext().SpanID()] = fdbv1beta2.None{}
+					}
+				}
+
+				Expect(spans).To(HaveKey("AdminClient.GetStatus"))
+				for _, span := range spans["AdminClient.GetStatus"] {
+					Expect(subReconcilerSpanIDs).To(HaveKey(span.Parent().SpanID()))
+				}
+			})
+		})
+
+		When("the cluster is owned by another shard", func() {
+			BeforeEach(func() {
+				// A sharder that hasn't synced the membership doesn't own any
+				// cluster.
+				clusterReconciler.Sharder = sharding.NewSharder(k8sClient, k8sClient, log, sharding.Config{
+					Namespace:     "operator",
+					Group:         "test",
+					Identity:      "operator-0",
+					LeaseDuration: 15 * time.Second,
+				})
+
+				cluster.Spec.ProcessCounts.Storage = 5
+				err := k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+				generationGap = 0
+			})
+
+			AfterEach(func() {
+				clusterReconciler.Sharder = nil
+			})
+
+			It("should not reconcile the cluster", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(pods.Items)).To(Equal(len(originalPods.Items)))
+				Expect(cluster.Status.Generations.Reconciled).To(BeNumerically("<", cluster.ObjectMeta.Generation))
+			})
+		})
+
 		Context("when buggifying an empty fdbmonitor conf", func() {
 			BeforeEach(func() {
 				cluster.Spec.Buggify.EmptyMonitorConf = true
@@ -436,6 +603,33 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with decreased replicas for the scale subresource", func() {
+			BeforeEach(func() {
+				cluster.Spec.Scale.Replicas = pointer.Int(3)
+				err = k8sClient.Update(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should remove a storage pod through the exclusion flow", func() {
+				pods := &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), pods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(getProcessClassMap(cluster, pods.Items)).To(Equal(map[fdbv1beta2.ProcessClass]int{
+					fdbv1beta2.ProcessClassStorage:           3,
+					fdbv1beta2.ProcessClassLog:               4,
+					fdbv1beta2.ProcessClassStateless:         8,
+					fdbv1beta2.ProcessClassClusterController: 1,
+				}))
+
+				adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(adminClient.ReincludedAddresses).To(HaveLen(1))
+
+				Expect(cluster.Status.Scale.Replicas).To(Equal(3))
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+			})
+		})
+
 		Context("with an increased process count", func() {
 			BeforeEach(func() {
 				cluster.Spec.ProcessCounts.Storage = 5
@@ -835,6 +1029,79 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with an active operator freeze window", func() {
+			var adminClient *mock.AdminClient
+			var coordinatorAddress string
+
+			BeforeEach(func() {
+				adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+
+				clusterReconciler.FreezeWindows = []fdbv1beta2.FreezeWindow{
+					{
+						Start:  metav1.NewTime(time.Now().Add(-1 * time.Hour)),
+						End:    metav1.NewTime(time.Now().Add(1 * time.Hour)),
+						Reason: "release freeze",
+					},
+				}
+
+				connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+				Expect(err).NotTo(HaveOccurred())
+				coordinatorAddress = connectionString.Coordinators[0]
+			})
+
+			AfterEach(func() {
+				clusterReconciler.FreezeWindows = nil
+			})
+
+			When("a coordinator is missing", func() {
+				BeforeEach(func() {
+					shouldCompleteReconciliation = false
+					// Disable replacements, so the missing process group is not removed.
+					cluster.Spec.AutomationOptions.Replacements.Enabled = pointer.Bool(false)
+					Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+					for _, processGroup := range cluster.Status.ProcessGroups {
+						if fmt.Sprintf("%s:4501", processGroup.Addresses[0]) == coordinatorAddress {
+							adminClient.MockMissingProcessGroup(processGroup.ProcessGroupID, true)
+						}
+					}
+				})
+
+				It("should change the coordinators", func() {
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(cluster.Status.ConnectionString).NotTo(ContainSubstring(coordinatorAddress))
+					Expect(cluster.Status.NeedsNewCoordinators).To(BeFalse())
+					Expect(cluster.Status.HasUnhealthyCoordinators).To(BeFalse())
+				})
+			})
+
+			When("the coordinator selection is changed", func() {
+				var originalConnectionString string
+
+				BeforeEach(func() {
+					originalConnectionString = cluster.Status.ConnectionString
+					generationGap = 0
+					cluster.Spec.CoordinatorSelection = []fdbv1beta2.CoordinatorSelectionSetting{
+						{
+							ProcessClass: fdbv1beta2.ProcessClassLog,
+							Priority:     0,
+						},
+					}
+					Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+				})
+
+				It("should not change the coordinators", func() {
+					Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+					Expect(cluster.Status.NeedsNewCoordinators).To(BeTrue())
+					Expect(cluster.Status.HasUnhealthyCoordinators).To(BeFalse())
+					Expect(cluster.Status.PendingDisruptions).NotTo(BeNil())
+					Expect(cluster.Status.PendingDisruptions.Actions).To(ConsistOf("controllers.changeCoordinators"))
+				})
+			})
+		})
+
 		Context("with a missing process", func() {
 			var adminClient *mock.AdminClient
 
@@ -1077,6 +1344,56 @@ var _ = Describe("cluster_controller", func() {
 				})
 			})
 
+			Context("with an active operator freeze window", func() {
+				var freezeEnd time.Time
+
+				BeforeEach(func() {
+					generationGap = 0
+					freezeEnd = time.Now().Add(1 * time.Hour).Truncate(time.Second)
+					clusterReconciler.FreezeWindows = []fdbv1beta2.FreezeWindow{
+						{
+							Start:  metav1.NewTime(time.Now().Add(-1 * time.Hour)),
+							End:    metav1.NewTime(freezeEnd),
+							Reason: "release freeze",
+						},
+					}
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				AfterEach(func() {
+					clusterReconciler.FreezeWindows = nil
+				})
+
+				It("should not kill any processes", func() {
+					Expect(adminClient.KilledAddresses).To(BeEmpty())
+				})
+
+				It("should record the pending disruption in the status", func() {
+					Expect(cluster.Status.PendingDisruptions).NotTo(BeNil())
+					Expect(cluster.Status.PendingDisruptions.Actions).To(ConsistOf("controllers.bounceProcesses"))
+					Expect(cluster.Status.PendingDisruptions.Reason).To(HaveSuffix("release freeze"))
+					Expect(cluster.Status.PendingDisruptions.NextAllowedTime).NotTo(BeNil())
+					Expect(cluster.Status.PendingDisruptions.NextAllowedTime.Time).To(BeTemporally("==", freezeEnd))
+				})
+
+				When("the freeze window is removed", func() {
+					JustBeforeEach(func() {
+						clusterReconciler.FreezeWindows = nil
+						_, err := reconcileCluster(cluster)
+						Expect(err).NotTo(HaveOccurred())
+						_, err = reloadCluster(cluster)
+						Expect(err).NotTo(HaveOccurred())
+					})
+
+					It("should bounce the processes and clear the pending disruptions", func() {
+						Expect(adminClient.KilledAddresses).NotTo(BeEmpty())
+						Expect(cluster.Status.PendingDisruptions).To(BeNil())
+						Expect(cluster.Status.Generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
+					})
+				})
+			})
+
 			Context("with multiple storage servers per pod", func() {
 				BeforeEach(func() {
 					cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{fdbv1beta2.ProcessClassGeneral: {CustomParameters: fdbv1beta2.FoundationDBCustomParameters{}}}
@@ -1894,6 +2211,92 @@ var _ = Describe("cluster_controller", func() {
 					Expect(coordinator).To(HaveSuffix("tls"))
 				}
 			})
+
+			It("should complete the TLS transition", func() {
+				Expect(cluster.Status.TLS.Transition).To(BeEmpty())
+				Expect(cluster.Status.RequiredAddresses).To(Equal(fdbv1beta2.RequiredAddressSet{TLS: true}))
+			})
+		})
+
+		Context("with a certificate Secret", func() {
+			var secret *corev1.Secret
+
+			BeforeEach(func() {
+				secret, err = internal.CreateTestCertificateSecret(cluster.Namespace, "fdb-certificate", 1)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(k8sClient.Create(context.TODO(), secret)).NotTo(HaveOccurred())
+
+				cluster.Spec.TLS = &fdbv1beta2.TLSConfiguration{
+					CertificateSecrets: []string{secret.Name},
+				}
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should report the certificate in the status", func() {
+				Expect(cluster.Status.TLS.Certificates).To(HaveLen(1))
+				Expect(cluster.Status.TLS.Certificates[0].SecretName).To(Equal(secret.Name))
+				Expect(cluster.Status.TLS.Certificates[0].SerialNumber).To(Equal("1"))
+				Expect(cluster.Status.TLS.Certificates[0].NotAfter).NotTo(BeNil())
+			})
+
+			It("should trust the CA from the Secret", func() {
+				ca := internal.GetCAFromSecret(secret)
+				Expect(cluster.Status.TLS.TrustedCAs).To(Equal([]string{ca}))
+
+				configMap := &corev1.ConfigMap{}
+				Expect(k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: cluster.Namespace, Name: "operator-test-1-config"}, configMap)).NotTo(HaveOccurred())
+				Expect(configMap.Data["ca-file"]).To(Equal(ca))
+			})
+
+			When("the certificate is issued by a new CA", func() {
+				JustBeforeEach(func() {
+					rotated, err := internal.CreateTestCertificateSecret(cluster.Namespace, secret.Name, 2)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(secret), secret)).NotTo(HaveOccurred())
+					secret.Data = rotated.Data
+					Expect(k8sClient.Update(context.TODO(), secret)).NotTo(HaveOccurred())
+
+					result, err := reconcileCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(result.Requeue).To(BeFalse())
+
+					_, err = reloadCluster(cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should report the new certificate", func() {
+					Expect(cluster.Status.TLS.Certificates).To(HaveLen(1))
+					Expect(cluster.Status.TLS.Certificates[0].SerialNumber).To(Equal("2"))
+				})
+
+				It("should only trust the new CA", func() {
+					Expect(cluster.Status.TLS.TrustedCAs).To(Equal([]string{internal.GetCAFromSecret(secret)}))
+				})
+
+				It("should trust both CAs before removing the old CA", func() {
+					events := &corev1.EventList{}
+					Expect(k8sClient.List(context.TODO(), events)).NotTo(HaveOccurred())
+
+					var messages []string
+					var rotated bool
+					for _, event := range events.Items {
+						if event.Reason == "UpdatingTrustedCAs" {
+							messages = append(messages, event.Message)
+						}
+
+						if event.Reason == "CertificateRotated" {
+							rotated = true
+						}
+					}
+
+					Expect(messages).To(ConsistOf(
+						"Changing the number of trusted CAs from 0 to 1",
+						"Changing the number of trusted CAs from 1 to 2",
+						"Changing the number of trusted CAs from 2 to 1",
+					))
+					Expect(rotated).To(BeTrue())
+				})
+			})
 		})
 
 		Context("with a conversion to IPv6", func() {
@@ -1953,6 +2356,93 @@ var _ = Describe("cluster_controller", func() {
 			})
 		})
 
+		Context("with a newly created cluster in an IPv6-only Kubernetes cluster", func() {
+			BeforeEach(func() {
+				k8sClient.Clear()
+				k8sClient.MockIPFamilies(corev1.IPv6Protocol)
+				mock.ClearMockAdminClients()
+				mock.ClearMockLockClients()
+
+				cluster = internal.CreateDefaultCluster()
+				cluster.Spec.Routing.PodIPFamily = pointer.Int(6)
+				source := fdbv1beta2.PublicIPSourceService
+				cluster.Spec.Routing.PublicIPSource = &source
+
+				err = k8sClient.Create(context.TODO(), cluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				result, err := reconcileCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Requeue).To(BeFalse())
+
+				_, err = reloadCluster(cluster)
+				Expect(err).NotTo(HaveOccurred())
+
+				originalVersion = cluster.ObjectMeta.Generation
+
+				originalPods = &corev1.PodList{}
+				err = k8sClient.List(context.TODO(), originalPods, getListOptions(cluster)...)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(len(originalPods.Items)).To(Equal(17))
+
+				sortPodsByName(originalPods)
+
+				generationGap = 0
+			})
+
+			It("should use the IPv6 service addresses as public IPs", func() {
+				for _, pod := range originalPods.Items {
+					service := &corev1.Service{}
+					err = k8sClient.Get(context.TODO(), types.NamespacedName{Namespace: pod.Namespace, Name: pod.Name}, service)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(service.Spec.IPFamilies).To(ConsistOf(corev1.IPv6Protocol))
+
+					publicIP := net.ParseIP(pod.Annotations[fdbv1beta2.PublicIPAnnotation])
+					Expect(publicIP).NotTo(BeNil())
+					Expect(publicIP.To4()).To(BeNil())
+					Expect(publicIP.String()).To(Equal(service.Spec.ClusterIP))
+				}
+			})
+
+			It("should use bracketed addresses for the coordinators", func() {
+				connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(connectionString.Coordinators).To(HaveLen(3))
+				for _, coordinator := range connectionString.Coordinators {
+					Expect(coordinator).To(HavePrefix("["))
+					address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(address.IPAddress.To4()).To(BeNil())
+				}
+			})
+
+			When("a process group is removed", func() {
+				var removedAddress string
+
+				BeforeEach(func() {
+					for _, processGroup := range cluster.Status.ProcessGroups {
+						if processGroup.ProcessGroupID == "storage-4" {
+							removedAddress = processGroup.Addresses[0]
+						}
+					}
+					generationGap = 1
+					cluster.Spec.ProcessCounts.Storage = 3
+					err = k8sClient.Update(context.TODO(), cluster)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("should exclude and re-include the IPv6 address", func() {
+					adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+					Expect(net.ParseIP(removedAddress).To4()).To(BeNil())
+					Expect(adminClient.ReincludedAddresses).To(Equal(map[string]bool{
+						removedAddress: true,
+					}))
+				})
+			})
+		})
+
 		Context("with only storage processes as coordinator", func() {
 			BeforeEach(func() {
 				cluster.Spec.CoordinatorSelection = []fdbv1beta2.CoordinatorSelectionSetting{
@@ -136,6 +136,7 @@ func (d deletePodsForBuggification) reconcile(ctx context.Context, r *Foundation
 		logger.Info("Deleting pods", "count", len(updates))
 		r.Recorder.Event(cluster, "Normal", "UpdatingPods", "Recreating pods for buggification")
 		err = r.PodLifecycleManager.UpdatePods(logr.NewContext(ctx, logger), r, cluster, updates, true)
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionDeletePod, getPodNames(updates), "Pods are recreated for buggification", err)
 		if err != nil {
 			return &requeue{curError: err}
 		}
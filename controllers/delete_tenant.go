@@ -0,0 +1,98 @@
+/*
+ * delete_tenant.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// deleteTenant provides a reconciliation step for deleting a tenant from the
+// cluster before the resource is removed.
+type deleteTenant struct{}
+
+// reconcile runs the reconciler's work.
+func (d deleteTenant) reconcile(ctx context.Context, r *FoundationDBTenantReconciler, tenant *fdbv1beta2.FoundationDBTenant) *requeue {
+	if !controllerutil.ContainsFinalizer(tenant, fdbv1beta2.TenantFinalizer) {
+		return nil
+	}
+
+	adminClient, err := r.adminClientForTenant(ctx, tenant)
+	if err != nil {
+		// The tenant is removed together with the cluster, so there is nothing
+		// left to clean up if the cluster resource doesn't exist anymore.
+		if k8serrors.IsNotFound(err) {
+			r.Recorder.Event(tenant, corev1.EventTypeNormal, "ClusterNotFound", fmt.Sprintf("Cluster %s doesn't exist, removing the finalizer without deleting the tenant", tenant.Spec.ClusterName))
+			return removeTenantFinalizer(ctx, r, tenant)
+		}
+
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	tenantName := tenant.GetTenantName()
+	liveStatus, err := adminClient.GetTenant(tenantName)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if liveStatus != nil {
+		if !tenant.Spec.ForceDeletion {
+			empty, err := adminClient.IsTenantEmpty(tenantName)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			if !empty {
+				r.Recorder.Event(tenant, corev1.EventTypeWarning, "TenantNotEmpty", fmt.Sprintf("Tenant %s holds data and will not be deleted unless forceDeletion is set", tenantName))
+				return &requeue{message: fmt.Sprintf("Tenant %s holds data", tenantName), delay: time.Minute}
+			}
+		}
+
+		err = adminClient.DeleteTenant(tenantName, tenant.Spec.ForceDeletion)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		r.Recorder.Event(tenant, corev1.EventTypeNormal, "DeleteTenant", fmt.Sprintf("Deleted tenant %s", tenantName))
+	}
+
+	return removeTenantFinalizer(ctx, r, tenant)
+}
+
+// removeTenantFinalizer removes the tenant finalizer, which allows Kubernetes
+// to delete the resource.
+func removeTenantFinalizer(ctx context.Context, r *FoundationDBTenantReconciler, tenant *fdbv1beta2.FoundationDBTenant) *requeue {
+	controllerutil.RemoveFinalizer(tenant, fdbv1beta2.TenantFinalizer)
+	err := r.Update(ctx, tenant)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
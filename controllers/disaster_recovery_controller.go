@@ -0,0 +1,248 @@
+/*
+ * disaster_recovery_controller.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// disasterRecoveryStatusRefreshInterval defines how often the status of a
+// running DR is refreshed, to keep the lag in the status up to date.
+const disasterRecoveryStatusRefreshInterval = time.Minute
+
+// FoundationDBDisasterRecoveryReconciler reconciles a FoundationDBDisasterRecovery object
+type FoundationDBDisasterRecoveryReconciler struct {
+	client.Client
+	Recorder               record.EventRecorder
+	Log                    logr.Logger
+	InSimulation           bool
+	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
+	ServerSideApply        bool
+	Sharder                *sharding.Sharder
+}
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbdisasterrecoveries,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbdisasterrecoveries/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs the reconciliation logic.
+func (r *FoundationDBDisasterRecoveryReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	dr := &fdbv1beta2.FoundationDBDisasterRecovery{}
+
+	err := r.Get(ctx, request.NamespacedName, dr)
+
+	originalGeneration := dr.ObjectMeta.Generation
+
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Object not found, return.  Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	drLog := log.WithValues("namespace", dr.Namespace, "disasterRecovery", dr.Name)
+
+	if !r.Sharder.OwnsCluster(dr.Namespace, dr.Spec.DestinationClusterName) {
+		drLog.V(1).Info("Skipping disaster recovery of cluster owned by another shard", "cluster", dr.Spec.DestinationClusterName)
+		return ctrl.Result{}, nil
+	}
+
+	acquired, err := r.Sharder.AcquireCluster(ctx, dr.Namespace, dr.Spec.DestinationClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !acquired {
+		drLog.Info("Waiting for the previous shard to finish the reconciliation of the cluster", "cluster", dr.Spec.DestinationClusterName)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	defer r.Sharder.ReleaseCluster(ctx, dr.Namespace, dr.Spec.DestinationClusterName)
+
+	subReconcilers := []disasterRecoverySubReconciler{
+		updateDisasterRecoveryStatus{},
+		switchoverDisasterRecovery{},
+		updateDisasterRecoveryAgents{},
+		startDisasterRecovery{},
+		abortDisasterRecovery{},
+		toggleDisasterRecoveryPaused{},
+		updateDisasterRecoveryStatus{},
+	}
+
+	for _, subReconciler := range subReconcilers {
+		// The cluster might have been assigned to another shard while the
+		// previous sub-reconcilers were running.
+		if !r.Sharder.HoldsCluster(dr.Namespace, dr.Spec.DestinationClusterName) {
+			drLog.Info("Stopping reconciliation of cluster owned by another shard", "subReconciler", fmt.Sprintf("%T", subReconciler), "cluster", dr.Spec.DestinationClusterName)
+			return ctrl.Result{}, nil
+		}
+
+		requeue := subReconciler.reconcile(ctx, r, dr)
+		if requeue == nil {
+			continue
+		}
+
+		return processRequeue(requeue, subReconciler, dr, r.Recorder, drLog)
+	}
+
+	if dr.Status.Generations.Reconciled < originalGeneration {
+		drLog.Info("Disaster recovery was not fully reconciled by reconciliation process")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	drLog.Info("Reconciliation complete")
+
+	if dr.ShouldRun() {
+		return ctrl.Result{RequeueAfter: disasterRecoveryStatusRefreshInterval}, nil
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// getDatabaseClientProvider gets the client provider for a reconciler.
+func (r *FoundationDBDisasterRecoveryReconciler) getDatabaseClientProvider() fdbadminclient.DatabaseClientProvider {
+	if r.DatabaseClientProvider != nil {
+		return r.DatabaseClientProvider
+	}
+	panic("Disaster recovery reconciler does not have a DatabaseClientProvider defined")
+}
+
+// adminClientForDisasterRecovery provides an admin client for the destination
+// cluster of a DR, together with the connection string of the source
+// cluster.
+func (r *FoundationDBDisasterRecoveryReconciler) adminClientForDisasterRecovery(ctx context.Context, dr *fdbv1beta2.FoundationDBDisasterRecovery, sourceClusterName string, destinationClusterName string) (fdbadminclient.AdminClient, string, error) {
+	source := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: dr.ObjectMeta.Namespace, Name: sourceClusterName}, source)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if source.Status.ConnectionString == "" {
+		return nil, "", fmt.Errorf("source cluster %s has no connection string", source.Name)
+	}
+
+	destination := &fdbv1beta2.FoundationDBCluster{}
+	err = r.Get(ctx, types.NamespacedName{Namespace: dr.ObjectMeta.Namespace, Name: destinationClusterName}, destination)
+	if err != nil {
+		return nil, "", err
+	}
+
+	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(destination, r)
+	if err != nil {
+		return nil, "", err
+	}
+
+	adminClient.SetKnobs(dr.Spec.CustomParameters.GetKnobsForCLI())
+
+	return adminClient, source.Status.ConnectionString, nil
+}
+
+// SetupWithManager prepares a reconciler for use.
+func (r *FoundationDBDisasterRecoveryReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector) error {
+	labelSelectorPredicate, err := predicate.LabelSelectorPredicate(selector)
+	if err != nil {
+		return err
+	}
+
+	managedBy := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentReconciles},
+		).
+		For(&fdbv1beta2.FoundationDBDisasterRecovery{}).
+		Owns(&appsv1.Deployment{}).
+		// Only react on generation changes or annotation changes and only watch
+		// resources with the provided label selector.
+		WithEventFilter(
+			predicate.And(
+				labelSelectorPredicate,
+				predicate.Or(
+					predicate.GenerationChangedPredicate{},
+					predicate.AnnotationChangedPredicate{},
+				),
+			))
+
+	if r.Sharder != nil {
+		managedBy.Watches(r.Sharder.GetSource(&fdbv1beta2.FoundationDBDisasterRecoveryList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	return managedBy.Complete(r)
+}
+
+// disasterRecoverySubReconciler describes a class that does part of the work
+// of reconciliation for a DR.
+type disasterRecoverySubReconciler interface {
+	/**
+	reconcile runs the reconciler's work.
+
+	If reconciliation can continue, this should return nil.
+
+	If reconciliation encounters an error, this should return a requeue object
+	with an `Error` field.
+
+	If reconciliation cannot proceed, this should return a requeue object with a
+	`Message` field.
+	*/
+	reconcile(ctx context.Context, r *FoundationDBDisasterRecoveryReconciler, dr *fdbv1beta2.FoundationDBDisasterRecovery) *requeue
+}
+
+// updateOrApply updates the status either with server-side apply or if disabled with the normal update call.
+func (r *FoundationDBDisasterRecoveryReconciler) updateOrApply(ctx context.Context, dr *fdbv1beta2.FoundationDBDisasterRecovery) error {
+	if r.ServerSideApply {
+		// We have to set the TypeMeta otherwise the Patch command will fail.
+		patch := &fdbv1beta2.FoundationDBDisasterRecovery{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       dr.Kind,
+				APIVersion: dr.APIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      dr.Name,
+				Namespace: dr.Namespace,
+			},
+			Status: dr.Status,
+		}
+
+		return r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner("fdb-operator"), client.ForceOwnership)
+	}
+
+	return r.Status().Update(ctx, dr)
+}
@@ -0,0 +1,200 @@
+/*
+ * disaster_recovery_controller_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+func reloadDisasterRecovery(dr *fdbv1beta2.FoundationDBDisasterRecovery) error {
+	return k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(dr), dr)
+}
+
+var _ = Describe("disaster_recovery_controller", func() {
+	var source *fdbv1beta2.FoundationDBCluster
+	var destination *fdbv1beta2.FoundationDBCluster
+	var dr *fdbv1beta2.FoundationDBDisasterRecovery
+	var sourceAdminClient *mock.AdminClient
+	var destinationAdminClient *mock.AdminClient
+
+	BeforeEach(func() {
+		source = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(source)).NotTo(HaveOccurred())
+
+		destination = internal.CreateDefaultCluster()
+		destination.Name = "operator-test-2"
+		Expect(setupClusterForTest(destination)).NotTo(HaveOccurred())
+
+		var err error
+		sourceAdminClient, err = mock.NewMockAdminClientUncast(source, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+		destinationAdminClient, err = mock.NewMockAdminClientUncast(destination, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		dr = internal.CreateDefaultDisasterRecovery(source, destination)
+		Expect(k8sClient.Create(context.TODO(), dr)).NotTo(HaveOccurred())
+
+		_, err = reconcileDisasterRecovery(dr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+	})
+
+	When("reconciling a new DR", func() {
+		It("should create the agent deployment", func() {
+			deployment := &appsv1.Deployment{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: dr.Namespace, Name: "operator-test-1-dr-agents"}, deployment)).NotTo(HaveOccurred())
+			Expect(deployment.Spec.Replicas).To(Equal(pointer.Int32(3)))
+			Expect(deployment.Labels[fdbv1beta2.DisasterRecoveryDeploymentLabel]).To(Equal(string(dr.UID)))
+		})
+
+		It("should start the DR on the destination cluster", func() {
+			Expect(destinationAdminClient.DisasterRecoveries).To(HaveKey(source.Status.ConnectionString))
+			Expect(destinationAdminClient.DisasterRecoveries[source.Status.ConnectionString].Running).To(BeTrue())
+			Expect(sourceAdminClient.DisasterRecoveries).To(BeEmpty())
+		})
+
+		It("should update the status", func() {
+			Expect(dr.Status.Generations.Reconciled).To(Equal(dr.Generation))
+			Expect(dr.Status.AgentCount).To(Equal(3))
+			Expect(dr.Status.DeploymentConfigured).To(BeTrue())
+			Expect(dr.Status.DRDetails).To(Equal(&fdbv1beta2.FoundationDBDisasterRecoveryStatusDetails{
+				SourceClusterName:      source.Name,
+				DestinationClusterName: destination.Name,
+				Running:                true,
+			}))
+		})
+	})
+
+	When("the destination cluster is lagging behind", func() {
+		BeforeEach(func() {
+			status := destinationAdminClient.DisasterRecoveries[source.Status.ConnectionString]
+			status.SecondsBehind = 1.5
+			destinationAdminClient.DisasterRecoveries[source.Status.ConnectionString] = status
+
+			_, err := reconcileDisasterRecovery(dr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+		})
+
+		It("should expose the lag in the status", func() {
+			Expect(dr.Status.DRDetails.LagMilliseconds).To(BeNumerically("==", 1500))
+		})
+	})
+
+	When("pausing the DR", func() {
+		BeforeEach(func() {
+			dr.Spec.DRState = fdbv1beta2.DisasterRecoveryStatePaused
+			Expect(k8sClient.Update(context.TODO(), dr)).NotTo(HaveOccurred())
+
+			_, err := reconcileDisasterRecovery(dr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+		})
+
+		It("should pause the DR", func() {
+			Expect(destinationAdminClient.DisasterRecoveries[source.Status.ConnectionString].Paused).To(BeTrue())
+			Expect(dr.Status.DRDetails.Paused).To(BeTrue())
+			Expect(dr.Status.Generations.Reconciled).To(Equal(dr.Generation))
+		})
+
+		When("resuming the DR", func() {
+			BeforeEach(func() {
+				dr.Spec.DRState = fdbv1beta2.DisasterRecoveryStateRunning
+				Expect(k8sClient.Update(context.TODO(), dr)).NotTo(HaveOccurred())
+
+				_, err := reconcileDisasterRecovery(dr)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+			})
+
+			It("should resume the DR", func() {
+				Expect(destinationAdminClient.DisasterRecoveries[source.Status.ConnectionString].Paused).To(BeFalse())
+				Expect(dr.Status.DRDetails.Paused).To(BeFalse())
+				Expect(dr.Status.Generations.Reconciled).To(Equal(dr.Generation))
+			})
+		})
+	})
+
+	When("stopping the DR", func() {
+		BeforeEach(func() {
+			dr.Spec.DRState = fdbv1beta2.DisasterRecoveryStateStopped
+			Expect(k8sClient.Update(context.TODO(), dr)).NotTo(HaveOccurred())
+
+			_, err := reconcileDisasterRecovery(dr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+		})
+
+		It("should abort the DR", func() {
+			Expect(destinationAdminClient.DisasterRecoveries).To(BeEmpty())
+			Expect(dr.Status.DRDetails.Running).To(BeFalse())
+			Expect(dr.Status.Generations.Reconciled).To(Equal(dr.Generation))
+		})
+	})
+
+	When("swapping the source and the destination cluster", func() {
+		BeforeEach(func() {
+			dr.Spec.SourceClusterName = destination.Name
+			dr.Spec.DestinationClusterName = source.Name
+			Expect(k8sClient.Update(context.TODO(), dr)).NotTo(HaveOccurred())
+
+			_, err := reconcileDisasterRecovery(dr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+		})
+
+		It("should switch over the DR", func() {
+			Expect(destinationAdminClient.DisasterRecoveries).To(BeEmpty())
+			Expect(sourceAdminClient.DisasterRecoveries).To(HaveKey(destination.Status.ConnectionString))
+			Expect(dr.Status.DRDetails.SourceClusterName).To(Equal(destination.Name))
+			Expect(dr.Status.DRDetails.DestinationClusterName).To(Equal(source.Name))
+			Expect(dr.Status.Generations.Reconciled).To(Equal(dr.Generation))
+		})
+	})
+
+	When("changing the destination cluster of a running DR", func() {
+		BeforeEach(func() {
+			dr.Spec.DestinationClusterName = "operator-test-3"
+			Expect(k8sClient.Update(context.TODO(), dr)).NotTo(HaveOccurred())
+
+			_, err := reconcileDisasterRecovery(dr)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reloadDisasterRecovery(dr)).NotTo(HaveOccurred())
+		})
+
+		It("should keep the current DR running", func() {
+			Expect(destinationAdminClient.DisasterRecoveries).To(HaveKey(source.Status.ConnectionString))
+			Expect(dr.Status.DRDetails.DestinationClusterName).To(Equal(destination.Name))
+			Expect(dr.Status.Generations.Reconciled).To(BeNumerically("<", dr.Generation))
+			Expect(dr.Status.Generations.NeedsSwitchover).To(Equal(dr.Generation))
+		})
+	})
+})
@@ -25,7 +25,9 @@ import (
 	"fmt"
 	"math"
 	"net"
+	"sort"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
 	corev1 "k8s.io/api/core/v1"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
@@ -40,9 +42,9 @@ var missingProcessThreshold = 0.8
 type excludeProcesses struct{}
 
 // reconcile runs the reconciler's work.
-func (e excludeProcesses) reconcile(_ context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+func (e excludeProcesses) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "excludeProcesses")
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -82,6 +84,7 @@ func (e excludeProcesses) reconcile(_ context.Context, r *FoundationDBClusterRec
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "ExcludingProcesses", fmt.Sprintf("Excluding %v", fdbProcessesToExclude))
 
 		err = adminClient.ExcludeProcesses(fdbProcessesToExclude)
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionExcludeProcesses, audit.GetProcessAddressTargets(fdbProcessesToExclude), "Process groups are marked for removal", err)
 		if err != nil {
 			return &requeue{curError: err, delayedRequeue: true}
 		}
@@ -99,12 +102,20 @@ func getProcessesToExclude(exclusions []fdbv1beta2.ProcessAddress, cluster *fdbv
 		currentExclusionMap[exclusion.String()] = fdbv1beta2.None{}
 	}
 
+	faultDomainsToExclude := getFaultDomainsToExclude(cluster, currentExclusionMap)
+
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		// Process already excluded using locality, so we don't have to exclude it again
 		if _, ok := currentExclusionMap[processGroup.GetExclusionString()]; ok {
 			continue
 		}
 
+		if faultDomainsToExclude != nil {
+			if _, ok := faultDomainsToExclude[processGroup.FaultDomain]; !ok {
+				continue
+			}
+		}
+
 		// We are excluding process here using the locality field. It might be possible that the process was already excluded using IP before
 		// but for the sake of consistency it is better to exclude process using locality as well.
 		if cluster.UseLocalitiesForExclusion() && processGroup.IsMarkedForRemoval() && !processGroup.IsExcluded() {
@@ -128,6 +139,56 @@ func getProcessesToExclude(exclusions []fdbv1beta2.ProcessAddress, cluster *fdbv
 	return fdbProcessesToExclude, processClassesToExclude
 }
 
+// getFaultDomainsToExclude returns the fault domains whose processes can be excluded. Unless the removal mode allows to
+// remove all process groups at once, the processes are excluded one fault domain at a time and the processes of the next
+// fault domain are only excluded once the process groups with excluded processes are removed. A nil map means that the
+// processes of all fault domains can be excluded.
+func getFaultDomainsToExclude(cluster *fdbv1beta2.FoundationDBCluster, currentExclusionMap map[string]fdbv1beta2.None) map[string]fdbv1beta2.None {
+	if cluster.GetRemovalMode() == fdbv1beta2.PodUpdateModeAll {
+		return nil
+	}
+
+	faultDomains := make(map[string]fdbv1beta2.None)
+	pendingFaultDomains := make([]string, 0)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if !processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		if processGroup.IsExcluded() || isExcluded(processGroup, currentExclusionMap) {
+			faultDomains[processGroup.FaultDomain] = fdbv1beta2.None{}
+			continue
+		}
+
+		pendingFaultDomains = append(pendingFaultDomains, processGroup.FaultDomain)
+	}
+
+	if len(faultDomains) > 0 || len(pendingFaultDomains) == 0 {
+		return faultDomains
+	}
+
+	// Pick the fault domains in a stable order, so that all reconciliations exclude the same fault domain.
+	sort.Strings(pendingFaultDomains)
+	faultDomains[pendingFaultDomains[0]] = fdbv1beta2.None{}
+
+	return faultDomains
+}
+
+// isExcluded returns true if the locality or one of the addresses of the process group is part of the exclusions.
+func isExcluded(processGroup *fdbv1beta2.ProcessGroupStatus, currentExclusionMap map[string]fdbv1beta2.None) bool {
+	if _, ok := currentExclusionMap[processGroup.GetExclusionString()]; ok {
+		return true
+	}
+
+	for _, address := range processGroup.Addresses {
+		if _, ok := currentExclusionMap[address]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
 func canExcludeNewProcesses(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass) (bool, []fdbv1beta2.ProcessGroupID) {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "excludeProcesses")
 
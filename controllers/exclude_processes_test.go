@@ -24,10 +24,17 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"time"
 
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
@@ -352,6 +359,144 @@ var _ = Describe("exclude_processes", func() {
 	})
 })
 
+var _ = Describe("exclude_processes reconcile", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var adminClient *mock.AdminClient
+	var result *requeue
+
+	markForRemoval := func(processGroupID fdbv1beta2.ProcessGroupID) {
+		processGroup := fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID)
+		Expect(processGroup).NotTo(BeNil())
+		processGroup.MarkForRemoval()
+	}
+
+	getEventMessages := func(reason string) []string {
+		events := &corev1.EventList{}
+		Expect(k8sClient.List(context.TODO(), events, client.InNamespace(cluster.Namespace))).To(Succeed())
+
+		var messages []string
+		for _, event := range events.Items {
+			if event.Reason == reason {
+				messages = append(messages, event.Message)
+			}
+		}
+
+		return messages
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(setupClusterForTest(cluster)).To(Succeed())
+
+		var err error
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+		markForRemoval("storage-1")
+	})
+
+	When("the exclusion fails", func() {
+		BeforeEach(func() {
+			Expect(adminClient.InjectFault("ExcludeProcesses", mock.Fault{Error: fmt.Errorf("timeout")})).To(Succeed())
+			result = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should requeue with a delay", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.curError).To(MatchError("timeout"))
+			Expect(result.delayedRequeue).To(BeTrue())
+		})
+
+		It("should not exclude the process", func() {
+			Expect(adminClient.GetCallCount("ExcludeProcesses")).To(Equal(1))
+			Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+		})
+
+		It("should emit an event for the exclusion", func() {
+			Expect(getEventMessages("ExcludingProcesses")).To(HaveLen(1))
+		})
+
+		It("should return the error when processing the requeue", func() {
+			_, err := processRequeue(result, excludeProcesses{}, cluster, clusterReconciler.Recorder, logr.Discard())
+			Expect(err).To(MatchError("timeout"))
+			Expect(getEventMessages("ReconciliationTerminatedEarly")).To(ConsistOf("timeout"))
+		})
+
+		When("the exclusion is retried after the fault is cleared", func() {
+			BeforeEach(func() {
+				adminClient.ClearFaults()
+				result = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+			})
+
+			It("should exclude the process", func() {
+				Expect(result).To(BeNil())
+				Expect(adminClient.GetCallCount("ExcludeProcesses")).To(Equal(2))
+				Expect(adminClient.ExcludedAddresses).NotTo(BeEmpty())
+			})
+		})
+	})
+
+	When("the exclusion fails with a conflict", func() {
+		BeforeEach(func() {
+			conflict := k8serrors.NewConflict(schema.GroupResource{Group: fdbv1beta2.GroupVersion.Group, Resource: "foundationdbclusters"}, cluster.Name, fmt.Errorf("conflict"))
+			Expect(adminClient.InjectFault("ExcludeProcesses", mock.Fault{Error: conflict})).To(Succeed())
+			result = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should requeue after a minute without an error when processing the requeue", func() {
+			Expect(result).NotTo(BeNil())
+			res, err := processRequeue(result, excludeProcesses{}, cluster, clusterReconciler.Recorder, logr.Discard())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Requeue).To(BeTrue())
+			Expect(res.RequeueAfter).To(Equal(time.Minute))
+		})
+	})
+
+	When("the processes of another fault domain are excluded", func() {
+		BeforeEach(func() {
+			Expect(excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+
+			markForRemoval("log-1")
+			result = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should wait until the excluded process group is removed", func() {
+			Expect(result).To(BeNil())
+			Expect(adminClient.GetCallCount("ExcludeProcesses")).To(Equal(1))
+			Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+		})
+	})
+
+	When("only the second exclusion fails", func() {
+		BeforeEach(func() {
+			// Allow the exclusion of the next fault domain before the first
+			// process group is removed.
+			cluster.Spec.AutomationOptions.DeletionMode = fdbv1beta2.PodUpdateModeAll
+			Expect(adminClient.InjectFault("ExcludeProcesses", mock.Fault{Error: fmt.Errorf("timeout"), Skip: 1, Times: 1})).To(Succeed())
+			Expect(excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+
+			markForRemoval("log-1")
+			result = excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)
+		})
+
+		It("should requeue with the error of the second call", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.curError).To(MatchError("timeout"))
+			Expect(result.delayedRequeue).To(BeTrue())
+			Expect(adminClient.GetCallCount("ExcludeProcesses")).To(Equal(2))
+			Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+			Expect(getEventMessages("ExcludingProcesses")).To(HaveLen(2))
+		})
+
+		It("should exclude the process when retrying", func() {
+			Expect(excludeProcesses{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(adminClient.GetCallCount("ExcludeProcesses")).To(Equal(3))
+			Expect(adminClient.ExcludedAddresses).To(HaveLen(2))
+		})
+	})
+})
+
 func createMissingProcesses(cluster *fdbv1beta2.FoundationDBCluster, count int, processClass fdbv1beta2.ProcessClass) {
 	missing := 0
 	for _, processGroup := range cluster.Status.ProcessGroups {
@@ -104,7 +104,7 @@ func (g generateInitialClusterFile) reconcile(ctx context.Context, r *Foundation
 
 	processLocality := make([]locality.Info, 0, len(pods))
 	for _, pod := range pods {
-		client, message := r.getPodClient(cluster, pod)
+		client, message := r.getPodClient(ctx, cluster, pod)
 		if client == nil {
 			return &requeue{message: message, delay: podSchedulingDelayDuration}
 		}
@@ -0,0 +1,320 @@
+/*
+ * inject_chaos.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// injectChaos provides a reconciliation step for injecting the faults of a
+// running chaos experiment. This also ends the experiment once its duration
+// has passed, and aborts it if the database becomes unavailable.
+type injectChaos struct{}
+
+// reconcile runs the reconciler's work.
+func (i injectChaos) reconcile(ctx context.Context, r *FoundationDBChaosReconciler, chaos *fdbv1beta2.FoundationDBChaos) *requeue {
+	if chaos.GetPhase() != fdbv1beta2.ChaosPhaseRunning {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", chaos.Namespace, "chaos", chaos.Name, "reconciler", "injectChaos")
+
+	cluster, adminClient, err := r.adminClientForChaos(ctx, chaos)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	originalStatus := chaos.Status.DeepCopy()
+	report := &chaos.Status.Report
+
+	faultTolerance := status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability
+	if status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData < faultTolerance {
+		faultTolerance = status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData
+	}
+
+	if report.MinimumFaultTolerance == nil || faultTolerance < *report.MinimumFaultTolerance {
+		report.MinimumFaultTolerance = pointer.Int(faultTolerance)
+	}
+
+	now := time.Now()
+	if !status.Client.DatabaseStatus.Available {
+		logger.Info("Aborting chaos experiment because the database is unavailable")
+		chaos.Status.Phase = fdbv1beta2.ChaosPhaseAborted
+		report.LostAvailability = true
+		report.Message = fmt.Sprintf("Aborted after %d injections because the database became unavailable", report.Injections)
+		r.Recorder.Event(chaos, corev1.EventTypeWarning, "ChaosAborted", report.Message)
+	} else if !now.Before(*chaos.GetEndTime()) {
+		logger.Info("Chaos experiment has reached its duration")
+		chaos.Status.Phase = fdbv1beta2.ChaosPhaseCompleted
+		report.Message = fmt.Sprintf("Completed after %d injections", report.Injections)
+		r.Recorder.Event(chaos, corev1.EventTypeNormal, "ChaosCompleted", report.Message)
+	} else if chaos.NeedsInjection(now) {
+		injectionTime := metav1.NewTime(now)
+
+		// A fault is only injected into a cluster that can tolerate it.
+		// Faults that were injected before stay in place, so the
+		// experiment can observe how the cluster recovers from them.
+		if !internal.HasDesiredFaultToleranceFromStatus(logger, status, cluster) {
+			report.SkippedInjections++
+			r.Recorder.Event(chaos, corev1.EventTypeNormal, "ChaosInjectionSkipped", "Skipping injection because the cluster doesn't have the desired fault tolerance")
+		} else {
+			// Every injection creates its own resources. The resources of
+			// an earlier attempt of the same injection are removed first,
+			// so the report only contains the faults that are in place.
+			injection := report.Injections + 1
+			remaining, err := removeChaosObjects(ctx, r, logger, chaos, internal.GetChaosInjectionLabels(chaos, injection))
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			if remaining {
+				return &requeue{message: fmt.Sprintf("Waiting for the resources of an earlier attempt of injection %d to be removed", injection), delayedRequeue: true}
+			}
+
+			targets, err := injectChaosFault(ctx, r, adminClient, chaos, cluster, status, injection)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			chaos.Status.Targets = targets
+			report.Injections++
+
+			targetNames := make([]string, 0, len(targets))
+			for _, target := range targets {
+				targetNames = append(targetNames, string(target))
+			}
+
+			logger.Info("Injected chaos", "action", chaos.Spec.Action, "targets", targets)
+			r.Recorder.Event(chaos, corev1.EventTypeNormal, "ChaosInjected", fmt.Sprintf("Injected %s into process groups %s", chaos.Spec.Action, strings.Join(targetNames, ", ")))
+		}
+
+		chaos.Status.LastInjectionTime = &injectionTime
+	}
+
+	if !equality.Semantic.DeepEqual(chaos.Status, *originalStatus) {
+		err = r.updateOrApply(ctx, chaos)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
+
+// injectChaosFault injects the fault of a chaos experiment and returns the
+// process groups that were targeted.
+func injectChaosFault(ctx context.Context, r *FoundationDBChaosReconciler, adminClient fdbadminclient.AdminClient, chaos *fdbv1beta2.FoundationDBChaos, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, injection int) ([]fdbv1beta2.ProcessGroupID, error) {
+	switch chaos.Spec.Action {
+	case fdbv1beta2.ChaosActionKillProcesses:
+		targets, err := selectChaosTargets(chaos, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		targetSet := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(targets))
+		for _, target := range targets {
+			targetSet[target] = fdbv1beta2.None{}
+		}
+
+		var addresses []fdbv1beta2.ProcessAddress
+		for _, process := range status.Cluster.Processes {
+			if _, ok := targetSet[fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])]; ok {
+				addresses = append(addresses, process.Address)
+			}
+		}
+
+		if len(addresses) == 0 {
+			return nil, fmt.Errorf("could not find any processes for process groups %v", targets)
+		}
+
+		err = adminClient.KillProcesses(addresses)
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionKillProcesses, audit.GetProcessAddressTargets(addresses), fmt.Sprintf("Chaos experiment %s kills processes", chaos.Name), err)
+
+		return targets, err
+	case fdbv1beta2.ChaosActionPartitionZone:
+		targets, err := selectChaosZone(chaos, cluster, status)
+		if err != nil {
+			return nil, err
+		}
+
+		policy, err := internal.GetChaosNetworkPolicy(chaos, cluster, targets, injection)
+		if err != nil {
+			return nil, err
+		}
+
+		return targets, r.Create(ctx, policy)
+	case fdbv1beta2.ChaosActionStallSidecars:
+		targets, err := selectChaosTargets(chaos, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		policy, err := internal.GetChaosNetworkPolicy(chaos, cluster, targets, injection)
+		if err != nil {
+			return nil, err
+		}
+
+		return targets, r.Create(ctx, policy)
+	case fdbv1beta2.ChaosActionDiskFull:
+		targets, err := selectChaosTargets(chaos, cluster)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, target := range targets {
+			pods := &corev1.PodList{}
+			err = r.List(ctx, pods, internal.GetSinglePodListOptions(cluster, target)...)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(pods.Items) != 1 {
+				return nil, fmt.Errorf("expected one pod for process group %s, found %d", target, len(pods.Items))
+			}
+
+			pod, err := internal.GetChaosDiskFillPod(chaos, cluster, &pods.Items[0], injection)
+			if err != nil {
+				return nil, err
+			}
+
+			err = r.Create(ctx, pod)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return targets, nil
+	}
+
+	return nil, fmt.Errorf("unknown chaos action %s", chaos.Spec.Action)
+}
+
+// selectChaosTargets picks random process groups that a fault is injected
+// into. Process groups that are being removed are never targeted.
+func selectChaosTargets(chaos *fdbv1beta2.FoundationDBChaos, cluster *fdbv1beta2.FoundationDBCluster) ([]fdbv1beta2.ProcessGroupID, error) {
+	candidates := make([]fdbv1beta2.ProcessGroupID, 0, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			continue
+		}
+
+		if chaos.Spec.ProcessClass != "" && processGroup.ProcessClass != chaos.Spec.ProcessClass {
+			continue
+		}
+
+		// Only stateful processes have a data volume that can be filled up.
+		if chaos.Spec.Action == fdbv1beta2.ChaosActionDiskFull && !processGroup.ProcessClass.IsStateful() {
+			continue
+		}
+
+		candidates = append(candidates, processGroup.ProcessGroupID)
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("cluster %s has no process groups that can be targeted", cluster.Name)
+	}
+
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	count := chaos.GetCount()
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+
+	targets := candidates[:count]
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i] < targets[j]
+	})
+
+	return targets, nil
+}
+
+// selectChaosZone picks the zone that is partitioned by a chaos experiment and
+// returns the process groups in that zone. This uses the zone from the spec
+// if it is set, and a random zone otherwise.
+func selectChaosZone(chaos *fdbv1beta2.FoundationDBChaos, cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus) ([]fdbv1beta2.ProcessGroupID, error) {
+	processGroupsByZone := map[string]map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	for _, process := range status.Cluster.Processes {
+		processGroupID := fdbv1beta2.ProcessGroupID(process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])
+		zone := process.Locality[fdbv1beta2.FDBLocalityZoneIDKey]
+		if processGroupID == "" || zone == "" || cluster.ProcessGroupIsBeingRemoved(processGroupID) {
+			continue
+		}
+
+		if _, ok := processGroupsByZone[zone]; !ok {
+			processGroupsByZone[zone] = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+		}
+
+		processGroupsByZone[zone][processGroupID] = fdbv1beta2.None{}
+	}
+
+	zone := chaos.Spec.ZoneID
+	if zone == "" {
+		zones := make([]string, 0, len(processGroupsByZone))
+		for candidate := range processGroupsByZone {
+			zones = append(zones, candidate)
+		}
+
+		if len(zones) > 0 {
+			sort.Strings(zones)
+			zone = zones[rand.Intn(len(zones))]
+		}
+	}
+
+	processGroups, ok := processGroupsByZone[zone]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s has no processes in zone %q", cluster.Name, zone)
+	}
+
+	targets := make([]fdbv1beta2.ProcessGroupID, 0, len(processGroups))
+	for processGroupID := range processGroups {
+		targets = append(targets, processGroupID)
+	}
+
+	sort.Slice(targets, func(i, j int) bool {
+		return targets[i] < targets[j]
+	})
+
+	return targets, nil
+}
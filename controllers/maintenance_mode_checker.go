@@ -23,7 +23,6 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 )
@@ -39,7 +38,7 @@ func (maintenanceModeChecker) reconcile(ctx context.Context, r *FoundationDBClus
 		return nil
 	}
 
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r.Client)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -86,7 +85,7 @@ func (maintenanceModeChecker) reconcile(ctx context.Context, r *FoundationDBClus
 			continue
 		}
 		// TODO: Also include deletion timestamp to make this logic more robust to account for the corner case of the process crash/restarts.
-		if process.UptimeSeconds < time.Since(cluster.Status.MaintenanceModeInfo.StartTimestamp.Time).Seconds() {
+		if process.UptimeSeconds < r.now().Sub(cluster.Status.MaintenanceModeInfo.StartTimestamp.Time).Seconds() {
 			delete(processGroupsToCheck, process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey])
 		} else {
 			return &requeue{message: fmt.Sprintf("Waiting for pod %s to be updated", process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey]), delayedRequeue: true}
@@ -103,6 +102,7 @@ func (maintenanceModeChecker) reconcile(ctx context.Context, r *FoundationDBClus
 	}
 	logger.Info("Switching off maintenance mode", "zone", maintenanceZone)
 	err = adminClient.ResetMaintenanceMode()
+	r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionResetMaintenanceMode, []string{maintenanceZone}, "All processes in the maintenance zone are up", err)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -0,0 +1,251 @@
+/*
+ * manage_storage_capacity.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+)
+
+// manageStorageCapacity expands the volumes of process groups that are running
+// low on disk space. If a volume can't be expanded, the reconciler increases
+// the storage process count instead.
+type manageStorageCapacity struct{}
+
+// reconcile runs the reconciler's work.
+func (m manageStorageCapacity) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "manageStorageCapacity")
+	if !cluster.GetStorageCapacityManagementEnabled() {
+		return nil
+	}
+
+	lowDiskSpace := make([]*fdbv1beta2.ProcessGroupStatus, 0)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() || processGroup.GetConditionTime(fdbv1beta2.LowDiskSpace) == nil {
+			continue
+		}
+
+		lowDiskSpace = append(lowDiskSpace, processGroup)
+	}
+
+	if len(lowDiskSpace) == 0 {
+		return nil
+	}
+
+	pvcs := &corev1.PersistentVolumeClaimList{}
+	err := r.List(ctx, pvcs, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	pvcMap := internal.CreatePVCMap(cluster, pvcs)
+
+	expansionAllowed := map[string]bool{}
+	needsMoreStorage := make([]fdbv1beta2.ProcessGroupID, 0)
+	for _, processGroup := range lowDiskSpace {
+		pvc, hasPVC := pvcMap[processGroup.ProcessGroupID]
+		if hasPVC {
+			allowed, err := volumeExpansionAllowed(ctx, r, logger, &pvc, expansionAllowed)
+			if err != nil {
+				return &requeue{curError: err}
+			}
+
+			if allowed {
+				if volumeResizeInProgress(&pvc) {
+					logger.Info("Waiting for volume resize", "processGroupID", processGroup.ProcessGroupID, "pvc", pvc.Name)
+					continue
+				}
+
+				currentSize := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+				newSize, canExpand := getExpandedVolumeSize(cluster, currentSize)
+				if canExpand {
+					logger.Info("Expanding volume", "processGroupID", processGroup.ProcessGroupID, "pvc", pvc.Name, "currentSize", currentSize.String(), "newSize", newSize.String())
+					pvc.Spec.Resources.Requests[corev1.ResourceStorage] = newSize
+					err = r.Update(ctx, &pvc)
+					if err != nil {
+						return &requeue{curError: err}
+					}
+
+					r.Recorder.Event(cluster, corev1.EventTypeNormal, "ExpandingVolume",
+						fmt.Sprintf("Expanding volume %s of process group %s from %s to %s because of low disk space", pvc.Name, processGroup.ProcessGroupID, currentSize.String(), newSize.String()))
+					continue
+				}
+			}
+		}
+
+		if processGroup.ProcessClass == fdbv1beta2.ProcessClassStorage {
+			needsMoreStorage = append(needsMoreStorage, processGroup.ProcessGroupID)
+			continue
+		}
+
+		logger.Info("Cannot increase storage capacity", "processGroupID", processGroup.ProcessGroupID, "processClass", processGroup.ProcessClass)
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "LowDiskSpace",
+			fmt.Sprintf("Process group %s is running low on disk space and its volume can't be expanded", processGroup.ProcessGroupID))
+	}
+
+	if len(needsMoreStorage) == 0 {
+		return nil
+	}
+
+	return scaleStorageProcesses(ctx, r, logger, cluster, needsMoreStorage)
+}
+
+// volumeExpansionAllowed returns true if the StorageClass of the PVC allows
+// volume expansion. The results are cached per StorageClass.
+func volumeExpansionAllowed(ctx context.Context, r *FoundationDBClusterReconciler, logger logr.Logger, pvc *corev1.PersistentVolumeClaim, cache map[string]bool) (bool, error) {
+	storageClassName := pointer.StringDeref(pvc.Spec.StorageClassName, "")
+	if storageClassName == "" {
+		return false, nil
+	}
+
+	allowed, ok := cache[storageClassName]
+	if ok {
+		return allowed, nil
+	}
+
+	storageClass := &storagev1.StorageClass{}
+	err := r.Get(ctx, client.ObjectKey{Name: storageClassName}, storageClass)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) && !k8serrors.IsForbidden(err) {
+			return false, err
+		}
+
+		logger.Info("Could not fetch StorageClass, assuming that volume expansion is not allowed", "storageClass", storageClassName, "error", err.Error())
+		cache[storageClassName] = false
+		return false, nil
+	}
+
+	allowed = pointer.BoolDeref(storageClass.AllowVolumeExpansion, false)
+	cache[storageClassName] = allowed
+
+	return allowed, nil
+}
+
+// volumeResizeInProgress returns true if the requested size of the PVC is not
+// yet reflected in its capacity.
+func volumeResizeInProgress(pvc *corev1.PersistentVolumeClaim) bool {
+	for _, condition := range pvc.Status.Conditions {
+		if condition.Type == corev1.PersistentVolumeClaimResizing || condition.Type == corev1.PersistentVolumeClaimFileSystemResizePending {
+			return true
+		}
+	}
+
+	requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+	capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+
+	return capacity.Cmp(requested) < 0
+}
+
+// getExpandedVolumeSize returns the new size of a volume, rounded up to the
+// next GiB and limited by the maximum volume size. The second return value
+// is false if the volume can't be expanded any further.
+func getExpandedVolumeSize(cluster *fdbv1beta2.FoundationDBCluster, currentSize resource.Quantity) (resource.Quantity, bool) {
+	gibibyte := int64(1024 * 1024 * 1024)
+	newBytes := currentSize.Value() * int64(100+cluster.GetVolumeExpansionPercent()) / 100
+	newBytes = (newBytes + gibibyte - 1) / gibibyte * gibibyte
+
+	maxVolumeSize := cluster.Spec.AutomationOptions.StorageCapacity.MaxVolumeSize
+	if maxVolumeSize != nil && newBytes >= maxVolumeSize.Value() {
+		if maxVolumeSize.Cmp(currentSize) <= 0 {
+			return currentSize, false
+		}
+
+		return maxVolumeSize.DeepCopy(), true
+	}
+
+	return *resource.NewQuantity(newBytes, resource.BinarySI), true
+}
+
+// scaleStorageProcesses increases the storage process count by one for every
+// process group that is running low on disk space and can't be expanded.
+func scaleStorageProcesses(ctx context.Context, r *FoundationDBClusterReconciler, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, processGroups []fdbv1beta2.ProcessGroupID) *requeue {
+	if cluster.Status.StorageScaling != nil {
+		nextScale := time.Unix(cluster.Status.StorageScaling.Timestamp, 0).Add(time.Duration(cluster.GetStorageScaleIntervalSeconds()) * time.Second)
+		if r.now().Before(nextScale) {
+			logger.Info("Waiting before increasing the storage process count again", "lastScale", cluster.Status.StorageScaling.Timestamp, "processGroups", processGroups)
+			return nil
+		}
+	}
+
+	counts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	currentCount := counts.Storage
+	storageProcessGroups := 0
+	for _, processGroup := range cluster.Status.ProcessGroupsByProcessClass(fdbv1beta2.ProcessClassStorage) {
+		if !processGroup.IsMarkedForRemoval() {
+			storageProcessGroups++
+		}
+	}
+
+	// Wait until the process groups of the last increase have been created.
+	if storageProcessGroups < currentCount {
+		logger.Info("Waiting for new storage process groups", "desired", currentCount, "current", storageProcessGroups)
+		return nil
+	}
+
+	newCount := currentCount + len(processGroups)
+	maxCount := cluster.Spec.AutomationOptions.StorageCapacity.MaxStorageProcesses
+	if maxCount != nil && newCount > *maxCount {
+		newCount = *maxCount
+	}
+
+	if newCount <= currentCount {
+		logger.Info("Cannot increase the storage process count", "current", currentCount, "processGroups", processGroups)
+		r.Recorder.Event(cluster, corev1.EventTypeWarning, "LowDiskSpace",
+			fmt.Sprintf("Process groups %v are running low on disk space and the storage process count can't be increased above %d", processGroups, currentCount))
+		return nil
+	}
+
+	logger.Info("Increasing the storage process count", "current", currentCount, "new", newCount, "processGroups", processGroups)
+	now := r.now()
+	err = r.updateProcessCounts(ctx, cluster, func(current *fdbv1beta2.FoundationDBCluster) {
+		current.SetProcessCount(fdbv1beta2.ProcessClassStorage, newCount)
+	}, func(status *fdbv1beta2.FoundationDBClusterStatus) {
+		status.StorageScaling = &fdbv1beta2.StorageScalingStatus{
+			Timestamp:     now.Unix(),
+			PreviousCount: currentCount,
+			Count:         newCount,
+			ProcessGroups: processGroups,
+		}
+	})
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "ScalingStorageProcesses",
+		fmt.Sprintf("Increasing the storage process count from %d to %d because process groups %v are running low on disk space", currentCount, newCount, processGroups))
+
+	return &requeue{message: "Increased the storage process count"}
+}
@@ -0,0 +1,256 @@
+/*
+ * manage_storage_capacity_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("manage_storage_capacity", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var result *requeue
+	var lowDiskSpaceProcessGroup fdbv1beta2.ProcessGroupID
+
+	getPVC := func(processGroupID fdbv1beta2.ProcessGroupID) *corev1.PersistentVolumeClaim {
+		pvcs := &corev1.PersistentVolumeClaimList{}
+		Expect(k8sClient.List(context.TODO(), pvcs, internal.GetSinglePodListOptions(cluster, processGroupID)...)).To(Succeed())
+		Expect(pvcs.Items).To(HaveLen(1))
+		return &pvcs.Items[0]
+	}
+
+	setStorageClass := func(processGroupID fdbv1beta2.ProcessGroupID, name string, allowVolumeExpansion bool) {
+		Expect(k8sClient.Create(context.TODO(), &storagev1.StorageClass{
+			ObjectMeta:           metav1.ObjectMeta{Name: name},
+			AllowVolumeExpansion: pointer.Bool(allowVolumeExpansion),
+		})).To(Succeed())
+
+		pvc := getPVC(processGroupID)
+		pvc.Spec.StorageClassName = pointer.String(name)
+		Expect(k8sClient.Update(context.TODO(), pvc)).To(Succeed())
+		pvc.Status.Capacity = corev1.ResourceList{
+			corev1.ResourceStorage: pvc.Spec.Resources.Requests[corev1.ResourceStorage],
+		}
+		Expect(k8sClient.Status().Update(context.TODO(), pvc)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.AutomationOptions.StorageCapacity.Enabled = pointer.Bool(true)
+		Expect(setupClusterForTest(cluster)).To(Succeed())
+		lowDiskSpaceProcessGroup = "storage-1"
+	})
+
+	JustBeforeEach(func() {
+		for _, processGroup := range cluster.Status.ProcessGroups {
+			if processGroup.ProcessGroupID == lowDiskSpaceProcessGroup {
+				processGroup.ProcessGroupConditions = append(processGroup.ProcessGroupConditions, fdbv1beta2.NewProcessGroupCondition(fdbv1beta2.LowDiskSpace))
+			}
+		}
+
+		result = manageStorageCapacity{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("no process group is running low on disk space", func() {
+		BeforeEach(func() {
+			lowDiskSpaceProcessGroup = ""
+		})
+
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+	})
+
+	When("the volume can be expanded", func() {
+		BeforeEach(func() {
+			setStorageClass(lowDiskSpaceProcessGroup, "expandable", true)
+		})
+
+		It("should expand the PVC", func() {
+			Expect(result).To(BeNil())
+			size := getPVC(lowDiskSpaceProcessGroup).Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(size.String()).To(Equal("179Gi"))
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+
+		It("should not change the other PVCs", func() {
+			size := getPVC("storage-2").Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(size.String()).To(Equal("128G"))
+		})
+
+		When("the storage capacity management is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.StorageCapacity.Enabled = nil
+			})
+
+			It("should not expand the PVC", func() {
+				Expect(result).To(BeNil())
+				size := getPVC(lowDiskSpaceProcessGroup).Spec.Resources.Requests[corev1.ResourceStorage]
+				Expect(size.String()).To(Equal("128G"))
+			})
+		})
+
+		When("a resize is in progress", func() {
+			BeforeEach(func() {
+				pvc := getPVC(lowDiskSpaceProcessGroup)
+				pvc.Status.Capacity = corev1.ResourceList{
+					corev1.ResourceStorage: resource.MustParse("100G"),
+				}
+				Expect(k8sClient.Status().Update(context.TODO(), pvc)).To(Succeed())
+			})
+
+			It("should not expand the PVC again", func() {
+				Expect(result).To(BeNil())
+				size := getPVC(lowDiskSpaceProcessGroup).Spec.Resources.Requests[corev1.ResourceStorage]
+				Expect(size.String()).To(Equal("128G"))
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+			})
+		})
+
+		When("the maximum volume size is reached", func() {
+			BeforeEach(func() {
+				maxVolumeSize := resource.MustParse("128G")
+				cluster.Spec.AutomationOptions.StorageCapacity.MaxVolumeSize = &maxVolumeSize
+			})
+
+			It("should increase the storage process count", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+				size := getPVC(lowDiskSpaceProcessGroup).Spec.Resources.Requests[corev1.ResourceStorage]
+				Expect(size.String()).To(Equal("128G"))
+			})
+		})
+
+		When("the maximum volume size is only slightly above the current size", func() {
+			BeforeEach(func() {
+				maxVolumeSize := resource.MustParse("150G")
+				cluster.Spec.AutomationOptions.StorageCapacity.MaxVolumeSize = &maxVolumeSize
+			})
+
+			It("should expand the PVC to the maximum size", func() {
+				Expect(result).To(BeNil())
+				size := getPVC(lowDiskSpaceProcessGroup).Spec.Resources.Requests[corev1.ResourceStorage]
+				Expect(size.String()).To(Equal("150G"))
+			})
+		})
+	})
+
+	When("the volume can't be expanded", func() {
+		BeforeEach(func() {
+			setStorageClass(lowDiskSpaceProcessGroup, "fixed", false)
+		})
+
+		It("should increase the storage process count", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.message).To(Equal("Increased the storage process count"))
+
+			fetchedCluster := &fdbv1beta2.FoundationDBCluster{}
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), fetchedCluster)).To(Succeed())
+			Expect(fetchedCluster.Spec.ProcessCounts.Storage).To(Equal(5))
+			Expect(fetchedCluster.Status.StorageScaling).NotTo(BeNil())
+			Expect(fetchedCluster.Status.StorageScaling.PreviousCount).To(Equal(4))
+			Expect(fetchedCluster.Status.StorageScaling.Count).To(Equal(5))
+			Expect(fetchedCluster.Status.StorageScaling.ProcessGroups).To(ConsistOf(lowDiskSpaceProcessGroup))
+		})
+
+		It("should not expand the PVC", func() {
+			size := getPVC(lowDiskSpaceProcessGroup).Spec.Resources.Requests[corev1.ResourceStorage]
+			Expect(size.String()).To(Equal("128G"))
+		})
+
+		When("the storage process count was increased recently", func() {
+			BeforeEach(func() {
+				cluster.Status.StorageScaling = &fdbv1beta2.StorageScalingStatus{
+					Timestamp:     time.Now().Add(-1 * time.Minute).Unix(),
+					PreviousCount: 3,
+					Count:         4,
+				}
+			})
+
+			It("should not increase the storage process count", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+			})
+		})
+
+		When("the storage process count was increased before the scale interval", func() {
+			BeforeEach(func() {
+				cluster.Status.StorageScaling = &fdbv1beta2.StorageScalingStatus{
+					Timestamp:     time.Now().Add(-2 * time.Hour).Unix(),
+					PreviousCount: 3,
+					Count:         4,
+				}
+			})
+
+			It("should increase the storage process count", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+			})
+		})
+
+		When("the new storage process groups are not created yet", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+			})
+
+			It("should not increase the storage process count", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+			})
+		})
+
+		When("the maximum number of storage processes is reached", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.StorageCapacity.MaxStorageProcesses = pointer.Int(4)
+			})
+
+			It("should not increase the storage process count", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+			})
+		})
+	})
+
+	When("a log process is running low on disk space", func() {
+		BeforeEach(func() {
+			lowDiskSpaceProcessGroup = "log-1"
+			setStorageClass(lowDiskSpaceProcessGroup, "fixed", false)
+		})
+
+		It("should not increase the storage process count", func() {
+			Expect(result).To(BeNil())
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+		})
+	})
+})
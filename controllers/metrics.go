@@ -25,6 +25,7 @@ import (
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 )
 
@@ -100,6 +101,55 @@ var (
 		append(descClusterDefaultLabels, "process_class"),
 		nil,
 	)
+
+	descBackupStatus = prometheus.NewDesc(
+		"fdb_operator_backup_status",
+		"status of the Fdb backup.",
+		append(descClusterDefaultLabels, "status_type"),
+		nil,
+	)
+
+	descBackupSecondsBehind = prometheus.NewDesc(
+		"fdb_operator_backup_seconds_behind",
+		"the number of seconds that the latest restorable version of the Fdb backup is behind the cluster.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupLatestRestorableTime = prometheus.NewDesc(
+		"fdb_operator_backup_latest_restorable_time",
+		"the time in unix timestamp of the latest restorable version of the Fdb backup.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupSnapshotProgress = prometheus.NewDesc(
+		"fdb_operator_backup_snapshot_progress_percent",
+		"the expected progress of the current snapshot of the Fdb backup.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupErrors = prometheus.NewDesc(
+		"fdb_operator_backup_errors_total",
+		"the count of recent errors reported by the agents of the Fdb backup.",
+		descClusterDefaultLabels,
+		nil,
+	)
+
+	descBackupAgents = prometheus.NewDesc(
+		"fdb_operator_backup_agents_total",
+		"the count of ready and desired agents of the Fdb backup.",
+		append(descClusterDefaultLabels, "agent_state"),
+		nil,
+	)
+
+	descBackupCondition = prometheus.NewDesc(
+		"fdb_operator_backup_condition",
+		"the conditions of the Fdb backup.",
+		append(descClusterDefaultLabels, "condition"),
+		nil,
+	)
 )
 
 type fdbClusterCollector struct {
@@ -169,6 +219,66 @@ func collectMetrics(ch chan<- prometheus.Metric, cluster *fdbv1beta2.FoundationD
 	}
 }
 
+type fdbBackupCollector struct {
+	reconciler *FoundationDBBackupReconciler
+}
+
+func newFDBBackupCollector(reconciler *FoundationDBBackupReconciler) *fdbBackupCollector {
+	return &fdbBackupCollector{reconciler: reconciler}
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *fdbBackupCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descBackupStatus
+	ch <- descBackupSecondsBehind
+	ch <- descBackupLatestRestorableTime
+	ch <- descBackupSnapshotProgress
+	ch <- descBackupErrors
+	ch <- descBackupAgents
+	ch <- descBackupCondition
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *fdbBackupCollector) Collect(ch chan<- prometheus.Metric) {
+	backups := &fdbv1beta2.FoundationDBBackupList{}
+	err := c.reconciler.List(context.Background(), backups)
+	if err != nil {
+		return
+	}
+	for _, backup := range backups.Items {
+		collectBackupMetrics(ch, &backup)
+	}
+}
+
+func collectBackupMetrics(ch chan<- prometheus.Metric, backup *fdbv1beta2.FoundationDBBackup) {
+	addGauge := func(desc *prometheus.Desc, v float64, lv ...string) {
+		lv = append([]string{backup.Namespace, backup.Name}, lv...)
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, lv...)
+	}
+
+	addGauge(descBackupAgents, float64(backup.Status.AgentCount), "ready")
+	addGauge(descBackupAgents, float64(backup.GetDesiredAgentCount()), "desired")
+
+	for _, condition := range backup.Status.Conditions {
+		addGauge(descBackupCondition, boolFloat64(condition.Status == metav1.ConditionTrue), condition.Type)
+	}
+
+	details := backup.Status.BackupDetails
+	if details == nil {
+		return
+	}
+
+	addGauge(descBackupStatus, boolFloat64(details.Running), "running")
+	addGauge(descBackupStatus, boolFloat64(details.Paused), "paused")
+	addGauge(descBackupSecondsBehind, float64(details.SecondsBehind))
+	addGauge(descBackupSnapshotProgress, float64(details.SnapshotProgressPercent))
+	addGauge(descBackupErrors, float64(len(details.Errors)))
+
+	if details.LatestRestorableTime != nil {
+		addGauge(descBackupLatestRestorableTime, float64(details.LatestRestorableTime.Unix()))
+	}
+}
+
 func getProcessGroupMetrics(cluster *fdbv1beta2.FoundationDBCluster) (map[fdbv1beta2.ProcessClass]map[fdbv1beta2.ProcessGroupConditionType]int, map[fdbv1beta2.ProcessClass]int, map[fdbv1beta2.ProcessClass]int) {
 	metricMap := map[fdbv1beta2.ProcessClass]map[fdbv1beta2.ProcessGroupConditionType]int{}
 	removals := map[fdbv1beta2.ProcessClass]int{}
@@ -221,6 +331,13 @@ func InitCustomMetrics(reconciler *FoundationDBClusterReconciler) {
 	)
 }
 
+// InitBackupMetrics initializes the metrics collectors for the backups.
+func InitBackupMetrics(reconciler *FoundationDBBackupReconciler) {
+	metrics.Registry.MustRegister(
+		newFDBBackupCollector(reconciler),
+	)
+}
+
 func boolFloat64(b bool) float64 {
 	if b {
 		return 1
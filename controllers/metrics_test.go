@@ -23,6 +23,8 @@ package controllers
 import (
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -75,4 +77,42 @@ var _ = Describe("metrics", func() {
 			Expect(exclusions[fdbv1beta2.ProcessClassStateless]).To(BeNumerically("==", 1))
 		})
 	})
+
+	Context("Collecting the backup metrics", func() {
+		It("generate the backup metrics", func() {
+			backup := &fdbv1beta2.FoundationDBBackup{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "backup",
+					Namespace: "default",
+				},
+				Status: fdbv1beta2.FoundationDBBackupStatus{
+					AgentCount: 2,
+					BackupDetails: &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
+						Running:              true,
+						SecondsBehind:        30,
+						LatestRestorableTime: &metav1.Time{Time: time.Now()},
+					},
+					Conditions: []metav1.Condition{
+						{
+							Type:   string(fdbv1beta2.BackupLagging),
+							Status: metav1.ConditionTrue,
+						},
+					},
+				},
+			}
+
+			ch := make(chan prometheus.Metric, 20)
+			collectBackupMetrics(ch, backup)
+			close(ch)
+
+			var names []string
+			for metric := range ch {
+				names = append(names, metric.Desc().String())
+			}
+
+			// ready and desired agents, one condition, running and paused,
+			// lag, snapshot progress, errors and the restorable time.
+			Expect(names).To(HaveLen(9))
+		})
+	})
 })
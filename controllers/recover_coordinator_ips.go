@@ -0,0 +1,226 @@
+/*
+ * recover_coordinator_ips.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+)
+
+// coordinatorIPRecoveryConfirmationDelay defines how long the operator waits
+// after it observed a coordinator IP update before it updates the cluster
+// file. The update is only done if it is observed again after this delay.
+const coordinatorIPRecoveryConfirmationDelay = 30 * time.Second
+
+// recoverCoordinatorIPs updates the coordinator IPs in the cluster file when
+// the coordinators are unreachable because their Pods got new IP addresses.
+type recoverCoordinatorIPs struct{}
+
+// reconcile runs the reconciler's work.
+func (recoverCoordinatorIPs) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if !cluster.GetFixCoordinatorIPs() || !cluster.Status.Configured || cluster.Status.ConnectionString == "" {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "recoverCoordinatorIPs")
+
+	adminClient, err := r.getAdminClient(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	status, err := adminClient.GetStatus()
+	_ = adminClient.Close()
+	if err != nil {
+		// An error doesn't mean that the coordinators are unreachable, e.g. the
+		// status request could time out during a recovery.
+		logger.Info("Could not fetch the status to check the coordinator quorum", "error", err.Error())
+		return nil
+	}
+
+	if !coordinatorQuorumLost(status) {
+		return clearPendingCoordinatorIPRecovery(ctx, r, cluster)
+	}
+
+	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	pods, err := r.PodLifecycleManager.GetPods(ctx, r, cluster, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	changes, err := internal.GetCoordinatorIPChanges(cluster, connectionString, getCurrentIPs(logger, cluster, pods))
+	if err != nil {
+		clearRequeue := clearPendingCoordinatorIPRecovery(ctx, r, cluster)
+		if clearRequeue != nil {
+			return clearRequeue
+		}
+
+		return &requeue{message: fmt.Sprintf("cannot update the coordinator IPs: %s", err.Error()), delayedRequeue: true}
+	}
+
+	// If the coordinators that kept their IP addresses could form a quorum,
+	// the new IP addresses don't explain why the quorum is lost and updating
+	// the cluster file would only restart all processes.
+	if len(connectionString.Coordinators)-len(changes) > len(connectionString.Coordinators)/2 {
+		logger.Info("Coordinator quorum is lost but not enough coordinators got new IPs", "changes", changes)
+		return clearPendingCoordinatorIPRecovery(ctx, r, cluster)
+	}
+
+	if r.PodCommandExecutor == nil {
+		return &requeue{message: "cannot update the coordinator IPs without a pod command executor", delayedRequeue: true}
+	}
+
+	previousConnectionString := connectionString.String()
+	internal.ApplyCoordinatorIPChanges(&connectionString, changes)
+	newConnectionString := connectionString.String()
+
+	// The cluster file is only updated if the same update was observed in an
+	// earlier reconciliation to prevent that a short glitch restarts all
+	// processes.
+	pending := cluster.Status.PendingCoordinatorIPRecovery
+	if pending == nil || pending.PreviousConnectionString != previousConnectionString || pending.ConnectionString != newConnectionString {
+		logger.Info("Observed new coordinator IPs, waiting for confirmation", "previousConnectionString", previousConnectionString, "connectionString", newConnectionString, "changes", changes)
+		cluster.Status.PendingCoordinatorIPRecovery = &fdbv1beta2.CoordinatorIPRecoveryStatus{
+			Timestamp:                r.now().Unix(),
+			PreviousConnectionString: previousConnectionString,
+			ConnectionString:         newConnectionString,
+			Coordinators:             changes,
+		}
+
+		err = r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		return &requeue{message: "waiting to confirm the new coordinator IPs", delayedRequeue: true}
+	}
+
+	if r.now().Sub(time.Unix(pending.Timestamp, 0)) < coordinatorIPRecoveryConfirmationDelay {
+		return &requeue{message: "waiting to confirm the new coordinator IPs", delayedRequeue: true}
+	}
+
+	logger.Info("Updating coordinator IPs", "previousConnectionString", previousConnectionString, "connectionString", newConnectionString, "changes", changes)
+	r.Recorder.Event(cluster, corev1.EventTypeNormal, "RecoveringCoordinatorIPs", fmt.Sprintf("Updating the coordinator IPs in the cluster file from %s to %s", previousConnectionString, newConnectionString))
+
+	// This is the same update that is done by the kubectl fdb fix-coordinator-ips
+	// command: the fdbserver processes only read the cluster file during start up.
+	command := []string{"bash", "-c", fmt.Sprintf("echo %s > /var/fdb/data/fdb.cluster && pkill fdbserver", newConnectionString)}
+	updatedPods := make([]string, 0, len(pods))
+	var stderr string
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		_, stderr, err = r.PodCommandExecutor.ExecuteCommand(ctx, pod, fdbv1beta2.MainContainerName, command)
+		if err != nil {
+			logger.Error(err, "Could not update cluster file", "pod", pod.Name, "stderr", stderr)
+			break
+		}
+		updatedPods = append(updatedPods, pod.Name)
+	}
+
+	r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionChangeCoordinators, connectionString.Coordinators, "Coordinator Pods got new IP addresses", err)
+	if len(updatedPods) > 0 {
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionKillProcesses, updatedPods, "Processes are restarted to read the updated cluster file", nil)
+	}
+
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	cluster.Status.ConnectionString = newConnectionString
+	cluster.Status.PendingCoordinatorIPRecovery = nil
+	cluster.Status.CoordinatorIPRecovery = &fdbv1beta2.CoordinatorIPRecoveryStatus{
+		Timestamp:                r.now().Unix(),
+		PreviousConnectionString: previousConnectionString,
+		ConnectionString:         newConnectionString,
+		Coordinators:             changes,
+	}
+
+	err = r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// clearPendingCoordinatorIPRecovery removes the pending coordinator IP update
+// from the status.
+func clearPendingCoordinatorIPRecovery(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	if cluster.Status.PendingCoordinatorIPRecovery == nil {
+		return nil
+	}
+
+	cluster.Status.PendingCoordinatorIPRecovery = nil
+	err := r.updateOrApply(ctx, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// coordinatorQuorumLost returns true if the status reports that the client
+// can't reach a quorum of the coordinators.
+func coordinatorQuorumLost(status *fdbv1beta2.FoundationDBStatus) bool {
+	if status.Client.Coordinators.QuorumReachable {
+		return false
+	}
+
+	coordinators := status.Client.Coordinators.Coordinators
+	reachable := 0
+	for _, coordinator := range coordinators {
+		if coordinator.Reachable {
+			reachable++
+		}
+	}
+
+	return reachable <= len(coordinators)/2
+}
+
+// getCurrentIPs returns the public IP addresses of the running Pods by their
+// process group.
+func getCurrentIPs(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pods []*corev1.Pod) map[fdbv1beta2.ProcessGroupID][]string {
+	currentIPs := make(map[fdbv1beta2.ProcessGroupID][]string, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodRunning || !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		currentIPs[podmanager.GetProcessGroupID(cluster, pod)] = podmanager.GetPublicIPs(pod, logger)
+	}
+
+	return currentIPs
+}
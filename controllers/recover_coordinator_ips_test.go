@@ -0,0 +1,429 @@
+/*
+ * recover_coordinator_ips_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// mockPodCommandExecutor records the commands that should be executed.
+type mockPodCommandExecutor struct {
+	commands map[string][]string
+
+	// failAfter defines after how many successful commands all further
+	// commands fail. A negative value means that no command fails.
+	failAfter int
+}
+
+// ExecuteCommand records the command for the Pod.
+func (executor *mockPodCommandExecutor) ExecuteCommand(_ context.Context, pod *corev1.Pod, _ string, command []string) (string, string, error) {
+	if executor.failAfter >= 0 && len(executor.commands) >= executor.failAfter {
+		return "", "pkill failed", fmt.Errorf("command failed in pod %s", pod.Name)
+	}
+
+	executor.commands[pod.Name] = command
+	return "", "", nil
+}
+
+var _ = Describe("recover_coordinator_ips", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var executor *mockPodCommandExecutor
+	var result *requeue
+	var originalConnectionString string
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.AutomationOptions.FixCoordinatorIPs = pointer.Bool(true)
+		Expect(setupClusterForTest(cluster)).To(Succeed())
+		originalConnectionString = cluster.Status.ConnectionString
+
+		executor = &mockPodCommandExecutor{commands: map[string][]string{}, failAfter: -1}
+		clusterReconciler.PodCommandExecutor = executor
+	})
+
+	AfterEach(func() {
+		clusterReconciler.PodCommandExecutor = nil
+	})
+
+	JustBeforeEach(func() {
+		result = recoverCoordinatorIPs{}.reconcile(context.TODO(), clusterReconciler, cluster)
+	})
+
+	When("the coordinators are reachable", func() {
+		It("should not requeue", func() {
+			Expect(result).To(BeNil())
+		})
+
+		It("should not change the connection string", func() {
+			Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			Expect(cluster.Status.CoordinatorIPRecovery).To(BeNil())
+			Expect(executor.commands).To(BeEmpty())
+		})
+	})
+
+	When("the coordinator Pods got new IP addresses", func() {
+		var newIPs map[fdbv1beta2.ProcessGroupID]string
+		var newConnectionString string
+
+		BeforeEach(func() {
+			newIPs = map[fdbv1beta2.ProcessGroupID]string{}
+			connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+			Expect(err).NotTo(HaveOccurred())
+
+			coordinatorIPs := map[string]bool{}
+			for _, coordinator := range connectionString.Coordinators {
+				address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+				Expect(err).NotTo(HaveOccurred())
+				coordinatorIPs[address.IPAddress.String()] = true
+			}
+
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), pods)).To(Succeed())
+			newConnectionString = cluster.Status.ConnectionString
+			for index, pod := range pods.Items {
+				if !coordinatorIPs[pod.Status.PodIP] {
+					continue
+				}
+
+				newIP := fmt.Sprintf("10.1.0.%d", index)
+				newIPs[fdbv1beta2.ProcessGroupID(pod.Labels[fdbv1beta2.FDBProcessGroupIDLabel])] = newIP
+				newConnectionString = strings.Replace(newConnectionString, pod.Status.PodIP+":", newIP+":", 1)
+				pod.Status.PodIP = newIP
+				pod.Status.PodIPs = []corev1.PodIP{{IP: newIP}}
+				Expect(k8sClient.Status().Update(context.TODO(), &pod)).To(Succeed())
+			}
+
+			Expect(newIPs).To(HaveLen(len(connectionString.Coordinators)))
+		})
+
+		When("the update was not observed before", func() {
+			It("should wait for the confirmation", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(Equal("waiting to confirm the new coordinator IPs"))
+				Expect(executor.commands).To(BeEmpty())
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			})
+
+			It("should record the pending update", func() {
+				Expect(cluster.Status.PendingCoordinatorIPRecovery).NotTo(BeNil())
+				Expect(cluster.Status.PendingCoordinatorIPRecovery.PreviousConnectionString).To(Equal(originalConnectionString))
+				Expect(cluster.Status.PendingCoordinatorIPRecovery.ConnectionString).To(Equal(newConnectionString))
+				Expect(cluster.Status.PendingCoordinatorIPRecovery.Timestamp).NotTo(BeZero())
+
+				fetchedCluster := &fdbv1beta2.FoundationDBCluster{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), fetchedCluster)).To(Succeed())
+				Expect(fetchedCluster.Status.PendingCoordinatorIPRecovery).To(Equal(cluster.Status.PendingCoordinatorIPRecovery))
+			})
+
+			It("should keep the pending update when the status is updated", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.PendingCoordinatorIPRecovery).NotTo(BeNil())
+				Expect(cluster.Status.PendingCoordinatorIPRecovery.ConnectionString).To(Equal(newConnectionString))
+			})
+		})
+
+		When("the update was observed before the confirmation delay passed", func() {
+			BeforeEach(func() {
+				cluster.Status.PendingCoordinatorIPRecovery = &fdbv1beta2.CoordinatorIPRecoveryStatus{
+					Timestamp:                time.Now().Unix(),
+					PreviousConnectionString: originalConnectionString,
+					ConnectionString:         newConnectionString,
+				}
+			})
+
+			It("should wait for the confirmation", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(executor.commands).To(BeEmpty())
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			})
+		})
+
+		When("a different update was observed before", func() {
+			BeforeEach(func() {
+				cluster.Status.PendingCoordinatorIPRecovery = &fdbv1beta2.CoordinatorIPRecoveryStatus{
+					Timestamp:                time.Now().Add(-time.Hour).Unix(),
+					PreviousConnectionString: originalConnectionString,
+					ConnectionString:         originalConnectionString,
+				}
+			})
+
+			It("should record the new update and wait for the confirmation", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(executor.commands).To(BeEmpty())
+				Expect(cluster.Status.PendingCoordinatorIPRecovery.ConnectionString).To(Equal(newConnectionString))
+				Expect(time.Since(time.Unix(cluster.Status.PendingCoordinatorIPRecovery.Timestamp, 0))).To(BeNumerically("<", time.Minute))
+			})
+		})
+
+		When("the status can't be fetched", func() {
+			BeforeEach(func() {
+				cluster.Status.PendingCoordinatorIPRecovery = &fdbv1beta2.CoordinatorIPRecoveryStatus{
+					Timestamp:                time.Now().Add(-time.Hour).Unix(),
+					PreviousConnectionString: originalConnectionString,
+					ConnectionString:         newConnectionString,
+				}
+
+				adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(adminClient.InjectFault("GetStatus", mock.Fault{Error: fmt.Errorf("timeout")})).To(Succeed())
+			})
+
+			AfterEach(func() {
+				adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+				Expect(err).NotTo(HaveOccurred())
+				adminClient.ClearFaults()
+			})
+
+			It("should not update the cluster file", func() {
+				Expect(result).To(BeNil())
+				Expect(executor.commands).To(BeEmpty())
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			})
+		})
+
+		When("the update was confirmed", func() {
+			BeforeEach(func() {
+				cluster.Status.PendingCoordinatorIPRecovery = &fdbv1beta2.CoordinatorIPRecoveryStatus{
+					Timestamp:                time.Now().Add(-time.Minute).Unix(),
+					PreviousConnectionString: originalConnectionString,
+					ConnectionString:         newConnectionString,
+				}
+			})
+
+			It("should not requeue", func() {
+				Expect(result).To(BeNil())
+			})
+
+			It("should update the connection string", func() {
+				Expect(cluster.Status.ConnectionString).To(Equal(newConnectionString))
+				for _, ip := range newIPs {
+					Expect(cluster.Status.ConnectionString).To(ContainSubstring(ip + ":4501"))
+				}
+
+				fetchedCluster := &fdbv1beta2.FoundationDBCluster{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(cluster), fetchedCluster)).To(Succeed())
+				Expect(fetchedCluster.Status.ConnectionString).To(Equal(cluster.Status.ConnectionString))
+				Expect(fetchedCluster.Status.PendingCoordinatorIPRecovery).To(BeNil())
+			})
+
+			It("should record the mapping of the coordinators", func() {
+				Expect(cluster.Status.CoordinatorIPRecovery).NotTo(BeNil())
+				Expect(cluster.Status.CoordinatorIPRecovery.PreviousConnectionString).To(Equal(originalConnectionString))
+				Expect(cluster.Status.CoordinatorIPRecovery.ConnectionString).To(Equal(cluster.Status.ConnectionString))
+				Expect(cluster.Status.CoordinatorIPRecovery.Timestamp).NotTo(BeZero())
+				Expect(cluster.Status.CoordinatorIPRecovery.Coordinators).To(HaveLen(len(newIPs)))
+				for _, change := range cluster.Status.CoordinatorIPRecovery.Coordinators {
+					Expect(change.Address).To(Equal(newIPs[change.ProcessGroupID] + ":4501"))
+					Expect(originalConnectionString).To(ContainSubstring(change.PreviousAddress))
+				}
+			})
+
+			It("should keep the record when the status is updated", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.CoordinatorIPRecovery).NotTo(BeNil())
+				Expect(cluster.Status.CoordinatorIPRecovery.ConnectionString).To(Equal(cluster.Status.ConnectionString))
+			})
+
+			It("should update the cluster file in all Pods", func() {
+				pods := &corev1.PodList{}
+				Expect(k8sClient.List(context.TODO(), pods)).To(Succeed())
+				Expect(executor.commands).To(HaveLen(len(pods.Items)))
+				for _, command := range executor.commands {
+					Expect(strings.Join(command, " ")).To(ContainSubstring(fmt.Sprintf("echo %s > /var/fdb/data/fdb.cluster", cluster.Status.ConnectionString)))
+				}
+			})
+
+			When("the audit log is enabled", func() {
+				BeforeEach(func() {
+					clusterReconciler.AuditRecorder = audit.NewRecorder(logr.Discard(), "test-operator", audit.NewKubernetesSink(k8sClient))
+				})
+
+				AfterEach(func() {
+					clusterReconciler.AuditRecorder = nil
+				})
+
+				It("should record the coordinator change and the restarted processes", func() {
+					events := &fdbv1beta2.FoundationDBAuditEventList{}
+					Expect(k8sClient.List(context.TODO(), events, client.InNamespace(cluster.Namespace), client.MatchingLabels{fdbv1beta2.FDBClusterLabel: cluster.Name})).To(Succeed())
+					Expect(events.Items).To(HaveLen(2))
+
+					entries := map[fdbv1beta2.AuditAction]fdbv1beta2.AuditEntry{}
+					for _, event := range events.Items {
+						entries[event.Spec.Action] = event.Spec
+					}
+
+					connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(entries).To(HaveKey(fdbv1beta2.AuditActionChangeCoordinators))
+					Expect(entries[fdbv1beta2.AuditActionChangeCoordinators].Outcome).To(Equal(fdbv1beta2.AuditOutcomeSucceeded))
+					Expect(entries[fdbv1beta2.AuditActionChangeCoordinators].Targets).To(ConsistOf(connectionString.Coordinators))
+
+					Expect(entries).To(HaveKey(fdbv1beta2.AuditActionKillProcesses))
+					Expect(entries[fdbv1beta2.AuditActionKillProcesses].Outcome).To(Equal(fdbv1beta2.AuditOutcomeSucceeded))
+					var podNames []string
+					for podName := range executor.commands {
+						podNames = append(podNames, podName)
+					}
+					Expect(entries[fdbv1beta2.AuditActionKillProcesses].Targets).To(ConsistOf(podNames))
+				})
+
+				When("updating the cluster file fails", func() {
+					BeforeEach(func() {
+						executor.failAfter = 1
+					})
+
+					It("should requeue without changing the connection string", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.curError).To(HaveOccurred())
+						Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+					})
+
+					It("should record the failed coordinator change and the restarted process", func() {
+						events := &fdbv1beta2.FoundationDBAuditEventList{}
+						Expect(k8sClient.List(context.TODO(), events, client.InNamespace(cluster.Namespace), client.MatchingLabels{fdbv1beta2.FDBClusterLabel: cluster.Name})).To(Succeed())
+						Expect(events.Items).To(HaveLen(2))
+
+						for _, event := range events.Items {
+							if event.Spec.Action == fdbv1beta2.AuditActionChangeCoordinators {
+								Expect(event.Spec.Outcome).To(Equal(fdbv1beta2.AuditOutcomeFailed))
+								continue
+							}
+
+							Expect(event.Spec.Action).To(Equal(fdbv1beta2.AuditActionKillProcesses))
+							Expect(event.Spec.Targets).To(HaveLen(1))
+						}
+					})
+				})
+			})
+		})
+
+		When("the automatic recovery is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.FixCoordinatorIPs = nil
+			})
+
+			It("should not change the connection string", func() {
+				Expect(result).To(BeNil())
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+				Expect(executor.commands).To(BeEmpty())
+			})
+		})
+
+		When("no pod command executor is configured", func() {
+			BeforeEach(func() {
+				clusterReconciler.PodCommandExecutor = nil
+			})
+
+			It("should requeue", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			})
+		})
+
+		When("a coordinator has no running Pod", func() {
+			BeforeEach(func() {
+				for processGroupID := range newIPs {
+					pod := &corev1.Pod{}
+					Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: cluster.Namespace, Name: fmt.Sprintf("%s-%s", cluster.Name, processGroupID)}, pod)).To(Succeed())
+					Expect(k8sClient.Delete(context.TODO(), pod)).To(Succeed())
+					break
+				}
+			})
+
+			It("should requeue without changing the connection string", func() {
+				Expect(result).NotTo(BeNil())
+				Expect(result.delayedRequeue).To(BeTrue())
+				Expect(result.message).To(ContainSubstring("has no current IP address"))
+				Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+				Expect(executor.commands).To(BeEmpty())
+			})
+		})
+	})
+
+	When("only one coordinator Pod got a new IP address and another coordinator is down", func() {
+		BeforeEach(func() {
+			connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+			Expect(err).NotTo(HaveOccurred())
+
+			coordinatorIPs := map[string]bool{}
+			for _, coordinator := range connectionString.Coordinators {
+				address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+				Expect(err).NotTo(HaveOccurred())
+				coordinatorIPs[address.IPAddress.String()] = true
+			}
+
+			adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			pods := &corev1.PodList{}
+			Expect(k8sClient.List(context.TODO(), pods)).To(Succeed())
+			var changed, missing bool
+			for index, pod := range pods.Items {
+				if !coordinatorIPs[pod.Status.PodIP] {
+					continue
+				}
+
+				if !changed {
+					newIP := fmt.Sprintf("10.1.0.%d", index)
+					pod.Status.PodIP = newIP
+					pod.Status.PodIPs = []corev1.PodIP{{IP: newIP}}
+					Expect(k8sClient.Status().Update(context.TODO(), &pod)).To(Succeed())
+					changed = true
+					continue
+				}
+
+				if !missing {
+					adminClient.MockMissingProcessGroup(fdbv1beta2.ProcessGroupID(pod.Labels[fdbv1beta2.FDBProcessGroupIDLabel]), true)
+					missing = true
+				}
+			}
+
+			Expect(changed).To(BeTrue())
+			Expect(missing).To(BeTrue())
+		})
+
+		It("should not update the cluster file", func() {
+			Expect(result).To(BeNil())
+			Expect(executor.commands).To(BeEmpty())
+			Expect(cluster.Status.ConnectionString).To(Equal(originalConnectionString))
+			Expect(cluster.Status.PendingCoordinatorIPRecovery).To(BeNil())
+		})
+	})
+})
@@ -71,7 +71,7 @@ func processIncompatibleProcesses(ctx context.Context, r *FoundationDBClusterRec
 
 	podMap := internal.CreatePodMap(cluster, pods)
 
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r.Client)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -121,8 +121,15 @@ func processIncompatibleProcesses(ctx context.Context, r *FoundationDBClusterRec
 		}
 	}
 
+	if len(incompatiblePods) == 0 {
+		return nil
+	}
+
 	// Do an unsafe update of the Pods since they are not reachable anyway
-	return r.PodLifecycleManager.UpdatePods(ctx, r, cluster, incompatiblePods, true)
+	err = r.PodLifecycleManager.UpdatePods(ctx, r, cluster, incompatiblePods, true)
+	r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionDeletePod, getPodNames(incompatiblePods), "Processes have incompatible connections", err)
+
+	return err
 }
 
 // parseIncompatibleConnections parses the incompatible connections string slice to a map and removes all false reported incompatible processes.
@@ -30,6 +30,7 @@ import (
 	"github.com/go-logr/logr"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	corev1 "k8s.io/api/core/v1"
@@ -39,10 +40,21 @@ import (
 // shrink or replacement.
 type removeProcessGroups struct{}
 
+// hasPendingDisruption returns true if process groups are marked for removal.
+func (removeProcessGroups) hasPendingDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool {
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.IsMarkedForRemoval() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // reconcile runs the reconciler's work.
 func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "removeProcessGroups")
-	adminClient, err := r.DatabaseClientProvider.GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -54,7 +66,7 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		return &requeue{curError: err}
 	}
 
-	allExcluded, newExclusions, processGroupsToRemove := r.getProcessGroupsToRemove(cluster, remainingMap)
+	allExcluded, newExclusions, processGroupsToRemove := r.getProcessGroupsToRemove(ctx, cluster, remainingMap)
 	// If no process groups are marked to remove we have to check if all process groups are excluded.
 	if len(processGroupsToRemove) == 0 {
 		if !allExcluded {
@@ -101,7 +113,7 @@ func (u removeProcessGroups) reconcile(ctx context.Context, r *FoundationDBClust
 		// To ensure we are not deletion zones faster than Kubernetes actually removes Pods we are adding a wait time
 		// if we have resources in the terminating state. We will only block if the terminating state was recently (in the
 		// last minute).
-		waitTime, allowed := removals.RemovalAllowed(lastDeletion, time.Now().Unix(), cluster.GetWaitBetweenRemovalsSeconds())
+		waitTime, allowed := removals.RemovalAllowed(lastDeletion, r.now().Unix(), cluster.GetWaitBetweenRemovalsSeconds())
 		if !allowed {
 			return &requeue{message: fmt.Sprintf("not allowed to remove process groups, waiting: %v", waitTime), delay: time.Duration(waitTime) * time.Second}
 		}
@@ -134,6 +146,7 @@ func removeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler, c
 
 	if len(pods) == 1 && pods[0].DeletionTimestamp.IsZero() {
 		err = r.PodLifecycleManager.DeletePod(ctx, r, pods[0])
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionDeletePod, []string{pods[0].Name}, fmt.Sprintf("Process group %s is removed", processGroupID), err)
 		if err != nil {
 			return err
 		}
@@ -149,6 +162,7 @@ func removeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler, c
 	if len(pvcs.Items) == 1 && pvcs.Items[0].DeletionTimestamp.IsZero() {
 		logr.FromContextOrDiscard(ctx).V(1).Info("Deleting pvc", "name", pvcs.Items[0].Name)
 		err = r.Delete(ctx, &pvcs.Items[0])
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionDeletePVC, []string{pvcs.Items[0].Name}, fmt.Sprintf("Process group %s is removed", processGroupID), err)
 		if err != nil {
 			return err
 		}
@@ -234,7 +248,7 @@ func confirmRemoval(ctx context.Context, r *FoundationDBClusterReconciler, clust
 }
 
 func includeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, removedProcessGroups map[fdbv1beta2.ProcessGroupID]bool) error {
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -245,6 +259,7 @@ func includeProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 		r.Recorder.Event(cluster, corev1.EventTypeNormal, "IncludingProcesses", fmt.Sprintf("Including removed processes: %v", fdbProcessesToInclude))
 
 		err = adminClient.IncludeProcesses(fdbProcessesToInclude)
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionIncludeProcesses, audit.GetProcessAddressTargets(fdbProcessesToInclude), "Process groups were removed", err)
 		if err != nil {
 			return err
 		}
@@ -286,7 +301,7 @@ func getProcessesToInclude(cluster *fdbv1beta2.FoundationDBCluster, removedProce
 	return fdbProcessesToInclude
 }
 
-func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(cluster *fdbv1beta2.FoundationDBCluster, remainingMap map[string]bool) (bool, bool, []*fdbv1beta2.ProcessGroupStatus) {
+func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, remainingMap map[string]bool) (bool, bool, []*fdbv1beta2.ProcessGroupStatus) {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "removeProcessGroups")
 	var cordSet map[string]fdbv1beta2.None
 	allExcluded := true
@@ -301,7 +316,7 @@ func (r *FoundationDBClusterReconciler) getProcessGroupsToRemove(cluster *fdbv1b
 		// Only query FDB if we have a pending removal otherwise don't query FDB
 		if len(cordSet) == 0 {
 			var err error
-			cordSet, err = r.getCoordinatorSet(cluster)
+			cordSet, err = r.getCoordinatorSet(ctx, cluster)
 
 			if err != nil {
 				logger.Error(err, "Fetching coordinator set for removal")
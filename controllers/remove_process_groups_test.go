@@ -80,7 +80,7 @@ var _ = Describe("remove_process_groups", func() {
 					coordinatorIP: false,
 				}
 
-				allExcluded, newExclusions, processes := clusterReconciler.getProcessGroupsToRemove(cluster, remaining)
+				allExcluded, newExclusions, processes := clusterReconciler.getProcessGroupsToRemove(context.TODO(), cluster, remaining)
 				Expect(allExcluded).To(BeFalse())
 				Expect(processes).To(BeEmpty())
 				Expect(newExclusions).To(BeFalse())
@@ -151,6 +151,45 @@ var _ = Describe("remove_process_groups", func() {
 					})
 				})
 
+				When("the safety check for the removal fails", func() {
+					BeforeEach(func() {
+						adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(adminClient.InjectFault("CanSafelyRemove", mock.Fault{Error: fmt.Errorf("timeout")})).NotTo(HaveOccurred())
+					})
+
+					It("should requeue with the error and not remove that process group", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.curError).To(MatchError("timeout"))
+						// Ensure resources are not deleted
+						removed, include, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+						Expect(err).To(BeNil())
+						Expect(removed).To(BeFalse())
+						Expect(include).To(BeFalse())
+					})
+				})
+
+				When("the safety check for the removal returns a stale result", func() {
+					BeforeEach(func() {
+						adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(adminClient.InjectFault("CanSafelyRemove", mock.Fault{Stale: true, Times: 1})).NotTo(HaveOccurred())
+					})
+
+					It("should requeue and remove that process group in the next reconciliation", func() {
+						Expect(result).NotTo(BeNil())
+						Expect(result.message).To(Equal("Reconciliation needs to exclude more processes"))
+
+						result = removeProcessGroups{}.reconcile(context.TODO(), clusterReconciler, cluster)
+						Expect(result).To(BeNil())
+						// Ensure resources are deleted
+						removed, include, err := confirmRemoval(context.Background(), clusterReconciler, cluster, removedProcessGroup.ProcessGroupID)
+						Expect(err).To(BeNil())
+						Expect(removed).To(BeTrue())
+						Expect(include).To(BeTrue())
+					})
+				})
+
 				When("the cluster is not available", func() {
 					BeforeEach(func() {
 						adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
@@ -41,13 +41,13 @@ func (c replaceFailedProcessGroups) reconcile(ctx context.Context, r *Foundation
 		return nil
 	}
 
-	adminClient, err := r.DatabaseClientProvider.GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 	defer adminClient.Close()
 
-	if replacements.ReplaceFailedProcessGroups(logger, cluster, adminClient) {
+	if replacements.ReplaceFailedProcessGroups(logger, cluster, adminClient, r.now()) {
 		err := r.updateOrApply(ctx, cluster)
 		if err != nil {
 			return &requeue{curError: err}
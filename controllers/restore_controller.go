@@ -22,16 +22,21 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -46,6 +51,7 @@ type FoundationDBRestoreReconciler struct {
 	Log                    logr.Logger
 	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
 	ServerSideApply        bool
+	Sharder                *sharding.Sharder
 }
 
 // +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbrestores,verbs=get;list;watch;create;update;patch;delete
@@ -69,24 +75,70 @@ func (r *FoundationDBRestoreReconciler) Reconcile(ctx context.Context, request c
 
 	restoreLog := log.WithValues("namespace", restore.Namespace, "restore", restore.Name)
 
+	if !r.Sharder.OwnsCluster(restore.Namespace, restore.Spec.DestinationClusterName) {
+		restoreLog.V(1).Info("Skipping restore of cluster owned by another shard", "cluster", restore.Spec.DestinationClusterName)
+		return ctrl.Result{}, nil
+	}
+
+	acquired, err := r.Sharder.AcquireCluster(ctx, restore.Namespace, restore.Spec.DestinationClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !acquired {
+		restoreLog.Info("Waiting for the previous shard to finish the reconciliation of the cluster", "cluster", restore.Spec.DestinationClusterName)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	defer r.Sharder.ReleaseCluster(ctx, restore.Namespace, restore.Spec.DestinationClusterName)
+
 	subReconcilers := []restoreSubReconciler{
 		startRestore{},
 	}
 
 	for _, subReconciler := range subReconcilers {
+		// The cluster might have been assigned to another shard while the
+		// previous sub-reconcilers were running.
+		if !r.Sharder.HoldsCluster(restore.Namespace, restore.Spec.DestinationClusterName) {
+			restoreLog.Info("Stopping reconciliation of cluster owned by another shard", "subReconciler", fmt.Sprintf("%T", subReconciler), "cluster", restore.Spec.DestinationClusterName)
+			return ctrl.Result{}, nil
+		}
+
 		requeue := subReconciler.reconcile(ctx, r, restore)
 		if requeue == nil {
 			continue
 		}
 
+		message := requeue.message
+		if requeue.curError != nil {
+			message = requeue.curError.Error()
+		}
+		r.updateConditions(ctx, restore, false, message, restoreLog)
+
 		return processRequeue(requeue, subReconciler, restore, r.Recorder, restoreLog)
 	}
 
+	r.updateConditions(ctx, restore, true, "", restoreLog)
 	restoreLog.Info("Reconciliation complete")
 
 	return ctrl.Result{}, nil
 }
 
+// updateConditions updates the conditions in the restore status and persists
+// them if they changed. Errors are only logged, as the conditions are
+// updated again in the next reconciliation.
+func (r *FoundationDBRestoreReconciler) updateConditions(ctx context.Context, restore *fdbv1beta2.FoundationDBRestore, reconciled bool, message string, logger logr.Logger) {
+	originalStatus := restore.Status.DeepCopy()
+	restore.UpdateConditions(reconciled, message, time.Now())
+
+	if equality.Semantic.DeepEqual(restore.Status, *originalStatus) {
+		return
+	}
+
+	err := r.updateOrApply(ctx, restore)
+	if err != nil {
+		logger.Error(err, "Error updating restore conditions")
+	}
+}
+
 // getDatabaseClientProvider gets the client provider for a reconciler.
 func (r *FoundationDBRestoreReconciler) getDatabaseClientProvider() fdbadminclient.DatabaseClientProvider {
 	if r.DatabaseClientProvider != nil {
@@ -120,7 +172,7 @@ func (r *FoundationDBRestoreReconciler) SetupWithManager(mgr ctrl.Manager, maxCo
 		return err
 	}
 
-	return ctrl.NewControllerManagedBy(mgr).
+	managedBy := ctrl.NewControllerManagedBy(mgr).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: maxConcurrentReconciles},
 		).
@@ -134,8 +186,13 @@ func (r *FoundationDBRestoreReconciler) SetupWithManager(mgr ctrl.Manager, maxCo
 					predicate.GenerationChangedPredicate{},
 					predicate.AnnotationChangedPredicate{},
 				),
-			)).
-		Complete(r)
+			))
+
+	if r.Sharder != nil {
+		managedBy.Watches(r.Sharder.GetSource(&fdbv1beta2.FoundationDBRestoreList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	return managedBy.Complete(r)
 }
 
 // restoreSubReconciler describes a class that does part of the work of
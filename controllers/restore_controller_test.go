@@ -27,8 +27,10 @@ import (
 	. "github.com/onsi/gomega"
 
 	"context"
+	"strings"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -96,6 +98,11 @@ var _ = Describe("restore_controller", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(status).To(Equal("blobstore://test@test-service/test-backup?bucket=fdb-backups\n"))
 			})
+
+			It("should set the conditions of the restore", func() {
+				Expect(meta.IsStatusConditionTrue(restore.Status.Conditions, string(fdbv1beta2.RestoreRunning))).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(restore.Status.Conditions, string(fdbv1beta2.RestoreReconciled))).To(BeTrue())
+			})
 		})
 
 		When("providing custom parameters", func() {
@@ -113,4 +120,29 @@ var _ = Describe("restore_controller", func() {
 			})
 		})
 	})
+
+	When("restoring from the file backend", func() {
+		var result *requeue
+
+		BeforeEach(func() {
+			Expect(k8sClient.Create(context.TODO(), cluster)).To(Succeed())
+			restore.Spec.BlobStoreConfiguration = &fdbv1beta2.BlobStoreConfiguration{
+				BackupName:      "test-backup",
+				Backend:         fdbv1beta2.BlobStoreBackendFile,
+				VolumeClaimName: "backup-data",
+			}
+			Expect(k8sClient.Create(context.TODO(), restore)).To(Succeed())
+
+			result = startRestore{}.reconcile(context.TODO(), restoreReconciler, restore)
+		})
+
+		It("should not start the restore", func() {
+			Expect(result).NotTo(BeNil())
+			Expect(result.curError).To(MatchError("restores from the file backend are not supported"))
+
+			status, err := adminClient.GetRestoreStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(strings.TrimSpace(status)).To(BeEmpty())
+		})
+	})
 })
@@ -0,0 +1,177 @@
+/*
+ * revert_chaos.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// revertChaos provides a reconciliation step for reverting the faults of a
+// chaos experiment that has ended or that is being deleted.
+type revertChaos struct{}
+
+// reconcile runs the reconciler's work.
+func (c revertChaos) reconcile(ctx context.Context, r *FoundationDBChaosReconciler, chaos *fdbv1beta2.FoundationDBChaos) *requeue {
+	deleting := !chaos.ObjectMeta.DeletionTimestamp.IsZero()
+	if !chaos.IsFinished() && !deleting {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", chaos.Namespace, "chaos", chaos.Name, "reconciler", "revertChaos")
+	_, err := removeChaosObjects(ctx, r, logger, chaos, map[string]string{fdbv1beta2.ChaosLabel: chaos.Name})
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if !deleting && chaos.Status.EndTime == nil {
+		now := metav1.Now()
+		chaos.Status.EndTime = &now
+		chaos.Status.Targets = nil
+		err = r.updateOrApply(ctx, chaos)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	// All faults are reverted, so the resource can be deleted without any
+	// further cleanup.
+	if controllerutil.ContainsFinalizer(chaos, fdbv1beta2.ChaosFinalizer) {
+		patch := client.MergeFrom(chaos.DeepCopy())
+		controllerutil.RemoveFinalizer(chaos, fdbv1beta2.ChaosFinalizer)
+		err = r.Patch(ctx, chaos, patch)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
+
+// removeChaosObjects deletes the resources of a chaos experiment that match
+// the provided labels. The file that fills up a data volume is removed through
+// the FoundationDB Pod before the Pod that created it is deleted, so the
+// volume is freed even if that Pod doesn't handle its termination. This
+// returns true if any of the resources still existed.
+func removeChaosObjects(ctx context.Context, r *FoundationDBChaosReconciler, logger logr.Logger, chaos *fdbv1beta2.FoundationDBChaos, labels map[string]string) (bool, error) {
+	listOptions := []client.ListOption{
+		client.InNamespace(chaos.Namespace),
+		client.MatchingLabels(labels),
+	}
+
+	policies := &networkingv1.NetworkPolicyList{}
+	err := r.List(ctx, policies, listOptions...)
+	if err != nil {
+		return false, err
+	}
+
+	for index := range policies.Items {
+		logger.Info("Deleting chaos network policy", "networkPolicy", policies.Items[index].Name)
+		err = r.Delete(ctx, &policies.Items[index])
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	pods := &corev1.PodList{}
+	err = r.List(ctx, pods, listOptions...)
+	if err != nil {
+		return false, err
+	}
+
+	for index := range pods.Items {
+		pod := &pods.Items[index]
+		if !pod.ObjectMeta.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		err = removeChaosDiskFillFile(ctx, r, logger, chaos, pod)
+		if err != nil {
+			return false, err
+		}
+
+		logger.Info("Deleting chaos pod", "pod", pod.Name)
+		err = r.Delete(ctx, pod)
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+
+	return len(policies.Items) > 0 || len(pods.Items) > 0, nil
+}
+
+// removeChaosDiskFillFile removes the file that the provided chaos Pod created
+// to fill up the data volume of its target process group.
+func removeChaosDiskFillFile(ctx context.Context, r *FoundationDBChaosReconciler, logger logr.Logger, chaos *fdbv1beta2.FoundationDBChaos, chaosPod *corev1.Pod) error {
+	processGroupID := fdbv1beta2.ProcessGroupID(chaosPod.ObjectMeta.Annotations[fdbv1beta2.ChaosTargetAnnotation])
+	if processGroupID == "" {
+		return nil
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: chaos.Namespace, Name: chaos.Spec.ClusterName}, cluster)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	// The volume of a process group that was removed from the cluster is
+	// deleted together with the file.
+	if fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, processGroupID) == nil {
+		return nil
+	}
+
+	pods := &corev1.PodList{}
+	err = r.List(ctx, pods, internal.GetSinglePodListOptions(cluster, processGroupID)...)
+	if err != nil {
+		return err
+	}
+
+	if len(pods.Items) != 1 || pods.Items[0].Status.Phase != corev1.PodRunning {
+		return fmt.Errorf("cannot remove the disk fill file of process group %s because its pod is not running", processGroupID)
+	}
+
+	if r.PodCommandExecutor == nil {
+		return fmt.Errorf("cannot remove the disk fill file of process group %s without a pod command executor", processGroupID)
+	}
+
+	logger.Info("Removing chaos disk fill file", "processGroupID", processGroupID, "pod", pods.Items[0].Name)
+	_, stderr, err := r.PodCommandExecutor.ExecuteCommand(ctx, &pods.Items[0], fdbv1beta2.MainContainerName, internal.GetChaosDiskFillCleanupCommand())
+	if err != nil {
+		return fmt.Errorf("could not remove the disk fill file in pod %s: %w, stderr: %s", pods.Items[0].Name, err, stderr)
+	}
+
+	return nil
+}
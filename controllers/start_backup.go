@@ -22,8 +22,12 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strconv"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // startBackup provides a reconciliation step for starting a new backup.
@@ -36,6 +40,16 @@ func (s startBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconci
 		return nil
 	}
 
+	err := backup.Spec.BlobStoreConfiguration.ValidateURL(backup.BackupURL())
+	if err != nil {
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "InvalidBackupURL", err.Error())
+		return &requeue{curError: err}
+	}
+
+	if backup.Spec.BlobStoreConfiguration.GetBackend() == fdbv1beta2.BlobStoreBackendFile {
+		return startFileBackup(ctx, r, backup)
+	}
+
 	adminClient, err := r.adminClientForBackup(ctx, backup)
 	if err != nil {
 		return &requeue{curError: err}
@@ -49,3 +63,44 @@ func (s startBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconci
 
 	return nil
 }
+
+// startFileBackup starts a backup with the file backend. fdbbackup creates the
+// backup directory when the backup is started, so the command runs in one of
+// the backup agents, which have the volume mounted, instead of in the
+// operator.
+func startFileBackup(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	logger := log.WithValues("namespace", backup.Namespace, "backup", backup.Name, "reconciler", "startBackup")
+
+	if r.PodCommandExecutor == nil {
+		return &requeue{message: "cannot start a file backup without a pod command executor", delayedRequeue: true}
+	}
+
+	pods := &corev1.PodList{}
+	err := r.List(ctx, pods, client.InNamespace(backup.Namespace), client.MatchingLabels{"foundationdb.org/deployment-name": fmt.Sprintf("%s-backup-agents", backup.Name)})
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	var agent *corev1.Pod
+	for index, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp.IsZero() {
+			agent = &pods.Items[index]
+			break
+		}
+	}
+
+	if agent == nil {
+		return &requeue{message: "Waiting for a running backup agent to start the file backup", delayedRequeue: true}
+	}
+
+	command := []string{"fdbbackup", "start", "-d", backup.BackupURL(), "-s", strconv.Itoa(backup.SnapshotPeriodSeconds()), "-z"}
+	command = append(command, backup.Spec.CustomParameters.GetKnobsForCLI()...)
+
+	logger.Info("Starting file backup in backup agent", "pod", agent.Name)
+	_, stderr, err := r.PodCommandExecutor.ExecuteCommand(ctx, agent, fdbv1beta2.MainContainerName, command)
+	if err != nil {
+		return &requeue{curError: fmt.Errorf("could not start the backup in pod %s: %w, stderr: %s", agent.Name, err, stderr)}
+	}
+
+	return nil
+}
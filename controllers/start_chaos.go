@@ -0,0 +1,90 @@
+/*
+ * start_chaos.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// startChaos provides a reconciliation step for starting a chaos experiment
+// once its start time has been reached and the cluster has the desired fault
+// tolerance.
+type startChaos struct{}
+
+// reconcile runs the reconciler's work.
+func (s startChaos) reconcile(ctx context.Context, r *FoundationDBChaosReconciler, chaos *fdbv1beta2.FoundationDBChaos) *requeue {
+	if chaos.GetPhase() != fdbv1beta2.ChaosPhasePending {
+		return nil
+	}
+
+	if chaos.Spec.StartTime != nil && time.Now().Before(chaos.Spec.StartTime.Time) {
+		return nil
+	}
+
+	logger := log.WithValues("namespace", chaos.Namespace, "chaos", chaos.Name, "reconciler", "startChaos")
+
+	cluster, adminClient, err := r.adminClientForChaos(ctx, chaos)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	hasDesiredFaultTolerance, err := internal.HasDesiredFaultTolerance(logger, adminClient, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if !hasDesiredFaultTolerance {
+		return &requeue{message: "Chaos experiment cannot start because the cluster doesn't have the desired fault tolerance", delay: chaosStatusRefreshInterval}
+	}
+
+	// The finalizer must be present before any fault is injected, otherwise
+	// the faults could be left behind when the experiment is deleted.
+	if !controllerutil.ContainsFinalizer(chaos, fdbv1beta2.ChaosFinalizer) {
+		controllerutil.AddFinalizer(chaos, fdbv1beta2.ChaosFinalizer)
+		err = r.Update(ctx, chaos)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	now := metav1.Now()
+	chaos.Status.Phase = fdbv1beta2.ChaosPhaseRunning
+	chaos.Status.StartTime = &now
+	err = r.updateOrApply(ctx, chaos)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	logger.Info("Starting chaos experiment", "action", chaos.Spec.Action, "duration", chaos.GetDuration())
+	r.Recorder.Event(chaos, corev1.EventTypeNormal, "ChaosStarted", fmt.Sprintf("Starting %s experiment against cluster %s for %s", chaos.Spec.Action, cluster.Name, chaos.GetDuration()))
+
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ * start_disaster_recovery.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// startDisasterRecovery provides a reconciliation step for starting a new DR.
+type startDisasterRecovery struct{}
+
+// reconcile runs the reconciler's work.
+func (s startDisasterRecovery) reconcile(ctx context.Context, r *FoundationDBDisasterRecoveryReconciler, dr *fdbv1beta2.FoundationDBDisasterRecovery) *requeue {
+	if !dr.ShouldRun() || (dr.Status.DRDetails != nil && dr.Status.DRDetails.Running) {
+		return nil
+	}
+
+	if dr.Spec.SourceClusterName == dr.Spec.DestinationClusterName {
+		return &requeue{curError: fmt.Errorf("source and destination cluster must be different")}
+	}
+
+	adminClient, sourceConnectionString, err := r.adminClientForDisasterRecovery(ctx, dr, dr.Spec.SourceClusterName, dr.Spec.DestinationClusterName)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	err = adminClient.StartDisasterRecovery(sourceConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	r.Recorder.Event(dr, corev1.EventTypeNormal, "StartDisasterRecovery", fmt.Sprintf("Started replicating %s into %s", dr.Spec.SourceClusterName, dr.Spec.DestinationClusterName))
+	dr.Status.DRDetails = &fdbv1beta2.FoundationDBDisasterRecoveryStatusDetails{
+		SourceClusterName:      dr.Spec.SourceClusterName,
+		DestinationClusterName: dr.Spec.DestinationClusterName,
+		Running:                true,
+	}
+
+	return nil
+}
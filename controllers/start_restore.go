@@ -22,9 +22,11 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // startRestore provides a reconciliation step for starting a new restore.
@@ -45,6 +47,18 @@ func (s startRestore) reconcile(ctx context.Context, r *FoundationDBRestoreRecon
 	}
 
 	if len(strings.TrimSpace(status)) == 0 {
+		err = restore.Spec.BlobStoreConfiguration.ValidateURL(restore.BackupURL())
+		if err == nil && restore.Spec.BlobStoreConfiguration.GetBackend() == fdbv1beta2.BlobStoreBackendFile {
+			// fdbrestore reads the backup in the operator and the restore
+			// runs in the backup agents of the cluster, neither of them
+			// has the volume mounted.
+			err = fmt.Errorf("restores from the %s backend are not supported", fdbv1beta2.BlobStoreBackendFile)
+		}
+		if err != nil {
+			r.Recorder.Event(restore, corev1.EventTypeWarning, "InvalidBackupURL", err.Error())
+			return &requeue{curError: err}
+		}
+
 		err = adminClient.StartRestore(restore.BackupURL(), restore.Spec.KeyRanges)
 		if err != nil {
 			return &requeue{curError: err}
@@ -59,6 +59,9 @@ var k8sClient *mockclient.MockClient
 var clusterReconciler *FoundationDBClusterReconciler
 var backupReconciler *FoundationDBBackupReconciler
 var restoreReconciler *FoundationDBRestoreReconciler
+var disasterRecoveryReconciler *FoundationDBDisasterRecoveryReconciler
+var tenantReconciler *FoundationDBTenantReconciler
+var chaosReconciler *FoundationDBChaosReconciler
 
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
@@ -93,6 +96,28 @@ var _ = BeforeSuite(func() {
 		Recorder:               k8sClient,
 		DatabaseClientProvider: mock.DatabaseClientProvider{},
 	}
+
+	disasterRecoveryReconciler = &FoundationDBDisasterRecoveryReconciler{
+		Client:                 k8sClient,
+		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBDisasterRecovery"),
+		Recorder:               k8sClient,
+		InSimulation:           true,
+		DatabaseClientProvider: mock.DatabaseClientProvider{},
+	}
+
+	tenantReconciler = &FoundationDBTenantReconciler{
+		Client:                 k8sClient,
+		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBTenant"),
+		Recorder:               k8sClient,
+		DatabaseClientProvider: mock.DatabaseClientProvider{},
+	}
+
+	chaosReconciler = &FoundationDBChaosReconciler{
+		Client:                 k8sClient,
+		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBChaos"),
+		Recorder:               k8sClient,
+		DatabaseClientProvider: mock.DatabaseClientProvider{},
+	}
 })
 
 var _ = AfterSuite(func() {
@@ -136,6 +161,18 @@ func reconcileRestore(restore *fdbv1beta2.FoundationDBRestore) (reconcile.Result
 	return reconcileObject(restoreReconciler, restore.ObjectMeta, 20)
 }
 
+func reconcileDisasterRecovery(dr *fdbv1beta2.FoundationDBDisasterRecovery) (reconcile.Result, error) {
+	return reconcileObject(disasterRecoveryReconciler, dr.ObjectMeta, 20)
+}
+
+func reconcileTenant(tenant *fdbv1beta2.FoundationDBTenant) (reconcile.Result, error) {
+	return reconcileObject(tenantReconciler, tenant.ObjectMeta, 20)
+}
+
+func reconcileChaos(chaos *fdbv1beta2.FoundationDBChaos) (reconcile.Result, error) {
+	return reconcileObject(chaosReconciler, chaos.ObjectMeta, 20)
+}
+
 func reconcileObject(reconciler reconcile.Reconciler, metadata metav1.ObjectMeta, requeueLimit int) (reconcile.Result, error) {
 	attempts := requeueLimit + 1
 	result := reconcile.Result{Requeue: true}
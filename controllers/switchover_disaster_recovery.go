@@ -0,0 +1,77 @@
+/*
+ * switchover_disaster_recovery.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// switchoverDisasterRecovery provides a reconciliation step for switching the
+// source and the destination cluster of a running DR.
+type switchoverDisasterRecovery struct{}
+
+// reconcile runs the reconciler's work.
+func (s switchoverDisasterRecovery) reconcile(ctx context.Context, r *FoundationDBDisasterRecoveryReconciler, dr *fdbv1beta2.FoundationDBDisasterRecovery) *requeue {
+	// If the DR should be stopped, the abortDisasterRecovery step will abort
+	// the DR between the current clusters.
+	if !dr.ShouldRun() || dr.Status.DRDetails == nil || !dr.Status.DRDetails.Running {
+		return nil
+	}
+
+	sourceClusterName, destinationClusterName := dr.GetCurrentClusterNames()
+	if sourceClusterName == dr.Spec.SourceClusterName && destinationClusterName == dr.Spec.DestinationClusterName {
+		return nil
+	}
+
+	if !dr.NeedsSwitchover() {
+		return &requeue{message: fmt.Sprintf("DR from %s to %s is running, the DR must be stopped before it can be started between different clusters", sourceClusterName, destinationClusterName)}
+	}
+
+	logger := log.WithValues("namespace", dr.Namespace, "disasterRecovery", dr.Name, "reconciler", "switchoverDisasterRecovery")
+	adminClient, sourceConnectionString, err := r.adminClientForDisasterRecovery(ctx, dr, sourceClusterName, destinationClusterName)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	logger.Info("Switching over DR", "source", sourceClusterName, "destination", destinationClusterName)
+	err = adminClient.SwitchoverDisasterRecovery(sourceConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	r.Recorder.Event(dr, corev1.EventTypeNormal, "SwitchoverDisasterRecovery", fmt.Sprintf("Switched the primary from %s to %s", sourceClusterName, destinationClusterName))
+
+	// We persist the new clusters directly, otherwise the next reconciliation
+	// would try to switch over again.
+	dr.Status.DRDetails.SourceClusterName = dr.Spec.SourceClusterName
+	dr.Status.DRDetails.DestinationClusterName = dr.Spec.DestinationClusterName
+	err = r.updateOrApply(ctx, dr)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
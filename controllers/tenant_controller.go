@@ -0,0 +1,226 @@
+/*
+ * tenant_controller.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// FoundationDBTenantReconciler reconciles a FoundationDBTenant object
+type FoundationDBTenantReconciler struct {
+	client.Client
+	Recorder               record.EventRecorder
+	Log                    logr.Logger
+	DatabaseClientProvider fdbadminclient.DatabaseClientProvider
+	ServerSideApply        bool
+	Sharder                *sharding.Sharder
+}
+
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbtenants,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbtenants/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.foundationdb.org,resources=foundationdbclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups="coordination.k8s.io",resources=leases,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile runs the reconciliation logic.
+func (r *FoundationDBTenantReconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	tenant := &fdbv1beta2.FoundationDBTenant{}
+
+	err := r.Get(ctx, request.NamespacedName, tenant)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			// Object not found, return.  Created objects are automatically garbage collected.
+			// For additional cleanup logic use finalizers.
+			return ctrl.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return ctrl.Result{}, err
+	}
+
+	tenantLog := log.WithValues("namespace", tenant.Namespace, "tenant", tenant.Name)
+
+	if !r.Sharder.OwnsCluster(tenant.Namespace, tenant.Spec.ClusterName) {
+		tenantLog.V(1).Info("Skipping tenant of cluster owned by another shard", "cluster", tenant.Spec.ClusterName)
+		return ctrl.Result{}, nil
+	}
+
+	acquired, err := r.Sharder.AcquireCluster(ctx, tenant.Namespace, tenant.Spec.ClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !acquired {
+		tenantLog.Info("Waiting for the previous shard to finish the reconciliation of the cluster", "cluster", tenant.Spec.ClusterName)
+		return ctrl.Result{Requeue: true}, nil
+	}
+	defer r.Sharder.ReleaseCluster(ctx, tenant.Namespace, tenant.Spec.ClusterName)
+
+	if tenant.ObjectMeta.DeletionTimestamp.IsZero() {
+		err = tenant.Validate()
+		if err != nil {
+			r.Recorder.Event(tenant, corev1.EventTypeWarning, "TenantSpec not valid", err.Error())
+			return ctrl.Result{}, fmt.Errorf("TenantSpec is not valid: %w", err)
+		}
+	}
+
+	subReconcilers := []tenantSubReconciler{
+		updateTenant{},
+		updateTenantStatus{},
+	}
+
+	if !tenant.ObjectMeta.DeletionTimestamp.IsZero() {
+		subReconcilers = []tenantSubReconciler{
+			deleteTenant{},
+		}
+	}
+
+	for _, subReconciler := range subReconcilers {
+		// The cluster might have been assigned to another shard while the
+		// previous sub-reconcilers were running.
+		if !r.Sharder.HoldsCluster(tenant.Namespace, tenant.Spec.ClusterName) {
+			tenantLog.Info("Stopping reconciliation of cluster owned by another shard", "subReconciler", fmt.Sprintf("%T", subReconciler), "cluster", tenant.Spec.ClusterName)
+			return ctrl.Result{}, nil
+		}
+
+		requeue := subReconciler.reconcile(ctx, r, tenant)
+		if requeue == nil {
+			continue
+		}
+
+		return processRequeue(requeue, subReconciler, tenant, r.Recorder, tenantLog)
+	}
+
+	tenantLog.Info("Reconciliation complete")
+
+	return ctrl.Result{}, nil
+}
+
+// getDatabaseClientProvider gets the client provider for a reconciler.
+func (r *FoundationDBTenantReconciler) getDatabaseClientProvider() fdbadminclient.DatabaseClientProvider {
+	if r.DatabaseClientProvider != nil {
+		return r.DatabaseClientProvider
+	}
+	panic("Tenant reconciler does not have a DatabaseClientProvider defined")
+}
+
+// adminClientForTenant provides an admin client for the cluster of a tenant.
+func (r *FoundationDBTenantReconciler) adminClientForTenant(ctx context.Context, tenant *fdbv1beta2.FoundationDBTenant) (fdbadminclient.AdminClient, error) {
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: tenant.ObjectMeta.Namespace, Name: tenant.Spec.ClusterName}, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := fdbv1beta2.ParseFdbVersion(cluster.GetRunningVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if !version.SupportsTenants() {
+		return nil, fmt.Errorf("cluster %s runs version %s, which doesn't support tenants", cluster.Name, version)
+	}
+
+	return r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+}
+
+// SetupWithManager prepares a reconciler for use.
+func (r *FoundationDBTenantReconciler) SetupWithManager(mgr ctrl.Manager, maxConcurrentReconciles int, selector metav1.LabelSelector) error {
+	labelSelectorPredicate, err := predicate.LabelSelectorPredicate(selector)
+	if err != nil {
+		return err
+	}
+
+	managedBy := ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: maxConcurrentReconciles},
+		).
+		For(&fdbv1beta2.FoundationDBTenant{}).
+		// Only react on generation changes or annotation changes and only watch
+		// resources with the provided label selector.
+		WithEventFilter(
+			predicate.And(
+				labelSelectorPredicate,
+				predicate.Or(
+					predicate.GenerationChangedPredicate{},
+					predicate.AnnotationChangedPredicate{},
+				),
+			))
+
+	if r.Sharder != nil {
+		managedBy.Watches(r.Sharder.GetSource(&fdbv1beta2.FoundationDBTenantList{}), &handler.EnqueueRequestForObject{})
+	}
+
+	return managedBy.Complete(r)
+}
+
+// tenantSubReconciler describes a class that does part of the work of
+// reconciliation for a tenant.
+type tenantSubReconciler interface {
+	/**
+	reconcile runs the reconciler's work.
+
+	If reconciliation can continue, this should return nil.
+
+	If reconciliation encounters an error, this should return a requeue object
+	with an `Error` field.
+
+	If reconciliation cannot proceed, this should return a requeue object with a
+	`Message` field.
+	*/
+	reconcile(ctx context.Context, r *FoundationDBTenantReconciler, tenant *fdbv1beta2.FoundationDBTenant) *requeue
+}
+
+// updateOrApply updates the status either with server-side apply or if disabled with the normal update call.
+func (r *FoundationDBTenantReconciler) updateOrApply(ctx context.Context, tenant *fdbv1beta2.FoundationDBTenant) error {
+	if r.ServerSideApply {
+		// We have to set the TypeMeta otherwise the Patch command will fail.
+		patch := &fdbv1beta2.FoundationDBTenant{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       tenant.Kind,
+				APIVersion: tenant.APIVersion,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      tenant.Name,
+				Namespace: tenant.Namespace,
+			},
+			Status: tenant.Status,
+		}
+
+		return r.Status().Patch(ctx, patch, client.Apply, client.FieldOwner("fdb-operator"), client.ForceOwnership)
+	}
+
+	return r.Status().Update(ctx, tenant)
+}
@@ -0,0 +1,225 @@
+/*
+ * tenant_controller_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+func reloadTenant(tenant *fdbv1beta2.FoundationDBTenant) error {
+	return k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(tenant), tenant)
+}
+
+var _ = Describe("tenant_controller", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var tenant *fdbv1beta2.FoundationDBTenant
+	var adminClient *mock.AdminClient
+	var err error
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Version = fdbv1beta2.Versions.SupportsTenants.String()
+		Expect(setupClusterForTest(cluster)).NotTo(HaveOccurred())
+
+		adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		tenant = &fdbv1beta2.FoundationDBTenant{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sample-tenant",
+				Namespace: cluster.Namespace,
+			},
+			Spec: fdbv1beta2.FoundationDBTenantSpec{
+				ClusterName: cluster.Name,
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		Expect(k8sClient.Create(context.TODO(), tenant)).NotTo(HaveOccurred())
+
+		_, err = reconcileTenant(tenant)
+		Expect(reloadTenant(tenant)).NotTo(HaveOccurred())
+	})
+
+	When("reconciling a new tenant", func() {
+		It("should create the tenant in the cluster", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.Tenants).To(HaveKeyWithValue("sample-tenant", fdbv1beta2.FoundationDBLiveTenantStatus{
+				ID:    1,
+				State: "ready",
+			}))
+		})
+
+		It("should add the finalizer", func() {
+			Expect(tenant.Finalizers).To(ContainElement(fdbv1beta2.TenantFinalizer))
+		})
+
+		It("should update the status", func() {
+			Expect(tenant.Status).To(Equal(fdbv1beta2.FoundationDBTenantStatus{
+				ID:    pointer.Int64(1),
+				State: "ready",
+				Generations: fdbv1beta2.TenantGenerationStatus{
+					Reconciled: tenant.Generation,
+				},
+			}))
+		})
+	})
+
+	When("a tenant name is defined", func() {
+		BeforeEach(func() {
+			tenant.Spec.TenantName = "other-tenant"
+		})
+
+		It("should create the tenant with the name from the spec", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.Tenants).To(HaveKey("other-tenant"))
+			Expect(adminClient.Tenants).NotTo(HaveKey("sample-tenant"))
+		})
+	})
+
+	When("the cluster doesn't support tenants", func() {
+		BeforeEach(func() {
+			cluster.Spec.Version = fdbv1beta2.Versions.Default.String()
+			cluster.Status.RunningVersion = fdbv1beta2.Versions.Default.String()
+			Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+		})
+
+		It("should not create the tenant", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(adminClient.Tenants).To(BeEmpty())
+			Expect(tenant.Status.ID).To(BeNil())
+		})
+	})
+
+	When("a tenant group is defined", func() {
+		BeforeEach(func() {
+			tenant.Spec.TenantGroup = "sample-group"
+		})
+
+		It("should not create the tenant", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("tenantGroup is not supported"))
+			Expect(adminClient.Tenants).To(BeEmpty())
+			Expect(tenant.Status.ID).To(BeNil())
+		})
+	})
+
+	When("the tenant was assigned to a tenant group outside of the operator", func() {
+		JustBeforeEach(func() {
+			liveStatus := adminClient.Tenants["sample-tenant"]
+			liveStatus.TenantGroup = "sample-group"
+			adminClient.Tenants["sample-tenant"] = liveStatus
+
+			_, err = reconcileTenant(tenant)
+			Expect(reloadTenant(tenant)).NotTo(HaveOccurred())
+		})
+
+		It("should keep the tenant group", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.Tenants["sample-tenant"].TenantGroup).To(Equal("sample-group"))
+			Expect(tenant.Status.TenantGroup).To(Equal("sample-group"))
+			Expect(tenant.Status.Generations.Reconciled).To(Equal(tenant.Generation))
+		})
+	})
+
+	When("deleting the tenant", func() {
+		var holdsData bool
+		var clusterDeleted bool
+		var reconcileErr error
+
+		BeforeEach(func() {
+			holdsData = false
+			clusterDeleted = false
+		})
+
+		JustBeforeEach(func() {
+			if holdsData {
+				adminClient.NonEmptyTenants["sample-tenant"] = fdbv1beta2.None{}
+			}
+
+			if clusterDeleted {
+				Expect(k8sClient.Delete(context.TODO(), cluster)).NotTo(HaveOccurred())
+			}
+
+			Expect(k8sClient.Delete(context.TODO(), tenant)).NotTo(HaveOccurred())
+
+			_, reconcileErr = reconcileTenant(tenant)
+		})
+
+		When("the tenant is empty", func() {
+			It("should delete the tenant and the resource", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(adminClient.Tenants).To(BeEmpty())
+				Expect(k8serrors.IsNotFound(reloadTenant(tenant))).To(BeTrue())
+			})
+		})
+
+		When("the tenant holds data", func() {
+			BeforeEach(func() {
+				holdsData = true
+			})
+
+			It("should not delete the tenant", func() {
+				Expect(reconcileErr).NotTo(HaveOccurred())
+				Expect(adminClient.Tenants).To(HaveKey("sample-tenant"))
+				Expect(reloadTenant(tenant)).NotTo(HaveOccurred())
+				Expect(tenant.DeletionTimestamp).NotTo(BeNil())
+				Expect(tenant.Finalizers).To(ContainElement(fdbv1beta2.TenantFinalizer))
+			})
+
+			When("the deletion is forced", func() {
+				BeforeEach(func() {
+					tenant.Spec.ForceDeletion = true
+				})
+
+				It("should clear the data and delete the tenant", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(adminClient.Tenants).To(BeEmpty())
+					Expect(adminClient.NonEmptyTenants).To(BeEmpty())
+					Expect(k8serrors.IsNotFound(reloadTenant(tenant))).To(BeTrue())
+				})
+			})
+
+			When("the cluster was deleted", func() {
+				BeforeEach(func() {
+					clusterDeleted = true
+				})
+
+				It("should remove the finalizer and delete the resource", func() {
+					Expect(reconcileErr).NotTo(HaveOccurred())
+					Expect(k8serrors.IsNotFound(reloadTenant(tenant))).To(BeTrue())
+				})
+			})
+		})
+	})
+})
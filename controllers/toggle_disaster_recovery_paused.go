@@ -0,0 +1,64 @@
+/*
+ * toggle_disaster_recovery_paused.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// toggleDisasterRecoveryPaused provides a reconciliation step for pausing and
+// resuming the DR agents.
+type toggleDisasterRecoveryPaused struct{}
+
+// reconcile runs the reconciler's work.
+func (s toggleDisasterRecoveryPaused) reconcile(ctx context.Context, r *FoundationDBDisasterRecoveryReconciler, dr *fdbv1beta2.FoundationDBDisasterRecovery) *requeue {
+	if dr.Status.DRDetails == nil || !dr.Status.DRDetails.Running {
+		if dr.ShouldRun() {
+			return &requeue{message: "Cannot toggle DR state because DR is not running"}
+		}
+		return nil
+	}
+
+	if dr.ShouldBePaused() == dr.Status.DRDetails.Paused {
+		return nil
+	}
+
+	sourceClusterName, destinationClusterName := dr.GetCurrentClusterNames()
+	adminClient, sourceConnectionString, err := r.adminClientForDisasterRecovery(ctx, dr, sourceClusterName, destinationClusterName)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	if dr.ShouldBePaused() {
+		err = adminClient.PauseDisasterRecovery(sourceConnectionString)
+	} else {
+		err = adminClient.ResumeDisasterRecovery(sourceConnectionString)
+	}
+
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
@@ -56,7 +56,13 @@ func (u updateBackupAgents) reconcile(ctx context.Context, r *FoundationDBBackup
 		}
 	}
 
-	deployment, err := internal.GetBackupDeployment(backup)
+	credentialsSecret, err := r.getCredentialsSecret(ctx, backup)
+	if err != nil {
+		r.Recorder.Event(backup, corev1.EventTypeWarning, "GetCredentialsSecret", err.Error())
+		return &requeue{curError: err}
+	}
+
+	deployment, err := internal.GetBackupDeployment(backup, credentialsSecret)
 	if err != nil {
 		r.Recorder.Event(backup, corev1.EventTypeWarning, "GetBackupDeployment", err.Error())
 		return &requeue{curError: err}
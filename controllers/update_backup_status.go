@@ -22,6 +22,10 @@ package controllers
 
 import (
 	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -39,6 +43,8 @@ type updateBackupStatus struct{}
 func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
 	status := fdbv1beta2.FoundationDBBackupStatus{}
 	status.Generations.Reconciled = backup.Status.Generations.Reconciled
+	status.Verification = backup.Status.Verification
+	status.Conditions = backup.Status.Conditions
 
 	backupDeployments := &appsv1.DeploymentList{}
 	err := r.List(ctx, backupDeployments, client.InNamespace(backup.Namespace), client.MatchingLabels(map[string]string{fdbv1beta2.BackupDeploymentLabel: string(backup.ObjectMeta.UID)}))
@@ -46,7 +52,12 @@ func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackup
 		return &requeue{curError: err}
 	}
 
-	desiredBackupDeployment, err := internal.GetBackupDeployment(backup)
+	credentialsSecret, err := r.getCredentialsSecret(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	desiredBackupDeployment, err := internal.GetBackupDeployment(backup, credentialsSecret)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -87,12 +98,7 @@ func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackup
 		return &requeue{curError: err}
 	}
 
-	status.BackupDetails = &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
-		URL:                   liveStatus.DestinationURL,
-		Running:               liveStatus.Status.Running,
-		Paused:                liveStatus.BackupAgentsPaused,
-		SnapshotPeriodSeconds: liveStatus.SnapshotIntervalSeconds,
-	}
+	status.BackupDetails = getBackupDetails(liveStatus)
 
 	originalStatus := backup.Status.DeepCopy()
 
@@ -103,6 +109,8 @@ func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackup
 		return &requeue{curError: err}
 	}
 
+	backup.UpdateConditions(time.Now())
+
 	if !equality.Semantic.DeepEqual(backup.Status, *originalStatus) {
 		err = r.updateOrApply(ctx, backup)
 		if err != nil {
@@ -113,3 +121,48 @@ func (s updateBackupStatus) reconcile(ctx context.Context, r *FoundationDBBackup
 
 	return nil
 }
+
+// getBackupDetails converts the live status of the backup into the details
+// that are stored in the backup status.
+func getBackupDetails(liveStatus *fdbv1beta2.FoundationDBLiveBackupStatus) *fdbv1beta2.FoundationDBBackupStatusBackupDetails {
+	details := &fdbv1beta2.FoundationDBBackupStatusBackupDetails{
+		URL:                   liveStatus.DestinationURL,
+		Running:               liveStatus.Status.Running,
+		Paused:                liveStatus.BackupAgentsPaused,
+		SnapshotPeriodSeconds: liveStatus.SnapshotIntervalSeconds,
+	}
+
+	if liveStatus.LatestRestorablePoint != nil {
+		restorablePoint := liveStatus.LatestRestorablePoint
+		details.LatestRestorableVersion = pointer.Int64(restorablePoint.Version)
+		details.SecondsBehind = int64(restorablePoint.LagSeconds)
+		if restorablePoint.EpochSeconds > 0 {
+			details.LatestRestorableTime = epochSecondsToTime(restorablePoint.EpochSeconds)
+		}
+	}
+
+	if liveStatus.CurrentSnapshot != nil {
+		details.SnapshotProgressPercent = int(liveStatus.CurrentSnapshot.ExpectedProgress)
+		if liveStatus.CurrentSnapshot.Begin.EpochSeconds > 0 {
+			details.CurrentSnapshotStartTime = epochSecondsToTime(liveStatus.CurrentSnapshot.Begin.EpochSeconds)
+		}
+	}
+
+	for _, backupError := range liveStatus.Errors {
+		if len(details.Errors) >= fdbv1beta2.MaxBackupStatusErrors {
+			break
+		}
+
+		details.Errors = append(details.Errors, backupError.Message)
+	}
+
+	return details
+}
+
+// epochSecondsToTime converts a unix timestamp from the backup status into a
+// time. The precision is reduced to seconds, since that is the precision
+// that is kept in the status.
+func epochSecondsToTime(epochSeconds float64) *metav1.Time {
+	timestamp := metav1.Unix(int64(epochSeconds), 0)
+	return &timestamp
+}
@@ -34,6 +34,12 @@ import (
 // database configuration.
 type updateDatabaseConfiguration struct{}
 
+// hasPendingDisruption returns true if the database configuration must be
+// changed.
+func (updateDatabaseConfiguration) hasPendingDisruption(cluster *fdbtypes.FoundationDBCluster) bool {
+	return cluster.Status.Generations.NeedsConfigurationChange > 0
+}
+
 // reconcile runs the reconciler's work.
 func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbtypes.FoundationDBCluster) *requeue {
 	if !pointer.BoolDeref(cluster.Spec.AutomationOptions.ConfigureDatabase, true) {
@@ -41,7 +47,7 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 	}
 
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateDatabaseConfiguration")
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err, delayedRequeue: true}
 	}
@@ -96,6 +102,7 @@ func (u updateDatabaseConfiguration) reconcile(ctx context.Context, r *Foundatio
 			fmt.Sprintf("Setting database configuration to `%s`", configurationString),
 		)
 		err = adminClient.ConfigureDatabase(nextConfiguration, initialConfig, cluster.Spec.Version)
+		r.AuditRecorder.Record(ctx, cluster, fdbtypes.AuditActionConfigureDatabase, []string{configurationString}, "Database configuration differs from the spec", err)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -0,0 +1,122 @@
+/*
+ * update_disaster_recovery_status.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// updateDisasterRecoveryStatus provides a reconciliation step for updating
+// the status in the CRD.
+type updateDisasterRecoveryStatus struct{}
+
+// reconcile runs the reconciler's work.
+func (s updateDisasterRecoveryStatus) reconcile(ctx context.Context, r *FoundationDBDisasterRecoveryReconciler, dr *fdbv1beta2.FoundationDBDisasterRecovery) *requeue {
+	status := fdbv1beta2.FoundationDBDisasterRecoveryStatus{}
+	status.Generations.Reconciled = dr.Status.Generations.Reconciled
+
+	agentDeployments := &appsv1.DeploymentList{}
+	err := r.List(ctx, agentDeployments, client.InNamespace(dr.Namespace), client.MatchingLabels(map[string]string{fdbv1beta2.DisasterRecoveryDeploymentLabel: string(dr.ObjectMeta.UID)}))
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	desiredAgentDeployment, err := internal.GetDisasterRecoveryDeployment(dr)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if len(agentDeployments.Items) == 1 && desiredAgentDeployment != nil {
+		agentDeployment := agentDeployments.Items[0]
+		status.AgentCount = int(agentDeployment.Status.ReadyReplicas)
+		if status.AgentCount > int(agentDeployment.Status.UpdatedReplicas) {
+			status.AgentCount = int(agentDeployment.Status.UpdatedReplicas)
+		}
+		generationsMatch := agentDeployment.Status.ObservedGeneration == agentDeployment.ObjectMeta.Generation
+
+		annotationChange := mergeAnnotations(&agentDeployment.ObjectMeta, desiredAgentDeployment.ObjectMeta)
+
+		metadataMatch := !annotationChange &&
+			equality.Semantic.DeepEqual(agentDeployment.ObjectMeta.Labels, desiredAgentDeployment.ObjectMeta.Labels)
+
+		status.DeploymentConfigured = generationsMatch && metadataMatch
+
+		if r.InSimulation {
+			status.AgentCount = int(*agentDeployment.Spec.Replicas)
+			status.DeploymentConfigured = metadataMatch
+		}
+	} else if len(agentDeployments.Items) == 0 && desiredAgentDeployment == nil {
+		status.DeploymentConfigured = true
+	} else {
+		status.DeploymentConfigured = false
+	}
+
+	// While a switchover is pending the running DR still uses the clusters
+	// from the status, so we have to fetch the live status for those.
+	sourceClusterName, destinationClusterName := dr.GetCurrentClusterNames()
+	adminClient, sourceConnectionString, err := r.adminClientForDisasterRecovery(ctx, dr, sourceClusterName, destinationClusterName)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	liveStatus, err := adminClient.GetDisasterRecoveryStatus(sourceConnectionString)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	status.DRDetails = &fdbv1beta2.FoundationDBDisasterRecoveryStatusDetails{
+		Running:         liveStatus.Running,
+		Paused:          liveStatus.Paused,
+		LagMilliseconds: int64(liveStatus.SecondsBehind * 1000),
+	}
+
+	if liveStatus.Running {
+		status.DRDetails.SourceClusterName = sourceClusterName
+		status.DRDetails.DestinationClusterName = destinationClusterName
+	}
+
+	originalStatus := dr.Status.DeepCopy()
+
+	dr.Status = status
+
+	_, err = dr.CheckReconciliation()
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if !equality.Semantic.DeepEqual(dr.Status, *originalStatus) {
+		err = r.updateOrApply(ctx, dr)
+		if err != nil {
+			log.Error(err, "Error updating disaster recovery status", "namespace", dr.Namespace, "disasterRecovery", dr.Name)
+			return &requeue{curError: err}
+		}
+	}
+
+	return nil
+}
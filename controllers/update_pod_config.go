@@ -23,7 +23,6 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 
@@ -100,7 +99,7 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 			continue
 		}
 
-		synced, err := r.updatePodDynamicConf(cluster, pod)
+		synced, err := r.updatePodDynamicConf(ctx, cluster, pod)
 		if !synced {
 			allSynced = false
 			if err != nil {
@@ -109,17 +108,17 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 
 			if internal.IsNetworkError(err) && processGroup.GetConditionTime(fdbv1beta2.SidecarUnreachable) == nil {
 				curLogger.Info("process group sidecar is not reachable")
-				processGroup.UpdateCondition(fdbv1beta2.SidecarUnreachable, true, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+				processGroup.UpdateConditionAt(fdbv1beta2.SidecarUnreachable, true, cluster.Status.ProcessGroups, processGroup.ProcessGroupID, r.now())
 				hasUpdate = true
 			} else if processGroup.GetConditionTime(fdbv1beta2.IncorrectConfigMap) == nil {
-				processGroup.UpdateCondition(fdbv1beta2.IncorrectConfigMap, true, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+				processGroup.UpdateConditionAt(fdbv1beta2.IncorrectConfigMap, true, cluster.Status.ProcessGroups, processGroup.ProcessGroupID, r.now())
 				hasUpdate = true
 				// If we are still waiting for a ConfigMap update we should not delay the requeue to ensure all processes are bounced
 				// at the same time. If the process is unreachable e.g. has the SidecarUnreachable status we can delay the requeue.
 				delayedRequeue = false
 			}
 
-			pod.ObjectMeta.Annotations[fdbv1beta2.OutdatedConfigMapKey] = fmt.Sprintf("%d", time.Now().Unix())
+			pod.ObjectMeta.Annotations[fdbv1beta2.OutdatedConfigMapKey] = fmt.Sprintf("%d", r.now().Unix())
 			err = r.PodLifecycleManager.UpdateMetadata(ctx, r, cluster, pod)
 			if err != nil {
 				allSynced = false
@@ -139,7 +138,7 @@ func (updatePodConfig) reconcile(ctx context.Context, r *FoundationDBClusterReco
 		}
 
 		hasUpdate = true
-		processGroup.UpdateCondition(fdbv1beta2.SidecarUnreachable, false, cluster.Status.ProcessGroups, processGroup.ProcessGroupID)
+		processGroup.UpdateConditionAt(fdbv1beta2.SidecarUnreachable, false, cluster.Status.ProcessGroups, processGroup.ProcessGroupID, r.now())
 	}
 
 	if hasUpdate {
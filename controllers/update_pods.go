@@ -27,6 +27,7 @@ import (
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/operations"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 	"github.com/go-logr/logr"
@@ -38,6 +39,12 @@ import (
 // specs.
 type updatePods struct{}
 
+// hasPendingDisruption returns true if process groups have a Pod with an
+// incorrect spec that must be deleted.
+func (updatePods) hasPendingDisruption(cluster *fdbv1beta2.FoundationDBCluster) bool {
+	return len(fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.IncorrectPodSpec, true)) > 0
+}
+
 // reconcile runs the reconciler's work.
 func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updatePods")
@@ -77,7 +84,7 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 			// TODO should not be continue but rather be a requeue?
 		}
 
-		if shouldRequeueDueToTerminatingPod(pod, cluster, processGroup.ProcessGroupID) {
+		if shouldRequeueDueToTerminatingPod(pod, cluster, processGroup.ProcessGroupID, r.now()) {
 			return &requeue{message: "Cluster has pod that is pending deletion", delay: podSchedulingDelayDuration, delayedRequeue: true}
 		}
 
@@ -101,7 +108,7 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 				"processGroupID", processGroup.ProcessGroupID,
 				"reason", fmt.Sprintf("specHash has changed from %s to %s", specHash, pod.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey]))
 
-			podClient, message := r.getPodClient(cluster, pod)
+			podClient, message := r.getPodClient(ctx, cluster, pod)
 			if podClient == nil {
 				return &requeue{message: message, delay: podSchedulingDelayDuration}
 			}
@@ -147,11 +154,17 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 		}
 	}
 
+	updates, blocked := planPodUpdates(cluster, updates, r.now())
 	if len(updates) == 0 {
+		if blocked > 0 {
+			logger.Info("Waiting for the operation queue to update pods", "blocked", blocked)
+			return &requeue{message: "Waiting for the operation queue to update pods", delay: podSchedulingDelayDuration, delayedRequeue: true}
+		}
+
 		return nil
 	}
 
-	adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r.Client)
+	adminClient, err := r.getAdminClient(ctx, cluster)
 	if err != nil {
 		return &requeue{curError: err}
 	}
@@ -160,9 +173,41 @@ func (updatePods) reconcile(ctx context.Context, r *FoundationDBClusterReconcile
 	return deletePodsForUpdates(ctx, r, cluster, adminClient, updates, logger)
 }
 
-func shouldRequeueDueToTerminatingPod(pod *corev1.Pod, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID) bool {
+// planPodUpdates queues the pod updates in the operation queue and returns
+// the updates that are allowed to run now together with the number of pods
+// that have to wait.
+func planPodUpdates(cluster *fdbv1beta2.FoundationDBCluster, updates map[string][]*corev1.Pod, now time.Time) (map[string][]*corev1.Pod, int) {
+	requests := make([]operations.Request, 0, len(updates))
+	for _, pods := range updates {
+		for _, pod := range pods {
+			requests = append(requests, operations.Request{ProcessGroupID: podmanager.GetProcessGroupID(cluster, pod), Reason: "Pod spec has changed"})
+		}
+	}
+
+	allowed := operations.Plan(cluster, fdbv1beta2.ProcessGroupOperationPodUpdate, requests, now)
+	if len(allowed) == len(requests) {
+		return updates, 0
+	}
+
+	blocked := 0
+	allowedUpdates := make(map[string][]*corev1.Pod, len(updates))
+	for zone, pods := range updates {
+		for _, pod := range pods {
+			if _, ok := allowed[podmanager.GetProcessGroupID(cluster, pod)]; !ok {
+				blocked++
+				continue
+			}
+
+			allowedUpdates[zone] = append(allowedUpdates[zone], pod)
+		}
+	}
+
+	return allowedUpdates, blocked
+}
+
+func shouldRequeueDueToTerminatingPod(pod *corev1.Pod, cluster *fdbv1beta2.FoundationDBCluster, processGroupID fdbv1beta2.ProcessGroupID, now time.Time) bool {
 	return pod.DeletionTimestamp != nil &&
-		pod.DeletionTimestamp.Add(time.Duration(cluster.GetIgnoreTerminatingPodsSeconds())*time.Second).After(time.Now()) &&
+		pod.DeletionTimestamp.Add(time.Duration(cluster.GetIgnoreTerminatingPodsSeconds())*time.Second).After(now) &&
 		!cluster.ProcessGroupIsBeingRemoved(processGroupID)
 }
 
@@ -236,7 +281,7 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 
 		logger.Info("Setting maintenance mode", "zone", zone)
 		cluster.Status.MaintenanceModeInfo = fdbv1beta2.MaintenanceModeInfo{
-			StartTimestamp: &metav1.Time{Time: time.Now()},
+			StartTimestamp: &metav1.Time{Time: r.now()},
 			ZoneID:         zone,
 			ProcessGroups:  processGroups,
 		}
@@ -245,6 +290,7 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 			return &requeue{curError: err}
 		}
 		err = adminClient.SetMaintenanceZone(zone, cluster.GetMaintenaceModeTimeoutSeconds())
+		r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionSetMaintenanceZone, []string{zone}, "Pods in the zone are recreated", err)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -254,9 +300,20 @@ func deletePodsForUpdates(ctx context.Context, r *FoundationDBClusterReconciler,
 	r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingPods", fmt.Sprintf("Recreating pods in zone %s", zone))
 
 	err = r.PodLifecycleManager.UpdatePods(logr.NewContext(ctx, logger), r, cluster, deletions, false)
+	r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionDeletePod, getPodNames(deletions), fmt.Sprintf("Pods in zone %s need to be recreated", zone), err)
 	if err != nil {
 		return &requeue{curError: err}
 	}
 
 	return &requeue{message: "Pods need to be recreated", delayedRequeue: true}
 }
+
+// getPodNames returns the names of the provided Pods.
+func getPodNames(pods []*corev1.Pod) []string {
+	names := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+
+	return names
+}
@@ -109,6 +109,67 @@ var _ = Describe("update_pods", func() {
 		)
 	})
 
+	When("planning the Pod updates through the operation queue", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var updates map[string][]*corev1.Pod
+
+		newPod := func(processGroupID string) *corev1.Pod {
+			return &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: processGroupID,
+					Labels: map[string]string{
+						fdbv1beta2.FDBProcessGroupIDLabel: processGroupID,
+					},
+				},
+			}
+		}
+
+		BeforeEach(func() {
+			cluster = &fdbv1beta2.FoundationDBCluster{
+				Spec: fdbv1beta2.FoundationDBClusterSpec{
+					AutomationOptions: fdbv1beta2.FoundationDBClusterAutomationOptions{
+						OperationQueue: fdbv1beta2.OperationQueueOptions{
+							Enabled: pointer.Bool(true),
+						},
+					},
+				},
+				Status: fdbv1beta2.FoundationDBClusterStatus{
+					ProcessGroups: []*fdbv1beta2.ProcessGroupStatus{
+						{ProcessGroupID: "storage-1", FaultDomain: "zone1"},
+						{ProcessGroupID: "storage-2", FaultDomain: "zone1"},
+						{ProcessGroupID: "storage-3", FaultDomain: "zone2"},
+					},
+				},
+			}
+
+			updates = map[string][]*corev1.Pod{
+				"zone1": {newPod("storage-1"), newPod("storage-2")},
+				"zone2": {newPod("storage-3")},
+			}
+		})
+
+		It("should only allow the updates in one fault domain", func() {
+			allowedUpdates, blocked := planPodUpdates(cluster, updates, time.Now())
+			Expect(blocked).To(Equal(1))
+			Expect(allowedUpdates).To(HaveLen(1))
+			Expect(allowedUpdates["zone1"]).To(HaveLen(2))
+			Expect(cluster.Status.Operations).To(HaveLen(3))
+		})
+
+		When("the operation queue is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.OperationQueue.Enabled = nil
+			})
+
+			It("should allow all updates", func() {
+				allowedUpdates, blocked := planPodUpdates(cluster, updates, time.Now())
+				Expect(blocked).To(BeZero())
+				Expect(allowedUpdates).To(Equal(updates))
+				Expect(cluster.Status.Operations).To(BeEmpty())
+			})
+		})
+	})
+
 	Context("Validating shouldRequeueDueToTerminatingPod", func() {
 		var processGroup = fdbv1beta2.ProcessGroupID("")
 
@@ -125,7 +186,7 @@ var _ = Describe("update_pods", func() {
 			})
 
 			It("should not requeue due to terminating pods", func() {
-				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup)).To(BeFalse())
+				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup, time.Now())).To(BeFalse())
 			})
 		})
 
@@ -143,7 +204,7 @@ var _ = Describe("update_pods", func() {
 			})
 
 			It("should requeue due to terminating pods", func() {
-				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup)).To(BeTrue())
+				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup, time.Now())).To(BeTrue())
 			})
 		})
 
@@ -162,7 +223,7 @@ var _ = Describe("update_pods", func() {
 			})
 
 			It("should not requeue", func() {
-				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup)).To(BeFalse())
+				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup, time.Now())).To(BeFalse())
 			})
 		})
 
@@ -187,7 +248,7 @@ var _ = Describe("update_pods", func() {
 			})
 
 			It("should not requeue", func() {
-				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup)).To(BeFalse())
+				Expect(shouldRequeueDueToTerminatingPod(pod, cluster, processGroup, time.Now())).To(BeFalse())
 			})
 		})
 	})
@@ -27,6 +27,7 @@ import (
 	"time"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal/locality"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/recommendations"
 
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
 	"github.com/go-logr/logr"
@@ -38,6 +39,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -52,6 +54,20 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	status := fdbv1beta2.FoundationDBClusterStatus{}
 	// Pass through Maintenance Mode Info as the maintenance_mode_checker reconciler takes care of updating it
 	originalStatus.MaintenanceModeInfo.DeepCopyInto(&status.MaintenanceModeInfo)
+	// Pass through the TLS information as the updateTLSCertificates reconciler takes care of updating the trusted CAs
+	// and the certificates.
+	originalStatus.TLS.DeepCopyInto(&status.TLS)
+	// Pass through the last coordinator IP recovery as the recoverCoordinatorIPs reconciler takes care of updating it.
+	status.CoordinatorIPRecovery = originalStatus.CoordinatorIPRecovery
+	status.PendingCoordinatorIPRecovery = originalStatus.PendingCoordinatorIPRecovery
+	// Pass through the last storage scaling as the manageStorageCapacity reconciler takes care of updating it.
+	status.StorageScaling = originalStatus.StorageScaling
+	// Pass through the last autoscaling decisions as the autoscaleCluster reconciler takes care of updating them.
+	status.Autoscaling = originalStatus.Autoscaling
+	// Pass through the conditions as they are recomputed from the new status below.
+	status.Conditions = cluster.Status.Conditions
+	// Pass through the pending disruptions as they are updated by the reconciliation loop.
+	status.PendingDisruptions = cluster.Status.PendingDisruptions
 	status.Generations.Reconciled = cluster.Status.Generations.Reconciled
 
 	// Initialize with the current desired storage servers per Pod
@@ -70,13 +86,13 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 			},
 		}
 	} else {
-		connectionString, err := tryConnectionOptions(logger, cluster, r)
+		connectionString, err := tryConnectionOptions(ctx, logger, cluster, r)
 		if err != nil {
 			return &requeue{curError: err}
 		}
 		cluster.Status.ConnectionString = connectionString
 
-		adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+		adminClient, err := r.getAdminClient(ctx, cluster)
 		if err != nil {
 			return &requeue{curError: err}
 		}
@@ -151,6 +167,20 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 
 	cluster.Status.RequiredAddresses = status.RequiredAddresses
 
+	if cluster.GetResourceRecommendationsEnabled() {
+		status.ResourceRecommendations = recommendations.UpdateRecommendations(cluster, originalStatus.ResourceRecommendations, databaseStatus, r.now())
+	}
+
+	if databaseStatus != nil {
+		status.TLS.Transition = getTLSTransition(cluster, status.RequiredAddresses, databaseStatus)
+		if status.TLS.Transition != originalStatus.TLS.Transition {
+			logger.Info("TLS transition changed", "previous", originalStatus.TLS.Transition, "current", status.TLS.Transition)
+			if status.TLS.Transition != "" {
+				r.Recorder.Event(cluster, corev1.EventTypeNormal, "TLSTransition", fmt.Sprintf("TLS transition is in step %s", status.TLS.Transition))
+			}
+		}
+	}
+
 	configMap, err := internal.GetConfigMap(cluster)
 	if err != nil {
 		return &requeue{curError: err}
@@ -173,6 +203,9 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		return &requeue{curError: err}
 	}
 	removeDuplicateConditions(status)
+	status.Scale = getScaleStatus(cluster, status.ProcessGroups)
+	// Pass through the queued operations as the reconcilers that plan the operations take care of updating them.
+	status.Operations = getRemainingOperations(cluster, originalStatus.Operations, status.ProcessGroups)
 
 	existingConfigMap := &corev1.ConfigMap{}
 	err = r.Get(ctx, types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}, existingConfigMap)
@@ -218,17 +251,19 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 	status.HasIncorrectServiceConfig = (service == nil) != (existingService == nil)
 
 	if status.Configured && cluster.Status.ConnectionString != "" {
-		coordinatorStatus := make(map[string]bool, len(databaseStatus.Client.Coordinators.Coordinators))
-		for _, coordinator := range databaseStatus.Client.Coordinators.Coordinators {
-			coordinatorStatus[coordinator.Address.String()] = false
-		}
-
+		coordinatorStatus := locality.GetCoordinatorStatus(databaseStatus)
 		coordinatorsValid, _, err := locality.CheckCoordinatorValidity(logger, cluster, databaseStatus, coordinatorStatus)
 		if err != nil {
 			return &requeue{curError: err, delayedRequeue: true}
 		}
 
 		status.NeedsNewCoordinators = !coordinatorsValid
+		for _, healthy := range coordinatorStatus {
+			if !healthy {
+				status.HasUnhealthyCoordinators = true
+				break
+			}
+		}
 	}
 
 	if len(cluster.Spec.LockOptions.DenyList) > 0 && cluster.ShouldUseLocks() && status.Configured {
@@ -266,6 +301,8 @@ func (updateStatus) reconcile(ctx context.Context, r *FoundationDBClusterReconci
 		return &requeue{curError: err}
 	}
 
+	cluster.UpdateConditions(r.now())
+
 	// See: https://github.com/kubernetes-sigs/kubebuilder/issues/592
 	// If we use the default reflect.DeepEqual method it will be recreating the
 	// status multiple times because the pointers are different.
@@ -307,7 +344,7 @@ func optionList(options ...string) []string {
 
 // tryConnectionOptions attempts to connect with all the connection strings for this cluster and
 // returns the connection string that allows connecting to the cluster.
-func tryConnectionOptions(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, r *FoundationDBClusterReconciler) (string, error) {
+func tryConnectionOptions(ctx context.Context, logger logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, r *FoundationDBClusterReconciler) (string, error) {
 	connectionStrings := optionList(cluster.Status.ConnectionString, cluster.Spec.SeedConnectionString)
 
 	if len(connectionStrings) == 1 {
@@ -322,7 +359,7 @@ func tryConnectionOptions(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCl
 	for _, connectionString := range connectionStrings {
 		logger.Info("Attempting to get connection string from cluster", "connectionString", connectionString)
 		cluster.Status.ConnectionString = connectionString
-		adminClient, clientErr := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+		adminClient, clientErr := r.getAdminClient(ctx, cluster)
 		if clientErr != nil {
 			return originalConnectionString, clientErr
 		}
@@ -345,7 +382,7 @@ func tryConnectionOptions(logger logr.Logger, cluster *fdbv1beta2.FoundationDBCl
 }
 
 // checkAndSetProcessStatus checks the status of the Process and if missing or incorrect add it to the related status field
-func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, processMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo, processNumber int, processCount int, processGroupStatus *fdbv1beta2.ProcessGroupStatus) error {
+func checkAndSetProcessStatus(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, processMap map[fdbv1beta2.ProcessGroupID][]fdbv1beta2.FoundationDBStatusProcessInfo, processNumber int, processCount int, processGroupStatus *fdbv1beta2.ProcessGroupStatus) error {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateStatus")
 	processID := processGroupStatus.ProcessGroupID
 
@@ -355,12 +392,32 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1be
 
 	processStatus := processMap[processID]
 
-	processGroupStatus.UpdateCondition(fdbv1beta2.MissingProcesses, len(processStatus) == 0, cluster.Status.ProcessGroups, processID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.MissingProcesses, len(processStatus) == 0, cluster.Status.ProcessGroups, processID, r.now())
 	if len(processStatus) == 0 {
 		return nil
 	}
 
-	podClient, message := r.getPodClient(cluster, pod)
+	// The LowDiskSpace condition is only used by the storage capacity management.
+	lowDiskSpace := false
+	if cluster.GetStorageCapacityManagementEnabled() {
+		for _, process := range processStatus {
+			if internal.HasLowDiskSpace(process, cluster.GetLowDiskSpaceThresholdPercent()) {
+				lowDiskSpace = true
+				break
+			}
+		}
+	}
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.LowDiskSpace, lowDiskSpace, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
+
+	for _, process := range processStatus {
+		faultDomain := process.Locality[fdbv1beta2.FDBLocalityZoneIDKey]
+		if faultDomain != "" {
+			processGroupStatus.FaultDomain = faultDomain
+			break
+		}
+	}
+
+	podClient, message := r.getPodClient(ctx, cluster, pod)
 	if podClient == nil {
 		logger.Info("Unable to build pod client", "processGroupID", processGroupStatus.ProcessGroupID, "message", message)
 		return nil
@@ -371,7 +428,7 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1be
 		commandLine, err := internal.GetStartCommand(cluster, processGroupStatus.ProcessClass, podClient, processNumber, processCount)
 		if err != nil {
 			if internal.IsNetworkError(err) {
-				processGroupStatus.UpdateCondition(fdbv1beta2.SidecarUnreachable, true, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+				processGroupStatus.UpdateConditionAt(fdbv1beta2.SidecarUnreachable, true, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 				return nil
 			}
 
@@ -388,9 +445,9 @@ func checkAndSetProcessStatus(r *FoundationDBClusterReconciler, cluster *fdbv1be
 		}
 	}
 
-	processGroupStatus.UpdateCondition(fdbv1beta2.IncorrectCommandLine, !correct, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.IncorrectCommandLine, !correct, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 	// Reset status for sidecar unreachable, since we are here at this point we were able to reach the sidecar for the substitute variables.
-	processGroupStatus.UpdateCondition(fdbv1beta2.SidecarUnreachable, false, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.SidecarUnreachable, false, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 
 	return nil
 }
@@ -407,7 +464,7 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 	// Clear the IncorrectCommandLine condition to prevent it being held over
 	// when pods get deleted.
 	for _, processGroup := range processGroups {
-		processGroup.UpdateCondition(fdbv1beta2.IncorrectCommandLine, false, nil, "")
+		processGroup.UpdateConditionAt(fdbv1beta2.IncorrectCommandLine, false, nil, "", r.now())
 	}
 
 	podMap := internal.CreatePodMap(cluster, pods)
@@ -422,9 +479,9 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 			// Mark process groups as terminating if the pod has been deleted but other
 			// resources are stuck in terminating.
 			if isBeingRemoved {
-				processGroup.UpdateCondition(fdbv1beta2.ResourcesTerminating, true, processGroups, processGroup.ProcessGroupID)
+				processGroup.UpdateConditionAt(fdbv1beta2.ResourcesTerminating, true, processGroups, processGroup.ProcessGroupID, r.now())
 			} else {
-				processGroup.UpdateCondition(fdbv1beta2.MissingPod, true, processGroups, processGroup.ProcessGroupID)
+				processGroup.UpdateConditionAt(fdbv1beta2.MissingPod, true, processGroups, processGroup.ProcessGroupID, r.now())
 			}
 			continue
 		}
@@ -436,15 +493,15 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 		if !pod.ObjectMeta.DeletionTimestamp.IsZero() {
 			// If the ProcessGroup is marked for removal we can put the status into ResourcesTerminating
 			if processGroup.IsMarkedForRemoval() {
-				processGroup.UpdateCondition(fdbv1beta2.ResourcesTerminating, true, processGroups, processGroup.ProcessGroupID)
+				processGroup.UpdateConditionAt(fdbv1beta2.ResourcesTerminating, true, processGroups, processGroup.ProcessGroupID, r.now())
 				continue
 			}
 			// Otherwise we set PodFailing to ensure that the operator will trigger a replacement. This case can happen
 			// if a Pod is marked for terminating (e.g. node failure) but the process itself is still reporting to the
 			// cluster. We only set this condition if the Pod is in this state for GetFailedPodDuration(), the default
 			// here is 5 minutes.
-			if pod.ObjectMeta.DeletionTimestamp.Add(cluster.GetFailedPodDuration()).Before(time.Now()) {
-				processGroup.UpdateCondition(fdbv1beta2.PodFailing, true, processGroups, processGroup.ProcessGroupID)
+			if pod.ObjectMeta.DeletionTimestamp.Add(cluster.GetFailedPodDuration()).Before(r.now()) {
+				processGroup.UpdateConditionAt(fdbv1beta2.PodFailing, true, processGroups, processGroup.ProcessGroupID, r.now())
 				continue
 			}
 		}
@@ -499,7 +556,7 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 
 		// In theory we could also support multiple processes per pod for different classes
 		for i := 1; i <= processCount; i++ {
-			err := checkAndSetProcessStatus(r, cluster, pod, processMap, i, processCount, processGroup)
+			err := checkAndSetProcessStatus(ctx, r, cluster, pod, processMap, i, processCount, processGroup)
 			if err != nil {
 				return processGroups, err
 			}
@@ -530,7 +587,7 @@ func validateProcessGroups(ctx context.Context, r *FoundationDBClusterReconciler
 // returns failing, incorrect, error
 func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, currentPVC *corev1.PersistentVolumeClaim, configMapHash string, processGroupStatus *fdbv1beta2.ProcessGroupStatus) error {
 	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateStatus")
-	processGroupStatus.UpdateCondition(fdbv1beta2.MissingPod, pod == nil, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.MissingPod, pod == nil, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 	if pod == nil {
 		return nil
 	}
@@ -554,10 +611,10 @@ func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 		incorrectPod = !updated
 	}
 
-	processGroupStatus.UpdateCondition(fdbv1beta2.IncorrectPodSpec, incorrectPod, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.IncorrectPodSpec, incorrectPod, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 
 	incorrectConfigMap := pod.ObjectMeta.Annotations[fdbv1beta2.LastConfigMapKey] != configMapHash
-	processGroupStatus.UpdateCondition(fdbv1beta2.IncorrectConfigMap, incorrectConfigMap, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.IncorrectConfigMap, incorrectConfigMap, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 
 	desiredPvc, err := internal.GetPvc(cluster, processGroupStatus.ProcessClass, idNum)
 	if err != nil {
@@ -569,10 +626,10 @@ func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 		incorrectPVC = !metadataMatches(currentPVC.ObjectMeta, desiredPvc.ObjectMeta)
 	}
 
-	processGroupStatus.UpdateCondition(fdbv1beta2.MissingPVC, incorrectPVC, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.MissingPVC, incorrectPVC, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 
 	if pod.Status.Phase == corev1.PodPending {
-		processGroupStatus.UpdateCondition(fdbv1beta2.PodPending, true, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+		processGroupStatus.UpdateConditionAt(fdbv1beta2.PodPending, true, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 		return nil
 	}
 
@@ -592,19 +649,20 @@ func validateProcessGroup(ctx context.Context, r *FoundationDBClusterReconciler,
 
 		// Only recreate the Pod if it is already 5 minutes up (just to prevent to recreate the Pod multiple times
 		// and give the cluster some time to get the kubelet up
-		if pod.Status.Reason == "NodeAffinity" && pod.CreationTimestamp.Add(5*time.Minute).Before(time.Now()) {
+		if pod.Status.Reason == "NodeAffinity" && pod.CreationTimestamp.Add(5*time.Minute).Before(r.now()) {
 			logger.Info("Delete Pod that is stuck in NodeAffinity",
 				"processGroupID", processGroupStatus.ProcessGroupID)
 
 			err = r.PodLifecycleManager.DeletePod(logr.NewContext(ctx, logger), r, pod)
+			r.AuditRecorder.Record(ctx, cluster, fdbv1beta2.AuditActionDeletePod, []string{pod.Name}, "Pod is stuck in NodeAffinity", err)
 			if err != nil {
 				return err
 			}
 		}
 	}
 
-	processGroupStatus.UpdateCondition(fdbv1beta2.PodFailing, failing, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
-	processGroupStatus.UpdateCondition(fdbv1beta2.PodPending, false, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID)
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.PodFailing, failing, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
+	processGroupStatus.UpdateConditionAt(fdbv1beta2.PodPending, false, cluster.Status.ProcessGroups, processGroupStatus.ProcessGroupID, r.now())
 
 	return nil
 }
@@ -732,3 +790,92 @@ func getRunningVersion(versionMap map[string]int, fallback string) (string, erro
 
 	return currentCandidate.String(), nil
 }
+
+// getTLSTransition determines the current step of a transition between TLS and
+// non-TLS, based on the addresses that the processes are listening on.
+func getTLSTransition(cluster *fdbv1beta2.FoundationDBCluster, requiredAddresses fdbv1beta2.RequiredAddressSet, databaseStatus *fdbv1beta2.FoundationDBStatus) fdbv1beta2.TLSTransitionPhase {
+	desiredTLS := cluster.Spec.MainContainer.EnableTLS
+	// The required addresses contain both address types as long as any
+	// coordinator uses an address that doesn't match the spec.
+	needsBothListeners := requiredAddresses.TLS && requiredAddresses.NonTLS
+
+	for _, process := range databaseStatus.Cluster.Processes {
+		if process.Excluded || process.ProcessClass == fdbv1beta2.ProcessClassTest {
+			continue
+		}
+
+		addresses, err := fdbv1beta2.ParseProcessAddressesFromCmdline(process.CommandLine)
+		if err != nil {
+			// The coordinators will only be changed once the addresses of
+			// all processes can be parsed.
+			if needsBothListeners {
+				return fdbv1beta2.TLSTransitionAddListeners
+			}
+			continue
+		}
+
+		hasDesiredListener := false
+		hasOtherListener := false
+		for _, address := range addresses {
+			if address.Flags["tls"] == desiredTLS {
+				hasDesiredListener = true
+			} else {
+				hasOtherListener = true
+			}
+		}
+
+		if needsBothListeners && !hasDesiredListener {
+			return fdbv1beta2.TLSTransitionAddListeners
+		}
+
+		if !needsBothListeners && hasOtherListener {
+			return fdbv1beta2.TLSTransitionRemoveListeners
+		}
+	}
+
+	if needsBothListeners {
+		return fdbv1beta2.TLSTransitionChangeCoordinators
+	}
+
+	return ""
+}
+
+// getScaleStatus returns the information for the scale subresource. The
+// replicas are the process groups of the scaled process class that are not
+// marked for removal.
+func getScaleStatus(cluster *fdbv1beta2.FoundationDBCluster, processGroups []*fdbv1beta2.ProcessGroupStatus) fdbv1beta2.ScaleStatus {
+	processClass := cluster.GetScaleProcessClass()
+	replicas := 0
+	for _, processGroup := range processGroups {
+		if processGroup.ProcessClass == processClass && !processGroup.IsMarkedForRemoval() {
+			replicas++
+		}
+	}
+
+	return fdbv1beta2.ScaleStatus{
+		Replicas: replicas,
+		Selector: labels.SelectorFromSet(internal.GetPodMatchLabels(cluster, processClass, "")).String(),
+	}
+}
+
+// getRemainingOperations returns the queued operations for process groups
+// that still exist. If the operation queue is disabled, the queue is cleared.
+func getRemainingOperations(cluster *fdbv1beta2.FoundationDBCluster, operations []fdbv1beta2.ProcessGroupOperation, processGroups []*fdbv1beta2.ProcessGroupStatus) []fdbv1beta2.ProcessGroupOperation {
+	if !cluster.GetOperationQueueEnabled() || len(operations) == 0 {
+		return nil
+	}
+
+	existing := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(processGroups))
+	for _, processGroup := range processGroups {
+		existing[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	remaining := make([]fdbv1beta2.ProcessGroupOperation, 0, len(operations))
+	for _, operation := range operations {
+		if _, ok := existing[operation.ProcessGroupID]; ok {
+			remaining = append(remaining, operation)
+		}
+	}
+
+	return remaining
+}
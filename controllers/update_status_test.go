@@ -22,6 +22,7 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"time"
 
@@ -37,6 +38,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -499,6 +501,28 @@ var _ = Describe("update_status", func() {
 			Expect(cluster.Status.Generations.Reconciled).To(Equal(cluster.ObjectMeta.Generation))
 		})
 
+		It("should set the conditions of the cluster", func() {
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(fdbv1beta2.ClusterReconciled))).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(fdbv1beta2.ClusterAvailable))).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions, string(fdbv1beta2.ClusterProgressing))).To(BeTrue())
+		})
+
+		When("the spec of the cluster is changed", func() {
+			BeforeEach(func() {
+				cluster.Spec.ProcessCounts.Storage = 5
+				Expect(k8sClient.Update(context.TODO(), cluster)).NotTo(HaveOccurred())
+			})
+
+			It("should mark the cluster as progressing", func() {
+				condition := meta.FindStatusCondition(cluster.Status.Conditions, string(fdbv1beta2.ClusterReconciled))
+				Expect(condition).NotTo(BeNil())
+				Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+				Expect(condition.Reason).To(Equal("NeedsGrow"))
+				Expect(condition.ObservedGeneration).To(Equal(cluster.ObjectMeta.Generation))
+				Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions, string(fdbv1beta2.ClusterProgressing))).To(BeTrue())
+			})
+		})
+
 		When("disabling an explicit listen address", func() {
 			BeforeEach(func() {
 				result, err := reconcileCluster(cluster)
@@ -530,4 +554,250 @@ var _ = Describe("update_status", func() {
 			"7.1.15": 50,
 		}, "0", "7.1.15"),
 		Entry("when the versionMap is empty", map[string]int{}, "7.1.15", "7.1.15"))
+
+	DescribeTable("when getting the TLS transition", func(enableTLS bool, requiredAddresses fdbv1beta2.RequiredAddressSet, commandLines []string, expected fdbv1beta2.TLSTransitionPhase) {
+		cluster := internal.CreateDefaultCluster()
+		cluster.Spec.MainContainer.EnableTLS = enableTLS
+
+		status := &fdbv1beta2.FoundationDBStatus{
+			Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+				Processes: map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{},
+			},
+		}
+		for index, commandLine := range commandLines {
+			status.Cluster.Processes[fdbv1beta2.ProcessGroupID(fmt.Sprintf("storage-%d", index))] = fdbv1beta2.FoundationDBStatusProcessInfo{
+				ProcessClass: fdbv1beta2.ProcessClassStorage,
+				CommandLine:  commandLine,
+			}
+		}
+
+		Expect(getTLSTransition(cluster, requiredAddresses, status)).To(Equal(expected))
+	},
+		Entry("without a transition",
+			false,
+			fdbv1beta2.RequiredAddressSet{NonTLS: true},
+			[]string{"--public_address=1.1.1.1:4501", "--public_address=1.1.1.2:4501"},
+			fdbv1beta2.TLSTransitionPhase(""),
+		),
+		Entry("when some processes don't listen on the TLS address",
+			true,
+			fdbv1beta2.RequiredAddressSet{NonTLS: true, TLS: true},
+			[]string{"--public_address=1.1.1.1:4500:tls,1.1.1.1:4501", "--public_address=1.1.1.2:4501"},
+			fdbv1beta2.TLSTransitionAddListeners,
+		),
+		Entry("when the address of a process can't be parsed",
+			true,
+			fdbv1beta2.RequiredAddressSet{NonTLS: true, TLS: true},
+			[]string{"--public_address=1.1.1.1:4500:tls,1.1.1.1:4501", ""},
+			fdbv1beta2.TLSTransitionAddListeners,
+		),
+		Entry("when all processes listen on the TLS address",
+			true,
+			fdbv1beta2.RequiredAddressSet{NonTLS: true, TLS: true},
+			[]string{"--public_address=1.1.1.1:4500:tls,1.1.1.1:4501", "--public_address=1.1.1.2:4500:tls,1.1.1.2:4501"},
+			fdbv1beta2.TLSTransitionChangeCoordinators,
+		),
+		Entry("when the coordinators use TLS and some processes still listen on the non-TLS address",
+			true,
+			fdbv1beta2.RequiredAddressSet{TLS: true},
+			[]string{"--public_address=1.1.1.1:4500:tls", "--public_address=1.1.1.2:4500:tls,1.1.1.2:4501"},
+			fdbv1beta2.TLSTransitionRemoveListeners,
+		),
+		Entry("when the coordinators use non-TLS and some processes still listen on the TLS address",
+			false,
+			fdbv1beta2.RequiredAddressSet{NonTLS: true},
+			[]string{"--public_address=1.1.1.1:4501,1.1.1.1:4500:tls", "--public_address=1.1.1.2:4501"},
+			fdbv1beta2.TLSTransitionRemoveListeners,
+		),
+	)
+
+	When("resource recommendations are enabled", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(setupClusterForTest(cluster)).To(Succeed())
+
+			adminClient, err := mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			for processGroupID, process := range status.Cluster.Processes {
+				process.CPU.UsageCores = 0.5
+				process.Memory.UsedBytes = 1024 * 1024 * 1024
+				status.Cluster.Processes[processGroupID] = process
+			}
+			adminClient.FrozenStatus = status
+
+			cluster.Spec.ResourceRecommendations.Enabled = pointer.Bool(true)
+		})
+
+		It("should add the recommendations to the status", func() {
+			Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(cluster.Status.ResourceRecommendations).To(HaveLen(4))
+			for _, recommendation := range cluster.Status.ResourceRecommendations {
+				Expect(recommendation.Samples).To(Equal(1))
+				cpu := recommendation.Requests[corev1.ResourceCPU]
+				Expect(cpu.String()).To(Equal("600m"))
+			}
+		})
+
+		When("the recommendations are disabled again", func() {
+			It("should remove the recommendations from the status", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				cluster.Spec.ResourceRecommendations.Enabled = nil
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.ResourceRecommendations).To(BeEmpty())
+			})
+		})
+	})
+
+	When("a process is running low on disk space", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var adminClient *mock.AdminClient
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.AutomationOptions.StorageCapacity.Enabled = pointer.Bool(true)
+			Expect(setupClusterForTest(cluster)).To(Succeed())
+
+			var err error
+			adminClient, err = mock.NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			for processID, process := range status.Cluster.Processes {
+				if process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey] != "storage-1" {
+					continue
+				}
+
+				process.Roles = append(process.Roles, fdbv1beta2.FoundationDBStatusProcessRoleInfo{
+					Role:                  string(fdbv1beta2.ProcessRoleStorage),
+					KVStoreAvailableBytes: 5 * 1024 * 1024 * 1024,
+					KVStoreTotalBytes:     100 * 1024 * 1024 * 1024,
+				})
+				status.Cluster.Processes[processID] = process
+			}
+			adminClient.FrozenStatus = status
+		})
+
+		It("should add the LowDiskSpace condition to the process group", func() {
+			Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.LowDiskSpace, false)).To(ConsistOf(fdbv1beta2.ProcessGroupID("storage-1")))
+		})
+
+		When("the storage capacity management is disabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.StorageCapacity.Enabled = nil
+			})
+
+			It("should not add the LowDiskSpace condition", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.LowDiskSpace, false)).To(BeEmpty())
+			})
+		})
+
+		When("the threshold is lower than the available disk space", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.StorageCapacity.LowDiskSpaceThresholdPercent = pointer.Int(5)
+			})
+
+			It("should not add the LowDiskSpace condition", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(fdbv1beta2.FilterByCondition(cluster.Status.ProcessGroups, fdbv1beta2.LowDiskSpace, false)).To(BeEmpty())
+			})
+		})
+
+		When("the operator increased the storage process count before", func() {
+			BeforeEach(func() {
+				cluster.Status.StorageScaling = &fdbv1beta2.StorageScalingStatus{Timestamp: 1, PreviousCount: 4, Count: 5}
+			})
+
+			It("should keep the record when the status is updated", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.StorageScaling).NotTo(BeNil())
+				Expect(cluster.Status.StorageScaling.Count).To(Equal(5))
+			})
+		})
+	})
+
+	When("updating the information for the scale subresource", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			Expect(setupClusterForTest(cluster)).To(Succeed())
+		})
+
+		It("should report the storage process groups", func() {
+			Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(cluster.Status.Scale).To(Equal(fdbv1beta2.ScaleStatus{
+				Replicas: 4,
+				Selector: "foundationdb.org/fdb-cluster-name=operator-test-1,foundationdb.org/fdb-process-class=storage",
+			}))
+		})
+
+		When("a storage process group is marked for removal", func() {
+			BeforeEach(func() {
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if processGroup.ProcessGroupID == "storage-1" {
+						processGroup.MarkForRemoval()
+					}
+				}
+			})
+
+			It("should not count the process group", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.Scale.Replicas).To(Equal(3))
+			})
+		})
+
+		When("a different process class is scaled", func() {
+			BeforeEach(func() {
+				cluster.Spec.Scale.ProcessClass = fdbv1beta2.ProcessClassClusterController
+			})
+
+			It("should report the process groups of that class", func() {
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.Scale).To(Equal(fdbv1beta2.ScaleStatus{
+					Replicas: 1,
+					Selector: "foundationdb.org/fdb-cluster-name=operator-test-1,foundationdb.org/fdb-process-class=cluster_controller",
+				}))
+			})
+		})
+	})
+
+	When("the operation queue is enabled", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		BeforeEach(func() {
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.AutomationOptions.OperationQueue.Enabled = pointer.Bool(true)
+			Expect(setupClusterForTest(cluster)).To(Succeed())
+			cluster.Status.Operations = []fdbv1beta2.ProcessGroupOperation{
+				{ProcessGroupID: "storage-1", Type: fdbv1beta2.ProcessGroupOperationPodUpdate},
+				{ProcessGroupID: "storage-100", Type: fdbv1beta2.ProcessGroupOperationPodUpdate},
+			}
+		})
+
+		It("should record the fault domain of the process groups", func() {
+			Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(fdbv1beta2.FindProcessGroupByID(cluster.Status.ProcessGroups, "storage-1").FaultDomain).To(Equal("operator-test-1-storage-1"))
+		})
+
+		It("should keep the operations of existing process groups", func() {
+			Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+			Expect(cluster.Status.Operations).To(ConsistOf(fdbv1beta2.ProcessGroupOperation{ProcessGroupID: "storage-1", Type: fdbv1beta2.ProcessGroupOperationPodUpdate}))
+		})
+
+		When("the operation queue is disabled again", func() {
+			It("should clear the operations", func() {
+				cluster.Spec.AutomationOptions.OperationQueue.Enabled = nil
+				Expect(updateStatus{}.reconcile(context.TODO(), clusterReconciler, cluster)).To(BeNil())
+				Expect(cluster.Status.Operations).To(BeEmpty())
+			})
+		})
+	})
 })
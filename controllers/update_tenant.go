@@ -0,0 +1,70 @@
+/*
+ * update_tenant.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateTenant provides a reconciliation step for creating a tenant.
+type updateTenant struct{}
+
+// reconcile runs the reconciler's work.
+func (u updateTenant) reconcile(ctx context.Context, r *FoundationDBTenantReconciler, tenant *fdbv1beta2.FoundationDBTenant) *requeue {
+	// The finalizer must be present before the tenant is created, otherwise
+	// the tenant could be left behind in the cluster.
+	if !controllerutil.ContainsFinalizer(tenant, fdbv1beta2.TenantFinalizer) {
+		controllerutil.AddFinalizer(tenant, fdbv1beta2.TenantFinalizer)
+		err := r.Update(ctx, tenant)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	adminClient, err := r.adminClientForTenant(ctx, tenant)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	tenantName := tenant.GetTenantName()
+	liveStatus, err := adminClient.GetTenant(tenantName)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if liveStatus == nil {
+		err = adminClient.CreateTenant(tenantName, "")
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		r.Recorder.Event(tenant, corev1.EventTypeNormal, "CreateTenant", fmt.Sprintf("Created tenant %s", tenantName))
+	}
+
+	return nil
+}
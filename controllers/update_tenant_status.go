@@ -0,0 +1,77 @@
+/*
+ * update_tenant_status.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateTenantStatus provides a reconciliation step for updating the status in
+// the CRD.
+type updateTenantStatus struct{}
+
+// reconcile runs the reconciler's work.
+func (s updateTenantStatus) reconcile(ctx context.Context, r *FoundationDBTenantReconciler, tenant *fdbv1beta2.FoundationDBTenant) *requeue {
+	adminClient, err := r.adminClientForTenant(ctx, tenant)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	defer adminClient.Close()
+
+	liveStatus, err := adminClient.GetTenant(tenant.GetTenantName())
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	status := fdbv1beta2.FoundationDBTenantStatus{}
+	status.Generations.Reconciled = tenant.Status.Generations.Reconciled
+
+	if liveStatus != nil {
+		status.ID = pointer.Int64(liveStatus.ID)
+		status.State = liveStatus.State
+		status.TenantGroup = liveStatus.TenantGroup
+	}
+
+	originalStatus := tenant.Status.DeepCopy()
+
+	tenant.Status = status
+
+	reconciled := tenant.CheckReconciliation()
+
+	if !equality.Semantic.DeepEqual(tenant.Status, *originalStatus) {
+		err = r.updateOrApply(ctx, tenant)
+		if err != nil {
+			log.Error(err, "Error updating tenant status", "namespace", tenant.Namespace, "tenant", tenant.Name)
+			return &requeue{curError: err}
+		}
+	}
+
+	if !reconciled {
+		return &requeue{message: "Tenant was not fully reconciled by reconciliation process"}
+	}
+
+	return nil
+}
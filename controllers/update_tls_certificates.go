@@ -0,0 +1,146 @@
+/*
+ * update_tls_certificates.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// updateTLSCertificates provides a reconciliation step for reporting the
+// certificates of a cluster and for rolling out changes to the trusted CAs.
+type updateTLSCertificates struct{}
+
+// reconcile runs the reconciler's work.
+func (u updateTLSCertificates) reconcile(ctx context.Context, r *FoundationDBClusterReconciler, cluster *fdbv1beta2.FoundationDBCluster) *requeue {
+	logger := log.WithValues("namespace", cluster.Namespace, "cluster", cluster.Name, "reconciler", "updateTLSCertificates")
+
+	secretNames := cluster.GetTLSCertificateSecrets()
+	secrets := make([]*corev1.Secret, 0, len(secretNames))
+	var certificates []fdbv1beta2.CertificateStatus
+	var secretCAs []string
+
+	for _, secretName := range secretNames {
+		secret := &corev1.Secret{}
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, secret)
+		if err != nil {
+			if !k8serrors.IsNotFound(err) {
+				return &requeue{curError: err}
+			}
+
+			certificates = append(certificates, fdbv1beta2.CertificateStatus{
+				SecretName: secretName,
+				Error:      "Secret not found",
+			})
+			continue
+		}
+
+		certificate := internal.GetCertificateStatus(secret)
+		for _, previous := range cluster.Status.TLS.Certificates {
+			if previous.SecretName == secretName && previous.SerialNumber != "" && certificate.SerialNumber != "" && previous.SerialNumber != certificate.SerialNumber {
+				logger.Info("Detected rotated certificate", "secret", secretName, "serialNumber", certificate.SerialNumber)
+				r.Recorder.Event(cluster, corev1.EventTypeNormal, "CertificateRotated", fmt.Sprintf("Certificate in Secret %s was rotated, new certificate expires at %s", secretName, certificate.NotAfter.UTC()))
+			}
+		}
+
+		certificates = append(certificates, certificate)
+		secrets = append(secrets, secret)
+
+		ca := internal.GetCAFromSecret(secret)
+		if ca != "" {
+			secretCAs = append(secretCAs, ca)
+		}
+	}
+
+	desiredCAs := internal.MergeTrustedCAs(cluster.Spec.TrustedCAs, secretCAs)
+	currentCAs := cluster.GetTrustedCAs()
+
+	// New CAs are added first, so that all processes trust the new CAs before
+	// any process presents a certificate issued by one of them.
+	trustedCAs := internal.MergeTrustedCAs(currentCAs, desiredCAs)
+
+	// Once all new CAs are rolled out, the old CAs can be removed.
+	if len(trustedCAs) == len(currentCAs) && len(trustedCAs) != len(desiredCAs) && canRemoveTrustedCAs(cluster, secrets, desiredCAs) {
+		logger.Info("Removing old trusted CAs")
+		trustedCAs = desiredCAs
+	}
+
+	// The trusted CAs are only tracked in the status if they differ from the
+	// spec.
+	if equality.Semantic.DeepEqual(trustedCAs, cluster.Spec.TrustedCAs) {
+		trustedCAs = nil
+	}
+
+	tlsStatus := fdbv1beta2.TLSStatus{
+		Transition:   cluster.Status.TLS.Transition,
+		TrustedCAs:   trustedCAs,
+		Certificates: certificates,
+	}
+
+	if !equality.Semantic.DeepEqual(cluster.Status.TLS, tlsStatus) {
+		previousCount := len(cluster.GetTrustedCAs())
+		cluster.Status.TLS = tlsStatus
+		if previousCount != len(cluster.GetTrustedCAs()) {
+			r.Recorder.Event(cluster, corev1.EventTypeNormal, "UpdatingTrustedCAs", fmt.Sprintf("Changing the number of trusted CAs from %d to %d", previousCount, len(cluster.GetTrustedCAs())))
+		}
+
+		err := r.updateOrApply(ctx, cluster)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+	}
+
+	if len(internal.MergeTrustedCAs(cluster.GetTrustedCAs(), desiredCAs)) != len(desiredCAs) {
+		return &requeue{message: "Waiting for the old trusted CAs to be removed", delayedRequeue: true}
+	}
+
+	return nil
+}
+
+// canRemoveTrustedCAs checks if all processes have received the new CAs and
+// if all certificates are issued by one of the new CAs.
+func canRemoveTrustedCAs(cluster *fdbv1beta2.FoundationDBCluster, secrets []*corev1.Secret, desiredCAs []string) bool {
+	if cluster.Status.HasIncorrectConfigMap {
+		return false
+	}
+
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if processGroup.GetConditionTime(fdbv1beta2.IncorrectConfigMap) != nil {
+			return false
+		}
+	}
+
+	for _, secret := range secrets {
+		if !internal.IsCertificateTrusted(secret, desiredCAs) {
+			return false
+		}
+	}
+
+	return true
+}
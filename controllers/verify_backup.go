@@ -0,0 +1,374 @@
+/*
+ * verify_backup.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// verifyBackup provides a reconciliation step for periodically restoring the
+// backup into a temporary cluster to verify that the backup is restorable.
+type verifyBackup struct{}
+
+// reconcile runs the reconciler's work.
+func (v verifyBackup) reconcile(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) *requeue {
+	logger := log.WithValues("namespace", backup.Namespace, "backup", backup.Name, "reconciler", "verifyBackup")
+
+	status := backup.Status.Verification
+	if status == nil || status.Phase == "" {
+		if !backup.ShouldVerify() || time.Now().Before(backup.NextVerificationTime()) {
+			return nil
+		}
+
+		if backup.Spec.BlobStoreConfiguration.GetBackend() == fdbv1beta2.BlobStoreBackendFile {
+			r.Recorder.Event(backup, corev1.EventTypeWarning, "BackupVerificationNotSupported", fmt.Sprintf("Backups with the %s backend cannot be verified, because restores from the %s backend are not supported", fdbv1beta2.BlobStoreBackendFile, fdbv1beta2.BlobStoreBackendFile))
+			return nil
+		}
+
+		restorable, err := isBackupRestorable(ctx, r, backup)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		if !restorable {
+			logger.Info("Backup is not restorable yet, skipping verification")
+			return nil
+		}
+
+		return startBackupVerification(ctx, r, backup, logger)
+	}
+
+	// If the verification was disabled while a run was in progress we abort the
+	// current run and clean up the temporary resources.
+	if !backup.ShouldVerify() {
+		return finishBackupVerification(ctx, r, backup, false, "Verification was disabled", logger)
+	}
+
+	if status.StartTime != nil && time.Since(status.StartTime.Time) > backup.VerificationTimeout() {
+		return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Verification did not complete within %s", backup.VerificationTimeout()), logger)
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: backup.Namespace, Name: status.ClusterName}, cluster)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return finishBackupVerification(ctx, r, backup, false, "Verification cluster was deleted", logger)
+		}
+		return &requeue{curError: err}
+	}
+
+	if !internal.IsBackupVerificationObject(backup, cluster) {
+		return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Cluster %s doesn't belong to the verification of the backup", cluster.Name), logger)
+	}
+
+	switch status.Phase {
+	case fdbv1beta2.BackupVerificationPhaseCreatingCluster:
+		if !cluster.Status.Configured || cluster.Status.Generations.Reconciled < cluster.ObjectMeta.Generation {
+			logger.V(1).Info("Waiting for verification cluster to be reconciled", "cluster", cluster.Name)
+			return nil
+		}
+
+		restore := internal.GetBackupVerificationRestore(backup)
+		logger.Info("Starting restore into verification cluster", "cluster", cluster.Name, "restore", restore.Name)
+		created, err := createBackupVerificationObject(ctx, r, backup, restore)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		if !created {
+			return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Restore %s doesn't belong to the verification of the backup", restore.Name), logger)
+		}
+
+		return updateBackupVerificationPhase(ctx, r, backup, fdbv1beta2.BackupVerificationPhaseRestoring)
+	case fdbv1beta2.BackupVerificationPhaseRestoring:
+		adminClient, err := r.getDatabaseClientProvider().GetAdminClient(cluster, r)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		defer adminClient.Close()
+
+		restoreStatus, err := adminClient.GetRestoreStatus()
+		if err != nil {
+			return &requeue{curError: err}
+		}
+
+		switch getRestoreState(restoreStatus) {
+		case "completed":
+		case "aborted":
+			return finishBackupVerification(ctx, r, backup, false, "Restore into verification cluster was aborted", logger)
+		default:
+			logger.V(1).Info("Waiting for restore into verification cluster", "cluster", cluster.Name)
+			return nil
+		}
+
+		job := internal.GetBackupVerificationJob(backup)
+		if job == nil {
+			return finishBackupVerification(ctx, r, backup, true, "Backup was restored successfully", logger)
+		}
+
+		logger.Info("Starting check job", "job", job.Name)
+		created, err := createBackupVerificationObject(ctx, r, backup, job)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		if !created {
+			return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Job %s doesn't belong to the verification of the backup", job.Name), logger)
+		}
+
+		return updateBackupVerificationPhase(ctx, r, backup, fdbv1beta2.BackupVerificationPhaseChecking)
+	case fdbv1beta2.BackupVerificationPhaseChecking:
+		desiredJob := internal.GetBackupVerificationJob(backup)
+		if desiredJob == nil {
+			return finishBackupVerification(ctx, r, backup, true, "Backup was restored successfully", logger)
+		}
+
+		job := &batchv1.Job{}
+		err = r.Get(ctx, client.ObjectKeyFromObject(desiredJob), job)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				return finishBackupVerification(ctx, r, backup, false, "Check job was deleted", logger)
+			}
+			return &requeue{curError: err}
+		}
+
+		if !internal.IsBackupVerificationObject(backup, job) {
+			return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Job %s doesn't belong to the verification of the backup", job.Name), logger)
+		}
+
+		if job.Status.Succeeded > 0 {
+			return finishBackupVerification(ctx, r, backup, true, "Backup was restored and checked successfully", logger)
+		}
+
+		if job.Status.Failed > 0 {
+			return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Check job %s failed", job.Name), logger)
+		}
+
+		logger.V(1).Info("Waiting for check job", "job", job.Name)
+		return nil
+	}
+
+	return &requeue{curError: fmt.Errorf("unknown verification phase %s", status.Phase)}
+}
+
+// isBackupRestorable checks if the running backup has reached a restorable
+// version.
+func isBackupRestorable(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup) (bool, error) {
+	if backup.Status.BackupDetails == nil || !backup.Status.BackupDetails.Running {
+		return false, nil
+	}
+
+	adminClient, err := r.adminClientForBackup(ctx, backup)
+	if err != nil {
+		return false, err
+	}
+	defer adminClient.Close()
+
+	status, err := adminClient.GetStatus()
+	if err != nil {
+		return false, err
+	}
+
+	for _, tag := range status.Cluster.Layers.Backup.Tags {
+		if tag.CurrentContainer == backup.Status.BackupDetails.URL && tag.Restorable {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// startBackupVerification creates the temporary cluster and the backup
+// agents for the temporary cluster and starts a new verification run.
+func startBackupVerification(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, logger logr.Logger) *requeue {
+	cluster := internal.GetBackupVerificationCluster(backup)
+	logger.Info("Starting backup verification", "cluster", cluster.Name)
+
+	if backup.Status.Verification == nil {
+		backup.Status.Verification = &fdbv1beta2.BackupVerificationStatus{}
+	}
+
+	startTime := metav1.Now()
+	backup.Status.Verification.ClusterName = cluster.Name
+	backup.Status.Verification.StartTime = &startTime
+
+	created, err := createBackupVerificationObject(ctx, r, backup, cluster)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+	if !created {
+		return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Cluster %s doesn't belong to the verification of the backup", cluster.Name), logger)
+	}
+
+	credentialsSecret, err := r.getCredentialsSecret(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	deployment, err := internal.GetBackupVerificationAgentDeployment(backup, credentialsSecret)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	if deployment != nil {
+		created, err = createBackupVerificationObject(ctx, r, backup, deployment)
+		if err != nil {
+			return &requeue{curError: err}
+		}
+		if !created {
+			return finishBackupVerification(ctx, r, backup, false, fmt.Sprintf("Deployment %s doesn't belong to the verification of the backup", deployment.Name), logger)
+		}
+	}
+
+	r.Recorder.Event(backup, corev1.EventTypeNormal, "BackupVerificationStarted", fmt.Sprintf("Restoring backup into cluster %s", cluster.Name))
+
+	return updateBackupVerificationPhase(ctx, r, backup, fdbv1beta2.BackupVerificationPhaseCreatingCluster)
+}
+
+// createBackupVerificationObject creates an object for the current
+// verification run. An existing object is only reused if it belongs to the
+// verification of the backup, otherwise this returns false.
+func createBackupVerificationObject(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, object client.Object) (bool, error) {
+	err := r.Create(ctx, object)
+	if err == nil {
+		return true, nil
+	}
+
+	if !k8serrors.IsAlreadyExists(err) {
+		return false, err
+	}
+
+	existing, ok := object.DeepCopyObject().(client.Object)
+	if !ok {
+		return false, fmt.Errorf("could not copy object %s", object.GetName())
+	}
+
+	err = r.Get(ctx, client.ObjectKeyFromObject(object), existing)
+	if err != nil {
+		return false, err
+	}
+
+	return internal.IsBackupVerificationObject(backup, existing), nil
+}
+
+// updateBackupVerificationPhase updates the phase of the current
+// verification run.
+func updateBackupVerificationPhase(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, phase fdbv1beta2.BackupVerificationPhase) *requeue {
+	backup.Status.Verification.Phase = phase
+	err := r.updateOrApply(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// finishBackupVerification records the result of the current verification
+// run and removes the temporary resources.
+func finishBackupVerification(ctx context.Context, r *FoundationDBBackupReconciler, backup *fdbv1beta2.FoundationDBBackup, passed bool, message string, logger logr.Logger) *requeue {
+	logger.Info("Finishing backup verification", "passed", passed, "message", message)
+
+	objects := []client.Object{
+		&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-check", backup.VerificationClusterName())}},
+		&fdbv1beta2.FoundationDBRestore{ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: fmt.Sprintf("%s-backup-agents", backup.VerificationClusterName())}},
+		&fdbv1beta2.FoundationDBCluster{ObjectMeta: metav1.ObjectMeta{Namespace: backup.Namespace, Name: backup.VerificationClusterName()}},
+	}
+
+	for _, object := range objects {
+		err := r.Get(ctx, client.ObjectKeyFromObject(object), object)
+		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return &requeue{curError: err}
+		}
+
+		// Objects with the same name that were not created for the
+		// verification of this backup are left untouched.
+		if !internal.IsBackupVerificationObject(backup, object) {
+			logger.Info("Not deleting object that doesn't belong to the verification", "name", object.GetName(), "type", fmt.Sprintf("%T", object))
+			continue
+		}
+
+		err = r.Delete(ctx, object, client.PropagationPolicy(metav1.DeletePropagationBackground))
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return &requeue{curError: err}
+		}
+	}
+
+	status := backup.Status.Verification
+	completionTime := metav1.Now()
+	result := &fdbv1beta2.BackupVerificationResult{
+		Passed:         passed,
+		CompletionTime: completionTime,
+		Message:        message,
+	}
+
+	if status.StartTime != nil {
+		result.StartTime = *status.StartTime
+		result.DurationSeconds = int(completionTime.Sub(status.StartTime.Time).Seconds())
+	}
+
+	backup.Status.Verification = &fdbv1beta2.BackupVerificationStatus{
+		LastResult: result,
+	}
+
+	eventType := corev1.EventTypeNormal
+	reason := "BackupVerificationPassed"
+	if !passed {
+		eventType = corev1.EventTypeWarning
+		reason = "BackupVerificationFailed"
+	}
+	r.Recorder.Event(backup, eventType, reason, message)
+
+	err := r.updateOrApply(ctx, backup)
+	if err != nil {
+		return &requeue{curError: err}
+	}
+
+	return nil
+}
+
+// getRestoreState returns the state of the restore from the output of
+// `fdbrestore status`.
+func getRestoreState(status string) string {
+	fields := strings.Fields(status)
+	for idx, field := range fields {
+		if field == "State:" && idx+1 < len(fields) {
+			return fields[idx+1]
+		}
+	}
+
+	return ""
+}
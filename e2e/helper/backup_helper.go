@@ -0,0 +1,373 @@
+/*
+ * backup_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/e2e-framework/klient/decoder"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+	"sigs.k8s.io/e2e-framework/klient/wait"
+	"sigs.k8s.io/e2e-framework/klient/wait/conditions"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+const (
+	// minioConfigDirectory is the directory that contains the configuration of MinIO.
+	minioConfigDirectory = "../config/minio"
+
+	// backupConfigDirectory is the directory that contains the configuration of the backup tests.
+	backupConfigDirectory = "../config/tests/backup"
+
+	// backupTestKey is the key that is written before the backup and read after the restore.
+	backupTestKey = "e2e-backup-key"
+
+	// backupTestValue is the value of the backupTestKey.
+	backupTestValue = "e2e-backup-value"
+)
+
+// BackupRestoreTest returns an e2e test that backs up a cluster into MinIO from config/minio, restores the backup
+// into a new cluster and assess that the data written before the backup was restored.
+func BackupRestoreTest(version fdbv1beta2.Version, t *testing.T) features.Feature {
+	return features.
+		New("backup and restore cluster for "+version.Compact()).
+		WithLabel("type", "backup-restore").
+		WithLabel("profile", "backup").
+		Setup(installMinIO).
+		Setup(createClusterStep("fdb-backup", version, nil)).
+		Setup(waitForReconciliationStep).
+		Setup(func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			_, err := runFdbCli(ctx, cfg, getTestCluster(ctx), "writemode on; set "+backupTestKey+" "+backupTestValue)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should start the backup", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			backup, err := getBackupFromFile(ctx, version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = cfg.Client().Resources(backup.Namespace).Create(ctx, backup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should have a restorable backup", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			err := wait.For(conditions.New(cfg.Client().Resources(getTestCluster(ctx).Namespace)).ResourceMatch(getTestBackup(ctx), func(object k8s.Object) bool {
+				backup := object.(*fdbv1beta2.FoundationDBBackup)
+				return backup.Status.BackupDetails != nil && backup.Status.BackupDetails.Running && backup.Status.BackupDetails.LatestRestorableVersion != nil
+			}), wait.WithTimeout(10*time.Minute), wait.WithInterval(10*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should stop the backup", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			backup := getTestBackup(ctx)
+			err := cfg.Client().Resources(backup.Namespace).Get(ctx, backup.Name, backup.Namespace, backup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			backup.Spec.BackupState = fdbv1beta2.BackupStateStopped
+			err = cfg.Client().Resources(backup.Namespace).Update(ctx, backup)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = wait.For(conditions.New(cfg.Client().Resources(backup.Namespace)).ResourceMatch(backup, func(object k8s.Object) bool {
+				current := object.(*fdbv1beta2.FoundationDBBackup)
+				return current.Status.Generations.Reconciled == current.ObjectMeta.Generation
+			}), wait.WithTimeout(5*time.Minute), wait.WithInterval(5*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should restore the backup into a new cluster", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			namespace := ctx.Value(keyNamespaceID).(string)
+			restoreClusterName := envconf.RandomName("fdb-restore", 32)
+
+			restoreCluster := createFoundationDBCluster(restoreClusterName, namespace, version.String())
+			err := cfg.Client().Resources(namespace).Create(ctx, restoreCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = waitForClusterReconciled(ctx, cfg, restoreCluster, 10*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			restore, err := getRestoreFromFile(ctx, restoreClusterName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = cfg.Client().Resources(namespace).Create(ctx, restore)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return context.WithValue(ctx, keyRestoreClusterNameID, restoreClusterName)
+		}).
+		Assess("it should restore the data written before the backup", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			restoreCluster := getTestRestoreCluster(ctx)
+
+			// The restore has no status that shows if the restore is done, so we wait until the key is readable.
+			err := wait.For(func() (bool, error) {
+				output, err := runFdbCli(ctx, cfg, restoreCluster, "get "+backupTestKey)
+				if err != nil {
+					t.Logf("could not read key from restored cluster: %s", err.Error())
+					return false, nil
+				}
+
+				return strings.Contains(output, backupTestValue), nil
+			}, wait.WithTimeout(10*time.Minute), wait.WithInterval(10*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Teardown(func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			namespace := ctx.Value(keyNamespaceID).(string)
+
+			objects := []k8s.Object{getTestBackup(ctx)}
+			if ctx.Value(keyRestoreClusterNameID) != nil {
+				objects = append(objects, getTestRestore(ctx), getTestRestoreCluster(ctx))
+			}
+
+			for _, object := range objects {
+				err := cfg.Client().Resources(namespace).Delete(ctx, object)
+				if err != nil {
+					t.Error(err)
+				}
+			}
+
+			return ctx
+		}).
+		Teardown(deleteClusterStep).Feature()
+}
+
+// installMinIO installs the MinIO operator and a MinIO instance from config/minio into the namespace of the test and
+// waits until MinIO is ready. The overrides from config/minio/instance_overrides.yaml are applied to the instance.
+func installMinIO(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+	namespace := ctx.Value(keyNamespaceID).(string)
+
+	r, err := resources.New(cfg.Client().RESTConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The MinIO operator is installed in its own namespace and is shared between all tests.
+	err = decoder.DecodeEachFile(ctx, os.DirFS(minioConfigDirectory), "operator.yaml",
+		decoder.CreateIgnoreAlreadyExists(r),
+		mutateDeploymentImages(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	overrides := &unstructured.Unstructured{}
+	err = decoder.DecodeFile(os.DirFS(minioConfigDirectory), "instance_overrides.yaml", overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The MinIOInstance can only be created once the CRD from operator.yaml is served. A new client is created for
+	// every attempt to make sure that the REST mapping is discovered again.
+	err = wait.For(func() (bool, error) {
+		r, err := resources.New(cfg.Client().RESTConfig())
+		if err != nil {
+			return false, err
+		}
+
+		err = decoder.DecodeEachFile(ctx, os.DirFS(minioConfigDirectory), "instance.yaml",
+			decoder.CreateIgnoreAlreadyExists(r),
+			decoder.MutateNamespace(namespace),
+			mutateMinIOInstance(overrides),
+		)
+		if err != nil {
+			t.Logf("could not create MinIO instance: %s", err.Error())
+			return false, nil
+		}
+
+		return true, nil
+	}, wait.WithTimeout(2*time.Minute), wait.WithInterval(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = decoder.DecodeEachFile(ctx, os.DirFS(minioConfigDirectory), "service.yaml",
+		decoder.CreateHandler(r),
+		decoder.MutateNamespace(namespace),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The MinIO operator creates a StatefulSet named after the instance.
+	minioPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "minio-0", Namespace: namespace}}
+	err = wait.For(conditions.New(cfg.Client().Resources(namespace)).PodReady(minioPod), wait.WithTimeout(5*time.Minute), wait.WithInterval(5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ctx
+}
+
+// mutateDeploymentImages updates the images of all deployments to use the images from the registry of the tests.
+func mutateDeploymentImages() decoder.DecodeOption {
+	return decoder.MutateOption(func(obj k8s.Object) error {
+		deployment, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return nil
+		}
+
+		setContainerImages(&deployment.Spec.Template.Spec)
+
+		return nil
+	})
+}
+
+// mutateMinIOInstance merges the overrides into the spec of the MinIO instance and updates the image of the instance
+// to use the registry of the tests.
+func mutateMinIOInstance(overrides *unstructured.Unstructured) decoder.DecodeOption {
+	return decoder.MutateOption(func(obj k8s.Object) error {
+		instance, ok := obj.(*unstructured.Unstructured)
+		if !ok || instance.GetKind() != overrides.GetKind() {
+			return nil
+		}
+
+		mergeMaps(instance.Object, overrides.Object)
+
+		image, found, err := unstructured.NestedString(instance.Object, "spec", "image")
+		if err != nil || !found {
+			return err
+		}
+
+		return unstructured.SetNestedField(instance.Object, getImage(image), "spec", "image")
+	})
+}
+
+// mergeMaps merges the values from the source into the target like a JSON merge patch, without removing any values.
+func mergeMaps(target map[string]interface{}, source map[string]interface{}) {
+	for key, value := range source {
+		sourceMap, sourceIsMap := value.(map[string]interface{})
+		targetMap, targetIsMap := target[key].(map[string]interface{})
+		if sourceIsMap && targetIsMap {
+			mergeMaps(targetMap, sourceMap)
+			continue
+		}
+
+		target[key] = value
+	}
+}
+
+// getBackupFromFile reads the backup from config/tests/backup/base and updates it to back up the cluster of the test.
+func getBackupFromFile(ctx context.Context, version fdbv1beta2.Version) (*fdbv1beta2.FoundationDBBackup, error) {
+	testCluster := getTestCluster(ctx)
+
+	backup := &fdbv1beta2.FoundationDBBackup{}
+	err := decoder.DecodeFile(os.DirFS(backupConfigDirectory), "base/backup.yaml", backup, decoder.MutateNamespace(testCluster.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	backup.Name = testCluster.Name
+	backup.Spec.ClusterName = testCluster.Name
+	backup.Spec.Version = version.String()
+	backup.Spec.SnapshotPeriodSeconds = pointer.Int(30)
+	backup.Spec.MainContainer.ImageConfigs = getImageConfigs(foundationDBImage)
+	backup.Spec.SidecarContainer.ImageConfigs = getImageConfigs(sidecarImage)
+
+	return backup, nil
+}
+
+// getRestoreFromFile reads the restore from config/tests/backup/restore and updates it to restore the backup of the
+// test into the destination cluster.
+func getRestoreFromFile(ctx context.Context, destinationClusterName string) (*fdbv1beta2.FoundationDBRestore, error) {
+	testCluster := getTestCluster(ctx)
+
+	restore := &fdbv1beta2.FoundationDBRestore{}
+	err := decoder.DecodeFile(os.DirFS(backupConfigDirectory), "restore/restore.yaml", restore, decoder.MutateNamespace(testCluster.Namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	restore.Name = destinationClusterName
+	restore.Spec.DestinationClusterName = destinationClusterName
+	restore.Spec.BlobStoreConfiguration.BackupName = testCluster.Name
+
+	return restore, nil
+}
+
+// getTestBackup returns a reference to the backup of the test.
+func getTestBackup(ctx context.Context) *fdbv1beta2.FoundationDBBackup {
+	testCluster := getTestCluster(ctx)
+
+	return &fdbv1beta2.FoundationDBBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testCluster.Name,
+			Namespace: testCluster.Namespace,
+		},
+	}
+}
+
+// getTestRestore returns a reference to the restore of the test.
+func getTestRestore(ctx context.Context) *fdbv1beta2.FoundationDBRestore {
+	return &fdbv1beta2.FoundationDBRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Value(keyRestoreClusterNameID).(string),
+			Namespace: ctx.Value(keyNamespaceID).(string),
+		},
+	}
+}
+
+// getTestRestoreCluster returns a reference to the cluster that the backup of the test is restored into.
+func getTestRestoreCluster(ctx context.Context) *fdbv1beta2.FoundationDBCluster {
+	return &fdbv1beta2.FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Value(keyRestoreClusterNameID).(string),
+			Namespace: ctx.Value(keyNamespaceID).(string),
+		},
+	}
+}
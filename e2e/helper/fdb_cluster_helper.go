@@ -22,6 +22,7 @@ package helper
 
 import (
 	"context"
+	"net"
 	"testing"
 	"time"
 
@@ -30,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/e2e-framework/klient/k8s"
 	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
 	"sigs.k8s.io/e2e-framework/klient/wait"
@@ -43,6 +45,8 @@ type contextKey int
 const (
 	keyNamespaceID contextKey = iota
 	keyClusterNameID
+	keyReplacedProcessGroupsID
+	keyRestoreClusterNameID
 )
 
 // GetTestFDBVersions returns a slice that contains all FDB versions we use to do e2e tests.
@@ -59,51 +63,11 @@ func CreateSingleClusterTest(version fdbv1beta2.Version, t *testing.T) features.
 	return features.
 		New("create single cluster for "+version.Compact()).
 		WithLabel("type", "create-cluster").
-		Setup(func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
-			clusterName := envconf.RandomName("fdb-create", 32)
-			namespace := ctx.Value(keyNamespaceID).(string)
-
-			testCluster := createFoundationDBCluster(clusterName, namespace, version.String())
-
-			err := cfg.Client().Resources(namespace).Create(ctx, testCluster)
-			if err != nil {
-				t.Error(err)
-			}
-
-			return context.WithValue(ctx, keyClusterNameID, clusterName)
-		}).
-		Assess("it should create an FDB cluster in "+version.Compact(), func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
-			namespace := ctx.Value(keyNamespaceID).(string)
-			clusterName := ctx.Value(keyClusterNameID).(string)
-
-			testCluster := &fdbv1beta2.FoundationDBCluster{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      clusterName,
-					Namespace: namespace,
-				},
-			}
-
-			err := wait.For(conditions.New(cfg.Client().Resources(namespace)).ResourceMatch(testCluster, func(object k8s.Object) bool {
-				cluster := object.(*fdbv1beta2.FoundationDBCluster)
-				return cluster.Status.Generations.Reconciled >= 1
-			}), wait.WithTimeout(time.Minute*3))
-
-			if err != nil {
-				t.Error(err)
-			}
-
-			return ctx
-		}).
+		Setup(createClusterStep("fdb-create", version, nil)).
+		Assess("it should create an FDB cluster in "+version.Compact(), waitForReconciliationStep).
 		Assess("it should create all desired Pods", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
-			namespace := ctx.Value(keyNamespaceID).(string)
-			clusterName := ctx.Value(keyClusterNameID).(string)
-
-			testCluster := &fdbv1beta2.FoundationDBCluster{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      clusterName,
-					Namespace: namespace,
-				},
-			}
+			testCluster := getTestCluster(ctx)
+			namespace := testCluster.Namespace
 
 			err := cfg.Client().Resources(namespace).Get(ctx, testCluster.Name, testCluster.Namespace, testCluster)
 			if err != nil {
@@ -157,28 +121,116 @@ Expected Log: %d, got: %d
 
 			return ctx
 		}).
-		Teardown(func(ctx context.Context, t *testing.T, config *envconf.Config) context.Context {
-			namespace := ctx.Value(keyNamespaceID).(string)
-			clusterName := ctx.Value(keyClusterNameID).(string)
-
-			testCluster := &fdbv1beta2.FoundationDBCluster{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      clusterName,
-					Namespace: namespace,
-				},
+		Teardown(deleteClusterStep).Feature()
+}
+
+// CreateDualStackClusterTest returns an e2e test that creates a cluster with IPv6 pod addresses and assess that all
+// processes and coordinators use IPv6 addresses. This test requires a dual-stack or IPv6-only Kubernetes cluster and is
+// labelled with the dual-stack profile, so it can be skipped with --skip-labels="profile=dual-stack".
+func CreateDualStackClusterTest(version fdbv1beta2.Version, t *testing.T) features.Feature {
+	return features.
+		New("create dual-stack cluster for "+version.Compact()).
+		WithLabel("type", "create-cluster").
+		WithLabel("profile", "dual-stack").
+		Setup(createClusterStep("fdb-dual-stack", version, pointer.Int(6))).
+		Assess("it should create an FDB cluster in "+version.Compact(), waitForReconciliationStep).
+		Assess("it should use IPv6 addresses for all processes", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			testCluster := getTestCluster(ctx)
+
+			err := cfg.Client().Resources(testCluster.Namespace).Get(ctx, testCluster.Name, testCluster.Namespace, testCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, processGroup := range testCluster.Status.ProcessGroups {
+				for _, address := range processGroup.Addresses {
+					ip := net.ParseIP(address)
+					if ip == nil || ip.To4() != nil {
+						t.Errorf("expected process group %s to have an IPv6 address, got: %s", processGroup.ProcessGroupID, address)
+					}
+				}
 			}
 
-			err := config.Client().Resources(namespace).Delete(ctx, testCluster)
+			connectionString, err := fdbv1beta2.ParseConnectionString(testCluster.Status.ConnectionString)
 			if err != nil {
-				t.Error(err)
+				t.Fatal(err)
+			}
+
+			for _, coordinator := range connectionString.Coordinators {
+				address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+				if err != nil {
+					t.Error(err)
+					continue
+				}
+
+				if address.IPAddress == nil || address.IPAddress.To4() != nil {
+					t.Errorf("expected coordinator to have an IPv6 address, got: %s", coordinator)
+				}
 			}
 
 			return ctx
-		}).Feature()
+		}).
+		Teardown(deleteClusterStep).Feature()
 }
 
-func createFoundationDBCluster(clusterName string, namespace string, version string) *fdbv1beta2.FoundationDBCluster {
+// createClusterStep returns a step that creates a cluster with the provided version and pod IP family.
+func createClusterStep(namePrefix string, version fdbv1beta2.Version, podIPFamily *int) features.Func {
+	return func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+		clusterName := envconf.RandomName(namePrefix, 32)
+		namespace := ctx.Value(keyNamespaceID).(string)
+
+		testCluster := createFoundationDBCluster(clusterName, namespace, version.String())
+		testCluster.Spec.Routing.PodIPFamily = podIPFamily
+
+		err := cfg.Client().Resources(namespace).Create(ctx, testCluster)
+		if err != nil {
+			t.Error(err)
+		}
+
+		return context.WithValue(ctx, keyClusterNameID, clusterName)
+	}
+}
+
+// waitForReconciliationStep waits until the cluster of the test was reconciled.
+func waitForReconciliationStep(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+	testCluster := getTestCluster(ctx)
+
+	err := wait.For(conditions.New(cfg.Client().Resources(testCluster.Namespace)).ResourceMatch(testCluster, func(object k8s.Object) bool {
+		cluster := object.(*fdbv1beta2.FoundationDBCluster)
+		return cluster.Status.Generations.Reconciled >= 1
+	}), wait.WithTimeout(time.Minute*3))
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	return ctx
+}
+
+// deleteClusterStep deletes the cluster of the test.
+func deleteClusterStep(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+	testCluster := getTestCluster(ctx)
+
+	err := cfg.Client().Resources(testCluster.Namespace).Delete(ctx, testCluster)
+	if err != nil {
+		t.Error(err)
+	}
+
+	return ctx
+}
+
+// getTestCluster returns a reference to the cluster of the test.
+func getTestCluster(ctx context.Context) *fdbv1beta2.FoundationDBCluster {
 	return &fdbv1beta2.FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ctx.Value(keyClusterNameID).(string),
+			Namespace: ctx.Value(keyNamespaceID).(string),
+		},
+	}
+}
+
+func createFoundationDBCluster(clusterName string, namespace string, version string) *fdbv1beta2.FoundationDBCluster {
+	cluster := &fdbv1beta2.FoundationDBCluster{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      clusterName,
 			Namespace: namespace,
@@ -239,6 +291,10 @@ func createFoundationDBCluster(clusterName string, namespace string, version str
 			},
 		},
 	}
+
+	setClusterImages(cluster)
+
+	return cluster
 }
 
 // RegisterFDBScheme registers the fdbv1beta2 scheme to the rest config
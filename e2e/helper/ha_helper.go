@@ -0,0 +1,222 @@
+/*
+ * ha_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/e2e-framework/klient/decoder"
+	"sigs.k8s.io/e2e-framework/klient/wait"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+const (
+	// multiDCConfigDirectory is the directory that contains the configuration of the multi-DC cluster.
+	multiDCConfigDirectory = "../config/tests/multi_dc"
+
+	// primaryDC is the data center that is the primary in config/tests/multi_dc.
+	primaryDC = "dc1"
+)
+
+// multiDCs are the data centers of the multi-DC cluster in config/tests/multi_dc.
+var multiDCs = []string{"dc1", "dc2", "dc3"}
+
+// MultiDCClusterTest returns an e2e test that creates the multi-DC cluster from config/tests/multi_dc in a single
+// Kubernetes cluster, fails all Pods of the primary data center and assess that the database stays available.
+func MultiDCClusterTest(version fdbv1beta2.Version, t *testing.T) features.Feature {
+	return features.
+		New("create multi-DC cluster for "+version.Compact()).
+		WithLabel("type", "ha").
+		WithLabel("profile", "multi-dc").
+		Setup(func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			err := createMultiDCCluster(ctx, cfg, version)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should stay available when the primary data center fails", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			namespace := ctx.Value(keyNamespaceID).(string)
+			primary := getMultiDCCluster(namespace, primaryDC)
+
+			podList, err := getClusterPods(ctx, cfg, primary)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, pod := range podList.Items {
+				err = cfg.Client().Resources(namespace).Delete(ctx, &pod)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Writing a key through another data center can only succeed once the database is available again.
+			secondary := getMultiDCCluster(namespace, "dc2")
+			err = wait.For(func() (bool, error) {
+				_, err := runFdbCli(ctx, cfg, secondary, "writemode on; set e2e-ha-key e2e-ha-value")
+				if err != nil {
+					t.Logf("database is not available yet: %s", err.Error())
+					return false, nil
+				}
+
+				return true, nil
+			}, wait.WithTimeout(5*time.Minute), wait.WithInterval(10*time.Second))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should reconcile the primary data center", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			namespace := ctx.Value(keyNamespaceID).(string)
+
+			err := waitForClusterReconciled(ctx, cfg, getMultiDCCluster(namespace, primaryDC), 15*time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Teardown(func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			namespace := ctx.Value(keyNamespaceID).(string)
+
+			for _, dc := range multiDCs {
+				err := cfg.Client().Resources(namespace).Delete(ctx, getMultiDCCluster(namespace, dc))
+				if err != nil {
+					t.Error(err)
+				}
+			}
+
+			return ctx
+		}).Feature()
+}
+
+// createMultiDCCluster creates the multi-DC cluster in the same stages as config/tests/multi_dc/create.bash. The
+// primary data center is created first with a single region, afterwards all data centers are created with the final
+// configuration and the connection string of the primary.
+func createMultiDCCluster(ctx context.Context, cfg *envconf.Config, version fdbv1beta2.Version) error {
+	namespace := ctx.Value(keyNamespaceID).(string)
+
+	initialCluster, err := getMultiDCClusterFromFile("stage_1.yaml", namespace, primaryDC, "", version)
+	if err != nil {
+		return err
+	}
+
+	err = cfg.Client().Resources(namespace).Create(ctx, initialCluster)
+	if err != nil {
+		return err
+	}
+
+	err = waitForClusterReconciled(ctx, cfg, initialCluster, 10*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	connectionString := initialCluster.Status.ConnectionString
+	for _, dc := range multiDCs {
+		cluster, err := getMultiDCClusterFromFile("final.yaml", namespace, dc, connectionString, version)
+		if err != nil {
+			return err
+		}
+
+		if dc != primaryDC {
+			err = cfg.Client().Resources(namespace).Create(ctx, cluster)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		err = updateCluster(ctx, cfg, getMultiDCCluster(namespace, dc), func(current *fdbv1beta2.FoundationDBCluster) {
+			current.Spec = cluster.Spec
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, dc := range multiDCs {
+		err = waitForClusterReconciled(ctx, cfg, getMultiDCCluster(namespace, dc), 20*time.Minute)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getMultiDCClusterFromFile reads the cluster of the data center from a file in config/tests/multi_dc. The variables
+// in the file are replaced like the applyFile function in config/tests/multi_dc/functions.bash does.
+func getMultiDCClusterFromFile(fileName string, namespace string, dc string, connectionString string, version fdbv1beta2.Version) (*fdbv1beta2.FoundationDBCluster, error) {
+	content, err := os.ReadFile(path.Join(multiDCConfigDirectory, fileName))
+	if err != nil {
+		return nil, err
+	}
+
+	logCount := "-1"
+	if dc == "dc3" {
+		logCount = "0"
+	}
+
+	if connectionString == "" {
+		connectionString = `""`
+	}
+
+	variables := map[string]string{
+		"dc":               dc,
+		"logCount":         logCount,
+		"connectionString": connectionString,
+	}
+
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err = decoder.DecodeString(os.Expand(string(content), func(key string) string {
+		return variables[key]
+	}), cluster, decoder.MutateNamespace(namespace))
+	if err != nil {
+		return nil, err
+	}
+
+	cluster.Spec.Version = version.String()
+	setClusterImages(cluster)
+
+	return cluster, nil
+}
+
+// getMultiDCCluster returns a reference to the cluster of the data center.
+func getMultiDCCluster(namespace string, dc string) *fdbv1beta2.FoundationDBCluster {
+	return &fdbv1beta2.FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("test-cluster-%s", dc),
+			Namespace: namespace,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+/*
+ * images.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"os"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// registryEnvironmentVariable defines the environment variable that contains the registry that all images are
+	// pulled from, e.g. the local registry of a kind cluster.
+	registryEnvironmentVariable = "E2E_IMAGE_REGISTRY"
+
+	// foundationDBImage is the image of the foundationdb container.
+	foundationDBImage = "foundationdb/foundationdb"
+
+	// sidecarImage is the image of the foundationdb-kubernetes-sidecar container.
+	sidecarImage = "foundationdb/foundationdb-kubernetes-sidecar"
+
+	// operatorImage is the image of the operator.
+	operatorImage = "foundationdb/fdb-kubernetes-operator:latest"
+)
+
+// getImage returns the image from the registry defined in the E2E_IMAGE_REGISTRY environment variable. If no registry
+// is defined the image is returned unchanged.
+func getImage(image string) string {
+	registry := strings.TrimSuffix(os.Getenv(registryEnvironmentVariable), "/")
+	if registry == "" {
+		return image
+	}
+
+	return registry + "/" + image
+}
+
+// getImageConfigs returns the image configs that pull the provided image from the registry of the tests.
+func getImageConfigs(image string) []fdbv1beta2.ImageConfig {
+	return []fdbv1beta2.ImageConfig{
+		{
+			BaseImage: getImage(image),
+		},
+	}
+}
+
+// setClusterImages updates the cluster to pull all images from the registry of the tests.
+func setClusterImages(cluster *fdbv1beta2.FoundationDBCluster) {
+	cluster.Spec.MainContainer.ImageConfigs = getImageConfigs(foundationDBImage)
+	cluster.Spec.SidecarContainer.ImageConfigs = getImageConfigs(sidecarImage)
+}
+
+// setContainerImages updates all containers of the Pod spec to pull their images from the registry of the tests.
+func setContainerImages(podSpec *corev1.PodSpec) {
+	for idx, container := range podSpec.InitContainers {
+		podSpec.InitContainers[idx].Image = getImage(container.Image)
+	}
+
+	for idx, container := range podSpec.Containers {
+		podSpec.Containers[idx].Image = getImage(container.Image)
+	}
+}
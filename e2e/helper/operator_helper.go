@@ -27,6 +27,7 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/e2e-framework/klient/decoder"
 	"sigs.k8s.io/e2e-framework/klient/k8s"
@@ -45,11 +46,15 @@ func InstallOperator(ctx context.Context, cfg *envconf.Config, _ *testing.T) (co
 
 	ns := ctx.Value(keyNamespaceID).(string)
 
-	// TODO (johscheuer): Make the image a flag that can be passed
+	err = createTestSecrets(ctx, r, ns)
+	if err != nil {
+		return ctx, err
+	}
+
 	err = decoder.DecodeEachFile(ctx, os.DirFS("../config/samples"), "deployment.yaml",
 		decoder.CreateHandler(r),
 		decoder.MutateNamespace(ns),
-		mutateOperatorImage("manager", "foundationdb/fdb-kubernetes-operator:latest"),
+		mutateOperatorDeployment("manager", getImage(operatorImage)),
 	)
 	if err != nil {
 		return ctx, err
@@ -70,22 +75,90 @@ func InstallOperator(ctx context.Context, cfg *envconf.Config, _ *testing.T) (co
 	return ctx, err
 }
 
-func mutateOperatorImage(name string, image string) decoder.DecodeOption {
+// createTestSecrets creates the secret with the test certificates from config/test-certs and the secret with the
+// credentials for the MinIO instance from config/minio.
+func createTestSecrets(ctx context.Context, r *resources.Resources, namespace string) error {
+	certificate, err := os.ReadFile("../config/test-certs/cert.pem")
+	if err != nil {
+		return err
+	}
+
+	key, err := os.ReadFile("../config/test-certs/key.pem")
+	if err != nil {
+		return err
+	}
+
+	err = r.Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testCertificatesSecret,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"app": "fdb-kubernetes-operator",
+			},
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certificate,
+			corev1.TLSPrivateKeyKey: key,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.DecodeEachFile(ctx, os.DirFS("../config/minio"), "credentials.yaml",
+		decoder.CreateHandler(r),
+		decoder.MutateNamespace(namespace),
+	)
+}
+
+// mutateOperatorDeployment updates the image of the operator, pulls all images from the registry of the tests and
+// configures the operator to use the test certificates and the MinIO credentials, like the patches in
+// config/development.
+func mutateOperatorDeployment(name string, image string) decoder.DecodeOption {
 	return decoder.MutateOption(func(obj k8s.Object) error {
 		deploy, ok := obj.(*appsv1.Deployment)
 		if !ok {
 			return nil
 		}
 
+		setContainerImages(&deploy.Spec.Template.Spec)
+
 		for idx, container := range deploy.Spec.Template.Spec.Containers {
 			if container.Name != name {
 				continue
 			}
 
 			container.Image = image
+			container.Env = append(container.Env,
+				corev1.EnvVar{Name: "DISABLE_SIDECAR_TLS_CHECK", Value: "1"},
+				corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/tmp/fdb-certs/tls.crt"},
+				corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/tmp/fdb-certs/tls.crt"},
+				corev1.EnvVar{Name: "FDB_TLS_KEY_FILE", Value: "/tmp/fdb-certs/tls.key"},
+				corev1.EnvVar{Name: "FDB_BLOB_CREDENTIALS", Value: "/tmp/fdb-backup-credentials/credentials"},
+			)
+			container.VolumeMounts = append(container.VolumeMounts,
+				corev1.VolumeMount{Name: "fdb-certs", MountPath: "/tmp/fdb-certs", ReadOnly: true},
+				corev1.VolumeMount{Name: "fdb-backup-credentials", MountPath: "/tmp/fdb-backup-credentials", ReadOnly: true},
+			)
 			deploy.Spec.Template.Spec.Containers[idx] = container
 		}
 
+		deploy.Spec.Template.Spec.Volumes = append(deploy.Spec.Template.Spec.Volumes,
+			corev1.Volume{
+				Name: "fdb-certs",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: testCertificatesSecret},
+				},
+			},
+			corev1.Volume{
+				Name: "fdb-backup-credentials",
+				VolumeSource: corev1.VolumeSource{
+					Secret: &corev1.SecretVolumeSource{SecretName: minioCredentialsSecret},
+				},
+			},
+		)
+
 		return nil
 	})
 }
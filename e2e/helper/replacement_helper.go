@@ -0,0 +1,91 @@
+/*
+ * replacement_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+// ReplacePodsUnderLoadTest returns an e2e test that replaces storage process groups while the data loader from
+// sample-apps/data-loader writes data into the cluster, and assess that the replacements and the data loader complete.
+func ReplacePodsUnderLoadTest(version fdbv1beta2.Version, replacements int, t *testing.T) features.Feature {
+	return features.
+		New("replace pods under load for "+version.Compact()).
+		WithLabel("type", "replacement").
+		Setup(createClusterStep("fdb-replace", version, nil)).
+		Setup(waitForReconciliationStep).
+		Setup(startDataLoaderStep(20000)).
+		Assess("it should replace the storage process groups", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			var replaced []fdbv1beta2.ProcessGroupID
+			err := updateCluster(ctx, cfg, getTestCluster(ctx), func(cluster *fdbv1beta2.FoundationDBCluster) {
+				replaced = make([]fdbv1beta2.ProcessGroupID, 0, replacements)
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					if len(replaced) >= replacements {
+						break
+					}
+
+					if processGroup.ProcessClass != fdbv1beta2.ProcessClassStorage || processGroup.IsMarkedForRemoval() {
+						continue
+					}
+
+					replaced = append(replaced, processGroup.ProcessGroupID)
+				}
+
+				cluster.Spec.ProcessGroupsToRemove = append(cluster.Spec.ProcessGroupsToRemove, replaced...)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return context.WithValue(ctx, keyReplacedProcessGroupsID, replaced)
+		}).
+		Assess("it should reconcile the cluster", waitForUpdateStep(15*time.Minute)).
+		Assess("it should remove the replaced process groups", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			testCluster := getTestCluster(ctx)
+			replaced := ctx.Value(keyReplacedProcessGroupsID).([]fdbv1beta2.ProcessGroupID)
+
+			err := cfg.Client().Resources(testCluster.Namespace).Get(ctx, testCluster.Name, testCluster.Namespace, testCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			replacedMap := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(replaced))
+			for _, processGroupID := range replaced {
+				replacedMap[processGroupID] = fdbv1beta2.None{}
+			}
+
+			for _, processGroup := range testCluster.Status.ProcessGroups {
+				if _, ok := replacedMap[processGroup.ProcessGroupID]; ok {
+					t.Errorf("expected process group %s to be removed", processGroup.ProcessGroupID)
+				}
+			}
+
+			return ctx
+		}).
+		Assess("it should load all data", waitForDataLoaderStep(15*time.Minute)).
+		Teardown(deleteClusterStep).Feature()
+}
@@ -0,0 +1,240 @@
+/*
+ * scenario_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/e2e-framework/klient/decoder"
+	"sigs.k8s.io/e2e-framework/klient/k8s"
+	"sigs.k8s.io/e2e-framework/klient/k8s/resources"
+	"sigs.k8s.io/e2e-framework/klient/wait"
+	"sigs.k8s.io/e2e-framework/klient/wait/conditions"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+const (
+	// testCertificatesSecret is the name of the secret that contains the certificates from config/test-certs.
+	testCertificatesSecret = "fdb-kubernetes-operator-secrets"
+
+	// minioCredentialsSecret is the name of the secret that contains the credentials for MinIO from config/minio.
+	minioCredentialsSecret = "minio-credentials"
+
+	// dataLoaderJobName is the name of the data loader job from sample-apps/data-loader.
+	dataLoaderJobName = "fdb-data-loader"
+)
+
+// updateClusterStep returns a step that updates the spec of the cluster of the test. The update is retried if the
+// cluster was changed in the meantime.
+func updateClusterStep(update func(cluster *fdbv1beta2.FoundationDBCluster)) features.Func {
+	return func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+		err := updateCluster(ctx, cfg, getTestCluster(ctx), update)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return ctx
+	}
+}
+
+// updateCluster fetches the latest version of the cluster and updates its spec. The update is retried if the cluster
+// was changed in the meantime.
+func updateCluster(ctx context.Context, cfg *envconf.Config, cluster *fdbv1beta2.FoundationDBCluster, update func(cluster *fdbv1beta2.FoundationDBCluster)) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		err := cfg.Client().Resources(cluster.Namespace).Get(ctx, cluster.Name, cluster.Namespace, cluster)
+		if err != nil {
+			return err
+		}
+
+		update(cluster)
+
+		return cfg.Client().Resources(cluster.Namespace).Update(ctx, cluster)
+	})
+}
+
+// waitForUpdateStep returns a step that waits until the latest generation of the cluster of the test was reconciled.
+func waitForUpdateStep(timeout time.Duration) features.Func {
+	return func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+		err := waitForClusterReconciled(ctx, cfg, getTestCluster(ctx), timeout)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return ctx
+	}
+}
+
+// waitForClusterReconciled waits until the latest generation of the cluster was reconciled.
+func waitForClusterReconciled(ctx context.Context, cfg *envconf.Config, cluster *fdbv1beta2.FoundationDBCluster, timeout time.Duration) error {
+	return wait.For(conditions.New(cfg.Client().Resources(cluster.Namespace)).ResourceMatch(cluster, func(object k8s.Object) bool {
+		current := object.(*fdbv1beta2.FoundationDBCluster)
+		return current.Status.Generations.Reconciled > 0 && current.Status.Generations.Reconciled == current.ObjectMeta.Generation
+	}), wait.WithTimeout(timeout), wait.WithInterval(5*time.Second))
+}
+
+// getClusterPods returns the Pods of the cluster.
+func getClusterPods(ctx context.Context, cfg *envconf.Config, cluster *fdbv1beta2.FoundationDBCluster) (*corev1.PodList, error) {
+	err := cfg.Client().Resources(cluster.Namespace).Get(ctx, cluster.Name, cluster.Namespace, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	podList := &corev1.PodList{}
+	err = cfg.Client().Resources(cluster.Namespace).List(ctx, podList, resources.WithLabelSelector(labels.SelectorFromSet(cluster.GetMatchLabels()).String()))
+	if err != nil {
+		return nil, err
+	}
+
+	return podList, nil
+}
+
+// runFdbCli runs the fdbcli command in the main container of a running Pod of the cluster and returns the output.
+func runFdbCli(ctx context.Context, cfg *envconf.Config, cluster *fdbv1beta2.FoundationDBCluster, command string) (string, error) {
+	podList, err := getClusterPods(ctx, cfg, cluster)
+	if err != nil {
+		return "", err
+	}
+
+	executor, err := internal.NewPodCommandExecutor(cfg.Client().RESTConfig())
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range podList.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		stdout, stderr, err := executor.ExecuteCommand(ctx, &pod, fdbv1beta2.MainContainerName, []string{
+			"fdbcli", "-C", "/var/dynamic-conf/fdb.cluster", "--timeout", "30", "--exec", command,
+		})
+		if err != nil {
+			return stdout, fmt.Errorf("%w, stderr: %s", err, stderr)
+		}
+
+		return stdout, nil
+	}
+
+	return "", fmt.Errorf("no running Pod found for cluster %s/%s", cluster.Namespace, cluster.Name)
+}
+
+// startDataLoaderStep returns a step that starts the data loader from sample-apps/data-loader against the cluster of
+// the test. The data loader loads the provided number of keys.
+func startDataLoaderStep(keys int) features.Func {
+	return func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+		testCluster := getTestCluster(ctx)
+		err := cfg.Client().Resources(testCluster.Namespace).Get(ctx, testCluster.Name, testCluster.Namespace, testCluster)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := resources.New(cfg.Client().RESTConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = decoder.DecodeEachFile(ctx, os.DirFS("../sample-apps/data-loader"), "job.yaml",
+			decoder.CreateHandler(r),
+			decoder.MutateNamespace(testCluster.Namespace),
+			mutateDataLoaderJob(testCluster, keys),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return ctx
+	}
+}
+
+// mutateDataLoaderJob updates the data loader job to load data into the cluster with the client library of the
+// version that the cluster is running.
+func mutateDataLoaderJob(cluster *fdbv1beta2.FoundationDBCluster, keys int) decoder.DecodeOption {
+	return decoder.MutateOption(func(obj k8s.Object) error {
+		job, ok := obj.(*batchv1.Job)
+		if !ok {
+			return nil
+		}
+
+		version, err := fdbv1beta2.ParseFdbVersion(cluster.GetRunningVersion())
+		if err != nil {
+			return err
+		}
+
+		podSpec := &job.Spec.Template.Spec
+		for idx, container := range podSpec.InitContainers {
+			container.Image = fmt.Sprintf("%s:%s-1", sidecarImage, version.String())
+			container.Args = []string{
+				"--copy-file", "fdb.cluster",
+				"--copy-library", version.GetBinaryVersion(),
+				"--init-mode",
+				"--require-not-empty", "fdb.cluster",
+			}
+			podSpec.InitContainers[idx] = container
+		}
+
+		for idx, container := range podSpec.Containers {
+			container.Args = []string{"--keys", strconv.Itoa(keys)}
+			podSpec.Containers[idx] = container
+		}
+
+		for idx, volume := range podSpec.Volumes {
+			if volume.ConfigMap == nil {
+				continue
+			}
+
+			volume.ConfigMap.Name = fmt.Sprintf("%s-config", cluster.Name)
+			podSpec.Volumes[idx] = volume
+		}
+
+		setContainerImages(podSpec)
+
+		return nil
+	})
+}
+
+// waitForDataLoaderStep returns a step that waits until the data loader has loaded all keys.
+func waitForDataLoaderStep(timeout time.Duration) features.Func {
+	return func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+		namespace := ctx.Value(keyNamespaceID).(string)
+		job := &batchv1.Job{}
+		job.Name = dataLoaderJobName
+		job.Namespace = namespace
+
+		err := wait.For(conditions.New(cfg.Client().Resources(namespace)).JobCompleted(job), wait.WithTimeout(timeout), wait.WithInterval(5*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return ctx
+	}
+}
@@ -0,0 +1,111 @@
+/*
+ * tls_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+// TLSMigrationTest returns an e2e test that creates a cluster without TLS, migrates the cluster to TLS with the
+// certificates from config/test-certs and assess that all processes and coordinators use TLS afterwards.
+func TLSMigrationTest(version fdbv1beta2.Version, t *testing.T) features.Feature {
+	return features.
+		New("migrate cluster to TLS for "+version.Compact()).
+		WithLabel("type", "tls-migration").
+		Setup(createClusterStep("fdb-tls", version, nil)).
+		Setup(waitForReconciliationStep).
+		Assess("it should enable TLS", updateClusterStep(enableTLS)).
+		Assess("it should reconcile the cluster", waitForUpdateStep(15*time.Minute)).
+		Assess("it should use TLS for all processes", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			testCluster := getTestCluster(ctx)
+
+			err := cfg.Client().Resources(testCluster.Namespace).Get(ctx, testCluster.Name, testCluster.Namespace, testCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !testCluster.Status.RequiredAddresses.TLS || testCluster.Status.RequiredAddresses.NonTLS {
+				t.Errorf("expected only TLS addresses to be required, got: %+v", testCluster.Status.RequiredAddresses)
+			}
+
+			connectionString, err := fdbv1beta2.ParseConnectionString(testCluster.Status.ConnectionString)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, coordinator := range connectionString.Coordinators {
+				address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+				if err != nil {
+					t.Error(err)
+					continue
+				}
+
+				if !address.Flags["tls"] {
+					t.Errorf("expected coordinator to use TLS, got: %s", coordinator)
+				}
+			}
+
+			return ctx
+		}).
+		Teardown(deleteClusterStep).Feature()
+}
+
+// enableTLS mounts the test certificates into the containers of the cluster and enables TLS, like the patch in
+// config/tests/tls.
+func enableTLS(cluster *fdbv1beta2.FoundationDBCluster) {
+	processSettings := cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral]
+	podSpec := &processSettings.PodTemplate.Spec
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: "fdb-certs",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{SecretName: testCertificatesSecret},
+		},
+	})
+
+	for idx, container := range podSpec.Containers {
+		if container.Name != fdbv1beta2.MainContainerName && container.Name != fdbv1beta2.SidecarContainerName {
+			continue
+		}
+
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "FDB_TLS_CERTIFICATE_FILE", Value: "/tmp/fdb-certs/tls.crt"},
+			corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/tmp/fdb-certs/tls.crt"},
+			corev1.EnvVar{Name: "FDB_TLS_KEY_FILE", Value: "/tmp/fdb-certs/tls.key"},
+		)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      "fdb-certs",
+			MountPath: "/tmp/fdb-certs",
+		})
+		podSpec.Containers[idx] = container
+	}
+
+	cluster.Spec.Processes[fdbv1beta2.ProcessClassGeneral] = processSettings
+	cluster.Spec.MainContainer.EnableTLS = true
+	cluster.Spec.SidecarContainer.EnableTLS = true
+}
@@ -0,0 +1,102 @@
+/*
+ * upgrade_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package helper
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"sigs.k8s.io/e2e-framework/pkg/envconf"
+	"sigs.k8s.io/e2e-framework/pkg/features"
+)
+
+const (
+	// upgradeTestKey is the key that is written before the upgrade and read after the upgrade.
+	upgradeTestKey = "e2e-upgrade-key"
+
+	// upgradeTestValue is the value of the upgradeTestKey.
+	upgradeTestValue = "e2e-upgrade-value"
+)
+
+// GetUpgradeTestVersions returns the pairs of versions that are used for the upgrade tests. Every version from
+// GetTestFDBVersions is upgraded to the next version in the list.
+func GetUpgradeTestVersions() [][2]fdbv1beta2.Version {
+	testVersions := GetTestFDBVersions()
+	upgradeVersions := make([][2]fdbv1beta2.Version, 0, len(testVersions))
+
+	for idx := 1; idx < len(testVersions); idx++ {
+		upgradeVersions = append(upgradeVersions, [2]fdbv1beta2.Version{testVersions[idx-1], testVersions[idx]})
+	}
+
+	return upgradeVersions
+}
+
+// UpgradeClusterTest returns an e2e test that creates a cluster in the initial version, upgrades the cluster to the
+// target version and assess that the data written before the upgrade is still readable.
+func UpgradeClusterTest(initialVersion fdbv1beta2.Version, targetVersion fdbv1beta2.Version, t *testing.T) features.Feature {
+	return features.
+		New("upgrade cluster from "+initialVersion.Compact()+" to "+targetVersion.Compact()).
+		WithLabel("type", "upgrade").
+		Setup(createClusterStep("fdb-upgrade", initialVersion, nil)).
+		Setup(waitForReconciliationStep).
+		Setup(func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			_, err := runFdbCli(ctx, cfg, getTestCluster(ctx), "writemode on; set "+upgradeTestKey+" "+upgradeTestValue)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return ctx
+		}).
+		Assess("it should upgrade the cluster to "+targetVersion.Compact(), updateClusterStep(func(cluster *fdbv1beta2.FoundationDBCluster) {
+			cluster.Spec.Version = targetVersion.String()
+		})).
+		Assess("it should reconcile the upgraded cluster", waitForUpdateStep(15*time.Minute)).
+		Assess("it should run the target version", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			testCluster := getTestCluster(ctx)
+
+			err := cfg.Client().Resources(testCluster.Namespace).Get(ctx, testCluster.Name, testCluster.Namespace, testCluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if testCluster.Status.RunningVersion != targetVersion.String() {
+				t.Errorf("expected the cluster to run version %s, got: %s", targetVersion.String(), testCluster.Status.RunningVersion)
+			}
+
+			return ctx
+		}).
+		Assess("it should keep the data written before the upgrade", func(ctx context.Context, t *testing.T, cfg *envconf.Config) context.Context {
+			output, err := runFdbCli(ctx, cfg, getTestCluster(ctx), "get "+upgradeTestKey)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(output, upgradeTestValue) {
+				t.Errorf("expected the value %s for key %s, got: %s", upgradeTestValue, upgradeTestKey, output)
+			}
+
+			return ctx
+		}).
+		Teardown(deleteClusterStep).Feature()
+}
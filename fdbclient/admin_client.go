@@ -39,6 +39,7 @@ import (
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
 	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -46,6 +47,7 @@ const (
 	fdbcliStr     = "fdbcli"
 	fdbbackupStr  = "fdbbackup"
 	fdbrestoreStr = "fdbrestore"
+	fdbdrStr      = "fdbdr"
 )
 
 var adminClientMutex sync.Mutex
@@ -66,6 +68,10 @@ func parseMaxCommandOutput() int {
 
 var protocolVersionRegex = regexp.MustCompile(`(?m)^protocol (\w+)$`)
 
+var drRunningRegex = regexp.MustCompile("The DR on tag `.*' is (NOT )?a complete copy of the primary database")
+
+var drSecondsBehindRegex = regexp.MustCompile(`The DR is ([0-9.]+) seconds behind`)
+
 // cliAdminClient provides an implementation of the admin interface using the
 // FDB CLI.
 type cliAdminClient struct {
@@ -143,6 +149,10 @@ func (command cliCommand) getClusterFileFlag() string {
 		return "--dest_cluster_file"
 	}
 
+	if command.binary == fdbdrStr {
+		return "-d"
+	}
+
 	return "-C"
 }
 
@@ -849,6 +859,322 @@ func (client *cliAdminClient) GetRestoreStatus() (string, error) {
 	})
 }
 
+// runDisasterRecoveryCommand runs a fdbdr command with this cluster as
+// destination and the provided cluster as source.
+func (client *cliAdminClient) runDisasterRecoveryCommand(sourceConnectionString string, timeout time.Duration, args ...string) (string, error) {
+	sourceClusterFile, err := ensureClusterFileIsPresent(os.TempDir(), fmt.Sprintf("%s-dr-source", client.Cluster.UID), sourceConnectionString)
+	if err != nil {
+		return "", err
+	}
+
+	return client.runCommand(cliCommand{
+		binary:  fdbdrStr,
+		args:    append(args, "-s", sourceClusterFile),
+		timeout: timeout,
+	})
+}
+
+// StartDisasterRecovery starts replicating the source cluster into this
+// cluster.
+func (client *cliAdminClient) StartDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.runDisasterRecoveryCommand(sourceConnectionString, 0, "start")
+	return err
+}
+
+// AbortDisasterRecovery aborts replicating the source cluster into this
+// cluster.
+func (client *cliAdminClient) AbortDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.runDisasterRecoveryCommand(sourceConnectionString, 0, "abort")
+	return err
+}
+
+// PauseDisasterRecovery pauses the DR agents.
+func (client *cliAdminClient) PauseDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.runDisasterRecoveryCommand(sourceConnectionString, 0, "pause")
+	return err
+}
+
+// ResumeDisasterRecovery resumes the DR agents.
+func (client *cliAdminClient) ResumeDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.runDisasterRecoveryCommand(sourceConnectionString, 0, "resume")
+	return err
+}
+
+// SwitchoverDisasterRecovery makes this cluster the primary and starts
+// replicating it into the source cluster.
+func (client *cliAdminClient) SwitchoverDisasterRecovery(sourceConnectionString string) error {
+	// The switchover waits until the destination has caught up with the
+	// source, so we allow more time than for the other commands.
+	_, err := client.runDisasterRecoveryCommand(sourceConnectionString, 5*time.Minute, "switch")
+	return err
+}
+
+// GetDisasterRecoveryStatus gets the status of replicating the source cluster
+// into this cluster.
+func (client *cliAdminClient) GetDisasterRecoveryStatus(sourceConnectionString string) (*fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus, error) {
+	output, err := client.runDisasterRecoveryCommand(sourceConnectionString, 0, "status")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseDisasterRecoveryStatus(output)
+}
+
+// parseDisasterRecoveryStatus parses the output of `fdbdr status`.
+func parseDisasterRecoveryStatus(output string) (*fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus, error) {
+	status := &fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{
+		Running: drRunningRegex.MatchString(output),
+		Paused:  strings.Contains(output, "All DR agents have been paused"),
+	}
+
+	secondsBehindMatch := drSecondsBehindRegex.FindStringSubmatch(output)
+	if secondsBehindMatch != nil {
+		secondsBehind, err := strconv.ParseFloat(secondsBehindMatch[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DR lag from status output:\n%s", output)
+		}
+		status.SecondsBehind = secondsBehind
+	}
+
+	return status, nil
+}
+
+const (
+	// tenantMapPrefix is the prefix of the special keys that hold the tenant
+	// map for API version 720 and newer.
+	tenantMapPrefix = "\xff\xff/management/tenant/map/"
+
+	// legacyTenantMapPrefix is the prefix of the special keys that hold the
+	// tenant map for API version 710.
+	legacyTenantMapPrefix = "\xff\xff/management/tenant_map/"
+
+	// tenantConfigurePrefix is the prefix of the special keys that configure
+	// a tenant for API version 720 and newer.
+	tenantConfigurePrefix = "\xff\xff/management/tenant/configure/"
+)
+
+// tenantMapEntry describes the value of a tenant in the tenant map.
+type tenantMapEntry struct {
+	ID          int64  `json:"id"`
+	State       string `json:"tenant_state,omitempty"`
+	TenantGroup *struct {
+		Printable string `json:"printable"`
+	} `json:"tenant_group,omitempty"`
+}
+
+// getTenantMapKey returns the special key of the tenant in the tenant map for
+// the API version of the operator.
+func getTenantMapKey(apiVersion int, name string) (fdb.Key, error) {
+	if apiVersion >= 720 {
+		return fdb.Key(tenantMapPrefix + name), nil
+	}
+
+	if apiVersion >= 710 {
+		return fdb.Key(legacyTenantMapPrefix + name), nil
+	}
+
+	return nil, fmt.Errorf("managing tenants requires at least FDB API version 710, the operator uses API version %d", apiVersion)
+}
+
+// getTenantGroupKey returns the special key that configures the tenant group
+// of the tenant.
+func getTenantGroupKey(apiVersion int, name string) (fdb.Key, error) {
+	if apiVersion < 720 {
+		return nil, fmt.Errorf("managing tenant groups requires at least FDB API version 720, the operator uses API version %d", apiVersion)
+	}
+
+	return fdb.Key(tenantConfigurePrefix + name + "/tenant_group"), nil
+}
+
+// parseTenantMapEntry parses the value of a tenant in the tenant map.
+func parseTenantMapEntry(value []byte) (*fdbv1beta2.FoundationDBLiveTenantStatus, error) {
+	entry := &tenantMapEntry{}
+	err := json.Unmarshal(value, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	liveStatus := &fdbv1beta2.FoundationDBLiveTenantStatus{
+		ID:    entry.ID,
+		State: entry.State,
+	}
+
+	if entry.TenantGroup != nil {
+		liveStatus.TenantGroup = entry.TenantGroup.Printable
+	}
+
+	return liveStatus, nil
+}
+
+// GetTenant gets the live status of a tenant. This will return nil if the
+// tenant doesn't exist.
+func (client *cliAdminClient) GetTenant(name string) (*fdbv1beta2.FoundationDBLiveTenantStatus, error) {
+	key, err := getTenantMapKey(fdb.MustGetAPIVersion(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	database, err := getFDBDatabase(client.Cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := database.ReadTransact(func(transaction fdb.ReadTransaction) (interface{}, error) {
+		return transaction.Get(key).Get()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rawValue, ok := value.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("could not cast result into byte slice")
+	}
+
+	if len(rawValue) == 0 {
+		return nil, nil
+	}
+
+	return parseTenantMapEntry(rawValue)
+}
+
+// CreateTenant creates a new tenant in the provided tenant group.
+func (client *cliAdminClient) CreateTenant(name string, tenantGroup string) error {
+	apiVersion := fdb.MustGetAPIVersion()
+	key, err := getTenantMapKey(apiVersion, name)
+	if err != nil {
+		return err
+	}
+
+	var groupKey fdb.Key
+	if tenantGroup != "" {
+		groupKey, err = getTenantGroupKey(apiVersion, name)
+		if err != nil {
+			return err
+		}
+	}
+
+	database, err := getFDBDatabase(client.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetSpecialKeySpaceEnableWrites()
+		if err != nil {
+			return nil, err
+		}
+
+		transaction.Set(key, nil)
+		if groupKey != nil {
+			transaction.Set(groupKey, []byte(tenantGroup))
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// ConfigureTenant assigns a tenant to the provided tenant group. An empty
+// tenant group removes the tenant from its current group.
+func (client *cliAdminClient) ConfigureTenant(name string, tenantGroup string) error {
+	groupKey, err := getTenantGroupKey(fdb.MustGetAPIVersion(), name)
+	if err != nil {
+		return err
+	}
+
+	database, err := getFDBDatabase(client.Cluster)
+	if err != nil {
+		return err
+	}
+
+	_, err = database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetSpecialKeySpaceEnableWrites()
+		if err != nil {
+			return nil, err
+		}
+
+		if tenantGroup == "" {
+			transaction.Clear(groupKey)
+		} else {
+			transaction.Set(groupKey, []byte(tenantGroup))
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// IsTenantEmpty checks whether a tenant holds any data.
+func (client *cliAdminClient) IsTenantEmpty(name string) (bool, error) {
+	database, err := getFDBDatabase(client.Cluster)
+	if err != nil {
+		return false, err
+	}
+
+	tenant, err := database.OpenTenant(fdb.Key(name))
+	if err != nil {
+		return false, err
+	}
+
+	isEmpty, err := tenant.ReadTransact(func(transaction fdb.ReadTransaction) (interface{}, error) {
+		keyValues, err := transaction.GetRange(fdb.KeyRange{Begin: fdb.Key(""), End: fdb.Key("\xff")}, fdb.RangeOptions{Limit: 1}).GetSliceWithError()
+		if err != nil {
+			return false, err
+		}
+
+		return len(keyValues) == 0, nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return isEmpty.(bool), nil
+}
+
+// DeleteTenant deletes a tenant. If clearData is true, the data of the tenant
+// will be cleared before the tenant is deleted.
+func (client *cliAdminClient) DeleteTenant(name string, clearData bool) error {
+	key, err := getTenantMapKey(fdb.MustGetAPIVersion(), name)
+	if err != nil {
+		return err
+	}
+
+	database, err := getFDBDatabase(client.Cluster)
+	if err != nil {
+		return err
+	}
+
+	if clearData {
+		tenant, err := database.OpenTenant(fdb.Key(name))
+		if err != nil {
+			return err
+		}
+
+		_, err = tenant.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+			transaction.ClearRange(fdb.KeyRange{Begin: fdb.Key(""), End: fdb.Key("\xff")})
+			return nil, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetSpecialKeySpaceEnableWrites()
+		if err != nil {
+			return nil, err
+		}
+
+		transaction.Clear(key)
+		return nil, nil
+	})
+
+	return err
+}
+
 // Close cleans up any pending resources.
 func (client *cliAdminClient) Close() error {
 	// Allow to reuse the same file.
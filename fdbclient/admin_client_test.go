@@ -23,6 +23,7 @@ package fdbclient
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
 	"os"
 	"path"
@@ -31,6 +32,7 @@ import (
 	"github.com/go-logr/logr"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -205,6 +207,117 @@ var _ = Describe("admin_client_test", func() {
 		)
 	})
 
+	When("parsing the DR status", func() {
+		DescribeTable("it should return the correct status",
+			func(input string, expected fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus) {
+				status, err := parseDisasterRecoveryStatus(input)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*status).To(Equal(expected))
+			},
+			Entry("without a DR",
+				"No previous backups found.\n",
+				fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{},
+			),
+			Entry("with a DR that is not a complete copy",
+				"The DR on tag `default' is NOT a complete copy of the primary database.\n",
+				fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{
+					Running: true,
+				},
+			),
+			Entry("with a running DR",
+				"The DR on tag `default' is a complete copy of the primary database.\n\nThe DR is 1.250000 seconds behind.\n",
+				fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{
+					Running:       true,
+					SecondsBehind: 1.25,
+				},
+			),
+			Entry("with a paused DR",
+				"The DR on tag `default' is a complete copy of the primary database.\n\nThe DR is 0.500000 seconds behind.\n\nAll DR agents have been paused.\n",
+				fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{
+					Running:       true,
+					Paused:        true,
+					SecondsBehind: 0.5,
+				},
+			),
+			Entry("with an aborted DR",
+				"The previous DR on tag `default' has been aborted.\n",
+				fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{},
+			),
+		)
+	})
+
+	When("getting the tenant map key", func() {
+		DescribeTable("it should return the key for the API version",
+			func(apiVersion int, expected fdb.Key) {
+				key, err := getTenantMapKey(apiVersion, "sample")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(key).To(Equal(expected))
+			},
+			Entry("with API version 710",
+				710,
+				fdb.Key("\xff\xff/management/tenant_map/sample"),
+			),
+			Entry("with API version 720",
+				720,
+				fdb.Key("\xff\xff/management/tenant/map/sample"),
+			),
+		)
+
+		It("should return an error for an API version without tenant support", func() {
+			_, err := getTenantMapKey(630, "sample")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("getting the tenant group key", func() {
+		It("should return the configure key with API version 720", func() {
+			key, err := getTenantGroupKey(720, "sample")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(key).To(Equal(fdb.Key("\xff\xff/management/tenant/configure/sample/tenant_group")))
+		})
+
+		It("should return an error with API version 710", func() {
+			_, err := getTenantGroupKey(710, "sample")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("parsing the tenant map entry", func() {
+		DescribeTable("it should return the correct status",
+			func(input string, expected fdbv1beta2.FoundationDBLiveTenantStatus) {
+				status, err := parseTenantMapEntry([]byte(input))
+				Expect(err).NotTo(HaveOccurred())
+				Expect(*status).To(Equal(expected))
+			},
+			Entry("with the format of 7.1",
+				`{"id":1,"prefix":"\u0000\u0000\u0000\u0000\u0000\u0000\u0000\u0001"}`,
+				fdbv1beta2.FoundationDBLiveTenantStatus{
+					ID: 1,
+				},
+			),
+			Entry("without a tenant group",
+				`{"id":1,"prefix":{"base64":"AAAAAAAAAAE=","printable":"\\x00\\x00\\x00\\x00\\x00\\x00\\x00\\x01"},"tenant_state":"ready"}`,
+				fdbv1beta2.FoundationDBLiveTenantStatus{
+					ID:    1,
+					State: "ready",
+				},
+			),
+			Entry("with a tenant group",
+				`{"id":2,"tenant_group":{"base64":"Z3JvdXA=","printable":"group"},"tenant_state":"ready"}`,
+				fdbv1beta2.FoundationDBLiveTenantStatus{
+					ID:          2,
+					State:       "ready",
+					TenantGroup: "group",
+				},
+			),
+		)
+
+		It("should return an error if the value is not valid JSON", func() {
+			_, err := parseTenantMapEntry([]byte("invalid"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
 	When("getting the log dir parameter", func() {
 		DescribeTable("it should return the correct format of the log dir paramater",
 			func(cmd cliCommand, expected string) {
@@ -234,6 +347,12 @@ var _ = Describe("admin_client_test", func() {
 				},
 				"--logdir",
 			),
+			Entry("fdbdr binary set",
+				cliCommand{
+					binary: fdbdrStr,
+				},
+				"--logdir",
+			),
 		)
 	})
 
@@ -731,5 +850,79 @@ protocol fdb00b071010000`,
 		})
 	})
 
-	// TODO(johscheuer): Add test case for timeout.
+	When("running a command with backoff", func() {
+		var mockRunner *mockCommandRunner
+		var output string
+		var err error
+		timeoutResult := mockedCommandResult{
+			output: "Specified timeout reached -- this may indicate that the database is unavailable",
+			err:    fmt.Errorf("exit status 1"),
+		}
+
+		JustBeforeEach(func() {
+			cliClient := &cliAdminClient{
+				Cluster: &fdbv1beta2.FoundationDBCluster{
+					Spec: fdbv1beta2.FoundationDBClusterSpec{
+						Version: fdbv1beta2.Versions.Default.String(),
+					},
+					Status: fdbv1beta2.FoundationDBClusterStatus{
+						RunningVersion: fdbv1beta2.Versions.Default.String(),
+					},
+				},
+				clusterFilePath: "test",
+				log:             logr.Discard(),
+				cmdRunner:       mockRunner,
+			}
+
+			output, err = cliClient.runCommandWithBackoff("status json")
+		})
+
+		When("the command hits a timeout once", func() {
+			BeforeEach(func() {
+				mockRunner = &mockCommandRunner{
+					mockedResults: []mockedCommandResult{timeoutResult},
+					mockedOutput:  "{}",
+				}
+			})
+
+			It("should retry the command with a higher timeout", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(output).To(Equal("{}"))
+				Expect(mockRunner.receivedArgsPerCall).To(HaveLen(2))
+				Expect(mockRunner.receivedArgsPerCall[0]).To(ContainElements("--timeout", "10"))
+				Expect(mockRunner.receivedArgsPerCall[1]).To(ContainElements("--timeout", "20"))
+			})
+		})
+
+		When("the command always hits a timeout", func() {
+			BeforeEach(func() {
+				mockRunner = &mockCommandRunner{
+					mockedResults: []mockedCommandResult{timeoutResult, timeoutResult, timeoutResult, timeoutResult},
+				}
+			})
+
+			It("should return the timeout error after three attempts", func() {
+				var timeoutError *fdbv1beta2.TimeoutError
+				Expect(errors.As(err, &timeoutError)).To(BeTrue())
+				Expect(mockRunner.receivedArgsPerCall).To(HaveLen(3))
+				Expect(mockRunner.receivedArgsPerCall[2]).To(ContainElements("--timeout", "40"))
+			})
+		})
+
+		When("the command fails with an error other than a timeout", func() {
+			BeforeEach(func() {
+				mockRunner = &mockCommandRunner{
+					mockedResults: []mockedCommandResult{
+						{output: "ERROR: unknown command", err: fmt.Errorf("exit status 1")},
+					},
+					mockedOutput: "{}",
+				}
+			})
+
+			It("should return the error without retrying", func() {
+				Expect(err).To(MatchError("exit status 1"))
+				Expect(mockRunner.receivedArgsPerCall).To(HaveLen(1))
+			})
+		})
+	})
 })
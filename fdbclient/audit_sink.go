@@ -0,0 +1,80 @@
+/*
+ * audit_sink.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fdbclient
+
+import (
+	"context"
+	"encoding/json"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
+	"github.com/apple/foundationdb/bindings/go/src/fdb"
+	"github.com/go-logr/logr"
+)
+
+// realDatabaseAuditSink provides an implementation of audit.Sink that stores
+// the audit entries in a key range inside the cluster, next to the lock keys.
+type realDatabaseAuditSink struct {
+	// log implementation for logging output
+	log logr.Logger
+}
+
+// NewDatabaseAuditSink creates a new audit sink that stores the audit entries
+// in the key range under the lock prefix of the cluster.
+func NewDatabaseAuditSink(log logr.Logger) audit.Sink {
+	return &realDatabaseAuditSink{
+		log: log.WithName("fdbclient"),
+	}
+}
+
+// Record stores the audit entry as JSON value. The keys are ordered by the
+// timestamp of the audit entry.
+func (sink *realDatabaseAuditSink) Record(_ context.Context, cluster *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) error {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	database, err := getFDBDatabase(cluster)
+	if err != nil {
+		return err
+	}
+
+	key := fdb.Key(audit.GetDatabaseKey(cluster, entry))
+	sink.log.V(1).Info("Recording audit entry", "namespace", cluster.Namespace, "cluster", cluster.Name, "key", key)
+
+	_, err = database.Transact(func(transaction fdb.Transaction) (interface{}, error) {
+		err := transaction.Options().SetAccessSystemKeys()
+		if err != nil {
+			return nil, err
+		}
+
+		transaction.Set(key, value)
+		return nil, nil
+	})
+
+	return err
+}
+
+// String returns a description of the sink for logging.
+func (sink *realDatabaseAuditSink) String() string {
+	return "database"
+}
@@ -83,11 +83,31 @@ type mockCommandRunner struct {
 	// mockedOutputPerBinary is the output returned if the binary is matching. This can be helpful to test the behaviour for
 	// different versions.
 	mockedOutputPerBinary map[string]string
+	// mockedResults are returned in order by the calls to runCommand. Once all results are used, the mockedOutput and
+	// mockedError will be returned. This can be helpful to test retries.
+	mockedResults []mockedCommandResult
+	// receivedArgsPerCall will be the args of all calls to runCommand.
+	receivedArgsPerCall [][]string
+}
+
+// mockedCommandResult is the result of a single call to runCommand of the mockCommandRunner.
+type mockedCommandResult struct {
+	// output is the output returned by runCommand.
+	output string
+	// err is the error returned by runCommand.
+	err error
 }
 
 func (runner *mockCommandRunner) runCommand(_ context.Context, name string, arg ...string) ([]byte, error) {
 	runner.receivedBinary = name
 	runner.receivedArgs = arg
+	runner.receivedArgsPerCall = append(runner.receivedArgsPerCall, arg)
+
+	if len(runner.mockedResults) > 0 {
+		result := runner.mockedResults[0]
+		runner.mockedResults = runner.mockedResults[1:]
+		return []byte(result.output), result.err
+	}
 
 	var mockedOutput string
 	if output, ok := runner.mockedOutputPerBinary[name]; ok {
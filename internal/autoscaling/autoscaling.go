@@ -0,0 +1,340 @@
+/*
+ * autoscaling.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoscaling
+
+import (
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// storageLimitingReasons contains the ratekeeper limiting reasons that can be
+// resolved by adding storage processes.
+var storageLimitingReasons = map[string]fdbv1beta2.None{
+	"storage_server_write_queue_size":     {},
+	"storage_server_write_bandwidth_mvcc": {},
+	"storage_server_readable_behind":      {},
+	"storage_server_durability_lag":       {},
+	"storage_server_min_free_space":       {},
+	"storage_server_min_free_space_ratio": {},
+}
+
+// logLimitingReasons contains the ratekeeper limiting reasons that can be
+// resolved by adding log processes.
+var logLimitingReasons = map[string]fdbv1beta2.None{
+	"log_server_mvcc_write_bandwidth": {},
+	"log_server_write_queue":          {},
+	"log_server_min_free_space":       {},
+	"log_server_min_free_space_ratio": {},
+}
+
+// signal represents a measured value and the threshold above which the
+// count should be increased.
+type signal struct {
+	name      string
+	value     float64
+	threshold float64
+	unit      string
+}
+
+// GetDecisions returns the changes of the process and role counts based on
+// the load reported in the machine-readable status. Every count is changed
+// by at most one per decision and stays within the configured range.
+func GetDecisions(cluster *fdbv1beta2.FoundationDBCluster, status *fdbv1beta2.FoundationDBStatus, now time.Time) ([]fdbv1beta2.AutoscalingDecision, error) {
+	if !cluster.GetAutoscalingEnabled() {
+		return nil, nil
+	}
+
+	processCounts, err := cluster.GetProcessCountsWithDefaults()
+	if err != nil {
+		return nil, err
+	}
+	roleCounts := cluster.GetRoleCountsWithDefaults()
+
+	qos := status.Cluster.Qos
+	// If ratekeeper doesn't report a reason, the quality of service
+	// information is not available.
+	qosAvailable := qos.PerformanceLimitedBy.Name != ""
+	notLimited := qos.PerformanceLimitedBy.Name == "workload"
+	limitedBy := qos.PerformanceLimitedBy.Name
+	options := cluster.Spec.Autoscaling
+
+	decisions := make([]fdbv1beta2.AutoscalingDecision, 0, 4)
+	addDecision := func(decision *fdbv1beta2.AutoscalingDecision) {
+		if decision != nil && canScale(cluster, decision, now) {
+			decisions = append(decisions, *decision)
+		}
+	}
+
+	if options.Storage != nil {
+		var signals []signal
+		if qosAvailable {
+			signals = append(signals, signal{
+				name:      "worst storage queue",
+				value:     float64(qos.WorstQueueBytesStorageServer),
+				threshold: float64(cluster.GetAutoscalingStorageQueueBytes()),
+				unit:      "bytes",
+			})
+		}
+
+		diskUsedPercent, ok := getMaxDiskUsedPercent(status)
+		if ok {
+			signals = append(signals, signal{
+				name:      "disk usage",
+				value:     diskUsedPercent,
+				threshold: float64(cluster.GetAutoscalingDiskUsedPercent()),
+				unit:      "percent",
+			})
+		}
+
+		_, limited := storageLimitingReasons[limitedBy]
+		addDecision(getDecision(cluster, fdbv1beta2.AutoscalingTargetStorage, processCounts.Storage, getStorageRange(cluster), signals, limited, limitedBy, qosAvailable && notLimited))
+	}
+
+	if options.Logs != nil {
+		var signals []signal
+		if qosAvailable {
+			signals = append(signals, signal{
+				name:      "worst log queue",
+				value:     float64(qos.WorstQueueBytesLogServer),
+				threshold: float64(cluster.GetAutoscalingLogQueueBytes()),
+				unit:      "bytes",
+			})
+		}
+
+		_, limited := logLimitingReasons[limitedBy]
+		addDecision(getDecision(cluster, fdbv1beta2.AutoscalingTargetLogs, roleCounts.Logs, options.Logs, signals, limited, limitedBy, qosAvailable && notLimited))
+	}
+
+	if options.CommitProxies != nil || options.GrvProxies != nil {
+		version, err := fdbv1beta2.ParseFdbVersion(cluster.GetRunningVersion())
+		if err != nil {
+			return nil, err
+		}
+
+		// Only scale the proxies if they are configured separately, otherwise
+		// setting one of the counts would change the proxy configuration.
+		if version.HasSeparatedProxies() && cluster.Spec.DatabaseConfiguration.AreSeparatedProxiesConfigured() {
+			latency := status.Cluster.LatencyProbe
+			if options.CommitProxies != nil {
+				var signals []signal
+				if latency.CommitSeconds > 0 {
+					signals = append(signals, signal{
+						name:      "commit latency",
+						value:     latency.CommitSeconds * 1000,
+						threshold: float64(cluster.GetAutoscalingCommitLatencyMilliseconds()),
+						unit:      "ms",
+					})
+				}
+
+				addDecision(getDecision(cluster, fdbv1beta2.AutoscalingTargetCommitProxies, roleCounts.CommitProxies, options.CommitProxies, signals, false, "", latency.CommitSeconds > 0))
+			}
+
+			if options.GrvProxies != nil {
+				var signals []signal
+				if latency.TransactionStartSeconds > 0 {
+					signals = append(signals, signal{
+						name:      "GRV latency",
+						value:     latency.TransactionStartSeconds * 1000,
+						threshold: float64(cluster.GetAutoscalingGrvLatencyMilliseconds()),
+						unit:      "ms",
+					})
+				}
+
+				addDecision(getDecision(cluster, fdbv1beta2.AutoscalingTargetGrvProxies, roleCounts.GrvProxies, options.GrvProxies, signals, false, "", latency.TransactionStartSeconds > 0))
+			}
+		}
+	}
+
+	if len(decisions) == 0 {
+		return nil, nil
+	}
+
+	return decisions, nil
+}
+
+// getDecision returns the change of a single count or nil if the count
+// should stay the same. A count outside of the range is moved into the
+// range. A count is increased if any signal is above its threshold or if
+// ratekeeper is limited by a reason of this target. A count is only
+// decreased if the signals are available and all of them are below the
+// scale down percentage of their threshold.
+func getDecision(cluster *fdbv1beta2.FoundationDBCluster, target fdbv1beta2.AutoscalingTarget, current int, autoscalingRange *fdbv1beta2.AutoscalingRange, signals []signal, limited bool, limitedBy string, allowScaleDown bool) *fdbv1beta2.AutoscalingDecision {
+	if current < autoscalingRange.Min {
+		return &fdbv1beta2.AutoscalingDecision{Target: target, Previous: current, Desired: autoscalingRange.Min, Reason: fmt.Sprintf("count is below the minimum of %d", autoscalingRange.Min)}
+	}
+
+	if current > autoscalingRange.Max {
+		return &fdbv1beta2.AutoscalingDecision{Target: target, Previous: current, Desired: autoscalingRange.Max, Reason: fmt.Sprintf("count is above the maximum of %d", autoscalingRange.Max)}
+	}
+
+	reason := ""
+	if limited {
+		reason = fmt.Sprintf("ratekeeper is limited by %s", limitedBy)
+	}
+
+	for _, currentSignal := range signals {
+		if reason != "" {
+			break
+		}
+
+		if currentSignal.value > currentSignal.threshold {
+			reason = fmt.Sprintf("%s of %.0f %s is above the threshold of %.0f %s", currentSignal.name, currentSignal.value, currentSignal.unit, currentSignal.threshold, currentSignal.unit)
+		}
+	}
+
+	if reason != "" {
+		if current >= autoscalingRange.Max {
+			return nil
+		}
+
+		return &fdbv1beta2.AutoscalingDecision{Target: target, Previous: current, Desired: current + 1, Reason: reason}
+	}
+
+	if !allowScaleDown || len(signals) == 0 || current <= autoscalingRange.Min {
+		return nil
+	}
+
+	scaleDownPercent := float64(cluster.GetAutoscalingScaleDownPercent())
+	for _, currentSignal := range signals {
+		if currentSignal.value*100 >= currentSignal.threshold*scaleDownPercent {
+			return nil
+		}
+	}
+
+	return &fdbv1beta2.AutoscalingDecision{Target: target, Previous: current, Desired: current - 1, Reason: fmt.Sprintf("all signals are below %.0f percent of their thresholds", scaleDownPercent)}
+}
+
+// getStorageRange returns the range for the storage process count. The
+// storage process count that was set by the storage capacity management,
+// because process groups were running low on disk space, takes precedence:
+// the autoscaler never scales below this count, and the maximum is raised to
+// this count if needed.
+func getStorageRange(cluster *fdbv1beta2.FoundationDBCluster) *fdbv1beta2.AutoscalingRange {
+	storageRange := *cluster.Spec.Autoscaling.Storage
+	if !cluster.GetStorageCapacityManagementEnabled() || cluster.Status.StorageScaling == nil {
+		return &storageRange
+	}
+
+	capacityCount := cluster.Status.StorageScaling.Count
+	if capacityCount > storageRange.Min {
+		storageRange.Min = capacityCount
+	}
+
+	if capacityCount > storageRange.Max {
+		storageRange.Max = capacityCount
+	}
+
+	return &storageRange
+}
+
+// canScale returns true if the cooldown since the last scaling has passed.
+// Changes that move a count into the configured range are always allowed.
+func canScale(cluster *fdbv1beta2.FoundationDBCluster, decision *fdbv1beta2.AutoscalingDecision, now time.Time) bool {
+	autoscalingStatus := cluster.Status.Autoscaling
+	if autoscalingStatus == nil || autoscalingStatus.LastScaleTimestamp == 0 {
+		return true
+	}
+
+	var autoscalingRange *fdbv1beta2.AutoscalingRange
+	switch decision.Target {
+	case fdbv1beta2.AutoscalingTargetStorage:
+		autoscalingRange = getStorageRange(cluster)
+	case fdbv1beta2.AutoscalingTargetLogs:
+		autoscalingRange = cluster.Spec.Autoscaling.Logs
+	case fdbv1beta2.AutoscalingTargetCommitProxies:
+		autoscalingRange = cluster.Spec.Autoscaling.CommitProxies
+	case fdbv1beta2.AutoscalingTargetGrvProxies:
+		autoscalingRange = cluster.Spec.Autoscaling.GrvProxies
+	}
+
+	if autoscalingRange != nil && (decision.Previous < autoscalingRange.Min || decision.Previous > autoscalingRange.Max) {
+		return true
+	}
+
+	cooldown := cluster.GetAutoscalingScaleDownCooldownSeconds()
+	if decision.Desired > decision.Previous {
+		cooldown = cluster.GetAutoscalingScaleUpCooldownSeconds()
+	}
+
+	return !now.Before(time.Unix(autoscalingStatus.LastScaleTimestamp, 0).Add(time.Duration(cooldown) * time.Second))
+}
+
+// getMaxDiskUsedPercent returns the disk usage of the fullest storage role.
+// The second return value is false if no storage role reports its disk
+// usage.
+func getMaxDiskUsedPercent(status *fdbv1beta2.FoundationDBStatus) (float64, bool) {
+	found := false
+	maxUsedPercent := 0.0
+	for _, process := range status.Cluster.Processes {
+		for _, role := range process.Roles {
+			if role.Role != string(fdbv1beta2.ProcessRoleStorage) || role.KVStoreTotalBytes <= 0 {
+				continue
+			}
+
+			found = true
+			usedPercent := float64(role.KVStoreTotalBytes-role.KVStoreAvailableBytes) * 100 / float64(role.KVStoreTotalBytes)
+			if usedPercent > maxUsedPercent {
+				maxUsedPercent = usedPercent
+			}
+		}
+	}
+
+	return maxUsedPercent, found
+}
+
+// ApplyDecisions changes the process and role counts in the cluster spec. If
+// the process count for the log or stateless processes is set explicitly, it
+// is changed by the same amount as the role count.
+func ApplyDecisions(cluster *fdbv1beta2.FoundationDBCluster, decisions []fdbv1beta2.AutoscalingDecision) {
+	for _, decision := range decisions {
+		delta := decision.Desired - decision.Previous
+		switch decision.Target {
+		case fdbv1beta2.AutoscalingTargetStorage:
+			cluster.SetProcessCount(fdbv1beta2.ProcessClassStorage, decision.Desired)
+		case fdbv1beta2.AutoscalingTargetLogs:
+			cluster.Spec.DatabaseConfiguration.RoleCounts.Logs = decision.Desired
+			adjustExplicitProcessCount(cluster, fdbv1beta2.ProcessClassLog, delta)
+		case fdbv1beta2.AutoscalingTargetCommitProxies:
+			cluster.Spec.DatabaseConfiguration.RoleCounts.CommitProxies = decision.Desired
+			adjustExplicitProcessCount(cluster, fdbv1beta2.ProcessClassStateless, delta)
+		case fdbv1beta2.AutoscalingTargetGrvProxies:
+			cluster.Spec.DatabaseConfiguration.RoleCounts.GrvProxies = decision.Desired
+			adjustExplicitProcessCount(cluster, fdbv1beta2.ProcessClassStateless, delta)
+		}
+	}
+}
+
+// adjustExplicitProcessCount changes the process count of the process class
+// by delta if the count is set explicitly in the spec or through the scale
+// subresource. Counts that are derived from the role counts are left
+// unchanged.
+func adjustExplicitProcessCount(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass, delta int) {
+	current := cluster.Spec.ProcessCounts.Map()[processClass]
+	if cluster.Spec.Scale.Replicas != nil && cluster.GetScaleProcessClass() == processClass {
+		current = *cluster.Spec.Scale.Replicas
+	}
+
+	if current > 0 {
+		cluster.SetProcessCount(processClass, current+delta)
+	}
+}
@@ -0,0 +1,352 @@
+/*
+ * autoscaling_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package autoscaling
+
+import (
+	"time"
+
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("autoscaling", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var status *fdbv1beta2.FoundationDBStatus
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Now()
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.Autoscaling.Enabled = pointer.Bool(true)
+		status = &fdbv1beta2.FoundationDBStatus{
+			Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+				Qos: fdbv1beta2.FoundationDBStatusQosInfo{
+					PerformanceLimitedBy: fdbv1beta2.FoundationDBStatusPerformanceLimitedBy{Name: "workload", ReasonID: 2},
+					// 40% of the default threshold
+					WorstQueueBytesStorageServer: 200000000,
+					WorstQueueBytesLogServer:     400000000,
+				},
+				LatencyProbe: fdbv1beta2.FoundationDBStatusLatencyProbe{
+					CommitSeconds:           0.02,
+					TransactionStartSeconds: 0.01,
+				},
+			},
+		}
+	})
+
+	When("getting the decisions", func() {
+		var decisions []fdbv1beta2.AutoscalingDecision
+
+		JustBeforeEach(func() {
+			var err error
+			decisions, err = GetDecisions(cluster, status, now)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("no range is configured", func() {
+			It("should not change any count", func() {
+				Expect(decisions).To(BeEmpty())
+			})
+		})
+
+		When("the storage range is configured", func() {
+			BeforeEach(func() {
+				cluster.Spec.Autoscaling.Storage = &fdbv1beta2.AutoscalingRange{Min: 3, Max: 6}
+			})
+
+			When("the signals are between the thresholds", func() {
+				It("should not change the storage count", func() {
+					Expect(decisions).To(BeEmpty())
+				})
+			})
+
+			When("the storage queue is above the threshold", func() {
+				BeforeEach(func() {
+					status.Cluster.Qos.WorstQueueBytesStorageServer = 600000000
+				})
+
+				It("should increase the storage count", func() {
+					Expect(decisions).To(ConsistOf(fdbv1beta2.AutoscalingDecision{
+						Target:   fdbv1beta2.AutoscalingTargetStorage,
+						Previous: 4,
+						Desired:  5,
+						Reason:   "worst storage queue of 600000000 bytes is above the threshold of 500000000 bytes",
+					}))
+				})
+
+				When("the maximum is reached", func() {
+					BeforeEach(func() {
+						cluster.Spec.ProcessCounts.Storage = 6
+					})
+
+					It("should not change the storage count", func() {
+						Expect(decisions).To(BeEmpty())
+					})
+				})
+
+				When("the cluster was scaled recently", func() {
+					BeforeEach(func() {
+						cluster.Status.Autoscaling = &fdbv1beta2.AutoscalingStatus{LastScaleTimestamp: now.Add(-1 * time.Minute).Unix()}
+					})
+
+					It("should not change the storage count", func() {
+						Expect(decisions).To(BeEmpty())
+					})
+				})
+
+				When("the scale up cooldown has passed", func() {
+					BeforeEach(func() {
+						cluster.Status.Autoscaling = &fdbv1beta2.AutoscalingStatus{LastScaleTimestamp: now.Add(-11 * time.Minute).Unix()}
+					})
+
+					It("should increase the storage count", func() {
+						Expect(decisions).To(HaveLen(1))
+						Expect(decisions[0].Desired).To(Equal(5))
+					})
+				})
+			})
+
+			When("ratekeeper is limited by the storage servers", func() {
+				BeforeEach(func() {
+					status.Cluster.Qos.PerformanceLimitedBy.Name = "storage_server_write_queue_size"
+				})
+
+				It("should increase the storage count", func() {
+					Expect(decisions).To(HaveLen(1))
+					Expect(decisions[0].Desired).To(Equal(5))
+					Expect(decisions[0].Reason).To(Equal("ratekeeper is limited by storage_server_write_queue_size"))
+				})
+			})
+
+			When("a storage process is running out of disk space", func() {
+				BeforeEach(func() {
+					status.Cluster.Processes = map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{
+						"storage-1": {
+							Roles: []fdbv1beta2.FoundationDBStatusProcessRoleInfo{
+								{Role: string(fdbv1beta2.ProcessRoleStorage), KVStoreAvailableBytes: 10, KVStoreTotalBytes: 100},
+							},
+						},
+					}
+				})
+
+				It("should increase the storage count", func() {
+					Expect(decisions).To(HaveLen(1))
+					Expect(decisions[0].Desired).To(Equal(5))
+					Expect(decisions[0].Reason).To(Equal("disk usage of 90 percent is above the threshold of 80 percent"))
+				})
+			})
+
+			When("all signals are below the scale down threshold", func() {
+				BeforeEach(func() {
+					status.Cluster.Qos.WorstQueueBytesStorageServer = 1000
+				})
+
+				It("should decrease the storage count", func() {
+					Expect(decisions).To(ConsistOf(fdbv1beta2.AutoscalingDecision{
+						Target:   fdbv1beta2.AutoscalingTargetStorage,
+						Previous: 4,
+						Desired:  3,
+						Reason:   "all signals are below 30 percent of their thresholds",
+					}))
+				})
+
+				When("the minimum is reached", func() {
+					BeforeEach(func() {
+						cluster.Spec.ProcessCounts.Storage = 3
+					})
+
+					It("should not change the storage count", func() {
+						Expect(decisions).To(BeEmpty())
+					})
+				})
+
+				When("the scale down cooldown has not passed", func() {
+					BeforeEach(func() {
+						cluster.Status.Autoscaling = &fdbv1beta2.AutoscalingStatus{LastScaleTimestamp: now.Add(-30 * time.Minute).Unix()}
+					})
+
+					It("should not change the storage count", func() {
+						Expect(decisions).To(BeEmpty())
+					})
+				})
+
+				When("the quality of service information is missing", func() {
+					BeforeEach(func() {
+						status.Cluster.Qos = fdbv1beta2.FoundationDBStatusQosInfo{}
+					})
+
+					It("should not change the storage count", func() {
+						Expect(decisions).To(BeEmpty())
+					})
+				})
+			})
+
+			When("the storage capacity management increased the storage count", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.StorageCapacity.Enabled = pointer.Bool(true)
+					cluster.Spec.ProcessCounts.Storage = 7
+					cluster.Status.StorageScaling = &fdbv1beta2.StorageScalingStatus{PreviousCount: 6, Count: 7}
+				})
+
+				It("should not change the storage count above the maximum", func() {
+					Expect(decisions).To(BeEmpty())
+				})
+
+				When("all signals are below the scale down threshold", func() {
+					BeforeEach(func() {
+						status.Cluster.Qos.WorstQueueBytesStorageServer = 1000
+					})
+
+					It("should not decrease the storage count below the capacity-driven count", func() {
+						Expect(decisions).To(BeEmpty())
+					})
+				})
+
+				When("the storage capacity management is disabled", func() {
+					BeforeEach(func() {
+						cluster.Spec.AutomationOptions.StorageCapacity.Enabled = nil
+					})
+
+					It("should decrease the storage count to the maximum", func() {
+						Expect(decisions).To(ConsistOf(fdbv1beta2.AutoscalingDecision{
+							Target:   fdbv1beta2.AutoscalingTargetStorage,
+							Previous: 7,
+							Desired:  6,
+							Reason:   "count is above the maximum of 6",
+						}))
+					})
+				})
+			})
+
+			When("the count is below the minimum", func() {
+				BeforeEach(func() {
+					cluster.Spec.ProcessCounts.Storage = 2
+					cluster.Status.Autoscaling = &fdbv1beta2.AutoscalingStatus{LastScaleTimestamp: now.Unix()}
+				})
+
+				It("should increase the storage count to the minimum", func() {
+					Expect(decisions).To(HaveLen(1))
+					Expect(decisions[0].Desired).To(Equal(3))
+				})
+			})
+
+			When("the autoscaling is disabled", func() {
+				BeforeEach(func() {
+					cluster.Spec.Autoscaling.Enabled = nil
+					status.Cluster.Qos.WorstQueueBytesStorageServer = 600000000
+				})
+
+				It("should not change any count", func() {
+					Expect(decisions).To(BeEmpty())
+				})
+			})
+		})
+
+		When("the log range is configured and ratekeeper is limited by the log servers", func() {
+			BeforeEach(func() {
+				cluster.Spec.Autoscaling.Logs = &fdbv1beta2.AutoscalingRange{Min: 3, Max: 5}
+				status.Cluster.Qos.PerformanceLimitedBy.Name = "log_server_write_queue"
+			})
+
+			It("should increase the log count", func() {
+				Expect(decisions).To(HaveLen(1))
+				Expect(decisions[0].Target).To(Equal(fdbv1beta2.AutoscalingTargetLogs))
+				Expect(decisions[0].Previous).To(Equal(3))
+				Expect(decisions[0].Desired).To(Equal(4))
+			})
+		})
+
+		When("the proxy ranges are configured", func() {
+			BeforeEach(func() {
+				cluster.Spec.Autoscaling.CommitProxies = &fdbv1beta2.AutoscalingRange{Min: 1, Max: 4}
+				cluster.Spec.Autoscaling.GrvProxies = &fdbv1beta2.AutoscalingRange{Min: 1, Max: 4}
+				status.Cluster.LatencyProbe.CommitSeconds = 0.1
+			})
+
+			When("the proxies are not configured separately", func() {
+				It("should not change the proxy counts", func() {
+					Expect(decisions).To(BeEmpty())
+				})
+			})
+
+			When("the proxies are configured separately", func() {
+				BeforeEach(func() {
+					cluster.Spec.Version = fdbv1beta2.Versions.NextMajorVersion.String()
+					cluster.Spec.DatabaseConfiguration.CommitProxies = 2
+					cluster.Spec.DatabaseConfiguration.GrvProxies = 2
+				})
+
+				It("should increase the commit proxy count", func() {
+					Expect(decisions).To(ConsistOf(fdbv1beta2.AutoscalingDecision{
+						Target:   fdbv1beta2.AutoscalingTargetCommitProxies,
+						Previous: 2,
+						Desired:  3,
+						Reason:   "commit latency of 100 ms is above the threshold of 50 ms",
+					}))
+				})
+			})
+		})
+	})
+
+	When("applying the decisions", func() {
+		BeforeEach(func() {
+			cluster.Spec.ProcessCounts = fdbv1beta2.ProcessCounts{
+				Storage:   4,
+				Stateless: 8,
+			}
+		})
+
+		It("should change the process and role counts", func() {
+			ApplyDecisions(cluster, []fdbv1beta2.AutoscalingDecision{
+				{Target: fdbv1beta2.AutoscalingTargetStorage, Previous: 4, Desired: 5},
+				{Target: fdbv1beta2.AutoscalingTargetLogs, Previous: 3, Desired: 4},
+				{Target: fdbv1beta2.AutoscalingTargetCommitProxies, Previous: 2, Desired: 3},
+				{Target: fdbv1beta2.AutoscalingTargetGrvProxies, Previous: 2, Desired: 1},
+			})
+
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(5))
+			Expect(cluster.Spec.ProcessCounts.Log).To(Equal(0))
+			Expect(cluster.Spec.ProcessCounts.Stateless).To(Equal(8))
+			Expect(cluster.Spec.DatabaseConfiguration.Logs).To(Equal(4))
+			Expect(cluster.Spec.DatabaseConfiguration.CommitProxies).To(Equal(3))
+			Expect(cluster.Spec.DatabaseConfiguration.GrvProxies).To(Equal(1))
+		})
+
+		When("the storage processes are scaled through the scale subresource", func() {
+			BeforeEach(func() {
+				cluster.Spec.Scale.Replicas = pointer.Int(4)
+			})
+
+			It("should change the replicas", func() {
+				ApplyDecisions(cluster, []fdbv1beta2.AutoscalingDecision{
+					{Target: fdbv1beta2.AutoscalingTargetStorage, Previous: 4, Desired: 5},
+				})
+
+				Expect(cluster.Spec.Scale.Replicas).To(Equal(pointer.Int(5)))
+				Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(4))
+			})
+		})
+	})
+})
@@ -0,0 +1,157 @@
+/*
+ * backup_verification.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// GetBackupVerificationCluster builds the temporary cluster that is used to
+// verify a backup.
+func GetBackupVerificationCluster(backup *fdbv1beta2.FoundationDBBackup) *fdbv1beta2.FoundationDBCluster {
+	cluster := &fdbv1beta2.FoundationDBCluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: backup.ObjectMeta.Namespace,
+			Name:      backup.VerificationClusterName(),
+			Labels: map[string]string{
+				fdbv1beta2.BackupVerificationLabel: string(backup.ObjectMeta.UID),
+			},
+			OwnerReferences: BuildOwnerReference(backup.TypeMeta, backup.ObjectMeta),
+		},
+		Spec: *backup.Spec.Verification.ClusterTemplate.DeepCopy(),
+	}
+
+	if cluster.Spec.Version == "" {
+		cluster.Spec.Version = backup.Spec.Version
+	}
+
+	return cluster
+}
+
+// GetBackupVerificationAgentDeployment builds the Deployment for the backup
+// agents that run against the temporary cluster. The restore into the
+// temporary cluster is performed by these agents. This will return nil if
+// the backup has no agents configured.
+func GetBackupVerificationAgentDeployment(backup *fdbv1beta2.FoundationDBBackup, credentialsSecret *corev1.Secret) (*appsv1.Deployment, error) {
+	verificationBackup := backup.DeepCopy()
+	verificationBackup.ObjectMeta.Name = backup.VerificationClusterName()
+	verificationBackup.Spec.ClusterName = backup.VerificationClusterName()
+
+	deployment, err := GetBackupDeployment(verificationBackup, credentialsSecret)
+	if err != nil || deployment == nil {
+		return deployment, err
+	}
+
+	// The agents of the temporary cluster must not be counted as agents of
+	// the backup.
+	delete(deployment.ObjectMeta.Labels, fdbv1beta2.BackupDeploymentLabel)
+	deployment.ObjectMeta.Labels[fdbv1beta2.BackupVerificationLabel] = string(backup.ObjectMeta.UID)
+
+	return deployment, nil
+}
+
+// IsBackupVerificationObject returns true if the object was created for the
+// verification of the backup.
+func IsBackupVerificationObject(backup *fdbv1beta2.FoundationDBBackup, object metav1.Object) bool {
+	backupUID, ok := object.GetLabels()[fdbv1beta2.BackupVerificationLabel]
+	return ok && backupUID == string(backup.ObjectMeta.UID)
+}
+
+// GetBackupVerificationRestore builds the restore that restores the backup
+// into the temporary cluster.
+func GetBackupVerificationRestore(backup *fdbv1beta2.FoundationDBBackup) *fdbv1beta2.FoundationDBRestore {
+	blobStoreConfiguration := backup.Spec.BlobStoreConfiguration.DeepCopy()
+	blobStoreConfiguration.BackupName = backup.BackupName()
+	blobStoreConfiguration.Bucket = backup.Bucket()
+
+	return &fdbv1beta2.FoundationDBRestore{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: backup.ObjectMeta.Namespace,
+			Name:      backup.VerificationClusterName(),
+			Labels: map[string]string{
+				fdbv1beta2.BackupVerificationLabel: string(backup.ObjectMeta.UID),
+			},
+			OwnerReferences: BuildOwnerReference(backup.TypeMeta, backup.ObjectMeta),
+		},
+		Spec: fdbv1beta2.FoundationDBRestoreSpec{
+			DestinationClusterName: backup.VerificationClusterName(),
+			BlobStoreConfiguration: blobStoreConfiguration,
+			CustomParameters:       backup.Spec.CustomParameters,
+		},
+	}
+}
+
+// GetBackupVerificationJob builds the Job that checks the data in the
+// temporary cluster after the restore completed. This will return nil if no
+// check is configured.
+func GetBackupVerificationJob(backup *fdbv1beta2.FoundationDBBackup) *batchv1.Job {
+	if backup.Spec.Verification == nil || backup.Spec.Verification.CheckPodTemplateSpec == nil {
+		return nil
+	}
+
+	clusterName := backup.VerificationClusterName()
+	podTemplate := backup.Spec.Verification.CheckPodTemplateSpec.DeepCopy()
+	if podTemplate.Spec.RestartPolicy == "" {
+		podTemplate.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+
+	for idx := range podTemplate.Spec.Containers {
+		container := &podTemplate.Spec.Containers[idx]
+		extendEnv(container, corev1.EnvVar{Name: "FDB_CLUSTER_FILE", Value: "/var/dynamic-conf/fdb.cluster"})
+		container.VolumeMounts = append(container.VolumeMounts,
+			corev1.VolumeMount{Name: "config-map", MountPath: "/var/dynamic-conf"},
+		)
+	}
+
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes,
+		corev1.Volume{
+			Name: "config-map",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: fmt.Sprintf("%s-config", clusterName)},
+				Items: []corev1.KeyToPath{
+					{Key: ClusterFileKey, Path: "fdb.cluster"},
+				},
+			}},
+		},
+	)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: backup.ObjectMeta.Namespace,
+			Name:      fmt.Sprintf("%s-check", clusterName),
+			Labels: map[string]string{
+				fdbv1beta2.BackupVerificationLabel: string(backup.ObjectMeta.UID),
+			},
+			OwnerReferences: BuildOwnerReference(backup.TypeMeta, backup.ObjectMeta),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: pointer.Int32(0),
+			Template:     *podTemplate,
+		},
+	}
+}
@@ -0,0 +1,157 @@
+/*
+ * backup_verification_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("backup_verification", func() {
+	var backup *fdbv1beta2.FoundationDBBackup
+
+	BeforeEach(func() {
+		cluster := CreateDefaultCluster()
+		backup = CreateDefaultBackup(cluster)
+		backup.Spec.Verification = &fdbv1beta2.BackupVerificationConfiguration{
+			ClusterTemplate: fdbv1beta2.FoundationDBClusterSpec{
+				ProcessCounts: fdbv1beta2.ProcessCounts{
+					Storage: 3,
+				},
+			},
+		}
+	})
+
+	Describe("GetBackupVerificationCluster", func() {
+		var cluster *fdbv1beta2.FoundationDBCluster
+
+		JustBeforeEach(func() {
+			cluster = GetBackupVerificationCluster(backup)
+		})
+
+		It("should set the metadata", func() {
+			Expect(cluster.Name).To(Equal("operator-test-1-verification"))
+			Expect(cluster.Namespace).To(Equal(backup.Namespace))
+			Expect(cluster.Labels).To(Equal(map[string]string{
+				fdbv1beta2.BackupVerificationLabel: string(backup.UID),
+			}))
+			Expect(cluster.OwnerReferences).To(HaveLen(1))
+			Expect(cluster.OwnerReferences[0].UID).To(Equal(backup.UID))
+		})
+
+		It("should use the template and the backup version", func() {
+			Expect(cluster.Spec.ProcessCounts.Storage).To(Equal(3))
+			Expect(cluster.Spec.Version).To(Equal(backup.Spec.Version))
+		})
+
+		When("the template defines a version", func() {
+			BeforeEach(func() {
+				backup.Spec.Verification.ClusterTemplate.Version = fdbv1beta2.Versions.NextMajorVersion.String()
+			})
+
+			It("should use the version from the template", func() {
+				Expect(cluster.Spec.Version).To(Equal(fdbv1beta2.Versions.NextMajorVersion.String()))
+			})
+		})
+	})
+
+	Describe("GetBackupVerificationAgentDeployment", func() {
+		It("should run the agents against the verification cluster", func() {
+			deployment, err := GetBackupVerificationAgentDeployment(backup, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment).NotTo(BeNil())
+			Expect(deployment.Name).To(Equal("operator-test-1-verification-backup-agents"))
+			Expect(deployment.Labels).To(HaveKeyWithValue(fdbv1beta2.BackupVerificationLabel, string(backup.UID)))
+			Expect(deployment.Labels).NotTo(HaveKey(fdbv1beta2.BackupDeploymentLabel))
+			Expect(deployment.OwnerReferences).To(HaveLen(1))
+			Expect(deployment.OwnerReferences[0].UID).To(Equal(backup.UID))
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(HaveField("VolumeSource.ConfigMap.LocalObjectReference.Name", "operator-test-1-verification-config")))
+		})
+
+		It("should not modify the backup", func() {
+			_, err := GetBackupVerificationAgentDeployment(backup, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(backup.Name).To(Equal("operator-test-1"))
+			Expect(backup.Spec.ClusterName).To(Equal("operator-test-1"))
+		})
+	})
+
+	Describe("IsBackupVerificationObject", func() {
+		It("should only match objects with the label of the backup", func() {
+			Expect(IsBackupVerificationObject(backup, GetBackupVerificationCluster(backup))).To(BeTrue())
+
+			cluster := CreateDefaultCluster()
+			Expect(IsBackupVerificationObject(backup, cluster)).To(BeFalse())
+
+			cluster.Labels = map[string]string{fdbv1beta2.BackupVerificationLabel: "other"}
+			Expect(IsBackupVerificationObject(backup, cluster)).To(BeFalse())
+		})
+	})
+
+	Describe("GetBackupVerificationRestore", func() {
+		It("should restore the backup into the verification cluster", func() {
+			restore := GetBackupVerificationRestore(backup)
+			Expect(restore.Name).To(Equal("operator-test-1-verification"))
+			Expect(restore.Spec.DestinationClusterName).To(Equal("operator-test-1-verification"))
+			Expect(restore.BackupURL()).To(Equal(backup.BackupURL()))
+		})
+	})
+
+	Describe("GetBackupVerificationJob", func() {
+		When("no check is defined", func() {
+			It("should not return a job", func() {
+				Expect(GetBackupVerificationJob(backup)).To(BeNil())
+			})
+		})
+
+		When("a check is defined", func() {
+			BeforeEach(func() {
+				backup.Spec.Verification.CheckPodTemplateSpec = &corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{
+							{
+								Name:  "check",
+								Image: "foundationdb/foundationdb",
+							},
+						},
+					},
+				}
+			})
+
+			It("should mount the cluster file of the verification cluster", func() {
+				job := GetBackupVerificationJob(backup)
+				Expect(job).NotTo(BeNil())
+				Expect(job.Name).To(Equal("operator-test-1-verification-check"))
+				Expect(*job.Spec.BackoffLimit).To(BeNumerically("==", 0))
+				Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+
+				container := job.Spec.Template.Spec.Containers[0]
+				Expect(container.Env).To(ConsistOf(corev1.EnvVar{Name: "FDB_CLUSTER_FILE", Value: "/var/dynamic-conf/fdb.cluster"}))
+				Expect(container.VolumeMounts).To(ConsistOf(corev1.VolumeMount{Name: "config-map", MountPath: "/var/dynamic-conf"}))
+
+				Expect(job.Spec.Template.Spec.Volumes).To(HaveLen(1))
+				Expect(job.Spec.Template.Spec.Volumes[0].ConfigMap.Name).To(Equal("operator-test-1-verification-config"))
+			})
+		})
+	})
+})
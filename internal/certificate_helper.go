@@ -0,0 +1,153 @@
+/*
+ * certificate_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CACertificateKey is the key in a TLS Secret that contains the CA that
+// issued the certificate, as used by cert-manager.
+const CACertificateKey = "ca.crt"
+
+// parseCertificates parses all certificates in a PEM encoded string.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+
+		certificates = append(certificates, certificate)
+	}
+
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("no certificate found")
+	}
+
+	return certificates, nil
+}
+
+// GetCertificateStatus provides information about the certificate in the
+// tls.crt key of a Secret.
+func GetCertificateStatus(secret *corev1.Secret) fdbv1beta2.CertificateStatus {
+	status := fdbv1beta2.CertificateStatus{
+		SecretName: secret.Name,
+	}
+
+	data, ok := secret.Data[corev1.TLSCertKey]
+	if !ok {
+		status.Error = fmt.Sprintf("Secret has no key %s", corev1.TLSCertKey)
+		return status
+	}
+
+	certificates, err := parseCertificates(data)
+	if err != nil {
+		status.Error = fmt.Sprintf("could not parse certificate: %s", err.Error())
+		return status
+	}
+
+	certificate := certificates[0]
+	status.Subject = certificate.Subject.String()
+	status.Issuer = certificate.Issuer.String()
+	status.SerialNumber = certificate.SerialNumber.String()
+	status.NotAfter = &metav1.Time{Time: certificate.NotAfter}
+
+	return status
+}
+
+// GetCAFromSecret returns the CA in the ca.crt key of a Secret. This will
+// return an empty string if the Secret contains no CA.
+func GetCAFromSecret(secret *corev1.Secret) string {
+	return strings.TrimSpace(string(secret.Data[CACertificateKey]))
+}
+
+// MergeTrustedCAs merges lists of CAs in PEM format, while preserving the
+// order and removing duplicates.
+func MergeTrustedCAs(caLists ...[]string) []string {
+	var merged []string
+	seen := map[string]fdbv1beta2.None{}
+
+	for _, cas := range caLists {
+		for _, ca := range cas {
+			trimmed := strings.TrimSpace(ca)
+			if trimmed == "" {
+				continue
+			}
+
+			if _, ok := seen[trimmed]; ok {
+				continue
+			}
+
+			seen[trimmed] = fdbv1beta2.None{}
+			merged = append(merged, ca)
+		}
+	}
+
+	return merged
+}
+
+// IsCertificateTrusted checks whether the certificate in the tls.crt key of a
+// Secret was issued by one of the provided CAs. This only checks the chain of
+// trust and ignores the expiry of the certificate.
+func IsCertificateTrusted(secret *corev1.Secret, cas []string) bool {
+	certificates, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+	if err != nil {
+		return false
+	}
+
+	roots := x509.NewCertPool()
+	for _, ca := range cas {
+		roots.AppendCertsFromPEM([]byte(ca))
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err = certificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   certificates[0].NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+
+	return err == nil
+}
@@ -0,0 +1,95 @@
+/*
+ * certificate_helper_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var _ = Describe("certificate_helper", func() {
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		var err error
+		secret, err = CreateTestCertificateSecret("my-ns", "fdb-certificate", 10)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("GetCertificateStatus", func() {
+		It("should report the certificate", func() {
+			status := GetCertificateStatus(secret)
+			Expect(status.SecretName).To(Equal("fdb-certificate"))
+			Expect(status.Subject).To(Equal("CN=fdb-certificate"))
+			Expect(status.Issuer).To(Equal("CN=fdb-certificate-ca"))
+			Expect(status.SerialNumber).To(Equal("10"))
+			Expect(status.NotAfter).NotTo(BeNil())
+			Expect(status.Error).To(BeEmpty())
+		})
+
+		When("the Secret has no certificate", func() {
+			BeforeEach(func() {
+				delete(secret.Data, corev1.TLSCertKey)
+			})
+
+			It("should report an error", func() {
+				status := GetCertificateStatus(secret)
+				Expect(status.NotAfter).To(BeNil())
+				Expect(status.Error).To(Equal("Secret has no key tls.crt"))
+			})
+		})
+
+		When("the certificate is invalid", func() {
+			BeforeEach(func() {
+				secret.Data[corev1.TLSCertKey] = []byte("invalid")
+			})
+
+			It("should report an error", func() {
+				status := GetCertificateStatus(secret)
+				Expect(status.NotAfter).To(BeNil())
+				Expect(status.Error).To(Equal("could not parse certificate: no certificate found"))
+			})
+		})
+	})
+
+	Describe("MergeTrustedCAs", func() {
+		It("should preserve the order and remove duplicates", func() {
+			Expect(MergeTrustedCAs([]string{"ca1", "ca2"}, []string{"ca2\n", "ca3", ""})).To(Equal([]string{"ca1", "ca2", "ca3"}))
+		})
+
+		It("should return nil without CAs", func() {
+			Expect(MergeTrustedCAs(nil, []string{})).To(BeNil())
+		})
+	})
+
+	Describe("IsCertificateTrusted", func() {
+		It("should trust the certificate with the issuing CA", func() {
+			Expect(IsCertificateTrusted(secret, []string{GetCAFromSecret(secret)})).To(BeTrue())
+		})
+
+		It("should not trust the certificate with a different CA", func() {
+			other, err := CreateTestCertificateSecret("my-ns", "other-certificate", 11)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(IsCertificateTrusted(secret, []string{GetCAFromSecret(other)})).To(BeFalse())
+		})
+	})
+})
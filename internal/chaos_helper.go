@@ -0,0 +1,184 @@
+/*
+ * chaos_helper.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strconv"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+)
+
+// chaosDiskFillPath is the file that is used to fill up the data volume of a
+// process group.
+const chaosDiskFillPath = "/var/fdb/data/chaos-disk-fill"
+
+// GetChaosObjectMetadata returns the metadata for a resource that is created
+// for the provided injection of a chaos experiment.
+func GetChaosObjectMetadata(chaos *fdbv1beta2.FoundationDBChaos, name string, injection int) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:            name,
+		Namespace:       chaos.Namespace,
+		Labels:          GetChaosInjectionLabels(chaos, injection),
+		OwnerReferences: BuildOwnerReference(chaos.TypeMeta, chaos.ObjectMeta),
+	}
+}
+
+// GetChaosInjectionLabels returns the labels of the resources that were
+// created for the provided injection of a chaos experiment.
+func GetChaosInjectionLabels(chaos *fdbv1beta2.FoundationDBChaos, injection int) map[string]string {
+	return map[string]string{
+		fdbv1beta2.ChaosLabel:          chaos.Name,
+		fdbv1beta2.ChaosInjectionLabel: strconv.Itoa(injection),
+	}
+}
+
+// GetChaosDiskFillCleanupCommand returns the command that removes the file
+// that fills up the data volume of a process group.
+func GetChaosDiskFillCleanupCommand() []string {
+	return []string{"rm", "-f", chaosDiskFillPath}
+}
+
+// GetChaosNetworkPolicy builds the NetworkPolicy that injects the fault of a
+// PartitionZone or a StallSidecars experiment into the provided process groups.
+func GetChaosNetworkPolicy(chaos *fdbv1beta2.FoundationDBChaos, cluster *fdbv1beta2.FoundationDBCluster, processGroupIDs []fdbv1beta2.ProcessGroupID, injection int) (*networkingv1.NetworkPolicy, error) {
+	ids := make([]string, 0, len(processGroupIDs))
+	for _, processGroupID := range processGroupIDs {
+		ids = append(ids, string(processGroupID))
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: GetChaosObjectMetadata(chaos, fmt.Sprintf("%s-%d", chaos.Name, injection), injection),
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: cluster.GetMatchLabels(),
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      cluster.GetProcessGroupIDLabel(),
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   ids,
+					},
+				},
+			},
+		},
+	}
+
+	switch chaos.Spec.Action {
+	case fdbv1beta2.ChaosActionPartitionZone:
+		// A policy without any rules denies all traffic for the selected
+		// pods.
+		policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}
+	case fdbv1beta2.ChaosActionStallSidecars:
+		// Only allow the traffic to the fdbserver processes, which blocks
+		// the traffic to the sidecar.
+		servicePorts := generateServicePorts(cluster.GetStorageServersPerPod())
+		ports := make([]networkingv1.NetworkPolicyPort, 0, len(servicePorts))
+		for _, servicePort := range servicePorts {
+			protocol := corev1.ProtocolTCP
+			port := intstr.FromInt(int(servicePort.Port))
+			ports = append(ports, networkingv1.NetworkPolicyPort{
+				Protocol: &protocol,
+				Port:     &port,
+			})
+		}
+
+		policy.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+		policy.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{Ports: ports}}
+	default:
+		return nil, fmt.Errorf("chaos action %s is not injected with a network policy", chaos.Spec.Action)
+	}
+
+	return policy, nil
+}
+
+// GetChaosDiskFillPod builds the Pod that fills up the data volume of the
+// provided FoundationDB Pod for a DiskFull experiment. The Pod is scheduled on
+// the same node so it can mount the volume. The operator removes the file
+// through the FoundationDB Pod before it deletes this Pod, the Pod only removes
+// the file itself as a fallback when it is terminated.
+func GetChaosDiskFillPod(chaos *fdbv1beta2.FoundationDBChaos, cluster *fdbv1beta2.FoundationDBCluster, pod *corev1.Pod, injection int) (*corev1.Pod, error) {
+	var claimName string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == "data" && volume.PersistentVolumeClaim != nil {
+			claimName = volume.PersistentVolumeClaim.ClaimName
+			break
+		}
+	}
+
+	if claimName == "" {
+		return nil, fmt.Errorf("pod %s has no persistent data volume", pod.Name)
+	}
+
+	var image string
+	for _, container := range pod.Spec.Containers {
+		if container.Name == fdbv1beta2.MainContainerName {
+			image = container.Image
+			break
+		}
+	}
+
+	if image == "" {
+		return nil, fmt.Errorf("pod %s has no %s container", pod.Name, fdbv1beta2.MainContainerName)
+	}
+
+	processGroupID := GetProcessGroupIDFromMeta(cluster, pod.ObjectMeta)
+	script := fmt.Sprintf(`trap 'rm -f %[1]s; exit 0' TERM INT
+fallocate -l "$(df -Pk /var/fdb/data | awk 'NR==2 {print $4}')K" %[1]s
+sleep infinity &
+wait`, chaosDiskFillPath)
+
+	metadata := GetChaosObjectMetadata(chaos, fmt.Sprintf("%s-%d-%s", chaos.Name, injection, processGroupID), injection)
+	metadata.Annotations = map[string]string{fdbv1beta2.ChaosTargetAnnotation: string(processGroupID)}
+
+	return &corev1.Pod{
+		ObjectMeta: metadata,
+		Spec: corev1.PodSpec{
+			NodeName:        pod.Spec.NodeName,
+			RestartPolicy:   corev1.RestartPolicyNever,
+			SecurityContext: pod.Spec.SecurityContext,
+			Tolerations:     pod.Spec.Tolerations,
+			Containers: []corev1.Container{
+				{
+					Name:    "disk-fill",
+					Image:   image,
+					Command: []string{"/bin/sh", "-c", script},
+					VolumeMounts: []corev1.VolumeMount{
+						{Name: "data", MountPath: "/var/fdb/data"},
+					},
+				},
+			},
+			Volumes: []corev1.Volume{
+				{
+					Name: "data",
+					VolumeSource: corev1.VolumeSource{
+						PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+					},
+				},
+			},
+			TerminationGracePeriodSeconds: pointer.Int64(30),
+		},
+	}, nil
+}
@@ -0,0 +1,164 @@
+/*
+ * chaos_helper_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("chaos_helper", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var chaos *fdbv1beta2.FoundationDBChaos
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		chaos = &fdbv1beta2.FoundationDBChaos{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "sample-chaos",
+				Namespace: cluster.Namespace,
+			},
+			Spec: fdbv1beta2.FoundationDBChaosSpec{
+				ClusterName: cluster.Name,
+			},
+		}
+	})
+
+	When("building the network policy", func() {
+		var policy *networkingv1.NetworkPolicy
+		var err error
+
+		JustBeforeEach(func() {
+			policy, err = GetChaosNetworkPolicy(chaos, cluster, []fdbv1beta2.ProcessGroupID{"storage-1", "storage-2"}, 3)
+		})
+
+		When("partitioning a zone", func() {
+			BeforeEach(func() {
+				chaos.Spec.Action = fdbv1beta2.ChaosActionPartitionZone
+			})
+
+			It("should deny all traffic for the process groups", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.ObjectMeta.Name).To(Equal("sample-chaos-3"))
+				Expect(policy.ObjectMeta.Labels).To(Equal(map[string]string{
+					fdbv1beta2.ChaosLabel:          "sample-chaos",
+					fdbv1beta2.ChaosInjectionLabel: "3",
+				}))
+				Expect(policy.Spec.PodSelector).To(Equal(metav1.LabelSelector{
+					MatchLabels: cluster.GetMatchLabels(),
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      fdbv1beta2.FDBProcessGroupIDLabel,
+							Operator: metav1.LabelSelectorOpIn,
+							Values:   []string{"storage-1", "storage-2"},
+						},
+					},
+				}))
+				Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+				Expect(policy.Spec.Ingress).To(BeEmpty())
+				Expect(policy.Spec.Egress).To(BeEmpty())
+			})
+		})
+
+		When("stalling the sidecars", func() {
+			BeforeEach(func() {
+				chaos.Spec.Action = fdbv1beta2.ChaosActionStallSidecars
+				cluster.Spec.StorageServersPerPod = 2
+			})
+
+			It("should only allow the traffic to the fdbserver processes", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(policy.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress))
+				Expect(policy.Spec.Ingress).To(HaveLen(1))
+
+				var ports []intstr.IntOrString
+				for _, port := range policy.Spec.Ingress[0].Ports {
+					Expect(*port.Protocol).To(Equal(corev1.ProtocolTCP))
+					ports = append(ports, *port.Port)
+				}
+				Expect(ports).To(ConsistOf(intstr.FromInt(4500), intstr.FromInt(4501), intstr.FromInt(4502), intstr.FromInt(4503)))
+			})
+		})
+
+		When("the action doesn't use a network policy", func() {
+			BeforeEach(func() {
+				chaos.Spec.Action = fdbv1beta2.ChaosActionDiskFull
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+
+	When("building the disk fill pod", func() {
+		var pod *corev1.Pod
+		var fillPod *corev1.Pod
+		var err error
+
+		BeforeEach(func() {
+			chaos.Spec.Action = fdbv1beta2.ChaosActionDiskFull
+			Expect(NormalizeClusterSpec(cluster, DeprecationOptions{})).NotTo(HaveOccurred())
+			pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			pod.Spec.NodeName = "node-1"
+		})
+
+		JustBeforeEach(func() {
+			fillPod, err = GetChaosDiskFillPod(chaos, cluster, pod, 2)
+		})
+
+		It("should mount the data volume on the same node", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fillPod.ObjectMeta.Name).To(Equal("sample-chaos-2-storage-1"))
+			Expect(fillPod.ObjectMeta.Labels).To(Equal(map[string]string{
+				fdbv1beta2.ChaosLabel:          "sample-chaos",
+				fdbv1beta2.ChaosInjectionLabel: "2",
+			}))
+			Expect(fillPod.ObjectMeta.Annotations).To(Equal(map[string]string{fdbv1beta2.ChaosTargetAnnotation: "storage-1"}))
+			Expect(fillPod.Spec.NodeName).To(Equal("node-1"))
+			Expect(fillPod.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyNever))
+			Expect(fillPod.Spec.Volumes).To(HaveLen(1))
+			Expect(fillPod.Spec.Volumes[0].PersistentVolumeClaim.ClaimName).To(Equal("operator-test-1-storage-1-data"))
+			Expect(fillPod.Spec.Containers).To(HaveLen(1))
+			Expect(fillPod.Spec.Containers[0].Image).To(Equal(pod.Spec.Containers[0].Image))
+		})
+
+		When("the pod has no persistent data volume", func() {
+			BeforeEach(func() {
+				for index, volume := range pod.Spec.Volumes {
+					if volume.Name == "data" {
+						pod.Spec.Volumes[index].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+					}
+				}
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+	})
+})
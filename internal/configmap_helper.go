@@ -44,7 +44,7 @@ func GetConfigMap(cluster *fdbv1beta2.FoundationDBCluster) (*corev1.ConfigMap, e
 	data["running-version"] = cluster.Status.RunningVersion
 
 	var caFile strings.Builder
-	for _, ca := range cluster.Spec.TrustedCAs {
+	for _, ca := range cluster.GetTrustedCAs() {
 		if caFile.Len() > 0 {
 			caFile.WriteString("\n")
 		}
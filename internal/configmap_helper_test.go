@@ -219,6 +219,19 @@ var _ = Describe("configmap_helper", func() {
 					Expect(configMap.Data["ca-file"]).To(Equal("-----BEGIN CERTIFICATE-----\nMIIFyDCCA7ACCQDqRnbTl1OkcTANBgkqhkiG9w0BAQsFADCBpTELMAkGA1UEBhMC\n---CERT2----"))
 				})
 			})
+
+			When("the trusted CAs are being changed", func() {
+				BeforeEach(func() {
+					cluster.Status.TLS.TrustedCAs = []string{
+						"---CERT2----",
+						"---CERT3----",
+					}
+				})
+
+				It("should use the CAs from the status", func() {
+					Expect(configMap.Data["ca-file"]).To(Equal("---CERT2----\n---CERT3----"))
+				})
+			})
 		})
 
 		Context("with an empty connection string", func() {
@@ -0,0 +1,154 @@
+/*
+ * coordinator_ips.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// UnmappedCoordinatorsError is returned by GetCoordinatorIPChanges if some
+// coordinators can't be mapped to a new IP address.
+type UnmappedCoordinatorsError struct {
+	// Reasons contains the reason for every coordinator that can't be mapped.
+	Reasons []string
+}
+
+// Error returns the error message.
+func (err *UnmappedCoordinatorsError) Error() string {
+	return fmt.Sprintf("could not map all coordinators to a new IP address: %s", strings.Join(err.Reasons, ", "))
+}
+
+// GetCoordinatorIPChanges returns the new addresses for all coordinators whose
+// process group got a new IP address. currentIPs contains the current IP
+// addresses of the process groups. A coordinator is only mapped to a new IP
+// address if its IP address is recorded in the status of exactly one process
+// group and this process group has a current IP address of the same family.
+// Coordinators that use DNS names or an IP address that is in use are not
+// changed. If any coordinator can't be mapped, the changes for all other
+// coordinators are returned together with an UnmappedCoordinatorsError.
+func GetCoordinatorIPChanges(cluster *fdbv1beta2.FoundationDBCluster, connectionString fdbv1beta2.ConnectionString, currentIPs map[fdbv1beta2.ProcessGroupID][]string) ([]fdbv1beta2.CoordinatorIPChange, error) {
+	inUse := make(map[string]fdbv1beta2.None)
+	for _, ips := range currentIPs {
+		for _, ip := range ips {
+			inUse[ip] = fdbv1beta2.None{}
+		}
+	}
+
+	var changes []fdbv1beta2.CoordinatorIPChange
+	var mappingErrors []string
+	for _, coordinator := range connectionString.Coordinators {
+		address, err := fdbv1beta2.ParseProcessAddress(coordinator)
+		if err != nil {
+			return nil, err
+		}
+
+		// DNS names don't change when a Pod gets a new IP address.
+		if address.StringAddress != "" {
+			continue
+		}
+
+		ip := address.IPAddress.String()
+		if _, ok := inUse[ip]; ok {
+			continue
+		}
+
+		processGroupID, err := getProcessGroupForAddress(cluster, ip)
+		if err != nil {
+			mappingErrors = append(mappingErrors, fmt.Sprintf("coordinator %s: %s", coordinator, err.Error()))
+			continue
+		}
+
+		family := 6
+		if address.IPAddress.To4() != nil {
+			family = 4
+		}
+
+		var newIP net.IP
+		for _, currentIP := range currentIPs[processGroupID] {
+			parsed := net.ParseIP(currentIP)
+			if parsed != nil && MatchesIPFamily(parsed, family) {
+				newIP = parsed
+				break
+			}
+		}
+
+		if newIP == nil {
+			mappingErrors = append(mappingErrors, fmt.Sprintf("coordinator %s: process group %s has no current IP address", coordinator, processGroupID))
+			continue
+		}
+
+		address.IPAddress = newIP
+		changes = append(changes, fdbv1beta2.CoordinatorIPChange{
+			ProcessGroupID:  processGroupID,
+			PreviousAddress: coordinator,
+			Address:         address.String(),
+		})
+	}
+
+	if len(mappingErrors) > 0 {
+		return changes, &UnmappedCoordinatorsError{Reasons: mappingErrors}
+	}
+
+	return changes, nil
+}
+
+// getProcessGroupForAddress returns the process group that has the IP address
+// recorded in its status.
+func getProcessGroupForAddress(cluster *fdbv1beta2.FoundationDBCluster, ip string) (fdbv1beta2.ProcessGroupID, error) {
+	var processGroupID fdbv1beta2.ProcessGroupID
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		for _, address := range processGroup.Addresses {
+			if address != ip {
+				continue
+			}
+
+			if processGroupID != "" && processGroupID != processGroup.ProcessGroupID {
+				return "", fmt.Errorf("IP address matches multiple process groups")
+			}
+			processGroupID = processGroup.ProcessGroupID
+		}
+	}
+
+	if processGroupID == "" {
+		return "", fmt.Errorf("could not find process group")
+	}
+
+	return processGroupID, nil
+}
+
+// ApplyCoordinatorIPChanges replaces the previous addresses of the changes
+// with the new addresses in the connection string.
+func ApplyCoordinatorIPChanges(connectionString *fdbv1beta2.ConnectionString, changes []fdbv1beta2.CoordinatorIPChange) {
+	newAddresses := make(map[string]string, len(changes))
+	for _, change := range changes {
+		newAddresses[change.PreviousAddress] = change.Address
+	}
+
+	for index, coordinator := range connectionString.Coordinators {
+		if address, ok := newAddresses[coordinator]; ok {
+			connectionString.Coordinators[index] = address
+		}
+	}
+}
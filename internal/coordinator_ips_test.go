@@ -0,0 +1,158 @@
+/*
+ * coordinator_ips_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("coordinator_ips", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var connectionString fdbv1beta2.ConnectionString
+	var currentIPs map[fdbv1beta2.ProcessGroupID][]string
+	var changes []fdbv1beta2.CoordinatorIPChange
+	var err error
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		cluster.Status.ProcessGroups = []*fdbv1beta2.ProcessGroupStatus{
+			{ProcessGroupID: "storage-1", Addresses: []string{"127.0.0.1"}},
+			{ProcessGroupID: "storage-2", Addresses: []string{"127.0.0.2"}},
+			{ProcessGroupID: "storage-3", Addresses: []string{"127.0.0.3"}},
+		}
+
+		connectionString, err = fdbv1beta2.ParseConnectionString("test:asdfkjh@127.0.0.1:4501,127.0.0.2:4501,127.0.0.3:4501")
+		Expect(err).NotTo(HaveOccurred())
+
+		currentIPs = map[fdbv1beta2.ProcessGroupID][]string{
+			"storage-1": {"127.0.0.1"},
+			"storage-2": {"127.0.0.2"},
+			"storage-3": {"127.0.0.3"},
+		}
+	})
+
+	JustBeforeEach(func() {
+		changes, err = GetCoordinatorIPChanges(cluster, connectionString, currentIPs)
+	})
+
+	When("the IP addresses didn't change", func() {
+		It("should not return any changes", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	When("process groups got new IP addresses", func() {
+		BeforeEach(func() {
+			currentIPs["storage-1"] = []string{"127.0.1.1"}
+			currentIPs["storage-2"] = []string{"fd00::2", "127.0.1.2"}
+		})
+
+		It("should map the coordinators to the new IP addresses", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				fdbv1beta2.CoordinatorIPChange{ProcessGroupID: "storage-1", PreviousAddress: "127.0.0.1:4501", Address: "127.0.1.1:4501"},
+				fdbv1beta2.CoordinatorIPChange{ProcessGroupID: "storage-2", PreviousAddress: "127.0.0.2:4501", Address: "127.0.1.2:4501"},
+			))
+		})
+
+		It("should update the connection string", func() {
+			ApplyCoordinatorIPChanges(&connectionString, changes)
+			Expect(connectionString.String()).To(Equal("test:asdfkjh@127.0.1.1:4501,127.0.1.2:4501,127.0.0.3:4501"))
+		})
+	})
+
+	When("the connection string contains DNS names", func() {
+		BeforeEach(func() {
+			connectionString, err = fdbv1beta2.ParseConnectionString("test:asdfkjh@storage-1.test.default.svc.cluster.local:4501,127.0.0.2:4501")
+			Expect(err).NotTo(HaveOccurred())
+			currentIPs["storage-1"] = []string{"127.0.1.1"}
+			currentIPs["storage-2"] = []string{"127.0.1.2"}
+		})
+
+		It("should only map the IP addresses", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				fdbv1beta2.CoordinatorIPChange{ProcessGroupID: "storage-2", PreviousAddress: "127.0.0.2:4501", Address: "127.0.1.2:4501"},
+			))
+		})
+	})
+
+	When("a coordinator uses an IPv6 address", func() {
+		BeforeEach(func() {
+			cluster.Status.ProcessGroups[0].Addresses = []string{"fd00::1"}
+			connectionString, err = fdbv1beta2.ParseConnectionString("test:asdfkjh@[fd00::1]:4501,127.0.0.2:4501,127.0.0.3:4501")
+			Expect(err).NotTo(HaveOccurred())
+			currentIPs["storage-1"] = []string{"127.0.1.1", "fd00::11"}
+		})
+
+		It("should use the IP address of the same family", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(changes).To(ConsistOf(
+				fdbv1beta2.CoordinatorIPChange{ProcessGroupID: "storage-1", PreviousAddress: "[fd00::1]:4501", Address: "[fd00::11]:4501"},
+			))
+		})
+	})
+
+	When("a coordinator has no process group", func() {
+		BeforeEach(func() {
+			cluster.Status.ProcessGroups[0].Addresses = nil
+			currentIPs["storage-1"] = []string{"127.0.1.1"}
+			currentIPs["storage-2"] = []string{"127.0.1.2"}
+		})
+
+		It("should return the other changes and an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err).To(BeAssignableToTypeOf(&UnmappedCoordinatorsError{}))
+			Expect(err.Error()).To(ContainSubstring("coordinator 127.0.0.1:4501: could not find process group"))
+			Expect(changes).To(ConsistOf(
+				fdbv1beta2.CoordinatorIPChange{ProcessGroupID: "storage-2", PreviousAddress: "127.0.0.2:4501", Address: "127.0.1.2:4501"},
+			))
+		})
+	})
+
+	When("a coordinator matches multiple process groups", func() {
+		BeforeEach(func() {
+			cluster.Status.ProcessGroups = append(cluster.Status.ProcessGroups, &fdbv1beta2.ProcessGroupStatus{ProcessGroupID: "storage-4", Addresses: []string{"127.0.0.1"}})
+			currentIPs["storage-1"] = []string{"127.0.1.1"}
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("coordinator 127.0.0.1:4501: IP address matches multiple process groups"))
+			Expect(changes).To(BeEmpty())
+		})
+	})
+
+	When("the process group of a coordinator has no current IP address", func() {
+		BeforeEach(func() {
+			delete(currentIPs, "storage-1")
+		})
+
+		It("should return an error", func() {
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("coordinator 127.0.0.1:4501: process group storage-1 has no current IP address"))
+			Expect(changes).To(BeEmpty())
+		})
+	})
+})
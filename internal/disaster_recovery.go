@@ -0,0 +1,204 @@
+/*
+ * disaster_recovery.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetDisasterRecoveryDeploymentName returns the name of the deployment for
+// the DR agents.
+func GetDisasterRecoveryDeploymentName(dr *fdbv1beta2.FoundationDBDisasterRecovery) string {
+	return fmt.Sprintf("%s-dr-agents", dr.ObjectMeta.Name)
+}
+
+// GetDisasterRecoveryDeployment builds a deployment for the DR agents.
+//
+// The DR agents get the cluster file of the destination cluster at
+// /var/dynamic-conf/fdb.cluster and the cluster file of the source cluster at
+// /var/dynamic-conf/source.cluster.
+func GetDisasterRecoveryDeployment(dr *fdbv1beta2.FoundationDBDisasterRecovery) (*appsv1.Deployment, error) {
+	agentCount := int32(dr.GetDesiredAgentCount())
+	if agentCount == 0 {
+		return nil, nil
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       dr.ObjectMeta.Namespace,
+			Name:            GetDisasterRecoveryDeploymentName(dr),
+			Annotations:     map[string]string{},
+			Labels:          map[string]string{},
+			OwnerReferences: BuildOwnerReference(dr.TypeMeta, dr.ObjectMeta),
+		},
+	}
+	deployment.Spec.Replicas = &agentCount
+
+	if dr.Spec.AgentDeploymentMetadata != nil {
+		for key, value := range dr.Spec.AgentDeploymentMetadata.Labels {
+			deployment.ObjectMeta.Labels[key] = value
+		}
+		for key, value := range dr.Spec.AgentDeploymentMetadata.Annotations {
+			deployment.ObjectMeta.Annotations[key] = value
+		}
+	}
+	deployment.ObjectMeta.Labels[fdbv1beta2.DisasterRecoveryDeploymentLabel] = string(dr.ObjectMeta.UID)
+
+	var podTemplate *corev1.PodTemplateSpec
+	if dr.Spec.PodTemplateSpec != nil {
+		podTemplate = dr.Spec.PodTemplateSpec.DeepCopy()
+	} else {
+		podTemplate = &corev1.PodTemplateSpec{}
+	}
+
+	var mainContainer *corev1.Container
+	var initContainer *corev1.Container
+
+	for index, container := range podTemplate.Spec.Containers {
+		if container.Name == fdbv1beta2.MainContainerName {
+			mainContainer = &podTemplate.Spec.Containers[index]
+		}
+	}
+
+	for index, container := range podTemplate.Spec.InitContainers {
+		if container.Name == fdbv1beta2.InitContainerName {
+			initContainer = &podTemplate.Spec.InitContainers[index]
+		}
+	}
+
+	if mainContainer == nil {
+		containers := []corev1.Container{
+			{
+				Name: fdbv1beta2.MainContainerName,
+			},
+		}
+		containers = append(containers, podTemplate.Spec.Containers...)
+		mainContainer = &containers[0]
+		podTemplate.Spec.Containers = containers
+	}
+
+	imageConfigs := dr.Spec.MainContainer.ImageConfigs
+	if len(imageConfigs) == 0 {
+		imageConfigs = []fdbv1beta2.ImageConfig{
+			{BaseImage: "foundationdb/foundationdb"},
+		}
+	}
+
+	image, err := GetImage(mainContainer.Image, imageConfigs, dr.Spec.Version, false)
+	if err != nil {
+		return nil, err
+	}
+	mainContainer.Image = image
+	mainContainer.Command = []string{"dr_agent"}
+	args := []string{
+		"-d", "/var/dynamic-conf/fdb.cluster",
+		"-s", "/var/dynamic-conf/source.cluster",
+		"--log", "--logdir", "/var/log/fdb-trace-logs",
+	}
+
+	if len(dr.Spec.CustomParameters) > 0 {
+		err := dr.Spec.CustomParameters.ValidateCustomParameters()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, customParameter := range dr.Spec.CustomParameters {
+			args = append(args, fmt.Sprintf("--%s", customParameter))
+		}
+	}
+
+	mainContainer.Args = args
+	mainContainer.VolumeMounts = append(mainContainer.VolumeMounts,
+		corev1.VolumeMount{Name: "logs", MountPath: "/var/log/fdb-trace-logs"},
+		corev1.VolumeMount{Name: "dynamic-conf", MountPath: "/var/dynamic-conf"},
+	)
+
+	if mainContainer.Resources.Requests == nil {
+		mainContainer.Resources.Requests = corev1.ResourceList{
+			"cpu":    resource.MustParse("1"),
+			"memory": resource.MustParse("1Gi"),
+		}
+	}
+
+	if mainContainer.Resources.Limits == nil {
+		mainContainer.Resources.Limits = mainContainer.Resources.Requests
+	}
+
+	if initContainer == nil {
+		podTemplate.Spec.InitContainers = append(podTemplate.Spec.InitContainers, corev1.Container{Name: fdbv1beta2.InitContainerName})
+		initContainer = &podTemplate.Spec.InitContainers[0]
+	}
+
+	err = configureSidecarContainer(initContainer, true, "", "", dr.Spec.Version, nil, dr.Spec.SidecarContainer.ImageConfigs, false)
+	if err != nil {
+		return nil, err
+	}
+	initContainer.Args = append([]string{"--copy-file", "source.cluster", "--require-not-empty", "source.cluster"}, initContainer.Args...)
+
+	if podTemplate.ObjectMeta.Labels == nil {
+		podTemplate.ObjectMeta.Labels = make(map[string]string, 1)
+	}
+	podTemplate.ObjectMeta.Labels["foundationdb.org/deployment-name"] = deployment.ObjectMeta.Name
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{
+		"foundationdb.org/deployment-name": deployment.ObjectMeta.Name,
+	}}
+
+	podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes,
+		corev1.Volume{Name: "logs", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		corev1.Volume{Name: "dynamic-conf", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+		corev1.Volume{
+			Name: "config-map",
+			VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{
+					{ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: fmt.Sprintf("%s-config", dr.Spec.DestinationClusterName)},
+						Items: []corev1.KeyToPath{
+							{Key: ClusterFileKey, Path: "fdb.cluster"},
+						},
+					}},
+					{ConfigMap: &corev1.ConfigMapProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: fmt.Sprintf("%s-config", dr.Spec.SourceClusterName)},
+						Items: []corev1.KeyToPath{
+							{Key: ClusterFileKey, Path: "source.cluster"},
+						},
+					}},
+				},
+			}},
+		},
+	)
+
+	deployment.Spec.Template = *podTemplate
+
+	specHash, err := GetJSONHash(deployment.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	deployment.ObjectMeta.Annotations[fdbv1beta2.LastSpecKey] = specHash
+
+	return deployment, nil
+}
@@ -0,0 +1,146 @@
+/*
+ * disaster_recovery_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("disaster_recovery", func() {
+	var dr *fdbv1beta2.FoundationDBDisasterRecovery
+
+	BeforeEach(func() {
+		source := CreateDefaultCluster()
+		destination := CreateDefaultCluster()
+		destination.Name = "operator-test-2"
+		dr = CreateDefaultDisasterRecovery(source, destination)
+		dr.ObjectMeta.UID = "12345"
+	})
+
+	Describe("GetDisasterRecoveryDeployment", func() {
+		var deployment *appsv1.Deployment
+		var err error
+
+		JustBeforeEach(func() {
+			deployment, err = GetDisasterRecoveryDeployment(dr)
+		})
+
+		It("should set the metadata", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deployment.Name).To(Equal("operator-test-1-dr-agents"))
+			Expect(deployment.Namespace).To(Equal("my-ns"))
+			Expect(deployment.Labels).To(Equal(map[string]string{
+				fdbv1beta2.DisasterRecoveryDeploymentLabel: "12345",
+			}))
+			Expect(deployment.Annotations).To(HaveKey(fdbv1beta2.LastSpecKey))
+			Expect(*deployment.Spec.Replicas).To(BeNumerically("==", 3))
+		})
+
+		It("should run the DR agent against both clusters", func() {
+			Expect(err).NotTo(HaveOccurred())
+			container := deployment.Spec.Template.Spec.Containers[0]
+			Expect(container.Name).To(Equal(fdbv1beta2.MainContainerName))
+			Expect(container.Image).To(Equal("foundationdb/foundationdb:" + dr.Spec.Version))
+			Expect(container.Command).To(Equal([]string{"dr_agent"}))
+			Expect(container.Args).To(Equal([]string{
+				"-d", "/var/dynamic-conf/fdb.cluster",
+				"-s", "/var/dynamic-conf/source.cluster",
+				"--log", "--logdir", "/var/log/fdb-trace-logs",
+			}))
+		})
+
+		It("should copy both cluster files", func() {
+			Expect(err).NotTo(HaveOccurred())
+			initContainer := deployment.Spec.Template.Spec.InitContainers[0]
+			Expect(initContainer.Name).To(Equal(fdbv1beta2.InitContainerName))
+			Expect(initContainer.Args).To(Equal([]string{
+				"--copy-file", "source.cluster",
+				"--require-not-empty", "source.cluster",
+				"--copy-file", "fdb.cluster",
+				"--require-not-empty", "fdb.cluster",
+				"--init-mode",
+			}))
+
+			Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+				Name: "config-map",
+				VolumeSource: corev1.VolumeSource{Projected: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "operator-test-2-config"},
+							Items: []corev1.KeyToPath{
+								{Key: ClusterFileKey, Path: "fdb.cluster"},
+							},
+						}},
+						{ConfigMap: &corev1.ConfigMapProjection{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "operator-test-1-config"},
+							Items: []corev1.KeyToPath{
+								{Key: ClusterFileKey, Path: "source.cluster"},
+							},
+						}},
+					},
+				}},
+			}))
+		})
+
+		When("the agent count is zero", func() {
+			BeforeEach(func() {
+				dr.Spec.AgentCount = pointer.Int(0)
+			})
+
+			It("should not return a deployment", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).To(BeNil())
+			})
+		})
+
+		When("custom parameters are defined", func() {
+			BeforeEach(func() {
+				dr.Spec.CustomParameters = fdbv1beta2.FoundationDBCustomParameters{"knob_http_verbose_level=3"}
+			})
+
+			It("should pass the custom parameters to the DR agent", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment.Spec.Template.Spec.Containers[0].Args).To(ContainElement("--knob_http_verbose_level=3"))
+			})
+		})
+
+		When("custom deployment metadata is defined", func() {
+			BeforeEach(func() {
+				dr.Spec.AgentDeploymentMetadata = &metav1.ObjectMeta{
+					Labels:      map[string]string{"fdb-test": "test-value"},
+					Annotations: map[string]string{"fdb-annotation": "test-value"},
+				}
+			})
+
+			It("should add the metadata to the deployment", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment.Labels).To(HaveKeyWithValue("fdb-test", "test-value"))
+				Expect(deployment.Annotations).To(HaveKeyWithValue("fdb-annotation", "test-value"))
+			})
+		})
+	})
+})
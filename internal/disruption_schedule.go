@@ -0,0 +1,249 @@
+/*
+ * disruption_schedule.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxNextAllowedTimeIterations limits the search for the next time that
+// disruptive actions are allowed, e.g. if the freeze windows cover all
+// maintenance windows.
+const maxNextAllowedTimeIterations = 100
+
+// DisruptionState describes whether the operator is allowed to perform
+// disruptive actions on a cluster.
+type DisruptionState struct {
+	// Allowed is true if disruptive actions are allowed.
+	Allowed bool
+
+	// Reason describes why disruptive actions are not allowed.
+	Reason string
+
+	// NextAllowedTime defines when disruptive actions are allowed again. This
+	// is nil if disruptive actions are allowed or if the next allowed time is
+	// unknown.
+	NextAllowedTime *time.Time
+}
+
+// maintenanceWindowSchedule represents a parsed maintenance window.
+type maintenanceWindowSchedule struct {
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// contains returns true if the provided time is inside a window of the
+// schedule. A window contains the time if the window started in the
+// duration before the provided time.
+func (window maintenanceWindowSchedule) contains(now time.Time) bool {
+	start := window.schedule.Next(now.Add(-window.duration))
+	return !start.IsZero() && !start.After(now)
+}
+
+// GetDisruptionState returns whether disruptive actions are allowed at the
+// provided time based on the disruption schedule of the cluster and the
+// freeze windows of the operator.
+func GetDisruptionState(cluster *fdbv1beta2.FoundationDBCluster, operatorFreezeWindows []fdbv1beta2.FreezeWindow, now time.Time) (DisruptionState, error) {
+	schedules, err := parseMaintenanceWindows(cluster.Spec.AutomationOptions.DisruptionSchedule.MaintenanceWindows)
+	if err != nil {
+		return DisruptionState{}, err
+	}
+
+	freezeWindows := make([]fdbv1beta2.FreezeWindow, 0, len(cluster.Spec.AutomationOptions.DisruptionSchedule.FreezeWindows)+len(operatorFreezeWindows))
+	freezeWindows = append(freezeWindows, cluster.Spec.AutomationOptions.DisruptionSchedule.FreezeWindows...)
+	freezeWindows = append(freezeWindows, operatorFreezeWindows...)
+
+	activeFreeze := getActiveFreezeWindow(freezeWindows, now)
+	if activeFreeze != nil {
+		reason := fmt.Sprintf("Disruptive actions are frozen until %s", activeFreeze.End.UTC().Format(time.RFC3339))
+		if activeFreeze.Reason != "" {
+			reason = fmt.Sprintf("%s: %s", reason, activeFreeze.Reason)
+		}
+
+		return DisruptionState{
+			Reason:          reason,
+			NextAllowedTime: getNextAllowedTime(schedules, freezeWindows, activeFreeze.End.Time),
+		}, nil
+	}
+
+	if len(schedules) == 0 || isInMaintenanceWindow(schedules, now) {
+		return DisruptionState{Allowed: true}, nil
+	}
+
+	return DisruptionState{
+		Reason:          "Disruptive actions are only allowed inside the maintenance windows",
+		NextAllowedTime: getNextAllowedTime(schedules, freezeWindows, now),
+	}, nil
+}
+
+// GetPendingDisruptionsStatus returns the status for the provided
+// sub-reconcilers that have pending disruptive work, which was delayed
+// because of the disruption state. If no work was delayed this returns nil.
+func GetPendingDisruptionsStatus(state DisruptionState, actions []string) *fdbv1beta2.PendingDisruptionsStatus {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	status := &fdbv1beta2.PendingDisruptionsStatus{
+		Actions: actions,
+		Reason:  state.Reason,
+	}
+
+	if state.NextAllowedTime != nil {
+		nextAllowedTime := metav1.NewTime(*state.NextAllowedTime)
+		status.NextAllowedTime = &nextAllowedTime
+	}
+
+	return status
+}
+
+// ParseFreezeWindows parses a comma separated list of freeze windows in the
+// format start/end, where start and end are timestamps in the RFC 3339
+// format, e.g. 2023-12-22T00:00:00Z/2024-01-02T00:00:00Z.
+func ParseFreezeWindows(value string) ([]fdbv1beta2.FreezeWindow, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	windows := make([]fdbv1beta2.FreezeWindow, 0, strings.Count(value, ",")+1)
+	for _, window := range strings.Split(value, ",") {
+		start, end, found := strings.Cut(strings.TrimSpace(window), "/")
+		if !found {
+			return nil, fmt.Errorf("invalid freeze window %s, expected the format start/end", window)
+		}
+
+		startTime, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start of freeze window %s: %w", window, err)
+		}
+
+		endTime, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid end of freeze window %s: %w", window, err)
+		}
+
+		if !endTime.After(startTime) {
+			return nil, fmt.Errorf("invalid freeze window %s, the end must be after the start", window)
+		}
+
+		windows = append(windows, fdbv1beta2.FreezeWindow{
+			Start:  metav1.NewTime(startTime),
+			End:    metav1.NewTime(endTime),
+			Reason: "operator freeze window",
+		})
+	}
+
+	return windows, nil
+}
+
+// parseMaintenanceWindows parses the schedules of the maintenance windows.
+func parseMaintenanceWindows(windows []fdbv1beta2.MaintenanceWindow) ([]maintenanceWindowSchedule, error) {
+	schedules := make([]maintenanceWindowSchedule, 0, len(windows))
+	for _, window := range windows {
+		schedule, err := cron.ParseStandard(window.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %s of maintenance window: %w", window.Schedule, err)
+		}
+
+		if window.Duration.Duration <= 0 {
+			return nil, fmt.Errorf("invalid duration %s of maintenance window with schedule %s, the duration must be positive", window.Duration.Duration, window.Schedule)
+		}
+
+		schedules = append(schedules, maintenanceWindowSchedule{
+			schedule: schedule,
+			duration: window.Duration.Duration,
+		})
+	}
+
+	return schedules, nil
+}
+
+// getActiveFreezeWindow returns the active freeze window that ends last or
+// nil if no freeze window is active.
+func getActiveFreezeWindow(freezeWindows []fdbv1beta2.FreezeWindow, now time.Time) *fdbv1beta2.FreezeWindow {
+	var activeFreeze *fdbv1beta2.FreezeWindow
+	for idx, window := range freezeWindows {
+		if !window.IsActive(now) {
+			continue
+		}
+
+		if activeFreeze == nil || window.End.After(activeFreeze.End.Time) {
+			activeFreeze = &freezeWindows[idx]
+		}
+	}
+
+	return activeFreeze
+}
+
+// isInMaintenanceWindow returns true if the provided time is inside any of the
+// maintenance windows.
+func isInMaintenanceWindow(schedules []maintenanceWindowSchedule, now time.Time) bool {
+	for _, schedule := range schedules {
+		if schedule.contains(now) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getNextAllowedTime returns the first time at or after from that is inside a
+// maintenance window, if any are defined, and outside of all freeze windows.
+// If no such time can be found this returns nil.
+func getNextAllowedTime(schedules []maintenanceWindowSchedule, freezeWindows []fdbv1beta2.FreezeWindow, from time.Time) *time.Time {
+	candidate := from
+	for i := 0; i < maxNextAllowedTimeIterations; i++ {
+		if len(schedules) > 0 && !isInMaintenanceWindow(schedules, candidate) {
+			var nextStart time.Time
+			for _, schedule := range schedules {
+				start := schedule.schedule.Next(candidate)
+				if start.IsZero() {
+					continue
+				}
+
+				if nextStart.IsZero() || start.Before(nextStart) {
+					nextStart = start
+				}
+			}
+
+			if nextStart.IsZero() {
+				return nil
+			}
+
+			candidate = nextStart
+		}
+
+		activeFreeze := getActiveFreezeWindow(freezeWindows, candidate)
+		if activeFreeze == nil {
+			return &candidate
+		}
+
+		candidate = activeFreeze.End.Time
+	}
+
+	return nil
+}
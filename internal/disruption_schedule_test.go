@@ -0,0 +1,222 @@
+/*
+ * disruption_schedule_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("disruption_schedule", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var operatorFreezeWindows []fdbv1beta2.FreezeWindow
+	var now time.Time
+	var state DisruptionState
+	var err error
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		operatorFreezeWindows = nil
+		// Monday, 4th of December 2023 at 12:00 UTC.
+		now = time.Date(2023, 12, 4, 12, 0, 0, 0, time.UTC)
+	})
+
+	JustBeforeEach(func() {
+		state, err = GetDisruptionState(cluster, operatorFreezeWindows, now)
+	})
+
+	When("no disruption schedule is defined", func() {
+		It("should allow disruptions", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.Allowed).To(BeTrue())
+		})
+	})
+
+	When("a maintenance window is defined", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.DisruptionSchedule.MaintenanceWindows = []fdbv1beta2.MaintenanceWindow{
+				{
+					Schedule: "0 11 * * 1-5",
+					Duration: metav1.Duration{Duration: 2 * time.Hour},
+				},
+			}
+		})
+
+		When("the time is inside the window", func() {
+			It("should allow disruptions", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Allowed).To(BeTrue())
+			})
+		})
+
+		When("the time is after the window", func() {
+			BeforeEach(func() {
+				now = now.Add(1 * time.Hour)
+			})
+
+			It("should not allow disruptions until the next window", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Allowed).To(BeFalse())
+				Expect(state.Reason).To(Equal("Disruptive actions are only allowed inside the maintenance windows"))
+				Expect(state.NextAllowedTime).NotTo(BeNil())
+				Expect(*state.NextAllowedTime).To(Equal(time.Date(2023, 12, 5, 11, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("the next window is after the weekend", func() {
+			BeforeEach(func() {
+				// Friday, 8th of December 2023 at 14:00 UTC.
+				now = time.Date(2023, 12, 8, 14, 0, 0, 0, time.UTC)
+			})
+
+			It("should return the window on Monday", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Allowed).To(BeFalse())
+				Expect(state.NextAllowedTime).NotTo(BeNil())
+				Expect(*state.NextAllowedTime).To(Equal(time.Date(2023, 12, 11, 11, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("a time zone is defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DisruptionSchedule.MaintenanceWindows[0].Schedule = "CRON_TZ=America/New_York 0 11 * * 1-5"
+			})
+
+			It("should evaluate the window in the time zone", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Allowed).To(BeFalse())
+				Expect(state.NextAllowedTime).NotTo(BeNil())
+				Expect(*state.NextAllowedTime).To(BeTemporally("==", time.Date(2023, 12, 4, 16, 0, 0, 0, time.UTC)))
+			})
+		})
+
+		When("the schedule is invalid", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DisruptionSchedule.MaintenanceWindows[0].Schedule = "every day"
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("the duration is missing", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.DisruptionSchedule.MaintenanceWindows[0].Duration = metav1.Duration{}
+			})
+
+			It("should return an error", func() {
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		When("a freeze window covers the next maintenance window", func() {
+			BeforeEach(func() {
+				now = now.Add(1 * time.Hour)
+				cluster.Spec.AutomationOptions.DisruptionSchedule.FreezeWindows = []fdbv1beta2.FreezeWindow{
+					{
+						Start: metav1.NewTime(time.Date(2023, 12, 5, 0, 0, 0, 0, time.UTC)),
+						End:   metav1.NewTime(time.Date(2023, 12, 6, 12, 0, 0, 0, time.UTC)),
+					},
+				}
+			})
+
+			It("should return the remaining time of the first window after the freeze", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Allowed).To(BeFalse())
+				Expect(state.NextAllowedTime).NotTo(BeNil())
+				Expect(*state.NextAllowedTime).To(Equal(time.Date(2023, 12, 6, 12, 0, 0, 0, time.UTC)))
+			})
+		})
+	})
+
+	When("an operator freeze window is active", func() {
+		BeforeEach(func() {
+			operatorFreezeWindows = []fdbv1beta2.FreezeWindow{
+				{
+					Start:  metav1.NewTime(now.Add(-1 * time.Hour)),
+					End:    metav1.NewTime(now.Add(24 * time.Hour)),
+					Reason: "holidays",
+				},
+			}
+		})
+
+		It("should not allow disruptions until the end of the freeze", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state.Allowed).To(BeFalse())
+			Expect(state.Reason).To(Equal("Disruptive actions are frozen until 2023-12-05T12:00:00Z: holidays"))
+			Expect(state.NextAllowedTime).NotTo(BeNil())
+			Expect(*state.NextAllowedTime).To(BeTemporally("==", now.Add(24*time.Hour)))
+		})
+
+		When("the freeze window has ended", func() {
+			BeforeEach(func() {
+				now = now.Add(48 * time.Hour)
+			})
+
+			It("should allow disruptions", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(state.Allowed).To(BeTrue())
+			})
+		})
+	})
+
+	DescribeTable("parsing the freeze windows",
+		func(value string, expected []fdbv1beta2.FreezeWindow, expectedErr bool) {
+			windows, err := ParseFreezeWindows(value)
+			if expectedErr {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(windows).To(HaveLen(len(expected)))
+			for idx, window := range windows {
+				Expect(window.Start.Time).To(BeTemporally("==", expected[idx].Start.Time))
+				Expect(window.End.Time).To(BeTemporally("==", expected[idx].End.Time))
+			}
+		},
+		Entry("empty value", "", nil, false),
+		Entry("single window", "2023-12-22T00:00:00Z/2024-01-02T00:00:00Z", []fdbv1beta2.FreezeWindow{
+			{
+				Start: metav1.NewTime(time.Date(2023, 12, 22, 0, 0, 0, 0, time.UTC)),
+				End:   metav1.NewTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+			},
+		}, false),
+		Entry("multiple windows", "2023-11-24T00:00:00Z/2023-11-27T00:00:00Z, 2023-12-22T00:00:00Z/2024-01-02T00:00:00Z", []fdbv1beta2.FreezeWindow{
+			{
+				Start: metav1.NewTime(time.Date(2023, 11, 24, 0, 0, 0, 0, time.UTC)),
+				End:   metav1.NewTime(time.Date(2023, 11, 27, 0, 0, 0, 0, time.UTC)),
+			},
+			{
+				Start: metav1.NewTime(time.Date(2023, 12, 22, 0, 0, 0, 0, time.UTC)),
+				End:   metav1.NewTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)),
+			},
+		}, false),
+		Entry("missing end", "2023-12-22T00:00:00Z", nil, true),
+		Entry("invalid timestamp", "2023-12-22/2024-01-02", nil, true),
+		Entry("end before start", "2024-01-02T00:00:00Z/2023-12-22T00:00:00Z", nil, true),
+	)
+})
@@ -79,3 +79,19 @@ func GetMinimumUptimeAndAddressMap(cluster *fdbv1beta2.FoundationDBCluster, stat
 
 	return minimumUptime, addressMap, nil
 }
+
+// HasLowDiskSpace returns true if any role of the process reports less available disk space for the key-value store
+// than the threshold percentage of the total disk space.
+func HasLowDiskSpace(process fdbv1beta2.FoundationDBStatusProcessInfo, thresholdPercent int) bool {
+	for _, role := range process.Roles {
+		if role.KVStoreTotalBytes <= 0 {
+			continue
+		}
+
+		if role.KVStoreAvailableBytes*100 < role.KVStoreTotalBytes*int64(thresholdPercent) {
+			return true
+		}
+	}
+
+	return false
+}
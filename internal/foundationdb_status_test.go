@@ -214,4 +214,33 @@ var _ = Describe("Internal FoundationDBStatus", func() {
 				},
 			}),
 	)
+
+	DescribeTable("checking if a process has low disk space", func(roles []fdbv1beta2.FoundationDBStatusProcessRoleInfo, thresholdPercent int, expected bool) {
+		Expect(HasLowDiskSpace(fdbv1beta2.FoundationDBStatusProcessInfo{Roles: roles}, thresholdPercent)).To(Equal(expected))
+	},
+		Entry("process without roles",
+			nil,
+			10,
+			false),
+		Entry("role without disk information",
+			[]fdbv1beta2.FoundationDBStatusProcessRoleInfo{{Role: "storage"}},
+			10,
+			false),
+		Entry("storage role with enough disk space",
+			[]fdbv1beta2.FoundationDBStatusProcessRoleInfo{{Role: "storage", KVStoreAvailableBytes: 50, KVStoreTotalBytes: 100}},
+			10,
+			false),
+		Entry("storage role with low disk space",
+			[]fdbv1beta2.FoundationDBStatusProcessRoleInfo{{Role: "storage", KVStoreAvailableBytes: 5, KVStoreTotalBytes: 100}},
+			10,
+			true),
+		Entry("storage role exactly at the threshold",
+			[]fdbv1beta2.FoundationDBStatusProcessRoleInfo{{Role: "storage", KVStoreAvailableBytes: 10, KVStoreTotalBytes: 100}},
+			10,
+			false),
+		Entry("log role with low disk space next to a coordinator",
+			[]fdbv1beta2.FoundationDBStatusProcessRoleInfo{{Role: "coordinator"}, {Role: "log", KVStoreAvailableBytes: 5, KVStoreTotalBytes: 100}},
+			10,
+			true),
+	)
 })
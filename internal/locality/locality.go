@@ -245,6 +245,32 @@ func GetHardLimits(cluster *fdbv1beta2.FoundationDBCluster) map[string]int {
 	return map[string]int{fdbv1beta2.FDBLocalityZoneIDKey: 1, fdbv1beta2.FDBLocalityDCIDKey: maxCoordinatorsPerDC}
 }
 
+// GetCoordinatorStatus returns the addresses of the current coordinators,
+// which can be passed down to CheckCoordinatorValidity.
+//
+// If the connection string uses DNS names, the coordinators are taken from the
+// connection string. The client status reports the IP addresses that the DNS
+// names were resolved to, which can be outdated once a Pod got a new IP
+// address.
+func GetCoordinatorStatus(status *fdbv1beta2.FoundationDBStatus) map[string]bool {
+	connectionString, err := fdbv1beta2.ParseConnectionString(status.Cluster.ConnectionString)
+	if err == nil && connectionString.HasDNSCoordinators() {
+		coordinatorStatus := make(map[string]bool, len(connectionString.Coordinators))
+		for _, coordinator := range connectionString.Coordinators {
+			coordinatorStatus[coordinator] = false
+		}
+
+		return coordinatorStatus
+	}
+
+	coordinatorStatus := make(map[string]bool, len(status.Client.Coordinators.Coordinators))
+	for _, coordinator := range status.Client.Coordinators.Coordinators {
+		coordinatorStatus[coordinator.Address.String()] = false
+	}
+
+	return coordinatorStatus
+}
+
 // CheckCoordinatorValidity determines if the cluster's current coordinators
 // meet the fault tolerance requirements.
 //
@@ -1003,6 +1003,86 @@ var _ = Describe("Change coordinators", func() {
 					Expect(err).NotTo(HaveOccurred())
 				})
 			})
+
+			When("the coordinators have DNS names and the pods got new IP addresses", func() {
+				BeforeEach(func() {
+					connectionString := fdbv1beta2.ConnectionString{
+						DatabaseName: "test",
+						GenerationID: "test",
+					}
+					status.Client.Coordinators.Coordinators = nil
+
+					for _, process := range status.Cluster.Processes {
+						process.Locality[fdbv1beta2.FDBLocalityDNSNameKey] = process.Locality[fdbv1beta2.FDBLocalityZoneIDKey]
+						connectionString.Coordinators = append(connectionString.Coordinators, fdbv1beta2.ProcessAddress{
+							StringAddress: process.Locality[fdbv1beta2.FDBLocalityZoneIDKey],
+							Port:          4501,
+						}.String())
+						// The client still reports the IP address that the DNS name
+						// was resolved to before the Pod was recreated.
+						status.Client.Coordinators.Coordinators = append(status.Client.Coordinators.Coordinators,
+							fdbv1beta2.FoundationDBStatusCoordinator{
+								Address: fdbv1beta2.ProcessAddress{
+									IPAddress:    net.ParseIP("192.168.0.1"),
+									Port:         4501,
+									FromHostname: true,
+								},
+							})
+					}
+
+					status.Cluster.ConnectionString = connectionString.String()
+				})
+
+				It("should return that the coordinators are valid", func() {
+					coordinatorsValid, addressesValid, err := CheckCoordinatorValidity(logr.Discard(), cluster, status, GetCoordinatorStatus(status))
+					Expect(coordinatorsValid).To(BeTrue())
+					Expect(addressesValid).To(BeTrue())
+					Expect(err).NotTo(HaveOccurred())
+				})
+			})
+		})
+	})
+
+	Describe("GetCoordinatorStatus", func() {
+		var status *fdbv1beta2.FoundationDBStatus
+
+		BeforeEach(func() {
+			status = &fdbv1beta2.FoundationDBStatus{
+				Client: fdbv1beta2.FoundationDBStatusLocalClientInfo{
+					Coordinators: fdbv1beta2.FoundationDBStatusCoordinatorInfo{
+						Coordinators: []fdbv1beta2.FoundationDBStatusCoordinator{
+							{Address: fdbv1beta2.ProcessAddress{IPAddress: net.ParseIP("192.168.0.1"), Port: 4501, FromHostname: true}},
+							{Address: fdbv1beta2.ProcessAddress{IPAddress: net.ParseIP("192.168.0.2"), Port: 4501, FromHostname: true}},
+						},
+					},
+				},
+			}
+		})
+
+		When("the connection string uses IP addresses", func() {
+			BeforeEach(func() {
+				status.Cluster.ConnectionString = "test:test@192.168.0.1:4501,192.168.0.2:4501"
+			})
+
+			It("should return the coordinators reported by the client", func() {
+				Expect(GetCoordinatorStatus(status)).To(Equal(map[string]bool{
+					"192.168.0.1:4501(fromHostname)": false,
+					"192.168.0.2:4501(fromHostname)": false,
+				}))
+			})
+		})
+
+		When("the connection string uses DNS names", func() {
+			BeforeEach(func() {
+				status.Cluster.ConnectionString = "test:test@storage-1.test.svc.cluster.local:4501,storage-2.test.svc.cluster.local:4501"
+			})
+
+			It("should return the coordinators from the connection string", func() {
+				Expect(GetCoordinatorStatus(status)).To(Equal(map[string]bool{
+					"storage-1.test.svc.cluster.local:4501": false,
+					"storage-2.test.svc.cluster.local:4501": false,
+				}))
+			})
 		})
 	})
 })
@@ -0,0 +1,199 @@
+/*
+ * operations.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"sort"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// disruptionConditions contains the process group conditions that indicate
+// that the processes of a process group are currently not serving.
+var disruptionConditions = []fdbv1beta2.ProcessGroupConditionType{
+	fdbv1beta2.MissingProcesses,
+	fdbv1beta2.MissingPod,
+	fdbv1beta2.PodFailing,
+	fdbv1beta2.PodPending,
+	fdbv1beta2.ResourcesTerminating,
+}
+
+// Request represents a process group that needs a disruptive operation.
+type Request struct {
+	// ProcessGroupID defines the process group the operation targets.
+	ProcessGroupID fdbv1beta2.ProcessGroupID
+	// Reason describes why the operation is needed.
+	Reason string
+}
+
+// Plan replaces the queued operations of the operation type with the
+// requests and returns the process groups that are allowed to run the
+// operation now. The queue is stored in the cluster status. If the operation
+// queue is disabled all requests are allowed.
+func Plan(cluster *fdbv1beta2.FoundationDBCluster, operationType fdbv1beta2.ProcessGroupOperationType, requests []Request, now time.Time) map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None {
+	allowed := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(requests))
+	if !cluster.GetOperationQueueEnabled() {
+		cluster.Status.Operations = nil
+		for _, request := range requests {
+			allowed[request.ProcessGroupID] = fdbv1beta2.None{}
+		}
+
+		return allowed
+	}
+
+	processGroups := make(map[fdbv1beta2.ProcessGroupID]*fdbv1beta2.ProcessGroupStatus, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = processGroup
+	}
+
+	queue := make([]fdbv1beta2.ProcessGroupOperation, 0, len(cluster.Status.Operations)+len(requests))
+	previousTimestamps := make(map[fdbv1beta2.ProcessGroupID]int64)
+	for _, operation := range cluster.Status.Operations {
+		if operation.Type == operationType {
+			previousTimestamps[operation.ProcessGroupID] = operation.Timestamp
+			continue
+		}
+
+		if _, ok := processGroups[operation.ProcessGroupID]; ok {
+			queue = append(queue, operation)
+		}
+	}
+
+	queued := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None, len(requests))
+	for _, request := range requests {
+		processGroup, ok := processGroups[request.ProcessGroupID]
+		if !ok {
+			continue
+		}
+
+		if _, ok := queued[request.ProcessGroupID]; ok {
+			continue
+		}
+		queued[request.ProcessGroupID] = fdbv1beta2.None{}
+
+		timestamp, ok := previousTimestamps[request.ProcessGroupID]
+		if !ok {
+			timestamp = now.Unix()
+		}
+
+		queue = append(queue, fdbv1beta2.ProcessGroupOperation{
+			ProcessGroupID: request.ProcessGroupID,
+			Type:           operationType,
+			Priority:       cluster.GetOperationPriority(operationType),
+			Reason:         request.Reason,
+			FaultDomain:    GetFaultDomain(processGroup),
+			Timestamp:      timestamp,
+		})
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		if queue[i].Priority != queue[j].Priority {
+			return queue[i].Priority > queue[j].Priority
+		}
+
+		if queue[i].Timestamp != queue[j].Timestamp {
+			return queue[i].Timestamp < queue[j].Timestamp
+		}
+
+		return queue[i].ProcessGroupID < queue[j].ProcessGroupID
+	})
+
+	allocate(cluster, queue)
+	cluster.Status.Operations = queue
+
+	for _, operation := range queue {
+		if operation.Type == operationType && operation.Allowed {
+			allowed[operation.ProcessGroupID] = fdbv1beta2.None{}
+		}
+	}
+
+	return allowed
+}
+
+// allocate marks the operations in the queue as allowed, in the order of the
+// queue, as long as the disruption limits are not exceeded. Process groups
+// that are already disrupted don't count against the limits again.
+func allocate(cluster *fdbv1beta2.FoundationDBCluster, queue []fdbv1beta2.ProcessGroupOperation) {
+	disrupted := make(map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None)
+	faultDomains := make(map[string]int)
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if IsDisrupted(processGroup) {
+			disrupted[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+			faultDomains[GetFaultDomain(processGroup)]++
+		}
+	}
+
+	options := cluster.Spec.AutomationOptions.OperationQueue
+	for index := range queue {
+		operation := &queue[index]
+		if _, ok := disrupted[operation.ProcessGroupID]; ok {
+			operation.Allowed = true
+			continue
+		}
+
+		domainCount, domainDisrupted := faultDomains[operation.FaultDomain]
+		if !domainDisrupted && len(faultDomains) >= cluster.GetMaxDisruptedFaultDomains() {
+			operation.Allowed = false
+			continue
+		}
+
+		if options.MaxOperationsPerFaultDomain != nil && domainCount >= *options.MaxOperationsPerFaultDomain {
+			operation.Allowed = false
+			continue
+		}
+
+		if options.MaxConcurrentOperations != nil && len(disrupted) >= *options.MaxConcurrentOperations {
+			operation.Allowed = false
+			continue
+		}
+
+		operation.Allowed = true
+		disrupted[operation.ProcessGroupID] = fdbv1beta2.None{}
+		faultDomains[operation.FaultDomain]++
+	}
+}
+
+// IsDisrupted returns true if the process group is marked for removal or if
+// its processes are currently not serving.
+func IsDisrupted(processGroup *fdbv1beta2.ProcessGroupStatus) bool {
+	if processGroup.IsMarkedForRemoval() {
+		return true
+	}
+
+	for _, condition := range disruptionConditions {
+		if processGroup.GetConditionTime(condition) != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetFaultDomain returns the fault domain of the process group. If the fault
+// domain is not known, the process group is treated as its own fault domain.
+func GetFaultDomain(processGroup *fdbv1beta2.ProcessGroupStatus) string {
+	if processGroup.FaultDomain == "" {
+		return string(processGroup.ProcessGroupID)
+	}
+
+	return processGroup.FaultDomain
+}
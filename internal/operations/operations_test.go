@@ -0,0 +1,225 @@
+/*
+ * operations_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package operations
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("operations", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var now time.Time
+
+	BeforeEach(func() {
+		now = time.Now()
+		cluster = &fdbv1beta2.FoundationDBCluster{
+			Spec: fdbv1beta2.FoundationDBClusterSpec{
+				AutomationOptions: fdbv1beta2.FoundationDBClusterAutomationOptions{
+					OperationQueue: fdbv1beta2.OperationQueueOptions{
+						Enabled: pointer.Bool(true),
+					},
+				},
+			},
+			Status: fdbv1beta2.FoundationDBClusterStatus{
+				ProcessGroups: []*fdbv1beta2.ProcessGroupStatus{
+					{ProcessGroupID: "storage-1", ProcessClass: fdbv1beta2.ProcessClassStorage, FaultDomain: "zone-a"},
+					{ProcessGroupID: "storage-2", ProcessClass: fdbv1beta2.ProcessClassStorage, FaultDomain: "zone-a"},
+					{ProcessGroupID: "storage-3", ProcessClass: fdbv1beta2.ProcessClassStorage, FaultDomain: "zone-b"},
+					{ProcessGroupID: "storage-4", ProcessClass: fdbv1beta2.ProcessClassStorage, FaultDomain: "zone-c"},
+				},
+			},
+		}
+	})
+
+	When("the operation queue is disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.OperationQueue.Enabled = nil
+			cluster.Status.Operations = []fdbv1beta2.ProcessGroupOperation{{ProcessGroupID: "storage-1", Type: fdbv1beta2.ProcessGroupOperationBounce}}
+		})
+
+		It("should allow all requests and clear the queue", func() {
+			allowed := Plan(cluster, fdbv1beta2.ProcessGroupOperationPodUpdate, []Request{{ProcessGroupID: "storage-1"}, {ProcessGroupID: "storage-3"}}, now)
+			Expect(allowed).To(HaveLen(2))
+			Expect(cluster.Status.Operations).To(BeNil())
+		})
+	})
+
+	When("process groups in multiple fault domains need an update", func() {
+		var allowed map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None
+
+		JustBeforeEach(func() {
+			allowed = Plan(cluster, fdbv1beta2.ProcessGroupOperationPodUpdate, []Request{
+				{ProcessGroupID: "storage-4", Reason: "spec changed"},
+				{ProcessGroupID: "storage-1", Reason: "spec changed"},
+				{ProcessGroupID: "storage-3", Reason: "spec changed"},
+				{ProcessGroupID: "storage-2", Reason: "spec changed"},
+			}, now)
+		})
+
+		It("should only allow the operations in one fault domain", func() {
+			Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-1")))
+			Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-2")))
+			Expect(allowed).To(HaveLen(2))
+		})
+
+		It("should store the queue in the status", func() {
+			Expect(cluster.Status.Operations).To(HaveLen(4))
+			Expect(cluster.Status.Operations[0]).To(Equal(fdbv1beta2.ProcessGroupOperation{
+				ProcessGroupID: "storage-1",
+				Type:           fdbv1beta2.ProcessGroupOperationPodUpdate,
+				Priority:       10,
+				Reason:         "spec changed",
+				FaultDomain:    "zone-a",
+				Timestamp:      now.Unix(),
+				Allowed:        true,
+			}))
+		})
+
+		When("a process group in another fault domain is already disrupted", func() {
+			BeforeEach(func() {
+				cluster.Status.ProcessGroups[3].ProcessGroupConditions = []*fdbv1beta2.ProcessGroupCondition{
+					fdbv1beta2.NewProcessGroupCondition(fdbv1beta2.MissingProcesses),
+				}
+			})
+
+			It("should only allow the operation for the disrupted process group", func() {
+				Expect(allowed).To(HaveLen(1))
+				Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-4")))
+			})
+		})
+
+		When("two fault domains can be disrupted", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.OperationQueue.MaxDisruptedFaultDomains = pointer.Int(2)
+			})
+
+			It("should allow the operations in two fault domains", func() {
+				Expect(allowed).To(HaveLen(3))
+				Expect(allowed).NotTo(HaveKey(fdbv1beta2.ProcessGroupID("storage-4")))
+			})
+
+			When("the operations per fault domain are limited", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.OperationQueue.MaxOperationsPerFaultDomain = pointer.Int(1)
+				})
+
+				It("should allow one operation per fault domain", func() {
+					Expect(allowed).To(HaveLen(2))
+					Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-1")))
+					Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-3")))
+				})
+			})
+
+			When("the concurrent operations are limited", func() {
+				BeforeEach(func() {
+					cluster.Spec.AutomationOptions.OperationQueue.MaxConcurrentOperations = pointer.Int(1)
+				})
+
+				It("should allow one operation", func() {
+					Expect(allowed).To(HaveLen(1))
+					Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-1")))
+				})
+			})
+		})
+
+		When("a replacement with a higher priority is queued in another fault domain", func() {
+			BeforeEach(func() {
+				cluster.Status.Operations = []fdbv1beta2.ProcessGroupOperation{
+					{ProcessGroupID: "storage-3", Type: fdbv1beta2.ProcessGroupOperationReplacement, Priority: 30, FaultDomain: "zone-b", Timestamp: now.Unix()},
+				}
+			})
+
+			It("should allow the operations in the fault domain of the replacement", func() {
+				Expect(allowed).To(HaveLen(1))
+				Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-3")))
+				Expect(cluster.Status.Operations[0].Type).To(Equal(fdbv1beta2.ProcessGroupOperationReplacement))
+				Expect(cluster.Status.Operations[0].Allowed).To(BeTrue())
+			})
+		})
+
+		When("the priority of Pod updates is overridden", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.OperationQueue.Priorities = map[fdbv1beta2.ProcessGroupOperationType]int{
+					fdbv1beta2.ProcessGroupOperationPodUpdate: 50,
+				}
+				cluster.Status.Operations = []fdbv1beta2.ProcessGroupOperation{
+					{ProcessGroupID: "storage-3", Type: fdbv1beta2.ProcessGroupOperationReplacement, Priority: 30, FaultDomain: "zone-b", Timestamp: now.Unix()},
+				}
+			})
+
+			It("should plan the Pod updates first", func() {
+				Expect(allowed).To(HaveLen(2))
+				Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-1")))
+				Expect(cluster.Status.Operations[4].Type).To(Equal(fdbv1beta2.ProcessGroupOperationReplacement))
+				Expect(cluster.Status.Operations[4].Allowed).To(BeFalse())
+			})
+		})
+	})
+
+	When("an operation was queued before", func() {
+		BeforeEach(func() {
+			cluster.Status.Operations = []fdbv1beta2.ProcessGroupOperation{
+				{ProcessGroupID: "storage-3", Type: fdbv1beta2.ProcessGroupOperationBounce, Timestamp: now.Add(-1 * time.Hour).Unix()},
+				{ProcessGroupID: "storage-4", Type: fdbv1beta2.ProcessGroupOperationBounce, Timestamp: now.Add(-1 * time.Hour).Unix()},
+				{ProcessGroupID: "storage-5", Type: fdbv1beta2.ProcessGroupOperationPodUpdate, Timestamp: now.Add(-1 * time.Hour).Unix()},
+			}
+		})
+
+		It("should keep the timestamp and drop operations that are not requested anymore", func() {
+			allowed := Plan(cluster, fdbv1beta2.ProcessGroupOperationBounce, []Request{{ProcessGroupID: "storage-4"}, {ProcessGroupID: "storage-1"}}, now)
+			Expect(allowed).To(HaveLen(1))
+			Expect(allowed).To(HaveKey(fdbv1beta2.ProcessGroupID("storage-4")))
+			Expect(cluster.Status.Operations).To(HaveLen(2))
+			Expect(cluster.Status.Operations[0].ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-4")))
+			Expect(cluster.Status.Operations[0].Timestamp).To(Equal(now.Add(-1 * time.Hour).Unix()))
+			Expect(cluster.Status.Operations[1].ProcessGroupID).To(Equal(fdbv1beta2.ProcessGroupID("storage-1")))
+		})
+	})
+
+	DescribeTable("checking if a process group is disrupted",
+		func(processGroup *fdbv1beta2.ProcessGroupStatus, expected bool) {
+			Expect(IsDisrupted(processGroup)).To(Equal(expected))
+		},
+		Entry("healthy process group", &fdbv1beta2.ProcessGroupStatus{ProcessGroupID: "storage-1"}, false),
+		Entry("process group with an incorrect config map",
+			&fdbv1beta2.ProcessGroupStatus{
+				ProcessGroupID:         "storage-1",
+				ProcessGroupConditions: []*fdbv1beta2.ProcessGroupCondition{fdbv1beta2.NewProcessGroupCondition(fdbv1beta2.IncorrectConfigMap)},
+			}, false),
+		Entry("process group with missing processes",
+			&fdbv1beta2.ProcessGroupStatus{
+				ProcessGroupID:         "storage-1",
+				ProcessGroupConditions: []*fdbv1beta2.ProcessGroupCondition{fdbv1beta2.NewProcessGroupCondition(fdbv1beta2.MissingProcesses)},
+			}, true),
+		Entry("process group marked for removal",
+			&fdbv1beta2.ProcessGroupStatus{
+				ProcessGroupID:   "storage-1",
+				RemovalTimestamp: &metav1.Time{Time: time.Now()},
+			}, true),
+	)
+})
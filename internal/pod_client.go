@@ -191,15 +191,27 @@ func generateRequest(retryClient *retryablehttp.Client, url string, method strin
 	return nil, fmt.Errorf("unknown HTTP method %s", method)
 }
 
-// makeRequest submits a request to the sidecar.
-func (client *realFdbPodSidecarClient) makeRequest(method, path string) (string, int, error) {
-	var err error
-
+// getSidecarURL returns the URL for the provided path on the sidecar. IPv6
+// addresses are put into brackets.
+func (client *realFdbPodSidecarClient) getSidecarURL(path string) url.URL {
 	target := url.URL{
 		Scheme: "http",
-		Host:   client.getListenIP() + ":8080",
+		Host:   net.JoinHostPort(client.getListenIP(), "8080"),
 		Path:   path,
 	}
+
+	if client.useTLS {
+		target.Scheme = "https"
+	}
+
+	return target
+}
+
+// makeRequest submits a request to the sidecar.
+func (client *realFdbPodSidecarClient) makeRequest(method, path string) (string, int, error) {
+	var err error
+
+	target := client.getSidecarURL(path)
 	retryClient := retryablehttp.NewClient()
 	retryClient.RetryMax = 2
 	retryClient.RetryWaitMax = 1 * time.Second
@@ -209,7 +221,6 @@ func (client *realFdbPodSidecarClient) makeRequest(method, path string) (string,
 
 	if client.useTLS {
 		retryClient.HTTPClient.Transport = &http.Transport{TLSClientConfig: client.tlsConfig}
-		target.Scheme = "https"
 	}
 
 	req, err := generateRequest(retryClient, target.String(), method, client.getTimeout, client.postTimeout)
@@ -448,7 +459,12 @@ func GetSubstitutionsFromClusterAndPod(logger logr.Logger, cluster *fdbv1beta2.F
 		}
 	}
 
-	ipString := GetPublicIPsForPod(pod, logger)[0]
+	var ipString string
+	// The Pod might not have an IP address of the requested IP family yet.
+	publicIPs := GetPublicIPsForPod(pod, logger)
+	if len(publicIPs) > 0 {
+		ipString = publicIPs[0]
+	}
 	substitutions["FDB_PUBLIC_IP"] = ipString
 	if ipString != "" {
 		ip := net.ParseIP(ipString)
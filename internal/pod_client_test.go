@@ -26,9 +26,12 @@ import (
 	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-retryablehttp"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 )
 
 var _ = Describe("pod_client", func() {
@@ -106,4 +109,61 @@ var _ = Describe("pod_client", func() {
 			})
 		})
 	})
+
+	When("the cluster uses the IPv6 pod IP family", func() {
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			cluster.Spec.Routing.PodIPFamily = pointer.Int(6)
+
+			var err error
+			pod, err = GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			pod.Status.PodIP = "1.1.1.1"
+			pod.Status.PodIPs = []corev1.PodIP{{IP: "1.1.1.1"}, {IP: "fd00::1"}}
+		})
+
+		It("should use the IPv6 address as public IP", func() {
+			Expect(GetPublicIPsForPod(pod, logr.Discard())).To(ConsistOf("fd00::1"))
+		})
+
+		It("should bracket the IPv6 address when connecting to the sidecar", func() {
+			client := &realFdbPodSidecarClient{Cluster: cluster, Pod: pod, logger: logr.Discard()}
+			target := client.getSidecarURL("substitutions")
+			Expect(target.String()).To(Equal("http://[fd00::1]:8080/substitutions"))
+		})
+
+		It("should bracket the IPv6 address in the substitutions", func() {
+			substitutions, err := GetSubstitutionsFromClusterAndPod(logr.Discard(), cluster, pod)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(substitutions).To(HaveKeyWithValue("FDB_PUBLIC_IP", "[fd00::1]"))
+		})
+
+		When("the Pod only reports the primary IP", func() {
+			BeforeEach(func() {
+				pod.Status.PodIP = "fd00::2"
+				pod.Status.PodIPs = nil
+			})
+
+			It("should use the primary IP", func() {
+				Expect(GetPublicIPsForPod(pod, logr.Discard())).To(ConsistOf("fd00::2"))
+			})
+		})
+
+		When("the Pod has no IPv6 address", func() {
+			BeforeEach(func() {
+				pod.Status.PodIPs = []corev1.PodIP{{IP: "1.1.1.1"}}
+			})
+
+			It("should not return a public IP", func() {
+				Expect(GetPublicIPsForPod(pod, logr.Discard())).To(BeEmpty())
+			})
+
+			It("should leave the public IP substitution empty", func() {
+				substitutions, err := GetSubstitutionsFromClusterAndPod(logr.Discard(), cluster, pod)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(substitutions).To(HaveKeyWithValue("FDB_PUBLIC_IP", ""))
+			})
+		})
+	})
 })
@@ -0,0 +1,89 @@
+/*
+ * pod_exec.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// PodCommandExecutor runs commands inside the containers of a Pod.
+type PodCommandExecutor interface {
+	// ExecuteCommand runs the command in the container of the Pod and returns
+	// stdout and stderr of the command.
+	ExecuteCommand(ctx context.Context, pod *corev1.Pod, container string, command []string) (string, string, error)
+}
+
+// kubernetesPodCommandExecutor runs commands through the exec subresource of
+// the Kubernetes API.
+type kubernetesPodCommandExecutor struct {
+	config    *rest.Config
+	clientSet *kubernetes.Clientset
+}
+
+// NewPodCommandExecutor creates a PodCommandExecutor that uses the exec
+// subresource of the Kubernetes API.
+func NewPodCommandExecutor(config *rest.Config) (PodCommandExecutor, error) {
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kubernetesPodCommandExecutor{config: config, clientSet: clientSet}, nil
+}
+
+// ExecuteCommand runs the command in the container of the Pod and returns
+// stdout and stderr of the command.
+func (executor *kubernetesPodCommandExecutor) ExecuteCommand(_ context.Context, pod *corev1.Pod, container string, command []string) (string, string, error) {
+	req := executor.clientSet.CoreV1().RESTClient().Post().
+		Resource("pods").Name(pod.Name).
+		Namespace(pod.Namespace).SubResource("exec")
+
+	req.VersionedParams(&corev1.PodExecOptions{
+		Command:   command,
+		Container: container,
+		Stdout:    true,
+		Stderr:    true,
+	}, clientgoscheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(executor.config, "POST", req.URL())
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	err = exec.Stream(remotecommand.StreamOptions{
+		Stdout: &stdout,
+		Stderr: &stderr,
+	})
+	if err != nil {
+		return stdout.String(), stderr.String(), fmt.Errorf("error running command in pod %s/%s: %w", pod.Namespace, pod.Name, err)
+	}
+
+	return stdout.String(), stderr.String(), nil
+}
@@ -69,6 +69,10 @@ func GetPublicIPsForPod(pod *corev1.Pod, log logr.Logger) []string {
 
 	if podIPFamily != nil {
 		podIPs := pod.Status.PodIPs
+		// Some environments only report the primary IP of the pod.
+		if len(podIPs) == 0 && pod.Status.PodIP != "" {
+			podIPs = []corev1.PodIP{{IP: pod.Status.PodIP}}
+		}
 		matchingIPs := make([]string, 0, len(podIPs))
 
 		for _, podIP := range podIPs {
@@ -77,16 +81,11 @@ func GetPublicIPsForPod(pod *corev1.Pod, log logr.Logger) []string {
 				log.Error(nil, "Failed to parse IP from pod", "ip", podIP)
 				continue
 			}
-			matches := false
-			switch *podIPFamily {
-			case 4:
-				matches = ip.To4() != nil
-			case 6:
-				matches = ip.To4() == nil
-			default:
+			if *podIPFamily != 4 && *podIPFamily != 6 {
 				log.Error(nil, "Could not match IP address against IP family", "family", *podIPFamily)
+				continue
 			}
-			if matches {
+			if MatchesIPFamily(ip, *podIPFamily) {
 				matchingIPs = append(matchingIPs, podIP.IP)
 			}
 		}
@@ -96,6 +95,19 @@ func GetPublicIPsForPod(pod *corev1.Pod, log logr.Logger) []string {
 	return []string{pod.Status.PodIP}
 }
 
+// MatchesIPFamily checks if the provided IP address belongs to the IP family,
+// which is either 4 or 6.
+func MatchesIPFamily(ip net.IP, family int) bool {
+	switch family {
+	case 4:
+		return ip.To4() != nil
+	case 6:
+		return ip.To4() == nil && ip.To16() != nil
+	default:
+		return false
+	}
+}
+
 // GetProcessGroupIDFromMeta fetches the process group ID from an object's metadata.
 func GetProcessGroupIDFromMeta(cluster *fdbv1beta2.FoundationDBCluster, metadata metav1.ObjectMeta) fdbv1beta2.ProcessGroupID {
 	return fdbv1beta2.ProcessGroupID(metadata.Labels[cluster.GetProcessGroupIDLabel()])
@@ -101,7 +101,7 @@ func GetService(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 		processesPerPod = cluster.GetStorageServersPerPod()
 	}
 
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metadata,
 		Spec: corev1.ServiceSpec{
 			Type:                     corev1.ServiceTypeClusterIP,
@@ -109,7 +109,10 @@ func GetService(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 			PublishNotReadyAddresses: true,
 			Selector:                 GetPodMatchLabels(cluster, "", string(id)),
 		},
-	}, nil
+	}
+	setServiceIPFamily(cluster, service)
+
+	return service, nil
 }
 
 // GetPod builds a pod for a new process group
@@ -322,7 +325,7 @@ func configureVolumesForContainers(cluster *fdbv1beta2.FoundationDBCluster, podS
 		{Key: ClusterFileKey, Path: "fdb.cluster"},
 	}
 
-	if len(cluster.Spec.TrustedCAs) > 0 {
+	if len(cluster.GetTrustedCAs()) > 0 {
 		configMapItems = append(configMapItems, corev1.KeyToPath{Key: "ca-file", Path: "ca.pem"})
 	}
 
@@ -423,7 +426,7 @@ func GetPodSpec(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2
 
 	extendEnv(mainContainer, corev1.EnvVar{Name: "FDB_CLUSTER_FILE", Value: "/var/dynamic-conf/fdb.cluster"})
 
-	if len(cluster.Spec.TrustedCAs) > 0 {
+	if len(cluster.GetTrustedCAs()) > 0 {
 		extendEnv(mainContainer, corev1.EnvVar{Name: "FDB_TLS_CA_FILE", Value: "/var/dynamic-conf/ca.pem"})
 	}
 
@@ -513,7 +516,7 @@ func configureSidecarContainerForBackup(backup *fdbv1beta2.FoundationDBBackup, c
 func configureSidecarContainer(container *corev1.Container, initMode bool, processGroupID fdbv1beta2.ProcessGroupID, podName string, versionString string, optionalCluster *fdbv1beta2.FoundationDBCluster, imageConfigs []fdbv1beta2.ImageConfig, allowTagOverride bool) error {
 	sidecarEnv := make([]corev1.EnvVar, 0, 4)
 
-	hasTrustedCAs := optionalCluster != nil && len(optionalCluster.Spec.TrustedCAs) > 0
+	hasTrustedCAs := optionalCluster != nil && len(optionalCluster.GetTrustedCAs()) > 0
 
 	var sidecarArgs []string
 
@@ -816,7 +819,11 @@ func extendEnv(container *corev1.Container, env ...corev1.EnvVar) {
 }
 
 // GetBackupDeployment builds a deployment for backup agents for a cluster.
-func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deployment, error) {
+//
+// The credentials secret is the Secret referenced in the blob store
+// configuration, if any. A hash of its data is added to the pod template so
+// that the backup agents are updated when the credentials are rotated.
+func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup, credentialsSecret *corev1.Secret) (*appsv1.Deployment, error) {
 	agentCount := int32(backup.GetDesiredAgentCount())
 	if agentCount == 0 {
 		return nil, nil
@@ -956,6 +963,11 @@ func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deploym
 		},
 	)
 
+	err = configureBlobStoreForBackup(backup, podTemplate, mainContainer, credentialsSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	deployment.Spec.Template = *podTemplate
 
 	specHash, err := GetJSONHash(deployment.Spec)
@@ -968,6 +980,57 @@ func GetBackupDeployment(backup *fdbv1beta2.FoundationDBBackup) (*appsv1.Deploym
 	return deployment, nil
 }
 
+// configureBlobStoreForBackup mounts the credentials and the volume for the
+// backup destination into the backup agents.
+func configureBlobStoreForBackup(backup *fdbv1beta2.FoundationDBBackup, podTemplate *corev1.PodTemplateSpec, mainContainer *corev1.Container, credentialsSecret *corev1.Secret) error {
+	configuration := backup.Spec.BlobStoreConfiguration
+	if configuration == nil {
+		return nil
+	}
+
+	if configuration.CredentialsSecret != nil {
+		extendEnv(mainContainer, corev1.EnvVar{Name: "FDB_BLOB_CREDENTIALS", Value: "/var/backup-credentials/credentials"})
+		mainContainer.VolumeMounts = append(mainContainer.VolumeMounts,
+			corev1.VolumeMount{Name: "backup-credentials", MountPath: "/var/backup-credentials", ReadOnly: true},
+		)
+		podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+			Name: "backup-credentials",
+			VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+				SecretName: configuration.CredentialsSecret.Name,
+				Items: []corev1.KeyToPath{
+					{Key: configuration.CredentialsSecret.Key, Path: "credentials"},
+				},
+			}},
+		})
+
+		if credentialsSecret != nil {
+			credentialsHash, err := GetJSONHash(credentialsSecret.Data)
+			if err != nil {
+				return err
+			}
+
+			if podTemplate.ObjectMeta.Annotations == nil {
+				podTemplate.ObjectMeta.Annotations = make(map[string]string, 1)
+			}
+			podTemplate.ObjectMeta.Annotations[fdbv1beta2.BlobCredentialsHashAnnotation] = credentialsHash
+		}
+	}
+
+	if configuration.GetBackend() == fdbv1beta2.BlobStoreBackendFile && configuration.VolumeClaimName != "" {
+		mainContainer.VolumeMounts = append(mainContainer.VolumeMounts,
+			corev1.VolumeMount{Name: "backup-data", MountPath: fdbv1beta2.FileBackupMountPath},
+		)
+		podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+			Name: "backup-data",
+			VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: configuration.VolumeClaimName,
+			}},
+		})
+	}
+
+	return nil
+}
+
 // GetStorageServersPerPodForPod returns the value of STORAGE_SERVERS_PER_POD from the sidecar or 1
 func GetStorageServersPerPodForPod(pod *corev1.Pod) (int, error) {
 	// If not specified we will default to 1
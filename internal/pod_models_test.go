@@ -2449,8 +2449,61 @@ var _ = Describe("pod_models", func() {
 				}))
 			})
 		})
+
+		Context("with the IPv6 pod IP family", func() {
+			BeforeEach(func() {
+				cluster.Spec.Routing.PodIPFamily = pointer.Int(6)
+				service, err = GetService(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should restrict the service to IPv6", func() {
+				Expect(service.Spec.IPFamilies).To(ConsistOf(corev1.IPv6Protocol))
+				Expect(service.Spec.IPFamilyPolicy).To(HaveValue(Equal(corev1.IPFamilyPolicySingleStack)))
+				Expect(len(service.Spec.Ports)).To(Equal(2))
+			})
+		})
+
+		Context("without a pod IP family", func() {
+			BeforeEach(func() {
+				service, err = GetService(cluster, fdbv1beta2.ProcessClassStorage, 1)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should use the default IP family of the Kubernetes cluster", func() {
+				Expect(service.Spec.IPFamilies).To(BeEmpty())
+				Expect(service.Spec.IPFamilyPolicy).To(BeNil())
+			})
+		})
 	})
 
+	DescribeTable("getting the service IP",
+		func(family *int, service *corev1.Service, expected string) {
+			cluster.Spec.Routing.PodIPFamily = family
+			Expect(GetServiceIP(cluster, service)).To(Equal(expected))
+		},
+		Entry("without a pod IP family",
+			nil,
+			&corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "192.168.0.1", ClusterIPs: []string{"192.168.0.1", "fd00::1"}}},
+			"192.168.0.1",
+		),
+		Entry("with the IPv6 family on a dual-stack service",
+			pointer.Int(6),
+			&corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "192.168.0.1", ClusterIPs: []string{"192.168.0.1", "fd00::1"}}},
+			"fd00::1",
+		),
+		Entry("with the IPv6 family on a service that only reports the primary IP",
+			pointer.Int(6),
+			&corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "fd00::1"}},
+			"fd00::1",
+		),
+		Entry("with the IPv4 family on an IPv6-only service",
+			pointer.Int(4),
+			&corev1.Service{Spec: corev1.ServiceSpec{ClusterIP: "fd00::1", ClusterIPs: []string{"fd00::1"}}},
+			"",
+		),
+	)
+
 	Describe("GetPvc", func() {
 		var pvc *corev1.PersistentVolumeClaim
 
@@ -2704,6 +2757,22 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with DNS in the cluster file", func() {
+			BeforeEach(func() {
+				cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			})
+
+			It("should publish the addresses of pods that are not ready", func() {
+				Expect(service.Spec).To(Equal(corev1.ServiceSpec{
+					ClusterIP:                "None",
+					PublishNotReadyAddresses: true,
+					Selector: map[string]string{
+						fdbv1beta2.FDBClusterLabel: "operator-test-1",
+					},
+				}))
+			})
+		})
+
 		Context("with custom resource labels", func() {
 			BeforeEach(func() {
 				cluster.Spec.LabelConfig = fdbv1beta2.LabelConfig{
@@ -2756,6 +2825,101 @@ var _ = Describe("pod_models", func() {
 		})
 	})
 
+	Describe("CheckPodDNSName", func() {
+		var service *corev1.Service
+		var pod *corev1.Pod
+		var dnsName string
+
+		BeforeEach(func() {
+			cluster.Spec.Routing.UseDNSInClusterFile = pointer.Bool(true)
+			service = GetHeadlessService(cluster)
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "operator-test-1-storage-1",
+					Namespace: cluster.Namespace,
+					Labels:    cluster.GetMatchLabels(),
+				},
+				Spec: corev1.PodSpec{
+					Hostname:  "operator-test-1-storage-1",
+					Subdomain: service.Name,
+				},
+				Status: corev1.PodStatus{
+					PodIP: "192.168.0.1",
+				},
+			}
+			dnsName = GetPodDNSName(cluster, pod.Name)
+		})
+
+		It("should accept a pod that is resolvable through the headless service", func() {
+			Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(Succeed())
+		})
+
+		When("the service is not headless", func() {
+			BeforeEach(func() {
+				service.Spec.ClusterIP = "192.168.1.1"
+			})
+
+			It("should return an error", func() {
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(MatchError("service operator-test-1 is not a headless service"))
+			})
+		})
+
+		When("the DNS name doesn't match the pod", func() {
+			BeforeEach(func() {
+				dnsName = GetPodDNSName(cluster, "operator-test-1-storage-2")
+			})
+
+			It("should return an error", func() {
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(HaveOccurred())
+			})
+		})
+
+		When("the pod doesn't use the headless service as subdomain", func() {
+			BeforeEach(func() {
+				pod.Spec.Subdomain = ""
+			})
+
+			It("should return an error", func() {
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(MatchError("pod operator-test-1-storage-1 doesn't use the headless service operator-test-1 as subdomain"))
+			})
+		})
+
+		When("the pod is not selected by the headless service", func() {
+			BeforeEach(func() {
+				pod.Labels = nil
+			})
+
+			It("should return an error", func() {
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(MatchError("pod operator-test-1-storage-1 is not selected by the headless service operator-test-1"))
+			})
+		})
+
+		When("the pod has no IP address", func() {
+			BeforeEach(func() {
+				pod.Status.PodIP = ""
+			})
+
+			It("should return an error", func() {
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(MatchError("pod operator-test-1-storage-1 has no IP address"))
+			})
+		})
+
+		When("the service doesn't publish not ready addresses", func() {
+			BeforeEach(func() {
+				service.Spec.PublishNotReadyAddresses = false
+			})
+
+			It("should return an error for a pod that is not ready", func() {
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(HaveOccurred())
+			})
+
+			It("should accept a pod that is ready", func() {
+				pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+				Expect(CheckPodDNSName(cluster, service, pod, dnsName)).To(Succeed())
+			})
+		})
+	})
+
 	Describe("GetBackupDeployment", func() {
 		var backup *fdbv1beta2.FoundationDBBackup
 		var deployment *appsv1.Deployment
@@ -2767,7 +2931,7 @@ var _ = Describe("pod_models", func() {
 
 		Context("with a basic deployment", func() {
 			BeforeEach(func() {
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deployment).NotTo(BeNil())
 			})
@@ -2912,7 +3076,7 @@ var _ = Describe("pod_models", func() {
 						},
 					},
 				}
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deployment).NotTo(BeNil())
 			})
@@ -2962,6 +3126,80 @@ var _ = Describe("pod_models", func() {
 			})
 		})
 
+		Context("with a credentials secret in the blob store configuration", func() {
+			var secret *corev1.Secret
+
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration.CredentialsSecret = &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "blob-credentials"},
+					Key:                  "credentials.json",
+				}
+				secret = &corev1.Secret{
+					Data: map[string][]byte{
+						"credentials.json": []byte("{}"),
+					},
+				}
+				deployment, err = GetBackupDeployment(backup, secret)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should mount the secret", func() {
+				Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "backup-credentials",
+					VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{
+						SecretName: "blob-credentials",
+						Items: []corev1.KeyToPath{
+							{Key: "credentials.json", Path: "credentials"},
+						},
+					}},
+				}))
+
+				container := deployment.Spec.Template.Spec.Containers[0]
+				Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "backup-credentials", MountPath: "/var/backup-credentials", ReadOnly: true}))
+				Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "FDB_BLOB_CREDENTIALS", Value: "/var/backup-credentials/credentials"}))
+			})
+
+			It("should add the hash of the credentials", func() {
+				hash, err := GetJSONHash(secret.Data)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment.Spec.Template.Annotations).To(HaveKeyWithValue(fdbv1beta2.BlobCredentialsHashAnnotation, hash))
+			})
+
+			When("the credentials are rotated", func() {
+				It("should change the hash", func() {
+					secret.Data["credentials.json"] = []byte(`{"accounts": {}}`)
+					rotatedDeployment, err := GetBackupDeployment(backup, secret)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(rotatedDeployment.Spec.Template.Annotations[fdbv1beta2.BlobCredentialsHashAnnotation]).NotTo(Equal(deployment.Spec.Template.Annotations[fdbv1beta2.BlobCredentialsHashAnnotation]))
+				})
+			})
+		})
+
+		Context("with the file backend", func() {
+			BeforeEach(func() {
+				backup.Spec.BlobStoreConfiguration = &fdbv1beta2.BlobStoreConfiguration{
+					Backend:         fdbv1beta2.BlobStoreBackendFile,
+					VolumeClaimName: "backup-data",
+				}
+				deployment, err = GetBackupDeployment(backup, nil)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(deployment).NotTo(BeNil())
+			})
+
+			It("should mount the volume claim", func() {
+				Expect(deployment.Spec.Template.Spec.Volumes).To(ContainElement(corev1.Volume{
+					Name: "backup-data",
+					VolumeSource: corev1.VolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: "backup-data",
+					}},
+				}))
+
+				container := deployment.Spec.Template.Spec.Containers[0]
+				Expect(container.VolumeMounts).To(ContainElement(corev1.VolumeMount{Name: "backup-data", MountPath: "/var/fdb-backups"}))
+			})
+		})
+
 		Context("with a custom label", func() {
 			BeforeEach(func() {
 				backup.Spec.BackupDeploymentMetadata = &metav1.ObjectMeta{
@@ -2969,7 +3207,7 @@ var _ = Describe("pod_models", func() {
 						"fdb-test": "test-value",
 					},
 				}
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deployment).NotTo(BeNil())
 			})
@@ -2985,7 +3223,7 @@ var _ = Describe("pod_models", func() {
 		Context("with a nil agent count", func() {
 			BeforeEach(func() {
 				backup.Spec.AgentCount = nil
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -2999,7 +3237,7 @@ var _ = Describe("pod_models", func() {
 			BeforeEach(func() {
 				agentCount := 0
 				backup.Spec.AgentCount = &agentCount
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3021,7 +3259,7 @@ var _ = Describe("pod_models", func() {
 						}},
 					},
 				}
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 			})
 
@@ -3037,7 +3275,7 @@ var _ = Describe("pod_models", func() {
 		Context("with customParameters", func() {
 			BeforeEach(func() {
 				backup.Spec.CustomParameters = []fdbv1beta2.FoundationDBCustomParameter{"customParameter=1337"}
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deployment).NotTo(BeNil())
 			})
@@ -3065,7 +3303,7 @@ var _ = Describe("pod_models", func() {
 				backup.Spec.SidecarContainer.ImageConfigs = []fdbv1beta2.ImageConfig{
 					{BaseImage: "foundationdb/foundationdb-kubernetes-sidecar", Tag: "dev-1"},
 				}
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deployment).NotTo(BeNil())
 			})
@@ -3097,7 +3335,7 @@ var _ = Describe("pod_models", func() {
 				}
 
 				backup.Spec.PodTemplateSpec = &templateSpec
-				deployment, err = GetBackupDeployment(backup)
+				deployment, err = GetBackupDeployment(backup, nil)
 				Expect(err).NotTo(HaveOccurred())
 				Expect(deployment).NotTo(BeNil())
 			})
@@ -0,0 +1,227 @@
+/*
+ * recommendations.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recommendations
+
+import (
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+const (
+	// peakDecayPercent defines by how many percent the peak values decay
+	// with every sample.
+	peakDecayPercent = 1
+
+	// maxAverageSamples defines how many samples are taken into account for
+	// the moving average.
+	maxAverageSamples = 100
+
+	// cpuGranularityMillicores defines the granularity of the recommended CPU.
+	cpuGranularityMillicores = 10
+
+	// memoryGranularityBytes defines the granularity of the recommended memory.
+	memoryGranularityBytes = 1024 * 1024
+
+	// storageGranularityBytes defines the granularity of the recommended
+	// storage.
+	storageGranularityBytes = 1024 * 1024 * 1024
+)
+
+// UpdateRecommendations adds a sample of the resource usage reported in the
+// status to the current recommendations and returns the updated
+// recommendations. A process class is only sampled again once the sample
+// interval of the cluster has passed.
+func UpdateRecommendations(cluster *fdbv1beta2.FoundationDBCluster, current []fdbv1beta2.ResourceRecommendation, status *fdbv1beta2.FoundationDBStatus, now time.Time) []fdbv1beta2.ResourceRecommendation {
+	recommendations := make(map[fdbv1beta2.ProcessClass]*fdbv1beta2.ResourceRecommendation, len(current))
+	for _, recommendation := range current {
+		recommendations[recommendation.ProcessClass] = recommendation.DeepCopy()
+	}
+
+	interval := int64(cluster.GetResourceRecommendationSampleIntervalSeconds())
+	headroom := int64(cluster.GetResourceRecommendationHeadroomPercent())
+	for processClass, sample := range getUsageSamples(status) {
+		recommendation, ok := recommendations[processClass]
+		if !ok {
+			recommendation = &fdbv1beta2.ResourceRecommendation{ProcessClass: processClass}
+			recommendations[processClass] = recommendation
+		} else if now.Unix()-recommendation.LastSampleTimestamp < interval {
+			continue
+		}
+
+		addSample(recommendation, sample, now)
+		recommendation.Requests, recommendation.Limits = recommendResources(recommendation.Usage, headroom)
+	}
+
+	if len(recommendations) == 0 {
+		return nil
+	}
+
+	result := make([]fdbv1beta2.ResourceRecommendation, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		result = append(result, *recommendation)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ProcessClass < result[j].ProcessClass
+	})
+
+	return result
+}
+
+// podUsage contains the resource usage of all processes in a Pod.
+type podUsage struct {
+	cpuMillicores int64
+	memoryBytes   int64
+	diskUsedBytes int64
+	queueBytes    int64
+}
+
+// getUsageSamples returns the resource usage of the busiest Pod for every
+// process class. The usage of processes that run in the same Pod is summed
+// up, except for the disk as those processes share the same volume.
+func getUsageSamples(status *fdbv1beta2.FoundationDBStatus) map[fdbv1beta2.ProcessClass]podUsage {
+	if status == nil {
+		return nil
+	}
+
+	pods := make(map[fdbv1beta2.ProcessClass]map[string]*podUsage)
+	for _, process := range status.Cluster.Processes {
+		instanceID, ok := process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey]
+		if !ok || process.ProcessClass == "" {
+			continue
+		}
+
+		if _, ok := pods[process.ProcessClass]; !ok {
+			pods[process.ProcessClass] = make(map[string]*podUsage)
+		}
+
+		usage, ok := pods[process.ProcessClass][instanceID]
+		if !ok {
+			usage = &podUsage{}
+			pods[process.ProcessClass][instanceID] = usage
+		}
+
+		usage.cpuMillicores += int64(process.CPU.UsageCores * 1000)
+		usage.memoryBytes += process.Memory.UsedBytes
+		usage.diskUsedBytes = maxInt64(usage.diskUsedBytes, process.Disk.TotalBytes-process.Disk.FreeBytes)
+		for _, role := range process.Roles {
+			if role.Role != string(fdbv1beta2.ProcessRoleStorage) && role.Role != string(fdbv1beta2.ProcessRoleLog) {
+				continue
+			}
+
+			usage.queueBytes += maxInt64(0, role.InputBytes.Counter-role.DurableBytes.Counter)
+		}
+	}
+
+	samples := make(map[fdbv1beta2.ProcessClass]podUsage, len(pods))
+	for processClass, classPods := range pods {
+		sample := podUsage{}
+		for _, usage := range classPods {
+			sample.cpuMillicores = maxInt64(sample.cpuMillicores, usage.cpuMillicores)
+			sample.memoryBytes = maxInt64(sample.memoryBytes, usage.memoryBytes)
+			sample.diskUsedBytes = maxInt64(sample.diskUsedBytes, usage.diskUsedBytes)
+			sample.queueBytes = maxInt64(sample.queueBytes, usage.queueBytes)
+		}
+
+		samples[processClass] = sample
+	}
+
+	return samples
+}
+
+// addSample adds the sample to the observed usage of the recommendation.
+func addSample(recommendation *fdbv1beta2.ResourceRecommendation, sample podUsage, now time.Time) {
+	usage := &recommendation.Usage
+	samples := int64(recommendation.Samples)
+	if samples > maxAverageSamples-1 {
+		samples = maxAverageSamples - 1
+	}
+
+	usage.PeakCPUMillicores = decayedPeak(usage.PeakCPUMillicores, sample.cpuMillicores)
+	usage.AverageCPUMillicores = (usage.AverageCPUMillicores*samples + sample.cpuMillicores) / (samples + 1)
+	usage.PeakMemoryBytes = decayedPeak(usage.PeakMemoryBytes, sample.memoryBytes)
+	usage.AverageMemoryBytes = (usage.AverageMemoryBytes*samples + sample.memoryBytes) / (samples + 1)
+	usage.PeakDiskUsedBytes = decayedPeak(usage.PeakDiskUsedBytes, sample.diskUsedBytes)
+	usage.PeakQueueBytes = decayedPeak(usage.PeakQueueBytes, sample.queueBytes)
+	usage.AverageQueueBytes = (usage.AverageQueueBytes*samples + sample.queueBytes) / (samples + 1)
+
+	recommendation.Samples++
+	recommendation.LastSampleTimestamp = now.Unix()
+}
+
+// decayedPeak returns the new peak value after the peak decayed.
+func decayedPeak(peak int64, sample int64) int64 {
+	return maxInt64(sample, peak-peak*peakDecayPercent/100)
+}
+
+// maxInt64 returns the larger of both values.
+func maxInt64(a int64, b int64) int64 {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+// recommendResources returns the recommended requests and limits based on the
+// observed peak usage with the headroom on top.
+func recommendResources(usage fdbv1beta2.ResourceUsage, headroom int64) (corev1.ResourceList, corev1.ResourceList) {
+	requests := corev1.ResourceList{}
+	limits := corev1.ResourceList{}
+
+	if usage.PeakCPUMillicores > 0 {
+		cpu := roundUp(usage.PeakCPUMillicores*(100+headroom)/100, cpuGranularityMillicores)
+		requests[corev1.ResourceCPU] = *resource.NewMilliQuantity(cpu, resource.DecimalSI)
+	}
+
+	// The memory limit matches the request, so the process won't be killed
+	// because the node is running out of memory.
+	if usage.PeakMemoryBytes > 0 {
+		memory := roundUp(usage.PeakMemoryBytes*(100+headroom)/100, memoryGranularityBytes)
+		requests[corev1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+		limits[corev1.ResourceMemory] = *resource.NewQuantity(memory, resource.BinarySI)
+	}
+
+	if usage.PeakDiskUsedBytes > 0 {
+		storage := roundUp(usage.PeakDiskUsedBytes*(100+headroom)/100, storageGranularityBytes)
+		requests[corev1.ResourceStorage] = *resource.NewQuantity(storage, resource.BinarySI)
+	}
+
+	if len(requests) == 0 {
+		requests = nil
+	}
+
+	if len(limits) == 0 {
+		limits = nil
+	}
+
+	return requests, limits
+}
+
+// roundUp rounds the value up to the next multiple of the granularity.
+func roundUp(value int64, granularity int64) int64 {
+	return (value + granularity - 1) / granularity * granularity
+}
@@ -0,0 +1,205 @@
+/*
+ * recommendations_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package recommendations
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func generateProcess(processClass fdbv1beta2.ProcessClass, instanceID string, cpu float64, memory int64, diskUsed int64, queue int64) fdbv1beta2.FoundationDBStatusProcessInfo {
+	process := fdbv1beta2.FoundationDBStatusProcessInfo{
+		ProcessClass: processClass,
+		Locality: map[string]string{
+			fdbv1beta2.FDBLocalityInstanceIDKey: instanceID,
+		},
+		CPU:    fdbv1beta2.FoundationDBStatusProcessCPU{UsageCores: cpu},
+		Memory: fdbv1beta2.FoundationDBStatusProcessMemory{UsedBytes: memory},
+		Disk:   fdbv1beta2.FoundationDBStatusProcessDisk{TotalBytes: 100 * 1024 * 1024 * 1024, FreeBytes: 100*1024*1024*1024 - diskUsed},
+	}
+
+	if queue > 0 {
+		role := fdbv1beta2.ProcessRoleStorage
+		if processClass == fdbv1beta2.ProcessClassLog {
+			role = fdbv1beta2.ProcessRoleLog
+		}
+
+		process.Roles = []fdbv1beta2.FoundationDBStatusProcessRoleInfo{
+			{
+				Role:         string(role),
+				InputBytes:   fdbv1beta2.FoundationDBStatusCounter{Counter: 10 * queue},
+				DurableBytes: fdbv1beta2.FoundationDBStatusCounter{Counter: 9 * queue},
+			},
+		}
+	}
+
+	return process
+}
+
+func quantityStrings(resources corev1.ResourceList) map[corev1.ResourceName]string {
+	result := make(map[corev1.ResourceName]string, len(resources))
+	for name, quantity := range resources {
+		result[name] = quantity.String()
+	}
+
+	return result
+}
+
+var _ = Describe("recommendations", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var status *fdbv1beta2.FoundationDBStatus
+	var now time.Time
+
+	BeforeEach(func() {
+		cluster = &fdbv1beta2.FoundationDBCluster{
+			Spec: fdbv1beta2.FoundationDBClusterSpec{
+				ResourceRecommendations: fdbv1beta2.ResourceRecommendationOptions{
+					Enabled: pointer.Bool(true),
+				},
+			},
+		}
+
+		now = time.Unix(1700000000, 0)
+		status = &fdbv1beta2.FoundationDBStatus{
+			Cluster: fdbv1beta2.FoundationDBStatusClusterInfo{
+				Processes: map[fdbv1beta2.ProcessGroupID]fdbv1beta2.FoundationDBStatusProcessInfo{
+					// Two storage processes share the storage-1 Pod.
+					"storage-1-1": generateProcess(fdbv1beta2.ProcessClassStorage, "storage-1", 0.5, 1000*1024*1024, 10*1024*1024*1024, 100),
+					"storage-1-2": generateProcess(fdbv1beta2.ProcessClassStorage, "storage-1", 0.3, 1000*1024*1024, 10*1024*1024*1024, 200),
+					"storage-2-1": generateProcess(fdbv1beta2.ProcessClassStorage, "storage-2", 0.9, 500*1024*1024, 20*1024*1024*1024, 50),
+					"log-1":       generateProcess(fdbv1beta2.ProcessClassLog, "log-1", 0.2, 2000*1024*1024, 1024*1024*1024, 1000),
+					"stateless-1": generateProcess(fdbv1beta2.ProcessClassStateless, "stateless-1", 0.1, 0, 0, 0),
+				},
+			},
+		}
+	})
+
+	When("no recommendations exist", func() {
+		var recommendations []fdbv1beta2.ResourceRecommendation
+
+		BeforeEach(func() {
+			recommendations = UpdateRecommendations(cluster, nil, status, now)
+		})
+
+		It("should create a recommendation for every process class", func() {
+			Expect(recommendations).To(HaveLen(3))
+			Expect(recommendations[0].ProcessClass).To(Equal(fdbv1beta2.ProcessClassLog))
+			Expect(recommendations[1].ProcessClass).To(Equal(fdbv1beta2.ProcessClassStateless))
+			Expect(recommendations[2].ProcessClass).To(Equal(fdbv1beta2.ProcessClassStorage))
+			for _, recommendation := range recommendations {
+				Expect(recommendation.Samples).To(Equal(1))
+				Expect(recommendation.LastSampleTimestamp).To(Equal(now.Unix()))
+			}
+		})
+
+		It("should use the usage of the busiest Pod", func() {
+			usage := recommendations[2].Usage
+			// The CPU and memory of the processes in the storage-2 Pod are
+			// summed up, the disk is shared.
+			Expect(usage.PeakCPUMillicores).To(BeNumerically("==", 900))
+			Expect(usage.PeakMemoryBytes).To(BeNumerically("==", 2000*1024*1024))
+			Expect(usage.PeakDiskUsedBytes).To(BeNumerically("==", 20*1024*1024*1024))
+			Expect(usage.PeakQueueBytes).To(BeNumerically("==", 300))
+			Expect(usage.AverageQueueBytes).To(BeNumerically("==", 300))
+		})
+
+		It("should recommend resources with headroom", func() {
+			Expect(quantityStrings(recommendations[2].Requests)).To(Equal(map[corev1.ResourceName]string{
+				corev1.ResourceCPU:     "1080m",
+				corev1.ResourceMemory:  "2400Mi",
+				corev1.ResourceStorage: "24Gi",
+			}))
+			Expect(quantityStrings(recommendations[2].Limits)).To(Equal(map[corev1.ResourceName]string{
+				corev1.ResourceMemory: "2400Mi",
+			}))
+		})
+
+		It("should only recommend the CPU if no memory or disk is used", func() {
+			Expect(quantityStrings(recommendations[1].Requests)).To(Equal(map[corev1.ResourceName]string{
+				corev1.ResourceCPU: "120m",
+			}))
+			Expect(recommendations[1].Limits).To(BeNil())
+		})
+
+		When("a new sample is added within the sample interval", func() {
+			It("should not change the recommendations", func() {
+				status.Cluster.Processes["log-1"] = generateProcess(fdbv1beta2.ProcessClassLog, "log-1", 1, 2000*1024*1024, 1024*1024*1024, 1000)
+				Expect(UpdateRecommendations(cluster, recommendations, status, now.Add(time.Minute))).To(Equal(recommendations))
+			})
+		})
+
+		When("a new sample is added after the sample interval", func() {
+			var updated []fdbv1beta2.ResourceRecommendation
+
+			BeforeEach(func() {
+				status.Cluster.Processes["log-1"] = generateProcess(fdbv1beta2.ProcessClassLog, "log-1", 0.1, 1000*1024*1024, 1024*1024*1024, 2000)
+				updated = UpdateRecommendations(cluster, recommendations, status, now.Add(10*time.Minute))
+			})
+
+			It("should update the average and let the peak decay", func() {
+				usage := updated[0].Usage
+				Expect(updated[0].Samples).To(Equal(2))
+				Expect(usage.PeakCPUMillicores).To(BeNumerically("==", 198))
+				Expect(usage.AverageCPUMillicores).To(BeNumerically("==", 150))
+				Expect(usage.PeakMemoryBytes).To(BeNumerically("==", 2000*1024*1024-20*1024*1024))
+				Expect(usage.PeakQueueBytes).To(BeNumerically("==", 2000))
+				Expect(usage.AverageQueueBytes).To(BeNumerically("==", 1500))
+			})
+
+			It("should not modify the previous recommendations", func() {
+				Expect(recommendations[0].Samples).To(Equal(1))
+			})
+		})
+
+		When("a process class is missing in the status", func() {
+			It("should keep the previous recommendation", func() {
+				delete(status.Cluster.Processes, "log-1")
+				updated := UpdateRecommendations(cluster, recommendations, status, now.Add(10*time.Minute))
+				Expect(updated).To(HaveLen(3))
+				Expect(updated[0]).To(Equal(recommendations[0]))
+			})
+		})
+	})
+
+	When("the headroom is changed", func() {
+		BeforeEach(func() {
+			cluster.Spec.ResourceRecommendations.HeadroomPercent = pointer.Int(0)
+		})
+
+		It("should recommend the observed peak", func() {
+			recommendations := UpdateRecommendations(cluster, nil, status, now)
+			cpu := recommendations[0].Requests[corev1.ResourceCPU]
+			Expect(cpu.String()).To(Equal("200m"))
+		})
+	})
+
+	When("the status contains no processes", func() {
+		It("should return no recommendations", func() {
+			Expect(UpdateRecommendations(cluster, nil, &fdbv1beta2.FoundationDBStatus{}, now)).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,98 @@
+/*
+ * reconciliation_history.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// ReconciliationHistoryKey defines the key name in the reconciliation history ConfigMap
+	ReconciliationHistoryKey = "history"
+)
+
+// GetReconciliationHistoryConfigMapName returns the name of the ConfigMap that contains the reconciliation history of
+// the cluster.
+func GetReconciliationHistoryConfigMapName(cluster *fdbv1beta2.FoundationDBCluster) string {
+	return fmt.Sprintf("%s-reconciliation-history", cluster.Name)
+}
+
+// GetReconciliationHistoryConfigMap builds an empty ConfigMap for the reconciliation history of the cluster. The
+// ConfigMap doesn't get the labels of the cluster, as it is only used for debugging and should not be selected together
+// with the other resources of the cluster.
+func GetReconciliationHistoryConfigMap(cluster *fdbv1beta2.FoundationDBCluster) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            GetReconciliationHistoryConfigMapName(cluster),
+			Namespace:       cluster.Namespace,
+			OwnerReferences: BuildOwnerReference(cluster.TypeMeta, cluster.ObjectMeta),
+		},
+		Data: map[string]string{},
+	}
+}
+
+// GetReconciliationHistory returns the reconciliation history from the ConfigMap, starting with the oldest entry.
+func GetReconciliationHistory(configMap *corev1.ConfigMap) ([]fdbv1beta2.ReconciliationHistoryEntry, error) {
+	rawHistory, ok := configMap.Data[ReconciliationHistoryKey]
+	if !ok || rawHistory == "" {
+		return nil, nil
+	}
+
+	var history []fdbv1beta2.ReconciliationHistoryEntry
+	err := json.Unmarshal([]byte(rawHistory), &history)
+	if err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// AddReconciliationHistoryEntry adds the entry to the reconciliation history in the ConfigMap and removes the oldest
+// entries if the history has more entries than allowed by automationOptions.reconciliationHistorySize.
+func AddReconciliationHistoryEntry(cluster *fdbv1beta2.FoundationDBCluster, configMap *corev1.ConfigMap, entry fdbv1beta2.ReconciliationHistoryEntry) error {
+	history, err := GetReconciliationHistory(configMap)
+	if err != nil {
+		return err
+	}
+
+	history = append(history, entry)
+	size := cluster.GetReconciliationHistorySize()
+	if len(history) > size {
+		history = history[len(history)-size:]
+	}
+
+	rawHistory, err := json.Marshal(history)
+	if err != nil {
+		return err
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	configMap.Data[ReconciliationHistoryKey] = string(rawHistory)
+
+	return nil
+}
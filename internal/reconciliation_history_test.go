@@ -0,0 +1,111 @@
+/*
+ * reconciliation_history_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package internal
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("reconciliation_history", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var configMap *corev1.ConfigMap
+
+	BeforeEach(func() {
+		cluster = CreateDefaultCluster()
+		configMap = GetReconciliationHistoryConfigMap(cluster)
+	})
+
+	When("building the ConfigMap", func() {
+		It("should use the name of the cluster", func() {
+			Expect(configMap.Name).To(Equal("operator-test-1-reconciliation-history"))
+			Expect(configMap.Namespace).To(Equal(cluster.Namespace))
+		})
+
+		It("should be owned by the cluster", func() {
+			Expect(configMap.OwnerReferences).To(HaveLen(1))
+			Expect(configMap.OwnerReferences[0].Name).To(Equal(cluster.Name))
+		})
+
+		It("should not have the labels of the cluster", func() {
+			Expect(configMap.Labels).To(BeEmpty())
+		})
+	})
+
+	When("the ConfigMap has no history", func() {
+		It("should return an empty history", func() {
+			history, err := GetReconciliationHistory(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(BeEmpty())
+		})
+	})
+
+	When("the ConfigMap has an invalid history", func() {
+		BeforeEach(func() {
+			configMap.Data[ReconciliationHistoryKey] = "{"
+		})
+
+		It("should return an error", func() {
+			_, err := GetReconciliationHistory(configMap)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	When("adding entries to the history", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.ReconciliationHistorySize = pointer.Int(3)
+
+			for generation := int64(1); generation <= 5; generation++ {
+				err := AddReconciliationHistoryEntry(cluster, configMap, fdbv1beta2.ReconciliationHistoryEntry{
+					Generation: generation,
+					Result:     fdbv1beta2.ReconciliationResultTerminatedEarly,
+					Requeue: &fdbv1beta2.ReconciliationRequeue{
+						SubReconciler: "controllers.addPods",
+						Message:       "Waiting for Pods",
+						DelaySeconds:  15,
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should only keep the most recent entries", func() {
+			history, err := GetReconciliationHistory(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(HaveLen(3))
+			Expect(history[0].Generation).To(Equal(int64(3)))
+			Expect(history[2].Generation).To(Equal(int64(5)))
+		})
+
+		It("should keep the requeue of the entries", func() {
+			history, err := GetReconciliationHistory(configMap)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history[2].Requeue).To(Equal(&fdbv1beta2.ReconciliationRequeue{
+				SubReconciler: "controllers.addPods",
+				Message:       "Waiting for Pods",
+				DelaySeconds:  15,
+			}))
+		})
+	})
+})
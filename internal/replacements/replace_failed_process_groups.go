@@ -46,8 +46,8 @@ func getMaxReplacements(cluster *fdbv1beta2.FoundationDBCluster, maxReplacements
 }
 
 // ReplaceFailedProcessGroups flags failed processes groups for removal and returns an indicator
-// of whether any processes were thus flagged.
-func ReplaceFailedProcessGroups(log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient) bool {
+// of whether any processes were thus flagged. The failure detection time is measured relative to the provided time.
+func ReplaceFailedProcessGroups(log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, adminClient fdbadminclient.AdminClient, now time.Time) bool {
 	// Automatic replacements are disabled, so we don't have to check anything further
 	if !cluster.GetEnableAutomaticReplacements() {
 		return false
@@ -82,7 +82,7 @@ ProcessGroupLoop:
 			}
 		}
 
-		needsReplacement, missingTime := processGroupStatus.NeedsReplacement(cluster.GetFailureDetectionTimeSeconds())
+		needsReplacement, missingTime := processGroupStatus.NeedsReplacementAt(cluster.GetFailureDetectionTimeSeconds(), now)
 		if !needsReplacement {
 			continue
 		}
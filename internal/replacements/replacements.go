@@ -22,9 +22,11 @@ package replacements
 
 import (
 	"fmt"
+	"time"
 
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/operations"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
@@ -33,10 +35,9 @@ import (
 )
 
 // ReplaceMisconfiguredProcessGroups checks if the cluster has any misconfigured process groups that must be replaced.
-func ReplaceMisconfiguredProcessGroups(log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pvcMap map[fdbv1beta2.ProcessGroupID]corev1.PersistentVolumeClaim, podMap map[fdbv1beta2.ProcessGroupID]*corev1.Pod) (bool, error) {
-	hasReplacements := false
-
+func ReplaceMisconfiguredProcessGroups(log logr.Logger, cluster *fdbv1beta2.FoundationDBCluster, pvcMap map[fdbv1beta2.ProcessGroupID]corev1.PersistentVolumeClaim, podMap map[fdbv1beta2.ProcessGroupID]*corev1.Pod, now time.Time) (bool, error) {
 	maxReplacements := getMaxReplacements(cluster, cluster.GetMaxConcurrentReplacements())
+	requests := make([]operations.Request, 0)
 	for _, processGroup := range cluster.Status.ProcessGroups {
 		if maxReplacements <= 0 {
 			log.Info("Early abort, reached limit of concurrent replacements")
@@ -53,12 +54,11 @@ func ReplaceMisconfiguredProcessGroups(log logr.Logger, cluster *fdbv1beta2.Foun
 		if hasPVC {
 			needsPVCRemoval, err := processGroupNeedsRemovalForPVC(cluster, pvc, log)
 			if err != nil {
-				return hasReplacements, err
+				return false, err
 			}
 
 			if needsPVCRemoval && hasPod {
-				processGroup.MarkForRemoval()
-				hasReplacements = true
+				requests = append(requests, operations.Request{ProcessGroupID: processGroup.ProcessGroupID, Reason: "PVC is misconfigured"})
 				maxReplacements--
 				continue
 			}
@@ -75,16 +75,30 @@ func ReplaceMisconfiguredProcessGroups(log logr.Logger, cluster *fdbv1beta2.Foun
 
 		needsRemoval, err := processGroupNeedsRemoval(cluster, pod, processGroup, log)
 		if err != nil {
-			return hasReplacements, err
+			return false, err
 		}
 
 		if needsRemoval {
-			processGroup.MarkForRemoval()
-			hasReplacements = true
+			requests = append(requests, operations.Request{ProcessGroupID: processGroup.ProcessGroupID, Reason: "Pod is misconfigured"})
 			maxReplacements--
 		}
 	}
 
+	allowed := operations.Plan(cluster, fdbv1beta2.ProcessGroupOperationReplacement, requests, now)
+	hasReplacements := false
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if _, ok := allowed[processGroup.ProcessGroupID]; !ok {
+			continue
+		}
+
+		processGroup.MarkForRemoval()
+		hasReplacements = true
+	}
+
+	if len(allowed) < len(requests) {
+		log.Info("Waiting for the operation queue to replace process groups", "requested", len(requests), "allowed", len(allowed))
+	}
+
 	return hasReplacements, nil
 }
 
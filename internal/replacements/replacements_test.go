@@ -22,6 +22,7 @@ package replacements
 
 import (
 	"fmt"
+	"time"
 
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -643,7 +644,7 @@ var _ = Describe("replace_misconfigured_pods", func() {
 			})
 
 			It("should not have a replacements", func() {
-				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap)
+				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap, time.Now())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasReplacement).To(BeFalse())
 
@@ -666,7 +667,7 @@ var _ = Describe("replace_misconfigured_pods", func() {
 			})
 
 			It("should have two replacements", func() {
-				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap)
+				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap, time.Now())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasReplacement).To(BeTrue())
 
@@ -683,9 +684,40 @@ var _ = Describe("replace_misconfigured_pods", func() {
 			})
 		})
 
+		When("the operation queue is enabled", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.OperationQueue.Enabled = pointer.Bool(true)
+				// New process groups start with conditions for the missing resources, which would count as a disruption.
+				for _, processGroup := range cluster.Status.ProcessGroups {
+					processGroup.ProcessGroupConditions = nil
+				}
+			})
+
+			It("should only replace the process groups in one fault domain", func() {
+				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap, time.Now())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(hasReplacement).To(BeTrue())
+
+				cntReplacements := 0
+				for _, pGroup := range cluster.Status.ProcessGroups {
+					if !pGroup.IsMarkedForRemoval() {
+						continue
+					}
+
+					cntReplacements++
+				}
+
+				Expect(cntReplacements).To(BeNumerically("==", 1))
+				Expect(cluster.Status.Operations).To(HaveLen(len(cluster.Status.ProcessGroups)))
+				Expect(cluster.Status.Operations[0].Type).To(Equal(fdbv1beta2.ProcessGroupOperationReplacement))
+				Expect(cluster.Status.Operations[0].Allowed).To(BeTrue())
+				Expect(cluster.Status.Operations[1].Allowed).To(BeFalse())
+			})
+		})
+
 		When("Setting is unset", func() {
 			It("should replace all process groups", func() {
-				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap)
+				hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap, time.Now())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(hasReplacement).To(BeTrue())
 
@@ -727,7 +759,7 @@ var _ = Describe("replace_misconfigured_pods", func() {
 				})
 
 				It("should not have any replacements", func() {
-					hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap)
+					hasReplacement, err := ReplaceMisconfiguredProcessGroups(log, cluster, pvcMap, podMap, time.Now())
 					Expect(err).NotTo(HaveOccurred())
 					Expect(hasReplacement).To(BeFalse())
 
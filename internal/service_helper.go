@@ -21,8 +21,12 @@
 package internal
 
 import (
+	"fmt"
+	"net"
+
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 // GetHeadlessService builds a headless service for a FoundationDB cluster.
@@ -37,6 +41,92 @@ func GetHeadlessService(cluster *fdbv1beta2.FoundationDBCluster) *corev1.Service
 	service.ObjectMeta.Name = cluster.ObjectMeta.Name
 	service.Spec.ClusterIP = "None"
 	service.Spec.Selector = cluster.GetMatchLabels()
+	// If the cluster file uses DNS names, the DNS records of Pods that are not
+	// ready must be published, otherwise the coordinators can't be resolved
+	// while they are starting up.
+	service.Spec.PublishNotReadyAddresses = cluster.UseDNSInClusterFile()
+	setServiceIPFamily(cluster, service)
 
 	return service
 }
+
+// setServiceIPFamily restricts the service to the IP family of the pods, if
+// the cluster defines one. Otherwise the service uses the default IP family of
+// the Kubernetes cluster, which might not be the family that the FoundationDB
+// processes listen on in a dual-stack cluster.
+func setServiceIPFamily(cluster *fdbv1beta2.FoundationDBCluster, service *corev1.Service) {
+	family := cluster.Spec.Routing.PodIPFamily
+	if family == nil {
+		return
+	}
+
+	policy := corev1.IPFamilyPolicySingleStack
+	service.Spec.IPFamilyPolicy = &policy
+	if *family == 6 {
+		service.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	} else {
+		service.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol}
+	}
+}
+
+// GetServiceIP returns the cluster IP of a service that matches the IP family
+// of the cluster. If the cluster doesn't define an IP family, this returns the
+// primary cluster IP.
+func GetServiceIP(cluster *fdbv1beta2.FoundationDBCluster, service *corev1.Service) string {
+	family := cluster.Spec.Routing.PodIPFamily
+	if family == nil {
+		return service.Spec.ClusterIP
+	}
+
+	clusterIPs := service.Spec.ClusterIPs
+	if len(clusterIPs) == 0 && service.Spec.ClusterIP != "" {
+		clusterIPs = []string{service.Spec.ClusterIP}
+	}
+
+	for _, clusterIP := range clusterIPs {
+		ip := net.ParseIP(clusterIP)
+		if ip != nil && MatchesIPFamily(ip, *family) {
+			return clusterIP
+		}
+	}
+
+	return ""
+}
+
+// CheckPodDNSName checks if the DNS name of a Pod resolves through the
+// headless service. The DNS records are only created for Pods that use the
+// headless service as subdomain and that are selected by the service.
+func CheckPodDNSName(cluster *fdbv1beta2.FoundationDBCluster, service *corev1.Service, pod *corev1.Pod, dnsName string) error {
+	if service.Spec.ClusterIP != corev1.ClusterIPNone {
+		return fmt.Errorf("service %s is not a headless service", service.Name)
+	}
+
+	expectedName := GetPodDNSName(cluster, pod.Name)
+	if dnsName != expectedName {
+		return fmt.Errorf("DNS name %s of pod %s doesn't match the expected name %s", dnsName, pod.Name, expectedName)
+	}
+
+	if pod.Spec.Hostname != pod.Name || pod.Spec.Subdomain != service.Name {
+		return fmt.Errorf("pod %s doesn't use the headless service %s as subdomain", pod.Name, service.Name)
+	}
+
+	if !labels.SelectorFromSet(service.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+		return fmt.Errorf("pod %s is not selected by the headless service %s", pod.Name, service.Name)
+	}
+
+	if pod.Status.PodIP == "" {
+		return fmt.Errorf("pod %s has no IP address", pod.Name)
+	}
+
+	if service.Spec.PublishNotReadyAddresses {
+		return nil
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("pod %s is not ready and the headless service %s doesn't publish not ready addresses", pod.Name, service.Name)
+}
@@ -0,0 +1,80 @@
+/*
+ * metrics.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sharding
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	descShardDefaultLabels = []string{"group", "shard"}
+
+	descShardMembers = prometheus.NewDesc(
+		"fdb_operator_shard_members_total",
+		"the count of operator replicas with a valid lease in the shard group.",
+		descShardDefaultLabels,
+		nil,
+	)
+
+	descShardOwnedClusters = prometheus.NewDesc(
+		"fdb_operator_shard_owned_clusters_total",
+		"the count of clusters owned by this operator replica.",
+		descShardDefaultLabels,
+		nil,
+	)
+
+	descShardMembershipChanges = prometheus.NewDesc(
+		"fdb_operator_shard_membership_changes_total",
+		"the count of membership changes observed by this operator replica.",
+		descShardDefaultLabels,
+		nil,
+	)
+)
+
+type shardCollector struct {
+	sharder *Sharder
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *shardCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descShardMembers
+	ch <- descShardOwnedClusters
+	ch <- descShardMembershipChanges
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *shardCollector) Collect(ch chan<- prometheus.Metric) {
+	c.sharder.lock.RLock()
+	defer c.sharder.lock.RUnlock()
+
+	labelValues := []string{c.sharder.config.Group, c.sharder.config.Identity}
+	ch <- prometheus.MustNewConstMetric(descShardMembers, prometheus.GaugeValue, float64(len(c.sharder.members)), labelValues...)
+	ch <- prometheus.MustNewConstMetric(descShardOwnedClusters, prometheus.GaugeValue, float64(c.sharder.ownedClusters), labelValues...)
+	ch <- prometheus.MustNewConstMetric(descShardMembershipChanges, prometheus.CounterValue, float64(c.sharder.membershipChanges), labelValues...)
+}
+
+// InitMetrics initializes the metrics collector for the sharder.
+func InitMetrics(sharder *Sharder) {
+	metrics.Registry.MustRegister(
+		&shardCollector{sharder: sharder},
+	)
+}
@@ -0,0 +1,105 @@
+/*
+ * ring.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sharding
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// virtualNodesPerMember defines how many points every member gets on the hash
+// ring. More points result in a more even distribution of the clusters.
+const virtualNodesPerMember = 128
+
+// Ring implements a consistent hash ring that maps clusters to the members
+// of a sharded operator deployment. Adding or removing a member only moves the
+// clusters of the member's points on the ring.
+type Ring struct {
+	// hashes contains the sorted points of all members on the ring.
+	hashes []uint64
+
+	// owners maps the points on the ring to the members.
+	owners map[uint64]string
+}
+
+// NewRing creates a hash ring for the provided members.
+func NewRing(members []string) *Ring {
+	ring := &Ring{
+		hashes: make([]uint64, 0, len(members)*virtualNodesPerMember),
+		owners: make(map[uint64]string, len(members)*virtualNodesPerMember),
+	}
+
+	for _, member := range members {
+		for i := 0; i < virtualNodesPerMember; i++ {
+			hash := getHash(fmt.Sprintf("%s#%d", member, i))
+			// In the unlikely case of a hash collision the points will be
+			// assigned to the member that sorts first, to make the
+			// assignment independent of the order of the members.
+			if owner, ok := ring.owners[hash]; ok {
+				if owner < member {
+					continue
+				}
+			} else {
+				ring.hashes = append(ring.hashes, hash)
+			}
+
+			ring.owners[hash] = member
+		}
+	}
+
+	sort.Slice(ring.hashes, func(i, j int) bool {
+		return ring.hashes[i] < ring.hashes[j]
+	})
+
+	return ring
+}
+
+// GetOwner returns the member that owns the key. If the ring has no members
+// an empty string is returned.
+func (ring *Ring) GetOwner(key string) string {
+	if len(ring.hashes) == 0 {
+		return ""
+	}
+
+	hash := getHash(key)
+	idx := sort.Search(len(ring.hashes), func(i int) bool {
+		return ring.hashes[i] >= hash
+	})
+
+	if idx == len(ring.hashes) {
+		idx = 0
+	}
+
+	return ring.owners[ring.hashes[idx]]
+}
+
+// GetClusterKey returns the key of the cluster on the hash ring.
+func GetClusterKey(namespace string, clusterName string) string {
+	return fmt.Sprintf("%s/%s", namespace, clusterName)
+}
+
+// getHash returns the position of the value on the ring.
+func getHash(value string) uint64 {
+	sum := sha256.Sum256([]byte(value))
+	return binary.BigEndian.Uint64(sum[:8])
+}
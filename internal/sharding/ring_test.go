@@ -0,0 +1,104 @@
+/*
+ * ring_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sharding
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ring", func() {
+	var keys []string
+
+	BeforeEach(func() {
+		keys = make([]string, 0, 1000)
+		for i := 0; i < 1000; i++ {
+			keys = append(keys, GetClusterKey(fmt.Sprintf("namespace-%d", i%10), fmt.Sprintf("cluster-%d", i)))
+		}
+	})
+
+	When("the ring has no members", func() {
+		It("should not return an owner", func() {
+			Expect(NewRing(nil).GetOwner(keys[0])).To(BeEmpty())
+		})
+	})
+
+	When("the ring has three members", func() {
+		var ring *Ring
+
+		BeforeEach(func() {
+			ring = NewRing([]string{"operator-0", "operator-1", "operator-2"})
+		})
+
+		It("should distribute the clusters between all members", func() {
+			counts := map[string]int{}
+			for _, key := range keys {
+				counts[ring.GetOwner(key)]++
+			}
+
+			Expect(counts).To(HaveLen(3))
+			for _, count := range counts {
+				Expect(count).To(BeNumerically("~", len(keys)/3, len(keys)/10))
+			}
+		})
+
+		It("should not depend on the order of the members", func() {
+			reordered := NewRing([]string{"operator-2", "operator-0", "operator-1"})
+			for _, key := range keys {
+				Expect(reordered.GetOwner(key)).To(Equal(ring.GetOwner(key)))
+			}
+		})
+
+		When("a member is added", func() {
+			It("should only move clusters to the new member", func() {
+				extended := NewRing([]string{"operator-0", "operator-1", "operator-2", "operator-3"})
+				moved := 0
+				for _, key := range keys {
+					newOwner := extended.GetOwner(key)
+					if newOwner == ring.GetOwner(key) {
+						continue
+					}
+
+					Expect(newOwner).To(Equal("operator-3"))
+					moved++
+				}
+
+				Expect(moved).To(BeNumerically("~", len(keys)/4, len(keys)/10))
+			})
+		})
+
+		When("a member is removed", func() {
+			It("should only move the clusters of the removed member", func() {
+				reduced := NewRing([]string{"operator-0", "operator-2"})
+				for _, key := range keys {
+					if ring.GetOwner(key) == "operator-1" {
+						Expect(reduced.GetOwner(key)).To(BeElementOf("operator-0", "operator-2"))
+						continue
+					}
+
+					Expect(reduced.GetOwner(key)).To(Equal(ring.GetOwner(key)))
+				}
+			})
+		})
+	})
+})
@@ -0,0 +1,745 @@
+/*
+ * sharder.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ShardGroupLabel defines the label that groups the leases of the members of
+// a sharded operator deployment.
+const ShardGroupLabel = "foundationdb.org/operator-shard-group"
+
+// ClusterLeaseGroupLabel defines the label that groups the cluster leases of
+// a sharded operator deployment.
+const ClusterLeaseGroupLabel = "foundationdb.org/operator-shard-cluster-lease-group"
+
+// ClusterLeaseAnnotation defines the annotation that contains the cluster of
+// a cluster lease.
+const ClusterLeaseAnnotation = "foundationdb.org/operator-shard-cluster"
+
+// Config defines the configuration of a Sharder.
+type Config struct {
+	// Namespace defines the namespace of the leases.
+	Namespace string
+
+	// Group defines the name of the sharded deployment. All operator
+	// replicas with the same group share the clusters.
+	Group string
+
+	// Identity defines the unique identity of this operator replica.
+	Identity string
+
+	// LeaseDuration defines how long a lease is valid without being renewed.
+	// The lease is renewed every third of the duration.
+	LeaseDuration time.Duration
+
+	// ClusterSelector selects the clusters that are managed by the operator.
+	ClusterSelector labels.Selector
+}
+
+// listener receives an event for every object of its list type when the
+// membership changes.
+type listener struct {
+	// list defines the type of objects that are enqueued.
+	list client.ObjectList
+
+	// events receives the events for the objects.
+	events chan event.GenericEvent
+}
+
+// ringHistoryEntry contains a hash ring that was replaced because the
+// membership changed or because the lease of this replica expired.
+type ringHistoryEntry struct {
+	// ring defines the replaced ring. A nil ring means that this replica
+	// didn't own any clusters, e.g. before the first sync or while its lease
+	// was expired.
+	ring *Ring
+
+	// replacedAt defines when the ring was replaced.
+	replacedAt time.Time
+}
+
+// clusterLease contains the state of a cluster lease that is held by this
+// replica.
+type clusterLease struct {
+	// namespace defines the namespace of the cluster.
+	namespace string
+
+	// clusterName defines the name of the cluster.
+	clusterName string
+
+	// inFlight contains the number of running reconciliations for the
+	// cluster.
+	inFlight int
+
+	// renewedAt defines when the lease was last renewed successfully.
+	renewedAt time.Time
+}
+
+// Sharder partitions the clusters between the replicas of a sharded operator
+// deployment. Every replica renews its own lease and the replicas with a valid
+// lease are placed on a consistent hash ring that defines which replica owns
+// a cluster.
+type Sharder struct {
+	// client is used to update the leases and to list the clusters.
+	client client.Client
+
+	// apiReader is used to read the leases without a cache, as the leases
+	// might be in a namespace that is not watched by the operator.
+	apiReader client.Reader
+
+	// config defines the configuration of the sharder.
+	config Config
+
+	// log implementation for logging output
+	log logr.Logger
+
+	// lock protects the fields below.
+	lock sync.RWMutex
+
+	// members contains the sorted identities of all live members.
+	members []string
+
+	// ring maps the clusters to the members.
+	ring *Ring
+
+	// previousRings contains the rings that were replaced within the last
+	// lease duration. A cluster that was owned by another member in one of
+	// those rings is not reconciled until the handoff grace period is over,
+	// so that the previous owner observed the new membership.
+	previousRings []ringHistoryEntry
+
+	// lastRenewal defines when the lease of this replica was last renewed
+	// successfully. If the lease was not renewed within the lease duration,
+	// the other members consider this replica as gone and take over its
+	// clusters.
+	lastRenewal time.Time
+
+	// now returns the current time.
+	now func() time.Time
+
+	// ownedClusters contains the number of clusters owned by this replica.
+	ownedClusters int
+
+	// membershipChanges counts how often the membership changed.
+	membershipChanges int
+
+	// listeners receive events when the membership changes.
+	listeners []listener
+
+	// clusterLeases contains the cluster leases that are held by this
+	// replica.
+	clusterLeases map[string]*clusterLease
+
+	// clusterLeaseLock serializes the updates of the cluster leases. It must
+	// be acquired before the lock.
+	clusterLeaseLock sync.Mutex
+}
+
+// NewSharder creates a new Sharder. The sharder will not own any clusters
+// until it has synced the membership for the first time.
+func NewSharder(kubeClient client.Client, apiReader client.Reader, log logr.Logger, config Config) *Sharder {
+	if config.ClusterSelector == nil {
+		config.ClusterSelector = labels.Everything()
+	}
+
+	return &Sharder{
+		client:        kubeClient,
+		apiReader:     apiReader,
+		config:        config,
+		log:           log.WithName("sharding").WithValues("group", config.Group, "identity", config.Identity),
+		now:           time.Now,
+		clusterLeases: map[string]*clusterLease{},
+	}
+}
+
+// OwnsCluster returns true if this replica owns the cluster. A nil Sharder
+// owns all clusters. A replica doesn't own any clusters if its lease was not
+// renewed within the lease duration, as the other members might have taken
+// over its clusters. A cluster that was newly assigned to this replica is
+// only owned after the handoff grace period of one lease duration, so that
+// the previous owner stopped reconciling it.
+func (sharder *Sharder) OwnsCluster(namespace string, clusterName string) bool {
+	if sharder == nil {
+		return true
+	}
+
+	sharder.lock.RLock()
+	defer sharder.lock.RUnlock()
+
+	if sharder.ring == nil {
+		return false
+	}
+
+	now := sharder.now()
+	if now.Sub(sharder.lastRenewal) >= sharder.config.LeaseDuration {
+		return false
+	}
+
+	key := GetClusterKey(namespace, clusterName)
+	if sharder.ring.GetOwner(key) != sharder.config.Identity {
+		return false
+	}
+
+	for _, entry := range sharder.previousRings {
+		if now.Sub(entry.replacedAt) >= sharder.config.LeaseDuration {
+			continue
+		}
+
+		if entry.ring == nil || entry.ring.GetOwner(key) != sharder.config.Identity {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AcquireCluster acquires the lease of the cluster for a reconciliation. The
+// lease is held as long as this replica owns the cluster or a reconciliation
+// of the cluster is running, so a new owner only reconciles the cluster after
+// the reconciliations of the previous owner are finished. This returns false
+// if the lease is held by another replica. A nil Sharder acquires all
+// clusters. Every successful call must be followed by a call to
+// ReleaseCluster once the reconciliation is finished.
+func (sharder *Sharder) AcquireCluster(ctx context.Context, namespace string, clusterName string) (bool, error) {
+	if sharder == nil {
+		return true, nil
+	}
+
+	sharder.clusterLeaseLock.Lock()
+	defer sharder.clusterLeaseLock.Unlock()
+
+	key := GetClusterKey(namespace, clusterName)
+	now := sharder.now()
+
+	sharder.lock.Lock()
+	current, held := sharder.clusterLeases[key]
+	if held && now.Sub(current.renewedAt) < sharder.config.LeaseDuration {
+		current.inFlight++
+		sharder.lock.Unlock()
+		return true, nil
+	}
+	sharder.lock.Unlock()
+
+	acquired, err := sharder.updateClusterLease(ctx, key, now)
+	if err != nil || !acquired {
+		return false, err
+	}
+
+	sharder.lock.Lock()
+	defer sharder.lock.Unlock()
+
+	if !held {
+		current = &clusterLease{namespace: namespace, clusterName: clusterName}
+		sharder.clusterLeases[key] = current
+	}
+	current.renewedAt = now
+	current.inFlight++
+
+	return true, nil
+}
+
+// ReleaseCluster marks the reconciliation of the cluster as finished. The
+// lease of the cluster is deleted if this replica doesn't own the cluster
+// anymore and no other reconciliation of the cluster is running.
+func (sharder *Sharder) ReleaseCluster(ctx context.Context, namespace string, clusterName string) {
+	if sharder == nil {
+		return
+	}
+
+	sharder.clusterLeaseLock.Lock()
+	defer sharder.clusterLeaseLock.Unlock()
+
+	key := GetClusterKey(namespace, clusterName)
+	sharder.lock.Lock()
+	current, held := sharder.clusterLeases[key]
+	if held && current.inFlight > 0 {
+		current.inFlight--
+	}
+	idle := held && current.inFlight == 0
+	sharder.lock.Unlock()
+
+	if !idle || sharder.OwnsCluster(namespace, clusterName) {
+		return
+	}
+
+	sharder.deleteClusterLease(ctx, key)
+}
+
+// HoldsCluster returns true if this replica owns the cluster and holds its
+// lease. Reconciliations check this between their steps, so that they stop
+// once the cluster was assigned to another replica. A nil Sharder holds all
+// clusters.
+func (sharder *Sharder) HoldsCluster(namespace string, clusterName string) bool {
+	if sharder == nil {
+		return true
+	}
+
+	if !sharder.OwnsCluster(namespace, clusterName) {
+		return false
+	}
+
+	sharder.lock.RLock()
+	defer sharder.lock.RUnlock()
+
+	current, held := sharder.clusterLeases[GetClusterKey(namespace, clusterName)]
+	return held && sharder.now().Sub(current.renewedAt) < sharder.config.LeaseDuration
+}
+
+// GetMembers returns the identities of all live members.
+func (sharder *Sharder) GetMembers() []string {
+	sharder.lock.RLock()
+	defer sharder.lock.RUnlock()
+
+	return append([]string{}, sharder.members...)
+}
+
+// GetSource returns a source that emits an event for every object of the list
+// type when the membership changes, so that the clusters are reconciled by
+// their new owners.
+func (sharder *Sharder) GetSource(list client.ObjectList) source.Source {
+	sharder.lock.Lock()
+	defer sharder.lock.Unlock()
+
+	events := make(chan event.GenericEvent)
+	sharder.listeners = append(sharder.listeners, listener{list: list, events: events})
+
+	return &source.Channel{Source: events}
+}
+
+// NeedLeaderElection returns false, as every replica has to renew its lease.
+func (sharder *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start renews the lease and syncs the membership until the context is
+// cancelled. The lease is released when the sharder stops, so that the other
+// replicas take over the clusters without waiting for the lease to expire.
+func (sharder *Sharder) Start(ctx context.Context) error {
+	ticker := time.NewTicker(sharder.config.LeaseDuration / 3)
+	defer ticker.Stop()
+
+	for {
+		err := sharder.Sync(ctx)
+		if err != nil {
+			sharder.log.Error(err, "Error syncing shard membership")
+		}
+
+		select {
+		case <-ctx.Done():
+			sharder.releaseClusterLeases()
+			err = sharder.releaseLease()
+			if err != nil {
+				sharder.log.Error(err, "Error releasing lease")
+			}
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync renews the lease of this replica and updates the membership from the
+// live leases of the group.
+func (sharder *Sharder) Sync(ctx context.Context) error {
+	renewTime := sharder.now()
+	err := sharder.renewLease(ctx, renewTime)
+	if err != nil {
+		return err
+	}
+
+	sharder.lock.Lock()
+	// If the lease was expired, the other members might have taken over the
+	// clusters of this replica, so all clusters go through the handoff grace
+	// period again.
+	wasExpired := renewTime.Sub(sharder.lastRenewal) >= sharder.config.LeaseDuration
+	if wasExpired {
+		sharder.log.Info("Lease was expired, waiting for the handoff grace period", "lastRenewal", sharder.lastRenewal)
+		sharder.replaceRing(sharder.ring, nil, renewTime)
+	}
+	sharder.lastRenewal = renewTime
+	sharder.lock.Unlock()
+
+	members, err := sharder.getLiveMembers(ctx)
+	if err != nil {
+		return err
+	}
+
+	sharder.lock.Lock()
+	changed := !equality.Semantic.DeepEqual(sharder.members, members)
+	if changed {
+		sharder.log.Info("Shard membership changed", "previousMembers", sharder.members, "members", members)
+		sharder.members = members
+		sharder.replaceRing(NewRing(members), sharder.ring, renewTime)
+		sharder.membershipChanges++
+	}
+	listeners := sharder.listeners
+	sharder.lock.Unlock()
+
+	// The events are emitted in the background after the handoff grace
+	// period, as the newly owned clusters are not reconciled before. This
+	// also ensures that the lease is renewed in time if the controllers are
+	// not started yet.
+	if changed || wasExpired {
+		for _, currentListener := range listeners {
+			go func(currentListener listener) {
+				select {
+				case <-time.After(sharder.config.LeaseDuration):
+				case <-ctx.Done():
+					return
+				}
+
+				err := sharder.enqueueAll(ctx, currentListener)
+				if err != nil {
+					sharder.log.Error(err, "Error enqueuing objects after membership change")
+				}
+			}(currentListener)
+		}
+	}
+
+	sharder.renewClusterLeases(ctx)
+
+	return sharder.updateOwnedClusters(ctx)
+}
+
+// replaceRing sets the current ring and adds the previous ring to the ring
+// history. Entries that are older than the lease duration are removed from the
+// history. The caller must hold the lock.
+func (sharder *Sharder) replaceRing(ring *Ring, previousRing *Ring, now time.Time) {
+	previousRings := make([]ringHistoryEntry, 0, len(sharder.previousRings)+1)
+	for _, entry := range sharder.previousRings {
+		if now.Sub(entry.replacedAt) < sharder.config.LeaseDuration {
+			previousRings = append(previousRings, entry)
+		}
+	}
+
+	sharder.previousRings = append(previousRings, ringHistoryEntry{ring: previousRing, replacedAt: now})
+	sharder.ring = ring
+}
+
+// getLeaseName returns the name of the lease of this replica.
+func (sharder *Sharder) getLeaseName() string {
+	return fmt.Sprintf("%s-%s", sharder.config.Group, sharder.config.Identity)
+}
+
+// renewLease creates or renews the lease of this replica with the provided
+// renew time.
+func (sharder *Sharder) renewLease(ctx context.Context, renewTime time.Time) error {
+	now := metav1.NewMicroTime(renewTime)
+	lease := &coordinationv1.Lease{}
+	err := sharder.apiReader.Get(ctx, client.ObjectKey{Namespace: sharder.config.Namespace, Name: sharder.getLeaseName()}, lease)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return err
+		}
+
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: sharder.config.Namespace,
+				Name:      sharder.getLeaseName(),
+				Labels: map[string]string{
+					ShardGroupLabel: sharder.config.Group,
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(sharder.config.Identity),
+				LeaseDurationSeconds: pointer.Int32(int32(sharder.config.LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+
+		return sharder.client.Create(ctx, lease)
+	}
+
+	lease.Spec.HolderIdentity = pointer.String(sharder.config.Identity)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(sharder.config.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+
+	return sharder.client.Update(ctx, lease)
+}
+
+// releaseLease deletes the lease of this replica.
+func (sharder *Sharder) releaseLease() error {
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: sharder.config.Namespace,
+			Name:      sharder.getLeaseName(),
+		},
+	}
+
+	return client.IgnoreNotFound(sharder.client.Delete(context.Background(), lease))
+}
+
+// getClusterLeaseName returns the name of the lease of the cluster.
+func (sharder *Sharder) getClusterLeaseName(key string) string {
+	return fmt.Sprintf("%s-cluster-%x", sharder.config.Group, getHash(key))
+}
+
+// updateClusterLease creates or renews the lease of the cluster with the
+// provided renew time. This returns false if the lease is held by another
+// replica.
+func (sharder *Sharder) updateClusterLease(ctx context.Context, key string, renewTime time.Time) (bool, error) {
+	now := metav1.NewMicroTime(renewTime)
+	lease := &coordinationv1.Lease{}
+	err := sharder.apiReader.Get(ctx, client.ObjectKey{Namespace: sharder.config.Namespace, Name: sharder.getClusterLeaseName(key)}, lease)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return false, err
+		}
+
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: sharder.config.Namespace,
+				Name:      sharder.getClusterLeaseName(key),
+				Labels: map[string]string{
+					ClusterLeaseGroupLabel: sharder.config.Group,
+				},
+				Annotations: map[string]string{
+					ClusterLeaseAnnotation: key,
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       pointer.String(sharder.config.Identity),
+				LeaseDurationSeconds: pointer.Int32(int32(sharder.config.LeaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+
+		err = sharder.client.Create(ctx, lease)
+		if k8serrors.IsAlreadyExists(err) {
+			return false, nil
+		}
+
+		return err == nil, err
+	}
+
+	if lease.Spec.HolderIdentity != nil && *lease.Spec.HolderIdentity != sharder.config.Identity && isLeaseValid(*lease, renewTime) {
+		return false, nil
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != sharder.config.Identity {
+		lease.Spec.AcquireTime = &now
+	}
+	lease.Spec.HolderIdentity = pointer.String(sharder.config.Identity)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32(int32(sharder.config.LeaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+
+	err = sharder.client.Update(ctx, lease)
+	if k8serrors.IsConflict(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// renewClusterLeases renews the cluster leases that are held by this
+// replica. The leases of clusters that are not owned anymore and have no
+// running reconciliation are deleted.
+func (sharder *Sharder) renewClusterLeases(ctx context.Context) {
+	sharder.clusterLeaseLock.Lock()
+	defer sharder.clusterLeaseLock.Unlock()
+
+	sharder.lock.RLock()
+	leases := make(map[string]clusterLease, len(sharder.clusterLeases))
+	for key, current := range sharder.clusterLeases {
+		leases[key] = *current
+	}
+	sharder.lock.RUnlock()
+
+	for key, current := range leases {
+		if current.inFlight == 0 && !sharder.OwnsCluster(current.namespace, current.clusterName) {
+			sharder.deleteClusterLease(ctx, key)
+			continue
+		}
+
+		renewTime := sharder.now()
+		renewed, err := sharder.updateClusterLease(ctx, key, renewTime)
+		if err != nil {
+			sharder.log.Error(err, "Error renewing cluster lease", "cluster", key)
+			continue
+		}
+
+		if !renewed {
+			sharder.log.Info("Lost cluster lease to another replica", "cluster", key)
+			sharder.lock.Lock()
+			delete(sharder.clusterLeases, key)
+			sharder.lock.Unlock()
+			continue
+		}
+
+		sharder.lock.Lock()
+		if held, ok := sharder.clusterLeases[key]; ok {
+			held.renewedAt = renewTime
+		}
+		sharder.lock.Unlock()
+	}
+}
+
+// deleteClusterLease deletes the lease of the cluster, if it is held by this
+// replica, and stops tracking it. The caller must hold the clusterLeaseLock.
+func (sharder *Sharder) deleteClusterLease(ctx context.Context, key string) {
+	lease := &coordinationv1.Lease{}
+	err := sharder.apiReader.Get(ctx, client.ObjectKey{Namespace: sharder.config.Namespace, Name: sharder.getClusterLeaseName(key)}, lease)
+	if err == nil && pointer.StringDeref(lease.Spec.HolderIdentity, "") == sharder.config.Identity {
+		err = sharder.client.Delete(ctx, lease)
+	}
+
+	err = client.IgnoreNotFound(err)
+	if err != nil {
+		sharder.log.Error(err, "Error deleting cluster lease", "cluster", key)
+		return
+	}
+
+	sharder.lock.Lock()
+	delete(sharder.clusterLeases, key)
+	sharder.lock.Unlock()
+}
+
+// releaseClusterLeases deletes all cluster leases of this replica.
+func (sharder *Sharder) releaseClusterLeases() {
+	sharder.clusterLeaseLock.Lock()
+	defer sharder.clusterLeaseLock.Unlock()
+
+	sharder.lock.RLock()
+	keys := make([]string, 0, len(sharder.clusterLeases))
+	for key := range sharder.clusterLeases {
+		keys = append(keys, key)
+	}
+	sharder.lock.RUnlock()
+
+	for _, key := range keys {
+		sharder.deleteClusterLease(context.Background(), key)
+	}
+}
+
+// getLiveMembers returns the sorted identities of all members with a valid
+// lease. Expired leases of other members are deleted.
+func (sharder *Sharder) getLiveMembers(ctx context.Context) ([]string, error) {
+	leases := &coordinationv1.LeaseList{}
+	err := sharder.apiReader.List(ctx, leases, client.InNamespace(sharder.config.Namespace), client.MatchingLabels{ShardGroupLabel: sharder.config.Group})
+	if err != nil {
+		return nil, err
+	}
+
+	now := sharder.now()
+	members := make([]string, 0, len(leases.Items))
+	for idx, lease := range leases.Items {
+		if lease.Spec.HolderIdentity == nil {
+			continue
+		}
+
+		if isLeaseValid(lease, now) {
+			members = append(members, *lease.Spec.HolderIdentity)
+			continue
+		}
+
+		sharder.log.Info("Deleting expired lease", "lease", lease.Name, "holder", *lease.Spec.HolderIdentity)
+		err = sharder.client.Delete(ctx, &leases.Items[idx])
+		if client.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+	}
+
+	sort.Strings(members)
+
+	return members, nil
+}
+
+// isLeaseValid returns true if the lease was renewed within its duration.
+func isLeaseValid(lease coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return false
+	}
+
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).After(now)
+}
+
+// enqueueAll emits an event for every object of the listener's list type.
+// The reconcilers skip the objects of clusters that are owned by other
+// members.
+func (sharder *Sharder) enqueueAll(ctx context.Context, currentListener listener) error {
+	list := currentListener.list.DeepCopyObject().(client.ObjectList)
+	err := sharder.client.List(ctx, list)
+	if err != nil {
+		return err
+	}
+
+	objects, err := meta.ExtractList(list)
+	if err != nil {
+		return err
+	}
+
+	for _, object := range objects {
+		clientObject, ok := object.(client.Object)
+		if !ok {
+			continue
+		}
+
+		select {
+		case currentListener.events <- event.GenericEvent{Object: clientObject}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// updateOwnedClusters counts the clusters that are owned by this replica.
+func (sharder *Sharder) updateOwnedClusters(ctx context.Context) error {
+	clusters := &fdbv1beta2.FoundationDBClusterList{}
+	err := sharder.client.List(ctx, clusters, client.MatchingLabelsSelector{Selector: sharder.config.ClusterSelector})
+	if err != nil {
+		return err
+	}
+
+	ownedClusters := 0
+	for _, cluster := range clusters.Items {
+		if sharder.OwnsCluster(cluster.Namespace, cluster.Name) {
+			ownedClusters++
+		}
+	}
+
+	sharder.lock.Lock()
+	sharder.ownedClusters = ownedClusters
+	sharder.lock.Unlock()
+
+	return nil
+}
@@ -0,0 +1,405 @@
+/*
+ * sharder_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	mockclient "github.com/FoundationDB/fdb-kubernetes-operator/mock-kubernetes-client/client"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// testLeaseDuration defines the lease duration of the sharders in the tests.
+// The duration is kept short, as the events after a membership change are
+// emitted after the handoff grace period.
+const testLeaseDuration = 1 * time.Second
+
+var _ = Describe("sharder", func() {
+	var k8sClient *mockclient.MockClient
+	var sharders []*Sharder
+	var clusters []*fdbv1beta2.FoundationDBCluster
+	var now time.Time
+
+	newSharder := func(identity string) *Sharder {
+		sharder := NewSharder(k8sClient, k8sClient, logr.Discard(), Config{
+			Namespace:     "operator",
+			Group:         "test",
+			Identity:      identity,
+			LeaseDuration: testLeaseDuration,
+		})
+		sharder.now = func() time.Time {
+			return now
+		}
+
+		return sharder
+	}
+
+	syncAll := func() {
+		for _, sharder := range sharders {
+			Expect(sharder.Sync(context.TODO())).NotTo(HaveOccurred())
+		}
+	}
+
+	// waitForGracePeriod advances the clock by one lease duration and renews
+	// the leases in between, so that the leases stay valid.
+	waitForGracePeriod := func() {
+		now = now.Add(testLeaseDuration / 2)
+		syncAll()
+		now = now.Add(testLeaseDuration / 2)
+		syncAll()
+	}
+
+	getOwners := func(cluster *fdbv1beta2.FoundationDBCluster) []string {
+		var owners []string
+		for _, sharder := range sharders {
+			if sharder.OwnsCluster(cluster.Namespace, cluster.Name) {
+				owners = append(owners, sharder.config.Identity)
+			}
+		}
+
+		return owners
+	}
+
+	BeforeEach(func() {
+		now = time.Now().Truncate(time.Second)
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).NotTo(HaveOccurred())
+		Expect(fdbv1beta2.AddToScheme(scheme)).NotTo(HaveOccurred())
+		k8sClient = mockclient.NewMockClient(scheme)
+
+		clusters = nil
+		for i := 0; i < 30; i++ {
+			cluster := &fdbv1beta2.FoundationDBCluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      fmt.Sprintf("cluster-%d", i),
+					Namespace: "test",
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+			clusters = append(clusters, cluster)
+		}
+
+		sharders = []*Sharder{newSharder("operator-0"), newSharder("operator-1"), newSharder("operator-2")}
+	})
+
+	When("the membership is not synced", func() {
+		It("should not own any cluster", func() {
+			Expect(sharders[0].OwnsCluster("test", "cluster-0")).To(BeFalse())
+		})
+	})
+
+	When("the sharder is nil", func() {
+		It("should own all clusters", func() {
+			var sharder *Sharder
+			Expect(sharder.OwnsCluster("test", "cluster-0")).To(BeTrue())
+			Expect(sharder.HoldsCluster("test", "cluster-0")).To(BeTrue())
+
+			acquired, err := sharder.AcquireCluster(context.TODO(), "test", "cluster-0")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(acquired).To(BeTrue())
+		})
+	})
+
+	When("all members are synced", func() {
+		BeforeEach(func() {
+			syncAll()
+			// Sync the first members again to observe the members that
+			// joined later.
+			syncAll()
+		})
+
+		It("should create a lease for every member", func() {
+			leases := &coordinationv1.LeaseList{}
+			Expect(k8sClient.List(context.TODO(), leases, client.InNamespace("operator"), client.MatchingLabels{ShardGroupLabel: "test"})).NotTo(HaveOccurred())
+			Expect(leases.Items).To(HaveLen(3))
+		})
+
+		It("should have the same members in all sharders", func() {
+			for _, sharder := range sharders {
+				Expect(sharder.GetMembers()).To(Equal([]string{"operator-0", "operator-1", "operator-2"}))
+			}
+		})
+
+		It("should not own any cluster during the handoff grace period", func() {
+			for _, cluster := range clusters {
+				Expect(getOwners(cluster)).To(BeEmpty())
+			}
+		})
+
+		When("the handoff grace period is over", func() {
+			BeforeEach(func() {
+				waitForGracePeriod()
+			})
+
+			It("should assign every cluster to exactly one member", func() {
+				for _, cluster := range clusters {
+					Expect(getOwners(cluster)).To(HaveLen(1))
+				}
+
+				ownedClusters := 0
+				for _, sharder := range sharders {
+					ownedClusters += sharder.ownedClusters
+				}
+				Expect(ownedClusters).To(Equal(len(clusters)))
+			})
+
+			When("a cluster is acquired by its owner", func() {
+				var owner *Sharder
+				var other *Sharder
+
+				BeforeEach(func() {
+					for _, sharder := range sharders {
+						if sharder.OwnsCluster("test", "cluster-0") {
+							owner = sharder
+							continue
+						}
+
+						other = sharder
+					}
+
+					acquired, err := owner.AcquireCluster(context.TODO(), "test", "cluster-0")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(acquired).To(BeTrue())
+				})
+
+				It("should hold the cluster", func() {
+					Expect(owner.HoldsCluster("test", "cluster-0")).To(BeTrue())
+					Expect(other.HoldsCluster("test", "cluster-0")).To(BeFalse())
+
+					leases := &coordinationv1.LeaseList{}
+					Expect(k8sClient.List(context.TODO(), leases, client.InNamespace("operator"), client.MatchingLabels{ClusterLeaseGroupLabel: "test"})).NotTo(HaveOccurred())
+					Expect(leases.Items).To(HaveLen(1))
+					Expect(*leases.Items[0].Spec.HolderIdentity).To(Equal(owner.config.Identity))
+					Expect(leases.Items[0].Annotations).To(HaveKeyWithValue(ClusterLeaseAnnotation, "test/cluster-0"))
+				})
+
+				It("should not allow other members to acquire the cluster", func() {
+					acquired, err := other.AcquireCluster(context.TODO(), "test", "cluster-0")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(acquired).To(BeFalse())
+				})
+
+				It("should keep the lease after the reconciliation while it owns the cluster", func() {
+					owner.ReleaseCluster(context.TODO(), "test", "cluster-0")
+					waitForGracePeriod()
+					Expect(owner.HoldsCluster("test", "cluster-0")).To(BeTrue())
+
+					acquired, err := other.AcquireCluster(context.TODO(), "test", "cluster-0")
+					Expect(err).NotTo(HaveOccurred())
+					Expect(acquired).To(BeFalse())
+				})
+
+				When("the owner stops renewing the cluster lease", func() {
+					It("should allow other members to acquire the cluster after the lease expired", func() {
+						now = now.Add(2 * testLeaseDuration)
+						acquired, err := other.AcquireCluster(context.TODO(), "test", "cluster-0")
+						Expect(err).NotTo(HaveOccurred())
+						Expect(acquired).To(BeTrue())
+						Expect(owner.HoldsCluster("test", "cluster-0")).To(BeFalse())
+					})
+				})
+			})
+
+			When("clusters move to a new member during a reconciliation", func() {
+				var previousOwners map[string]*Sharder
+				var newMember *Sharder
+
+				BeforeEach(func() {
+					previousOwners = map[string]*Sharder{}
+					for _, cluster := range clusters {
+						for _, sharder := range sharders {
+							if !sharder.OwnsCluster(cluster.Namespace, cluster.Name) {
+								continue
+							}
+
+							acquired, err := sharder.AcquireCluster(context.TODO(), cluster.Namespace, cluster.Name)
+							Expect(err).NotTo(HaveOccurred())
+							Expect(acquired).To(BeTrue())
+							previousOwners[cluster.Name] = sharder
+						}
+					}
+
+					newMember = newSharder("operator-3")
+					sharders = append(sharders, newMember)
+					syncAll()
+					syncAll()
+					waitForGracePeriod()
+				})
+
+				It("should only hand over the clusters after the previous owner finished the reconciliation", func() {
+					movedClusters := 0
+					for _, cluster := range clusters {
+						if !newMember.OwnsCluster(cluster.Namespace, cluster.Name) {
+							continue
+						}
+
+						movedClusters++
+						previousOwner := previousOwners[cluster.Name]
+						Expect(previousOwner.HoldsCluster(cluster.Namespace, cluster.Name)).To(BeFalse())
+
+						acquired, err := newMember.AcquireCluster(context.TODO(), cluster.Namespace, cluster.Name)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(acquired).To(BeFalse())
+
+						previousOwner.ReleaseCluster(context.TODO(), cluster.Namespace, cluster.Name)
+						acquired, err = newMember.AcquireCluster(context.TODO(), cluster.Namespace, cluster.Name)
+						Expect(err).NotTo(HaveOccurred())
+						Expect(acquired).To(BeTrue())
+						Expect(newMember.HoldsCluster(cluster.Namespace, cluster.Name)).To(BeTrue())
+					}
+
+					Expect(movedClusters).To(BeNumerically(">", 0))
+				})
+			})
+
+			When("a member stops", func() {
+				var events chan event.GenericEvent
+				var previousOwners map[string]string
+
+				BeforeEach(func() {
+					previousOwners = map[string]string{}
+					for _, cluster := range clusters {
+						previousOwners[cluster.Name] = getOwners(cluster)[0]
+					}
+
+					channel, ok := sharders[0].GetSource(&fdbv1beta2.FoundationDBClusterList{}).(*source.Channel)
+					Expect(ok).To(BeTrue())
+					events = make(chan event.GenericEvent, len(clusters))
+					go func() {
+						for object := range channel.Source {
+							events <- object
+						}
+					}()
+
+					Expect(sharders[2].releaseLease()).NotTo(HaveOccurred())
+					sharders = sharders[:2]
+					syncAll()
+				})
+
+				It("should remove the member", func() {
+					Expect(sharders[0].GetMembers()).To(Equal([]string{"operator-0", "operator-1"}))
+					Expect(sharders[0].membershipChanges).To(Equal(3))
+				})
+
+				It("should only keep the clusters that didn't move during the handoff grace period", func() {
+					for _, cluster := range clusters {
+						owners := getOwners(cluster)
+						if previousOwners[cluster.Name] == "operator-2" {
+							Expect(owners).To(BeEmpty())
+							continue
+						}
+
+						Expect(owners).To(Equal([]string{previousOwners[cluster.Name]}))
+					}
+				})
+
+				It("should assign the clusters of the stopped member after the handoff grace period", func() {
+					waitForGracePeriod()
+					for _, cluster := range clusters {
+						Expect(getOwners(cluster)).To(HaveLen(1))
+					}
+				})
+
+				It("should enqueue all clusters after the handoff grace period", func() {
+					Consistently(events, testLeaseDuration/2).Should(BeEmpty())
+					Eventually(events, 5*testLeaseDuration).Should(HaveLen(len(clusters)))
+				})
+			})
+
+			When("the lease of a member is not renewed", func() {
+				BeforeEach(func() {
+					now = now.Add(testLeaseDuration / 2)
+					Expect(sharders[1].Sync(context.TODO())).NotTo(HaveOccurred())
+					Expect(sharders[2].Sync(context.TODO())).NotTo(HaveOccurred())
+					now = now.Add(testLeaseDuration / 2)
+				})
+
+				It("should not own any cluster", func() {
+					for _, cluster := range clusters {
+						Expect(sharders[0].OwnsCluster(cluster.Namespace, cluster.Name)).To(BeFalse())
+					}
+				})
+
+				When("the other members take over the clusters", func() {
+					BeforeEach(func() {
+						Expect(sharders[1].Sync(context.TODO())).NotTo(HaveOccurred())
+						Expect(sharders[2].Sync(context.TODO())).NotTo(HaveOccurred())
+					})
+
+					It("should remove the member", func() {
+						Expect(sharders[1].GetMembers()).To(Equal([]string{"operator-1", "operator-2"}))
+					})
+
+					When("the member renews its lease again", func() {
+						BeforeEach(func() {
+							Expect(sharders[0].Sync(context.TODO())).NotTo(HaveOccurred())
+						})
+
+						It("should wait for the handoff grace period", func() {
+							for _, cluster := range clusters {
+								Expect(sharders[0].OwnsCluster(cluster.Namespace, cluster.Name)).To(BeFalse())
+							}
+
+							waitForGracePeriod()
+							for _, cluster := range clusters {
+								Expect(getOwners(cluster)).To(HaveLen(1))
+							}
+						})
+					})
+				})
+			})
+		})
+
+		When("the lease of a member expires", func() {
+			BeforeEach(func() {
+				lease := &coordinationv1.Lease{}
+				Expect(k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "operator", Name: "test-operator-2"}, lease)).NotTo(HaveOccurred())
+				lease.Spec.RenewTime = &metav1.MicroTime{Time: now.Add(-1 * time.Minute)}
+				lease.Spec.LeaseDurationSeconds = pointer.Int32(15)
+				Expect(k8sClient.Update(context.TODO(), lease)).NotTo(HaveOccurred())
+
+				Expect(sharders[0].Sync(context.TODO())).NotTo(HaveOccurred())
+			})
+
+			It("should remove the member and delete the lease", func() {
+				Expect(sharders[0].GetMembers()).To(Equal([]string{"operator-0", "operator-1"}))
+
+				lease := &coordinationv1.Lease{}
+				err := k8sClient.Get(context.TODO(), client.ObjectKey{Namespace: "operator", Name: "test-operator-2"}, lease)
+				Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+	})
+})
@@ -0,0 +1,125 @@
+/*
+ * admin_client.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DatabaseClientProvider provides admin clients for the simulated database.
+type DatabaseClientProvider struct {
+	simulator *Simulator
+}
+
+// GetLockClient generates a client for working with locks through the database.
+func (provider DatabaseClientProvider) GetLockClient(cluster *fdbv1beta2.FoundationDBCluster) (fdbadminclient.LockClient, error) {
+	return mock.NewMockLockClient(cluster)
+}
+
+// GetAdminClient generates a client for performing administrative actions
+// against the simulated database.
+func (provider DatabaseClientProvider) GetAdminClient(cluster *fdbv1beta2.FoundationDBCluster, kubernetesClient client.Client) (fdbadminclient.AdminClient, error) {
+	mockClient, err := mock.NewMockAdminClientUncast(cluster, kubernetesClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AdminClient{AdminClient: mockClient, simulator: provider.simulator}, nil
+}
+
+// AdminClient is an admin client that reports the state of the simulated
+// database. The mock admin client provides the process information based on
+// the Pods, the coordinators and the configuration, and handles backups,
+// disaster recoveries and tenants.
+type AdminClient struct {
+	*mock.AdminClient
+	simulator *Simulator
+}
+
+// GetStatus gets the database's status with the processes, roles, data
+// distribution, recoveries and fault tolerance of the simulated database.
+func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
+	status, err := client.AdminClient.GetStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	err = client.simulator.applyStatus(client.AdminClient, status)
+	if err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// ConfigureDatabase changes the database configuration, which causes a
+// recovery once the database exists.
+func (client *AdminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) error {
+	err := client.AdminClient.ConfigureDatabase(configuration, newDatabase, version)
+	if err != nil {
+		return err
+	}
+
+	if !newDatabase {
+		client.simulator.startRecovery("configuration changed")
+	}
+
+	return nil
+}
+
+// ExcludeProcesses starts evacuating processes so that they can be removed
+// from the database. Excluding a process of the transaction system causes a
+// recovery.
+func (client *AdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+	err := client.AdminClient.ExcludeProcesses(addresses)
+	if err != nil {
+		return err
+	}
+
+	client.simulator.recordExclusion(client.AdminClient, addresses)
+	return nil
+}
+
+// CanSafelyRemove checks whether it is safe to remove the processes from the
+// database. A process is safe to remove once it is excluded, all of its data
+// was moved to other processes and it runs no role of the transaction
+// system. Addresses without a port match all processes with that IP.
+//
+// The list returned by this method will be the addresses that are *not*
+// safe to remove.
+func (client *AdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error) {
+	return client.simulator.getUnsafeAddresses(client.AdminClient, addresses), nil
+}
+
+// KillProcesses restarts processes. Restarting a process of the transaction
+// system causes a recovery.
+func (client *AdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+	err := client.AdminClient.KillProcesses(addresses)
+	if err != nil {
+		return err
+	}
+
+	client.simulator.restartProcesses(addresses)
+	return nil
+}
@@ -0,0 +1,395 @@
+/*
+ * database.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// roleMaster represents the master role in the simulated database.
+	roleMaster fdbv1beta2.ProcessRole = "master"
+	// roleClusterController represents the cluster controller role in the
+	// simulated database.
+	roleClusterController fdbv1beta2.ProcessRole = "cluster_controller"
+)
+
+// process represents a single fdbserver process in the simulated database.
+type process struct {
+	// id is the key of the process in the machine-readable status, which is
+	// based on the Pod name and the process number.
+	id string
+	// processGroupID is the process group the process belongs to.
+	processGroupID fdbv1beta2.ProcessGroupID
+	// processClass is the process class of the process.
+	processClass fdbv1beta2.ProcessClass
+	// address is the current address of the process.
+	address fdbv1beta2.ProcessAddress
+	// zone is the fault domain of the process, which is the zone ID that the
+	// process reports in its locality.
+	zone string
+	// startTime is the simulated time when the process was started.
+	startTime time.Time
+	// down is true if the process is not reporting to the database.
+	down bool
+	// downSince is the simulated time when the process went down.
+	downSince time.Time
+	// destroyed is true if the process group was removed, so the data of the
+	// process can't come back.
+	destroyed bool
+	// data is the number of data units that are stored on the process.
+	data int
+	// roles are the transaction system roles recruited on the process.
+	roles []fdbv1beta2.ProcessRole
+}
+
+// database represents the state of the simulated FoundationDB cluster.
+type database struct {
+	// processes contains all processes that hold data or run in a Pod.
+	processes map[string]*process
+	// failedZones contains the zones whose processes are not reachable.
+	failedZones map[string]fdbv1beta2.None
+	// seeded is true once the database was configured and the initial data
+	// was placed on the storage processes.
+	seeded bool
+	// recovering is true while the database is in a recovery.
+	recovering bool
+	// recoveryEnd is the simulated time when the current recovery completes.
+	recoveryEnd time.Time
+	// lastRecovered is the simulated time when the last recovery completed.
+	lastRecovered time.Time
+	// recoveries is the number of recoveries since the simulation started.
+	recoveries int
+}
+
+// newDatabase creates an empty simulated database.
+func newDatabase() *database {
+	return &database{
+		processes:   map[string]*process{},
+		failedZones: map[string]fdbv1beta2.None{},
+	}
+}
+
+// hasTransactionRole returns true if the process runs a role of the
+// transaction system, which requires a recovery when the process fails.
+func (process *process) hasTransactionRole() bool {
+	return len(process.roles) > 0
+}
+
+// isExcluded returns true if the address of the process is excluded.
+func (process *process) isExcluded(exclusions map[string]fdbv1beta2.None) bool {
+	if _, ok := exclusions[process.address.MachineAddress()]; ok {
+		return true
+	}
+
+	_, ok := exclusions[process.address.String()]
+	return ok
+}
+
+// getSortedProcesses returns the processes sorted by their ID, so that every
+// decision of the simulator is deterministic.
+func (db *database) getSortedProcesses() []*process {
+	processes := make([]*process, 0, len(db.processes))
+	for _, process := range db.processes {
+		processes = append(processes, process)
+	}
+
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].id < processes[j].id
+	})
+
+	return processes
+}
+
+// refresh updates the processes based on the Pods of the cluster. Processes
+// of Pods that are not running or that are in a failed zone are down, and
+// processes of removed process groups are destroyed. This returns true if a
+// process of the transaction system went down.
+func (db *database) refresh(cluster *fdbv1beta2.FoundationDBCluster, pods []corev1.Pod, now time.Time) (bool, error) {
+	running := map[string]fdbv1beta2.None{}
+	for index := range pods {
+		pod := &pods[index]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		processCount, err := internal.GetStorageServersPerPodForPod(pod)
+		if err != nil {
+			return false, err
+		}
+
+		substitutions, err := internal.GetSubstitutionsFromClusterAndPod(logr.Discard(), cluster, pod)
+		if err != nil {
+			return false, err
+		}
+
+		zone := substitutions["FDB_ZONE_ID"]
+		_, zoneFailed := db.failedZones[zone]
+
+		for processNumber := 1; processNumber <= processCount; processNumber++ {
+			id := fmt.Sprintf("%s-%d", pod.Name, processNumber)
+			current, ok := db.processes[id]
+			if !ok {
+				current = &process{
+					id:             id,
+					processGroupID: podmanager.GetProcessGroupID(cluster, pod),
+					processClass:   internal.GetProcessClassFromMeta(cluster, pod.ObjectMeta),
+					startTime:      now,
+					down:           true,
+					downSince:      now,
+				}
+				db.processes[id] = current
+			}
+
+			current.address = cluster.GetFullAddress(pod.Status.PodIP, processNumber)
+			current.zone = zone
+			current.destroyed = false
+
+			if pod.Status.Phase != corev1.PodRunning || zoneFailed {
+				continue
+			}
+
+			running[id] = fdbv1beta2.None{}
+			if current.down {
+				current.down = false
+				current.startTime = now
+			}
+		}
+	}
+
+	processGroups := map[fdbv1beta2.ProcessGroupID]fdbv1beta2.None{}
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		processGroups[processGroup.ProcessGroupID] = fdbv1beta2.None{}
+	}
+
+	needsRecovery := false
+	for _, current := range db.getSortedProcesses() {
+		if _, ok := running[current.id]; ok {
+			continue
+		}
+
+		if !current.down {
+			current.down = true
+			current.downSince = now
+			if current.hasTransactionRole() {
+				needsRecovery = true
+			}
+		}
+
+		if _, ok := processGroups[current.processGroupID]; !ok {
+			current.destroyed = true
+		}
+
+		if current.destroyed && current.data == 0 {
+			delete(db.processes, current.id)
+		}
+	}
+
+	return needsRecovery, nil
+}
+
+// seed places the initial data on the storage processes and recruits the
+// transaction system once the database is configured.
+func (db *database) seed(cluster *fdbv1beta2.FoundationDBCluster, dataPerProcess int, exclusions map[string]fdbv1beta2.None, now time.Time) {
+	for _, current := range db.getSortedProcesses() {
+		if current.processClass == fdbv1beta2.ProcessClassStorage && !current.down {
+			current.data = dataPerProcess
+		}
+	}
+
+	db.recruit(cluster, exclusions)
+	db.seeded = true
+	db.lastRecovered = now
+}
+
+// startRecovery starts a new recovery of the database.
+func (db *database) startRecovery(now time.Time, duration time.Duration) {
+	db.recovering = true
+	db.recoveryEnd = now.Add(duration)
+	db.recoveries++
+}
+
+// recruit places the transaction system roles on processes that are up and
+// not excluded.
+func (db *database) recruit(cluster *fdbv1beta2.FoundationDBCluster, exclusions map[string]fdbv1beta2.None) {
+	candidates := make([]*process, 0, len(db.processes))
+	for _, current := range db.getSortedProcesses() {
+		current.roles = nil
+		if !current.down && !current.isExcluded(exclusions) {
+			candidates = append(candidates, current)
+		}
+	}
+
+	logs := cluster.DesiredDatabaseConfiguration().Logs
+	for _, candidate := range candidates {
+		if logs == 0 {
+			break
+		}
+
+		if candidate.processClass == fdbv1beta2.ProcessClassLog || candidate.processClass == fdbv1beta2.ProcessClassTransaction {
+			candidate.roles = append(candidate.roles, fdbv1beta2.ProcessRoleLog)
+			logs--
+		}
+	}
+
+	for _, role := range []fdbv1beta2.ProcessRole{roleClusterController, roleMaster} {
+		var target *process
+		for _, candidate := range candidates {
+			if candidate.processClass == fdbv1beta2.ProcessClassStateless || candidate.processClass == fdbv1beta2.ProcessClassClusterController {
+				target = candidate
+				break
+			}
+		}
+
+		if target == nil && len(candidates) > 0 {
+			target = candidates[0]
+		}
+
+		if target != nil {
+			target.roles = append(target.roles, role)
+		}
+	}
+}
+
+// moveData moves data away from excluded processes and re-replicates the
+// data of processes that have been down for longer than the replication
+// delay or that were destroyed. Every source moves up to rate data units
+// per call to the least loaded healthy storage process in another zone.
+func (db *database) moveData(exclusions map[string]fdbv1beta2.None, rate int, replicationDelay time.Duration, now time.Time) {
+	for _, source := range db.getSortedProcesses() {
+		if source.data == 0 {
+			continue
+		}
+
+		needsMovement := source.destroyed ||
+			(source.down && now.Sub(source.downSince) >= replicationDelay) ||
+			(!source.down && source.isExcluded(exclusions))
+		if !needsMovement {
+			continue
+		}
+
+		var target *process
+		for _, candidate := range db.getSortedProcesses() {
+			if candidate.zone == source.zone || candidate.down || candidate.destroyed ||
+				candidate.processClass != fdbv1beta2.ProcessClassStorage || candidate.isExcluded(exclusions) {
+				continue
+			}
+
+			if target == nil || candidate.data < target.data {
+				target = candidate
+			}
+		}
+
+		if target == nil {
+			continue
+		}
+
+		amount := rate
+		if source.data < amount {
+			amount = source.data
+		}
+
+		source.data -= amount
+		target.data += amount
+
+		if source.destroyed && source.data == 0 {
+			delete(db.processes, source.id)
+		}
+	}
+}
+
+// getDegradedZones returns the zones that have processes which are down and
+// still have data that is not re-replicated.
+func (db *database) getDegradedZones() map[string]fdbv1beta2.None {
+	zones := map[string]fdbv1beta2.None{}
+	for _, current := range db.processes {
+		if current.data > 0 && (current.down || current.destroyed) {
+			zones[current.zone] = fdbv1beta2.None{}
+		}
+	}
+
+	return zones
+}
+
+// getFaultTolerance returns the number of zones that can fail without losing
+// data and without losing availability.
+func (db *database) getFaultTolerance(desiredFaultTolerance int) (int, int) {
+	dataFaultTolerance := desiredFaultTolerance - len(db.getDegradedZones())
+
+	logZones := map[string]fdbv1beta2.None{}
+	for _, current := range db.processes {
+		if current.down && current.hasTransactionRole() {
+			logZones[current.zone] = fdbv1beta2.None{}
+		}
+	}
+
+	availabilityFaultTolerance := desiredFaultTolerance - len(logZones)
+	if availabilityFaultTolerance > dataFaultTolerance {
+		availabilityFaultTolerance = dataFaultTolerance
+	}
+
+	return dataFaultTolerance, availabilityFaultTolerance
+}
+
+// getMovingData returns the number of data units that are waiting to be
+// moved.
+func (db *database) getMovingData(exclusions map[string]fdbv1beta2.None) int {
+	moving := 0
+	for _, current := range db.processes {
+		if current.down || current.destroyed || current.isExcluded(exclusions) {
+			moving += current.data
+		}
+	}
+
+	return moving
+}
+
+// getExcludedZones returns the zones of all processes that are excluded and
+// still part of the database.
+func (db *database) getExcludedZones(exclusions map[string]fdbv1beta2.None) []string {
+	zones := map[string]fdbv1beta2.None{}
+	for _, current := range db.processes {
+		if !current.destroyed && current.isExcluded(exclusions) {
+			zones[current.zone] = fdbv1beta2.None{}
+		}
+	}
+
+	return getSortedKeys(zones)
+}
+
+// getSortedKeys returns the keys of the set in sorted order.
+func getSortedKeys(set map[string]fdbv1beta2.None) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
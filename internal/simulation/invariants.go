@@ -0,0 +1,130 @@
+/*
+ * invariants.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// Snapshot describes the state of the simulation after a tick.
+type Snapshot struct {
+	// Time is the simulated time of the snapshot.
+	Time time.Time
+
+	// Cluster is the cluster resource at the time of the snapshot.
+	Cluster *fdbv1beta2.FoundationDBCluster
+
+	// DesiredFaultTolerance is the number of zones the cluster should be able
+	// to lose.
+	DesiredFaultTolerance int
+
+	// MaxZoneFailuresWithoutLosingData is the number of zones that can fail
+	// without losing data.
+	MaxZoneFailuresWithoutLosingData int
+
+	// MaxZoneFailuresWithoutLosingAvailability is the number of zones that
+	// can fail without losing availability.
+	MaxZoneFailuresWithoutLosingAvailability int
+
+	// FailedZones are the zones that were failed by the scenario.
+	FailedZones []string
+
+	// ExcludedZones are the zones with excluded processes that are still
+	// part of the database.
+	ExcludedZones []string
+
+	// Recoveries is the number of recoveries since the simulation started.
+	Recoveries int
+}
+
+// Invariant defines a condition that must hold after every tick of the
+// simulation.
+type Invariant struct {
+	// Name is the name of the invariant in the error messages.
+	Name string
+
+	// Check returns an error if the invariant does not hold for the snapshot.
+	Check func(snapshot Snapshot) error
+}
+
+// NeverLoseData checks that the database never loses data.
+func NeverLoseData() Invariant {
+	return Invariant{
+		Name: "NeverLoseData",
+		Check: func(snapshot Snapshot) error {
+			if snapshot.MaxZoneFailuresWithoutLosingData < 0 {
+				return fmt.Errorf("database lost data, fault tolerance is %d", snapshot.MaxZoneFailuresWithoutLosingData)
+			}
+
+			return nil
+		},
+	}
+}
+
+// NeverLoseFaultTolerance checks that the operator never takes down more
+// than one zone at a time. Every zone that was failed by the scenario may
+// reduce the fault tolerance by one more zone.
+func NeverLoseFaultTolerance() Invariant {
+	return Invariant{
+		Name: "NeverLoseFaultTolerance",
+		Check: func(snapshot Snapshot) error {
+			minimum := snapshot.DesiredFaultTolerance - 1 - len(snapshot.FailedZones)
+			if snapshot.MaxZoneFailuresWithoutLosingData < minimum {
+				return fmt.Errorf("fault tolerance is %d, expected at least %d", snapshot.MaxZoneFailuresWithoutLosingData, minimum)
+			}
+
+			return nil
+		},
+	}
+}
+
+// NeverExcludeMoreThanOneZone checks that processes of at most one zone are
+// excluded at the same time.
+func NeverExcludeMoreThanOneZone() Invariant {
+	return Invariant{
+		Name: "NeverExcludeMoreThanOneZone",
+		Check: func(snapshot Snapshot) error {
+			if len(snapshot.ExcludedZones) > 1 {
+				return fmt.Errorf("processes in %d zones are excluded: %v", len(snapshot.ExcludedZones), snapshot.ExcludedZones)
+			}
+
+			return nil
+		},
+	}
+}
+
+// MaxRecoveries checks that the database has at most the given number of
+// recoveries.
+func MaxRecoveries(maxRecoveries int) Invariant {
+	return Invariant{
+		Name: "MaxRecoveries",
+		Check: func(snapshot Snapshot) error {
+			if snapshot.Recoveries > maxRecoveries {
+				return fmt.Errorf("database had %d recoveries, expected at most %d", snapshot.Recoveries, maxRecoveries)
+			}
+
+			return nil
+		},
+	}
+}
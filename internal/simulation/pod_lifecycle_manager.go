@@ -0,0 +1,56 @@
+/*
+ * pod_lifecycle_manager.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"context"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podmanager"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PodLifecycleManager schedules the Pods of the simulated cluster onto the
+// simulated nodes. New Pods start in the pending phase and are running once
+// the startup duration of the simulator has passed.
+type PodLifecycleManager struct {
+	podmanager.StandardPodLifecycleManager
+	simulator *Simulator
+}
+
+// CreatePod creates a new Pod on a simulated node.
+func (manager PodLifecycleManager) CreatePod(ctx context.Context, r client.Client, pod *corev1.Pod) error {
+	node, err := manager.simulator.schedulePod(pod)
+	if err != nil {
+		return err
+	}
+
+	pod.Spec.NodeName = node
+	pod.Status.Phase = corev1.PodPending
+
+	err = manager.StandardPodLifecycleManager.CreatePod(ctx, r, pod)
+	if err != nil {
+		return err
+	}
+
+	manager.simulator.recordPodCreation(pod)
+	return nil
+}
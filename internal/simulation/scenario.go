@@ -0,0 +1,156 @@
+/*
+ * scenario.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// Scenario describes a sequence of steps that is run against a simulated
+// cluster, while the invariants are checked after every tick.
+type Scenario struct {
+	// Name is the name of the scenario in the error messages.
+	Name string
+
+	// Cluster is the cluster that is created by the CreateCluster step.
+	Cluster *fdbv1beta2.FoundationDBCluster
+
+	// Options defines the behavior of the simulated database.
+	Options Options
+
+	// Steps are run in order.
+	Steps []Step
+
+	// Invariants are checked after every tick.
+	Invariants []Invariant
+}
+
+// Step defines a single action of a scenario.
+type Step struct {
+	// Name describes the step in the error messages.
+	Name string
+
+	// Run performs the step.
+	Run func(simulator *Simulator) error
+}
+
+// Run runs all steps of the scenario against a new simulator. This returns
+// the simulator, so the final state can be inspected, and the first error of
+// a step.
+func (scenario Scenario) Run() (*Simulator, error) {
+	simulator, err := NewSimulator(scenario.Cluster, scenario.Options, scenario.Invariants...)
+	if err != nil {
+		return nil, err
+	}
+
+	for index, step := range scenario.Steps {
+		simulator.logger.Info("Running scenario step", "scenario", scenario.Name, "step", step.Name, "time", simulator.now)
+		err = step.Run(simulator)
+		if err != nil {
+			return simulator, fmt.Errorf("scenario %s failed in step %d (%s): %w", scenario.Name, index+1, step.Name, err)
+		}
+	}
+
+	return simulator, nil
+}
+
+// CreateCluster creates the cluster of the scenario.
+func CreateCluster() Step {
+	return Step{
+		Name: "create cluster",
+		Run: func(simulator *Simulator) error {
+			return simulator.client.Create(context.TODO(), simulator.cluster.DeepCopy())
+		},
+	}
+}
+
+// UpdateCluster changes the spec of the cluster.
+func UpdateCluster(description string, update func(cluster *fdbv1beta2.FoundationDBCluster)) Step {
+	return Step{
+		Name: fmt.Sprintf("update cluster: %s", description),
+		Run: func(simulator *Simulator) error {
+			cluster, err := simulator.GetCluster()
+			if err != nil {
+				return err
+			}
+
+			update(cluster)
+			return simulator.client.Update(context.TODO(), cluster)
+		},
+	}
+}
+
+// FailZone makes all processes in the zone unreachable.
+func FailZone(zone string) Step {
+	return Step{
+		Name: fmt.Sprintf("fail zone %s", zone),
+		Run: func(simulator *Simulator) error {
+			simulator.FailZone(zone)
+			return nil
+		},
+	}
+}
+
+// RecoverZone makes the processes in a failed zone reachable again.
+func RecoverZone(zone string) Step {
+	return Step{
+		Name: fmt.Sprintf("recover zone %s", zone),
+		Run: func(simulator *Simulator) error {
+			simulator.RecoverZone(zone)
+			return nil
+		},
+	}
+}
+
+// WaitForReconciliation runs reconciliations until the cluster is
+// reconciled, or until the maximum number of ticks from the options is
+// reached.
+func WaitForReconciliation() Step {
+	return Step{
+		Name: "wait for reconciliation",
+		Run: func(simulator *Simulator) error {
+			return simulator.RunUntilReconciled(simulator.options.MaxTicks)
+		},
+	}
+}
+
+// AdvanceTime runs reconciliations until the given simulated duration has
+// passed.
+func AdvanceTime(duration time.Duration) Step {
+	return Step{
+		Name: fmt.Sprintf("advance time by %s", duration),
+		Run: func(simulator *Simulator) error {
+			return simulator.RunFor(duration)
+		},
+	}
+}
+
+// Check checks a condition against the current state of the simulation.
+func Check(description string, check func(simulator *Simulator) error) Step {
+	return Step{
+		Name: fmt.Sprintf("check %s", description),
+		Run:  check,
+	}
+}
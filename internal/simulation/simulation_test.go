@@ -0,0 +1,258 @@
+/*
+ * simulation_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("simulation", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var invariants []Invariant
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		cluster.Spec.FaultDomain = fdbv1beta2.FoundationDBClusterFaultDomain{}
+		invariants = []Invariant{
+			NeverLoseData(),
+			NeverLoseFaultTolerance(),
+			NeverExcludeMoreThanOneZone(),
+		}
+	})
+
+	When("creating a cluster", func() {
+		It("should reconcile the cluster", func() {
+			simulator, err := Scenario{
+				Name:       "create",
+				Cluster:    cluster,
+				Steps:      []Step{CreateCluster(), WaitForReconciliation()},
+				Invariants: invariants,
+			}.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			current, err := simulator.GetCluster()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.Status.Configured).To(BeTrue())
+			Expect(current.Status.Health.Available).To(BeTrue())
+			Expect(current.Status.Health.FullReplication).To(BeTrue())
+		})
+	})
+
+	When("replacing a storage process group", func() {
+		It("should move the data before removing the process group", func() {
+			simulator, err := Scenario{
+				Name:    "replace storage",
+				Cluster: cluster,
+				Steps: []Step{
+					CreateCluster(),
+					WaitForReconciliation(),
+					UpdateCluster("remove storage-1", func(cluster *fdbv1beta2.FoundationDBCluster) {
+						cluster.Spec.ProcessGroupsToRemove = []fdbv1beta2.ProcessGroupID{"storage-1"}
+					}),
+					WaitForReconciliation(),
+				},
+				Invariants: append(invariants, MaxRecoveries(0)),
+			}.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			current, err := simulator.GetCluster()
+			Expect(err).NotTo(HaveOccurred())
+			processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(current.Status.ProcessGroups))
+			for _, processGroup := range current.Status.ProcessGroups {
+				processGroupIDs = append(processGroupIDs, processGroup.ProcessGroupID)
+			}
+			Expect(processGroupIDs).NotTo(ContainElement(fdbv1beta2.ProcessGroupID("storage-1")))
+			Expect(processGroupIDs).To(ContainElement(fdbv1beta2.ProcessGroupID("storage-5")))
+
+			totalData := 0
+			for _, current := range simulator.db.processes {
+				totalData += current.data
+			}
+			Expect(totalData).To(Equal(4 * 100))
+		})
+	})
+
+	When("a zone stays down for longer than the failure detection time", func() {
+		It("should replace the process groups in the zone", func() {
+			cluster.Spec.AutomationOptions.Replacements.FailureDetectionTimeSeconds = pointer.Int(600)
+
+			var failedProcessGroups []fdbv1beta2.ProcessGroupID
+			simulator, err := Scenario{
+				Name:    "replace failed zone",
+				Cluster: cluster,
+				Steps: []Step{
+					CreateCluster(),
+					WaitForReconciliation(),
+					FailZone("node-0"),
+					AdvanceTime(5 * time.Minute),
+					Check("the process groups are not replaced yet", func(simulator *Simulator) error {
+						current, err := simulator.GetCluster()
+						if err != nil {
+							return err
+						}
+
+						for _, processGroup := range current.Status.ProcessGroups {
+							if processGroup.IsMarkedForRemoval() {
+								return fmt.Errorf("process group %s was replaced before the failure detection time", processGroup.ProcessGroupID)
+							}
+
+							if processGroup.FaultDomain == "node-0" {
+								failedProcessGroups = append(failedProcessGroups, processGroup.ProcessGroupID)
+							}
+						}
+
+						if len(failedProcessGroups) == 0 {
+							return fmt.Errorf("no process groups were running in zone node-0")
+						}
+
+						return nil
+					}),
+					AdvanceTime(10 * time.Minute),
+				},
+				Invariants: invariants,
+			}.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			current, err := simulator.GetCluster()
+			Expect(err).NotTo(HaveOccurred())
+			processGroupIDs := make([]fdbv1beta2.ProcessGroupID, 0, len(current.Status.ProcessGroups))
+			for _, processGroup := range current.Status.ProcessGroups {
+				processGroupIDs = append(processGroupIDs, processGroup.ProcessGroupID)
+			}
+			for _, processGroupID := range failedProcessGroups {
+				Expect(processGroupIDs).NotTo(ContainElement(processGroupID))
+			}
+		})
+	})
+
+	When("upgrading a cluster while a zone is down", func() {
+		var version string
+
+		runUpgrade := func() *Simulator {
+			simulator, err := Scenario{
+				Name:    "upgrade with failed zone",
+				Cluster: cluster,
+				Steps: []Step{
+					CreateCluster(),
+					WaitForReconciliation(),
+					FailZone("node-0"),
+					UpdateCluster("upgrade", func(cluster *fdbv1beta2.FoundationDBCluster) {
+						cluster.Spec.Version = version
+					}),
+					AdvanceTime(5 * time.Minute),
+					// The processes in the failed zone are ignored once they are
+					// missing for longer than IgnoreMissingProcessesSeconds.
+					Check("the upgrade doesn't wait for the failed zone", func(simulator *Simulator) error {
+						current, err := simulator.GetCluster()
+						if err != nil {
+							return err
+						}
+
+						if current.Status.RunningVersion != version {
+							return fmt.Errorf("cluster was not upgraded while zone node-0 was down")
+						}
+
+						return nil
+					}),
+					RecoverZone("node-0"),
+					WaitForReconciliation(),
+				},
+				Invariants: invariants,
+			}.Run()
+			Expect(err).NotTo(HaveOccurred())
+
+			current, err := simulator.GetCluster()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(current.Status.RunningVersion).To(Equal(version))
+			Expect(simulator.Recoveries()).To(BeNumerically(">", 0))
+
+			return simulator
+		}
+
+		BeforeEach(func() {
+			version = fdbv1beta2.Versions.NextPatchVersion.String()
+			// The zone comes back before the operator replaces the failed
+			// process groups.
+			cluster.Spec.AutomationOptions.Replacements.FailureDetectionTimeSeconds = pointer.Int(600)
+		})
+
+		When("the Pods are deleted to update the processes", func() {
+			BeforeEach(func() {
+				cluster.Spec.AutomationOptions.PodUpdateStrategy = fdbv1beta2.PodUpdateStrategyDelete
+			})
+
+			It("should finish the upgrade once the zone is back", func() {
+				runUpgrade()
+			})
+		})
+
+		When("the transaction system is replaced to update the processes", func() {
+			It("should finish the upgrade once the zone is back", func() {
+				runUpgrade()
+			})
+		})
+	})
+
+	When("the operator takes down more than one zone", func() {
+		It("should report the violated invariant", func() {
+			_, err := Scenario{
+				Name:       "two zones down",
+				Cluster:    cluster,
+				Invariants: []Invariant{NeverLoseFaultTolerance()},
+				Steps: []Step{
+					CreateCluster(),
+					WaitForReconciliation(),
+					Check("deleting the Pods in two zones", func(simulator *Simulator) error {
+						pods := &corev1.PodList{}
+						err := simulator.Client().List(context.TODO(), pods)
+						if err != nil {
+							return err
+						}
+
+						for index := range pods.Items {
+							pod := &pods.Items[index]
+							if pod.Spec.NodeName != "node-0" && pod.Spec.NodeName != "node-1" {
+								continue
+							}
+
+							err = simulator.Client().Delete(context.TODO(), pod)
+							if err != nil {
+								return err
+							}
+						}
+
+						return simulator.Tick()
+					}),
+				},
+			}.Run()
+			Expect(err).To(MatchError(ContainSubstring("invariant NeverLoseFaultTolerance violated")))
+		})
+	})
+})
@@ -0,0 +1,681 @@
+/*
+ * simulator.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/controllers"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	mockclient "github.com/FoundationDB/fdb-kubernetes-operator/mock-kubernetes-client/client"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient/mock"
+	mockpodclient "github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient/mock"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// startTime is the simulated time when every simulation starts, so that the
+// status of the simulated database is the same for every run.
+var startTime = time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Options defines the behavior of the simulated database.
+type Options struct {
+	// TickDuration defines how much simulated time passes after every
+	// reconciliation.
+	//
+	// The default is 10 seconds.
+	TickDuration time.Duration
+
+	// DataMovementRate defines how many data units every storage process can
+	// move per tick.
+	//
+	// The default is 10.
+	DataMovementRate int
+
+	// InitialDataPerProcess defines how many data units every storage process
+	// holds once the database is configured.
+	//
+	// The default is 100.
+	InitialDataPerProcess int
+
+	// RecoveryDuration defines how long a recovery takes.
+	//
+	// The default is 5 seconds.
+	RecoveryDuration time.Duration
+
+	// ReplicationDelay defines how long a storage process must be down before
+	// its data is re-replicated to other processes.
+	//
+	// The default is 1 minute.
+	ReplicationDelay time.Duration
+
+	// PodStartupDuration defines how long a new Pod is pending before its
+	// processes are running.
+	//
+	// The default is 20 seconds.
+	PodStartupDuration time.Duration
+
+	// Nodes defines the number of nodes that Pods are scheduled on.
+	//
+	// The default is 10.
+	Nodes int
+
+	// MaxTicks defines how many ticks the simulator waits for the cluster to
+	// be reconciled.
+	//
+	// The default is 500.
+	MaxTicks int
+}
+
+// withDefaults returns the options with the defaults for all unset fields.
+func (options Options) withDefaults() Options {
+	if options.TickDuration == 0 {
+		options.TickDuration = 10 * time.Second
+	}
+
+	if options.DataMovementRate == 0 {
+		options.DataMovementRate = 10
+	}
+
+	if options.InitialDataPerProcess == 0 {
+		options.InitialDataPerProcess = 100
+	}
+
+	if options.RecoveryDuration == 0 {
+		options.RecoveryDuration = 5 * time.Second
+	}
+
+	if options.ReplicationDelay == 0 {
+		options.ReplicationDelay = time.Minute
+	}
+
+	if options.PodStartupDuration == 0 {
+		options.PodStartupDuration = 20 * time.Second
+	}
+
+	if options.Nodes == 0 {
+		options.Nodes = 10
+	}
+
+	if options.MaxTicks == 0 {
+		options.MaxTicks = 500
+	}
+
+	return options
+}
+
+// Simulator runs the cluster reconciler against a simulated Kubernetes
+// cluster and a simulated FoundationDB database. The simulation is driven by
+// a virtual clock that advances by one tick after every reconciliation, so
+// every run of a simulation produces the same results.
+//
+// The admin clients of the simulator share the cache of the mock admin
+// client, so simulations of clusters with the same name must not run
+// concurrently.
+type Simulator struct {
+	// options defines the behavior of the simulated database.
+	options Options
+	// now is the current simulated time.
+	now time.Time
+	// client is the simulated Kubernetes client.
+	client *mockclient.MockClient
+	// reconciler is the cluster reconciler under test.
+	reconciler *controllers.FoundationDBClusterReconciler
+	// cluster is the initial definition of the simulated cluster.
+	cluster *fdbv1beta2.FoundationDBCluster
+	// key is the namespaced name of the simulated cluster.
+	key types.NamespacedName
+	// db is the state of the simulated database.
+	db *database
+	// invariants are checked after every tick.
+	invariants []Invariant
+	// nodeAssignments contains the node of every Pod, so that a Pod is
+	// recreated on the same node.
+	nodeAssignments map[string]string
+	// podReadyTimes contains the simulated time when a pending Pod starts
+	// running.
+	podReadyTimes map[string]time.Time
+	// lastResult is the result of the last reconciliation.
+	lastResult reconcile.Result
+	// lastError is the error of the last reconciliation.
+	lastError error
+	// logger is used to log the events of the simulation.
+	logger logr.Logger
+}
+
+// NewSimulator creates a simulator for the cluster. The cluster is only
+// created in the simulated Kubernetes cluster by CreateCluster.
+func NewSimulator(cluster *fdbv1beta2.FoundationDBCluster, options Options, invariants ...Invariant) (*Simulator, error) {
+	scheme := runtime.NewScheme()
+	err := clientgoscheme.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	err = fdbv1beta2.AddToScheme(scheme)
+	if err != nil {
+		return nil, err
+	}
+
+	mock.ClearMockAdminClients()
+	mock.ClearMockLockClients()
+
+	simulator := &Simulator{
+		options:         options.withDefaults(),
+		now:             startTime,
+		client:          mockclient.NewMockClient(scheme),
+		cluster:         cluster.DeepCopy(),
+		key:             types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name},
+		db:              newDatabase(),
+		invariants:      invariants,
+		nodeAssignments: map[string]string{},
+		podReadyTimes:   map[string]time.Time{},
+		logger:          ctrl.Log.WithName("simulation").WithValues("namespace", cluster.Namespace, "cluster", cluster.Name),
+	}
+
+	simulator.reconciler = &controllers.FoundationDBClusterReconciler{
+		Client:                 simulator.client,
+		Log:                    ctrl.Log.WithName("controllers").WithName("FoundationDBCluster"),
+		Recorder:               simulator.client,
+		PodLifecycleManager:    PodLifecycleManager{simulator: simulator},
+		PodClientProvider:      mockpodclient.NewMockFdbPodClient,
+		DatabaseClientProvider: DatabaseClientProvider{simulator: simulator},
+		Clock:                  simulator,
+	}
+
+	return simulator, nil
+}
+
+// Now returns the current simulated time.
+func (simulator *Simulator) Now() time.Time {
+	return simulator.now
+}
+
+// Since returns the simulated time that has passed since the provided time.
+func (simulator *Simulator) Since(t time.Time) time.Duration {
+	return simulator.now.Sub(t)
+}
+
+// Client returns the simulated Kubernetes client.
+func (simulator *Simulator) Client() *mockclient.MockClient {
+	return simulator.client
+}
+
+// Recoveries returns the number of recoveries since the simulation started.
+func (simulator *Simulator) Recoveries() int {
+	return simulator.db.recoveries
+}
+
+// GetCluster fetches the latest version of the simulated cluster.
+func (simulator *Simulator) GetCluster() (*fdbv1beta2.FoundationDBCluster, error) {
+	cluster := &fdbv1beta2.FoundationDBCluster{}
+	err := simulator.client.Get(context.TODO(), simulator.key, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster, nil
+}
+
+// FailZone makes all processes in the zone unreachable, until the zone is
+// recovered.
+func (simulator *Simulator) FailZone(zone string) {
+	simulator.logger.Info("Failing zone", "zone", zone)
+	simulator.db.failedZones[zone] = fdbv1beta2.None{}
+}
+
+// RecoverZone makes the processes in a failed zone reachable again.
+func (simulator *Simulator) RecoverZone(zone string) {
+	simulator.logger.Info("Recovering zone", "zone", zone)
+	delete(simulator.db.failedZones, zone)
+}
+
+// Step runs a single reconciliation and advances the simulated time by one
+// tick. This returns an error if the simulator fails or if an invariant is
+// violated. Errors of the reconciliation are part of the reconciliation
+// result.
+func (simulator *Simulator) Step() error {
+	simulator.lastResult, simulator.lastError = simulator.reconciler.Reconcile(context.TODO(), reconcile.Request{NamespacedName: simulator.key})
+	if simulator.lastError != nil {
+		simulator.logger.Info("Reconciliation failed", "time", simulator.now, "error", simulator.lastError.Error())
+	}
+
+	return simulator.Tick()
+}
+
+// Tick advances the simulated time by one tick without running a
+// reconciliation. This starts pending Pods, completes recoveries, moves data
+// and checks the invariants.
+func (simulator *Simulator) Tick() error {
+	simulator.now = simulator.now.Add(simulator.options.TickDuration)
+
+	cluster, err := simulator.GetCluster()
+	if err != nil {
+		return err
+	}
+
+	err = simulator.startPendingPods(cluster)
+	if err != nil {
+		return err
+	}
+
+	mockClient, err := mock.NewMockAdminClientUncast(cluster, simulator.client)
+	if err != nil {
+		return err
+	}
+
+	err = simulator.updateProcesses(mockClient)
+	if err != nil {
+		return err
+	}
+
+	if simulator.db.recovering && !simulator.now.Before(simulator.db.recoveryEnd) {
+		simulator.db.recovering = false
+		simulator.db.lastRecovered = simulator.now
+		simulator.db.recruit(cluster, mockClient.ExcludedAddresses)
+	}
+
+	if simulator.db.seeded {
+		simulator.db.moveData(mockClient.ExcludedAddresses, simulator.options.DataMovementRate, simulator.options.ReplicationDelay, simulator.now)
+	}
+
+	return simulator.checkInvariants(cluster, mockClient)
+}
+
+// RunUntilReconciled runs reconciliations until the cluster is reconciled
+// and the database is not in a recovery. This returns an error if an
+// invariant is violated or if the cluster is not reconciled within the
+// given number of ticks.
+func (simulator *Simulator) RunUntilReconciled(maxTicks int) error {
+	for tick := 0; tick < maxTicks; tick++ {
+		err := simulator.Step()
+		if err != nil {
+			return err
+		}
+
+		reconciled, err := simulator.isReconciled()
+		if err != nil {
+			return err
+		}
+
+		if reconciled {
+			simulator.logger.Info("Cluster is reconciled", "time", simulator.now, "ticks", tick+1)
+			return nil
+		}
+	}
+
+	if simulator.lastError != nil {
+		return fmt.Errorf("cluster was not reconciled after %d ticks, last error: %w", maxTicks, simulator.lastError)
+	}
+
+	return fmt.Errorf("cluster was not reconciled after %d ticks", maxTicks)
+}
+
+// RunFor runs reconciliations until the given simulated duration has passed.
+func (simulator *Simulator) RunFor(duration time.Duration) error {
+	end := simulator.now.Add(duration)
+	for simulator.now.Before(end) {
+		err := simulator.Step()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isReconciled returns true if the last reconciliation succeeded without a
+// requeue, the latest generation of the cluster is reconciled and the
+// database is not in a recovery.
+func (simulator *Simulator) isReconciled() (bool, error) {
+	if simulator.lastError != nil || simulator.lastResult.Requeue || simulator.db.recovering {
+		return false, nil
+	}
+
+	cluster, err := simulator.GetCluster()
+	if err != nil {
+		return false, err
+	}
+
+	return cluster.Status.Generations.Reconciled == cluster.ObjectMeta.Generation, nil
+}
+
+// getPods returns the Pods of the simulated cluster.
+func (simulator *Simulator) getPods(cluster *fdbv1beta2.FoundationDBCluster) ([]corev1.Pod, error) {
+	pods := &corev1.PodList{}
+	err := simulator.client.List(context.TODO(), pods, internal.GetPodListOptions(cluster, "", "")...)
+	if err != nil {
+		return nil, err
+	}
+
+	return pods.Items, nil
+}
+
+// startPendingPods moves the pending Pods into the running phase once their
+// startup duration has passed.
+func (simulator *Simulator) startPendingPods(cluster *fdbv1beta2.FoundationDBCluster) error {
+	pods, err := simulator.getPods(cluster)
+	if err != nil {
+		return err
+	}
+
+	for index := range pods {
+		pod := &pods[index]
+		if pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+
+		readyTime, ok := simulator.podReadyTimes[pod.Name]
+		if ok && simulator.now.Before(readyTime) {
+			continue
+		}
+
+		pod.Status.Phase = corev1.PodRunning
+		err = simulator.client.Update(context.TODO(), pod)
+		if err != nil {
+			return err
+		}
+
+		delete(simulator.podReadyTimes, pod.Name)
+	}
+
+	return nil
+}
+
+// updateProcesses updates the simulated processes based on the current Pods.
+// This seeds the database once it is configured, and starts a recovery if a
+// process of the transaction system went down.
+func (simulator *Simulator) updateProcesses(mockClient *mock.AdminClient) error {
+	pods, err := simulator.getPods(mockClient.Cluster)
+	if err != nil {
+		return err
+	}
+
+	needsRecovery, err := simulator.db.refresh(mockClient.Cluster, pods, simulator.now)
+	if err != nil {
+		return err
+	}
+
+	if !simulator.db.seeded && mockClient.DatabaseConfiguration != nil {
+		simulator.logger.Info("Seeding database", "time", simulator.now)
+		simulator.db.seed(mockClient.Cluster, simulator.options.InitialDataPerProcess, mockClient.ExcludedAddresses, simulator.now)
+	}
+
+	if needsRecovery {
+		simulator.startRecovery("process of the transaction system failed")
+	}
+
+	return nil
+}
+
+// startRecovery starts a recovery of the simulated database.
+func (simulator *Simulator) startRecovery(reason string) {
+	if !simulator.db.seeded {
+		return
+	}
+
+	simulator.logger.Info("Starting recovery", "time", simulator.now, "reason", reason)
+	simulator.db.startRecovery(simulator.now, simulator.options.RecoveryDuration)
+}
+
+// recordExclusion starts a recovery if one of the newly excluded processes
+// runs a role of the transaction system.
+func (simulator *Simulator) recordExclusion(mockClient *mock.AdminClient, addresses []fdbv1beta2.ProcessAddress) {
+	for _, current := range simulator.getMatchingProcesses(addresses) {
+		if current.hasTransactionRole() && current.isExcluded(mockClient.ExcludedAddresses) {
+			simulator.startRecovery(fmt.Sprintf("process %s was excluded", current.id))
+			return
+		}
+	}
+}
+
+// restartProcesses restarts the processes with the given addresses. This
+// starts a recovery if one of the processes runs a role of the transaction
+// system.
+func (simulator *Simulator) restartProcesses(addresses []fdbv1beta2.ProcessAddress) {
+	needsRecovery := false
+	for _, current := range simulator.getMatchingProcesses(addresses) {
+		if current.down {
+			continue
+		}
+
+		current.startTime = simulator.now
+		if current.hasTransactionRole() {
+			needsRecovery = true
+		}
+	}
+
+	if needsRecovery {
+		simulator.startRecovery("process of the transaction system was restarted")
+	}
+}
+
+// getUnsafeAddresses returns the addresses with processes that are not
+// excluded, that still hold data or that still run a role of the
+// transaction system.
+func (simulator *Simulator) getUnsafeAddresses(mockClient *mock.AdminClient, addresses []fdbv1beta2.ProcessAddress) []fdbv1beta2.ProcessAddress {
+	unsafeAddresses := make([]fdbv1beta2.ProcessAddress, 0, len(addresses))
+	for _, address := range addresses {
+		for _, current := range simulator.getMatchingProcesses([]fdbv1beta2.ProcessAddress{address}) {
+			if !current.isExcluded(mockClient.ExcludedAddresses) || current.data > 0 || current.hasTransactionRole() {
+				unsafeAddresses = append(unsafeAddresses, address)
+				break
+			}
+		}
+	}
+
+	return unsafeAddresses
+}
+
+// getMatchingProcesses returns the processes with the given addresses.
+// Addresses without a port match all processes with the same IP.
+func (simulator *Simulator) getMatchingProcesses(addresses []fdbv1beta2.ProcessAddress) []*process {
+	matches := make([]*process, 0, len(addresses))
+	for _, current := range simulator.db.getSortedProcesses() {
+		for _, address := range addresses {
+			if address.Port == 0 && address.MachineAddress() == current.address.MachineAddress() ||
+				address.StringWithoutFlags() == current.address.StringWithoutFlags() {
+				matches = append(matches, current)
+				break
+			}
+		}
+	}
+
+	return matches
+}
+
+// schedulePod returns the node for a new Pod. A Pod that was scheduled
+// before is placed on the same node again. Other Pods are placed on the node
+// with the fewest Pods of the same process class, and then on the node with
+// the fewest Pods overall.
+func (simulator *Simulator) schedulePod(pod *corev1.Pod) (string, error) {
+	if node, ok := simulator.nodeAssignments[pod.Name]; ok {
+		return node, nil
+	}
+
+	pods := &corev1.PodList{}
+	err := simulator.client.List(context.TODO(), pods, client.InNamespace(pod.Namespace))
+	if err != nil {
+		return "", err
+	}
+
+	processClass := pod.Labels[fdbv1beta2.FDBProcessClassLabel]
+	classCounts := map[string]int{}
+	totalCounts := map[string]int{}
+	for _, existingPod := range pods.Items {
+		totalCounts[existingPod.Spec.NodeName]++
+		if existingPod.Labels[fdbv1beta2.FDBProcessClassLabel] == processClass {
+			classCounts[existingPod.Spec.NodeName]++
+		}
+	}
+
+	var selected string
+	for index := 0; index < simulator.options.Nodes; index++ {
+		node := fmt.Sprintf("node-%d", index)
+		if selected == "" || classCounts[node] < classCounts[selected] ||
+			(classCounts[node] == classCounts[selected] && totalCounts[node] < totalCounts[selected]) {
+			selected = node
+		}
+	}
+
+	simulator.nodeAssignments[pod.Name] = selected
+	return selected, nil
+}
+
+// recordPodCreation records when a newly created Pod starts running.
+func (simulator *Simulator) recordPodCreation(pod *corev1.Pod) {
+	simulator.podReadyTimes[pod.Name] = simulator.now.Add(simulator.options.PodStartupDuration)
+}
+
+// applyStatus adds the state of the simulated database to the status that
+// the mock admin client generated from the Pods.
+func (simulator *Simulator) applyStatus(mockClient *mock.AdminClient, status *fdbv1beta2.FoundationDBStatus) error {
+	err := simulator.updateProcesses(mockClient)
+	if err != nil {
+		return err
+	}
+
+	db := simulator.db
+	upAddresses := map[string]fdbv1beta2.None{}
+	for id, processInfo := range status.Cluster.Processes {
+		current, ok := db.processes[string(id)]
+		if !ok || current.down {
+			delete(status.Cluster.Processes, id)
+			continue
+		}
+
+		upAddresses[current.address.String()] = fdbv1beta2.None{}
+		processInfo.Locality[fdbv1beta2.FDBLocalityZoneIDKey] = current.zone
+		processInfo.UptimeSeconds = simulator.now.Sub(current.startTime).Seconds()
+		if current.processClass == fdbv1beta2.ProcessClassStorage {
+			processInfo.Roles = append(processInfo.Roles, fdbv1beta2.FoundationDBStatusProcessRoleInfo{Role: string(fdbv1beta2.ProcessRoleStorage)})
+		}
+
+		for _, role := range current.roles {
+			processInfo.Roles = append(processInfo.Roles, fdbv1beta2.FoundationDBStatusProcessRoleInfo{Role: string(role)})
+		}
+
+		status.Cluster.Processes[id] = processInfo
+	}
+
+	for index, coordinator := range status.Client.Coordinators.Coordinators {
+		if _, ok := upAddresses[coordinator.Address.String()]; !ok {
+			status.Client.Coordinators.Coordinators[index].Reachable = false
+		}
+	}
+
+	if !db.seeded {
+		return nil
+	}
+
+	desiredFaultTolerance := mockClient.Cluster.DesiredFaultTolerance()
+	dataFaultTolerance, availabilityFaultTolerance := db.getFaultTolerance(desiredFaultTolerance)
+	if mockClient.MaintenanceZone != "" {
+		dataFaultTolerance--
+		availabilityFaultTolerance--
+	}
+
+	status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingData = dataFaultTolerance
+	status.Cluster.FaultTolerance.MaxZoneFailuresWithoutLosingAvailability = availabilityFaultTolerance
+
+	movingData := db.getMovingData(mockClient.ExcludedAddresses)
+	status.Cluster.Data.MovingData.InQueueBytes = movingData
+	status.Cluster.Data.State.Description = ""
+	if dataFaultTolerance < 0 {
+		status.Cluster.FullReplication = false
+		status.Cluster.Data.State.Healthy = false
+		status.Cluster.Data.State.Name = "missing_data"
+		status.Cluster.Data.State.Description = "The database is missing data"
+	} else if len(db.getDegradedZones()) > 0 {
+		status.Cluster.FullReplication = false
+		status.Cluster.Data.State.Healthy = false
+		status.Cluster.Data.State.Name = "healing"
+		status.Cluster.Data.State.Description = "Restoring replication factor"
+	} else if movingData > 0 {
+		status.Cluster.Data.State.Name = "healthy_removing_server"
+		status.Cluster.Data.State.Description = "Removing storage server"
+	}
+
+	totalData := 0
+	for _, current := range db.processes {
+		totalData += current.data
+	}
+	status.Cluster.Data.KVBytes = totalData
+
+	available := status.Client.DatabaseStatus.Available && !db.recovering && dataFaultTolerance >= 0
+	status.Client.DatabaseStatus.Available = available
+	status.Client.DatabaseStatus.Healthy = available && status.Cluster.FullReplication
+
+	status.Cluster.RecoveryState.ActiveGenerations = 1
+	if db.recovering {
+		status.Cluster.RecoveryState.Name = "recruiting_transaction_servers"
+		status.Cluster.RecoveryState.SecondsSinceLastRecovered = 0
+	} else {
+		status.Cluster.RecoveryState.Name = "fully_recovered"
+		status.Cluster.RecoveryState.SecondsSinceLastRecovered = simulator.now.Sub(db.lastRecovered).Seconds()
+	}
+
+	return nil
+}
+
+// checkInvariants checks all invariants against the current state of the
+// simulation.
+func (simulator *Simulator) checkInvariants(cluster *fdbv1beta2.FoundationDBCluster, mockClient *mock.AdminClient) error {
+	snapshot := simulator.getSnapshot(cluster, mockClient)
+	for _, invariant := range simulator.invariants {
+		err := invariant.Check(snapshot)
+		if err != nil {
+			return fmt.Errorf("invariant %s violated at %s: %w", invariant.Name, snapshot.Time.Format(time.RFC3339), err)
+		}
+	}
+
+	return nil
+}
+
+// getSnapshot returns the current state of the simulation.
+func (simulator *Simulator) getSnapshot(cluster *fdbv1beta2.FoundationDBCluster, mockClient *mock.AdminClient) Snapshot {
+	desiredFaultTolerance := cluster.DesiredFaultTolerance()
+	snapshot := Snapshot{
+		Time:                                     simulator.now,
+		Cluster:                                  cluster,
+		DesiredFaultTolerance:                    desiredFaultTolerance,
+		MaxZoneFailuresWithoutLosingData:         desiredFaultTolerance,
+		MaxZoneFailuresWithoutLosingAvailability: desiredFaultTolerance,
+		FailedZones:                              getSortedKeys(simulator.db.failedZones),
+		ExcludedZones:                            simulator.db.getExcludedZones(mockClient.ExcludedAddresses),
+		Recoveries:                               simulator.db.recoveries,
+	}
+
+	if simulator.db.seeded {
+		snapshot.MaxZoneFailuresWithoutLosingData, snapshot.MaxZoneFailuresWithoutLosingAvailability = simulator.db.getFaultTolerance(desiredFaultTolerance)
+	}
+
+	return snapshot
+}
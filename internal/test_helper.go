@@ -21,6 +21,15 @@
 package internal
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
 	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -92,3 +101,79 @@ func CreateDefaultBackup(cluster *fdbv1beta2.FoundationDBCluster) *fdbv1beta2.Fo
 		Status: fdbv1beta2.FoundationDBBackupStatus{},
 	}
 }
+
+// CreateDefaultDisasterRecovery creates a default FoundationDBDisasterRecovery for testing
+func CreateDefaultDisasterRecovery(source *fdbv1beta2.FoundationDBCluster, destination *fdbv1beta2.FoundationDBCluster) *fdbv1beta2.FoundationDBDisasterRecovery {
+	agentCount := 3
+	return &fdbv1beta2.FoundationDBDisasterRecovery{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      source.Name,
+			Namespace: source.Namespace,
+		},
+		Spec: fdbv1beta2.FoundationDBDisasterRecoverySpec{
+			DRState:                fdbv1beta2.DisasterRecoveryStateRunning,
+			Version:                source.Spec.Version,
+			SourceClusterName:      source.Name,
+			DestinationClusterName: destination.Name,
+			AgentCount:             &agentCount,
+		},
+		Status: fdbv1beta2.FoundationDBDisasterRecoveryStatus{},
+	}
+}
+
+// CreateTestCertificateSecret creates a Secret that contains a certificate
+// and the CA that issued it, in the same format as a Secret issued by
+// cert-manager. Every call creates a new CA.
+func CreateTestCertificateSecret(namespace string, name string, serialNumber int64) (*v1.Secret, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	notBefore := time.Now().Add(-1 * time.Hour).Truncate(time.Second)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(serialNumber),
+		Subject:               pkix.Name{CommonName: name + "-ca"},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	caBytes, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serialNumber),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	certificateBytes, err := x509.CreateCertificate(rand.Reader, template, caTemplate, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: v1.SecretTypeTLS,
+		Data: map[string][]byte{
+			v1.TLSCertKey:    pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateBytes}),
+			CACertificateKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes}),
+		},
+	}, nil
+}
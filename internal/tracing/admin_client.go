@@ -0,0 +1,351 @@
+/*
+ * admin_client.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// adminClient wraps an admin client and creates a span for every call that
+// talks to the cluster.
+type adminClient struct {
+	fdbadminclient.AdminClient
+
+	// ctx contains the span that the spans of the admin client calls are
+	// created under.
+	ctx context.Context
+
+	// attributes are added to all spans.
+	attributes []attribute.KeyValue
+}
+
+// NewAdminClient wraps the admin client, so that every call creates a span as
+// child of the span in ctx. The spans are tagged with the cluster.
+func NewAdminClient(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, client fdbadminclient.AdminClient) fdbadminclient.AdminClient {
+	return &adminClient{
+		AdminClient: client,
+		ctx:         ctx,
+		attributes:  GetClusterAttributes(cluster),
+	}
+}
+
+// startSpan starts the span for the admin client call.
+func (client *adminClient) startSpan(method string, attributes ...attribute.KeyValue) trace.Span {
+	_, span := StartSpan(client.ctx, "AdminClient."+method, append(attributes, client.attributes...)...)
+	return span
+}
+
+// getAddressesAttribute returns the attribute for the targeted addresses.
+func getAddressesAttribute(addresses []fdbv1beta2.ProcessAddress) attribute.KeyValue {
+	values := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		values = append(values, address.String())
+	}
+
+	return AddressesKey.StringSlice(values)
+}
+
+// GetStatus gets the database's status.
+func (client *adminClient) GetStatus() (status *fdbv1beta2.FoundationDBStatus, err error) {
+	span := client.startSpan("GetStatus")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetStatus()
+}
+
+// ConfigureDatabase sets the database configuration.
+func (client *adminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseConfiguration, newDatabase bool, version string) (err error) {
+	span := client.startSpan("ConfigureDatabase", attribute.Bool("fdb.new_database", newDatabase))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ConfigureDatabase(configuration, newDatabase, version)
+}
+
+// ExcludeProcesses starts evacuating processes so that they can be removed
+// from the database.
+func (client *adminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddress) (err error) {
+	span := client.startSpan("ExcludeProcesses", getAddressesAttribute(addresses))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ExcludeProcesses(addresses)
+}
+
+// IncludeProcesses removes processes from the exclusion list and allows
+// them to take on roles again.
+func (client *adminClient) IncludeProcesses(addresses []fdbv1beta2.ProcessAddress) (err error) {
+	span := client.startSpan("IncludeProcesses", getAddressesAttribute(addresses))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.IncludeProcesses(addresses)
+}
+
+// GetExclusions gets a list of the addresses currently excluded from the
+// database.
+func (client *adminClient) GetExclusions() (exclusions []fdbv1beta2.ProcessAddress, err error) {
+	span := client.startSpan("GetExclusions")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetExclusions()
+}
+
+// CanSafelyRemove checks whether it is safe to remove processes from the
+// cluster.
+func (client *adminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress) (remaining []fdbv1beta2.ProcessAddress, err error) {
+	span := client.startSpan("CanSafelyRemove", getAddressesAttribute(addresses))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.CanSafelyRemove(addresses)
+}
+
+// KillProcesses restarts processes.
+func (client *adminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress) (err error) {
+	span := client.startSpan("KillProcesses", getAddressesAttribute(addresses))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.KillProcesses(addresses)
+}
+
+// ChangeCoordinators changes the coordinator set.
+func (client *adminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessAddress) (connectionString string, err error) {
+	span := client.startSpan("ChangeCoordinators", getAddressesAttribute(addresses))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ChangeCoordinators(addresses)
+}
+
+// GetConnectionString fetches the latest connection string.
+func (client *adminClient) GetConnectionString() (connectionString string, err error) {
+	span := client.startSpan("GetConnectionString")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetConnectionString()
+}
+
+// VersionSupported reports whether we can support a cluster with a given
+// version.
+func (client *adminClient) VersionSupported(version string) (supported bool, err error) {
+	span := client.startSpan("VersionSupported")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.VersionSupported(version)
+}
+
+// GetProtocolVersion determines the protocol version that is used by a
+// version of FDB.
+func (client *adminClient) GetProtocolVersion(version string) (protocolVersion string, err error) {
+	span := client.startSpan("GetProtocolVersion")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetProtocolVersion(version)
+}
+
+// StartBackup starts a new backup.
+func (client *adminClient) StartBackup(url string, snapshotPeriodSeconds int) (err error) {
+	span := client.startSpan("StartBackup")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.StartBackup(url, snapshotPeriodSeconds)
+}
+
+// StopBackup stops a backup.
+func (client *adminClient) StopBackup(url string) (err error) {
+	span := client.startSpan("StopBackup")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.StopBackup(url)
+}
+
+// PauseBackups pauses the backups.
+func (client *adminClient) PauseBackups() (err error) {
+	span := client.startSpan("PauseBackups")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.PauseBackups()
+}
+
+// ResumeBackups resumes the backups.
+func (client *adminClient) ResumeBackups() (err error) {
+	span := client.startSpan("ResumeBackups")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ResumeBackups()
+}
+
+// ModifyBackup modifies the configuration of the backup.
+func (client *adminClient) ModifyBackup(snapshotPeriodSeconds int) (err error) {
+	span := client.startSpan("ModifyBackup")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ModifyBackup(snapshotPeriodSeconds)
+}
+
+// GetBackupStatus gets the status of the current backup.
+func (client *adminClient) GetBackupStatus() (status *fdbv1beta2.FoundationDBLiveBackupStatus, err error) {
+	span := client.startSpan("GetBackupStatus")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetBackupStatus()
+}
+
+// StartRestore starts a new restore.
+func (client *adminClient) StartRestore(url string, keyRanges []fdbv1beta2.FoundationDBKeyRange) (err error) {
+	span := client.startSpan("StartRestore")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.StartRestore(url, keyRanges)
+}
+
+// GetRestoreStatus gets the status of the current restore.
+func (client *adminClient) GetRestoreStatus() (status string, err error) {
+	span := client.startSpan("GetRestoreStatus")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetRestoreStatus()
+}
+
+// StartDisasterRecovery starts replicating the source cluster into this
+// cluster.
+func (client *adminClient) StartDisasterRecovery(sourceConnectionString string) (err error) {
+	span := client.startSpan("StartDisasterRecovery")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.StartDisasterRecovery(sourceConnectionString)
+}
+
+// AbortDisasterRecovery aborts replicating the source cluster into this
+// cluster.
+func (client *adminClient) AbortDisasterRecovery(sourceConnectionString string) (err error) {
+	span := client.startSpan("AbortDisasterRecovery")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.AbortDisasterRecovery(sourceConnectionString)
+}
+
+// PauseDisasterRecovery pauses the DR agents.
+func (client *adminClient) PauseDisasterRecovery(sourceConnectionString string) (err error) {
+	span := client.startSpan("PauseDisasterRecovery")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.PauseDisasterRecovery(sourceConnectionString)
+}
+
+// ResumeDisasterRecovery resumes the DR agents.
+func (client *adminClient) ResumeDisasterRecovery(sourceConnectionString string) (err error) {
+	span := client.startSpan("ResumeDisasterRecovery")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ResumeDisasterRecovery(sourceConnectionString)
+}
+
+// SwitchoverDisasterRecovery makes this cluster the primary and starts
+// replicating it into the source cluster.
+func (client *adminClient) SwitchoverDisasterRecovery(sourceConnectionString string) (err error) {
+	span := client.startSpan("SwitchoverDisasterRecovery")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.SwitchoverDisasterRecovery(sourceConnectionString)
+}
+
+// GetDisasterRecoveryStatus gets the status of replicating the source
+// cluster into this cluster.
+func (client *adminClient) GetDisasterRecoveryStatus(sourceConnectionString string) (status *fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus, err error) {
+	span := client.startSpan("GetDisasterRecoveryStatus")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetDisasterRecoveryStatus(sourceConnectionString)
+}
+
+// GetTenant gets the live status of a tenant.
+func (client *adminClient) GetTenant(name string) (status *fdbv1beta2.FoundationDBLiveTenantStatus, err error) {
+	span := client.startSpan("GetTenant")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetTenant(name)
+}
+
+// CreateTenant creates a new tenant in the provided tenant group.
+func (client *adminClient) CreateTenant(name string, tenantGroup string) (err error) {
+	span := client.startSpan("CreateTenant")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.CreateTenant(name, tenantGroup)
+}
+
+// ConfigureTenant assigns a tenant to the provided tenant group.
+func (client *adminClient) ConfigureTenant(name string, tenantGroup string) (err error) {
+	span := client.startSpan("ConfigureTenant")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ConfigureTenant(name, tenantGroup)
+}
+
+// IsTenantEmpty checks whether a tenant holds any data.
+func (client *adminClient) IsTenantEmpty(name string) (empty bool, err error) {
+	span := client.startSpan("IsTenantEmpty")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.IsTenantEmpty(name)
+}
+
+// DeleteTenant deletes a tenant.
+func (client *adminClient) DeleteTenant(name string, clearData bool) (err error) {
+	span := client.startSpan("DeleteTenant")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.DeleteTenant(name, clearData)
+}
+
+// GetCoordinatorSet returns a set of the current coordinators.
+func (client *adminClient) GetCoordinatorSet() (coordinators map[string]fdbv1beta2.None, err error) {
+	span := client.startSpan("GetCoordinatorSet")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetCoordinatorSet()
+}
+
+// GetMaintenanceZone gets current maintenance zone, if any.
+func (client *adminClient) GetMaintenanceZone() (zone string, err error) {
+	span := client.startSpan("GetMaintenanceZone")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.GetMaintenanceZone()
+}
+
+// SetMaintenanceZone places zone into maintenance mode.
+func (client *adminClient) SetMaintenanceZone(zone string, timeoutSeconds int) (err error) {
+	span := client.startSpan("SetMaintenanceZone", attribute.String("fdb.maintenance_zone", zone))
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.SetMaintenanceZone(zone, timeoutSeconds)
+}
+
+// ResetMaintenanceMode resets the maintenance zone.
+func (client *adminClient) ResetMaintenanceMode() (err error) {
+	span := client.startSpan("ResetMaintenanceMode")
+	defer func() { EndSpan(span, err) }()
+
+	return client.AdminClient.ResetMaintenanceMode()
+}
@@ -0,0 +1,88 @@
+/*
+ * pod_client.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// podClient wraps a pod client and creates a span for every call to the
+// sidecar.
+type podClient struct {
+	podclient.FdbPodClient
+
+	// ctx contains the span that the spans of the pod client calls are
+	// created under.
+	ctx context.Context
+
+	// attributes are added to all spans.
+	attributes []attribute.KeyValue
+}
+
+// NewPodClient wraps the pod client, so that every call creates a span as
+// child of the span in ctx. The spans are tagged with the cluster, the Pod
+// and the process group of the Pod.
+func NewPodClient(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, podName string, processGroupID fdbv1beta2.ProcessGroupID, client podclient.FdbPodClient) podclient.FdbPodClient {
+	return &podClient{
+		FdbPodClient: client,
+		ctx:          ctx,
+		attributes: append(GetClusterAttributes(cluster),
+			PodKey.String(podName),
+			ProcessGroupIDKey.String(string(processGroupID)),
+		),
+	}
+}
+
+// startSpan starts the span for the pod client call.
+func (client *podClient) startSpan(method string, attributes ...attribute.KeyValue) trace.Span {
+	_, span := StartSpan(client.ctx, "FdbPodClient."+method, append(attributes, client.attributes...)...)
+	return span
+}
+
+// IsPresent checks whether a file is present.
+func (client *podClient) IsPresent(path string) (present bool, err error) {
+	span := client.startSpan("IsPresent", attribute.String("fdb.file", path))
+	defer func() { EndSpan(span, err) }()
+
+	return client.FdbPodClient.IsPresent(path)
+}
+
+// UpdateFile checks if a file is up-to-date and tries to update it.
+func (client *podClient) UpdateFile(name string, contents string) (updated bool, err error) {
+	span := client.startSpan("UpdateFile", attribute.String("fdb.file", name))
+	defer func() { EndSpan(span, err) }()
+
+	return client.FdbPodClient.UpdateFile(name, contents)
+}
+
+// GetVariableSubstitutions gets the current keys and values that this
+// process group will substitute into its monitor conf.
+func (client *podClient) GetVariableSubstitutions() (substitutions map[string]string, err error) {
+	span := client.startSpan("GetVariableSubstitutions")
+	defer func() { EndSpan(span, err) }()
+
+	return client.FdbPodClient.GetVariableSubstitutions()
+}
@@ -0,0 +1,102 @@
+/*
+ * tracing.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"context"
+	"io"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName defines the name of the tracer that creates all spans of
+// the operator.
+const InstrumentationName = "github.com/FoundationDB/fdb-kubernetes-operator"
+
+const (
+	// NamespaceKey defines the attribute key for the namespace of a resource.
+	NamespaceKey = attribute.Key("k8s.namespace.name")
+
+	// ClusterKey defines the attribute key for the name of the cluster.
+	ClusterKey = attribute.Key("fdb.cluster.name")
+
+	// ProcessGroupIDKey defines the attribute key for the process group ID.
+	ProcessGroupIDKey = attribute.Key("fdb.process_group.id")
+
+	// PodKey defines the attribute key for the name of the Pod.
+	PodKey = attribute.Key("k8s.pod.name")
+
+	// AddressesKey defines the attribute key for the process addresses that
+	// an admin client call targets.
+	AddressesKey = attribute.Key("fdb.addresses")
+)
+
+// NewTracerProvider creates a tracer provider that writes the spans as JSON to
+// the provided writer. Only the fraction of the traces defined by sampleRatio
+// is recorded.
+func NewTracerProvider(writer io.Writer, sampleRatio float64, serviceVersion string) (*sdktrace.TracerProvider, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(writer))
+	if err != nil {
+		return nil, err
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceName("fdb-kubernetes-operator"),
+			semconv.ServiceVersion(serviceVersion),
+		)),
+	), nil
+}
+
+// StartSpan starts a new span as child of the span in ctx. If no tracer
+// provider is configured the span will not be recorded.
+func StartSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(InstrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// EndSpan records the error, if any, and ends the span.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// GetClusterAttributes returns the attributes that identify the cluster.
+func GetClusterAttributes(cluster *fdbv1beta2.FoundationDBCluster) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		NamespaceKey.String(cluster.Namespace),
+		ClusterKey.String(cluster.Name),
+	}
+}
@@ -0,0 +1,192 @@
+/*
+ * tracing_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/podclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeAdminClient implements the admin client calls that are used in the
+// tests.
+type fakeAdminClient struct {
+	fdbadminclient.AdminClient
+	killErr error
+}
+
+// KillProcesses returns the configured error.
+func (client *fakeAdminClient) KillProcesses(_ []fdbv1beta2.ProcessAddress) error {
+	return client.killErr
+}
+
+// Close does nothing.
+func (client *fakeAdminClient) Close() error {
+	return nil
+}
+
+// fakePodClient implements the pod client calls that are used in the tests.
+type fakePodClient struct {
+	podclient.FdbPodClient
+}
+
+// IsPresent reports every file as present.
+func (client *fakePodClient) IsPresent(_ string) (bool, error) {
+	return true, nil
+}
+
+// getAttributes converts the attributes of the span into a map.
+func getAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attributes := map[attribute.Key]attribute.Value{}
+	for _, keyValue := range span.Attributes() {
+		attributes[keyValue.Key] = keyValue.Value
+	}
+
+	return attributes
+}
+
+var _ = Describe("tracing", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var recorder *tracetest.SpanRecorder
+	var ctx context.Context
+	var parent trace.Span
+
+	BeforeEach(func() {
+		cluster = &fdbv1beta2.FoundationDBCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test",
+			},
+		}
+
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+		ctx, parent = StartSpan(context.Background(), "parent")
+	})
+
+	AfterEach(func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	})
+
+	When("calling the admin client", func() {
+		var killErr error
+
+		JustBeforeEach(func() {
+			adminClient := NewAdminClient(ctx, cluster, &fakeAdminClient{killErr: killErr})
+			err := adminClient.KillProcesses([]fdbv1beta2.ProcessAddress{{StringAddress: "192.168.0.1", Port: 4501}})
+			if killErr == nil {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(Equal(killErr))
+			}
+			Expect(adminClient.Close()).NotTo(HaveOccurred())
+			parent.End()
+		})
+
+		When("the call succeeds", func() {
+			BeforeEach(func() {
+				killErr = nil
+			})
+
+			It("should create a child span tagged with the cluster", func() {
+				spans := recorder.Ended()
+				Expect(spans).To(HaveLen(2))
+				span := spans[0]
+				Expect(span.Name()).To(Equal("AdminClient.KillProcesses"))
+				Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+				Expect(span.Status().Code).To(Equal(codes.Unset))
+
+				attributes := getAttributes(span)
+				Expect(attributes).To(HaveKeyWithValue(NamespaceKey, attribute.StringValue("test")))
+				Expect(attributes).To(HaveKeyWithValue(ClusterKey, attribute.StringValue("test-cluster")))
+				Expect(attributes).To(HaveKeyWithValue(AddressesKey, attribute.StringSliceValue([]string{"192.168.0.1:4501"})))
+			})
+		})
+
+		When("the call fails", func() {
+			BeforeEach(func() {
+				killErr = fmt.Errorf("timeout")
+			})
+
+			It("should record the error", func() {
+				spans := recorder.Ended()
+				Expect(spans).To(HaveLen(2))
+				Expect(spans[0].Status().Code).To(Equal(codes.Error))
+				Expect(spans[0].Status().Description).To(Equal("timeout"))
+			})
+		})
+	})
+
+	When("calling the pod client", func() {
+		BeforeEach(func() {
+			podClient := NewPodClient(ctx, cluster, "test-cluster-storage-1", "storage-1", &fakePodClient{})
+			Expect(podClient.IsPresent("fdb.cluster")).To(BeTrue())
+			parent.End()
+		})
+
+		It("should create a child span tagged with the process group", func() {
+			spans := recorder.Ended()
+			Expect(spans).To(HaveLen(2))
+			span := spans[0]
+			Expect(span.Name()).To(Equal("FdbPodClient.IsPresent"))
+			Expect(span.Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+
+			attributes := getAttributes(span)
+			Expect(attributes).To(HaveKeyWithValue(ClusterKey, attribute.StringValue("test-cluster")))
+			Expect(attributes).To(HaveKeyWithValue(PodKey, attribute.StringValue("test-cluster-storage-1")))
+			Expect(attributes).To(HaveKeyWithValue(ProcessGroupIDKey, attribute.StringValue("storage-1")))
+		})
+	})
+
+	When("creating a tracer provider", func() {
+		var buffer *bytes.Buffer
+
+		BeforeEach(func() {
+			buffer = &bytes.Buffer{}
+			provider, err := NewTracerProvider(buffer, 1.0, "test")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, span := provider.Tracer(InstrumentationName).Start(context.Background(), "Reconcile")
+			span.End()
+			Expect(provider.Shutdown(context.Background())).NotTo(HaveOccurred())
+		})
+
+		It("should write the spans as JSON", func() {
+			span := map[string]interface{}{}
+			Expect(json.Unmarshal(buffer.Bytes(), &span)).NotTo(HaveOccurred())
+			Expect(span).To(HaveKeyWithValue("Name", "Reconcile"))
+		})
+	})
+})
@@ -22,9 +22,9 @@ package cmd
 
 import (
 	ctx "context"
+	"errors"
 	"fmt"
 	"log"
-	"net"
 	"os"
 	"os/exec"
 	"strings"
@@ -162,40 +162,74 @@ func buildClusterFileUpdateCommands(cluster *fdbv1beta2.FoundationDBCluster, kub
 }
 
 // updateIPsInConnectionString updates the connection string in the cluster
-// status by replacing old coordinator IPs with the latest IPs.
+// status by replacing old coordinator IPs with the latest IPs. The latest IP
+// of a process group is the last address in its status. Coordinators that
+// can't be mapped to a process group are kept.
 func updateIPsInConnectionString(cluster *fdbv1beta2.FoundationDBCluster) error {
 	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
 	if err != nil {
 		return err
 	}
-	newCoordinators := make([]string, len(connectionString.Coordinators))
-	for coordinatorIndex, coordinator := range connectionString.Coordinators {
-		coordinatorAddress, err := fdbv1beta2.ParseProcessAddress(coordinator)
-		if err != nil {
-			return err
-		}
-		for _, processGroup := range cluster.Status.ProcessGroups {
-			for _, address := range processGroup.Addresses {
-				if address == coordinatorAddress.IPAddress.String() {
-					coordinatorAddress.IPAddress = net.ParseIP(processGroup.Addresses[len(processGroup.Addresses)-1])
-					newCoordinators[coordinatorIndex] = coordinatorAddress.String()
-				}
-			}
+
+	currentIPs := make(map[fdbv1beta2.ProcessGroupID][]string, len(cluster.Status.ProcessGroups))
+	for _, processGroup := range cluster.Status.ProcessGroups {
+		if len(processGroup.Addresses) == 0 {
+			continue
 		}
-		if newCoordinators[coordinatorIndex] == "" {
-			log.Printf("Could not find process for coordinator IP %s", coordinator)
-			newCoordinators[coordinatorIndex] = coordinator
+
+		currentIPs[processGroup.ProcessGroupID] = processGroup.Addresses[len(processGroup.Addresses)-1:]
+	}
+
+	changes, err := internal.GetCoordinatorIPChanges(cluster, connectionString, currentIPs)
+	if err != nil {
+		var unmappedErr *internal.UnmappedCoordinatorsError
+		if !errors.As(err, &unmappedErr) {
+			return err
 		}
+		log.Print(err.Error())
 	}
-	connectionString.Coordinators = newCoordinators
+
+	internal.ApplyCoordinatorIPChanges(&connectionString, changes)
 	cluster.Status.ConnectionString = connectionString.String()
 
 	return nil
 }
 
+// onlyDNSCoordinators returns true if all coordinators in the connection
+// string of the cluster are DNS names.
+func onlyDNSCoordinators(cluster *fdbv1beta2.FoundationDBCluster) (bool, error) {
+	connectionString, err := fdbv1beta2.ParseConnectionString(cluster.Status.ConnectionString)
+	if err != nil {
+		return false, err
+	}
+
+	for _, coordinator := range connectionString.Coordinators {
+		coordinatorAddress, err := fdbv1beta2.ParseProcessAddress(coordinator)
+		if err != nil {
+			return false, err
+		}
+
+		if coordinatorAddress.StringAddress == "" {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
 func runFixCoordinatorIPs(kubeClient client.Client, cluster *fdbv1beta2.FoundationDBCluster, context string, namespace string, dryRun bool) error {
+	dnsOnly, err := onlyDNSCoordinators(cluster)
+	if err != nil {
+		return err
+	}
+
+	if dnsOnly {
+		log.Printf("All coordinators of %s use DNS names, no update required", cluster.Name)
+		return nil
+	}
+
 	patch := client.MergeFrom(cluster.DeepCopy())
-	err := updateIPsInConnectionString(cluster)
+	err = updateIPsInConnectionString(cluster)
 	if err != nil {
 		return err
 	}
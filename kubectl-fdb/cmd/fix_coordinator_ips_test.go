@@ -190,5 +190,37 @@ var _ = Describe("[plugin] fix-coordinator-ips command", func() {
 				},
 			),
 		)
+
+		When("the connection string contains DNS names", func() {
+			BeforeEach(func() {
+				cluster.Status.ConnectionString = "test:asdfkjh@storage-1.test.default.svc.cluster.local:4501,127.0.0.2:4501,storage-3.test.default.svc.cluster.local:4501"
+				cluster.Status.ProcessGroups[0].Addresses = append(cluster.Status.ProcessGroups[0].Addresses, "127.0.1.1")
+				cluster.Status.ProcessGroups[1].Addresses = append(cluster.Status.ProcessGroups[1].Addresses, "127.0.1.2")
+			})
+
+			It("should only update the IP addresses", func() {
+				Expect(updateIPsInConnectionString(cluster)).NotTo(HaveOccurred())
+				Expect(cluster.Status.ConnectionString).To(Equal("test:asdfkjh@storage-1.test.default.svc.cluster.local:4501,127.0.1.2:4501,storage-3.test.default.svc.cluster.local:4501"))
+			})
+
+			It("should not report that only DNS names are used", func() {
+				Expect(onlyDNSCoordinators(cluster)).To(BeFalse())
+			})
+
+			When("all coordinators use DNS names", func() {
+				BeforeEach(func() {
+					cluster.Status.ConnectionString = "test:asdfkjh@storage-1.test.default.svc.cluster.local:4501,storage-2.test.default.svc.cluster.local:4501,storage-3.test.default.svc.cluster.local:4501"
+				})
+
+				It("should report that only DNS names are used", func() {
+					Expect(onlyDNSCoordinators(cluster)).To(BeTrue())
+				})
+
+				It("should not update the IPs in the connection string", func() {
+					Expect(runFixCoordinatorIPs(k8sClient, cluster, "", namespace, false)).NotTo(HaveOccurred())
+					Expect(cluster.Status.ConnectionString).To(Equal("test:asdfkjh@storage-1.test.default.svc.cluster.local:4501,storage-2.test.default.svc.cluster.local:4501,storage-3.test.default.svc.cluster.local:4501"))
+				})
+			})
+		})
 	})
 })
@@ -42,6 +42,12 @@ kubectl fdb get configuration c1
 
 # Get the configuration string from cluster c1 in the namespace default
 kubectl fdb -n default get configuration c1
+
+# Get the queued operations from cluster c1
+kubectl fdb get operations c1
+
+# Get the reconciliation history from cluster c1
+kubectl fdb get reconcile-history c1
 `,
 	}
 	cmd.SetOut(o.Out)
@@ -50,6 +56,8 @@ kubectl fdb -n default get configuration c1
 
 	cmd.AddCommand(newConfigurationCmd(streams))
 	cmd.AddCommand(newExclusionStatusCmd(streams))
+	cmd.AddCommand(newOperationsCmd(streams))
+	cmd.AddCommand(newReconcileHistoryCmd(streams))
 	o.configFlags.AddFlags(cmd.Flags())
 
 	return cmd
@@ -0,0 +1,128 @@
+/*
+ * operations.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newOperationsCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "operations",
+		Short: "Get the queued operations of a given cluster",
+		Long:  "Get the disruptive operations that are planned through the operation queue of a given cluster",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := getKubeClient(o)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			for _, clusterName := range args {
+				operations, err := getOperations(kubeClient, clusterName, namespace)
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(operations)
+			}
+
+			return nil
+		},
+		Example: `
+The operation queue is only used if it is enabled in the cluster spec with
+"spec.automationOptions.operationQueue.enabled: true".
+
+# Get the queued operations of cluster c1
+kubectl fdb get operations c1
+
+# Get the queued operations of cluster c1 in the namespace default
+kubectl fdb -n default get operations c1
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// getOperations returns a table with the operations in the operation queue
+// of the cluster, in the order they are planned.
+func getOperations(kubeClient client.Client, clusterName string, namespace string) (string, error) {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	if !cluster.GetOperationQueueEnabled() {
+		return "", fmt.Errorf("the operation queue is not enabled for cluster %s/%s", namespace, clusterName)
+	}
+
+	if len(cluster.Status.Operations) == 0 {
+		return fmt.Sprintf("No operations queued for cluster %s/%s", namespace, clusterName), nil
+	}
+
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	_, err = fmt.Fprintln(writer, "PROCESS GROUP\tTYPE\tPRIORITY\tFAULT DOMAIN\tALLOWED\tQUEUED\tREASON")
+	if err != nil {
+		return "", err
+	}
+
+	for _, operation := range cluster.Status.Operations {
+		_, err = fmt.Fprintf(writer, "%s\t%s\t%d\t%s\t%t\t%s\t%s\n",
+			operation.ProcessGroupID,
+			operation.Type,
+			operation.Priority,
+			operation.FaultDomain,
+			operation.Allowed,
+			time.Unix(operation.Timestamp, 0).UTC().Format(time.RFC3339),
+			operation.Reason,
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
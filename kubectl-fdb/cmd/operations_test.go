@@ -0,0 +1,111 @@
+/*
+ * operations_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[plugin] get operations command", func() {
+	BeforeEach(func() {
+		cluster = generateClusterStruct(clusterName, namespace)
+	})
+
+	When("the operation queue is disabled", func() {
+		It("should return an error", func() {
+			_, err := getOperations(k8sClient, clusterName, namespace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the operation queue is not enabled"))
+		})
+	})
+
+	When("the operation queue is enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.OperationQueue.Enabled = pointer.Bool(true)
+		})
+
+		When("no operations are queued", func() {
+			It("should print a hint", func() {
+				operations, err := getOperations(k8sClient, clusterName, namespace)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(operations).To(ContainSubstring("No operations queued"))
+			})
+		})
+
+		When("operations are queued", func() {
+			BeforeEach(func() {
+				timestamp := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+				cluster.Status.Operations = []fdbv1beta2.ProcessGroupOperation{
+					{
+						ProcessGroupID: "storage-1",
+						Type:           fdbv1beta2.ProcessGroupOperationReplacement,
+						Priority:       30,
+						Reason:         "misconfigured",
+						FaultDomain:    "zone-1",
+						Timestamp:      timestamp,
+						Allowed:        true,
+					},
+					{
+						ProcessGroupID: "storage-2",
+						Type:           fdbv1beta2.ProcessGroupOperationBounce,
+						Priority:       0,
+						Reason:         "new configuration",
+						FaultDomain:    "zone-2",
+						Timestamp:      timestamp,
+					},
+				}
+			})
+
+			It("should print the operations in the queue", func() {
+				operations, err := getOperations(k8sClient, clusterName, namespace)
+				Expect(err).NotTo(HaveOccurred())
+				lines := strings.Split(operations, "\n")
+				Expect(lines).To(HaveLen(3))
+				Expect(lines[0]).To(HavePrefix("PROCESS GROUP"))
+				Expect(strings.Fields(lines[1])).To(Equal([]string{
+					"storage-1",
+					"Replacement",
+					"30",
+					"zone-1",
+					"true",
+					"2023-01-01T00:00:00Z",
+					"misconfigured",
+				}))
+				Expect(strings.Fields(lines[2])).To(Equal([]string{
+					"storage-2",
+					"Bounce",
+					"0",
+					"zone-2",
+					"false",
+					"2023-01-01T00:00:00Z",
+					"new",
+					"configuration",
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,166 @@
+/*
+ * recommend.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newRecommendCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "recommend",
+		Short: "Shows the recommended resources for the processes of a given cluster.",
+		Long:  "Shows the recommended resources for the processes of a given cluster based on the observed resource usage.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := getKubeClient(o)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			for _, clusterName := range args {
+				recommendations, err := getRecommendations(kubeClient, clusterName, namespace)
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(recommendations)
+			}
+
+			return nil
+		},
+		Example: `
+The recommendations are only available if the resource recommendations are enabled in the cluster spec
+with "spec.resourceRecommendations.enabled: true". The recommendations are based on the peak usage of the
+busiest Pod of each process class with the configured headroom on top.
+
+# Show the recommended resources for cluster c1
+kubectl fdb recommend c1
+
+# Show the recommended resources for cluster c1 in the namespace default
+kubectl fdb -n default recommend c1
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// getRecommendations returns a table with the current and the recommended
+// resources for every process class of the cluster.
+func getRecommendations(kubeClient client.Client, clusterName string, namespace string) (string, error) {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	if !cluster.GetResourceRecommendationsEnabled() {
+		return "", fmt.Errorf("resource recommendations are not enabled for cluster %s/%s", namespace, clusterName)
+	}
+
+	if len(cluster.Status.ResourceRecommendations) == 0 {
+		return fmt.Sprintf("No resource recommendations available for cluster %s/%s yet", namespace, clusterName), nil
+	}
+
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	_, err = fmt.Fprintln(writer, "PROCESS CLASS\tSAMPLES\tLAST SAMPLE\tCURRENT REQUESTS\tRECOMMENDED REQUESTS\tCURRENT LIMITS\tRECOMMENDED LIMITS")
+	if err != nil {
+		return "", err
+	}
+
+	for _, recommendation := range cluster.Status.ResourceRecommendations {
+		current := getMainContainerResources(cluster, recommendation.ProcessClass)
+		_, err = fmt.Fprintf(writer, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+			recommendation.ProcessClass,
+			recommendation.Samples,
+			time.Unix(recommendation.LastSampleTimestamp, 0).UTC().Format(time.RFC3339),
+			formatResourceList(current.Requests),
+			formatResourceList(recommendation.Requests),
+			formatResourceList(current.Limits),
+			formatResourceList(recommendation.Limits),
+		)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// getMainContainerResources returns the resources of the main container in
+// the Pod template of the process class.
+func getMainContainerResources(cluster *fdbv1beta2.FoundationDBCluster, processClass fdbv1beta2.ProcessClass) corev1.ResourceRequirements {
+	podTemplate := cluster.GetProcessSettings(processClass).PodTemplate
+	if podTemplate == nil {
+		return corev1.ResourceRequirements{}
+	}
+
+	for _, container := range podTemplate.Spec.Containers {
+		if container.Name == fdbv1beta2.MainContainerName {
+			return container.Resources
+		}
+	}
+
+	return corev1.ResourceRequirements{}
+}
+
+// formatResourceList formats the resources as a sorted list of name=value
+// pairs.
+func formatResourceList(resources corev1.ResourceList) string {
+	if len(resources) == 0 {
+		return "-"
+	}
+
+	values := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		values = append(values, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(values)
+
+	return strings.Join(values, ",")
+}
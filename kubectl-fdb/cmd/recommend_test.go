@@ -0,0 +1,117 @@
+/*
+ * recommend_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[plugin] recommend command", func() {
+	BeforeEach(func() {
+		cluster = generateClusterStruct(clusterName, namespace)
+	})
+
+	When("resource recommendations are disabled", func() {
+		It("should return an error", func() {
+			_, err := getRecommendations(k8sClient, clusterName, namespace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("resource recommendations are not enabled"))
+		})
+	})
+
+	When("resource recommendations are enabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.ResourceRecommendations.Enabled = pointer.Bool(true)
+		})
+
+		When("no recommendations are available", func() {
+			It("should print a hint", func() {
+				recommendations, err := getRecommendations(k8sClient, clusterName, namespace)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(recommendations).To(ContainSubstring("No resource recommendations available"))
+			})
+		})
+
+		When("recommendations are available", func() {
+			BeforeEach(func() {
+				cluster.Spec.Processes = map[fdbv1beta2.ProcessClass]fdbv1beta2.ProcessSettings{
+					fdbv1beta2.ProcessClassGeneral: {
+						PodTemplate: &corev1.PodTemplateSpec{
+							Spec: corev1.PodSpec{
+								Containers: []corev1.Container{
+									{
+										Name: fdbv1beta2.MainContainerName,
+										Resources: corev1.ResourceRequirements{
+											Requests: corev1.ResourceList{
+												corev1.ResourceCPU: resource.MustParse("1"),
+											},
+											Limits: corev1.ResourceList{
+												corev1.ResourceCPU: resource.MustParse("2"),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				}
+				cluster.Status.ResourceRecommendations = []fdbv1beta2.ResourceRecommendation{
+					{
+						ProcessClass:        fdbv1beta2.ProcessClassStorage,
+						Samples:             3,
+						LastSampleTimestamp: 1672531200,
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("600m"),
+							corev1.ResourceMemory: resource.MustParse("1229Mi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceMemory: resource.MustParse("1229Mi"),
+						},
+					},
+				}
+			})
+
+			It("should print the current and the recommended resources", func() {
+				recommendations, err := getRecommendations(k8sClient, clusterName, namespace)
+				Expect(err).NotTo(HaveOccurred())
+				lines := strings.Split(recommendations, "\n")
+				Expect(lines).To(HaveLen(2))
+				Expect(lines[0]).To(HavePrefix("PROCESS CLASS"))
+				Expect(strings.Fields(lines[1])).To(Equal([]string{
+					"storage",
+					"3",
+					"2023-01-01T00:00:00Z",
+					"cpu=1",
+					"cpu=600m,memory=1229Mi",
+					"cpu=2",
+					"memory=1229Mi",
+				}))
+			})
+		})
+	})
+})
@@ -0,0 +1,174 @@
+/*
+ * reconcile_history.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newReconcileHistoryCmd(streams genericclioptions.IOStreams) *cobra.Command {
+	o := newFDBOptions(streams)
+
+	cmd := &cobra.Command{
+		Use:   "reconcile-history",
+		Short: "Get the reconciliation history of a given cluster",
+		Long:  "Get the most recent reconciliation runs of a given cluster, including the sub-reconciler that stopped each run",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeClient, err := getKubeClient(o)
+			if err != nil {
+				return err
+			}
+
+			namespace, err := getNamespace(*o.configFlags.Namespace)
+			if err != nil {
+				return err
+			}
+
+			for _, clusterName := range args {
+				history, err := getReconcileHistory(kubeClient, clusterName, namespace)
+				if err != nil {
+					return err
+				}
+
+				cmd.Println(history)
+			}
+
+			return nil
+		},
+		Example: `
+The number of recorded reconciliation runs can be changed in the cluster spec with
+"spec.automationOptions.reconciliationHistorySize".
+
+# Get the reconciliation history of cluster c1
+kubectl fdb get reconcile-history c1
+
+# Get the reconciliation history of cluster c1 in the namespace default
+kubectl fdb -n default get reconcile-history c1
+`,
+	}
+	cmd.SetOut(o.Out)
+	cmd.SetErr(o.ErrOut)
+	cmd.SetIn(o.In)
+
+	o.configFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+// getReconcileHistory returns a table with the recorded reconciliation runs
+// of the cluster, starting with the oldest run. Requeues that were delayed
+// to the end of a run are printed below the run.
+func getReconcileHistory(kubeClient client.Client, clusterName string, namespace string) (string, error) {
+	cluster, err := loadCluster(kubeClient, namespace, clusterName)
+	if err != nil {
+		return "", err
+	}
+
+	if cluster.GetReconciliationHistorySize() <= 0 {
+		return "", fmt.Errorf("the reconciliation history is disabled for cluster %s/%s", namespace, clusterName)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = kubeClient.Get(context.Background(), client.ObjectKey{Namespace: namespace, Name: internal.GetReconciliationHistoryConfigMapName(cluster)}, configMap)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return "", err
+	}
+
+	history, err := internal.GetReconciliationHistory(configMap)
+	if err != nil {
+		return "", err
+	}
+
+	if len(history) == 0 {
+		return fmt.Sprintf("No reconciliation runs recorded for cluster %s/%s", namespace, clusterName), nil
+	}
+
+	var buffer bytes.Buffer
+	writer := tabwriter.NewWriter(&buffer, 0, 0, 2, ' ', 0)
+	_, err = fmt.Fprintln(writer, "START\tDURATION\tGENERATION\tRECONCILED\tRESULT\tSUB-RECONCILER\tDELAY\tMESSAGE")
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range history {
+		subReconciler, delay, message := "-", "-", "-"
+		if entry.Requeue != nil {
+			subReconciler, delay, message = formatReconciliationRequeue(entry.Requeue)
+		}
+
+		_, err = fmt.Fprintf(writer, "%s\t%s\t%d\t%d\t%s\t%s\t%s\t%s\n",
+			time.Unix(entry.StartTimestamp, 0).UTC().Format(time.RFC3339),
+			time.Duration(entry.EndTimestamp-entry.StartTimestamp)*time.Second,
+			entry.Generation,
+			entry.ReconciledGeneration,
+			entry.Result,
+			subReconciler,
+			delay,
+			message,
+		)
+		if err != nil {
+			return "", err
+		}
+
+		for _, delayedRequeue := range entry.DelayedRequeues {
+			subReconciler, delay, message = formatReconciliationRequeue(&delayedRequeue)
+			_, err = fmt.Fprintf(writer, "\t\t\t\tDelayed\t%s\t%s\t%s\n", subReconciler, delay, message)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	err = writer.Flush()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buffer.String(), "\n"), nil
+}
+
+// formatReconciliationRequeue returns the sub-reconciler, the delay and the
+// message of the requeue for printing.
+func formatReconciliationRequeue(requeue *fdbv1beta2.ReconciliationRequeue) (string, string, string) {
+	message := requeue.Message
+	if requeue.Error != "" && requeue.Error != message {
+		message = strings.TrimSpace(fmt.Sprintf("%s error: %s", message, requeue.Error))
+	}
+
+	if message == "" {
+		message = "-"
+	}
+
+	return strings.TrimPrefix(requeue.SubReconciler, "controllers."), (time.Duration(requeue.DelaySeconds) * time.Second).String(), message
+}
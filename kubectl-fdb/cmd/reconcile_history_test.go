@@ -0,0 +1,142 @@
+/*
+ * reconcile_history_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/pointer"
+)
+
+var _ = Describe("[plugin] get reconcile-history command", func() {
+	BeforeEach(func() {
+		cluster = generateClusterStruct(clusterName, namespace)
+	})
+
+	When("the reconciliation history is disabled", func() {
+		BeforeEach(func() {
+			cluster.Spec.AutomationOptions.ReconciliationHistorySize = pointer.Int(0)
+		})
+
+		It("should return an error", func() {
+			_, err := getReconcileHistory(k8sClient, clusterName, namespace)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("the reconciliation history is disabled"))
+		})
+	})
+
+	When("no reconciliation runs are recorded", func() {
+		It("should print a hint", func() {
+			history, err := getReconcileHistory(k8sClient, clusterName, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(history).To(ContainSubstring("No reconciliation runs recorded"))
+		})
+	})
+
+	When("reconciliation runs are recorded", func() {
+		JustBeforeEach(func() {
+			timestamp := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+			configMap := internal.GetReconciliationHistoryConfigMap(cluster)
+
+			entries := []fdbv1beta2.ReconciliationHistoryEntry{
+				{
+					StartTimestamp:       timestamp,
+					EndTimestamp:         timestamp + 2,
+					Generation:           2,
+					ReconciledGeneration: 1,
+					Result:               fdbv1beta2.ReconciliationResultTerminatedEarly,
+					Requeue: &fdbv1beta2.ReconciliationRequeue{
+						SubReconciler: "controllers.removeProcessGroups",
+						Message:       "Reconciliation needs to exclude more processes",
+					},
+					DelayedRequeues: []fdbv1beta2.ReconciliationRequeue{
+						{
+							SubReconciler: "controllers.excludeProcesses",
+							Message:       "Waiting for missing processes",
+							DelaySeconds:  15,
+						},
+					},
+				},
+				{
+					StartTimestamp:       timestamp + 60,
+					EndTimestamp:         timestamp + 61,
+					Generation:           2,
+					ReconciledGeneration: 2,
+					Result:               fdbv1beta2.ReconciliationResultCompleted,
+				},
+			}
+
+			for _, entry := range entries {
+				Expect(internal.AddReconciliationHistoryEntry(cluster, configMap, entry)).NotTo(HaveOccurred())
+			}
+
+			Expect(k8sClient.Create(context.TODO(), configMap)).NotTo(HaveOccurred())
+		})
+
+		It("should print the reconciliation runs", func() {
+			history, err := getReconcileHistory(k8sClient, clusterName, namespace)
+			Expect(err).NotTo(HaveOccurred())
+			lines := strings.Split(history, "\n")
+			Expect(lines).To(HaveLen(4))
+			Expect(lines[0]).To(HavePrefix("START"))
+			Expect(strings.Fields(lines[1])).To(Equal([]string{
+				"2023-01-01T00:00:00Z",
+				"2s",
+				"2",
+				"1",
+				"TerminatedEarly",
+				"removeProcessGroups",
+				"0s",
+				"Reconciliation",
+				"needs",
+				"to",
+				"exclude",
+				"more",
+				"processes",
+			}))
+			Expect(strings.Fields(lines[2])).To(Equal([]string{
+				"Delayed",
+				"excludeProcesses",
+				"15s",
+				"Waiting",
+				"for",
+				"missing",
+				"processes",
+			}))
+			Expect(strings.Fields(lines[3])).To(Equal([]string{
+				"2023-01-01T00:01:00Z",
+				"1s",
+				"2",
+				"2",
+				"Completed",
+				"-",
+				"-",
+				"-",
+			}))
+		})
+	})
+})
@@ -90,6 +90,7 @@ func NewRootCmd(streams genericclioptions.IOStreams) *cobra.Command {
 		newGetCmd(streams),
 		newBuggifyCmd(streams),
 		newProfileAnalyzerCmd(streams),
+		newRecommendCmd(streams),
 	)
 
 	return cmd
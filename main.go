@@ -49,7 +49,6 @@ func init() {
 }
 
 func main() {
-	fdb.MustAPIVersion(620)
 	operatorOpts := setup.Options{}
 	operatorOpts.BindFlags(flag.CommandLine)
 
@@ -57,6 +56,8 @@ func main() {
 	logOpts.BindFlags(flag.CommandLine)
 	flag.Parse()
 
+	fdb.MustAPIVersion(operatorOpts.FDBAPIVersion)
+
 	mgr, file := setup.StartManager(
 		scheme,
 		operatorOpts,
@@ -66,6 +67,9 @@ func main() {
 		),
 		&controllers.FoundationDBBackupReconciler{},
 		&controllers.FoundationDBRestoreReconciler{},
+		&controllers.FoundationDBDisasterRecoveryReconciler{},
+		&controllers.FoundationDBTenantReconciler{},
+		&controllers.FoundationDBChaosReconciler{},
 		ctrl.Log)
 
 	if file != nil {
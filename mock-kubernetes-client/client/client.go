@@ -53,6 +53,10 @@ type MockClient struct {
 
 	// updateHooks allow to inject custom logic to the update of objects.
 	updateHooks []func(ctx context.Context, client *MockClient, object ctrlClient.Object) error
+
+	// ipFamilies defines the IP families of the mocked Kubernetes cluster. The first family is the primary family.
+	// If this is empty the cluster is a dual-stack cluster with IPv4 as the primary family.
+	ipFamilies []corev1.IPFamily
 }
 
 // NewMockClient creates a new MockClient.
@@ -69,7 +73,24 @@ func NewMockClientWithHooks(scheme *runtime.Scheme, createHooks []func(ctx conte
 		}
 
 		if svc.Spec.ClusterIP == "" {
-			svc.Spec.ClusterIP = client.generateIP()
+			families := svc.Spec.IPFamilies
+			if len(families) == 0 {
+				families = client.getIPFamilies()[:1]
+			}
+
+			clusterIPs := make([]string, 0, len(families))
+			for _, family := range families {
+				if family == corev1.IPv6Protocol {
+					clusterIPs = append(clusterIPs, client.generateIPv6())
+					continue
+				}
+
+				clusterIPs = append(clusterIPs, client.generateIP())
+			}
+
+			svc.Spec.ClusterIP = clusterIPs[0]
+			svc.Spec.ClusterIPs = clusterIPs
+			svc.Spec.IPFamilies = families
 		}
 
 		return nil
@@ -81,9 +102,17 @@ func NewMockClientWithHooks(scheme *runtime.Scheme, createHooks []func(ctx conte
 			return nil
 		}
 
-		v4Address := client.generatePodIPv4()
-		pod.Status.PodIP = v4Address
-		pod.Status.PodIPs = []corev1.PodIP{{IP: v4Address}, {IP: client.generatePodIPv6()}}
+		families := client.getIPFamilies()
+		pod.Status.PodIPs = make([]corev1.PodIP, 0, len(families))
+		for _, family := range families {
+			if family == corev1.IPv6Protocol {
+				pod.Status.PodIPs = append(pod.Status.PodIPs, corev1.PodIP{IP: client.generatePodIPv6()})
+				continue
+			}
+
+			pod.Status.PodIPs = append(pod.Status.PodIPs, corev1.PodIP{IP: client.generatePodIPv4()})
+		}
+		pod.Status.PodIP = pod.Status.PodIPs[0].IP
 
 		if pod.Status.Phase == "" {
 			pod.Status.Phase = corev1.PodRunning
@@ -103,6 +132,23 @@ func NewMockClientWithHooks(scheme *runtime.Scheme, createHooks []func(ctx conte
 // Clear erases any mock data.
 func (client *MockClient) Clear() {
 	client.fakeClient = fake.NewClientBuilder().WithScheme(client.scheme).Build()
+	client.ipFamilies = nil
+}
+
+// MockIPFamilies defines the IP families of the mocked Kubernetes cluster, e.g. corev1.IPv6Protocol for an IPv6-only
+// cluster. The first family is the primary family, which is used for the Pod IP and for services that don't request
+// a specific family.
+func (client *MockClient) MockIPFamilies(families ...corev1.IPFamily) {
+	client.ipFamilies = families
+}
+
+// getIPFamilies returns the IP families of the mocked Kubernetes cluster.
+func (client *MockClient) getIPFamilies() []corev1.IPFamily {
+	if len(client.ipFamilies) == 0 {
+		return []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	}
+
+	return client.ipFamilies
 }
 
 // Scheme returns the runtime Scheme
@@ -121,6 +167,12 @@ func (client *MockClient) generateIP() string {
 	return fmt.Sprintf("192.168.%d.%d", client.ipCounter/256, client.ipCounter%256)
 }
 
+// generateIPv6 generates a unique IPv6 address.
+func (client *MockClient) generateIPv6() string {
+	client.ipCounter++
+	return fmt.Sprintf("fd00:10:96::%x", client.ipCounter)
+}
+
 // Create creates a new object
 func (client *MockClient) Create(ctx context.Context, object ctrlClient.Object, options ...ctrlClient.CreateOption) error {
 	// Ensure the default values are set.
@@ -167,6 +167,80 @@ var _ = Describe("[mock client]", func() {
 			Expect(service.ObjectMeta.Generation).To(Equal(int64(1)))
 			Expect(service.Spec.ClusterIP).To(Equal("None"))
 		})
+
+		When("the service requests the IPv6 family", func() {
+			It("should assign an IPv6 cluster IP", func() {
+				service := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      "service1",
+					},
+					Spec: corev1.ServiceSpec{
+						IPFamilies: []corev1.IPFamily{corev1.IPv6Protocol},
+					},
+				}
+				err := mockClient.Create(context.TODO(), service)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(service.Spec.ClusterIP).To(Equal("fd00:10:96::1"))
+				Expect(service.Spec.ClusterIPs).To(ConsistOf("fd00:10:96::1"))
+			})
+		})
+	})
+
+	When("the mocked cluster uses specific IP families", func() {
+		When("the cluster is IPv6-only", func() {
+			BeforeEach(func() {
+				mockClient.MockIPFamilies(corev1.IPv6Protocol)
+			})
+
+			It("should only assign IPv6 addresses", func() {
+				pod := createDummyPod()
+				err := mockClient.Create(context.TODO(), pod)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pod.Status.PodIP).To(Equal("::1"))
+				Expect(pod.Status.PodIPs).To(ConsistOf(corev1.PodIP{IP: "::1"}))
+
+				service := &corev1.Service{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "default",
+						Name:      "service1",
+					},
+				}
+				err = mockClient.Create(context.TODO(), service)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(service.Spec.ClusterIP).To(Equal("fd00:10:96::2"))
+				Expect(service.Spec.IPFamilies).To(ConsistOf(corev1.IPv6Protocol))
+			})
+		})
+
+		When("the cluster is dual-stack with IPv6 as primary family", func() {
+			BeforeEach(func() {
+				mockClient.MockIPFamilies(corev1.IPv6Protocol, corev1.IPv4Protocol)
+			})
+
+			It("should use the IPv6 address as primary Pod IP", func() {
+				pod := createDummyPod()
+				err := mockClient.Create(context.TODO(), pod)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pod.Status.PodIP).To(Equal("::1"))
+				Expect(pod.Status.PodIPs).To(Equal([]corev1.PodIP{{IP: "::1"}, {IP: "1.1.0.2"}}))
+			})
+		})
+
+		When("the mock data is cleared", func() {
+			BeforeEach(func() {
+				mockClient.MockIPFamilies(corev1.IPv6Protocol)
+				mockClient.Clear()
+			})
+
+			It("should fall back to the dual-stack default", func() {
+				pod := createDummyPod()
+				err := mockClient.Create(context.TODO(), pod)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pod.Status.PodIP).To(Equal("1.1.0.1"))
+				Expect(pod.Status.PodIPs).To(HaveLen(2))
+			})
+		})
 	})
 
 	When("getting a missing object", func() {
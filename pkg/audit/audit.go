@@ -0,0 +1,114 @@
+/*
+ * audit.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Sink provides an abstraction for storing audit entries durably.
+type Sink interface {
+	// Record stores the audit entry of an action that was taken on the
+	// cluster.
+	Record(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) error
+}
+
+// Recorder creates the audit entries for the disruptive actions of the
+// operator and stores them in all configured sinks. A nil Recorder doesn't
+// record anything.
+type Recorder struct {
+	// sinks contains the sinks that store the audit entries.
+	sinks []Sink
+
+	// operatorInstance defines the instance of the operator that is added to
+	// all audit entries.
+	operatorInstance string
+
+	// log implementation for logging output
+	log logr.Logger
+}
+
+// NewRecorder creates a new Recorder that stores the audit entries in the
+// provided sinks.
+func NewRecorder(log logr.Logger, operatorInstance string, sinks ...Sink) *Recorder {
+	return &Recorder{
+		sinks:            sinks,
+		operatorInstance: operatorInstance,
+		log:              log.WithName("audit"),
+	}
+}
+
+// Record creates an audit entry for the action and stores it in all sinks.
+// The outcome of the action is derived from actionErr. Errors of the sinks
+// are only logged, as an unavailable sink should not block the operator from
+// managing the cluster.
+func (recorder *Recorder) Record(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, action fdbv1beta2.AuditAction, targets []string, reason string, actionErr error) {
+	if recorder == nil || len(recorder.sinks) == 0 {
+		return
+	}
+
+	entry := fdbv1beta2.AuditEntry{
+		Timestamp:        metav1.NewTime(time.Now()),
+		Namespace:        cluster.Namespace,
+		ClusterName:      cluster.Name,
+		Action:           action,
+		Targets:          targets,
+		Reason:           reason,
+		OperatorInstance: recorder.operatorInstance,
+		Outcome:          fdbv1beta2.AuditOutcomeSucceeded,
+	}
+
+	if actionErr != nil {
+		entry.Outcome = fdbv1beta2.AuditOutcomeFailed
+		entry.Error = actionErr.Error()
+	}
+
+	for _, sink := range recorder.sinks {
+		err := sink.Record(ctx, cluster, entry)
+		if err != nil {
+			recorder.log.Error(err, "Error recording audit entry", "namespace", cluster.Namespace, "cluster", cluster.Name, "action", action, "sink", sink)
+		}
+	}
+}
+
+// GetProcessAddressTargets converts the process addresses into the targets of
+// an audit entry.
+func GetProcessAddressTargets(addresses []fdbv1beta2.ProcessAddress) []string {
+	targets := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		targets = append(targets, address.String())
+	}
+
+	return targets
+}
+
+// GetDatabaseKey returns the key of the audit entry in the key range of the
+// cluster. The keys are placed under the lock prefix of the cluster and are
+// ordered by the timestamp of the audit entry.
+func GetDatabaseKey(cluster *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) string {
+	return fmt.Sprintf("%s/audit/%020d/%s", cluster.GetLockPrefix(), entry.Timestamp.UnixNano(), entry.Action)
+}
@@ -0,0 +1,225 @@
+/*
+ * audit_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	mockclient "github.com/FoundationDB/fdb-kubernetes-operator/mock-kubernetes-client/client"
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// testSink stores all recorded audit entries in memory.
+type testSink struct {
+	entries []fdbv1beta2.AuditEntry
+	err     error
+}
+
+// Record stores the audit entry in memory.
+func (sink *testSink) Record(_ context.Context, _ *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) error {
+	if sink.err != nil {
+		return sink.err
+	}
+
+	sink.entries = append(sink.entries, entry)
+	return nil
+}
+
+var _ = Describe("audit", func() {
+	var cluster *fdbv1beta2.FoundationDBCluster
+
+	BeforeEach(func() {
+		cluster = &fdbv1beta2.FoundationDBCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-cluster",
+				Namespace: "test",
+			},
+		}
+	})
+
+	When("recording an action", func() {
+		var sink *testSink
+		var failingSink *testSink
+		var recorder *Recorder
+		var actionErr error
+
+		BeforeEach(func() {
+			sink = &testSink{}
+			failingSink = &testSink{err: fmt.Errorf("sink is unavailable")}
+			recorder = NewRecorder(logr.Discard(), "operator-1", failingSink, sink)
+		})
+
+		JustBeforeEach(func() {
+			recorder.Record(context.Background(), cluster, fdbv1beta2.AuditActionExcludeProcesses, []string{"192.168.0.1:4501"}, "Process groups are marked for removal", actionErr)
+		})
+
+		When("the action succeeded", func() {
+			BeforeEach(func() {
+				actionErr = nil
+			})
+
+			It("should record a succeeded entry in the available sink", func() {
+				Expect(failingSink.entries).To(BeEmpty())
+				Expect(sink.entries).To(HaveLen(1))
+				entry := sink.entries[0]
+				Expect(entry.Namespace).To(Equal("test"))
+				Expect(entry.ClusterName).To(Equal("test-cluster"))
+				Expect(entry.Action).To(Equal(fdbv1beta2.AuditActionExcludeProcesses))
+				Expect(entry.Targets).To(ConsistOf("192.168.0.1:4501"))
+				Expect(entry.Reason).To(Equal("Process groups are marked for removal"))
+				Expect(entry.OperatorInstance).To(Equal("operator-1"))
+				Expect(entry.Outcome).To(Equal(fdbv1beta2.AuditOutcomeSucceeded))
+				Expect(entry.Error).To(BeEmpty())
+				Expect(entry.Timestamp.IsZero()).To(BeFalse())
+			})
+		})
+
+		When("the action failed", func() {
+			BeforeEach(func() {
+				actionErr = fmt.Errorf("timeout")
+			})
+
+			It("should record a failed entry", func() {
+				Expect(sink.entries).To(HaveLen(1))
+				Expect(sink.entries[0].Outcome).To(Equal(fdbv1beta2.AuditOutcomeFailed))
+				Expect(sink.entries[0].Error).To(Equal("timeout"))
+			})
+		})
+
+		When("the recorder is nil", func() {
+			BeforeEach(func() {
+				recorder = nil
+			})
+
+			It("should not record anything", func() {
+				Expect(sink.entries).To(BeEmpty())
+			})
+		})
+	})
+
+	When("getting the process address targets", func() {
+		It("should return the string representation of the addresses", func() {
+			Expect(GetProcessAddressTargets([]fdbv1beta2.ProcessAddress{
+				{StringAddress: "192.168.0.1", Port: 4501},
+				{StringAddress: "192.168.0.2", Port: 4501, Flags: map[string]bool{"tls": true}},
+			})).To(Equal([]string{"192.168.0.1:4501", "192.168.0.2:4501:tls"}))
+		})
+	})
+
+	When("getting the database key", func() {
+		var entry fdbv1beta2.AuditEntry
+
+		BeforeEach(func() {
+			entry = fdbv1beta2.AuditEntry{
+				Timestamp: metav1.Unix(1, 0),
+				Action:    fdbv1beta2.AuditActionKillProcesses,
+			}
+		})
+
+		It("should place the key under the default lock prefix", func() {
+			Expect(GetDatabaseKey(cluster, entry)).To(Equal("\xff\x02/org.foundationdb.kubernetes-operator/audit/00000000001000000000/KillProcesses"))
+		})
+
+		When("a custom lock prefix is defined", func() {
+			BeforeEach(func() {
+				cluster.Spec.LockOptions.LockKeyPrefix = "\xff\x02/custom"
+			})
+
+			It("should place the key under the custom lock prefix", func() {
+				Expect(GetDatabaseKey(cluster, entry)).To(Equal("\xff\x02/custom/audit/00000000001000000000/KillProcesses"))
+			})
+		})
+	})
+
+	When("using the file sink", func() {
+		var filePath string
+
+		BeforeEach(func() {
+			filePath = path.Join(GinkgoT().TempDir(), "audit.log")
+			sink := NewFileSink(filePath)
+			for _, action := range []fdbv1beta2.AuditAction{fdbv1beta2.AuditActionDeletePod, fdbv1beta2.AuditActionDeletePVC} {
+				Expect(sink.Record(context.Background(), cluster, fdbv1beta2.AuditEntry{
+					ClusterName: cluster.Name,
+					Action:      action,
+					Outcome:     fdbv1beta2.AuditOutcomeSucceeded,
+				})).NotTo(HaveOccurred())
+			}
+		})
+
+		It("should append one JSON line per entry", func() {
+			file, err := os.Open(filePath)
+			Expect(err).NotTo(HaveOccurred())
+			defer func() {
+				_ = file.Close()
+			}()
+
+			var actions []fdbv1beta2.AuditAction
+			scanner := bufio.NewScanner(file)
+			for scanner.Scan() {
+				entry := fdbv1beta2.AuditEntry{}
+				Expect(json.Unmarshal(scanner.Bytes(), &entry)).NotTo(HaveOccurred())
+				Expect(entry.ClusterName).To(Equal(cluster.Name))
+				actions = append(actions, entry.Action)
+			}
+			Expect(scanner.Err()).NotTo(HaveOccurred())
+			Expect(actions).To(Equal([]fdbv1beta2.AuditAction{fdbv1beta2.AuditActionDeletePod, fdbv1beta2.AuditActionDeletePVC}))
+		})
+	})
+
+	When("using the Kubernetes sink", func() {
+		var k8sClient *mockclient.MockClient
+		var entry fdbv1beta2.AuditEntry
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(fdbv1beta2.AddToScheme(scheme)).NotTo(HaveOccurred())
+			k8sClient = mockclient.NewMockClient(scheme)
+			entry = fdbv1beta2.AuditEntry{
+				Timestamp:   metav1.Unix(1, 0),
+				Namespace:   cluster.Namespace,
+				ClusterName: cluster.Name,
+				Action:      fdbv1beta2.AuditActionChangeCoordinators,
+				Outcome:     fdbv1beta2.AuditOutcomeSucceeded,
+			}
+
+			Expect(NewKubernetesSink(k8sClient).Record(context.Background(), cluster, entry)).NotTo(HaveOccurred())
+		})
+
+		It("should create an audit event in the namespace of the cluster", func() {
+			event := &fdbv1beta2.FoundationDBAuditEvent{}
+			Expect(k8sClient.Get(context.Background(), types.NamespacedName{Namespace: "test", Name: "test-cluster-changecoordinators-1000000000"}, event)).NotTo(HaveOccurred())
+			Expect(event.Labels).To(HaveKeyWithValue(fdbv1beta2.FDBClusterLabel, cluster.Name))
+			Expect(event.OwnerReferences).To(BeEmpty())
+			Expect(event.Spec.Action).To(Equal(fdbv1beta2.AuditActionChangeCoordinators))
+		})
+	})
+})
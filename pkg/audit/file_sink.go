@@ -0,0 +1,77 @@
+/*
+ * file_sink.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+)
+
+// FileSink provides an implementation of Sink that appends the audit entries
+// as JSON lines to a file.
+type FileSink struct {
+	// path defines the file that the audit entries are appended to.
+	path string
+
+	// lock serializes the writes of concurrent reconciliations.
+	lock sync.Mutex
+}
+
+// NewFileSink creates a new FileSink that appends the audit entries to the
+// file at path. The file is created if it doesn't exist.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+// Record appends the audit entry as a single JSON line to the file. The file
+// is opened for every entry, so the file can be rotated by an external tool.
+func (sink *FileSink) Record(_ context.Context, _ *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	file, err := os.OpenFile(sink.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+
+	return file.Close()
+}
+
+// String returns a description of the sink for logging.
+func (sink *FileSink) String() string {
+	return fmt.Sprintf("file:%s", sink.path)
+}
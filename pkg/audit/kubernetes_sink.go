@@ -0,0 +1,73 @@
+/*
+ * kubernetes_sink.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// KubernetesSink provides an implementation of Sink that creates a
+// FoundationDBAuditEvent resource for every audit entry.
+type KubernetesSink struct {
+	// client is used to create the audit events.
+	client client.Client
+}
+
+// NewKubernetesSink creates a new KubernetesSink that creates the audit events
+// with the provided client.
+func NewKubernetesSink(client client.Client) *KubernetesSink {
+	return &KubernetesSink{client: client}
+}
+
+// Record creates a FoundationDBAuditEvent in the namespace of the cluster.
+// The audit event is not owned by the cluster, so the audit trail is kept when
+// the cluster is deleted.
+func (sink *KubernetesSink) Record(ctx context.Context, cluster *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) error {
+	event := &fdbv1beta2.FoundationDBAuditEvent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetAuditEventName(cluster, entry),
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				fdbv1beta2.FDBClusterLabel: cluster.Name,
+			},
+		},
+		Spec: entry,
+	}
+
+	return sink.client.Create(ctx, event)
+}
+
+// String returns a description of the sink for logging.
+func (sink *KubernetesSink) String() string {
+	return "kubernetes"
+}
+
+// GetAuditEventName returns the name of the FoundationDBAuditEvent for the
+// audit entry.
+func GetAuditEventName(cluster *fdbv1beta2.FoundationDBCluster, entry fdbv1beta2.AuditEntry) string {
+	return fmt.Sprintf("%s-%s-%d", cluster.Name, strings.ToLower(string(entry.Action)), entry.Timestamp.UnixNano())
+}
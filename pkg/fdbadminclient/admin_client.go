@@ -93,6 +93,46 @@ type AdminClient interface {
 	// GetRestoreStatus gets the status of the current restore.
 	GetRestoreStatus() (string, error)
 
+	// StartDisasterRecovery starts replicating the source cluster into this
+	// cluster.
+	StartDisasterRecovery(sourceConnectionString string) error
+
+	// AbortDisasterRecovery aborts replicating the source cluster into this
+	// cluster.
+	AbortDisasterRecovery(sourceConnectionString string) error
+
+	// PauseDisasterRecovery pauses the DR agents.
+	PauseDisasterRecovery(sourceConnectionString string) error
+
+	// ResumeDisasterRecovery resumes the DR agents.
+	ResumeDisasterRecovery(sourceConnectionString string) error
+
+	// SwitchoverDisasterRecovery makes this cluster the primary and starts
+	// replicating it into the source cluster.
+	SwitchoverDisasterRecovery(sourceConnectionString string) error
+
+	// GetDisasterRecoveryStatus gets the status of replicating the source
+	// cluster into this cluster.
+	GetDisasterRecoveryStatus(sourceConnectionString string) (*fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus, error)
+
+	// GetTenant gets the live status of a tenant. This will return nil if the
+	// tenant doesn't exist.
+	GetTenant(name string) (*fdbv1beta2.FoundationDBLiveTenantStatus, error)
+
+	// CreateTenant creates a new tenant in the provided tenant group.
+	CreateTenant(name string, tenantGroup string) error
+
+	// ConfigureTenant assigns a tenant to the provided tenant group. An empty
+	// tenant group removes the tenant from its current group.
+	ConfigureTenant(name string, tenantGroup string) error
+
+	// IsTenantEmpty checks whether a tenant holds any data.
+	IsTenantEmpty(name string) (bool, error)
+
+	// DeleteTenant deletes a tenant. If clearData is true, the data of the
+	// tenant will be cleared before the tenant is deleted.
+	DeleteTenant(name string, clearData bool) error
+
 	// Close shuts down any resources for the client once it is no longer
 	// needed.
 	Close() error
@@ -23,7 +23,6 @@ package mock
 import (
 	"context"
 	"fmt"
-	"net"
 	"strings"
 	"sync"
 	"time"
@@ -49,6 +48,10 @@ type AdminClient struct {
 	Knobs                                    map[string]fdbv1beta2.None
 	FrozenStatus                             *fdbv1beta2.FoundationDBStatus
 	Backups                                  map[string]fdbv1beta2.FoundationDBBackupStatusBackupDetails
+	DisasterRecoveries                       map[string]fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus
+	Tenants                                  map[string]fdbv1beta2.FoundationDBLiveTenantStatus
+	NonEmptyTenants                          map[string]fdbv1beta2.None
+	nextTenantID                             int64
 	clientVersions                           map[string][]string
 	currentCommandLines                      map[string]string
 	VersionProcessGroups                     map[fdbv1beta2.ProcessGroupID]string
@@ -60,9 +63,24 @@ type AdminClient struct {
 	MaxZoneFailuresWithoutLosingData         *int
 	MaxZoneFailuresWithoutLosingAvailability *int
 	MaintenanceZone                          string
+	unavailable                              bool
 	restoreURL                               string
+	RestoreState                             string
 	maintenanceZoneStartTimestamp            time.Time
 	uptimeSecondsForMaintenanceZone          float64
+	// resolvedCoordinators contains the addresses that DNS names of the
+	// coordinators were resolved to. Like a real client, this only resolves
+	// a DNS name when it connects to the coordinators for the first time.
+	resolvedCoordinators map[string]fdbv1beta2.ProcessAddress
+	// faults contains the faults that were injected per method.
+	faults map[string]*injectedFault
+	// callCounts contains the number of calls per method.
+	callCounts map[string]int
+	// lastStatus is the last status that was returned by GetStatus without
+	// being affected by a fault.
+	lastStatus *fdbv1beta2.FoundationDBStatus
+	// faultMutex protects the state of the fault injection.
+	faultMutex sync.Mutex
 }
 
 // adminClientCache provides a cache of mock admin clients.
@@ -95,9 +113,13 @@ func NewMockAdminClientUncast(cluster *fdbv1beta2.FoundationDBCluster, kubeClien
 			currentCommandLines:  make(map[string]string),
 			Knobs:                make(map[string]fdbv1beta2.None),
 			VersionProcessGroups: make(map[fdbv1beta2.ProcessGroupID]string),
+			resolvedCoordinators: make(map[string]fdbv1beta2.ProcessAddress),
 		}
 		adminClientCache[cluster.Name] = cachedClient
 		cachedClient.Backups = make(map[string]fdbv1beta2.FoundationDBBackupStatusBackupDetails)
+		cachedClient.DisasterRecoveries = make(map[string]fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus)
+		cachedClient.Tenants = make(map[string]fdbv1beta2.FoundationDBLiveTenantStatus)
+		cachedClient.NonEmptyTenants = make(map[string]fdbv1beta2.None)
 	} else {
 		cachedClient.Cluster = cluster.DeepCopy()
 	}
@@ -112,6 +134,33 @@ func ClearMockAdminClients() {
 
 // GetStatus gets the database's status
 func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
+	stale, err := client.applyFault("GetStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	client.faultMutex.Lock()
+	lastStatus := client.lastStatus
+	client.faultMutex.Unlock()
+
+	if stale && lastStatus != nil {
+		return lastStatus.DeepCopy(), nil
+	}
+
+	status, err := client.getStatus()
+	if err != nil {
+		return nil, err
+	}
+
+	client.faultMutex.Lock()
+	client.lastStatus = status.DeepCopy()
+	client.faultMutex.Unlock()
+
+	return status, nil
+}
+
+// getStatus generates the database's status from the Pods of the cluster.
+func (client *AdminClient) getStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -166,19 +215,44 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 			processIP = pod.Status.PodIP
 		}
 
+		var dnsName string
+		for _, container := range pod.Spec.Containers {
+			for _, envVar := range container.Env {
+				if envVar.Name == "FDB_DNS_NAME" {
+					dnsName = envVar.Value
+				}
+			}
+		}
+
 		for processIndex := 1; processIndex <= processCount; processIndex++ {
 			var fdbRoles []fdbv1beta2.FoundationDBStatusProcessRoleInfo
 
 			fullAddress := client.Cluster.GetFullAddress(processIP, processIndex)
-			_, ipExcluded := client.ExcludedAddresses[processIP]
+			_, ipExcluded := client.ExcludedAddresses[fullAddress.MachineAddress()]
 			_, addressExcluded := client.ExcludedAddresses[fullAddress.String()]
-			excluded := ipExcluded || addressExcluded
+			_, localityExcluded := client.ExcludedAddresses[(&fdbv1beta2.ProcessGroupStatus{ProcessGroupID: processGroupID}).GetExclusionString()]
+			excluded := ipExcluded || addressExcluded || localityExcluded
 			_, isCoordinator := coordinators[fullAddress.String()]
 			if isCoordinator && !excluded {
 				coordinators[fullAddress.String()] = true
 				fdbRoles = append(fdbRoles, fdbv1beta2.FoundationDBStatusProcessRoleInfo{Role: string(fdbv1beta2.ProcessRoleCoordinator)})
 			}
 
+			if dnsName != "" {
+				dnsAddress := fdbv1beta2.ProcessAddress{StringAddress: dnsName, Port: fullAddress.Port, Flags: fullAddress.Flags}
+				_, isDNSCoordinator := coordinators[dnsAddress.String()]
+				if isDNSCoordinator && !excluded {
+					coordinators[dnsAddress.String()] = true
+					fdbRoles = append(fdbRoles, fdbv1beta2.FoundationDBStatusProcessRoleInfo{Role: string(fdbv1beta2.ProcessRoleCoordinator)})
+
+					if _, resolved := client.resolvedCoordinators[dnsAddress.String()]; !resolved {
+						resolvedAddress := fullAddress
+						resolvedAddress.FromHostname = true
+						client.resolvedCoordinators[dnsAddress.String()] = resolvedAddress
+					}
+				}
+			}
+
 			pClass, err := podmanager.GetProcessClass(client.Cluster, &pod)
 			if err != nil {
 				return nil, err
@@ -209,12 +283,8 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 				locality[key] = value
 			}
 
-			for _, container := range pod.Spec.Containers {
-				for _, envVar := range container.Env {
-					if envVar.Name == "FDB_DNS_NAME" {
-						locality[fdbv1beta2.FDBLocalityDNSNameKey] = envVar.Value
-					}
-				}
+			if dnsName != "" {
+				locality[fdbv1beta2.FDBLocalityDNSNameKey] = dnsName
 			}
 
 			if processCount > 1 {
@@ -307,14 +377,27 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 			return nil, err
 		}
 
+		// A real client reports the address that a DNS name was resolved to.
+		if resolvedAddress, ok := client.resolvedCoordinators[address]; ok {
+			pAddr = resolvedAddress
+		}
+
 		status.Client.Coordinators.Coordinators = append(status.Client.Coordinators.Coordinators, fdbv1beta2.FoundationDBStatusCoordinator{
 			Address:   pAddr,
 			Reachable: reachable,
 		})
 	}
 
-	status.Client.DatabaseStatus.Available = true
-	status.Client.DatabaseStatus.Healthy = true
+	reachableCoordinators := 0
+	for _, coordinator := range status.Client.Coordinators.Coordinators {
+		if coordinator.Reachable {
+			reachableCoordinators++
+		}
+	}
+	status.Client.Coordinators.QuorumReachable = reachableCoordinators > len(status.Client.Coordinators.Coordinators)/2
+
+	status.Client.DatabaseStatus.Available = !client.unavailable
+	status.Client.DatabaseStatus.Healthy = !client.unavailable
 
 	if client.DatabaseConfiguration == nil {
 		status.Cluster.Layers.Error = "configurationMissing"
@@ -358,6 +441,11 @@ func (client *AdminClient) GetStatus() (*fdbv1beta2.FoundationDBStatus, error) {
 
 // ConfigureDatabase changes the database configuration
 func (client *AdminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseConfiguration, _ bool, version string) error {
+	_, err := client.applyFault("ConfigureDatabase")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -379,6 +467,11 @@ func (client *AdminClient) ConfigureDatabase(configuration fdbv1beta2.DatabaseCo
 // ExcludeProcesses starts evacuating processes so that they can be removed
 // from the database.
 func (client *AdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+	_, err := client.applyFault("ExcludeProcesses")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -392,6 +485,11 @@ func (client *AdminClient) ExcludeProcesses(addresses []fdbv1beta2.ProcessAddres
 // IncludeProcesses removes processes from the exclusion list and allows
 // them to take on roles again.
 func (client *AdminClient) IncludeProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+	_, err := client.applyFault("IncludeProcesses")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -412,6 +510,15 @@ func (client *AdminClient) IncludeProcesses(addresses []fdbv1beta2.ProcessAddres
 // The list returned by this method will be the addresses that are *not*
 // safe to remove.
 func (client *AdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress) ([]fdbv1beta2.ProcessAddress, error) {
+	stale, err := client.applyFault("CanSafelyRemove")
+	if err != nil {
+		return nil, err
+	}
+
+	if stale {
+		return addresses, nil
+	}
+
 	skipExclude := map[string]fdbv1beta2.None{}
 
 	// Check which process groups have the skip exclusion flag or are already
@@ -450,16 +557,29 @@ func (client *AdminClient) CanSafelyRemove(addresses []fdbv1beta2.ProcessAddress
 // GetExclusions gets a list of the addresses currently excluded from the
 // database.
 func (client *AdminClient) GetExclusions() ([]fdbv1beta2.ProcessAddress, error) {
+	_, err := client.applyFault("GetExclusions")
+	if err != nil {
+		return nil, err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
-	pAddrs := make([]fdbv1beta2.ProcessAddress, len(client.ExcludedAddresses))
+	pAddrs := make([]fdbv1beta2.ProcessAddress, 0, len(client.ExcludedAddresses))
 	for addr := range client.ExcludedAddresses {
-		pAddrs = append(pAddrs, fdbv1beta2.ProcessAddress{
-			IPAddress: net.ParseIP(addr),
-			Port:      0,
-			Flags:     nil,
-		})
+		// Exclusions based on a locality are not an address, the real admin client
+		// reports them with the locality as string address.
+		if strings.HasPrefix(addr, "locality_") {
+			pAddrs = append(pAddrs, fdbv1beta2.ProcessAddress{StringAddress: addr})
+			continue
+		}
+
+		pAddr, err := fdbv1beta2.ParseProcessAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		pAddrs = append(pAddrs, pAddr)
 	}
 
 	return pAddrs, nil
@@ -467,6 +587,11 @@ func (client *AdminClient) GetExclusions() ([]fdbv1beta2.ProcessAddress, error)
 
 // KillProcesses restarts processes
 func (client *AdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress) error {
+	_, err := client.applyFault("KillProcesses")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	for _, addr := range addresses {
 		client.KilledAddresses[addr.String()] = fdbv1beta2.None{}
@@ -492,6 +617,11 @@ func (client *AdminClient) KillProcesses(addresses []fdbv1beta2.ProcessAddress)
 
 // ChangeCoordinators changes the coordinator set
 func (client *AdminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessAddress) (string, error) {
+	_, err := client.applyFault("ChangeCoordinators")
+	if err != nil {
+		return "", err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -509,11 +639,17 @@ func (client *AdminClient) ChangeCoordinators(addresses []fdbv1beta2.ProcessAddr
 	}
 
 	connectionString.Coordinators = newCoord
+	client.resolvedCoordinators = make(map[string]fdbv1beta2.ProcessAddress)
 	return connectionString.String(), err
 }
 
 // GetConnectionString fetches the latest connection string.
 func (client *AdminClient) GetConnectionString() (string, error) {
+	_, err := client.applyFault("GetConnectionString")
+	if err != nil {
+		return "", err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -523,6 +659,11 @@ func (client *AdminClient) GetConnectionString() (string, error) {
 // VersionSupported reports whether we can support a cluster with a given
 // version.
 func (client *AdminClient) VersionSupported(versionString string) (bool, error) {
+	_, err := client.applyFault("VersionSupported")
+	if err != nil {
+		return false, err
+	}
+
 	version, err := fdbv1beta2.ParseFdbVersion(versionString)
 	if err != nil {
 		return false, err
@@ -538,11 +679,21 @@ func (client *AdminClient) VersionSupported(versionString string) (bool, error)
 // GetProtocolVersion determines the protocol version that is used by a
 // version of FDB.
 func (client *AdminClient) GetProtocolVersion(version string) (string, error) {
+	_, err := client.applyFault("GetProtocolVersion")
+	if err != nil {
+		return "", err
+	}
+
 	return version, nil
 }
 
 // StartBackup starts a new backup.
 func (client *AdminClient) StartBackup(url string, snapshotPeriodSeconds int) error {
+	_, err := client.applyFault("StartBackup")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -556,6 +707,11 @@ func (client *AdminClient) StartBackup(url string, snapshotPeriodSeconds int) er
 
 // PauseBackups pauses backups.
 func (client *AdminClient) PauseBackups() error {
+	_, err := client.applyFault("PauseBackups")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -568,6 +724,11 @@ func (client *AdminClient) PauseBackups() error {
 
 // ResumeBackups resumes backups.
 func (client *AdminClient) ResumeBackups() error {
+	_, err := client.applyFault("ResumeBackups")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -580,6 +741,11 @@ func (client *AdminClient) ResumeBackups() error {
 
 // ModifyBackup reconfigures the backup.
 func (client *AdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
+	_, err := client.applyFault("ModifyBackup")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -591,6 +757,11 @@ func (client *AdminClient) ModifyBackup(snapshotPeriodSeconds int) error {
 
 // StopBackup stops a backup.
 func (client *AdminClient) StopBackup(url string) error {
+	_, err := client.applyFault("StopBackup")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -606,6 +777,11 @@ func (client *AdminClient) StopBackup(url string) error {
 
 // GetBackupStatus gets the status of the current backup.
 func (client *AdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackupStatus, error) {
+	_, err := client.applyFault("GetBackupStatus")
+	if err != nil {
+		return nil, err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -618,6 +794,33 @@ func (client *AdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackup
 		status.Status.Running = backup.Running
 		status.BackupAgentsPaused = backup.Paused
 		status.SnapshotIntervalSeconds = backup.SnapshotPeriodSeconds
+
+		if backup.LatestRestorableVersion != nil {
+			status.LatestRestorablePoint = &fdbv1beta2.FoundationDBLiveBackupStatusRestorablePoint{
+				FoundationDBLiveBackupStatusVersion: fdbv1beta2.FoundationDBLiveBackupStatusVersion{
+					Version: *backup.LatestRestorableVersion,
+				},
+				LagSeconds: float64(backup.SecondsBehind),
+			}
+
+			if backup.LatestRestorableTime != nil {
+				status.LatestRestorablePoint.EpochSeconds = float64(backup.LatestRestorableTime.Unix())
+			}
+		}
+
+		if backup.CurrentSnapshotStartTime != nil {
+			status.CurrentSnapshot = &fdbv1beta2.FoundationDBLiveBackupStatusSnapshot{
+				Begin: fdbv1beta2.FoundationDBLiveBackupStatusVersion{
+					EpochSeconds: float64(backup.CurrentSnapshotStartTime.Unix()),
+				},
+				IntervalSeconds:  backup.SnapshotPeriodSeconds,
+				ExpectedProgress: float64(backup.SnapshotProgressPercent),
+			}
+		}
+
+		for _, message := range backup.Errors {
+			status.Errors = append(status.Errors, fdbv1beta2.FoundationDBLiveBackupStatusError{Message: message})
+		}
 	}
 
 	return status, nil
@@ -625,6 +828,11 @@ func (client *AdminClient) GetBackupStatus() (*fdbv1beta2.FoundationDBLiveBackup
 
 // StartRestore starts a new restore.
 func (client *AdminClient) StartRestore(url string, _ []fdbv1beta2.FoundationDBKeyRange) error {
+	_, err := client.applyFault("StartRestore")
+	if err != nil {
+		return err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
@@ -634,12 +842,255 @@ func (client *AdminClient) StartRestore(url string, _ []fdbv1beta2.FoundationDBK
 
 // GetRestoreStatus gets the status of the current restore.
 func (client *AdminClient) GetRestoreStatus() (string, error) {
+	_, err := client.applyFault("GetRestoreStatus")
+	if err != nil {
+		return "", err
+	}
+
 	adminClientMutex.Lock()
 	defer adminClientMutex.Unlock()
 
+	if client.RestoreState != "" {
+		return fmt.Sprintf("%s\nState: %s\n", client.restoreURL, client.RestoreState), nil
+	}
+
 	return fmt.Sprintf("%s\n", client.restoreURL), nil
 }
 
+// StartDisasterRecovery starts replicating the source cluster into this
+// cluster.
+func (client *AdminClient) StartDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.applyFault("StartDisasterRecovery")
+	if err != nil {
+		return err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if client.DisasterRecoveries[sourceConnectionString].Running {
+		return fmt.Errorf("DR from %s is already running", sourceConnectionString)
+	}
+
+	client.DisasterRecoveries[sourceConnectionString] = fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{
+		Running: true,
+	}
+	return nil
+}
+
+// AbortDisasterRecovery aborts replicating the source cluster into this
+// cluster.
+func (client *AdminClient) AbortDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.applyFault("AbortDisasterRecovery")
+	if err != nil {
+		return err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if !client.DisasterRecoveries[sourceConnectionString].Running {
+		return fmt.Errorf("no DR found from %s", sourceConnectionString)
+	}
+
+	delete(client.DisasterRecoveries, sourceConnectionString)
+	return nil
+}
+
+// PauseDisasterRecovery pauses the DR agents.
+func (client *AdminClient) PauseDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.applyFault("PauseDisasterRecovery")
+	if err != nil {
+		return err
+	}
+
+	return client.setDisasterRecoveryPaused(sourceConnectionString, true)
+}
+
+// ResumeDisasterRecovery resumes the DR agents.
+func (client *AdminClient) ResumeDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.applyFault("ResumeDisasterRecovery")
+	if err != nil {
+		return err
+	}
+
+	return client.setDisasterRecoveryPaused(sourceConnectionString, false)
+}
+
+// setDisasterRecoveryPaused updates the paused state of the DR.
+func (client *AdminClient) setDisasterRecoveryPaused(sourceConnectionString string, paused bool) error {
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	dr, present := client.DisasterRecoveries[sourceConnectionString]
+	if !present {
+		return fmt.Errorf("no DR found from %s", sourceConnectionString)
+	}
+
+	dr.Paused = paused
+	client.DisasterRecoveries[sourceConnectionString] = dr
+	return nil
+}
+
+// SwitchoverDisasterRecovery makes this cluster the primary and starts
+// replicating it into the source cluster.
+func (client *AdminClient) SwitchoverDisasterRecovery(sourceConnectionString string) error {
+	_, err := client.applyFault("SwitchoverDisasterRecovery")
+	if err != nil {
+		return err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	dr, present := client.DisasterRecoveries[sourceConnectionString]
+	if !present || !dr.Running {
+		return fmt.Errorf("no DR found from %s", sourceConnectionString)
+	}
+
+	var sourceClient *AdminClient
+	for _, cachedClient := range adminClientCache {
+		if cachedClient.Cluster.Status.ConnectionString == sourceConnectionString {
+			sourceClient = cachedClient
+			break
+		}
+	}
+
+	if sourceClient == nil {
+		return fmt.Errorf("no cluster found with connection string %s", sourceConnectionString)
+	}
+
+	delete(client.DisasterRecoveries, sourceConnectionString)
+	sourceClient.DisasterRecoveries[client.Cluster.Status.ConnectionString] = fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus{
+		Running: true,
+		Paused:  dr.Paused,
+	}
+	return nil
+}
+
+// GetDisasterRecoveryStatus gets the status of replicating the source cluster
+// into this cluster.
+func (client *AdminClient) GetDisasterRecoveryStatus(sourceConnectionString string) (*fdbv1beta2.FoundationDBLiveDisasterRecoveryStatus, error) {
+	_, err := client.applyFault("GetDisasterRecoveryStatus")
+	if err != nil {
+		return nil, err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	status := client.DisasterRecoveries[sourceConnectionString]
+	return &status, nil
+}
+
+// GetTenant gets the live status of a tenant. This will return nil if the
+// tenant doesn't exist.
+func (client *AdminClient) GetTenant(name string) (*fdbv1beta2.FoundationDBLiveTenantStatus, error) {
+	_, err := client.applyFault("GetTenant")
+	if err != nil {
+		return nil, err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	tenant, present := client.Tenants[name]
+	if !present {
+		return nil, nil
+	}
+
+	return &tenant, nil
+}
+
+// CreateTenant creates a new tenant in the provided tenant group.
+func (client *AdminClient) CreateTenant(name string, tenantGroup string) error {
+	_, err := client.applyFault("CreateTenant")
+	if err != nil {
+		return err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if _, present := client.Tenants[name]; present {
+		return fmt.Errorf("tenant %s already exists", name)
+	}
+
+	client.nextTenantID++
+	client.Tenants[name] = fdbv1beta2.FoundationDBLiveTenantStatus{
+		ID:          client.nextTenantID,
+		State:       "ready",
+		TenantGroup: tenantGroup,
+	}
+	return nil
+}
+
+// ConfigureTenant assigns a tenant to the provided tenant group. An empty
+// tenant group removes the tenant from its current group.
+func (client *AdminClient) ConfigureTenant(name string, tenantGroup string) error {
+	_, err := client.applyFault("ConfigureTenant")
+	if err != nil {
+		return err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	tenant, present := client.Tenants[name]
+	if !present {
+		return fmt.Errorf("tenant %s does not exist", name)
+	}
+
+	tenant.TenantGroup = tenantGroup
+	client.Tenants[name] = tenant
+	return nil
+}
+
+// IsTenantEmpty checks whether a tenant holds any data.
+func (client *AdminClient) IsTenantEmpty(name string) (bool, error) {
+	_, err := client.applyFault("IsTenantEmpty")
+	if err != nil {
+		return false, err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if _, present := client.Tenants[name]; !present {
+		return false, fmt.Errorf("tenant %s does not exist", name)
+	}
+
+	_, nonEmpty := client.NonEmptyTenants[name]
+	return !nonEmpty, nil
+}
+
+// DeleteTenant deletes a tenant. If clearData is true, the data of the tenant
+// will be cleared before the tenant is deleted.
+func (client *AdminClient) DeleteTenant(name string, clearData bool) error {
+	_, err := client.applyFault("DeleteTenant")
+	if err != nil {
+		return err
+	}
+
+	adminClientMutex.Lock()
+	defer adminClientMutex.Unlock()
+
+	if _, present := client.Tenants[name]; !present {
+		return fmt.Errorf("tenant %s does not exist", name)
+	}
+
+	if clearData {
+		delete(client.NonEmptyTenants, name)
+	}
+
+	if _, nonEmpty := client.NonEmptyTenants[name]; nonEmpty {
+		return fmt.Errorf("tenant %s is not empty", name)
+	}
+
+	delete(client.Tenants, name)
+	return nil
+}
+
 // MockClientVersion returns a mocked client version
 func (client *AdminClient) MockClientVersion(version string, clients []string) {
 	adminClientMutex.Lock()
@@ -670,6 +1121,12 @@ func (client *AdminClient) MockLocalityInfo(processGroupID fdbv1beta2.ProcessGro
 	client.localityInfo[processGroupID] = locality
 }
 
+// MockAvailability updates the mock for whether the database should be
+// reported as available in the cluster status.
+func (client *AdminClient) MockAvailability(available bool) {
+	client.unavailable = !available
+}
+
 // MockIncorrectCommandLine updates the mock for whether a process group should
 // be have an incorrect command-line.
 func (client *AdminClient) MockIncorrectCommandLine(processGroupID fdbv1beta2.ProcessGroupID, incorrect bool) {
@@ -682,6 +1139,11 @@ func (client *AdminClient) MockIncorrectCommandLine(processGroupID fdbv1beta2.Pr
 // Close shuts down any resources for the client once it is no longer
 // needed.
 func (client *AdminClient) Close() error {
+	_, err := client.applyFault("Close")
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -712,6 +1174,11 @@ func (client *AdminClient) UnfreezeStatus() {
 
 // GetCoordinatorSet gets the current coordinators from the status
 func (client *AdminClient) GetCoordinatorSet() (map[string]fdbv1beta2.None, error) {
+	_, err := client.applyFault("GetCoordinatorSet")
+	if err != nil {
+		return nil, err
+	}
+
 	status, err := client.GetStatus()
 	if err != nil {
 		return nil, err
@@ -730,11 +1197,21 @@ func (client *AdminClient) SetKnobs(knobs []string) {
 
 // GetMaintenanceZone gets current maintenance zone, if any
 func (client *AdminClient) GetMaintenanceZone() (string, error) {
+	_, err := client.applyFault("GetMaintenanceZone")
+	if err != nil {
+		return "", err
+	}
+
 	return client.MaintenanceZone, nil
 }
 
 // SetMaintenanceZone places zone into maintenance mode
 func (client *AdminClient) SetMaintenanceZone(zone string, _ int) error {
+	_, err := client.applyFault("SetMaintenanceZone")
+	if err != nil {
+		return err
+	}
+
 	client.MaintenanceZone = zone
 	client.maintenanceZoneStartTimestamp = time.Now()
 	return nil
@@ -742,6 +1219,11 @@ func (client *AdminClient) SetMaintenanceZone(zone string, _ int) error {
 
 // ResetMaintenanceMode resets the maintenance zone
 func (client *AdminClient) ResetMaintenanceMode() error {
+	_, err := client.applyFault("ResetMaintenanceMode")
+	if err != nil {
+		return err
+	}
+
 	client.MaintenanceZone = ""
 	return nil
 }
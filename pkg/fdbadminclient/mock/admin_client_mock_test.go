@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -260,6 +261,144 @@ var _ = Describe("mock_client", func() {
 			})
 		})
 	})
+
+	When("a process is excluded by its locality", func() {
+		var adminClient *AdminClient
+
+		BeforeEach(func() {
+			cluster := internal.CreateDefaultCluster()
+			Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			pod, err := internal.GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(context.TODO(), pod)).NotTo(HaveOccurred())
+
+			adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.ExcludeProcesses([]fdbv1beta2.ProcessAddress{{StringAddress: "locality_instance_id:storage-1"}})).NotTo(HaveOccurred())
+		})
+
+		It("should report the locality as exclusion", func() {
+			exclusions, err := adminClient.GetExclusions()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exclusions).To(ConsistOf(fdbv1beta2.ProcessAddress{StringAddress: "locality_instance_id:storage-1"}))
+		})
+
+		It("should report the process as excluded", func() {
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status.Cluster.Processes).To(HaveLen(1))
+			for _, process := range status.Cluster.Processes {
+				Expect(process.Excluded).To(BeTrue())
+			}
+		})
+	})
+
+	When("the cluster uses IPv6 addresses", func() {
+		var adminClient *AdminClient
+		var cluster *fdbv1beta2.FoundationDBCluster
+		var processAddress fdbv1beta2.ProcessAddress
+		targetProcess := "storage-1"
+
+		BeforeEach(func() {
+			k8sClient.MockIPFamilies(corev1.IPv6Protocol)
+			cluster = internal.CreateDefaultCluster()
+			cluster.Spec.Routing.PodIPFamily = pointer.Int(6)
+			Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+			pod, err := internal.GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Create(context.TODO(), pod)).NotTo(HaveOccurred())
+
+			adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
+			Expect(err).NotTo(HaveOccurred())
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, process := range status.Cluster.Processes {
+				if process.Locality[fdbv1beta2.FDBLocalityInstanceIDKey] != targetProcess {
+					continue
+				}
+
+				processAddress = process.Address
+				break
+			}
+		})
+
+		It("should report the IPv6 address of the process", func() {
+			Expect(processAddress.IPAddress).NotTo(BeNil())
+			Expect(processAddress.IPAddress.To4()).To(BeNil())
+			Expect(processAddress.String()).To(HavePrefix("["))
+		})
+
+		When("the process is excluded by its IP address", func() {
+			BeforeEach(func() {
+				Expect(adminClient.ExcludeProcesses([]fdbv1beta2.ProcessAddress{{IPAddress: processAddress.IPAddress}})).NotTo(HaveOccurred())
+			})
+
+			It("should report the process as excluded", func() {
+				status, err := adminClient.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Cluster.Processes).To(HaveLen(1))
+				for _, process := range status.Cluster.Processes {
+					Expect(process.Excluded).To(BeTrue())
+				}
+
+				exclusions, err := adminClient.GetExclusions()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exclusions).To(ConsistOf(fdbv1beta2.ProcessAddress{IPAddress: processAddress.IPAddress}))
+			})
+
+			It("should use the IP address without brackets", func() {
+				Expect(adminClient.ExcludedAddresses).To(HaveKey(processAddress.IPAddress.String()))
+				Expect(processAddress.IPAddress.String()).NotTo(HavePrefix("["))
+			})
+		})
+
+		When("the process is excluded by its full address", func() {
+			BeforeEach(func() {
+				Expect(adminClient.ExcludeProcesses([]fdbv1beta2.ProcessAddress{processAddress})).NotTo(HaveOccurred())
+			})
+
+			It("should report the bracketed address as excluded", func() {
+				exclusions, err := adminClient.GetExclusions()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(exclusions).To(HaveLen(1))
+				Expect(exclusions[0].String()).To(Equal(processAddress.String()))
+			})
+		})
+
+		When("the process is a coordinator", func() {
+			var connectionString string
+
+			BeforeEach(func() {
+				var err error
+				adminClient.Cluster.Status.ConnectionString = "operator_test:abcd@" + processAddress.String()
+				connectionString, err = adminClient.ChangeCoordinators([]fdbv1beta2.ProcessAddress{processAddress})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should use the bracketed address in the connection string", func() {
+				parsed, err := fdbv1beta2.ParseConnectionString(connectionString)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed.Coordinators).To(ConsistOf(processAddress.String()))
+
+				coordinator, err := fdbv1beta2.ParseProcessAddress(parsed.Coordinators[0])
+				Expect(err).NotTo(HaveOccurred())
+				Expect(coordinator.IPAddress.Equal(processAddress.IPAddress)).To(BeTrue())
+			})
+
+			It("should report the coordinator role", func() {
+				status, err := adminClient.GetStatus()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Client.Coordinators.Coordinators).To(HaveLen(1))
+				Expect(status.Client.Coordinators.Coordinators[0].Reachable).To(BeTrue())
+				Expect(status.Client.Coordinators.Coordinators[0].Address.String()).To(Equal(processAddress.String()))
+			})
+		})
+	})
 })
 
 func getCommandlineForProcessFromStatus(status *fdbv1beta2.FoundationDBStatus, targetProcess string) string {
@@ -0,0 +1,161 @@
+/*
+ * fault_injection.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/fdbadminclient"
+)
+
+// Fault describes how the calls to a method of the mock admin client fail.
+// By default a fault affects every call to the method after it was injected.
+type Fault struct {
+	// Error is returned by the affected calls instead of running them.
+	Error error
+
+	// Latency is added to the affected calls before they run.
+	Latency time.Duration
+
+	// Stale makes the affected calls return an outdated result. GetStatus
+	// returns the status of the last call that was not affected, and
+	// CanSafelyRemove reports all addresses as not safe to remove. Other
+	// methods ignore this setting.
+	Stale bool
+
+	// Skip defines how many calls are not affected before the fault takes
+	// effect. Setting this to N-1 and Times to 1 affects only the Nth call.
+	Skip int
+
+	// Times defines how many calls are affected. If this is 0 all calls
+	// after the skipped calls are affected.
+	Times int
+
+	// Every defines that only every Nth call after the skipped calls is
+	// affected, starting with the first one. Combined with Stale this makes
+	// the results flap between the current and the outdated result.
+	Every int
+}
+
+// injectedFault tracks the calls that a fault has seen.
+type injectedFault struct {
+	// fault defines which calls are affected.
+	fault Fault
+	// calls is the number of calls since the fault was injected.
+	calls int
+	// affected is the number of calls that were affected by the fault.
+	affected int
+}
+
+// isAffected records a new call and returns true if the call is affected by
+// the fault.
+func (injected *injectedFault) isAffected() bool {
+	injected.calls++
+	if injected.calls <= injected.fault.Skip {
+		return false
+	}
+
+	if injected.fault.Every > 1 && (injected.calls-injected.fault.Skip-1)%injected.fault.Every != 0 {
+		return false
+	}
+
+	if injected.fault.Times > 0 && injected.affected >= injected.fault.Times {
+		return false
+	}
+
+	injected.affected++
+	return true
+}
+
+// InjectFault makes the calls to the given method of the admin client fail
+// as described by the fault. The method must be a method of the
+// fdbadminclient.AdminClient interface that returns an error. This replaces
+// any fault that was injected for the method before.
+func (client *AdminClient) InjectFault(method string, fault Fault) error {
+	methodType, ok := reflect.TypeOf((*fdbadminclient.AdminClient)(nil)).Elem().MethodByName(method)
+	if !ok {
+		return fmt.Errorf("admin client has no method %s", method)
+	}
+
+	outputs := methodType.Type.NumOut()
+	if outputs == 0 || methodType.Type.Out(outputs-1) != reflect.TypeOf((*error)(nil)).Elem() {
+		return fmt.Errorf("method %s of the admin client does not return an error", method)
+	}
+
+	client.faultMutex.Lock()
+	defer client.faultMutex.Unlock()
+
+	if client.faults == nil {
+		client.faults = map[string]*injectedFault{}
+	}
+
+	client.faults[method] = &injectedFault{fault: fault}
+	return nil
+}
+
+// ClearFaults removes all faults that were injected into the admin client.
+func (client *AdminClient) ClearFaults() {
+	client.faultMutex.Lock()
+	defer client.faultMutex.Unlock()
+
+	client.faults = nil
+}
+
+// GetCallCount returns how many times the given method of the admin client
+// was called, including the calls that were affected by a fault.
+func (client *AdminClient) GetCallCount(method string) int {
+	client.faultMutex.Lock()
+	defer client.faultMutex.Unlock()
+
+	return client.callCounts[method]
+}
+
+// applyFault records a call to the given method and applies the injected
+// fault if the call is affected. This returns true if the call should return
+// an outdated result, and the error of the fault.
+func (client *AdminClient) applyFault(method string) (bool, error) {
+	client.faultMutex.Lock()
+	if client.callCounts == nil {
+		client.callCounts = map[string]int{}
+	}
+	client.callCounts[method]++
+
+	injected, ok := client.faults[method]
+	if !ok || !injected.isAffected() {
+		client.faultMutex.Unlock()
+		return false, nil
+	}
+
+	fault := injected.fault
+	client.faultMutex.Unlock()
+
+	if fault.Latency > 0 {
+		time.Sleep(fault.Latency)
+	}
+
+	if fault.Error != nil {
+		return false, fault.Error
+	}
+
+	return fault.Stale, nil
+}
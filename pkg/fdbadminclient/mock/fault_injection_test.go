@@ -0,0 +1,177 @@
+/*
+ * fault_injection_test.go
+ *
+ * This source file is part of the FoundationDB open source project
+ *
+ * Copyright 2023 Apple Inc. and the FoundationDB project authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	fdbv1beta2 "github.com/FoundationDB/fdb-kubernetes-operator/api/v1beta2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("fault_injection", func() {
+	var adminClient *AdminClient
+	var cluster *fdbv1beta2.FoundationDBCluster
+	var addresses []fdbv1beta2.ProcessAddress
+	injectedErr := fmt.Errorf("injected error")
+
+	BeforeEach(func() {
+		cluster = internal.CreateDefaultCluster()
+		Expect(internal.NormalizeClusterSpec(cluster, internal.DeprecationOptions{})).NotTo(HaveOccurred())
+		Expect(k8sClient.Create(context.TODO(), cluster)).NotTo(HaveOccurred())
+
+		pod, err := internal.GetPod(cluster, fdbv1beta2.ProcessClassStorage, 1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Create(context.TODO(), pod)).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(pod), pod)).NotTo(HaveOccurred())
+
+		adminClient, err = NewMockAdminClientUncast(cluster, k8sClient)
+		Expect(err).NotTo(HaveOccurred())
+
+		addresses = []fdbv1beta2.ProcessAddress{cluster.GetFullAddress(pod.Status.PodIP, 1)}
+	})
+
+	When("injecting a fault into an unknown method", func() {
+		It("should return an error", func() {
+			Expect(adminClient.InjectFault("Unknown", Fault{Error: injectedErr})).To(HaveOccurred())
+		})
+	})
+
+	When("injecting a fault into a method without an error", func() {
+		It("should return an error", func() {
+			Expect(adminClient.InjectFault("SetKnobs", Fault{Error: injectedErr})).To(HaveOccurred())
+		})
+	})
+
+	When("injecting an error", func() {
+		BeforeEach(func() {
+			Expect(adminClient.InjectFault("ExcludeProcesses", Fault{Error: injectedErr})).NotTo(HaveOccurred())
+		})
+
+		It("should return the error without running the call", func() {
+			Expect(adminClient.ExcludeProcesses(addresses)).To(MatchError(injectedErr))
+			Expect(adminClient.ExcludedAddresses).To(BeEmpty())
+			Expect(adminClient.GetCallCount("ExcludeProcesses")).To(Equal(1))
+		})
+
+		It("should not affect other methods", func() {
+			_, err := adminClient.GetExclusions()
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		When("the faults are cleared", func() {
+			BeforeEach(func() {
+				adminClient.ClearFaults()
+			})
+
+			It("should run the call", func() {
+				Expect(adminClient.ExcludeProcesses(addresses)).NotTo(HaveOccurred())
+				Expect(adminClient.ExcludedAddresses).To(HaveLen(1))
+			})
+		})
+	})
+
+	When("injecting an error into the nth call", func() {
+		BeforeEach(func() {
+			Expect(adminClient.InjectFault("GetStatus", Fault{Error: injectedErr, Skip: 2, Times: 1})).NotTo(HaveOccurred())
+		})
+
+		It("should only fail the nth call", func() {
+			var errs []error
+			for i := 0; i < 5; i++ {
+				_, err := adminClient.GetStatus()
+				errs = append(errs, err)
+			}
+
+			Expect(errs).To(Equal([]error{nil, nil, injectedErr, nil, nil}))
+			Expect(adminClient.GetCallCount("GetStatus")).To(Equal(5))
+		})
+	})
+
+	When("injecting latency", func() {
+		BeforeEach(func() {
+			Expect(adminClient.InjectFault("GetConnectionString", Fault{Latency: 50 * time.Millisecond})).NotTo(HaveOccurred())
+		})
+
+		It("should delay the call", func() {
+			start := time.Now()
+			_, err := adminClient.GetConnectionString()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+		})
+	})
+
+	When("injecting a stale status", func() {
+		var initialStatus *fdbv1beta2.FoundationDBStatus
+
+		BeforeEach(func() {
+			var err error
+			initialStatus, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(adminClient.ExcludeProcesses(addresses)).NotTo(HaveOccurred())
+			Expect(adminClient.InjectFault("GetStatus", Fault{Stale: true, Every: 2})).NotTo(HaveOccurred())
+		})
+
+		It("should alternate between the stale and the current status", func() {
+			status, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(initialStatus))
+
+			excludedStatus, err := adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(excludedStatus).NotTo(Equal(initialStatus))
+
+			Expect(adminClient.IncludeProcesses(addresses)).NotTo(HaveOccurred())
+
+			status, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(excludedStatus))
+
+			status, err = adminClient.GetStatus()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(status).To(Equal(initialStatus))
+		})
+	})
+
+	When("injecting a stale result into CanSafelyRemove", func() {
+		BeforeEach(func() {
+			Expect(adminClient.ExcludeProcesses(addresses)).NotTo(HaveOccurred())
+			Expect(adminClient.InjectFault("CanSafelyRemove", Fault{Stale: true, Times: 1})).NotTo(HaveOccurred())
+		})
+
+		It("should report the addresses as unsafe until the fault is used up", func() {
+			remaining, err := adminClient.CanSafelyRemove(addresses)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(Equal(addresses))
+
+			remaining, err = adminClient.CanSafelyRemove(addresses)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(remaining).To(BeEmpty())
+		})
+	})
+})
@@ -48,4 +48,5 @@ var _ = BeforeSuite(func() {
 
 var _ = AfterEach(func() {
 	k8sClient.Clear()
+	ClearMockAdminClients()
 })
@@ -2,7 +2,7 @@
 set -o errexit
 
 # We have to install the FDB client libraries
-export FDB_VERSION=6.2.29
+export FDB_VERSION=7.1.26
 export FDB_WEBSITE=https://github.com/apple/foundationdb/releases/download
 curl --fail -L ${FDB_WEBSITE}/${FDB_VERSION}/foundationdb-clients_${FDB_VERSION}-1_amd64.deb -o /tmp/fdb.deb && dpkg -i /tmp/fdb.deb && rm /tmp/fdb.deb
 # Some tests require the presence of kubectl, for more information about the installation see: https://kubernetes.io/docs/tasks/tools/install-kubectl-linux/
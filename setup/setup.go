@@ -21,6 +21,7 @@
 package setup
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
@@ -35,6 +36,10 @@ import (
 	"github.com/FoundationDB/fdb-kubernetes-operator/controllers"
 	"github.com/FoundationDB/fdb-kubernetes-operator/fdbclient"
 	"github.com/FoundationDB/fdb-kubernetes-operator/internal"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/sharding"
+	"github.com/FoundationDB/fdb-kubernetes-operator/internal/tracing"
+	"github.com/FoundationDB/fdb-kubernetes-operator/pkg/audit"
+	"go.opentelemetry.io/otel"
 	"gopkg.in/natefinch/lumberjack.v2"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -56,19 +61,31 @@ type Options struct {
 	EnableRestartIncompatibleProcesses bool
 	ServerSideApply                    bool
 	EnableRecoveryState                bool
+	EnableConversionWebhook            bool
+	EnableSharding                     bool
 	MetricsAddr                        string
 	LeaderElectionID                   string
 	LogFile                            string
 	LabelSelector                      string
 	WatchNamespace                     string
+	AuditSinks                         string
+	AuditLogFile                       string
+	TracingExporter                    string
+	TracingFile                        string
+	ShardingGroup                      string
+	ShardingNamespace                  string
+	FreezeWindows                      string
 	CliTimeout                         int
 	MaxConcurrentReconciles            int
 	LogFileMaxSize                     int
 	LogFileMaxAge                      int
 	MaxNumberOfOldLogFiles             int
+	FDBAPIVersion                      int
+	TracingSampleRatio                 float64
 	LogFileMinAge                      time.Duration
 	GetTimeout                         time.Duration
 	PostTimeout                        time.Duration
+	ShardingLeaseDuration              time.Duration
 	DeprecationOptions                 internal.DeprecationOptions
 }
 
@@ -99,6 +116,18 @@ func (o *Options) BindFlags(fs *flag.FlagSet) {
 	fs.BoolVar(&o.EnableRestartIncompatibleProcesses, "enable-restart-incompatible-processes", true, "This flag enables/disables in the operator to restart incompatible fdbserver processes.")
 	fs.BoolVar(&o.ServerSideApply, "server-side-apply", false, "This flag enables server side apply.")
 	fs.BoolVar(&o.EnableRecoveryState, "enable-recovery-state", true, "This flag enables the use of the recovery state for the minimum uptime between bounced if the FDB version supports it.")
+	fs.StringVar(&o.AuditSinks, "audit-sinks", "", "Defines a comma separated list of sinks that store the audit log of disruptive actions. Supported sinks are \"file\", \"kubernetes\" and \"database\".")
+	fs.StringVar(&o.AuditLogFile, "audit-log-file", "", "The path to a file to append the audit log to, if the \"file\" audit sink is enabled.")
+	fs.StringVar(&o.TracingExporter, "tracing-exporter", "", "Defines the exporter for the OpenTelemetry spans of the reconciliations. Supported exporters are \"stdout\" and \"file\". If empty tracing is disabled.")
+	fs.StringVar(&o.TracingFile, "tracing-file", "", "The path to a file to write the OpenTelemetry spans to, if the \"file\" tracing exporter is used.")
+	fs.Float64Var(&o.TracingSampleRatio, "tracing-sample-ratio", 1.0, "Defines the fraction of reconciliations that will be traced.")
+	fs.BoolVar(&o.EnableSharding, "enable-sharding", false, "This flag enables the sharding of the clusters between multiple operator replicas. Every replica owns a subset of the clusters, leader election will be disabled when sharding is enabled.")
+	fs.StringVar(&o.ShardingGroup, "sharding-group", "fdb-kubernetes-operator", "Defines the name of the sharded deployment. All replicas with the same group share the clusters.")
+	fs.StringVar(&o.ShardingNamespace, "sharding-namespace", "", "Defines the namespace of the leases that track the replicas of a sharded deployment. Defaults to the watched namespace.")
+	fs.DurationVar(&o.ShardingLeaseDuration, "sharding-lease-duration", 15*time.Second, "Defines how long the lease of a replica is valid without being renewed. Clusters of a replica that stopped are moved to the other replicas after this duration.")
+	fs.StringVar(&o.FreezeWindows, "freeze-windows", "", "Defines a comma separated list of periods in which the operator doesn't perform disruptive actions on any cluster, in the format start/end with RFC 3339 timestamps, e.g. \"2023-12-22T00:00:00Z/2024-01-02T00:00:00Z\".")
+	fs.IntVar(&o.FDBAPIVersion, "fdb-api-version", 620, "Defines the FDB API version the operator uses to connect to the clusters. All clusters managed by the operator must run a version that supports this API version. Managing tenants requires at least 710.")
+	fs.BoolVar(&o.EnableConversionWebhook, "enable-conversion-webhook", false, "This flag enables the conversion webhook for the v1beta1 and v1beta2 API versions. The webhook server expects the serving certificates in the default certificate directory of the controller-runtime.")
 }
 
 // StartManager will start the FoundationDB operator manager.
@@ -111,6 +140,9 @@ func StartManager(
 	clusterReconciler *controllers.FoundationDBClusterReconciler,
 	backupReconciler *controllers.FoundationDBBackupReconciler,
 	restoreReconciler *controllers.FoundationDBRestoreReconciler,
+	disasterRecoveryReconciler *controllers.FoundationDBDisasterRecoveryReconciler,
+	tenantReconciler *controllers.FoundationDBTenantReconciler,
+	chaosReconciler *controllers.FoundationDBChaosReconciler,
 	logr logr.Logger,
 	watchedObjects ...client.Object) (manager.Manager, *os.File) {
 	var logWriter io.Writer
@@ -153,6 +185,13 @@ func StartManager(
 		Port:               9443,
 	}
 
+	// With sharding every replica manages its own subset of the clusters, so
+	// all replicas must run the controllers.
+	if operatorOpts.EnableSharding && options.LeaderElection {
+		setupLog.Info("Disabling leader election as sharding is enabled")
+		options.LeaderElection = false
+	}
+
 	if operatorOpts.WatchNamespace != "" {
 		options.Namespace = operatorOpts.WatchNamespace
 		setupLog.Info("Operator starting in single namespace mode", "namespace", options.Namespace)
@@ -177,6 +216,30 @@ func StartManager(
 		os.Exit(1)
 	}
 
+	err = setupTracing(mgr, operatorOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
+	sharder, err := newSharder(logger, mgr, operatorOpts, labelSelector)
+	if err != nil {
+		setupLog.Error(err, "unable to create sharder")
+		os.Exit(1)
+	}
+
+	auditRecorder, err := newAuditRecorder(logger, mgr, operatorOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to create audit recorder")
+		os.Exit(1)
+	}
+
+	freezeWindows, err := internal.ParseFreezeWindows(operatorOpts.FreezeWindows)
+	if err != nil {
+		setupLog.Error(err, "unable to parse freeze windows")
+		os.Exit(1)
+	}
+
 	if clusterReconciler != nil {
 		clusterReconciler.Client = mgr.GetClient()
 		clusterReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbcluster-controller")
@@ -187,7 +250,15 @@ func StartManager(
 		clusterReconciler.Log = logr.WithName("controllers").WithName("FoundationDBCluster")
 		clusterReconciler.EnableRestartIncompatibleProcesses = operatorOpts.EnableRestartIncompatibleProcesses
 		clusterReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		clusterReconciler.Sharder = sharder
 		clusterReconciler.EnableRecoveryState = operatorOpts.EnableRecoveryState
+		clusterReconciler.AuditRecorder = auditRecorder
+		clusterReconciler.FreezeWindows = freezeWindows
+		clusterReconciler.PodCommandExecutor, err = internal.NewPodCommandExecutor(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create pod command executor")
+			os.Exit(1)
+		}
 
 		if err := clusterReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector, watchedObjects...); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBCluster")
@@ -205,11 +276,21 @@ func StartManager(
 		backupReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
 		backupReconciler.Log = logr.WithName("controllers").WithName("FoundationDBBackup")
 		backupReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		backupReconciler.Sharder = sharder
+		backupReconciler.PodCommandExecutor, err = internal.NewPodCommandExecutor(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create pod command executor")
+			os.Exit(1)
+		}
 
 		if err := backupReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBBackup")
 			os.Exit(1)
 		}
+
+		if operatorOpts.MetricsAddr != "0" {
+			controllers.InitBackupMetrics(backupReconciler)
+		}
 	}
 
 	if restoreReconciler != nil {
@@ -218,6 +299,7 @@ func StartManager(
 		restoreReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
 		restoreReconciler.Log = logr.WithName("controllers").WithName("FoundationDBRestore")
 		restoreReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		restoreReconciler.Sharder = sharder
 
 		if err := restoreReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBRestore")
@@ -225,6 +307,64 @@ func StartManager(
 		}
 	}
 
+	if disasterRecoveryReconciler != nil {
+		disasterRecoveryReconciler.Client = mgr.GetClient()
+		disasterRecoveryReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbdisasterrecovery-controller")
+		disasterRecoveryReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
+		disasterRecoveryReconciler.Log = logr.WithName("controllers").WithName("FoundationDBDisasterRecovery")
+		disasterRecoveryReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		disasterRecoveryReconciler.Sharder = sharder
+
+		if err := disasterRecoveryReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBDisasterRecovery")
+			os.Exit(1)
+		}
+	}
+
+	if tenantReconciler != nil {
+		tenantReconciler.Client = mgr.GetClient()
+		tenantReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbtenant-controller")
+		tenantReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
+		tenantReconciler.Log = logr.WithName("controllers").WithName("FoundationDBTenant")
+		tenantReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		tenantReconciler.Sharder = sharder
+
+		if err := tenantReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBTenant")
+			os.Exit(1)
+		}
+	}
+
+	if chaosReconciler != nil {
+		chaosReconciler.Client = mgr.GetClient()
+		chaosReconciler.Recorder = mgr.GetEventRecorderFor("foundationdbchaos-controller")
+		chaosReconciler.DatabaseClientProvider = fdbclient.NewDatabaseClientProvider(logger)
+		chaosReconciler.Log = logr.WithName("controllers").WithName("FoundationDBChaos")
+		chaosReconciler.ServerSideApply = operatorOpts.ServerSideApply
+		chaosReconciler.Sharder = sharder
+		chaosReconciler.AuditRecorder = auditRecorder
+		chaosReconciler.PodCommandExecutor, err = internal.NewPodCommandExecutor(mgr.GetConfig())
+		if err != nil {
+			setupLog.Error(err, "unable to create pod command executor")
+			os.Exit(1)
+		}
+
+		if err := chaosReconciler.SetupWithManager(mgr, operatorOpts.MaxConcurrentReconciles, *labelSelector); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "FoundationDBChaos")
+			os.Exit(1)
+		}
+	}
+
+	if operatorOpts.EnableConversionWebhook {
+		// The conversion webhook is only registered for resources that exist in the v1beta1 and v1beta2 API versions.
+		for _, object := range []client.Object{&v1beta2.FoundationDBCluster{}, &v1beta2.FoundationDBBackup{}, &v1beta2.FoundationDBRestore{}} {
+			if err := ctrl.NewWebhookManagedBy(mgr).For(object).Complete(); err != nil {
+				setupLog.Error(err, "unable to create conversion webhook", "object", fmt.Sprintf("%T", object))
+				os.Exit(1)
+			}
+		}
+	}
+
 	if operatorOpts.CleanUpOldLogFile {
 		setupLog.V(1).Info("setup log file cleaner", "LogFileMinAge", operatorOpts.LogFileMinAge.String())
 		cleaner := internal.NewCliLogFileCleaner(logger, operatorOpts.LogFileMinAge)
@@ -322,3 +462,117 @@ func moveFDBBinaries(log logr.Logger) error {
 
 	return nil
 }
+
+// newAuditRecorder creates the audit recorder with the sinks defined in the
+// operator options. The hostname of the operator Pod is used to identify the
+// operator instance in the audit entries.
+func newAuditRecorder(logger logr.Logger, mgr manager.Manager, operatorOpts Options) (*audit.Recorder, error) {
+	if operatorOpts.AuditSinks == "" {
+		return nil, nil
+	}
+
+	var sinks []audit.Sink
+	for _, sinkName := range strings.Split(operatorOpts.AuditSinks, ",") {
+		switch strings.TrimSpace(sinkName) {
+		case "file":
+			if operatorOpts.AuditLogFile == "" {
+				return nil, fmt.Errorf("the \"file\" audit sink requires the audit-log-file flag")
+			}
+			sinks = append(sinks, audit.NewFileSink(operatorOpts.AuditLogFile))
+		case "kubernetes":
+			sinks = append(sinks, audit.NewKubernetesSink(mgr.GetClient()))
+		case "database":
+			sinks = append(sinks, fdbclient.NewDatabaseAuditSink(logger))
+		default:
+			return nil, fmt.Errorf("unknown audit sink: %s", sinkName)
+		}
+	}
+
+	operatorInstance, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	return audit.NewRecorder(logger, operatorInstance, sinks...), nil
+}
+
+// setupTracing configures the global tracer provider with the exporter defined
+// in the operator options. The tracer provider is flushed when the manager
+// stops.
+func setupTracing(mgr manager.Manager, operatorOpts Options) error {
+	var writer io.Writer
+	switch operatorOpts.TracingExporter {
+	case "":
+		return nil
+	case "stdout":
+		writer = os.Stdout
+	case "file":
+		if operatorOpts.TracingFile == "" {
+			return fmt.Errorf("the \"file\" tracing exporter requires the tracing-file flag")
+		}
+
+		file, err := os.OpenFile(operatorOpts.TracingFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		writer = file
+	default:
+		return fmt.Errorf("unknown tracing exporter: %s", operatorOpts.TracingExporter)
+	}
+
+	provider, err := tracing.NewTracerProvider(writer, operatorOpts.TracingSampleRatio, operatorVersion)
+	if err != nil {
+		return err
+	}
+	otel.SetTracerProvider(provider)
+
+	return mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return provider.Shutdown(context.Background())
+	}))
+}
+
+// newSharder creates the sharder that partitions the clusters between the
+// operator replicas, if sharding is enabled. The hostname of the operator Pod
+// is used as identity of the replica.
+func newSharder(logger logr.Logger, mgr manager.Manager, operatorOpts Options, labelSelector *metav1.LabelSelector) (*sharding.Sharder, error) {
+	if !operatorOpts.EnableSharding {
+		return nil, nil
+	}
+
+	namespace := operatorOpts.ShardingNamespace
+	if namespace == "" {
+		namespace = operatorOpts.WatchNamespace
+	}
+
+	if namespace == "" {
+		return nil, fmt.Errorf("sharding requires the sharding-namespace flag if the operator is not limited to a single namespace")
+	}
+
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+
+	clusterSelector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	sharder := sharding.NewSharder(mgr.GetClient(), mgr.GetAPIReader(), logger, sharding.Config{
+		Namespace:       namespace,
+		Group:           operatorOpts.ShardingGroup,
+		Identity:        identity,
+		LeaseDuration:   operatorOpts.ShardingLeaseDuration,
+		ClusterSelector: clusterSelector,
+	})
+
+	err = mgr.Add(sharder)
+	if err != nil {
+		return nil, err
+	}
+
+	sharding.InitMetrics(sharder)
+
+	return sharder, nil
+}